@@ -19,7 +19,13 @@ import (
 	"github.com/altatech/ecosistema-imob/backend/internal/config"
 	"github.com/altatech/ecosistema-imob/backend/internal/handlers"
 	"github.com/altatech/ecosistema-imob/backend/internal/middleware"
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/notify"
+	"github.com/altatech/ecosistema-imob/backend/internal/payments"
 	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories/memory"
+	"github.com/altatech/ecosistema-imob/backend/internal/scheduler"
+	"github.com/altatech/ecosistema-imob/backend/internal/search"
 	"github.com/altatech/ecosistema-imob/backend/internal/services"
 	"github.com/altatech/ecosistema-imob/backend/internal/storage"
 )
@@ -34,25 +40,57 @@ func main() {
 	// Set Gin mode
 	gin.SetMode(cfg.GinMode)
 
-	// Initialize Firebase
+	// Initialize Firebase and the repositories. The in-memory backend runs
+	// without Firebase: Firestore-only features (signup, invitations, imports)
+	// are disabled and tokens are verified only against the Auth emulator.
 	ctx := context.Background()
-	firebaseApp, authClient, firestoreClient, err := initializeFirebase(ctx, cfg)
-	if err != nil {
-		log.Fatalf("Failed to initialize Firebase: %v", err)
-	}
-	defer firestoreClient.Close()
-	_ = firebaseApp // keep reference
+	var (
+		repos           *Repositories
+		authClient      *auth.Client
+		firestoreClient *firestore.Client
+	)
+	if cfg.UseMemoryRepositories() {
+		authClient, err = initializeEmulatorAuth(ctx, cfg)
+		if err != nil {
+			log.Fatalf("Failed to initialize the Firebase Auth emulator: %v", err)
+		}
+		if authClient == nil {
+			log.Println("⚠️  Firebase Auth disabled (set FIREBASE_AUTH_EMULATOR_HOST to authenticate requests)")
+		}
 
-	log.Println("Firebase initialized successfully")
+		repos = initializeMemoryRepositories()
+		log.Println("⚠️  Repositories initialized with the IN-MEMORY backend (data is not persisted)")
+	} else {
+		var firebaseApp *firebase.App
+		firebaseApp, authClient, firestoreClient, err = initializeFirebase(ctx, cfg)
+		if err != nil {
+			log.Fatalf("Failed to initialize Firebase: %v", err)
+		}
+		defer firestoreClient.Close()
+		_ = firebaseApp // keep reference
+
+		log.Println("Firebase initialized successfully")
 
-	// Initialize repositories
-	repos := initializeRepositories(firestoreClient)
-	log.Println("Repositories initialized")
+		repos = initializeRepositories(firestoreClient)
+		log.Println("Repositories initialized")
+	}
 
 	// Initialize services
 	services := initializeServices(ctx, cfg, repos, firestoreClient)
 	log.Println("Services initialized")
 
+	// Build the full-text search index in the background. The index lives in
+	// this instance's memory, so it is rebuilt periodically to pick up the
+	// writes made by other replicas.
+	go refreshSearchIndex(services.PropertyService, cfg.SearchIndexRefreshInterval)
+
+	// Start the background jobs (staleness recalculation, monthly confirmations)
+	if cfg.SchedulerEnabled {
+		services.Scheduler.Start(context.Background())
+	} else {
+		log.Println("⚠️  Background job scheduler disabled (SCHEDULER_ENABLED=false)")
+	}
+
 	// Initialize handlers
 	handlers := initializeHandlers(authClient, firestoreClient, services)
 	log.Println("Handlers initialized")
@@ -60,9 +98,10 @@ func main() {
 	// Initialize middleware
 	authMiddleware := middleware.NewAuthMiddleware(authClient)
 	tenantMiddleware := middleware.NewTenantMiddleware(repos.TenantRepo)
+	permissionMiddleware := middleware.NewPermissionMiddleware(repos.UserRepo, repos.BrokerRepo)
 
 	// Setup router
-	router := setupRouter(cfg, handlers, authMiddleware, tenantMiddleware)
+	router := setupRouter(cfg, handlers, authMiddleware, tenantMiddleware, permissionMiddleware)
 	log.Println("Router configured")
 
 	// Create HTTP server
@@ -97,6 +136,9 @@ func main() {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
 
+	// Stop the background jobs (cancels and waits for the runs in progress)
+	services.Scheduler.Stop()
+
 	log.Println("Server exited")
 }
 
@@ -128,19 +170,65 @@ func initializeFirebase(ctx context.Context, cfg *config.Config) (*firebase.App,
 	return app, authClient, firestoreClient, nil
 }
 
+// initializeEmulatorAuth connects Firebase Auth to the local emulator
+// (FIREBASE_AUTH_EMULATOR_HOST), which needs no credentials. Returns nil when
+// the emulator is not configured.
+func initializeEmulatorAuth(ctx context.Context, cfg *config.Config) (*auth.Client, error) {
+	if os.Getenv("FIREBASE_AUTH_EMULATOR_HOST") == "" {
+		return nil, nil
+	}
+
+	projectID := cfg.FirebaseProjectID
+	if projectID == "" {
+		projectID = "demo-ecosistema-imob"
+	}
+
+	app, err := firebase.NewApp(ctx, &firebase.Config{ProjectID: projectID}, option.WithoutAuthentication())
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize Firebase app: %w", err)
+	}
+
+	authClient, err := app.Auth(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize Firebase Auth: %w", err)
+	}
+	log.Printf("✅ Connected to the Firebase Auth emulator: %s", os.Getenv("FIREBASE_AUTH_EMULATOR_HOST"))
+
+	return authClient, nil
+}
+
 // Repositories holds all repository instances
 type Repositories struct {
-	TenantRepo                    *repositories.TenantRepository
-	BrokerRepo                    *repositories.BrokerRepository
-	UserRepo                      *repositories.UserRepository                      // Administrative users (PROMPT 10)
-	OwnerRepo                     *repositories.OwnerRepository
-	PropertyRepo                  *repositories.PropertyRepository
-	ListingRepo                   *repositories.ListingRepository
-	PropertyBrokerRoleRepo        *repositories.PropertyBrokerRoleRepository
-	LeadRepo                      *repositories.LeadRepository
-	ActivityLogRepo               *repositories.ActivityLogRepository
-	OwnerConfirmationTokenRepo    *repositories.OwnerConfirmationTokenRepository    // PROMPT 08
-	ScheduledConfirmationRepo     *repositories.ScheduledConfirmationRepository     // Monthly confirmations
+	TenantRepo                    repositories.TenantStore
+	BrokerRepo                    repositories.BrokerStore
+	UserRepo                      repositories.UserStore                      // Administrative users (PROMPT 10)
+	OwnerRepo                     repositories.OwnerStore
+	PropertyRepo                  repositories.PropertyStore
+	ListingRepo                   repositories.ListingStore
+	PropertyBrokerRoleRepo        repositories.PropertyBrokerRoleStore
+	LeadRepo                      repositories.LeadStore
+	ContactRepo                   repositories.ContactStore                   // Lead identities (dedup/merge)
+	LeadRoutingCursorRepo         repositories.LeadRoutingCursorStore         // Round-robin lead routing
+	LeadNoteRepo                  repositories.LeadNoteStore                  // Lead notes
+	LeadTaskRepo                  repositories.LeadTaskStore                  // Lead follow-up tasks
+	LeadInteractionRepo           repositories.LeadInteractionStore           // Calls/visits/messages logged on leads
+	VisitRepo                     repositories.VisitStore                     // Property visits
+	ProposalRepo                  repositories.ProposalStore                  // Offers and counter-offers
+	CommissionRepo                repositories.CommissionStore                // Commission ledger
+	DealRepo                      repositories.DealStore                      // Closed deals
+	RentalContractRepo            repositories.RentalContractStore            // Rental contracts
+	IndexRateRepo                 repositories.IndexRateStore                 // IGP-M/IPCA/INPC series
+	RentAdjustmentRepo            repositories.RentAdjustmentStore            // Annual rent adjustments
+	RentInvoiceRepo               repositories.RentInvoiceStore               // Monthly rent invoices
+	StayCalendarRepo              repositories.StayCalendarStore              // Short-stay rates and external calendars
+	BookingRepo                   repositories.BookingStore                   // Short-stay bookings and blocks
+	DevelopmentUnitRepo           repositories.DevelopmentUnitStore           // Units of real estate developments
+	ActivityLogRepo               repositories.ActivityLogStore
+	OwnerConfirmationTokenRepo    repositories.OwnerConfirmationTokenStore    // PROMPT 08
+	ScheduledConfirmationRepo     repositories.ScheduledConfirmationStore     // Monthly confirmations
+	PropertyHistoryRepo           repositories.PropertyHistoryStore           // Price/status history
+	JobRunRepo                    repositories.JobRunStore                    // Background job run history
+	JobLockRepo                   repositories.JobLockStore                   // Background job locks
 }
 
 // initializeRepositories initializes all repositories
@@ -154,9 +242,65 @@ func initializeRepositories(client *firestore.Client) *Repositories {
 		ListingRepo:                repositories.NewListingRepository(client),
 		PropertyBrokerRoleRepo:     repositories.NewPropertyBrokerRoleRepository(client),
 		LeadRepo:                   repositories.NewLeadRepository(client),
+		ContactRepo:                repositories.NewContactRepository(client),
+		LeadRoutingCursorRepo:      repositories.NewLeadRoutingCursorRepository(client),
+		LeadNoteRepo:               repositories.NewLeadNoteRepository(client),
+		LeadTaskRepo:               repositories.NewLeadTaskRepository(client),
+		LeadInteractionRepo:        repositories.NewLeadInteractionRepository(client),
+		VisitRepo:                  repositories.NewVisitRepository(client),
+		ProposalRepo:               repositories.NewProposalRepository(client),
+		CommissionRepo:             repositories.NewCommissionRepository(client),
+		DealRepo:                   repositories.NewDealRepository(client),
+		RentalContractRepo:         repositories.NewRentalContractRepository(client),
+		IndexRateRepo:              repositories.NewIndexRateRepository(client),
+		RentAdjustmentRepo:         repositories.NewRentAdjustmentRepository(client),
+		RentInvoiceRepo:            repositories.NewRentInvoiceRepository(client),
+		StayCalendarRepo:           repositories.NewStayCalendarRepository(client),
+		BookingRepo:                repositories.NewBookingRepository(client),
+		DevelopmentUnitRepo:        repositories.NewDevelopmentUnitRepository(client),
 		ActivityLogRepo:            repositories.NewActivityLogRepository(client),
 		OwnerConfirmationTokenRepo: repositories.NewOwnerConfirmationTokenRepository(client), // PROMPT 08
 		ScheduledConfirmationRepo:  repositories.NewScheduledConfirmationRepository(client),  // Monthly confirmations
+		PropertyHistoryRepo:        repositories.NewPropertyHistoryRepository(client),        // Price/status history
+		JobRunRepo:                 repositories.NewJobRunRepository(client),                 // Background jobs
+		JobLockRepo:                repositories.NewJobLockRepository(client),                // Background jobs
+	}
+}
+
+// initializeMemoryRepositories initializes all repositories with the in-memory
+// backend (REPOSITORY_BACKEND=memory). Data is lost when the process exits.
+func initializeMemoryRepositories() *Repositories {
+	return &Repositories{
+		TenantRepo:                 memory.NewTenantRepository(),
+		BrokerRepo:                 memory.NewBrokerRepository(),
+		UserRepo:                   memory.NewUserRepository(),
+		OwnerRepo:                  memory.NewOwnerRepository(),
+		PropertyRepo:               memory.NewPropertyRepository(),
+		ListingRepo:                memory.NewListingRepository(),
+		PropertyBrokerRoleRepo:     memory.NewPropertyBrokerRoleRepository(),
+		LeadRepo:                   memory.NewLeadRepository(),
+		ContactRepo:                memory.NewContactRepository(),
+		LeadRoutingCursorRepo:      memory.NewLeadRoutingCursorRepository(),
+		LeadNoteRepo:               memory.NewLeadNoteRepository(),
+		LeadTaskRepo:               memory.NewLeadTaskRepository(),
+		LeadInteractionRepo:        memory.NewLeadInteractionRepository(),
+		VisitRepo:                  memory.NewVisitRepository(),
+		ProposalRepo:               memory.NewProposalRepository(),
+		CommissionRepo:             memory.NewCommissionRepository(),
+		DealRepo:                   memory.NewDealRepository(),
+		RentalContractRepo:         memory.NewRentalContractRepository(),
+		IndexRateRepo:              memory.NewIndexRateRepository(),
+		RentAdjustmentRepo:         memory.NewRentAdjustmentRepository(),
+		RentInvoiceRepo:            memory.NewRentInvoiceRepository(),
+		StayCalendarRepo:           memory.NewStayCalendarRepository(),
+		BookingRepo:                memory.NewBookingRepository(),
+		DevelopmentUnitRepo:        memory.NewDevelopmentUnitRepository(),
+		ActivityLogRepo:            memory.NewActivityLogRepository(),
+		OwnerConfirmationTokenRepo: memory.NewOwnerConfirmationTokenRepository(),
+		ScheduledConfirmationRepo:  memory.NewScheduledConfirmationRepository(),
+		PropertyHistoryRepo:        memory.NewPropertyHistoryRepository(),
+		JobRunRepo:                 memory.NewJobRunRepository(),
+		JobLockRepo:                memory.NewJobLockRepository(),
 	}
 }
 
@@ -170,12 +314,26 @@ type Services struct {
 	ListingService                *services.ListingService
 	PropertyBrokerRoleService     *services.PropertyBrokerRoleService
 	LeadService                   *services.LeadService
+	VisitService                  *services.VisitService                  // Property visits and broker availability
+	ProposalService               *services.ProposalService               // Offers and counter-offers
+	CommissionService             *services.CommissionService             // Commission split and ledger
+	DealService                   *services.DealService                   // Closed deals and broker statistics
+	RentalContractService         *services.RentalContractService         // Rental contracts
+	RentAdjustmentService         *services.RentAdjustmentService         // Index series and annual rent adjustments
+	RentBillingService            *services.RentBillingService            // Rent invoices, payments and owner payouts
+	BookingService                *services.BookingService                // Short-stay calendars and bookings
+	DevelopmentService            *services.DevelopmentService            // Development unit inventory and sales mirror
 	ActivityLogService            *services.ActivityLogService
 	StorageService                *storage.StorageService
 	PhotoProcessor                *services.PhotoProcessor
 	ImportService                 *services.ImportService
 	OwnerConfirmationService      *services.OwnerConfirmationService      // PROMPT 08
 	MonthlyConfirmationScheduler  *services.MonthlyConfirmationScheduler  // Monthly confirmations
+	PortalFeedService             *services.PortalFeedService             // VRSync portal feeds
+	Scheduler                     *scheduler.Scheduler                    // Background jobs
+	WhatsAppNotifier              *notify.WhatsAppNotifier                // nil when WhatsApp is not configured
+	SMSNotifier                   *notify.SMSNotifier                     // nil when SMS is not configured
+	PaymentProviders              []payments.Provider                     // Empty when the payment webhook is not configured
 }
 
 // initializeServices initializes all services
@@ -193,17 +351,22 @@ func initializeServices(ctx context.Context, cfg *config.Config, repos *Reposito
 
 	// Initialize GCSClient for photo processing
 	// Use empty credentials file since Firebase already initialized with credentials
+	// ImportService writes to Firestore directly: disabled without a client
 	var importService *services.ImportService
 	var photoProcessor *services.PhotoProcessor
 	gcsClient, err := storage.NewGCSClient(ctx, cfg.FirebaseProjectID, cfg.GCSBucketName, "")
 	if err == nil && gcsClient != nil {
 		photoProcessor = services.NewPhotoProcessor(gcsClient)
+	} else if err != nil {
+		log.Printf("⚠️  Failed to initialize GCSClient for photos: %v", err)
+	}
+	switch {
+	case client == nil:
+		log.Println("⚠️  ImportService disabled (requires the Firestore backend)")
+	case photoProcessor != nil:
 		importService = services.NewImportServiceWithPhotos(client, photoProcessor)
 		log.Println("✅ ImportService initialized with photo processing enabled")
-	} else {
-		if err != nil {
-			log.Printf("⚠️  Failed to initialize GCSClient for photos: %v", err)
-		}
+	default:
 		importService = services.NewImportService(client)
 		log.Println("⚠️  ImportService initialized WITHOUT photo processing")
 	}
@@ -226,6 +389,15 @@ func initializeServices(ctx context.Context, cfg *config.Config, repos *Reposito
 		ownerConfirmationService,
 	)
 
+	// Outbound delivery of owner confirmations (WhatsApp, SMS, email)
+	dispatcher, whatsAppNotifier, smsNotifier := initializeNotifiers(cfg)
+	monthlyConfirmationScheduler.SetNotifier(dispatcher)
+	monthlyConfirmationScheduler.SetTenantRepository(repos.TenantRepo) // Channel preference, templates and reminder steps
+	monthlyConfirmationScheduler.SetBrokerRepository(repos.BrokerRepo) // Captador reminders
+
+	// Owner responses stop the reminder sequence of their scheduled confirmation
+	ownerConfirmationService.SetScheduledConfirmationRepository(repos.ScheduledConfirmationRepo)
+
 	// Initialize PropertyService
 	propertyService := services.NewPropertyService(
 		repos.PropertyRepo,
@@ -237,6 +409,155 @@ func initializeServices(ctx context.Context, cfg *config.Config, repos *Reposito
 	)
 	// PROMPT 08: Inject OwnerConfirmationService into PropertyService
 	propertyService.SetOwnerConfirmationService(ownerConfirmationService)
+	monthlyConfirmationScheduler.SetPropertyService(propertyService) // Reminders flag unanswered properties
+
+	// Price/status history (properties/{id}/history)
+	propertyService.SetHistoryRepository(repos.PropertyHistoryRepo)
+	ownerConfirmationService.SetHistoryRepository(repos.PropertyHistoryRepo)
+	ownerConfirmationService.SetTenantRepository(repos.TenantRepo) // Tenant policy (token TTL, reminder interval)
+
+	// Full-text search index (rebuilt from the repositories periodically)
+	propertyService.SetSearchIndex(search.NewIndex())
+
+	// Imported properties get their history and search entries too
+	if importService != nil {
+		importService.SetHistoryRepository(repos.PropertyHistoryRepo)
+		importService.SetPropertyIndexer(propertyService)
+	}
+
+	// VRSync portal feeds (re-rendered incrementally on property/listing changes)
+	portalFeedService := services.NewPortalFeedService(
+		repos.PropertyRepo,
+		repos.ListingRepo,
+		repos.TenantRepo,
+	)
+	propertyService.SetPortalFeed(portalFeedService)
+	ownerConfirmationService.SetPortalFeed(portalFeedService)
+	ownerConfirmationService.SetPropertyIndexer(propertyService)
+
+	listingService := services.NewListingService(
+		repos.ListingRepo,
+		repos.PropertyRepo,
+		repos.BrokerRepo,
+		repos.TenantRepo,
+		repos.ActivityLogRepo,
+	)
+	listingService.SetPropertyIndexer(propertyService)
+
+	leadService := services.NewLeadService(
+		repos.LeadRepo,
+		repos.PropertyRepo,
+		repos.PropertyBrokerRoleRepo,
+		repos.TenantRepo,
+		repos.ActivityLogRepo,
+	)
+	leadService.SetContactRepository(repos.ContactRepo) // Repeat inquiries attach to one contact
+
+	// Broker routing of new leads (tenant lead_routing strategy)
+	leadService.SetBrokerRepository(repos.BrokerRepo)
+	leadService.SetRoutingCursorStore(repos.LeadRoutingCursorRepo)
+
+	// First-response SLA: managers are notified of leads not contacted in time
+	leadService.SetNotifier(dispatcher)
+	leadService.SetUserRepository(repos.UserRepo)
+
+	// Notes, follow-up tasks and interactions (lead timeline)
+	leadService.SetLeadNoteRepository(repos.LeadNoteRepo)
+	leadService.SetLeadTaskRepository(repos.LeadTaskRepo)
+	leadService.SetLeadInteractionRepository(repos.LeadInteractionRepo)
+
+	visitService := services.NewVisitService(
+		repos.VisitRepo,
+		leadService,
+		repos.PropertyRepo,
+		repos.PropertyBrokerRoleRepo,
+		repos.BrokerRepo,
+		repos.TenantRepo,
+		repos.ActivityLogRepo,
+	)
+	visitService.SetNotifier(dispatcher)
+
+	proposalService := services.NewProposalService(
+		repos.ProposalRepo,
+		leadService,
+		propertyService,
+		repos.PropertyRepo,
+		repos.OwnerRepo,
+		repos.BrokerRepo,
+		repos.TenantRepo,
+		repos.ActivityLogRepo,
+	)
+	proposalService.SetNotifier(dispatcher)
+
+	commissionService := services.NewCommissionService(
+		repos.CommissionRepo,
+		repos.ProposalRepo,
+		repos.PropertyRepo,
+		repos.PropertyBrokerRoleRepo,
+		repos.BrokerRepo,
+		repos.TenantRepo,
+		repos.ActivityLogRepo,
+	)
+
+	dealService := services.NewDealService(
+		repos.DealRepo,
+		repos.ProposalRepo,
+		leadService,
+		propertyService,
+		repos.PropertyRepo,
+		repos.PropertyBrokerRoleRepo,
+		repos.BrokerRepo,
+		repos.ActivityLogRepo,
+	)
+	dealService.SetCommissionService(commissionService)
+
+	rentalContractService := services.NewRentalContractService(
+		repos.RentalContractRepo,
+		repos.DealRepo,
+		leadService,
+		propertyService,
+		repos.PropertyRepo,
+		repos.ActivityLogRepo,
+	)
+
+	rentAdjustmentService := services.NewRentAdjustmentService(
+		repos.IndexRateRepo,
+		repos.RentAdjustmentRepo,
+		repos.RentalContractRepo,
+		repos.ActivityLogRepo,
+	)
+
+	rentBillingService := services.NewRentBillingService(
+		repos.RentInvoiceRepo,
+		repos.RentalContractRepo,
+		repos.OwnerRepo,
+		repos.PropertyRepo,
+		repos.ActivityLogRepo,
+	)
+
+	bookingService := services.NewBookingService(
+		repos.StayCalendarRepo,
+		repos.BookingRepo,
+		leadService,
+		repos.PropertyRepo,
+		repos.ActivityLogRepo,
+	)
+
+	developmentService := services.NewDevelopmentService(
+		repos.DevelopmentUnitRepo,
+		repos.PropertyRepo,
+		repos.ActivityLogRepo,
+	)
+
+	// Payment confirmations of the rent invoices
+	var paymentProviders []payments.Provider
+	if cfg.PaymentWebhookEnabled() {
+		paymentProviders = append(paymentProviders, payments.NewWebhookProvider(payments.WebhookConfig{
+			Name:   cfg.PaymentProviderName,
+			Secret: cfg.PaymentWebhookSecret,
+		}))
+		log.Printf("✅ Payment webhook enabled (/api/v1/webhooks/payments/%s)", cfg.PaymentProviderName)
+	}
 
 	return &Services{
 		TenantService: services.NewTenantService(
@@ -262,13 +583,7 @@ func initializeServices(ctx context.Context, cfg *config.Config, repos *Reposito
 			repos.ActivityLogRepo,
 		),
 		PropertyService: propertyService, // Use the pre-configured instance
-		ListingService: services.NewListingService(
-			repos.ListingRepo,
-			repos.PropertyRepo,
-			repos.BrokerRepo,
-			repos.TenantRepo,
-			repos.ActivityLogRepo,
-		),
+		ListingService: listingService,
 		PropertyBrokerRoleService: services.NewPropertyBrokerRoleService(
 			repos.PropertyBrokerRoleRepo,
 			repos.PropertyRepo,
@@ -276,13 +591,16 @@ func initializeServices(ctx context.Context, cfg *config.Config, repos *Reposito
 			repos.TenantRepo,
 			repos.ActivityLogRepo,
 		),
-		LeadService: services.NewLeadService(
-			repos.LeadRepo,
-			repos.PropertyRepo,
-			repos.PropertyBrokerRoleRepo,
-			repos.TenantRepo,
-			repos.ActivityLogRepo,
-		),
+		LeadService: leadService,
+		VisitService: visitService,
+		ProposalService: proposalService,
+		CommissionService: commissionService,
+		DealService: dealService,
+		RentalContractService: rentalContractService,
+		RentAdjustmentService: rentAdjustmentService,
+		RentBillingService: rentBillingService,
+		BookingService: bookingService,
+		DevelopmentService: developmentService,
 		ActivityLogService: services.NewActivityLogService(
 			repos.ActivityLogRepo,
 			repos.TenantRepo,
@@ -292,9 +610,231 @@ func initializeServices(ctx context.Context, cfg *config.Config, repos *Reposito
 		ImportService:               importService,
 		OwnerConfirmationService:    ownerConfirmationService,    // PROMPT 08
 		MonthlyConfirmationScheduler: monthlyConfirmationScheduler, // Monthly confirmations
+		PortalFeedService:            portalFeedService,
+		Scheduler:                    initializeScheduler(cfg, repos, propertyService, leadService, proposalService, dealService, rentalContractService, rentAdjustmentService, rentBillingService, bookingService, developmentService, monthlyConfirmationScheduler),
+		WhatsAppNotifier:             whatsAppNotifier,
+		SMSNotifier:                  smsNotifier,
+		PaymentProviders:             paymentProviders,
 	}
 }
 
+// initializeNotifiers registers a notifier for each channel whose provider
+// credentials are configured. Without any, owner confirmations are left for
+// manual delivery.
+func initializeNotifiers(cfg *config.Config) (*notify.Dispatcher, *notify.WhatsAppNotifier, *notify.SMSNotifier) {
+	dispatcher := notify.NewDispatcher(notify.DefaultRetryPolicy())
+
+	var whatsAppNotifier *notify.WhatsAppNotifier
+	if cfg.WhatsAppEnabled() {
+		whatsAppNotifier = notify.NewWhatsAppNotifier(notify.WhatsAppConfig{
+			APIURL:        cfg.WhatsAppAPIURL,
+			PhoneNumberID: cfg.WhatsAppPhoneNumberID,
+			AccessToken:   cfg.WhatsAppAccessToken,
+			AppSecret:     cfg.WhatsAppAppSecret,
+			VerifyToken:   cfg.WhatsAppVerifyToken,
+		})
+		dispatcher.Register(whatsAppNotifier)
+		log.Printf("✅ WhatsApp notifications enabled")
+		if cfg.WhatsAppAppSecret == "" {
+			log.Printf("⚠️  WHATSAPP_APP_SECRET not set: WhatsApp delivery webhooks will be rejected")
+		}
+	}
+
+	var smsNotifier *notify.SMSNotifier
+	if cfg.SMSEnabled() {
+		smsNotifier = notify.NewSMSNotifier(notify.SMSConfig{
+			AccountSID:        cfg.TwilioAccountSID,
+			AuthToken:         cfg.TwilioAuthToken,
+			From:              cfg.TwilioFromNumber,
+			StatusCallbackURL: cfg.TwilioStatusCallback,
+		})
+		dispatcher.Register(smsNotifier)
+		log.Printf("✅ SMS notifications enabled")
+	}
+
+	if cfg.EmailEnabled() {
+		dispatcher.Register(notify.NewEmailNotifier(notify.EmailConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUser,
+			Password: cfg.SMTPPassword,
+			From:     cfg.EmailFrom,
+			FromName: cfg.EmailFromName,
+		}))
+		log.Printf("✅ Email notifications enabled")
+	}
+
+	return dispatcher, whatsAppNotifier, smsNotifier
+}
+
+// refreshSearchIndex builds the full-text search index and, when interval is
+// set, rebuilds it on every tick. It runs on every instance rather than as a
+// scheduler job, since the job lock would refresh a single replica.
+func refreshSearchIndex(propertyService *services.PropertyService, interval time.Duration) {
+	for {
+		indexed, err := propertyService.RebuildSearchIndex(context.Background())
+		if err != nil {
+			log.Printf("⚠️  Failed to build search index: %v", err)
+		} else {
+			log.Printf("✅ Search index built (%d properties)", indexed)
+		}
+
+		if interval <= 0 {
+			return
+		}
+		time.Sleep(interval)
+	}
+}
+
+// initializeScheduler registers the background jobs. Each job runs for every
+// active tenant; cron expressions are evaluated in SCHEDULER_TIMEZONE.
+func initializeScheduler(cfg *config.Config, repos *Repositories, propertyService *services.PropertyService, leadService *services.LeadService, proposalService *services.ProposalService, dealService *services.DealService, rentalContractService *services.RentalContractService, rentAdjustmentService *services.RentAdjustmentService, rentBillingService *services.RentBillingService, bookingService *services.BookingService, developmentService *services.DevelopmentService, monthlyConfirmationScheduler *services.MonthlyConfirmationScheduler) *scheduler.Scheduler {
+	jobScheduler := scheduler.NewScheduler(repos.TenantRepo, repos.JobLockRepo, repos.JobRunRepo)
+
+	location, err := time.LoadLocation(cfg.SchedulerTimezone)
+	if err != nil {
+		log.Printf("⚠️  Invalid SCHEDULER_TIMEZONE %q, using local time: %v", cfg.SchedulerTimezone, err)
+	} else {
+		jobScheduler.SetLocation(location)
+	}
+
+	jobs := []scheduler.Job{
+		{
+			Name:        "property_staleness",
+			Description: "Marks stale properties as pending confirmation and hides unconfirmed ones",
+			Schedule:    "0 3 * * *", // Daily at 03:00
+			Run: func(ctx context.Context, tenantID string) error {
+				_, err := propertyService.RecalculateTenantStaleness(ctx, tenantID)
+				return err
+			},
+		},
+		{
+			Name:        "monthly_confirmations_schedule",
+			Description: "Schedules the owner confirmations of next month",
+			Schedule:    "0 8 25 * *", // 25th of each month at 08:00
+			Run: func(ctx context.Context, tenantID string) error {
+				_, err := monthlyConfirmationScheduler.ScheduleMonthlyConfirmations(ctx, services.ScheduleMonthlyConfirmationsRequest{
+					TenantID: tenantID, // Default date: 1st of next month
+				})
+				return err
+			},
+		},
+		{
+			Name:        "monthly_confirmations_process",
+			Description: "Sends the owner confirmations scheduled for today",
+			Schedule:    "0 9 * * *", // Daily at 09:00
+			Run:         monthlyConfirmationScheduler.ProcessPendingConfirmations,
+		},
+		{
+			Name:        "monthly_confirmations_reminders",
+			Description: "Runs the follow-up steps of unanswered owner confirmations",
+			Schedule:    "0 10 * * *", // Daily at 10:00
+			Run: func(ctx context.Context, tenantID string) error {
+				_, err := monthlyConfirmationScheduler.ProcessReminders(ctx, tenantID)
+				return err
+			},
+		},
+		{
+			Name:        "lead_sla",
+			Description: "Escalates new leads not contacted within the first-response target",
+			Schedule:    "*/5 * * * *", // Every 5 minutes
+			Run: func(ctx context.Context, tenantID string) error {
+				_, err := leadService.ProcessSLABreaches(ctx, tenantID)
+				return err
+			},
+		},
+		{
+			Name:        "lead_task_reminders",
+			Description: "Sends the due reminders of lead follow-up tasks to their brokers",
+			Schedule:    "*/15 * * * *", // Every 15 minutes
+			Run: func(ctx context.Context, tenantID string) error {
+				_, err := leadService.ProcessTaskReminders(ctx, tenantID)
+				return err
+			},
+		},
+		{
+			Name:        "proposal_expiry",
+			Description: "Expires the proposals not answered within their validity",
+			Schedule:    "0 * * * *", // Hourly
+			Run: func(ctx context.Context, tenantID string) error {
+				_, err := proposalService.ProcessExpiredProposals(ctx, tenantID)
+				return err
+			},
+		},
+		{
+			Name:        "broker_stats",
+			Description: "Recomputes the broker statistics from closed deals and takes sold/rented properties off the market",
+			Schedule:    "30 3 * * *", // Daily at 03:30
+			Run: func(ctx context.Context, tenantID string) error {
+				_, err := dealService.RecalculateBrokerStats(ctx, tenantID)
+				return err
+			},
+		},
+		{
+			Name:        "rental_contract_end",
+			Description: "Ends the rental contracts past their end date and puts their properties back on the market",
+			Schedule:    "0 1 * * *", // Daily at 01:00
+			Run: func(ctx context.Context, tenantID string) error {
+				_, err := rentalContractService.ProcessEndedContracts(ctx, tenantID)
+				return err
+			},
+		},
+		{
+			Name:        "rent_adjustments",
+			Description: "Proposes the annual rent adjustment of the contracts due this month or the next",
+			Schedule:    "0 6 * * *", // Daily at 06:00 (retries until the index months are published)
+			Run: func(ctx context.Context, tenantID string) error {
+				_, err := rentAdjustmentService.ProposeAdjustments(ctx, tenantID, time.Now())
+				return err
+			},
+		},
+		{
+			Name:        "rent_invoices",
+			Description: "Issues the rent invoices of the current month for the active rental contracts",
+			Schedule:    "0 5 * * *", // Daily at 05:00 (picks up contracts started during the month)
+			Run: func(ctx context.Context, tenantID string) error {
+				_, err := rentBillingService.GenerateInvoices(ctx, tenantID, time.Now().Format(models.PeriodLayout))
+				return err
+			},
+		},
+		{
+			Name:        "booking_request_expiry",
+			Description: "Expires the short-stay booking requests not confirmed within 48 hours",
+			Schedule:    "0 * * * *", // Hourly
+			Run: func(ctx context.Context, tenantID string) error {
+				_, err := bookingService.ProcessExpiredBookings(ctx, tenantID)
+				return err
+			},
+		},
+		{
+			Name:        "stay_calendar_sync",
+			Description: "Imports the external calendars (Airbnb, Booking.com) of the short-stay properties",
+			Schedule:    "20 * * * *", // Hourly at :20
+			Run: func(ctx context.Context, tenantID string) error {
+				_, err := bookingService.SyncCalendars(ctx, tenantID)
+				return err
+			},
+		},
+		{
+			Name:        "development_reservation_expiry",
+			Description: "Releases the development units whose reservation ran out and recounts their developments",
+			Schedule:    "0 * * * *", // Hourly
+			Run: func(ctx context.Context, tenantID string) error {
+				_, err := developmentService.ProcessExpiredReservations(ctx, tenantID)
+				return err
+			},
+		},
+	}
+
+	for _, job := range jobs {
+		if err := jobScheduler.Register(job); err != nil {
+			log.Fatalf("Failed to register job: %v", err)
+		}
+	}
+
+	return jobScheduler
+}
+
 // Handlers holds all handler instances
 type Handlers struct {
 	AuthHandler                  *handlers.AuthHandler
@@ -307,15 +847,29 @@ type Handlers struct {
 	ListingHandler               *handlers.ListingHandler
 	PropertyBrokerRoleHandler    *handlers.PropertyBrokerRoleHandler
 	LeadHandler                  *handlers.LeadHandler
+	ContactHandler               *handlers.ContactHandler
+	VisitHandler                 *handlers.VisitHandler                 // Property visits and broker availability
+	ProposalHandler              *handlers.ProposalHandler              // Offers and counter-offers
+	CommissionHandler            *handlers.CommissionHandler            // Commission ledger
+	DealHandler                  *handlers.DealHandler                  // Closed deals
+	RentalContractHandler        *handlers.RentalContractHandler        // Rental contracts
+	RentAdjustmentHandler        *handlers.RentAdjustmentHandler        // Index series and rent adjustments
+	RentInvoiceHandler           *handlers.RentInvoiceHandler           // Rent billing
+	BookingHandler               *handlers.BookingHandler               // Short-stay calendars and bookings
+	DevelopmentHandler           *handlers.DevelopmentHandler           // Development unit inventory
 	ActivityLogHandler           *handlers.ActivityLogHandler
 	StorageHandler               *handlers.StorageHandler
 	ImportHandler                *handlers.ImportHandler
 	OwnerConfirmationHandler     *handlers.OwnerConfirmationHandler     // PROMPT 08
 	ScheduledConfirmationHandler *handlers.ScheduledConfirmationHandler // Monthly confirmations
 	// Public handlers (cross-tenant, no tenant_id required)
-	PublicPropertyHandler *handlers.PublicPropertyHandler // Portal agregador property endpoints
-	PublicLeadHandler     *handlers.PublicLeadHandler     // Portal agregador lead endpoints
-	PublicBrokerHandler   *handlers.PublicBrokerHandler   // Portal agregador broker endpoints
+	PublicPropertyHandler  *handlers.PublicPropertyHandler  // Portal agregador property endpoints
+	PublicLeadHandler      *handlers.PublicLeadHandler      // Portal agregador lead endpoints
+	PublicBrokerHandler    *handlers.PublicBrokerHandler    // Portal agregador broker endpoints
+	PortalFeedHandler      *handlers.PortalFeedHandler      // VRSync feeds for ZAP/VivaReal/OLX
+	JobHandler             *handlers.JobHandler             // Background jobs (platform admins)
+	DeliveryWebhookHandler *handlers.DeliveryWebhookHandler // WhatsApp/SMS delivery status webhooks
+	PaymentWebhookHandler  *handlers.PaymentWebhookHandler  // Rent payment confirmations
 }
 
 // initializeHandlers initializes all handlers
@@ -325,31 +879,58 @@ func initializeHandlers(authClient *auth.Client, firestoreClient *firestore.Clie
 		storageHandler = handlers.NewStorageHandler(services.StorageService, services.PhotoProcessor)
 	}
 
+	// Signup and invitations create Firebase users and write to Firestore directly
+	var authHandler *handlers.AuthHandler
+	var userInvitationHandler *handlers.UserInvitationHandler
+	if authClient != nil && firestoreClient != nil {
+		authHandler = handlers.NewAuthHandler(authClient, firestoreClient)
+		userInvitationHandler = handlers.NewUserInvitationHandler(authClient, firestoreClient)
+	}
+
+	var importHandler *handlers.ImportHandler
+	if services.ImportService != nil {
+		importHandler = handlers.NewImportHandler(services.ImportService)
+	}
+
 	return &Handlers{
-		AuthHandler:                  handlers.NewAuthHandler(authClient, firestoreClient),
+		AuthHandler:                  authHandler,
 		TenantHandler:                handlers.NewTenantHandler(services.TenantService),
 		BrokerHandler:                handlers.NewBrokerHandler(services.BrokerService, services.StorageService),
 		UserHandler:                  handlers.NewUserHandler(services.UserService, services.StorageService),           // PROMPT 10
-		UserInvitationHandler:        handlers.NewUserInvitationHandler(authClient, firestoreClient),                   // PROMPT 11
+		UserInvitationHandler:        userInvitationHandler,                                                            // PROMPT 11
 		OwnerHandler:                 handlers.NewOwnerHandler(services.OwnerService),
 		PropertyHandler:              handlers.NewPropertyHandler(services.PropertyService),
 		ListingHandler:               handlers.NewListingHandler(services.ListingService),
 		PropertyBrokerRoleHandler:    handlers.NewPropertyBrokerRoleHandler(services.PropertyBrokerRoleService),
 		LeadHandler:                  handlers.NewLeadHandler(services.LeadService),
+		ContactHandler:               handlers.NewContactHandler(services.LeadService),
+		VisitHandler:                 handlers.NewVisitHandler(services.VisitService),
+		ProposalHandler:              handlers.NewProposalHandler(services.ProposalService),
+		CommissionHandler:            handlers.NewCommissionHandler(services.CommissionService),
+		DealHandler:                  handlers.NewDealHandler(services.DealService),
+		RentalContractHandler:        handlers.NewRentalContractHandler(services.RentalContractService),
+		RentAdjustmentHandler:        handlers.NewRentAdjustmentHandler(services.RentAdjustmentService),
+		RentInvoiceHandler:           handlers.NewRentInvoiceHandler(services.RentBillingService),
+		BookingHandler:               handlers.NewBookingHandler(services.BookingService),
+		DevelopmentHandler:           handlers.NewDevelopmentHandler(services.DevelopmentService),
 		ActivityLogHandler:           handlers.NewActivityLogHandler(services.ActivityLogService),
 		StorageHandler:               storageHandler,
-		ImportHandler:                handlers.NewImportHandler(services.ImportService),
+		ImportHandler:                importHandler,
 		OwnerConfirmationHandler:     handlers.NewOwnerConfirmationHandler(services.OwnerConfirmationService),          // PROMPT 08
 		ScheduledConfirmationHandler: handlers.NewScheduledConfirmationHandler(services.MonthlyConfirmationScheduler),  // Monthly confirmations
 		// Public handlers (cross-tenant, no tenant_id required)
-		PublicPropertyHandler: handlers.NewPublicPropertyHandler(services.PropertyService),
-		PublicLeadHandler:     handlers.NewPublicLeadHandler(services.LeadService, services.PropertyService),
-		PublicBrokerHandler:   handlers.NewPublicBrokerHandler(services.BrokerService),
+		PublicPropertyHandler:  handlers.NewPublicPropertyHandler(services.PropertyService),
+		PublicLeadHandler:      handlers.NewPublicLeadHandler(services.LeadService, services.PropertyService),
+		PublicBrokerHandler:    handlers.NewPublicBrokerHandler(services.BrokerService),
+		PortalFeedHandler:      handlers.NewPortalFeedHandler(services.PortalFeedService),
+		JobHandler:             handlers.NewJobHandler(services.Scheduler),
+		DeliveryWebhookHandler: handlers.NewDeliveryWebhookHandler(services.MonthlyConfirmationScheduler, services.WhatsAppNotifier, services.SMSNotifier),
+		PaymentWebhookHandler:  handlers.NewPaymentWebhookHandler(services.RentBillingService, services.PaymentProviders...),
 	}
 }
 
 // setupRouter sets up the Gin router with middleware and routes
-func setupRouter(cfg *config.Config, handlers *Handlers, authMiddleware *middleware.AuthMiddleware, tenantMiddleware *middleware.TenantMiddleware) *gin.Engine {
+func setupRouter(cfg *config.Config, handlers *Handlers, authMiddleware *middleware.AuthMiddleware, tenantMiddleware *middleware.TenantMiddleware, permissionMiddleware *middleware.PermissionMiddleware) *gin.Engine {
 	router := gin.New()
 
 	// Global middleware
@@ -391,16 +972,16 @@ func setupRouter(cfg *config.Config, handlers *Handlers, authMiddleware *middlew
 	api := router.Group("/api/v1")
 
 	// Authentication routes (PUBLIC - no auth required)
-	auth := api.Group("/auth")
-	{
+	if handlers.AuthHandler != nil {
+		auth := api.Group("/auth")
 		auth.POST("/signup", handlers.AuthHandler.Signup)
 		auth.POST("/login", handlers.AuthHandler.Login)
 		auth.POST("/refresh", authMiddleware.AuthRequired(), handlers.AuthHandler.RefreshToken)
 	}
 
 	// User invitation routes (PUBLIC - no auth required for accept/verify)
-	invitations := api.Group("/invitations")
-	{
+	if handlers.UserInvitationHandler != nil {
+		invitations := api.Group("/invitations")
 		invitations.GET("/:token/verify", handlers.UserInvitationHandler.VerifyInvitation)
 		invitations.POST("/:token/accept", handlers.UserInvitationHandler.AcceptInvitation)
 	}
@@ -428,9 +1009,18 @@ func setupRouter(cfg *config.Config, handlers *Handlers, authMiddleware *middlew
 		public.POST("/properties/:property_id/leads/whatsapp", handlers.LeadHandler.CreateWhatsAppLead)
 		public.POST("/properties/:property_id/leads/form", handlers.LeadHandler.CreateFormLead)
 
+		// Visit requests from the property page and broker .ics feeds
+		handlers.VisitHandler.RegisterPublicRoutes(public)
+
+		// Short-stay availability, quotes, booking requests and .ics export
+		handlers.BookingHandler.RegisterPublicRoutes(public)
+
 		// Public images
 		public.GET("/property-images/:property_id", handlers.StorageHandler.ListImages)
 		public.GET("/property-images/:property_id/:image_id", handlers.StorageHandler.GetImageURL)
+
+		// Portal syndication feeds (stable URL registered in ZAP/VivaReal/OLX)
+		public.GET("/feeds/vrsync/:portal", handlers.PortalFeedHandler.GetVRSyncFeed)
 	}
 
 	// Public routes for portal agregador (NO tenant_id required)
@@ -453,6 +1043,15 @@ func setupRouter(cfg *config.Config, handlers *Handlers, authMiddleware *middlew
 		publicPortal.GET("/brokers/:id/properties", handlers.PublicBrokerHandler.GetPublicBrokerProperties)
 	}
 
+	// Delivery status and payment webhooks of the providers (PUBLIC - signed by the provider)
+	webhooks := api.Group("/webhooks")
+	{
+		webhooks.GET("/whatsapp", handlers.DeliveryWebhookHandler.VerifyWhatsApp)
+		webhooks.POST("/whatsapp", handlers.DeliveryWebhookHandler.WhatsAppStatus)
+		webhooks.POST("/sms", handlers.DeliveryWebhookHandler.SMSStatus)
+		webhooks.POST("/payments/:provider", handlers.PaymentWebhookHandler.PaymentStatus)
+	}
+
 	// Protected routes (require authentication) - admin dashboard
 	protected := api.Group("/admin")
 	protected.Use(authMiddleware.AuthRequired())
@@ -460,6 +1059,7 @@ func setupRouter(cfg *config.Config, handlers *Handlers, authMiddleware *middlew
 	{
 		tenantScoped := protected.Group("/:tenant_id")
 		tenantScoped.Use(tenantMiddleware.ValidateTenant())
+		tenantScoped.Use(permissionMiddleware.Authorize()) // Route permissions: middleware.AdminRoutePermissions
 		{
 			// Admin-only routes
 			handlers.PropertyHandler.RegisterRoutes(tenantScoped)
@@ -469,6 +1069,16 @@ func setupRouter(cfg *config.Config, handlers *Handlers, authMiddleware *middlew
 			handlers.ListingHandler.RegisterRoutes(tenantScoped)
 			handlers.PropertyBrokerRoleHandler.RegisterRoutes(tenantScoped)
 			handlers.LeadHandler.RegisterRoutes(tenantScoped)
+			handlers.ContactHandler.RegisterRoutes(tenantScoped)
+			handlers.VisitHandler.RegisterRoutes(tenantScoped)
+			handlers.ProposalHandler.RegisterRoutes(tenantScoped)
+			handlers.CommissionHandler.RegisterRoutes(tenantScoped)
+			handlers.DealHandler.RegisterRoutes(tenantScoped)
+			handlers.RentalContractHandler.RegisterRoutes(tenantScoped)
+			handlers.RentAdjustmentHandler.RegisterRoutes(tenantScoped)
+			handlers.RentInvoiceHandler.RegisterRoutes(tenantScoped)
+			handlers.BookingHandler.RegisterRoutes(tenantScoped)
+			handlers.DevelopmentHandler.RegisterRoutes(tenantScoped)
 			handlers.ActivityLogHandler.RegisterRoutes(tenantScoped)
 			if handlers.StorageHandler != nil {
 				handlers.StorageHandler.RegisterRoutes(tenantScoped)
@@ -484,14 +1094,33 @@ func setupRouter(cfg *config.Config, handlers *Handlers, authMiddleware *middlew
 			// Monthly confirmation scheduler routes
 			tenantScoped.POST("/scheduled-confirmations/schedule", handlers.ScheduledConfirmationHandler.ScheduleMonthlyConfirmations)
 			tenantScoped.POST("/scheduled-confirmations/process", handlers.ScheduledConfirmationHandler.ProcessPendingConfirmations)
+			tenantScoped.POST("/scheduled-confirmations/reminders/process", handlers.ScheduledConfirmationHandler.ProcessReminders)
 			tenantScoped.GET("/scheduled-confirmations/metrics", handlers.ScheduledConfirmationHandler.GetConfirmationMetrics)
 			tenantScoped.GET("/scheduled-confirmations/broker/:broker_id", handlers.ScheduledConfirmationHandler.GetBrokerScheduledConfirmations)
+			tenantScoped.GET("/scheduled-confirmations/property/:property_id", handlers.ScheduledConfirmationHandler.GetPropertyConfirmationStatus)
 			tenantScoped.GET("/scheduled-confirmations", handlers.ScheduledConfirmationHandler.GetScheduledConfirmations)
 
+			// Tenant staleness and confirmation policy
+			tenantScoped.GET("/policy", handlers.TenantHandler.GetTenantPolicy)
+			tenantScoped.PUT("/policy", handlers.TenantHandler.UpdateTenantPolicy)
+
+			// Background jobs (platform admins only)
+			jobs := tenantScoped.Group("/jobs", tenantMiddleware.RequirePlatformAdmin())
+			{
+				jobs.GET("", handlers.JobHandler.ListJobs)
+				jobs.GET("/:name/runs", handlers.JobHandler.ListJobRuns)
+				jobs.POST("/:name/run", handlers.JobHandler.TriggerJob)
+			}
+
+			// Index series import (platform admins only: shared by every tenant)
+			handlers.RentAdjustmentHandler.RegisterAdminRoutes(tenantScoped.Group("", tenantMiddleware.RequirePlatformAdmin()))
+
 			// User invitation routes (PROMPT 11)
-			tenantScoped.POST("/users/invite", handlers.UserInvitationHandler.InviteUser)
-			tenantScoped.GET("/users/invitations", handlers.UserInvitationHandler.ListInvitations)
-			tenantScoped.DELETE("/users/invitations/:invitation_id", handlers.UserInvitationHandler.CancelInvitation)
+			if handlers.UserInvitationHandler != nil {
+				tenantScoped.POST("/users/invite", handlers.UserInvitationHandler.InviteUser)
+				tenantScoped.GET("/users/invitations", handlers.UserInvitationHandler.ListInvitations)
+				tenantScoped.DELETE("/users/invitations/:invitation_id", handlers.UserInvitationHandler.CancelInvitation)
+			}
 		}
 	}
 
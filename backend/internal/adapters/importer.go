@@ -0,0 +1,107 @@
+// Package adapters defines the contract between CRM export parsers and the
+// import pipeline. Each supported export format lives in its own subpackage
+// (union, vrsync) and yields normalized PropertyPayload records that
+// services.ImportService imports the same way regardless of the source.
+package adapters
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+// ErrUnknownSource is returned when no importer is registered for a source
+var ErrUnknownSource = errors.New("unknown import source")
+
+// PropertyPayload represents normalized property data ready for import
+type PropertyPayload struct {
+	Property    models.Property
+	Owner       OwnerPayload
+	Photos      []string // Photo URLs, cover first
+	Title       string   // Título do anúncio
+	Description string   // Descrição completa do anúncio
+}
+
+// OwnerPayload represents owner data (may be incomplete/placeholder)
+type OwnerPayload struct {
+	Name            string
+	Phone           string
+	Email           string
+	Company         string
+	OwnerStatus     models.OwnerStatus // incomplete, partial, verified
+	EnrichedFromXLS bool
+}
+
+// Input holds the files uploaded for an import batch
+type Input struct {
+	TenantID   string
+	FeedPath   string // listings export (XML)
+	OwnersPath string // optional owners spreadsheet (XLS); only some formats support it
+}
+
+// Importer parses a CRM export into property payloads
+type Importer interface {
+	// Source is the value of ImportBatch.Source handled by this importer (ex: "union")
+	Source() string
+
+	// Parse reads the export and returns one payload per listed property
+	Parse(input Input) ([]PropertyPayload, error)
+}
+
+// Registry holds the importers keyed by source
+type Registry struct {
+	mu        sync.RWMutex
+	importers map[string]Importer
+}
+
+// NewRegistry creates a registry with the given importers
+func NewRegistry(importers ...Importer) *Registry {
+	r := &Registry{importers: make(map[string]Importer)}
+	for _, importer := range importers {
+		r.Register(importer)
+	}
+	return r
+}
+
+// Register adds an importer, replacing any importer for the same source
+func (r *Registry) Register(importer Importer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.importers[normalizeSource(importer.Source())] = importer
+}
+
+// Get returns the importer for a source
+func (r *Registry) Get(source string) (Importer, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	importer, ok := r.importers[normalizeSource(source)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q (supported: %s)", ErrUnknownSource, source, strings.Join(r.sources(), ", "))
+	}
+	return importer, nil
+}
+
+// Sources returns the registered sources, sorted
+func (r *Registry) Sources() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.sources()
+}
+
+func (r *Registry) sources() []string {
+	sources := make([]string, 0, len(r.importers))
+	for source := range r.importers {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+	return sources
+}
+
+func normalizeSource(source string) string {
+	return strings.ToLower(strings.TrimSpace(source))
+}
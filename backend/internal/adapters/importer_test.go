@@ -0,0 +1,32 @@
+package adapters
+
+import (
+	"errors"
+	"testing"
+)
+
+type stubImporter struct{ source string }
+
+func (s stubImporter) Source() string                         { return s.source }
+func (s stubImporter) Parse(Input) ([]PropertyPayload, error) { return nil, nil }
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry(stubImporter{"union"}, stubImporter{"vrsync"})
+
+	importer, err := registry.Get(" VRSync ")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if importer.Source() != "vrsync" {
+		t.Errorf("Get() returned %q, expected vrsync", importer.Source())
+	}
+
+	if _, err := registry.Get("jetimob"); !errors.Is(err, ErrUnknownSource) {
+		t.Errorf("Get(unknown) error = %v, expected ErrUnknownSource", err)
+	}
+
+	sources := registry.Sources()
+	if len(sources) != 2 || sources[0] != "union" || sources[1] != "vrsync" {
+		t.Errorf("Sources() = %v, expected [union vrsync]", sources)
+	}
+}
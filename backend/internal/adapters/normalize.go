@@ -0,0 +1,90 @@
+package adapters
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/utils"
+)
+
+// Fingerprint generates the deduplication fingerprint of a property
+// Based on: normalized address + type + total area. Every importer must use it
+// so the same property exported by different CRMs is matched.
+func Fingerprint(street, number, neighborhood, city string, propertyType models.PropertyType, totalArea float64) string {
+	// Normalize address components
+	street = strings.ToLower(strings.TrimSpace(street))
+	number = strings.ToLower(strings.TrimSpace(number))
+	neighborhood = strings.ToLower(strings.TrimSpace(neighborhood))
+	city = strings.ToLower(strings.TrimSpace(city))
+
+	// Normalize area (round to avoid float precision issues)
+	area := fmt.Sprintf("%.0f", totalArea)
+
+	// Build fingerprint string
+	fingerprintStr := fmt.Sprintf("%s|%s|%s|%s|%s|%s",
+		street, number, neighborhood, city, string(propertyType), area)
+
+	// Hash it
+	hash := sha256.Sum256([]byte(fingerprintStr))
+	return fmt.Sprintf("%x", hash)
+}
+
+// Slug creates a URL-friendly slug from the title (or reference), suffixed
+// with the reference for uniqueness
+func Slug(title, reference string) string {
+	if title == "" {
+		title = reference
+	}
+
+	slug := strings.ToLower(title)
+	slug = strings.ReplaceAll(slug, " ", "-")
+
+	// Remove special characters
+	var result strings.Builder
+	for _, r := range slug {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' {
+			result.WriteRune(r)
+		}
+	}
+
+	slug = result.String()
+
+	// Remove multiple hyphens
+	for strings.Contains(slug, "--") {
+		slug = strings.ReplaceAll(slug, "--", "-")
+	}
+
+	// Trim hyphens
+	slug = strings.Trim(slug, "-")
+
+	// Limit length
+	if len(slug) > 100 {
+		slug = slug[:100]
+	}
+
+	// Ensure uniqueness by appending reference
+	if reference != "" {
+		slug = slug + "-" + strings.ToLower(reference)
+	}
+
+	return slug
+}
+
+// ParseCoordinates parses a latitude/longitude pair from an export.
+// Missing, malformed and 0,0 placeholder coordinates are rejected.
+func ParseCoordinates(latitude, longitude string) (float64, float64, bool) {
+	lat, okLat := utils.ParseCoordinate(latitude)
+	lng, okLng := utils.ParseCoordinate(longitude)
+	if !okLat || !okLng {
+		return 0, 0, false
+	}
+	if lat == 0 && lng == 0 {
+		return 0, 0, false
+	}
+	if err := utils.ValidateCoordinates(lat, lng); err != nil {
+		return 0, 0, false
+	}
+	return lat, lng, true
+}
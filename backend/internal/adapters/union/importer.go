@@ -0,0 +1,62 @@
+package union
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/adapters"
+)
+
+// Source identifies Union CRM imports (ImportBatch.Source, Property.ExternalSource)
+const Source = "union"
+
+// Importer reads Union CRM exports: the XML feed plus the optional owners
+// spreadsheet used to enrich owner data
+type Importer struct{}
+
+// NewImporter creates a Union importer
+func NewImporter() *Importer {
+	return &Importer{}
+}
+
+// Source returns "union"
+func (i *Importer) Source() string {
+	return Source
+}
+
+// Parse reads the Union XML feed and matches each property to its XLS owner record
+func (i *Importer) Parse(input adapters.Input) ([]adapters.PropertyPayload, error) {
+	var xlsRecords []XLSRecord
+	if input.OwnersPath != "" {
+		var err error
+		xlsRecords, err = ParseXLS(input.OwnersPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse XLS: %w", err)
+		}
+	}
+
+	xmlFile, err := os.Open(input.FeedPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open XML file: %w", err)
+	}
+	defer xmlFile.Close()
+
+	xmlData, err := ParseXML(xmlFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse XML: %w", err)
+	}
+
+	payloads := make([]adapters.PropertyPayload, 0, len(xmlData.Imoveis))
+	for idx := range xmlData.Imoveis {
+		xmlImovel := &xmlData.Imoveis[idx]
+
+		var xlsRecord *XLSRecord
+		if len(xlsRecords) > 0 {
+			xlsRecord = FindXLSRecordByCode(xlsRecords, xmlImovel)
+		}
+
+		payloads = append(payloads, NormalizeProperty(xmlImovel, xlsRecord, input.TenantID))
+	}
+
+	return payloads, nil
+}
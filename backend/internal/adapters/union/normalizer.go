@@ -1,33 +1,20 @@
 package union
 
 import (
-	"crypto/sha256"
 	"fmt"
 	"strings"
 	"time"
 
+	"github.com/altatech/ecosistema-imob/backend/internal/adapters"
 	"github.com/altatech/ecosistema-imob/backend/internal/models"
 	"github.com/google/uuid"
 )
 
 // PropertyPayload represents normalized property data ready for import
-type PropertyPayload struct {
-	Property    models.Property
-	Owner       OwnerPayload
-	Photos      []string // URLs from XML
-	Title       string   // Título do anúncio (from XML)
-	Description string   // Descrição completa do anúncio (from XML)
-}
+type PropertyPayload = adapters.PropertyPayload
 
 // OwnerPayload represents owner data (may be incomplete/placeholder)
-type OwnerPayload struct {
-	Name            string
-	Phone           string
-	Email           string
-	Company         string
-	OwnerStatus     models.OwnerStatus // incomplete, partial, verified
-	EnrichedFromXLS bool
-}
+type OwnerPayload = adapters.OwnerPayload
 
 // NormalizeProperty converts XMLImovel + optional XLSRecord to PropertyPayload
 func NormalizeProperty(xml *XMLImovel, xls *XLSRecord, tenantID string) PropertyPayload {
@@ -61,8 +48,8 @@ func NormalizeProperty(xml *XMLImovel, xls *XLSRecord, tenantID string) Property
 		}
 		transactionType = &tt
 
-		// Create rental info if rental price available
-		if rentalPrice > 0 {
+		// Create rental info if rental price (or daily rate of temporada) available
+		if rentalPrice > 0 || (purpose == "seasonal" && xml.Valortemporada > 0) {
 			rentalInfo = &models.RentalInfo{
 				MonthlyRent:        rentalPrice,
 				CondoFee:           condoFee,
@@ -79,6 +66,7 @@ func NormalizeProperty(xml *XMLImovel, xls *XLSRecord, tenantID string) Property
 			if purpose == "seasonal" {
 				rentalInfo.RentalType = models.RentalTypeVacation
 				rentalInfo.MinRentalPeriod = 1
+				rentalInfo.DailyRate = xml.Valortemporada
 			}
 		}
 	}
@@ -120,7 +108,7 @@ func NormalizeProperty(xml *XMLImovel, xls *XLSRecord, tenantID string) Property
 		TenantID: tenantID,
 
 		// External identifiers (CRITICAL FOR DEDUPLICATION)
-		ExternalSource: "union",
+		ExternalSource: Source,
 		ExternalID:     xml.Codigoimovel, // main dedup key
 		Reference:      xml.Referencia,
 
@@ -164,7 +152,7 @@ func NormalizeProperty(xml *XMLImovel, xls *XLSRecord, tenantID string) Property
 		RentalInfo:      rentalInfo,
 
 		// Slug (generated from title or reference)
-		Slug: createSlug(xml.Titulo, xml.Referencia),
+		Slug: adapters.Slug(xml.Titulo, xml.Referencia),
 
 		// Deduplication fields
 		Fingerprint:       generateFingerprint(xml),
@@ -176,6 +164,11 @@ func NormalizeProperty(xml *XMLImovel, xls *XLSRecord, tenantID string) Property
 		UpdatedAt: now,
 	}
 
+	// Geolocation (skipped when missing, malformed or the 0,0 placeholder)
+	if lat, lng, ok := adapters.ParseCoordinates(xml.Latitude, xml.Longitude); ok {
+		property.SetCoordinates(lat, lng)
+	}
+
 	// Build owner payload
 	owner := buildOwnerPayload(xml, xls)
 
@@ -248,7 +241,6 @@ func normalizeType(tipo string) models.PropertyType {
 	return models.PropertyTypeApartment
 }
 
-
 // buildOwnerPayload builds owner data from XML and optionally XLS
 func buildOwnerPayload(xml *XMLImovel, xls *XLSRecord) OwnerPayload {
 	owner := OwnerPayload{
@@ -293,25 +285,7 @@ func buildOwnerPayload(xml *XMLImovel, xls *XLSRecord) OwnerPayload {
 // generateFingerprint generates deduplication fingerprint
 // Based on: normalized address + type + total area
 func generateFingerprint(xml *XMLImovel) string {
-	// Normalize address components
-	street := strings.ToLower(strings.TrimSpace(xml.Endereco))
-	number := strings.ToLower(strings.TrimSpace(xml.Numero))
-	neighborhood := strings.ToLower(strings.TrimSpace(xml.Bairro))
-	city := strings.ToLower(strings.TrimSpace(xml.Cidade))
-
-	// Normalize type
-	propertyType := normalizeType(xml.Tipo)
-
-	// Normalize area (round to avoid float precision issues)
-	area := fmt.Sprintf("%.0f", xml.Areatotal)
-
-	// Build fingerprint string
-	fingerprintStr := fmt.Sprintf("%s|%s|%s|%s|%s|%s",
-		street, number, neighborhood, city, string(propertyType), area)
-
-	// Hash it
-	hash := sha256.Sum256([]byte(fingerprintStr))
-	return fmt.Sprintf("%x", hash)
+	return adapters.Fingerprint(xml.Endereco, xml.Numero, xml.Bairro, xml.Cidade, normalizeType(xml.Tipo), xml.Areatotal)
 }
 
 // cleanString cleans string (trim, normalize spaces)
@@ -320,43 +294,3 @@ func cleanString(s string) string {
 	s = strings.Join(strings.Fields(s), " ")
 	return s
 }
-
-// createSlug creates URL-friendly slug
-func createSlug(title, reference string) string {
-	if title == "" {
-		title = reference
-	}
-
-	slug := strings.ToLower(title)
-	slug = strings.ReplaceAll(slug, " ", "-")
-
-	// Remove special characters
-	var result strings.Builder
-	for _, r := range slug {
-		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' {
-			result.WriteRune(r)
-		}
-	}
-
-	slug = result.String()
-
-	// Remove multiple hyphens
-	for strings.Contains(slug, "--") {
-		slug = strings.ReplaceAll(slug, "--", "-")
-	}
-
-	// Trim hyphens
-	slug = strings.Trim(slug, "-")
-
-	// Limit length
-	if len(slug) > 100 {
-		slug = slug[:100]
-	}
-
-	// Ensure uniqueness by appending reference
-	if reference != "" {
-		slug = slug + "-" + strings.ToLower(reference)
-	}
-
-	return slug
-}
@@ -0,0 +1,146 @@
+// Package vrsync parses VRSync XML feeds, the listing export format of
+// ZAP/VivaReal that most Brazilian real estate CRMs can generate.
+package vrsync
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Namespace is the VRSync XML namespace
+const Namespace = "http://www.vivareal.com/schemas/1.0/VRSync"
+
+// Feed represents the root of a VRSync export
+type Feed struct {
+	XMLName  xml.Name  `xml:"ListingDataFeed"`
+	Header   Header    `xml:"Header"`
+	Listings []Listing `xml:"Listings>Listing"`
+}
+
+// Header identifies the publisher of the feed
+type Header struct {
+	Provider    string `xml:"Provider,omitempty"`
+	Email       string `xml:"Email,omitempty"`
+	ContactName string `xml:"ContactName,omitempty"`
+	PublishDate string `xml:"PublishDate,omitempty"` // RFC 3339
+	Telephone   string `xml:"Telephone,omitempty"`
+}
+
+// Listing represents a property listing of the feed
+type Listing struct {
+	XMLName         xml.Name     `xml:"Listing"`
+	ListingID       string       `xml:"ListingID"` // external_id / reference
+	Title           string       `xml:"Title,omitempty"`
+	TransactionType string       `xml:"TransactionType"` // For Sale, For Rent, Sale/Rent
+	Media           []Media      `xml:"Media>Item,omitempty"`
+	Details         Details      `xml:"Details"`
+	Location        Location     `xml:"Location"`
+	ContactInfo     *ContactInfo `xml:"ContactInfo,omitempty"`
+}
+
+// Media represents a photo, video or floor plan of a listing
+type Media struct {
+	URL     string `xml:",chardata"`
+	Medium  string `xml:"medium,attr,omitempty"` // image, video, floorplan
+	Caption string `xml:"caption,attr,omitempty"`
+	Primary bool   `xml:"primary,attr,omitempty"`
+}
+
+// Details holds the listing characteristics
+type Details struct {
+	UsageType                 string  `xml:"UsageType,omitempty"`    // Residential, Commercial
+	PropertyType              string  `xml:"PropertyType,omitempty"` // ex: "Residential / Apartment"
+	Description               string  `xml:"Description,omitempty"`
+	ListPrice                 *Amount `xml:"ListPrice,omitempty"`
+	RentalPrice               *Amount `xml:"RentalPrice,omitempty"`
+	PropertyAdministrationFee *Amount `xml:"PropertyAdministrationFee,omitempty"` // condomínio
+	YearlyTax                 *Amount `xml:"YearlyTax,omitempty"`                 // IPTU
+	LivingArea                *Amount `xml:"LivingArea,omitempty"`                // área útil (m²)
+	LotArea                   *Amount `xml:"LotArea,omitempty"`                   // área do terreno (m²)
+	Bedrooms                  *Amount `xml:"Bedrooms,omitempty"`
+	Bathrooms                 *Amount `xml:"Bathrooms,omitempty"`
+	Suites                    *Amount `xml:"Suites,omitempty"`
+	Garage                    *Amount `xml:"Garage,omitempty"`
+}
+
+// Location holds the listing address
+type Location struct {
+	DisplayAddress string `xml:"displayAddress,attr,omitempty"` // All, Street, Neighborhood
+	Country        Place  `xml:"Country"`
+	State          Place  `xml:"State"`
+	City           string `xml:"City"`
+	Neighborhood   string `xml:"Neighborhood,omitempty"`
+	Address        string `xml:"Address,omitempty"`
+	StreetNumber   string `xml:"StreetNumber,omitempty"`
+	Complement     string `xml:"Complement,omitempty"`
+	PostalCode     string `xml:"PostalCode,omitempty"`
+	Latitude       string `xml:"Latitude,omitempty"`
+	Longitude      string `xml:"Longitude,omitempty"`
+}
+
+// Place is a named location with an abbreviation (ex: <State abbreviation="SP">São Paulo</State>)
+type Place struct {
+	Name         string `xml:",chardata"`
+	Abbreviation string `xml:"abbreviation,attr,omitempty"`
+}
+
+// ContactInfo is the advertiser contact shown by the portals
+type ContactInfo struct {
+	Name      string `xml:"Name,omitempty"`
+	Email     string `xml:"Email,omitempty"`
+	Telephone string `xml:"Telephone,omitempty"`
+}
+
+// Amount is a numeric element with its optional currency, period and unit
+// attributes. The value is kept as text because CRMs export empty elements
+// and Brazilian-formatted numbers.
+type Amount struct {
+	Value    string `xml:",chardata"`
+	Currency string `xml:"currency,attr,omitempty"`
+	Period   string `xml:"period,attr,omitempty"` // RentalPrice: Monthly, Daily, Weekly, Yearly
+	Unit     string `xml:"unit,attr,omitempty"`   // areas: square metres
+}
+
+// Float returns the amount as a number (0 when missing, empty or malformed)
+func (a *Amount) Float() float64 {
+	if a == nil {
+		return 0
+	}
+	return parseNumber(a.Value)
+}
+
+// Int returns the amount as an integer (0 when missing, empty or malformed)
+func (a *Amount) Int() int {
+	return int(a.Float())
+}
+
+// ParseFeed parses a VRSync XML feed
+func ParseFeed(r io.Reader) (*Feed, error) {
+	var feed Feed
+	if err := xml.NewDecoder(r).Decode(&feed); err != nil {
+		return nil, fmt.Errorf("failed to decode VRSync XML: %w", err)
+	}
+	return &feed, nil
+}
+
+// parseNumber parses "1234.56", "1234,56" and "1.234,56"
+func parseNumber(s string) float64 {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0
+	}
+
+	if strings.Contains(s, ",") {
+		s = strings.ReplaceAll(s, ".", "")
+		s = strings.ReplaceAll(s, ",", ".")
+	}
+
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0
+	}
+	return n
+}
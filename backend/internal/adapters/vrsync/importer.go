@@ -0,0 +1,46 @@
+package vrsync
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/adapters"
+)
+
+// Source identifies VRSync imports (ImportBatch.Source, Property.ExternalSource)
+const Source = "vrsync"
+
+// Importer reads VRSync (ZAP/VivaReal) XML feeds. The feed carries no owner
+// data, so Input.OwnersPath is not used and owners are created as placeholders.
+type Importer struct{}
+
+// NewImporter creates a VRSync importer
+func NewImporter() *Importer {
+	return &Importer{}
+}
+
+// Source returns "vrsync"
+func (i *Importer) Source() string {
+	return Source
+}
+
+// Parse reads the VRSync feed
+func (i *Importer) Parse(input adapters.Input) ([]adapters.PropertyPayload, error) {
+	feedFile, err := os.Open(input.FeedPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open XML file: %w", err)
+	}
+	defer feedFile.Close()
+
+	feed, err := ParseFeed(feedFile)
+	if err != nil {
+		return nil, err
+	}
+
+	payloads := make([]adapters.PropertyPayload, 0, len(feed.Listings))
+	for idx := range feed.Listings {
+		payloads = append(payloads, NormalizeListing(&feed.Listings[idx], input.TenantID))
+	}
+
+	return payloads, nil
+}
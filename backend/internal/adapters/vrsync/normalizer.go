@@ -0,0 +1,226 @@
+package vrsync
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/adapters"
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+// NormalizeListing converts a VRSync listing to PropertyPayload
+func NormalizeListing(listing *Listing, tenantID string) adapters.PropertyPayload {
+	now := time.Now()
+	details := &listing.Details
+	location := &listing.Location
+
+	reference := strings.TrimSpace(listing.ListingID)
+	propertyType := normalizeType(details.UsageType, details.PropertyType)
+
+	// Areas: VRSync has no total area, the lot area is used when present
+	usableArea := details.LivingArea.Float()
+	totalArea := details.LotArea.Float()
+	if totalArea == 0 {
+		totalArea = usableArea
+	}
+
+	// Transaction type and rental info
+	transactionType := normalizeTransactionType(listing.TransactionType)
+	var rentalInfo *models.RentalInfo
+	if transactionType != models.TransactionTypeSale {
+		rentalInfo = buildRentalInfo(details)
+	}
+
+	state := strings.TrimSpace(location.State.Abbreviation)
+	if state == "" {
+		state = strings.TrimSpace(location.State.Name)
+	}
+
+	country := strings.ToUpper(strings.TrimSpace(location.Country.Abbreviation))
+	if country == "" {
+		country = "BR"
+	}
+
+	photoURLs := photoURLs(listing.Media)
+
+	title := strings.TrimSpace(listing.Title)
+	description := strings.TrimSpace(details.Description)
+
+	// Determine data completeness
+	dataCompleteness := "partial"
+	if title != "" && description != "" && len(photoURLs) > 0 {
+		dataCompleteness = "complete"
+	}
+
+	property := models.Property{
+		ID:       uuid.New().String(),
+		TenantID: tenantID,
+
+		// External identifiers (CRITICAL FOR DEDUPLICATION)
+		ExternalSource: Source,
+		ExternalID:     reference,
+		Reference:      reference,
+
+		// Type and location
+		PropertyType: propertyType,
+		Street:       strings.TrimSpace(location.Address),
+		Number:       strings.TrimSpace(location.StreetNumber),
+		Complement:   strings.TrimSpace(location.Complement),
+		Neighborhood: strings.TrimSpace(location.Neighborhood),
+		City:         strings.TrimSpace(location.City),
+		State:        state,
+		ZipCode:      strings.TrimSpace(location.PostalCode),
+		Country:      country,
+
+		// Characteristics
+		Bedrooms:      details.Bedrooms.Int(),
+		Bathrooms:     details.Bathrooms.Int(),
+		Suites:        details.Suites.Int(),
+		ParkingSpaces: details.Garage.Int(),
+		TotalArea:     totalArea,
+		UsableArea:    usableArea,
+
+		// Pricing (use sale price as primary)
+		PriceAmount:   details.ListPrice.Float(),
+		PriceCurrency: "BRL",
+
+		// Status and visibility (listings in the feed are being advertised)
+		Status:     models.PropertyStatusAvailable,
+		Visibility: models.PropertyVisibilityNetwork,
+
+		// Transaction type and rental info
+		TransactionType: &transactionType,
+		RentalInfo:      rentalInfo,
+
+		Slug: adapters.Slug(title, reference),
+
+		// Deduplication fields
+		Fingerprint: adapters.Fingerprint(location.Address, location.StreetNumber, location.Neighborhood,
+			location.City, propertyType, totalArea),
+		DataCompleteness: dataCompleteness,
+
+		// Timestamps
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	// Geolocation (skipped when missing, malformed or the 0,0 placeholder)
+	if lat, lng, ok := adapters.ParseCoordinates(location.Latitude, location.Longitude); ok {
+		property.SetCoordinates(lat, lng)
+	}
+
+	if title == "" {
+		title = fmt.Sprintf("Imóvel %s", reference)
+	}
+	if description == "" {
+		description = fmt.Sprintf("Imóvel importado - Ref: %s", reference)
+	}
+
+	return adapters.PropertyPayload{
+		Property: property,
+		// VRSync feeds carry the agency contact, never the owner: placeholder owner
+		Owner: adapters.OwnerPayload{
+			Name:        "Proprietário de " + reference,
+			OwnerStatus: models.OwnerStatusIncomplete,
+		},
+		Photos:      photoURLs,
+		Title:       title,
+		Description: description,
+	}
+}
+
+// normalizeTransactionType maps the VRSync TransactionType to TransactionType
+func normalizeTransactionType(transactionType string) models.TransactionType {
+	switch strings.ToLower(strings.TrimSpace(transactionType)) {
+	case "for rent":
+		return models.TransactionTypeRent
+	case "sale/rent":
+		return models.TransactionTypeBoth
+	default:
+		return models.TransactionTypeSale
+	}
+}
+
+// buildRentalInfo builds rental info from the rental price, nil when the listing has none
+func buildRentalInfo(details *Details) *models.RentalInfo {
+	rentalPrice := details.RentalPrice.Float()
+	if rentalPrice <= 0 {
+		return nil
+	}
+
+	condoFee := details.PropertyAdministrationFee.Float()
+	rentalInfo := &models.RentalInfo{
+		MonthlyRent:        rentalPrice,
+		CondoFee:           condoFee,
+		DepositMonths:      3, // default
+		AcceptedGuarantees: []string{"fiador", "caucao"},
+		RentalType:         models.RentalTypeTraditional,
+		MinRentalPeriod:    12,
+		ImmediateOccupancy: true,
+	}
+
+	switch strings.ToLower(strings.TrimSpace(details.RentalPrice.Period)) {
+	case "yearly":
+		rentalInfo.MonthlyRent = rentalPrice / 12
+	case "daily":
+		rentalInfo.RentalType = models.RentalTypeVacation
+		rentalInfo.MinRentalPeriod = 1
+		rentalInfo.DailyRate = rentalPrice
+	case "weekly":
+		rentalInfo.RentalType = models.RentalTypeVacation
+		rentalInfo.MinRentalPeriod = 1
+	}
+
+	rentalInfo.TotalMonthlyCost = rentalInfo.MonthlyRent + condoFee
+	return rentalInfo
+}
+
+// normalizeType maps the VRSync usage and property type (ex: "Residential / Apartment") to PropertyType
+func normalizeType(usageType, propertyType string) models.PropertyType {
+	usage := strings.ToLower(strings.TrimSpace(usageType))
+	kind := strings.ToLower(strings.TrimSpace(propertyType))
+	if i := strings.Index(kind, "/"); i >= 0 {
+		if usage == "" {
+			usage = strings.TrimSpace(kind[:i])
+		}
+		kind = strings.TrimSpace(kind[i+1:])
+	}
+
+	switch kind {
+	case "land lot", "allotment land", "farm ranch", "agricultural":
+		return models.PropertyTypeLand
+	}
+
+	if usage == "commercial" {
+		return models.PropertyTypeCommercial
+	}
+
+	switch kind {
+	case "home", "sobrado", "village house", "condo":
+		return models.PropertyTypeHouse
+	case "business", "office", "building", "industrial", "warehouse", "loja", "consultorio":
+		return models.PropertyTypeCommercial
+	}
+
+	// Apartment, Penthouse, Flat, Kitnet... and unknown types
+	return models.PropertyTypeApartment
+}
+
+// photoURLs returns the image URLs, primary image first
+func photoURLs(media []Media) []string {
+	urls := make([]string, 0, len(media))
+	for _, item := range media {
+		url := strings.TrimSpace(item.URL)
+		if url == "" || (item.Medium != "" && !strings.EqualFold(item.Medium, "image")) {
+			continue
+		}
+		if item.Primary {
+			urls = append([]string{url}, urls...)
+		} else {
+			urls = append(urls, url)
+		}
+	}
+	return urls
+}
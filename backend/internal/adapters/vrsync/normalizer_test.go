@@ -0,0 +1,190 @@
+package vrsync
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/adapters"
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+const sampleFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<ListingDataFeed xmlns="http://www.vivareal.com/schemas/1.0/VRSync">
+  <Header><Provider>CRM</Provider></Header>
+  <Listings>
+    <Listing>
+      <ListingID>AP0042</ListingID>
+      <Title><![CDATA[Apartamento 2 dormitórios em Pinheiros]]></Title>
+      <TransactionType>Sale/Rent</TransactionType>
+      <Media>
+        <Item medium="image" caption="Sala">https://cdn.example.com/sala.jpg</Item>
+        <Item medium="video">https://video.example.com/tour</Item>
+        <Item medium="image" primary="true">https://cdn.example.com/fachada.jpg</Item>
+      </Media>
+      <Details>
+        <UsageType>Residential</UsageType>
+        <PropertyType>Residential / Apartment</PropertyType>
+        <Description><![CDATA[Apartamento reformado próximo ao metrô.]]></Description>
+        <ListPrice currency="BRL">750000</ListPrice>
+        <RentalPrice currency="BRL" period="Monthly">3.500,00</RentalPrice>
+        <PropertyAdministrationFee currency="BRL">850</PropertyAdministrationFee>
+        <LivingArea unit="square metres">72</LivingArea>
+        <LotArea unit="square metres"></LotArea>
+        <Bedrooms>2</Bedrooms>
+        <Bathrooms>2</Bathrooms>
+        <Suites>1</Suites>
+        <Garage type="Parking Space">1</Garage>
+      </Details>
+      <Location displayAddress="All">
+        <Country abbreviation="BR">Brasil</Country>
+        <State abbreviation="SP">São Paulo</State>
+        <City>São Paulo</City>
+        <Neighborhood>Pinheiros</Neighborhood>
+        <Address>Rua dos Pinheiros</Address>
+        <StreetNumber>100</StreetNumber>
+        <PostalCode>05422-000</PostalCode>
+        <Latitude>-23.5614</Latitude>
+        <Longitude>-46.6819</Longitude>
+      </Location>
+    </Listing>
+    <Listing>
+      <ListingID>TE0007</ListingID>
+      <TransactionType>For Sale</TransactionType>
+      <Details>
+        <PropertyType>Commercial / Land Lot</PropertyType>
+        <ListPrice currency="BRL">300000</ListPrice>
+        <LotArea unit="square metres">450</LotArea>
+      </Details>
+      <Location>
+        <State>PR</State>
+        <City>Curitiba</City>
+        <Latitude>0</Latitude>
+        <Longitude>0</Longitude>
+      </Location>
+    </Listing>
+  </Listings>
+</ListingDataFeed>`
+
+func parseSample(t *testing.T) []adapters.PropertyPayload {
+	t.Helper()
+	feed, err := ParseFeed(strings.NewReader(sampleFeed))
+	if err != nil {
+		t.Fatalf("ParseFeed() error = %v", err)
+	}
+	if len(feed.Listings) != 2 {
+		t.Fatalf("ParseFeed() returned %d listings, expected 2", len(feed.Listings))
+	}
+
+	payloads := make([]adapters.PropertyPayload, 0, len(feed.Listings))
+	for i := range feed.Listings {
+		payloads = append(payloads, NormalizeListing(&feed.Listings[i], "tenant-1"))
+	}
+	return payloads
+}
+
+func TestNormalizeListing(t *testing.T) {
+	payload := parseSample(t)[0]
+	property := payload.Property
+
+	if property.ExternalSource != Source || property.ExternalID != "AP0042" || property.Reference != "AP0042" {
+		t.Errorf("external identifiers = %q/%q/%q", property.ExternalSource, property.ExternalID, property.Reference)
+	}
+	if property.TenantID != "tenant-1" {
+		t.Errorf("TenantID = %q, expected tenant-1", property.TenantID)
+	}
+	if property.PropertyType != models.PropertyTypeApartment {
+		t.Errorf("PropertyType = %q, expected apartment", property.PropertyType)
+	}
+	if property.State != "SP" || property.Country != "BR" || property.Neighborhood != "Pinheiros" {
+		t.Errorf("location = %q/%q/%q", property.State, property.Country, property.Neighborhood)
+	}
+	if property.Bedrooms != 2 || property.Suites != 1 || property.ParkingSpaces != 1 {
+		t.Errorf("characteristics = %d bedrooms, %d suites, %d parking spaces", property.Bedrooms, property.Suites, property.ParkingSpaces)
+	}
+	// Empty LotArea falls back to the living area
+	if property.UsableArea != 72 || property.TotalArea != 72 {
+		t.Errorf("areas = %v usable, %v total, expected 72/72", property.UsableArea, property.TotalArea)
+	}
+	if property.PriceAmount != 750000 {
+		t.Errorf("PriceAmount = %v, expected 750000", property.PriceAmount)
+	}
+	if property.TransactionType == nil || *property.TransactionType != models.TransactionTypeBoth {
+		t.Errorf("TransactionType = %v, expected both", property.TransactionType)
+	}
+	if property.RentalInfo == nil || property.RentalInfo.MonthlyRent != 3500 || property.RentalInfo.TotalMonthlyCost != 4350 {
+		t.Errorf("RentalInfo = %+v, expected 3500 rent and 4350 total", property.RentalInfo)
+	}
+	if !property.HasCoordinates() {
+		t.Error("expected coordinates to be set")
+	}
+
+	expectedFingerprint := adapters.Fingerprint("Rua dos Pinheiros", "100", "Pinheiros", "São Paulo", models.PropertyTypeApartment, 72)
+	if property.Fingerprint != expectedFingerprint {
+		t.Errorf("Fingerprint = %q, expected %q", property.Fingerprint, expectedFingerprint)
+	}
+
+	// Primary image first, videos skipped
+	expectedPhotos := []string{"https://cdn.example.com/fachada.jpg", "https://cdn.example.com/sala.jpg"}
+	if strings.Join(payload.Photos, ",") != strings.Join(expectedPhotos, ",") {
+		t.Errorf("Photos = %v, expected %v", payload.Photos, expectedPhotos)
+	}
+	if payload.Title != "Apartamento 2 dormitórios em Pinheiros" {
+		t.Errorf("Title = %q", payload.Title)
+	}
+	if property.DataCompleteness != "complete" {
+		t.Errorf("DataCompleteness = %q, expected complete", property.DataCompleteness)
+	}
+	if payload.Owner.OwnerStatus != models.OwnerStatusIncomplete {
+		t.Errorf("Owner.OwnerStatus = %q, expected incomplete", payload.Owner.OwnerStatus)
+	}
+}
+
+func TestNormalizeListing_Minimal(t *testing.T) {
+	payload := parseSample(t)[1]
+	property := payload.Property
+
+	if property.PropertyType != models.PropertyTypeLand {
+		t.Errorf("PropertyType = %q, expected land", property.PropertyType)
+	}
+	if property.State != "PR" {
+		t.Errorf("State = %q, expected PR", property.State)
+	}
+	if property.TransactionType == nil || *property.TransactionType != models.TransactionTypeSale {
+		t.Errorf("TransactionType = %v, expected sale", property.TransactionType)
+	}
+	if property.RentalInfo != nil {
+		t.Errorf("RentalInfo = %+v, expected nil", property.RentalInfo)
+	}
+	if property.HasCoordinates() {
+		t.Error("0,0 placeholder coordinates should be skipped")
+	}
+	if payload.Title != "Imóvel TE0007" || property.DataCompleteness != "partial" {
+		t.Errorf("Title = %q, DataCompleteness = %q", payload.Title, property.DataCompleteness)
+	}
+}
+
+func TestNormalizeType(t *testing.T) {
+	tests := []struct {
+		usage    string
+		kind     string
+		expected models.PropertyType
+	}{
+		{"Residential", "Residential / Apartment", models.PropertyTypeApartment},
+		{"Residential", "Residential / Penthouse", models.PropertyTypeApartment},
+		{"Residential", "Residential / Home", models.PropertyTypeHouse},
+		{"Residential", "Residential / Condo", models.PropertyTypeHouse},
+		{"", "Residential / Sobrado", models.PropertyTypeHouse},
+		{"Residential", "Residential / Land Lot", models.PropertyTypeLand},
+		{"Commercial", "Commercial / Office", models.PropertyTypeCommercial},
+		{"", "Commercial / Building", models.PropertyTypeCommercial},
+		{"", "Unknown", models.PropertyTypeApartment},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.kind, func(t *testing.T) {
+			if got := normalizeType(tt.usage, tt.kind); got != tt.expected {
+				t.Errorf("normalizeType(%q, %q) = %q, expected %q", tt.usage, tt.kind, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package vrsync
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+// ListingFromProperty builds the feed listing of a property. Title, description
+// and photos (cover first) come from the canonical listing.
+func ListingFromProperty(property *models.Property, title, description string, photos []string, contact *ContactInfo) Listing {
+	usageType, propertyType := exportType(property.PropertyType)
+
+	transactionType := models.TransactionTypeSale
+	if property.TransactionType != nil {
+		transactionType = *property.TransactionType
+	}
+
+	listing := Listing{
+		ListingID:       listingID(property),
+		Title:           title,
+		TransactionType: exportTransactionType(transactionType),
+		Details: Details{
+			UsageType:    usageType,
+			PropertyType: propertyType,
+			Description:  description,
+			Bedrooms:     count(property.Bedrooms),
+			Bathrooms:    count(property.Bathrooms),
+			Suites:       count(property.Suites),
+			Garage:       count(property.ParkingSpaces),
+		},
+		Location: Location{
+			DisplayAddress: "All",
+			Country:        Place{Name: "Brasil", Abbreviation: property.Country},
+			State:          Place{Name: property.State, Abbreviation: property.State},
+			City:           property.City,
+			Neighborhood:   property.Neighborhood,
+			Address:        property.Street,
+			StreetNumber:   property.Number,
+			Complement:     property.Complement,
+			PostalCode:     property.ZipCode,
+		},
+		ContactInfo: contact,
+	}
+
+	if listing.Location.Country.Abbreviation == "" {
+		listing.Location.Country.Abbreviation = "BR"
+	}
+
+	if property.HasCoordinates() {
+		listing.Location.Latitude = formatNumber(*property.Latitude)
+		listing.Location.Longitude = formatNumber(*property.Longitude)
+	}
+
+	for i, url := range photos {
+		listing.Media = append(listing.Media, Media{URL: url, Medium: "image", Primary: i == 0})
+	}
+
+	// Prices
+	if transactionType != models.TransactionTypeRent {
+		listing.Details.ListPrice = money(property.PriceAmount)
+	}
+	if transactionType != models.TransactionTypeSale && property.RentalInfo != nil {
+		rental := property.RentalInfo
+		listing.Details.RentalPrice = money(rental.MonthlyRent)
+		if listing.Details.RentalPrice != nil {
+			listing.Details.RentalPrice.Period = "Monthly"
+			if rental.RentalType == models.RentalTypeVacation {
+				listing.Details.RentalPrice.Period = "Daily"
+			}
+		}
+		// Short stays are advertised at their daily rate
+		if daily := money(rental.DailyRate); daily != nil && rental.IsShortStay() {
+			daily.Period = "Daily"
+			listing.Details.RentalPrice = daily
+		}
+		listing.Details.PropertyAdministrationFee = money(rental.CondoFee)
+		listing.Details.YearlyTax = money(rental.IPTUMonthly * 12)
+	}
+
+	// Areas: the usable area is the living area, a larger total area is the lot
+	livingArea := property.UsableArea
+	if livingArea == 0 {
+		livingArea = property.TotalArea
+	}
+	listing.Details.LivingArea = area(livingArea)
+	if property.TotalArea != livingArea {
+		listing.Details.LotArea = area(property.TotalArea)
+	}
+
+	return listing
+}
+
+// MarshalListing renders a listing as a <Listing> element
+func MarshalListing(listing Listing) ([]byte, error) {
+	out, err := xml.MarshalIndent(listing, "    ", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode listing %s: %w", listing.ListingID, err)
+	}
+	return out, nil
+}
+
+// WriteFeed writes a complete feed from <Listing> elements rendered by MarshalListing
+func WriteFeed(w io.Writer, header Header, listings [][]byte) error {
+	headerXML, err := xml.MarshalIndent(header, "  ", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode feed header: %w", err)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	fmt.Fprintf(&buf, "<ListingDataFeed xmlns=%q>\n  ", Namespace)
+	buf.Write(headerXML)
+	buf.WriteString("\n  <Listings>\n")
+	for _, listing := range listings {
+		buf.WriteString("    ")
+		buf.Write(listing)
+		buf.WriteString("\n")
+	}
+	buf.WriteString("  </Listings>\n</ListingDataFeed>\n")
+
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// listingID is the portal-facing identifier: the reference, or the property ID
+func listingID(property *models.Property) string {
+	if property.Reference != "" {
+		return property.Reference
+	}
+	return property.ID
+}
+
+// exportTransactionType maps TransactionType to the VRSync TransactionType
+func exportTransactionType(transactionType models.TransactionType) string {
+	switch transactionType {
+	case models.TransactionTypeRent:
+		return "For Rent"
+	case models.TransactionTypeBoth:
+		return "Sale/Rent"
+	default:
+		return "For Sale"
+	}
+}
+
+// exportType maps PropertyType to the VRSync usage and property type
+func exportType(propertyType models.PropertyType) (string, string) {
+	switch propertyType {
+	case models.PropertyTypeHouse:
+		return "Residential", "Residential / Home"
+	case models.PropertyTypeLand, models.PropertyTypeCondoLot, models.PropertyTypeBuildingLot:
+		return "Residential", "Residential / Land Lot"
+	case models.PropertyTypeCommercial:
+		return "Commercial", "Commercial / Business"
+	default:
+		return "Residential", "Residential / Apartment"
+	}
+}
+
+func money(value float64) *Amount {
+	if value <= 0 {
+		return nil
+	}
+	return &Amount{Value: formatNumber(value), Currency: "BRL"}
+}
+
+func area(value float64) *Amount {
+	if value <= 0 {
+		return nil
+	}
+	return &Amount{Value: formatNumber(value), Unit: "square metres"}
+}
+
+func count(value int) *Amount {
+	if value <= 0 {
+		return nil
+	}
+	return &Amount{Value: strconv.Itoa(value)}
+}
+
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,77 @@
+package vrsync
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+func TestListingFromProperty_RoundTrip(t *testing.T) {
+	transactionType := models.TransactionTypeBoth
+	property := &models.Property{
+		ID:              "prop-1",
+		Reference:       "CA0010",
+		PropertyType:    models.PropertyTypeHouse,
+		Street:          "Rua das Flores",
+		Number:          "25",
+		Neighborhood:    "Centro",
+		City:            "Curitiba",
+		State:           "PR",
+		Country:         "BR",
+		Bedrooms:        3,
+		ParkingSpaces:   2,
+		UsableArea:      150,
+		TotalArea:       300,
+		PriceAmount:     980000,
+		TransactionType: &transactionType,
+		RentalInfo: &models.RentalInfo{
+			MonthlyRent: 5200,
+			CondoFee:    300,
+			RentalType:  models.RentalTypeTraditional,
+		},
+	}
+	property.SetCoordinates(-25.4284, -49.2733)
+
+	listing := ListingFromProperty(property, "Casa com quintal", "Casa térrea", []string{"https://cdn.example.com/1.jpg", "https://cdn.example.com/2.jpg"}, &ContactInfo{Name: "Imobiliária"})
+	out, err := MarshalListing(listing)
+	if err != nil {
+		t.Fatalf("MarshalListing() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteFeed(&buf, Header{Provider: "Imobiliária"}, [][]byte{out}); err != nil {
+		t.Fatalf("WriteFeed() error = %v", err)
+	}
+
+	feed, err := ParseFeed(&buf)
+	if err != nil {
+		t.Fatalf("ParseFeed() error = %v", err)
+	}
+	if feed.Header.Provider != "Imobiliária" || len(feed.Listings) != 1 {
+		t.Fatalf("feed = %+v", feed)
+	}
+
+	parsed := feed.Listings[0]
+	if parsed.Details.RentalPrice == nil || parsed.Details.RentalPrice.Period != "Monthly" {
+		t.Errorf("RentalPrice = %+v, expected monthly rent", parsed.Details.RentalPrice)
+	}
+	if len(parsed.Media) != 2 || !parsed.Media[0].Primary || parsed.Media[1].Primary {
+		t.Errorf("Media = %+v, expected the first photo as primary", parsed.Media)
+	}
+
+	// Importing the exported listing yields the same property data
+	imported := NormalizeListing(&parsed, "tenant-1").Property
+	if imported.Reference != "CA0010" || imported.PropertyType != models.PropertyTypeHouse {
+		t.Errorf("imported reference/type = %q/%q", imported.Reference, imported.PropertyType)
+	}
+	if imported.UsableArea != 150 || imported.TotalArea != 300 {
+		t.Errorf("imported areas = %v/%v, expected 150/300", imported.UsableArea, imported.TotalArea)
+	}
+	if imported.PriceAmount != 980000 || imported.RentalInfo == nil || imported.RentalInfo.MonthlyRent != 5200 {
+		t.Errorf("imported prices = %v sale, %+v rental", imported.PriceAmount, imported.RentalInfo)
+	}
+	if *imported.TransactionType != models.TransactionTypeBoth || imported.State != "PR" || !imported.HasCoordinates() {
+		t.Errorf("imported transaction/state/coordinates = %v/%q/%v", *imported.TransactionType, imported.State, imported.HasCoordinates())
+	}
+}
@@ -3,7 +3,9 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -16,6 +18,9 @@ type Config struct {
 	FirestoreDatabase   string
 	GCSBucketName       string
 
+	// Repository backend: "firestore" (default) or "memory" for offline runs
+	RepositoryBackend string
+
 	// Server configuration
 	Port        string
 	Host        string
@@ -27,6 +32,34 @@ type Config struct {
 
 	// Logging configuration
 	LogLevel string
+
+	// Background job scheduler
+	SchedulerEnabled  bool
+	SchedulerTimezone string // Time zone of the job cron expressions
+
+	// Full-text search index (in memory, per instance)
+	SearchIndexRefreshInterval time.Duration // How often each instance rebuilds it to see the others' writes; 0 disables
+
+	// Outbound notifications (each channel is enabled when its credentials are set)
+	WhatsAppAPIURL        string // WhatsApp Business Cloud API base URL
+	WhatsAppPhoneNumberID string
+	WhatsAppAccessToken   string
+	WhatsAppAppSecret     string // Verifies delivery webhook signatures
+	WhatsAppVerifyToken   string // Webhook subscription handshake
+	TwilioAccountSID      string
+	TwilioAuthToken       string
+	TwilioFromNumber      string // Sender number or messaging service SID
+	TwilioStatusCallback  string // Public URL of /api/v1/webhooks/sms
+	SMTPHost              string
+	SMTPPort              int
+	SMTPUser              string
+	SMTPPassword          string
+	EmailFrom             string
+	EmailFromName         string
+
+	// Rent payment provider webhook (enabled when the secret is set)
+	PaymentProviderName  string // Path segment of /api/v1/webhooks/payments/{provider}
+	PaymentWebhookSecret string // Verifies the X-Signature HMAC of the notifications
 }
 
 // Load loads configuration from environment variables
@@ -41,6 +74,7 @@ func Load() (*Config, error) {
 		FirebaseCredentials: getEnv("GOOGLE_APPLICATION_CREDENTIALS", "./config/firebase-adminsdk.json"),
 		FirestoreDatabase:   getEnv("FIRESTORE_DATABASE", "imob-dev"),
 		GCSBucketName:       getEnv("GCS_BUCKET_NAME", ""),
+		RepositoryBackend:   getEnv("REPOSITORY_BACKEND", "firestore"),
 
 		// Server
 		Port:        getEnv("PORT", "8080"),
@@ -53,8 +87,44 @@ func Load() (*Config, error) {
 
 		// Logging
 		LogLevel: getEnv("LOG_LEVEL", "info"),
+
+		// Scheduler
+		SchedulerEnabled:  getEnv("SCHEDULER_ENABLED", "true") == "true",
+		SchedulerTimezone: getEnv("SCHEDULER_TIMEZONE", "America/Sao_Paulo"),
+
+		// Notifications
+		WhatsAppAPIURL:        getEnv("WHATSAPP_API_URL", "https://graph.facebook.com/v19.0"),
+		WhatsAppPhoneNumberID: getEnv("WHATSAPP_PHONE_NUMBER_ID", ""),
+		WhatsAppAccessToken:   getEnv("WHATSAPP_ACCESS_TOKEN", ""),
+		WhatsAppAppSecret:     getEnv("WHATSAPP_APP_SECRET", ""),
+		WhatsAppVerifyToken:   getEnv("WHATSAPP_VERIFY_TOKEN", ""),
+		TwilioAccountSID:      getEnv("TWILIO_ACCOUNT_SID", ""),
+		TwilioAuthToken:       getEnv("TWILIO_AUTH_TOKEN", ""),
+		TwilioFromNumber:      getEnv("TWILIO_FROM_NUMBER", ""),
+		TwilioStatusCallback:  getEnv("TWILIO_STATUS_CALLBACK_URL", ""),
+		SMTPHost:              getEnv("SMTP_HOST", ""),
+		SMTPUser:              getEnv("SMTP_USER", ""),
+		SMTPPassword:          getEnv("SMTP_PASSWORD", ""),
+		EmailFrom:             getEnv("EMAIL_FROM", getEnv("SMTP_USER", "")),
+		EmailFromName:         getEnv("EMAIL_FROM_NAME", "Ecosistema Imob"),
+
+		// Payments
+		PaymentProviderName:  getEnv("PAYMENT_PROVIDER_NAME", "gateway"),
+		PaymentWebhookSecret: getEnv("PAYMENT_WEBHOOK_SECRET", ""),
 	}
 
+	smtpPort, err := strconv.Atoi(getEnv("SMTP_PORT", "587"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration: SMTP_PORT must be a number")
+	}
+	cfg.SMTPPort = smtpPort
+
+	searchRefresh, err := time.ParseDuration(getEnv("SEARCH_INDEX_REFRESH_INTERVAL", "10m"))
+	if err != nil || searchRefresh < 0 {
+		return nil, fmt.Errorf("invalid configuration: SEARCH_INDEX_REFRESH_INTERVAL must be a duration (ex: 10m)")
+	}
+	cfg.SearchIndexRefreshInterval = searchRefresh
+
 	// Validate required configuration
 	if err := cfg.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid configuration: %w", err)
@@ -63,19 +133,26 @@ func Load() (*Config, error) {
 	return cfg, nil
 }
 
-// Validate validates the configuration
+// Validate validates the configuration. Firebase settings are only required
+// by the Firestore backend.
 func (c *Config) Validate() error {
-	if c.FirebaseProjectID == "" {
-		return fmt.Errorf("FIREBASE_PROJECT_ID is required")
+	if c.RepositoryBackend != "firestore" && c.RepositoryBackend != "memory" {
+		return fmt.Errorf("REPOSITORY_BACKEND must be 'firestore' or 'memory'")
 	}
 
-	if c.FirebaseCredentials == "" {
-		return fmt.Errorf("GOOGLE_APPLICATION_CREDENTIALS is required")
-	}
+	if !c.UseMemoryRepositories() {
+		if c.FirebaseProjectID == "" {
+			return fmt.Errorf("FIREBASE_PROJECT_ID is required")
+		}
+
+		if c.FirebaseCredentials == "" {
+			return fmt.Errorf("GOOGLE_APPLICATION_CREDENTIALS is required")
+		}
 
-	// Check if credentials file exists
-	if _, err := os.Stat(c.FirebaseCredentials); os.IsNotExist(err) {
-		return fmt.Errorf("Firebase credentials file not found at: %s", c.FirebaseCredentials)
+		// Check if credentials file exists
+		if _, err := os.Stat(c.FirebaseCredentials); os.IsNotExist(err) {
+			return fmt.Errorf("Firebase credentials file not found at: %s", c.FirebaseCredentials)
+		}
 	}
 
 	if c.Port == "" {
@@ -95,6 +172,31 @@ func (c *Config) IsProduction() bool {
 	return c.Environment == "production" || c.Environment == "prod"
 }
 
+// UseMemoryRepositories returns true when the in-memory repository backend is selected
+func (c *Config) UseMemoryRepositories() bool {
+	return c.RepositoryBackend == "memory"
+}
+
+// WhatsAppEnabled returns true when the WhatsApp Business Cloud API is configured
+func (c *Config) WhatsAppEnabled() bool {
+	return c.WhatsAppPhoneNumberID != "" && c.WhatsAppAccessToken != ""
+}
+
+// SMSEnabled returns true when the SMS provider (Twilio) is configured
+func (c *Config) SMSEnabled() bool {
+	return c.TwilioAccountSID != "" && c.TwilioAuthToken != "" && c.TwilioFromNumber != ""
+}
+
+// EmailEnabled returns true when the SMTP server is configured
+func (c *Config) EmailEnabled() bool {
+	return c.SMTPHost != "" && c.EmailFrom != ""
+}
+
+// PaymentWebhookEnabled returns true when the payment provider webhook is configured
+func (c *Config) PaymentWebhookEnabled() bool {
+	return c.PaymentWebhookSecret != ""
+}
+
 // ServerAddr returns the server address in host:port format
 func (c *Config) ServerAddr() string {
 	return fmt.Sprintf("%s:%s", c.Host, c.Port)
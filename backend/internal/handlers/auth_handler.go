@@ -262,47 +262,19 @@ func (h *AuthHandler) Signup(c *gin.Context) {
 	userID := uuid.New().String()
 
 	// Determine role based on whether user is a broker
+	// Permissions come from the role defaults (models.DefaultPermissionsForRole)
 	var role string
 	var creci string
-	var permissions []string
 
 	if req.IsUserBroker {
 		// User is a broker (has CRECI)
 		role = "broker_admin" // First broker is always admin too
 		creci = req.UserCRECI
-		permissions = []string{
-			"properties.view_all",
-			"properties.create",
-			"properties.edit_all",
-			"properties.delete",
-			"brokers.view",
-			"brokers.create",
-			"brokers.edit",
-			"users.view",
-			"users.create",
-			"users.edit",
-			"settings.view",
-			"settings.edit",
-		}
 		log.Printf("✅ Creating broker admin with CRECI: %s", req.UserCRECI)
 	} else {
 		// User is admin but not a broker
 		role = "admin"
 		creci = ""
-		permissions = []string{
-			"properties.view_all",
-			"properties.create",
-			"properties.edit_all",
-			"properties.delete",
-			"brokers.view",
-			"brokers.create",
-			"brokers.edit",
-			"users.view",
-			"users.create",
-			"users.edit",
-			"settings.view",
-			"settings.edit",
-		}
 		log.Printf("✅ Creating administrative user (no CRECI)")
 	}
 
@@ -316,7 +288,6 @@ func (h *AuthHandler) Signup(c *gin.Context) {
 		CRECI:       creci,
 		Role:        role,
 		IsActive:    true,
-		Permissions: permissions,
 		CreatedAt:   now,
 		UpdatedAt:   now,
 	}
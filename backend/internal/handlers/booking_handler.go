@@ -0,0 +1,657 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/ical"
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// BookingHandler handles short-stay calendar and booking HTTP requests
+type BookingHandler struct {
+	bookingService *services.BookingService
+}
+
+// NewBookingHandler creates a new booking handler
+func NewBookingHandler(bookingService *services.BookingService) *BookingHandler {
+	return &BookingHandler{
+		bookingService: bookingService,
+	}
+}
+
+// RegisterRoutes registers stay calendar and booking routes (tenant-scoped)
+func (h *BookingHandler) RegisterRoutes(router *gin.RouterGroup) {
+	calendars := router.Group("/stay-calendars")
+	{
+		calendars.GET("/:property_id", h.GetCalendar)
+		calendars.PUT("/:property_id", h.SetCalendar)
+		calendars.POST("/:property_id/sync", h.SyncCalendar)
+		calendars.POST("/:property_id/export-token", h.RotateExportToken)
+	}
+
+	bookings := router.Group("/bookings")
+	{
+		bookings.GET("", h.ListBookings)
+		bookings.POST("", h.CreateBooking)
+		bookings.GET("/:id", h.GetBooking)
+		bookings.POST("/:id/confirm", h.ConfirmBooking)
+		bookings.POST("/:id/decline", h.DeclineBooking)
+		bookings.POST("/:id/cancel", h.CancelBooking)
+	}
+}
+
+// RegisterPublicRoutes registers the availability and booking request routes of the public portal
+func (h *BookingHandler) RegisterPublicRoutes(public *gin.RouterGroup) {
+	public.GET("/properties/:id/availability", h.GetAvailability)
+	public.GET("/properties/:id/stay-quote", h.GetStayQuote)
+	public.POST("/properties/:property_id/bookings", h.RequestBooking)
+	public.GET("/properties/:id/availability.ics", h.ExportCalendar)
+}
+
+// RequestBookingRequest represents the request body for requesting a stay from the property page
+type RequestBookingRequest struct {
+	Name         string `json:"name" binding:"required"`
+	Email        string `json:"email"`
+	Phone        string `json:"phone"`
+	Message      string `json:"message,omitempty"`
+	CheckIn      string `json:"check_in" binding:"required"`  // YYYY-MM-DD
+	CheckOut     string `json:"check_out" binding:"required"` // YYYY-MM-DD (departure)
+	Guests       int    `json:"guests"`
+	ConsentGiven bool   `json:"consent_given" binding:"required"`
+	ConsentText  string `json:"consent_text" binding:"required"`
+	UTMSource    string `json:"utm_source,omitempty"`
+	UTMCampaign  string `json:"utm_campaign,omitempty"`
+	UTMMedium    string `json:"utm_medium,omitempty"`
+	Referrer     string `json:"referrer,omitempty"`
+}
+
+// CreateBookingRequest represents the request body for booking or blocking dates from the dashboard
+type CreateBookingRequest struct {
+	PropertyID string               `json:"property_id" binding:"required"`
+	Status     models.BookingStatus `json:"status"`    // confirmed (default) or blocked
+	CheckIn    string               `json:"check_in"`  // YYYY-MM-DD
+	CheckOut   string               `json:"check_out"` // YYYY-MM-DD (departure)
+	LeadID     string               `json:"lead_id"`
+	GuestName  string               `json:"guest_name"` // Required for confirmed stays
+	GuestEmail string               `json:"guest_email"`
+	GuestPhone string               `json:"guest_phone"`
+	Guests     int                  `json:"guests"`
+	Notes      string               `json:"notes"`
+}
+
+// CloseBookingRequest represents the request body for declining or cancelling a booking
+type CloseBookingRequest struct {
+	Reason string `json:"reason"`
+}
+
+// GetAvailability returns the public availability calendar of a property
+// @Summary Availability of a property let by the night
+// @Description Night by night: available or not, nightly rate and minimum stay. Bookings are not disclosed.
+// @Tags bookings
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Property ID"
+// @Param from query string false "First night (YYYY-MM-DD, default today)"
+// @Param days query int false "Number of nights (default 90, max 366)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/properties/{id}/availability [get]
+func (h *BookingHandler) GetAvailability(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	propertyID := c.Param("id")
+
+	from := time.Now()
+	if value := c.Query("from"); value != "" {
+		parsed, err := parseStayDate(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "from must be YYYY-MM-DD",
+			})
+			return
+		}
+		from = parsed
+	}
+	days, _ := strconv.Atoi(c.Query("days"))
+
+	availability, err := h.bookingService.Availability(c.Request.Context(), tenantID, propertyID, from, days)
+	if err != nil {
+		h.respondBookingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    availability,
+	})
+}
+
+// GetStayQuote prices a stay
+// @Summary Quote a stay
+// @Description Price of the nights (seasonal rates) and cleaning fee; 409 when the dates are taken
+// @Tags bookings
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Property ID"
+// @Param check_in query string true "Check-in (YYYY-MM-DD)"
+// @Param check_out query string true "Check-out (YYYY-MM-DD)"
+// @Param guests query int false "Guests"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/{tenant_id}/properties/{id}/stay-quote [get]
+func (h *BookingHandler) GetStayQuote(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	propertyID := c.Param("id")
+
+	checkIn, checkOut, err := parseStay(c.Query("check_in"), c.Query("check_out"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	guests, _ := strconv.Atoi(c.Query("guests"))
+
+	quote, err := h.bookingService.Quote(c.Request.Context(), tenantID, propertyID, checkIn, checkOut, guests)
+	if err != nil {
+		h.respondBookingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    quote,
+	})
+}
+
+// RequestBooking asks for a stay from the property page
+// @Summary Request a booking
+// @Description Create a lead and a booking request that holds the nights for 48 hours while the agency confirms. LGPD consent required.
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param property_id path string true "Property ID"
+// @Param body body RequestBookingRequest true "Booking request"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/{tenant_id}/properties/{property_id}/bookings [post]
+func (h *BookingHandler) RequestBooking(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	propertyID := c.Param("property_id")
+
+	var req RequestBookingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	// LGPD validation: consent is mandatory
+	if !req.ConsentGiven {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "consent_given must be true (LGPD compliance)",
+		})
+		return
+	}
+
+	checkIn, checkOut, err := parseStay(req.CheckIn, req.CheckOut)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	lead := &models.Lead{
+		TenantID:     tenantID,
+		PropertyID:   propertyID,
+		Name:         req.Name,
+		Email:        req.Email,
+		Phone:        req.Phone,
+		Message:      req.Message,
+		Channel:      models.LeadChannelForm,
+		ConsentGiven: req.ConsentGiven,
+		ConsentText:  req.ConsentText,
+		ConsentIP:    c.ClientIP(),
+		UTMSource:    req.UTMSource,
+		UTMCampaign:  req.UTMCampaign,
+		UTMMedium:    req.UTMMedium,
+		Referrer:     req.Referrer,
+	}
+
+	booking, err := h.bookingService.RequestBooking(c.Request.Context(), lead, checkIn, checkOut, req.Guests)
+	if err != nil {
+		h.respondBookingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    booking,
+		"lead_id": lead.ID,
+		"message": "Pedido de reserva enviado. As datas ficam reservadas por 48 horas até a confirmação.",
+	})
+}
+
+// ExportCalendar returns the iCalendar feed of the nights taken at a property
+// @Summary Property availability calendar (.ics)
+// @Description Feed for Airbnb and Booking.com calendar sync; the token comes from export-token
+// @Tags bookings
+// @Produce text/calendar
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Property ID"
+// @Param token query string true "Export token"
+// @Success 200 {string} string
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/properties/{id}/availability.ics [get]
+func (h *BookingHandler) ExportCalendar(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	propertyID := c.Param("id")
+
+	calendar, err := h.bookingService.ExportCalendar(c.Request.Context(), tenantID, propertyID, c.Query("token"))
+	if err != nil {
+		h.respondBookingError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Data(http.StatusOK, ical.ContentType, calendar)
+}
+
+// GetCalendar returns the stay calendar of a property
+// @Summary Get stay calendar
+// @Description Nightly rate, seasons, minimum stay and external calendars (default one when not configured)
+// @Tags bookings
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param property_id path string true "Property ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/stay-calendars/{property_id} [get]
+func (h *BookingHandler) GetCalendar(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	propertyID := c.Param("property_id")
+
+	calendar, err := h.bookingService.GetCalendar(c.Request.Context(), tenantID, propertyID)
+	if err != nil {
+		h.respondBookingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    calendar,
+	})
+}
+
+// SetCalendar replaces the stay calendar of a property
+// @Summary Set stay calendar
+// @Description Nightly rate, cleaning fee, seasons, minimum stay, max guests and external .ics feeds (Airbnb, Booking.com)
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param property_id path string true "Property ID"
+// @Param body body models.StayCalendar true "Stay calendar"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/stay-calendars/{property_id} [put]
+func (h *BookingHandler) SetCalendar(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	propertyID := c.Param("property_id")
+
+	var req models.StayCalendar
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	calendar, err := h.bookingService.SetCalendar(c.Request.Context(), tenantID, propertyID, req, actorID(c))
+	if err != nil {
+		h.respondBookingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    calendar,
+	})
+}
+
+// SyncCalendar imports the external calendars of a property now
+// @Summary Sync external calendars
+// @Description Import the .ics feeds of the property as blocks (also run hourly)
+// @Tags bookings
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param property_id path string true "Property ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/stay-calendars/{property_id}/sync [post]
+func (h *BookingHandler) SyncCalendar(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	propertyID := c.Param("property_id")
+
+	result, err := h.bookingService.SyncCalendar(c.Request.Context(), tenantID, propertyID)
+	if err != nil {
+		h.respondBookingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
+
+// RotateExportToken issues a new .ics feed URL for a property
+// @Summary Rotate availability export token
+// @Description Issue a new secret for the property's .ics feed; the previous URL stops working
+// @Tags bookings
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param property_id path string true "Property ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/stay-calendars/{property_id}/export-token [post]
+func (h *BookingHandler) RotateExportToken(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	propertyID := c.Param("property_id")
+
+	token, err := h.bookingService.RotateExportToken(c.Request.Context(), tenantID, propertyID, actorID(c))
+	if err != nil {
+		h.respondBookingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"token": token,
+			"path":  "/api/v1/" + tenantID + "/properties/" + propertyID + "/availability.ics?token=" + token,
+		},
+	})
+}
+
+// ListBookings lists the bookings and blocks of a tenant
+// @Summary List bookings
+// @Description Bookings with filters; with from, earliest check-out first, otherwise latest check-in first
+// @Tags bookings
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param property_id query string false "Property ID filter"
+// @Param status query string false "Status filter (requested, confirmed, blocked, declined, cancelled, expired)"
+// @Param source query string false "Source filter (public, admin, ical)"
+// @Param from query string false "Bookings ending after (YYYY-MM-DD)"
+// @Param limit query int false "Limit" default(50)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/{tenant_id}/bookings [get]
+func (h *BookingHandler) ListBookings(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+
+	filters := &repositories.BookingFilters{
+		PropertyID: c.Query("property_id"),
+		Source:     models.BookingSource(c.Query("source")),
+	}
+	if status := c.Query("status"); status != "" {
+		filters.Statuses = []models.BookingStatus{models.BookingStatus(status)}
+	}
+	if value := c.Query("from"); value != "" {
+		from, err := parseStayDate(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "from must be YYYY-MM-DD",
+			})
+			return
+		}
+		filters.CheckOutAfter = &from
+	}
+
+	opts := parsePaginationOptions(c)
+	opts.OrderBy = ""
+
+	bookings, err := h.bookingService.ListBookings(c.Request.Context(), tenantID, filters, opts)
+	if err != nil {
+		h.respondBookingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    bookings,
+		"count":   len(bookings),
+	})
+}
+
+// GetBooking retrieves a booking by ID
+// @Summary Get booking
+// @Tags bookings
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Booking ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/bookings/{id} [get]
+func (h *BookingHandler) GetBooking(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	booking, err := h.bookingService.GetBooking(c.Request.Context(), tenantID, id)
+	if err != nil {
+		h.respondBookingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    booking,
+	})
+}
+
+// CreateBooking books a stay or blocks dates from the dashboard
+// @Summary Create booking
+// @Description Confirmed stay (guest_name required) or blocked dates; 409 when the nights are taken
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param body body CreateBookingRequest true "Booking"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/{tenant_id}/bookings [post]
+func (h *BookingHandler) CreateBooking(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+
+	var req CreateBookingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	checkIn, checkOut, err := parseStay(req.CheckIn, req.CheckOut)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	booking := &models.Booking{
+		TenantID:   tenantID,
+		PropertyID: req.PropertyID,
+		LeadID:     req.LeadID,
+		Status:     req.Status,
+		CheckIn:    checkIn,
+		CheckOut:   checkOut,
+		GuestName:  req.GuestName,
+		GuestEmail: req.GuestEmail,
+		GuestPhone: req.GuestPhone,
+		Guests:     req.Guests,
+		Notes:      req.Notes,
+	}
+	if err := h.bookingService.CreateBooking(c.Request.Context(), booking, actorID(c)); err != nil {
+		h.respondBookingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    booking,
+	})
+}
+
+// ConfirmBooking confirms a booking request
+// @Summary Confirm booking
+// @Tags bookings
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Booking ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/bookings/{id}/confirm [post]
+func (h *BookingHandler) ConfirmBooking(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	booking, err := h.bookingService.ConfirmBooking(c.Request.Context(), tenantID, id, actorID(c))
+	if err != nil {
+		h.respondBookingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    booking,
+	})
+}
+
+// DeclineBooking declines a booking request
+// @Summary Decline booking
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Booking ID"
+// @Param body body CloseBookingRequest false "Reason"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/bookings/{id}/decline [post]
+func (h *BookingHandler) DeclineBooking(c *gin.Context) {
+	h.closeBooking(c, h.bookingService.DeclineBooking)
+}
+
+// CancelBooking cancels a booking or block
+// @Summary Cancel booking
+// @Description Cancel a booking or block of the agency; imported blocks follow their external calendar
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Booking ID"
+// @Param body body CloseBookingRequest false "Reason"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/bookings/{id}/cancel [post]
+func (h *BookingHandler) CancelBooking(c *gin.Context) {
+	h.closeBooking(c, h.bookingService.CancelBooking)
+}
+
+// closeBooking binds the optional reason and runs a decline or cancellation
+func (h *BookingHandler) closeBooking(c *gin.Context, action func(ctx context.Context, tenantID, id, actorID, reason string) (*models.Booking, error)) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	var req CloseBookingRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+	}
+
+	booking, err := action(c.Request.Context(), tenantID, id, actorID(c), req.Reason)
+	if err != nil {
+		h.respondBookingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    booking,
+	})
+}
+
+// parseStay parses the check-in and check-out dates of a stay
+func parseStay(checkIn, checkOut string) (time.Time, time.Time, error) {
+	in, err := parseStayDate(checkIn)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("check_in must be YYYY-MM-DD")
+	}
+	out, err := parseStayDate(checkOut)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("check_out must be YYYY-MM-DD")
+	}
+	return in, out, nil
+}
+
+// parseStayDate parses a YYYY-MM-DD date of a stay calendar
+func parseStayDate(value string) (time.Time, error) {
+	return time.Parse("2006-01-02", value)
+}
+
+// respondBookingError maps booking errors to HTTP status codes
+func (h *BookingHandler) respondBookingError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrBookingConflict):
+		status = http.StatusConflict
+	case errors.Is(err, repositories.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, repositories.ErrInvalidInput):
+		status = http.StatusBadRequest
+	}
+
+	c.JSON(status, gin.H{
+		"success": false,
+		"error":   err.Error(),
+	})
+}
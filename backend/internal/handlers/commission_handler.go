@@ -0,0 +1,423 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/middleware"
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// CommissionHandler handles commission ledger HTTP requests
+type CommissionHandler struct {
+	commissionService *services.CommissionService
+}
+
+// NewCommissionHandler creates a new commission handler
+func NewCommissionHandler(commissionService *services.CommissionService) *CommissionHandler {
+	return &CommissionHandler{
+		commissionService: commissionService,
+	}
+}
+
+// RegisterRoutes registers commission routes (tenant-scoped)
+func (h *CommissionHandler) RegisterRoutes(router *gin.RouterGroup) {
+	commissions := router.Group("/commissions")
+	{
+		commissions.GET("", h.ListEntries)
+		commissions.GET("/split", h.CalculateSplit)
+		commissions.GET("/receivables", h.GetReceivables)
+		commissions.GET("/statement", h.GetStatement)
+		commissions.POST("/deals", h.RecordCommission)
+		commissions.POST("/deals/:deal_id/cancel", h.CancelDeal)
+		commissions.GET("/:id", h.GetEntry)
+		commissions.POST("/:id/pay", h.MarkPaid)
+	}
+
+	router.GET("/brokers/:id/commission-statement", h.GetBrokerStatement)
+}
+
+// RecordDealCommissionRequest represents the request body for recording the commission of a closed deal
+type RecordDealCommissionRequest struct {
+	DealID          string                 `json:"deal_id"`     // default: proposal_id
+	ProposalID      string                 `json:"proposal_id"` // Accepted proposal; defaults the fields below
+	PropertyID      string                 `json:"property_id"`
+	TransactionType models.TransactionType `json:"transaction_type"` // sale (default) or rent
+	Price           float64                `json:"price"`            // Sale price or monthly rent
+	ClosedAt        *time.Time             `json:"closed_at"`        // default: now
+}
+
+// CancelDealCommissionRequest represents the request body for cancelling the commission of a deal
+type CancelDealCommissionRequest struct {
+	Reason string `json:"reason"`
+}
+
+// MarkCommissionPaidRequest represents the request body for settling a commission entry
+type MarkCommissionPaidRequest struct {
+	PaymentReference string `json:"payment_reference"`
+}
+
+// ListEntries lists commission ledger entries
+// @Summary List commission entries
+// @Description Commission ledger entries with filters, most recently closed first
+// @Tags commissions
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param deal_id query string false "Deal ID filter"
+// @Param broker_id query string false "Broker ID filter"
+// @Param property_id query string false "Property ID filter"
+// @Param period query string false "Month filter (YYYY-MM)"
+// @Param beneficiary query string false "Beneficiary filter (broker, agency)"
+// @Param status query string false "Status filter (pending, paid, cancelled)"
+// @Param limit query int false "Limit" default(50)
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/{tenant_id}/commissions [get]
+func (h *CommissionHandler) ListEntries(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+
+	filters := &repositories.CommissionEntryFilters{
+		DealID:      c.Query("deal_id"),
+		BrokerID:    c.Query("broker_id"),
+		PropertyID:  c.Query("property_id"),
+		Period:      c.Query("period"),
+		Beneficiary: models.CommissionBeneficiary(c.Query("beneficiary")),
+	}
+	if status := c.Query("status"); status != "" {
+		filters.Statuses = []models.CommissionEntryStatus{models.CommissionEntryStatus(status)}
+	}
+
+	opts := parsePaginationOptions(c)
+	opts.OrderBy = "" // Most recently closed first
+
+	entries, err := h.commissionService.ListEntries(c.Request.Context(), tenantID, filters, opts)
+	if err != nil {
+		h.respondCommissionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    entries,
+		"count":   len(entries),
+	})
+}
+
+// GetEntry retrieves a commission entry by ID
+// @Summary Get commission entry
+// @Tags commissions
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Entry ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/commissions/{id} [get]
+func (h *CommissionHandler) GetEntry(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	entry, err := h.commissionService.GetEntry(c.Request.Context(), tenantID, id)
+	if err != nil {
+		h.respondCommissionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    entry,
+	})
+}
+
+// CalculateSplit previews the commission split of a deal
+// @Summary Preview commission split
+// @Description Commission entries a deal on the property would produce under the tenant rules (nothing is recorded)
+// @Tags commissions
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param property_id query string true "Property ID"
+// @Param price query number true "Sale price or monthly rent"
+// @Param transaction_type query string false "sale (default) or rent"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/commissions/split [get]
+func (h *CommissionHandler) CalculateSplit(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+
+	price, err := strconv.ParseFloat(c.Query("price"), 64)
+	if err != nil || c.Query("property_id") == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "property_id and a numeric price are required",
+		})
+		return
+	}
+
+	entries, err := h.commissionService.CalculateSplit(c.Request.Context(), tenantID, c.Query("property_id"), models.TransactionType(c.Query("transaction_type")), price)
+	if err != nil {
+		h.respondCommissionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    entries,
+	})
+}
+
+// RecordCommission records the commission of a closed deal
+// @Summary Record deal commission
+// @Description Split the commission of a closed deal between its brokers and the agency and record the pending entries
+// @Tags commissions
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param body body RecordDealCommissionRequest true "Deal"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/commissions/deals [post]
+func (h *CommissionHandler) RecordCommission(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+
+	var req RecordDealCommissionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	record := services.RecordCommissionRequest{
+		TenantID:        tenantID,
+		DealID:          req.DealID,
+		ProposalID:      req.ProposalID,
+		PropertyID:      req.PropertyID,
+		TransactionType: req.TransactionType,
+		Price:           req.Price,
+	}
+	if req.ClosedAt != nil {
+		record.ClosedAt = *req.ClosedAt
+	}
+
+	entries, err := h.commissionService.RecordCommission(c.Request.Context(), record, actorID(c))
+	if err != nil {
+		h.respondCommissionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    entries,
+	})
+}
+
+// CancelDeal cancels the pending commission of a deal
+// @Summary Cancel deal commission
+// @Description Cancel the pending entries of a deal that fell through; paid entries are kept
+// @Tags commissions
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param deal_id path string true "Deal ID"
+// @Param body body CancelDealCommissionRequest false "Reason"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/commissions/deals/{deal_id}/cancel [post]
+func (h *CommissionHandler) CancelDeal(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	dealID := c.Param("deal_id")
+
+	var req CancelDealCommissionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+	}
+
+	cancelled, err := h.commissionService.CancelDeal(c.Request.Context(), tenantID, dealID, req.Reason, actorID(c))
+	if err != nil {
+		h.respondCommissionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"cancelled": cancelled,
+	})
+}
+
+// MarkPaid settles a commission entry
+// @Summary Mark commission paid
+// @Tags commissions
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Entry ID"
+// @Param body body MarkCommissionPaidRequest false "Payment"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/commissions/{id}/pay [post]
+func (h *CommissionHandler) MarkPaid(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	var req MarkCommissionPaidRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+	}
+
+	entry, err := h.commissionService.MarkPaid(c.Request.Context(), tenantID, id, req.PaymentReference, actorID(c))
+	if err != nil {
+		h.respondCommissionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    entry,
+	})
+}
+
+// GetReceivables returns the pending commission of each broker
+// @Summary Broker commission receivables
+// @Tags commissions
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/{tenant_id}/commissions/receivables [get]
+func (h *CommissionHandler) GetReceivables(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+
+	receivables, err := h.commissionService.Receivables(c.Request.Context(), tenantID)
+	if err != nil {
+		h.respondCommissionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    receivables,
+	})
+}
+
+// GetStatement returns the commission statement of a month
+// @Summary Monthly commission statement
+// @Description Commission of a month for the whole agency or one broker; format=csv exports it for spreadsheets
+// @Tags commissions
+// @Produce json
+// @Produce text/csv
+// @Param tenant_id path string true "Tenant ID"
+// @Param period query string false "Month (YYYY-MM, default current month)"
+// @Param broker_id query string false "Broker ID"
+// @Param format query string false "json (default) or csv"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/{tenant_id}/commissions/statement [get]
+func (h *CommissionHandler) GetStatement(c *gin.Context) {
+	h.respondStatement(c, c.Query("broker_id"))
+}
+
+// GetBrokerStatement returns the monthly commission statement of a broker
+// @Summary Broker commission statement
+// @Description Commission of a month for a broker: their own, or any with finance.view
+// @Tags commissions
+// @Produce json
+// @Produce text/csv
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Broker ID"
+// @Param period query string false "Month (YYYY-MM, default current month)"
+// @Param format query string false "json (default) or csv"
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/{tenant_id}/brokers/{id}/commission-statement [get]
+func (h *CommissionHandler) GetBrokerStatement(c *gin.Context) {
+	brokerID := c.Param("id")
+	if !canViewCommission(c, brokerID) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   "only the broker or members with finance.view can see this statement",
+		})
+		return
+	}
+
+	h.respondStatement(c, brokerID)
+}
+
+// respondStatement writes the statement of the requested month as JSON or CSV
+func (h *CommissionHandler) respondStatement(c *gin.Context, brokerID string) {
+	tenantID := c.Param("tenant_id")
+	period := c.DefaultQuery("period", models.CommissionPeriod(time.Now()))
+
+	statement, err := h.commissionService.Statement(c.Request.Context(), tenantID, period, brokerID)
+	if err != nil {
+		h.respondCommissionError(c, err)
+		return
+	}
+
+	if c.Query("format") != "csv" {
+		c.JSON(http.StatusOK, gin.H{
+			"success": true,
+			"data":    statement,
+		})
+		return
+	}
+
+	data, err := h.commissionService.StatementCSV(statement)
+	if err != nil {
+		h.respondCommissionError(c, err)
+		return
+	}
+
+	filename := fmt.Sprintf("comissoes-%s.csv", period)
+	if brokerID != "" {
+		filename = fmt.Sprintf("comissoes-%s-%s.csv", period, brokerID)
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", data)
+}
+
+// canViewCommission checks if the member may see the commission of a broker:
+// their own, or any with finance.view
+func canViewCommission(c *gin.Context, brokerID string) bool {
+	member := middleware.GetMember(c)
+	if member == nil {
+		return true // No member resolved: route permissions are not enforced
+	}
+	return (member.Type == "broker" && member.ID == brokerID) || member.HasPermission(models.PermissionFinanceView)
+}
+
+// respondCommissionError maps commission errors to HTTP status codes
+func (h *CommissionHandler) respondCommissionError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, repositories.ErrInvalidInput):
+		status = http.StatusBadRequest
+	}
+
+	c.JSON(status, gin.H{
+		"success": false,
+		"error":   err.Error(),
+	})
+}
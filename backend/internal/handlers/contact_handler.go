@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// ContactHandler handles contact (lead identity) HTTP requests
+type ContactHandler struct {
+	leadService *services.LeadService
+}
+
+// NewContactHandler creates a new contact handler
+func NewContactHandler(leadService *services.LeadService) *ContactHandler {
+	return &ContactHandler{
+		leadService: leadService,
+	}
+}
+
+// RegisterRoutes registers contact routes (tenant-scoped)
+func (h *ContactHandler) RegisterRoutes(router *gin.RouterGroup) {
+	contacts := router.Group("/contacts")
+	{
+		contacts.GET("", h.ListContacts)
+		contacts.GET("/:id", h.GetContact)
+		contacts.POST("/:id/merge", h.MergeContacts)
+		contacts.POST("/:id/unmerge", h.UnmergeContacts)
+	}
+}
+
+// MergeContactsRequest names the contact merged into (or split from) the contact in the path
+type MergeContactsRequest struct {
+	SourceContactID string `json:"source_contact_id" binding:"required"`
+}
+
+// ListContacts lists the contacts of a tenant
+// @Summary List contacts
+// @Description List the people behind the leads of a tenant, merged contacts included
+// @Tags contacts
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param limit query int false "Limit" default(50)
+// @Param order_by query string false "Order by field (created_at, updated_at, last_inquiry_at)" default(created_at)
+// @Param order query string false "Sort direction (asc, desc)" default(desc)
+// @Param cursor query string false "next_cursor of the previous page"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/{tenant_id}/contacts [get]
+func (h *ContactHandler) ListContacts(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+
+	opts, err := parseListOptions(c, contactSortFields)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	contacts, err := h.leadService.ListContacts(c.Request.Context(), tenantID, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"data":        contacts,
+		"count":       len(contacts),
+		"next_cursor": repositories.NextCursor(contacts, opts),
+	})
+}
+
+// GetContact retrieves a contact with its inquiry history
+// @Summary Get contact
+// @Description Get a contact with its inquiries (leads) across properties, each with its LGPD consent record
+// @Tags contacts
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Contact ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/{tenant_id}/contacts/{id} [get]
+func (h *ContactHandler) GetContact(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	contact, err := h.leadService.GetContact(c.Request.Context(), tenantID, id)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    contact,
+	})
+}
+
+// MergeContacts merges a contact into the contact in the path
+// @Summary Merge contacts
+// @Description Move the leads of source_contact_id to this contact. Consent records stay on each lead; the merge can be undone.
+// @Tags contacts
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Contact ID (kept)"
+// @Param body body MergeContactsRequest true "Contact to merge"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/{tenant_id}/contacts/{id}/merge [post]
+func (h *ContactHandler) MergeContacts(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	var req MergeContactsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	contact, err := h.leadService.MergeContacts(c.Request.Context(), tenantID, id, req.SourceContactID, c.GetString("user_id"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    contact,
+	})
+}
+
+// UnmergeContacts undoes the merge of a contact into the contact in the path
+// @Summary Unmerge contacts
+// @Description Move the leads that came from source_contact_id back to it
+// @Tags contacts
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Contact ID (merge target)"
+// @Param body body MergeContactsRequest true "Contact to split off"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/{tenant_id}/contacts/{id}/unmerge [post]
+func (h *ContactHandler) UnmergeContacts(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	var req MergeContactsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	contact, err := h.leadService.UnmergeContacts(c.Request.Context(), tenantID, id, req.SourceContactID, c.GetString("user_id"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    contact,
+	})
+}
+
+// respondError maps contact errors to HTTP status codes
+func (h *ContactHandler) respondError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, repositories.ErrInvalidInput):
+		status = http.StatusBadRequest
+	}
+
+	c.JSON(status, gin.H{
+		"success": false,
+		"error":   err.Error(),
+	})
+}
@@ -0,0 +1,268 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// DealHandler handles closed deal HTTP requests
+type DealHandler struct {
+	dealService *services.DealService
+}
+
+// NewDealHandler creates a new deal handler
+func NewDealHandler(dealService *services.DealService) *DealHandler {
+	return &DealHandler{
+		dealService: dealService,
+	}
+}
+
+// RegisterRoutes registers deal routes (tenant-scoped)
+func (h *DealHandler) RegisterRoutes(router *gin.RouterGroup) {
+	deals := router.Group("/deals")
+	{
+		deals.GET("", h.ListDeals)
+		deals.POST("", h.CloseDeal)
+		deals.GET("/:id", h.GetDeal)
+		deals.POST("/:id/cancel", h.CancelDeal)
+		deals.POST("/:id/review", h.ReviewDeal)
+	}
+}
+
+// CloseDealRequest represents the request body for registering a closed deal
+type CloseDealRequest struct {
+	ProposalID      string                 `json:"proposal_id"`      // Accepted proposal; defaults the fields below
+	PropertyID      string                 `json:"property_id"`      // required without proposal_id
+	LeadID          string                 `json:"lead_id"`          // Buyer or tenant
+	TransactionType models.TransactionType `json:"transaction_type"` // sale (default) or rent
+	Price           float64                `json:"price"`            // Final sale price or monthly rent
+	ClosedAt        *time.Time             `json:"closed_at"`        // default: now
+	Brokers         []models.DealBroker    `json:"brokers"`          // default: property roles plus the lead's broker
+	Notes           string                 `json:"notes"`
+}
+
+// CancelDealRequest represents the request body for cancelling a deal
+type CancelDealRequest struct {
+	Reason string `json:"reason"`
+}
+
+// ReviewDealRequest represents the request body for reviewing the brokers of a deal
+type ReviewDealRequest struct {
+	Rating  int    `json:"rating" binding:"required"` // 1 to 5
+	Comment string `json:"comment"`
+}
+
+// ListDeals lists deals
+// @Summary List deals
+// @Description Closed and cancelled deals with filters, most recently closed first
+// @Tags deals
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param property_id query string false "Property ID filter"
+// @Param lead_id query string false "Lead ID filter"
+// @Param broker_id query string false "Broker ID filter (any role)"
+// @Param status query string false "Status filter (closed, cancelled)"
+// @Param limit query int false "Limit" default(50)
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/{tenant_id}/deals [get]
+func (h *DealHandler) ListDeals(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+
+	filters := &repositories.DealFilters{
+		PropertyID: c.Query("property_id"),
+		LeadID:     c.Query("lead_id"),
+		BrokerID:   c.Query("broker_id"),
+	}
+	if status := c.Query("status"); status != "" {
+		filters.Statuses = []models.DealStatus{models.DealStatus(status)}
+	}
+
+	deals, err := h.dealService.ListDeals(c.Request.Context(), tenantID, filters, parsePaginationOptions(c))
+	if err != nil {
+		h.respondDealError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    deals,
+		"count":   len(deals),
+	})
+}
+
+// GetDeal retrieves a deal by ID
+// @Summary Get deal
+// @Tags deals
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Deal ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/deals/{id} [get]
+func (h *DealHandler) GetDeal(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	deal, err := h.dealService.GetDeal(c.Request.Context(), tenantID, id)
+	if err != nil {
+		h.respondDealError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    deal,
+	})
+}
+
+// CloseDeal registers a closed deal
+// @Summary Close deal
+// @Description Register the sale or rental of a property; the property becomes unavailable (sold/rented), the lead is converted, the commission is recorded and the broker statistics are updated
+// @Tags deals
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param body body CloseDealRequest true "Deal"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/deals [post]
+func (h *DealHandler) CloseDeal(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+
+	var req CloseDealRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	deal := &models.Deal{
+		TenantID:        tenantID,
+		ProposalID:      req.ProposalID,
+		PropertyID:      req.PropertyID,
+		LeadID:          req.LeadID,
+		TransactionType: req.TransactionType,
+		Price:           req.Price,
+		Brokers:         req.Brokers,
+		Notes:           req.Notes,
+	}
+	if req.ClosedAt != nil {
+		deal.ClosedAt = *req.ClosedAt
+	}
+
+	if err := h.dealService.CloseDeal(c.Request.Context(), deal, actorID(c)); err != nil {
+		h.respondDealError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    deal,
+	})
+}
+
+// CancelDeal cancels a closed deal
+// @Summary Cancel deal
+// @Description Undo a closed deal; its pending commission is cancelled and the broker statistics are updated
+// @Tags deals
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Deal ID"
+// @Param body body CancelDealRequest false "Reason"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/deals/{id}/cancel [post]
+func (h *DealHandler) CancelDeal(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	var req CancelDealRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+	}
+
+	deal, err := h.dealService.CancelDeal(c.Request.Context(), tenantID, id, req.Reason, actorID(c))
+	if err != nil {
+		h.respondDealError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    deal,
+	})
+}
+
+// ReviewDeal records the buyer's or tenant's rating of the brokers of a deal
+// @Summary Review deal
+// @Tags deals
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Deal ID"
+// @Param body body ReviewDealRequest true "Review"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/deals/{id}/review [post]
+func (h *DealHandler) ReviewDeal(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	var req ReviewDealRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	deal, err := h.dealService.ReviewDeal(c.Request.Context(), tenantID, id, models.DealReview{
+		Rating:  req.Rating,
+		Comment: req.Comment,
+	}, actorID(c))
+	if err != nil {
+		h.respondDealError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    deal,
+	})
+}
+
+// respondDealError maps deal errors to HTTP status codes
+func (h *DealHandler) respondDealError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, repositories.ErrInvalidInput):
+		status = http.StatusBadRequest
+	}
+
+	c.JSON(status, gin.H{
+		"success": false,
+		"error":   err.Error(),
+	})
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/notify"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/services"
+)
+
+// DeliveryWebhookHandler receives the delivery status webhooks of the
+// messaging providers (public, authenticated by provider signatures)
+type DeliveryWebhookHandler struct {
+	scheduler *services.MonthlyConfirmationScheduler
+	whatsapp  *notify.WhatsAppNotifier // nil when WhatsApp is not configured
+	sms       *notify.SMSNotifier      // nil when SMS is not configured
+}
+
+// NewDeliveryWebhookHandler creates a new delivery webhook handler
+func NewDeliveryWebhookHandler(scheduler *services.MonthlyConfirmationScheduler, whatsapp *notify.WhatsAppNotifier, sms *notify.SMSNotifier) *DeliveryWebhookHandler {
+	return &DeliveryWebhookHandler{
+		scheduler: scheduler,
+		whatsapp:  whatsapp,
+		sms:       sms,
+	}
+}
+
+// VerifyWhatsApp answers the WhatsApp webhook subscription handshake
+// @Summary Verify WhatsApp webhook
+// @Description Echoes hub.challenge when hub.verify_token matches WHATSAPP_VERIFY_TOKEN
+// @Tags webhooks
+// @Produce plain
+// @Param hub.mode query string true "Mode (subscribe)"
+// @Param hub.verify_token query string true "Verify token"
+// @Param hub.challenge query string true "Challenge"
+// @Success 200 {string} string
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/webhooks/whatsapp [get]
+func (h *DeliveryWebhookHandler) VerifyWhatsApp(c *gin.Context) {
+	if h.whatsapp == nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": "whatsapp is not configured"})
+		return
+	}
+
+	challenge, ok := h.whatsapp.VerifySubscription(c.Query("hub.mode"), c.Query("hub.verify_token"), c.Query("hub.challenge"))
+	if !ok {
+		c.JSON(http.StatusForbidden, gin.H{"success": false, "error": "invalid verify token"})
+		return
+	}
+
+	c.String(http.StatusOK, challenge)
+}
+
+// WhatsAppStatus receives WhatsApp message status notifications
+// @Summary WhatsApp delivery webhook
+// @Description Updates the delivery status of owner confirmations sent through WhatsApp (signed with X-Hub-Signature-256)
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/v1/webhooks/whatsapp [post]
+func (h *DeliveryWebhookHandler) WhatsAppStatus(c *gin.Context) {
+	if h.whatsapp == nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": "whatsapp is not configured"})
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, 1<<20))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "failed to read body"})
+		return
+	}
+
+	if !h.whatsapp.VerifySignature(body, c.GetHeader("X-Hub-Signature-256")) {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": "invalid signature"})
+		return
+	}
+
+	updates, err := notify.ParseWhatsAppWebhook(body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
+		return
+	}
+
+	applied := 0
+	for _, update := range updates {
+		if h.apply(c, update) {
+			applied++
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "applied": applied})
+}
+
+// SMSStatus receives SMS status callbacks
+// @Summary SMS delivery webhook
+// @Description Updates the delivery status of owner confirmations sent through SMS (Twilio status callback, signed with X-Twilio-Signature)
+// @Tags webhooks
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/v1/webhooks/sms [post]
+func (h *DeliveryWebhookHandler) SMSStatus(c *gin.Context) {
+	if h.sms == nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": "sms is not configured"})
+		return
+	}
+
+	if err := c.Request.ParseForm(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "invalid form"})
+		return
+	}
+
+	if !h.sms.VerifySignature(c.Request.PostForm, c.GetHeader("X-Twilio-Signature")) {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": "invalid signature"})
+		return
+	}
+
+	applied := 0
+	if update, ok := notify.ParseSMSStatusCallback(c.Request.PostForm); ok && h.apply(c, update) {
+		applied++
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "applied": applied})
+}
+
+// apply records a status update. Unknown messages are acknowledged anyway so
+// the provider does not retry them.
+func (h *DeliveryWebhookHandler) apply(c *gin.Context, update notify.StatusUpdate) bool {
+	err := h.scheduler.ApplyDeliveryStatus(c.Request.Context(), update)
+	if err == nil {
+		return true
+	}
+	if !errors.Is(err, repositories.ErrNotFound) {
+		log.Printf("❌ Failed to apply %s delivery status for message %s: %v", update.Channel, update.ProviderMessageID, err)
+	}
+	return false
+}
@@ -0,0 +1,420 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// DevelopmentHandler handles development unit inventory HTTP requests
+type DevelopmentHandler struct {
+	developmentService *services.DevelopmentService
+}
+
+// NewDevelopmentHandler creates a new development handler
+func NewDevelopmentHandler(developmentService *services.DevelopmentService) *DevelopmentHandler {
+	return &DevelopmentHandler{
+		developmentService: developmentService,
+	}
+}
+
+// RegisterRoutes registers development unit routes (tenant-scoped)
+func (h *DevelopmentHandler) RegisterRoutes(router *gin.RouterGroup) {
+	developments := router.Group("/developments")
+	{
+		developments.GET("/:property_id/units", h.ListUnits)
+		developments.POST("/:property_id/units", h.CreateUnits)
+		developments.POST("/:property_id/units/reprice", h.RepriceUnits)
+		developments.GET("/:property_id/mirror", h.GetSalesMirror)
+	}
+
+	units := router.Group("/development-units")
+	{
+		units.GET("/:id", h.GetUnit)
+		units.PUT("/:id", h.UpdateUnit)
+		units.DELETE("/:id", h.DeleteUnit)
+		units.POST("/:id/reserve", h.ReserveUnit)
+		units.POST("/:id/release", h.ReleaseUnit)
+		units.POST("/:id/sell", h.SellUnit)
+	}
+}
+
+// CreateUnitsRequest represents the request body for adding units to a development
+type CreateUnitsRequest struct {
+	Units []*models.DevelopmentUnit `json:"units" binding:"required"`
+}
+
+// ReleaseUnitRequest represents the request body for releasing a unit
+type ReleaseUnitRequest struct {
+	Reason string `json:"reason"` // Required to undo a sale (distrato)
+}
+
+// ListUnits lists the units of a development
+// @Summary List development units
+// @Tags developments
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param property_id path string true "Development (property) ID"
+// @Param tower query string false "Filter by tower"
+// @Param typology query string false "Filter by typology"
+// @Param status query string false "Filter by status (available, reserved, sold)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/{tenant_id}/developments/{property_id}/units [get]
+func (h *DevelopmentHandler) ListUnits(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	propertyID := c.Param("property_id")
+
+	filters := &repositories.DevelopmentUnitFilters{
+		Tower:    c.Query("tower"),
+		Typology: c.Query("typology"),
+	}
+	if status := c.Query("status"); status != "" {
+		filters.Statuses = []models.DevelopmentUnitStatus{models.DevelopmentUnitStatus(status)}
+	}
+
+	opts := parsePaginationOptions(c)
+	opts.OrderBy = ""
+
+	units, err := h.developmentService.ListUnits(c.Request.Context(), tenantID, propertyID, filters, opts)
+	if err != nil {
+		h.respondDevelopmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    units,
+		"count":   len(units),
+	})
+}
+
+// CreateUnits adds units to the inventory of a development
+// @Summary Create development units
+// @Description Adds up to 500 units (tower, floor, number, typology, price table). Units the development already has are skipped.
+// @Tags developments
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param property_id path string true "Development (property) ID"
+// @Param body body CreateUnitsRequest true "Units"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/developments/{property_id}/units [post]
+func (h *DevelopmentHandler) CreateUnits(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	propertyID := c.Param("property_id")
+
+	var req CreateUnitsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	response, err := h.developmentService.CreateUnits(c.Request.Context(), tenantID, propertyID, req.Units, actorID(c))
+	if err != nil {
+		h.respondDevelopmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    response,
+	})
+}
+
+// RepriceUnits applies a percentage to the price table of a development
+// @Summary Reprice development units
+// @Description Applies a percentage (ex: INCC) to the price and payment plan of the units still for sale
+// @Tags developments
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param property_id path string true "Development (property) ID"
+// @Param body body services.RepriceUnitsRequest true "Percentage, tower and typology"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/developments/{property_id}/units/reprice [post]
+func (h *DevelopmentHandler) RepriceUnits(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	propertyID := c.Param("property_id")
+
+	var req services.RepriceUnitsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	response, err := h.developmentService.RepriceUnits(c.Request.Context(), tenantID, propertyID, req, actorID(c))
+	if err != nil {
+		h.respondDevelopmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    response,
+	})
+}
+
+// GetSalesMirror returns the sales mirror of a development
+// @Summary Sales mirror (espelho de vendas)
+// @Description Units by tower and floor with their status, counters and VGV
+// @Tags developments
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param property_id path string true "Development (property) ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/developments/{property_id}/mirror [get]
+func (h *DevelopmentHandler) GetSalesMirror(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	propertyID := c.Param("property_id")
+
+	mirror, err := h.developmentService.SalesMirror(c.Request.Context(), tenantID, propertyID)
+	if err != nil {
+		h.respondDevelopmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    mirror,
+	})
+}
+
+// GetUnit retrieves a development unit by ID
+// @Summary Get development unit
+// @Tags developments
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Unit ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/development-units/{id} [get]
+func (h *DevelopmentHandler) GetUnit(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	unit, err := h.developmentService.GetUnit(c.Request.Context(), tenantID, id)
+	if err != nil {
+		h.respondDevelopmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    unit,
+	})
+}
+
+// UpdateUnit updates the typology and price table of a development unit
+// @Summary Update development unit
+// @Tags developments
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Unit ID"
+// @Param body body services.UpdateUnitRequest true "Fields to update"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/development-units/{id} [put]
+func (h *DevelopmentHandler) UpdateUnit(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	var req services.UpdateUnitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	unit, err := h.developmentService.UpdateUnit(c.Request.Context(), tenantID, id, req, actorID(c))
+	if err != nil {
+		h.respondDevelopmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    unit,
+	})
+}
+
+// DeleteUnit removes a development unit that is neither reserved nor sold
+// @Summary Delete development unit
+// @Tags developments
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Unit ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/{tenant_id}/development-units/{id} [delete]
+func (h *DevelopmentHandler) DeleteUnit(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	if err := h.developmentService.DeleteUnit(c.Request.Context(), tenantID, id, actorID(c)); err != nil {
+		h.respondDevelopmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"message": "unit deleted successfully"},
+	})
+}
+
+// ReserveUnit holds a development unit for a buyer
+// @Summary Reserve development unit
+// @Description Holds an available unit for a lead or buyer for 48 hours by default (max 168)
+// @Tags developments
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Unit ID"
+// @Param body body services.ReserveUnitRequest true "Buyer and reservation term"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/{tenant_id}/development-units/{id}/reserve [post]
+func (h *DevelopmentHandler) ReserveUnit(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	var req services.ReserveUnitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	unit, err := h.developmentService.ReserveUnit(c.Request.Context(), tenantID, id, req, actorID(c))
+	if err != nil {
+		h.respondDevelopmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    unit,
+	})
+}
+
+// ReleaseUnit makes a reserved (or sold, as a distrato) unit available again
+// @Summary Release development unit
+// @Tags developments
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Unit ID"
+// @Param body body ReleaseUnitRequest false "Reason (required for sold units)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/{tenant_id}/development-units/{id}/release [post]
+func (h *DevelopmentHandler) ReleaseUnit(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	var req ReleaseUnitRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+	}
+
+	unit, err := h.developmentService.ReleaseUnit(c.Request.Context(), tenantID, id, req.Reason, actorID(c))
+	if err != nil {
+		h.respondDevelopmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    unit,
+	})
+}
+
+// SellUnit records the sale of a development unit
+// @Summary Sell development unit
+// @Description Sells an available unit, or a reserved one whose reservation is still running. The sale price defaults to the table price.
+// @Tags developments
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Unit ID"
+// @Param body body services.SellUnitRequest true "Buyer and sale price"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/{tenant_id}/development-units/{id}/sell [post]
+func (h *DevelopmentHandler) SellUnit(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	var req services.SellUnitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	unit, err := h.developmentService.SellUnit(c.Request.Context(), tenantID, id, req, actorID(c))
+	if err != nil {
+		h.respondDevelopmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    unit,
+	})
+}
+
+// respondDevelopmentError maps development service errors to HTTP responses
+func (h *DevelopmentHandler) respondDevelopmentError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrUnitUnavailable):
+		status = http.StatusConflict
+	case errors.Is(err, repositories.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, repositories.ErrInvalidInput):
+		status = http.StatusBadRequest
+	}
+
+	c.JSON(status, gin.H{
+		"success": false,
+		"error":   err.Error(),
+	})
+}
@@ -1,8 +1,13 @@
 package handlers
 
 import (
+	"fmt"
+	"sort"
 	"strconv"
+	"strings"
 
+	"cloud.google.com/go/firestore"
+	"github.com/altatech/ecosistema-imob/backend/internal/middleware"
 	"github.com/altatech/ecosistema-imob/backend/internal/models"
 	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
 	"github.com/gin-gonic/gin"
@@ -35,6 +40,110 @@ func parsePaginationOptions(c *gin.Context) repositories.PaginationOptions {
 	return opts
 }
 
+// Sortable fields of cursor-paginated listings (order_by value -> Firestore field).
+// Sorting by area lists only properties with a total area: Firestore leaves
+// out the documents without the field.
+var (
+	propertySortFields = map[string]string{
+		"created_at":   "created_at",
+		"updated_at":   "updated_at",
+		"price_amount": "price_amount",
+		"area":         "total_area",
+		"total_area":   "total_area",
+	}
+	leadSortFields = map[string]string{
+		"created_at": "created_at",
+		"updated_at": "updated_at",
+	}
+	contactSortFields = map[string]string{
+		"created_at":      "created_at",
+		"updated_at":      "updated_at",
+		"last_inquiry_at": "last_inquiry_at",
+	}
+	listingSortFields = map[string]string{
+		"created_at": "created_at",
+		"updated_at": "updated_at",
+	}
+	ownerSortFields = map[string]string{
+		"created_at": "created_at",
+		"updated_at": "updated_at",
+	}
+)
+
+// parseListOptions extracts pagination parameters for cursor-paginated listings:
+// limit, order_by (one of sortFields), order ("asc" or "desc", default "desc")
+// and cursor (the next_cursor of the previous page, which carries its ordering).
+// Errors wrap repositories.ErrInvalidInput.
+func parseListOptions(c *gin.Context, sortFields map[string]string) (repositories.PaginationOptions, error) {
+	opts := parsePaginationOptions(c)
+	opts.StartAfter = nil // raw start_after values are not comparable; use cursor
+
+	if orderBy := c.Query("order_by"); orderBy != "" {
+		field, ok := sortFields[orderBy]
+		if !ok {
+			return opts, fmt.Errorf("%w: order_by must be one of %s", repositories.ErrInvalidInput, strings.Join(sortFieldNames(sortFields), ", "))
+		}
+		opts.OrderBy = field
+	}
+
+	switch order := strings.ToLower(c.Query("order")); order {
+	case "", "desc":
+		opts.Direction = firestore.Desc
+	case "asc":
+		opts.Direction = firestore.Asc
+	default:
+		return opts, fmt.Errorf("%w: order must be 'asc' or 'desc'", repositories.ErrInvalidInput)
+	}
+
+	if err := opts.ApplyCursor(c.Query("cursor")); err != nil {
+		return opts, err
+	}
+	if !isSortField(sortFields, opts.OrderBy) {
+		return opts, fmt.Errorf("%w: cursor is not valid for this listing", repositories.ErrInvalidInput)
+	}
+
+	return opts, nil
+}
+
+// checkFilteredSort rejects the orderings of filtered listings that have no
+// composite index in firestore.indexes.json: only created_at (either
+// direction) is indexed with each filter. Errors wrap repositories.ErrInvalidInput.
+func checkFilteredSort(opts repositories.PaginationOptions, filtered bool) error {
+	if filtered && opts.OrderBy != "created_at" {
+		return fmt.Errorf("%w: filtered lists can only be sorted by created_at", repositories.ErrInvalidInput)
+	}
+	return nil
+}
+
+// sortFieldNames returns the accepted order_by values, sorted
+func sortFieldNames(sortFields map[string]string) []string {
+	names := make([]string, 0, len(sortFields))
+	for name := range sortFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// isSortField checks if field is one of the Firestore fields of sortFields
+func isSortField(sortFields map[string]string, field string) bool {
+	for _, f := range sortFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// actorID returns the ID of the tenant member making the request (broker or
+// user document), or the authenticated UID when no member was resolved
+func actorID(c *gin.Context) string {
+	if member := middleware.GetMember(c); member != nil {
+		return member.ID
+	}
+	return c.GetString(string(middleware.UserIDKey))
+}
+
 // UpdateStatusRequest is a common request structure for status updates
 type UpdateStatusRequest struct {
 	Status string `json:"status" binding:"required"`
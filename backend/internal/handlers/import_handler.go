@@ -12,6 +12,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 
+	"github.com/altatech/ecosistema-imob/backend/internal/adapters"
 	"github.com/altatech/ecosistema-imob/backend/internal/adapters/union"
 	"github.com/altatech/ecosistema-imob/backend/internal/middleware"
 	"github.com/altatech/ecosistema-imob/backend/internal/models"
@@ -32,7 +33,7 @@ func NewImportHandler(importService *services.ImportService) *ImportHandler {
 
 // ImportRequest represents the import request body
 type ImportRequest struct {
-	Source    string `json:"source" binding:"required"`    // "union"
+	Source    string `json:"source" binding:"required"`    // "union" (default), "vrsync"
 	CreatedBy string `json:"created_by" binding:"required"` // broker_id or "system"
 }
 
@@ -45,7 +46,8 @@ type ImportResponse struct {
 }
 
 // ImportFromFiles handles POST /api/v1/tenants/{tenantId}/import
-// Accepts multipart form with XML and optional XLS files
+// Accepts multipart form with XML and optional XLS files. The "source" field
+// selects the export format (see ImportService.ImportSources).
 func (h *ImportHandler) ImportFromFiles(c *gin.Context) {
 	// Get tenant ID from middleware
 	log.Printf("🔍 Checking for TenantID in context with key: %s", string(middleware.TenantIDKey))
@@ -88,7 +90,24 @@ func (h *ImportHandler) ImportFromFiles(c *gin.Context) {
 	// Get source and created_by from form
 	source := c.PostForm("source")
 	if source == "" {
-		source = "union" // default
+		source = union.Source // default
+	}
+
+	importer, err := h.importService.Importer(source)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":             "Unsupported import source",
+			"details":           err.Error(),
+			"supported_sources": h.importService.ImportSources(),
+		})
+		return
+	}
+	source = importer.Source()
+
+	// Owner spreadsheets (and XLS-only imports) are a Union CRM feature
+	if hasXLS && source != union.Source {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("XLS files are not supported for source %q", source)})
+		return
 	}
 
 	createdBy := c.PostForm("created_by")
@@ -167,11 +186,9 @@ func (h *ImportHandler) processImport(ctx context.Context, batch *models.ImportB
 		}
 	}()
 
-	// Parse XLS if provided
-	var xlsRecords []union.XLSRecord
-	if xlsPath != "" {
-		var err error
-		xlsRecords, err = union.ParseXLS(xlsPath)
+	// If only XLS provided (no XML), process XLS-only mode
+	if xmlPath == "" && xlsPath != "" {
+		xlsRecords, err := union.ParseXLS(xlsPath)
 		if err != nil {
 			log.Printf("❌ Failed to parse XLS: %v", err)
 			_ = h.importService.LogError(ctx, batch, "xls_parse", err.Error(), nil)
@@ -180,10 +197,7 @@ func (h *ImportHandler) processImport(ctx context.Context, batch *models.ImportB
 			return
 		}
 		log.Printf("✅ Parsed XLS with %d records", len(xlsRecords))
-	}
 
-	// If only XLS provided (no XML), process XLS-only mode
-	if xmlPath == "" && xlsPath != "" {
 		log.Printf("🎯 Detected XLS-only import mode (no XML file)")
 		log.Printf("🎯 XLS records count: %d", len(xlsRecords))
 		if len(xlsRecords) > 0 {
@@ -195,51 +209,37 @@ func (h *ImportHandler) processImport(ctx context.Context, batch *models.ImportB
 		return
 	}
 
-	// Parse XML
-	xmlFile, err := os.Open(xmlPath)
+	// Parse the export with the importer of the batch source
+	importer, err := h.importService.Importer(batch.Source)
 	if err != nil {
-		log.Printf("❌ Failed to open XML file: %v", err)
-		_ = h.importService.LogError(ctx, batch, "xml_open", err.Error(), nil)
+		log.Printf("❌ No importer for source %s: %v", batch.Source, err)
+		_ = h.importService.LogError(ctx, batch, "unknown_source", err.Error(), nil)
 		batch.Status = "failed"
 		_ = h.importService.CompleteBatch(ctx, batch)
 		return
 	}
-	defer xmlFile.Close()
 
-	xmlData, err := union.ParseXML(xmlFile)
+	payloads, err := importer.Parse(adapters.Input{
+		TenantID:   batch.TenantID,
+		FeedPath:   xmlPath,
+		OwnersPath: xlsPath,
+	})
 	if err != nil {
-		log.Printf("❌ Failed to parse XML: %v", err)
-		_ = h.importService.LogError(ctx, batch, "xml_parse", err.Error(), nil)
+		log.Printf("❌ Failed to parse %s export: %v", batch.Source, err)
+		_ = h.importService.LogError(ctx, batch, "parse_failed", err.Error(), nil)
 		batch.Status = "failed"
 		_ = h.importService.CompleteBatch(ctx, batch)
 		return
 	}
 
-	batch.TotalXMLRecords = len(xmlData.Imoveis)
-
-	// Debug: Log photo count in first property from XML
-	if len(xmlData.Imoveis) > 0 {
-		firstProp := xmlData.Imoveis[0]
-		log.Printf("🔍 DEBUG XML: First property %s has %d Foto tags in raw XML", firstProp.Referencia, len(firstProp.Fotos))
-		if len(firstProp.Fotos) > 0 {
-			log.Printf("   First Foto: URL='%s', Principal=%d", firstProp.Fotos[0].URL, firstProp.Fotos[0].Principal)
-		}
-	}
+	batch.TotalXMLRecords = len(payloads)
+	log.Printf("✅ Parsed %s export with %d properties", batch.Source, len(payloads))
 
 	// Import properties
-	for i, xmlImovel := range xmlData.Imoveis {
-		// Find matching XLS record
-		var xlsRecord *union.XLSRecord
-		if len(xlsRecords) > 0 {
-			xlsRecord = union.FindXLSRecordByCode(xlsRecords, &xmlImovel)
-		}
-
-		// Normalize to PropertyPayload
-		payload := union.NormalizeProperty(&xmlImovel, xlsRecord, batch.TenantID)
-
+	for i, payload := range payloads {
 		// Debug: Log photo count
 		if i < 3 { // Only log first 3 properties to avoid spam
-			log.Printf("🖼️  Property %s has %d photos in payload", xmlImovel.Referencia, len(payload.Photos))
+			log.Printf("🖼️  Property %s has %d photos in payload", payload.Property.Reference, len(payload.Photos))
 			if len(payload.Photos) > 0 {
 				log.Printf("   First photo URL: %s", payload.Photos[0])
 			}
@@ -247,10 +247,10 @@ func (h *ImportHandler) processImport(ctx context.Context, batch *models.ImportB
 
 		// Import property
 		if err := h.importService.ImportProperty(ctx, batch, payload); err != nil {
-			log.Printf("❌ Error importing property %s: %v", xmlImovel.Referencia, err)
+			log.Printf("❌ Error importing property %s: %v", payload.Property.Reference, err)
 			_ = h.importService.LogError(ctx, batch, "import_failed", err.Error(), map[string]interface{}{
-				"reference":    xmlImovel.Referencia,
-				"external_id":  xmlImovel.Codigoimovel,
+				"reference":    payload.Property.Reference,
+				"external_id":  payload.Property.ExternalID,
 				"property_idx": i,
 			})
 			continue
@@ -258,7 +258,7 @@ func (h *ImportHandler) processImport(ctx context.Context, batch *models.ImportB
 
 		// Progress log every 50 properties
 		if (i+1)%50 == 0 {
-			log.Printf("📥 Import progress: %d/%d properties", i+1, len(xmlData.Imoveis))
+			log.Printf("📥 Import progress: %d/%d properties", i+1, len(payloads))
 		}
 	}
 
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/scheduler"
+)
+
+// JobHandler exposes the background job scheduler (platform admins only)
+type JobHandler struct {
+	scheduler *scheduler.Scheduler
+}
+
+// NewJobHandler creates a new job handler
+func NewJobHandler(scheduler *scheduler.Scheduler) *JobHandler {
+	return &JobHandler{
+		scheduler: scheduler,
+	}
+}
+
+// ListJobs lists the background jobs with their schedule, next run and last run
+// @Summary List background jobs
+// @Description List the scheduled background jobs with their next and latest runs (platform admins only)
+// @Tags jobs
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/admin/{tenant_id}/jobs [get]
+func (h *JobHandler) ListJobs(c *gin.Context) {
+	jobs, err := h.scheduler.Jobs(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    jobs,
+		"count":   len(jobs),
+	})
+}
+
+// ListJobRuns lists the latest runs of a background job
+// @Summary List background job runs
+// @Description List the latest runs of a job with status, duration and per-tenant failures, newest first
+// @Tags jobs
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param name path string true "Job name"
+// @Param limit query int false "Limit" default(20)
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/admin/{tenant_id}/jobs/{name}/runs [get]
+func (h *JobHandler) ListJobRuns(c *gin.Context) {
+	limit := 20
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 && l <= 100 {
+		limit = l
+	}
+
+	runs, err := h.scheduler.Runs(c.Request.Context(), c.Param("name"), limit)
+	if err != nil {
+		if errors.Is(err, scheduler.ErrUnknownJob) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "job not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    runs,
+		"count":   len(runs),
+	})
+}
+
+// TriggerJob starts a manual run of a background job for every active tenant
+// @Summary Run a background job now
+// @Description Start a manual run of a job in the background; returns the run record
+// @Tags jobs
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param name path string true "Job name"
+// @Success 202 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/admin/{tenant_id}/jobs/{name}/run [post]
+func (h *JobHandler) TriggerJob(c *gin.Context) {
+	run, err := h.scheduler.RunNow(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		switch {
+		case errors.Is(err, scheduler.ErrUnknownJob):
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "job not found",
+			})
+		case errors.Is(err, scheduler.ErrJobLocked):
+			c.JSON(http.StatusConflict, gin.H{
+				"success": false,
+				"error":   "job is already running",
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"success": true,
+		"data":    run,
+	})
+}
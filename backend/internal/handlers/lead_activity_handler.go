@@ -0,0 +1,502 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// registerActivityRoutes registers the notes, tasks, interactions and
+// timeline routes of leads
+func (h *LeadHandler) registerActivityRoutes(leads *gin.RouterGroup) {
+	leads.GET("/tasks", h.ListTasks)
+	leads.GET("/:id/timeline", h.GetTimeline)
+
+	leads.GET("/:id/notes", h.ListNotes)
+	leads.POST("/:id/notes", h.AddNote)
+	leads.PUT("/:id/notes/:note_id", h.UpdateNote)
+	leads.DELETE("/:id/notes/:note_id", h.DeleteNote)
+
+	leads.GET("/:id/tasks", h.ListLeadTasks)
+	leads.POST("/:id/tasks", h.CreateTask)
+	leads.PUT("/:id/tasks/:task_id", h.UpdateTask)
+	leads.POST("/:id/tasks/:task_id/close", h.CloseTask)
+
+	leads.GET("/:id/interactions", h.ListInteractions)
+	leads.POST("/:id/interactions", h.LogInteraction)
+}
+
+// LeadNoteRequest represents the request body for writing a lead note
+type LeadNoteRequest struct {
+	Body string `json:"body" binding:"required"`
+}
+
+// CreateLeadTaskRequest represents the request body for creating a lead task
+type CreateLeadTaskRequest struct {
+	Title       string     `json:"title" binding:"required"`
+	Description string     `json:"description"`
+	AssignedTo  string     `json:"assigned_to"` // default: broker of the lead
+	DueAt       time.Time  `json:"due_at" binding:"required"`
+	RemindAt    *time.Time `json:"remind_at"`
+}
+
+// CloseLeadTaskRequest represents the request body for closing a lead task
+type CloseLeadTaskRequest struct {
+	Status models.LeadTaskStatus `json:"status"` // done (default) or cancelled
+}
+
+// LogLeadInteractionRequest represents the request body for logging an interaction
+type LogLeadInteractionRequest struct {
+	Type            models.LeadInteractionType      `json:"type" binding:"required"`
+	Direction       models.LeadInteractionDirection `json:"direction"`   // default outbound
+	OccurredAt      *time.Time                      `json:"occurred_at"` // default now
+	DurationMinutes int                             `json:"duration_minutes"`
+	Outcome         string                          `json:"outcome"`
+	Summary         string                          `json:"summary"`
+}
+
+// GetTimeline returns the combined timeline of a lead
+// @Summary Lead timeline
+// @Description Notes, tasks, interactions and activity log events of a lead, most recent first
+// @Tags leads
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Lead ID"
+// @Param limit query int false "Maximum entries (default 100, max 500)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/{tenant_id}/leads/{id}/timeline [get]
+func (h *LeadHandler) GetTimeline(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	entries, err := h.leadService.GetLeadTimeline(c.Request.Context(), tenantID, id, limit)
+	if err != nil {
+		h.respondActivityError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    entries,
+		"count":   len(entries),
+	})
+}
+
+// ListNotes lists the notes of a lead
+// @Summary List lead notes
+// @Tags leads
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Lead ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/leads/{id}/notes [get]
+func (h *LeadHandler) ListNotes(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	notes, err := h.leadService.ListNotes(c.Request.Context(), tenantID, id, parsePaginationOptions(c))
+	if err != nil {
+		h.respondActivityError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    notes,
+		"count":   len(notes),
+	})
+}
+
+// AddNote adds a note to a lead
+// @Summary Add lead note
+// @Tags leads
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Lead ID"
+// @Param body body LeadNoteRequest true "Note"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/leads/{id}/notes [post]
+func (h *LeadHandler) AddNote(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	var req LeadNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	note, err := h.leadService.AddNote(c.Request.Context(), tenantID, id, actorID(c), req.Body)
+	if err != nil {
+		h.respondActivityError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    note,
+	})
+}
+
+// UpdateNote replaces the body of a lead note
+// @Summary Update lead note
+// @Tags leads
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Lead ID"
+// @Param note_id path string true "Note ID"
+// @Param body body LeadNoteRequest true "Note"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/leads/{id}/notes/{note_id} [put]
+func (h *LeadHandler) UpdateNote(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	var req LeadNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	note, err := h.leadService.UpdateNote(c.Request.Context(), tenantID, id, c.Param("note_id"), req.Body)
+	if err != nil {
+		h.respondActivityError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    note,
+	})
+}
+
+// DeleteNote deletes a lead note
+// @Summary Delete lead note
+// @Tags leads
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Lead ID"
+// @Param note_id path string true "Note ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/leads/{id}/notes/{note_id} [delete]
+func (h *LeadHandler) DeleteNote(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	if err := h.leadService.DeleteNote(c.Request.Context(), tenantID, id, c.Param("note_id"), actorID(c)); err != nil {
+		h.respondActivityError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Note deleted successfully",
+	})
+}
+
+// ListTasks lists the lead tasks of the tenant
+// @Summary List lead tasks
+// @Description Follow-up tasks of every lead, soonest due first
+// @Tags leads
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param assigned_to query string false "Broker ID filter"
+// @Param status query string false "Status filter (open, done, cancelled)"
+// @Param overdue query bool false "Only open tasks past their due date"
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/{tenant_id}/leads/tasks [get]
+func (h *LeadHandler) ListTasks(c *gin.Context) {
+	h.listTasks(c, &repositories.LeadTaskFilters{})
+}
+
+// ListLeadTasks lists the tasks of a lead
+// @Summary List tasks of a lead
+// @Tags leads
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Lead ID"
+// @Param status query string false "Status filter (open, done, cancelled)"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/{tenant_id}/leads/{id}/tasks [get]
+func (h *LeadHandler) ListLeadTasks(c *gin.Context) {
+	h.listTasks(c, &repositories.LeadTaskFilters{LeadID: c.Param("id")})
+}
+
+func (h *LeadHandler) listTasks(c *gin.Context, filters *repositories.LeadTaskFilters) {
+	tenantID := c.Param("tenant_id")
+
+	if assignedTo := c.Query("assigned_to"); assignedTo != "" {
+		filters.AssignedTo = assignedTo
+	}
+	if status := c.Query("status"); status != "" {
+		taskStatus := models.LeadTaskStatus(status)
+		filters.Status = &taskStatus
+	}
+	if c.Query("overdue") == "true" {
+		open := models.LeadTaskStatusOpen
+		now := time.Now()
+		filters.Status = &open
+		filters.DueBefore = &now
+	}
+
+	opts := parsePaginationOptions(c)
+	opts.OrderBy = "" // Soonest due first
+
+	tasks, err := h.leadService.ListTasks(c.Request.Context(), tenantID, filters, opts)
+	if err != nil {
+		h.respondActivityError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    tasks,
+		"count":   len(tasks),
+	})
+}
+
+// CreateTask creates a follow-up task on a lead
+// @Summary Create lead task
+// @Tags leads
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Lead ID"
+// @Param body body CreateLeadTaskRequest true "Task"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/leads/{id}/tasks [post]
+func (h *LeadHandler) CreateTask(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	var req CreateLeadTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	task := &models.LeadTask{
+		TenantID:    tenantID,
+		LeadID:      id,
+		Title:       req.Title,
+		Description: req.Description,
+		AssignedTo:  req.AssignedTo,
+		CreatedBy:   actorID(c),
+		DueAt:       req.DueAt,
+		RemindAt:    req.RemindAt,
+	}
+	if err := h.leadService.CreateTask(c.Request.Context(), task); err != nil {
+		h.respondActivityError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    task,
+	})
+}
+
+// UpdateTask changes an open lead task
+// @Summary Update lead task
+// @Tags leads
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Lead ID"
+// @Param task_id path string true "Task ID"
+// @Param body body services.LeadTaskUpdate true "Fields to change"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/leads/{id}/tasks/{task_id} [put]
+func (h *LeadHandler) UpdateTask(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	var req services.LeadTaskUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	task, err := h.leadService.UpdateTask(c.Request.Context(), tenantID, id, c.Param("task_id"), req)
+	if err != nil {
+		h.respondActivityError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    task,
+	})
+}
+
+// CloseTask marks a lead task done or cancelled
+// @Summary Close lead task
+// @Tags leads
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Lead ID"
+// @Param task_id path string true "Task ID"
+// @Param body body CloseLeadTaskRequest false "Final status"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/leads/{id}/tasks/{task_id}/close [post]
+func (h *LeadHandler) CloseTask(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	var req CloseLeadTaskRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+	}
+	if req.Status == "" {
+		req.Status = models.LeadTaskStatusDone
+	}
+
+	task, err := h.leadService.CloseTask(c.Request.Context(), tenantID, id, c.Param("task_id"), req.Status, actorID(c))
+	if err != nil {
+		h.respondActivityError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    task,
+	})
+}
+
+// ListInteractions lists the interactions of a lead
+// @Summary List lead interactions
+// @Tags leads
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Lead ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/leads/{id}/interactions [get]
+func (h *LeadHandler) ListInteractions(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	opts := parsePaginationOptions(c)
+	opts.OrderBy = "" // Most recent first
+
+	interactions, err := h.leadService.ListInteractions(c.Request.Context(), tenantID, id, opts)
+	if err != nil {
+		h.respondActivityError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    interactions,
+		"count":   len(interactions),
+	})
+}
+
+// LogInteraction logs a call, visit or message with a lead
+// @Summary Log lead interaction
+// @Description Outbound interactions count as the first response to the lead
+// @Tags leads
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Lead ID"
+// @Param body body LogLeadInteractionRequest true "Interaction"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/leads/{id}/interactions [post]
+func (h *LeadHandler) LogInteraction(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	var req LogLeadInteractionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	interaction := &models.LeadInteraction{
+		TenantID:        tenantID,
+		LeadID:          id,
+		Type:            req.Type,
+		Direction:       req.Direction,
+		BrokerID:        actorID(c),
+		DurationMinutes: req.DurationMinutes,
+		Outcome:         req.Outcome,
+		Summary:         req.Summary,
+	}
+	if req.OccurredAt != nil {
+		interaction.OccurredAt = *req.OccurredAt
+	}
+	if err := h.leadService.LogInteraction(c.Request.Context(), interaction); err != nil {
+		h.respondActivityError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    interaction,
+	})
+}
+
+// respondActivityError maps lead note, task and interaction errors to HTTP status codes
+func (h *LeadHandler) respondActivityError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, repositories.ErrInvalidInput):
+		status = http.StatusBadRequest
+	}
+
+	c.JSON(status, gin.H{
+		"success": false,
+		"error":   err.Error(),
+	})
+}
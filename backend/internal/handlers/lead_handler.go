@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"time"
 
 	"github.com/altatech/ecosistema-imob/backend/internal/models"
 	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
@@ -30,10 +32,15 @@ func (h *LeadHandler) RegisterRoutes(router *gin.RouterGroup) {
 		leads.PUT("/:id", h.UpdateLead)
 		leads.DELETE("/:id", h.DeleteLead)
 		leads.GET("", h.ListLeads)
+		leads.GET("/sla-report", h.GetSLAReport)
 		leads.POST("/:id/status", h.UpdateStatus)
 		leads.POST("/:id/assign", h.AssignToBroker)
+		leads.POST("/:id/route", h.RouteLead)
 		leads.POST("/:id/revoke-consent", h.RevokeConsent)
 		leads.POST("/:id/anonymize", h.AnonymizeLead)
+
+		// Notes, follow-up tasks, interactions and timeline
+		h.registerActivityRoutes(leads)
 	}
 
 	// PROMPT 07: Public endpoints for WhatsApp and Form leads
@@ -216,8 +223,12 @@ func (h *LeadHandler) DeleteLead(c *gin.Context) {
 // @Produce json
 // @Param tenant_id path string true "Tenant ID"
 // @Param limit query int false "Limit" default(50)
-// @Param order_by query string false "Order by field" default(created_at)
+// @Param order_by query string false "Order by field (created_at, updated_at); filtered lists only by created_at" default(created_at)
+// @Param order query string false "Sort direction (asc, desc)" default(desc)
+// @Param cursor query string false "next_cursor of the previous page"
 // @Param property_id query string false "Property ID filter"
+// @Param contact_id query string false "Contact ID filter"
+// @Param assigned_broker_id query string false "Assigned broker ID filter"
 // @Param status query string false "Status filter"
 // @Param channel query string false "Channel filter"
 // @Success 200 {object} map[string]interface{}
@@ -227,7 +238,14 @@ func (h *LeadHandler) ListLeads(c *gin.Context) {
 	tenantID := c.Param("tenant_id")
 
 	// Parse pagination options
-	opts := parsePaginationOptions(c)
+	opts, err := parseListOptions(c, leadSortFields)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
 
 	// Parse filters
 	filters := &repositories.LeadFilters{}
@@ -236,6 +254,14 @@ func (h *LeadHandler) ListLeads(c *gin.Context) {
 		filters.PropertyID = propertyID
 	}
 
+	if contactID := c.Query("contact_id"); contactID != "" {
+		filters.ContactID = contactID
+	}
+
+	if brokerID := c.Query("assigned_broker_id"); brokerID != "" {
+		filters.AssignedBrokerID = brokerID
+	}
+
 	if status := c.Query("status"); status != "" {
 		leadStatus := models.LeadStatus(status)
 		filters.Status = &leadStatus
@@ -246,6 +272,14 @@ func (h *LeadHandler) ListLeads(c *gin.Context) {
 		filters.Channel = &leadChannel
 	}
 
+	if err := checkFilteredSort(opts, *filters != repositories.LeadFilters{}); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
 	leads, err := h.leadService.ListLeads(c.Request.Context(), tenantID, filters, opts)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -255,10 +289,71 @@ func (h *LeadHandler) ListLeads(c *gin.Context) {
 		return
 	}
 
+	c.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"data":        leads,
+		"count":       len(leads),
+		"next_cursor": repositories.NextCursor(leads, opts),
+	})
+}
+
+// GetSLAReport returns the first-response SLA compliance per broker
+// @Summary Lead SLA report
+// @Description First-response SLA compliance per broker of the leads created in a period (default: last 30 days)
+// @Tags leads
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param from query string false "Period start (RFC3339)"
+// @Param to query string false "Period end, exclusive (RFC3339)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/{tenant_id}/leads/sla-report [get]
+func (h *LeadHandler) GetSLAReport(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+
+	to := time.Now()
+	if toStr := c.Query("to"); toStr != "" {
+		parsed, err := time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "invalid to format, use RFC3339",
+			})
+			return
+		}
+		to = parsed
+	}
+
+	from := to.AddDate(0, 0, -30)
+	if fromStr := c.Query("from"); fromStr != "" {
+		parsed, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "invalid from format, use RFC3339",
+			})
+			return
+		}
+		from = parsed
+	}
+
+	report, err := h.leadService.GetSLAReport(c.Request.Context(), tenantID, from, to)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, repositories.ErrInvalidInput) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
-		"data":    leads,
-		"count":   len(leads),
+		"data":    report,
 	})
 }
 
@@ -343,10 +438,17 @@ func (h *LeadHandler) AssignToBroker(c *gin.Context) {
 	}
 
 	if err := h.leadService.AssignToBroker(c.Request.Context(), tenantID, id, req.BrokerID); err != nil {
-		if err == repositories.ErrNotFound {
+		if errors.Is(err, repositories.ErrNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{
 				"success": false,
-				"error":   "lead not found",
+				"error":   err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, repositories.ErrInvalidInput) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   err.Error(),
 			})
 			return
 		}
@@ -363,6 +465,51 @@ func (h *LeadHandler) AssignToBroker(c *gin.Context) {
 	})
 }
 
+// RouteLead routes a lead again with the tenant's routing strategy
+// @Summary Route lead
+// @Description Assign a lead to a broker with the tenant's lead routing strategy (primary broker, round-robin, least open leads or territory)
+// @Tags leads
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Lead ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/{tenant_id}/leads/{id}/route [post]
+func (h *LeadHandler) RouteLead(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	brokerID, err := h.leadService.RouteToAvailableBroker(c.Request.Context(), tenantID, id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, services.ErrNoBrokerAvailable) {
+			c.JSON(http.StatusConflict, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"broker_id": brokerID},
+	})
+}
+
 // RevokeConsent revokes lead consent (LGPD)
 // @Summary Revoke lead consent
 // @Description Revoke LGPD consent for a lead
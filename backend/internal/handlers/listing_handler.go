@@ -203,7 +203,9 @@ func (h *ListingHandler) DeleteListing(c *gin.Context) {
 // @Produce json
 // @Param tenant_id path string true "Tenant ID"
 // @Param limit query int false "Limit" default(50)
-// @Param order_by query string false "Order by field" default(created_at)
+// @Param order_by query string false "Order by field (created_at, updated_at)" default(created_at)
+// @Param order query string false "Sort direction (asc, desc)" default(desc)
+// @Param cursor query string false "next_cursor of the previous page"
 // @Param property_id query string false "Property ID filter"
 // @Param broker_id query string false "Broker ID filter"
 // @Success 200 {object} map[string]interface{}
@@ -213,10 +215,16 @@ func (h *ListingHandler) ListListings(c *gin.Context) {
 	tenantID := c.Param("tenant_id")
 
 	// Parse pagination options
-	opts := parsePaginationOptions(c)
+	opts, err := parseListOptions(c, listingSortFields)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
 
 	var listings []*models.Listing
-	var err error
 
 	// Check for filters
 	if propertyID := c.Query("property_id"); propertyID != "" {
@@ -236,9 +244,10 @@ func (h *ListingHandler) ListListings(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    listings,
-		"count":   len(listings),
+		"success":     true,
+		"data":        listings,
+		"count":       len(listings),
+		"next_cursor": repositories.NextCursor(listings, opts),
 	})
 }
 
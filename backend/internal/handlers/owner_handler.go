@@ -204,7 +204,9 @@ func (h *OwnerHandler) DeleteOwner(c *gin.Context) {
 // @Produce json
 // @Param tenant_id path string true "Tenant ID"
 // @Param limit query int false "Limit" default(50)
-// @Param order_by query string false "Order by field" default(created_at)
+// @Param order_by query string false "Order by field (created_at, updated_at)" default(created_at)
+// @Param order query string false "Sort direction (asc, desc)" default(desc)
+// @Param cursor query string false "next_cursor of the previous page"
 // @Success 200 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /api/{tenant_id}/owners [get]
@@ -212,7 +214,14 @@ func (h *OwnerHandler) ListOwners(c *gin.Context) {
 	tenantID := c.Param("tenant_id")
 
 	// Parse pagination options
-	opts := parsePaginationOptions(c)
+	opts, err := parseListOptions(c, ownerSortFields)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
 
 	owners, err := h.ownerService.ListOwners(c.Request.Context(), tenantID, opts)
 	if err != nil {
@@ -224,9 +233,10 @@ func (h *OwnerHandler) ListOwners(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    owners,
-		"count":   len(owners),
+		"success":     true,
+		"data":        owners,
+		"count":       len(owners),
+		"next_cursor": repositories.NextCursor(owners, opts),
 	})
 }
 
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/payments"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/services"
+)
+
+// PaymentWebhookHandler receives the payment confirmations of the payment
+// providers (public, authenticated by provider signatures)
+type PaymentWebhookHandler struct {
+	billingService *services.RentBillingService
+	providers      map[string]payments.Provider
+}
+
+// NewPaymentWebhookHandler creates a new payment webhook handler for the
+// configured providers
+func NewPaymentWebhookHandler(billingService *services.RentBillingService, providers ...payments.Provider) *PaymentWebhookHandler {
+	h := &PaymentWebhookHandler{
+		billingService: billingService,
+		providers:      make(map[string]payments.Provider),
+	}
+	for _, provider := range providers {
+		h.providers[provider.Name()] = provider
+	}
+	return h
+}
+
+// PaymentStatus receives payment notifications
+// @Summary Payment webhook
+// @Description Records the rent payments confirmed by a payment provider on the invoices charged with their reference
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param provider path string true "Provider name"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/webhooks/payments/{provider} [post]
+func (h *PaymentWebhookHandler) PaymentStatus(c *gin.Context) {
+	provider, ok := h.providers[c.Param("provider")]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": "payment provider is not configured"})
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, 1<<20))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "failed to read body"})
+		return
+	}
+
+	if !provider.VerifySignature(body, c.Request.Header) {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": "invalid signature"})
+		return
+	}
+
+	events, err := provider.ParseWebhook(body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
+		return
+	}
+
+	// Unknown references are acknowledged anyway so the provider does not
+	// retry them
+	applied := 0
+	for _, event := range events {
+		if _, err := h.billingService.ApplyProviderPayment(c.Request.Context(), event); err != nil {
+			if !errors.Is(err, repositories.ErrNotFound) {
+				log.Printf("❌ Failed to apply %s payment %s to invoice %s: %v", event.Provider, event.PaymentID, event.Reference, err)
+			}
+			continue
+		}
+		applied++
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "applied": applied})
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// PortalFeedHandler serves the VRSync feeds read by listing portals
+type PortalFeedHandler struct {
+	portalFeedService *services.PortalFeedService
+}
+
+// NewPortalFeedHandler creates a new portal feed handler
+func NewPortalFeedHandler(portalFeedService *services.PortalFeedService) *PortalFeedHandler {
+	return &PortalFeedHandler{
+		portalFeedService: portalFeedService,
+	}
+}
+
+// GetVRSyncFeed returns the tenant's VRSync feed for a portal
+// @Summary Get portal VRSync feed
+// @Description VRSync XML feed of the tenant properties syndicated to a portal (zap, vivareal, olx). The URL is stable and meant to be registered in the portal.
+// @Tags portal-feeds
+// @Produce xml
+// @Param tenant_id path string true "Tenant ID"
+// @Param portal path string true "Portal (zap, vivareal, olx), optionally with .xml"
+// @Success 200 {string} string "VRSync XML"
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/{tenant_id}/feeds/vrsync/{portal} [get]
+func (h *PortalFeedHandler) GetVRSyncFeed(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	portal := models.Portal(strings.TrimSuffix(strings.ToLower(c.Param("portal")), ".xml"))
+
+	feed, err := h.portalFeedService.RenderFeed(c.Request.Context(), tenantID, portal)
+	if err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrInvalidInput):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+		case errors.Is(err, repositories.ErrNotFound):
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "tenant not found",
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error":   "failed to generate feed",
+				"details": err.Error(),
+			})
+		}
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=300")
+	c.Data(http.StatusOK, "application/xml; charset=utf-8", feed)
+}
@@ -1,8 +1,11 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"strings"
 
+	"cloud.google.com/go/firestore"
 	"github.com/altatech/ecosistema-imob/backend/internal/models"
 	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
 	"github.com/altatech/ecosistema-imob/backend/internal/services"
@@ -33,7 +36,9 @@ func (h *PropertyHandler) RegisterRoutes(router *gin.RouterGroup) {
 		properties.GET("", h.ListProperties)
 		properties.POST("/:id/status", h.UpdateStatus)
 		properties.POST("/:id/visibility", h.UpdateVisibility)
+		properties.POST("/:id/portals", h.UpdatePortals)
 		properties.GET("/:id/duplicates", h.CheckDuplicates)
+		properties.GET("/:id/history", h.ListPropertyHistory)
 
 		// PROMPT 08: Property Status Confirmation
 		properties.PATCH("/:id/confirmations", h.ConfirmPropertyStatusPrice)
@@ -119,6 +124,61 @@ func (h *PropertyHandler) GetProperty(c *gin.Context) {
 	})
 }
 
+// ListPropertyHistory lists the price and status changes of a property
+// @Summary List property price/status history
+// @Description List the price and status changes of a property (old/new value, actor and source), newest first
+// @Tags properties
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Property ID"
+// @Param limit query int false "Limit" default(50)
+// @Param cursor query string false "next_cursor of the previous page"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/{tenant_id}/properties/{id}/history [get]
+func (h *PropertyHandler) ListPropertyHistory(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	// History is always read newest first
+	opts := parsePaginationOptions(c)
+	opts.OrderBy = "changed_at"
+	opts.Direction = firestore.Desc
+	opts.StartAfter = nil
+	if err := opts.ApplyCursor(c.Query("cursor")); err != nil || opts.OrderBy != "changed_at" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "invalid cursor",
+		})
+		return
+	}
+
+	entries, err := h.propertyService.ListPropertyHistory(c.Request.Context(), tenantID, id, opts)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "property not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"data":        entries,
+		"count":       len(entries),
+		"next_cursor": repositories.NextCursor(entries, opts),
+	})
+}
+
 // GetPropertyBySlug retrieves a property by slug
 // @Summary Get property by slug
 // @Description Get property details by slug
@@ -183,7 +243,7 @@ func (h *PropertyHandler) UpdateProperty(c *gin.Context) {
 		return
 	}
 
-	if err := h.propertyService.UpdateProperty(c.Request.Context(), tenantID, id, updates); err != nil {
+	if err := h.propertyService.UpdateProperty(c.Request.Context(), tenantID, id, actorID(c), updates); err != nil {
 		if err == repositories.ErrNotFound {
 			c.JSON(http.StatusNotFound, gin.H{
 				"success": false,
@@ -191,6 +251,13 @@ func (h *PropertyHandler) UpdateProperty(c *gin.Context) {
 			})
 			return
 		}
+		if errors.Is(err, repositories.ErrInvalidInput) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
 			"error":   err.Error(),
@@ -247,12 +314,15 @@ func (h *PropertyHandler) DeleteProperty(c *gin.Context) {
 // @Produce json
 // @Param tenant_id path string true "Tenant ID"
 // @Param limit query int false "Limit" default(50)
-// @Param order_by query string false "Order by field" default(created_at)
+// @Param order_by query string false "Order by field (created_at, updated_at, price_amount, area); filtered lists only by created_at, area leaves out properties without one" default(created_at)
+// @Param order query string false "Sort direction (asc, desc)" default(desc)
+// @Param cursor query string false "next_cursor of the previous page"
 // @Param property_type query string false "Property type filter"
 // @Param status query string false "Status filter"
 // @Param visibility query string false "Visibility filter"
 // @Param city query string false "City filter"
 // @Param neighborhood query string false "Neighborhood filter"
+// @Param q query string false "Full-text search (title, description, address, reference)"
 // @Success 200 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /api/{tenant_id}/properties [get]
@@ -260,7 +330,14 @@ func (h *PropertyHandler) ListProperties(c *gin.Context) {
 	tenantID := c.Param("tenant_id")
 
 	// Parse pagination options
-	opts := parsePaginationOptions(c)
+	opts, err := parseListOptions(c, propertySortFields)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
 
 	// Parse filters
 	filters := &repositories.PropertyFilters{}
@@ -292,20 +369,46 @@ func (h *PropertyHandler) ListProperties(c *gin.Context) {
 		filters.OwnerID = ownerID
 	}
 
-	properties, err := h.propertyService.ListProperties(c.Request.Context(), tenantID, filters, opts)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
-		return
-	}
+	var properties []*models.Property
+	var total int
+	var nextCursor string
 
-	// Get total count (without pagination)
-	total, err := h.propertyService.CountProperties(c.Request.Context(), tenantID, filters)
-	if err != nil {
-		// Log error but don't fail the request - count is optional
-		total = len(properties)
+	if query := strings.TrimSpace(c.Query("q")); query != "" {
+		// Full-text search: results are ordered by relevance and total counts every match
+		properties, total, err = h.propertyService.SearchPropertiesFullText(c.Request.Context(), tenantID, query, filters, opts)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+	} else {
+		if err := checkFilteredSort(opts, *filters != repositories.PropertyFilters{}); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+
+		properties, err = h.propertyService.ListProperties(c.Request.Context(), tenantID, filters, opts)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+
+		nextCursor = repositories.NextCursor(properties, opts)
+
+		// Get total count (without pagination)
+		total, err = h.propertyService.CountProperties(c.Request.Context(), tenantID, filters)
+		if err != nil {
+			// Log error but don't fail the request - count is optional
+			total = len(properties)
+		}
 	}
 
 	// Get property statistics (types and status counts)
@@ -316,11 +419,12 @@ func (h *PropertyHandler) ListProperties(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    properties,
-		"count":   len(properties),
-		"total":   total,
-		"stats":   stats,
+		"success":     true,
+		"data":        properties,
+		"count":       len(properties),
+		"total":       total,
+		"stats":       stats,
+		"next_cursor": nextCursor,
 	})
 }
 
@@ -352,7 +456,7 @@ func (h *PropertyHandler) UpdateStatus(c *gin.Context) {
 		return
 	}
 
-	if err := h.propertyService.UpdateStatus(c.Request.Context(), tenantID, id, req.Status); err != nil {
+	if err := h.propertyService.UpdateStatus(c.Request.Context(), tenantID, id, actorID(c), req.Status); err != nil {
 		if err == repositories.ErrNotFound {
 			c.JSON(http.StatusNotFound, gin.H{
 				"success": false,
@@ -426,6 +530,54 @@ func (h *PropertyHandler) UpdateVisibility(c *gin.Context) {
 	})
 }
 
+// UpdatePortals sets the portals a property is syndicated to
+// @Summary Update property portals
+// @Description Select the portals (ZAP, VivaReal, OLX) whose VRSync feed includes the property. Only public/marketplace available properties are published.
+// @Tags properties
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Property ID"
+// @Param body body models.PortalFlags true "Portal flags"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/{tenant_id}/properties/{id}/portals [post]
+func (h *PropertyHandler) UpdatePortals(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	var req models.PortalFlags
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	if err := h.propertyService.UpdatePortals(c.Request.Context(), tenantID, id, req); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "property not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"portals": req},
+	})
+}
+
 // CheckDuplicates checks for duplicate properties
 // @Summary Check for duplicate properties
 // @Description Check for duplicate properties by fingerprint
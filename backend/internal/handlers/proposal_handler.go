@@ -0,0 +1,286 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// ProposalHandler handles proposal (offer) HTTP requests
+type ProposalHandler struct {
+	proposalService *services.ProposalService
+}
+
+// NewProposalHandler creates a new proposal handler
+func NewProposalHandler(proposalService *services.ProposalService) *ProposalHandler {
+	return &ProposalHandler{
+		proposalService: proposalService,
+	}
+}
+
+// RegisterRoutes registers proposal routes (tenant-scoped)
+func (h *ProposalHandler) RegisterRoutes(router *gin.RouterGroup) {
+	proposals := router.Group("/proposals")
+	{
+		proposals.GET("", h.ListProposals)
+		proposals.POST("", h.SubmitProposal)
+		proposals.GET("/:id", h.GetProposal)
+		proposals.POST("/:id/counter", h.CounterProposal)
+		proposals.POST("/:id/accept", h.AcceptProposal)
+		proposals.POST("/:id/reject", h.RejectProposal)
+	}
+}
+
+// SubmitProposalRequest represents the request body for recording the offer of a lead
+type SubmitProposalRequest struct {
+	LeadID          string                 `json:"lead_id" binding:"required"`
+	PropertyID      string                 `json:"property_id"`      // default: property of the lead
+	BrokerID        string                 `json:"broker_id"`        // default: broker of the lead
+	TransactionType models.TransactionType `json:"transaction_type"` // sale (default) or rent
+	Terms           models.ProposalTerms   `json:"terms"`            // valid_until defaults to 7 days
+	Note            string                 `json:"note"`
+}
+
+// CounterProposalRequest represents the request body for a counter-offer
+type CounterProposalRequest struct {
+	By    models.ProposalParty `json:"by" binding:"required"` // owner or buyer
+	Terms models.ProposalTerms `json:"terms"`
+	Note  string               `json:"note"`
+}
+
+// RejectProposalRequest represents the request body for rejecting a proposal
+type RejectProposalRequest struct {
+	Reason string `json:"reason"`
+}
+
+// ListProposals lists proposals
+// @Summary List proposals
+// @Description Proposals with filters, most recent first
+// @Tags proposals
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param property_id query string false "Property ID filter"
+// @Param lead_id query string false "Lead ID filter"
+// @Param broker_id query string false "Broker ID filter"
+// @Param status query string false "Status filter (submitted, countered, accepted, rejected, expired)"
+// @Param limit query int false "Limit" default(50)
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/{tenant_id}/proposals [get]
+func (h *ProposalHandler) ListProposals(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+
+	filters := &repositories.ProposalFilters{
+		PropertyID: c.Query("property_id"),
+		LeadID:     c.Query("lead_id"),
+		BrokerID:   c.Query("broker_id"),
+	}
+	if status := c.Query("status"); status != "" {
+		filters.Statuses = []models.ProposalStatus{models.ProposalStatus(status)}
+	}
+
+	proposals, err := h.proposalService.ListProposals(c.Request.Context(), tenantID, filters, parsePaginationOptions(c))
+	if err != nil {
+		h.respondProposalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    proposals,
+		"count":   len(proposals),
+	})
+}
+
+// GetProposal retrieves a proposal by ID
+// @Summary Get proposal
+// @Tags proposals
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Proposal ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/proposals/{id} [get]
+func (h *ProposalHandler) GetProposal(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	proposal, err := h.proposalService.GetProposal(c.Request.Context(), tenantID, id)
+	if err != nil {
+		h.respondProposalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    proposal,
+	})
+}
+
+// SubmitProposal records the offer of a lead
+// @Summary Submit proposal
+// @Description Record the offer of a lead for a property; the lead moves to negotiating and the owner is notified
+// @Tags proposals
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param body body SubmitProposalRequest true "Proposal"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/proposals [post]
+func (h *ProposalHandler) SubmitProposal(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+
+	var req SubmitProposalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	proposal := &models.Proposal{
+		TenantID:        tenantID,
+		LeadID:          req.LeadID,
+		PropertyID:      req.PropertyID,
+		BrokerID:        req.BrokerID,
+		TransactionType: req.TransactionType,
+		Terms:           req.Terms,
+	}
+
+	if err := h.proposalService.SubmitProposal(c.Request.Context(), proposal, req.Note, actorID(c)); err != nil {
+		h.respondProposalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    proposal,
+	})
+}
+
+// CounterProposal records a counter-offer
+// @Summary Counter proposal
+// @Description Record a counter-offer of the side the proposal is waiting for (the owner answers submitted proposals, the buyer countered ones)
+// @Tags proposals
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Proposal ID"
+// @Param body body CounterProposalRequest true "Counter-offer"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/proposals/{id}/counter [post]
+func (h *ProposalHandler) CounterProposal(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	var req CounterProposalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	proposal, err := h.proposalService.CounterProposal(c.Request.Context(), tenantID, id, req.By, req.Terms, req.Note, actorID(c))
+	if err != nil {
+		h.respondProposalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    proposal,
+	})
+}
+
+// AcceptProposal accepts a proposal
+// @Summary Accept proposal
+// @Description Accept the current terms; the property becomes unavailable and the other open proposals on it are rejected
+// @Tags proposals
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Proposal ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/proposals/{id}/accept [post]
+func (h *ProposalHandler) AcceptProposal(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	proposal, err := h.proposalService.AcceptProposal(c.Request.Context(), tenantID, id, actorID(c))
+	if err != nil {
+		h.respondProposalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    proposal,
+	})
+}
+
+// RejectProposal rejects a proposal
+// @Summary Reject proposal
+// @Tags proposals
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Proposal ID"
+// @Param body body RejectProposalRequest false "Reason"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/proposals/{id}/reject [post]
+func (h *ProposalHandler) RejectProposal(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	var req RejectProposalRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+	}
+
+	proposal, err := h.proposalService.RejectProposal(c.Request.Context(), tenantID, id, req.Reason, actorID(c))
+	if err != nil {
+		h.respondProposalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    proposal,
+	})
+}
+
+// respondProposalError maps proposal errors to HTTP status codes
+func (h *ProposalHandler) respondProposalError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, repositories.ErrInvalidInput):
+		status = http.StatusBadRequest
+	}
+
+	c.JSON(status, gin.H{
+		"success": false,
+		"error":   err.Error(),
+	})
+}
@@ -1,12 +1,15 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/altatech/ecosistema-imob/backend/internal/models"
 	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
 	"github.com/altatech/ecosistema-imob/backend/internal/services"
+	"github.com/altatech/ecosistema-imob/backend/internal/utils"
 	"github.com/gin-gonic/gin"
 )
 
@@ -24,6 +27,8 @@ func NewPublicPropertyHandler(propertyService *services.PropertyService) *Public
 }
 
 // ListPublicProperties lists all public properties across all tenants
+// Passing bbox or lat/lng switches to a geographic search for the portal map view;
+// passing q switches to a full-text search.
 // @Summary List public properties (cross-tenant)
 // @Description List public and available properties from all tenants for portal agregador
 // @Tags public-properties
@@ -38,7 +43,13 @@ func NewPublicPropertyHandler(propertyService *services.PropertyService) *Public
 // @Param max_price query float64 false "Maximum price"
 // @Param min_bedrooms query int false "Minimum bedrooms"
 // @Param min_bathrooms query int false "Minimum bathrooms"
+// @Param bbox query string false "Map bounding box: min_lat,min_lng,max_lat,max_lng (at most 5 degrees per side)"
+// @Param lat query float64 false "Latitude of the radius search center"
+// @Param lng query float64 false "Longitude of the radius search center"
+// @Param radius_km query float64 false "Radius in km around lat/lng" default(5)
+// @Param q query string false "Full-text search (title, description, address, reference)"
 // @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /api/public/properties [get]
 func (h *PublicPropertyHandler) ListPublicProperties(c *gin.Context) {
@@ -46,6 +57,87 @@ func (h *PublicPropertyHandler) ListPublicProperties(c *gin.Context) {
 	opts := parsePaginationOptions(c)
 
 	// Parse filters from query params
+	filters := parsePublicPropertyFilters(c)
+
+	var properties []*models.Property
+	var err error
+
+	switch {
+	case c.Query("bbox") != "":
+		bounds, parseErr := parseBoundingBox(c.Query("bbox"))
+		if parseErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   parseErr.Error(),
+			})
+			return
+		}
+		properties, err = h.propertyService.ListPublicPropertiesInBounds(c.Request.Context(), bounds, filters, opts.Limit)
+
+	case c.Query("lat") != "" || c.Query("lng") != "":
+		lat, latOK := utils.ParseCoordinate(c.Query("lat"))
+		lng, lngOK := utils.ParseCoordinate(c.Query("lng"))
+		if !latOK || !lngOK {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "lat and lng must both be valid numbers",
+			})
+			return
+		}
+
+		radiusKm := defaultSearchRadiusKm
+		if radius := c.Query("radius_km"); radius != "" {
+			if _, scanErr := fmt.Sscanf(radius, "%f", &radiusKm); scanErr != nil || radiusKm <= 0 || radiusKm > maxSearchRadiusKm {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"success": false,
+					"error":   fmt.Sprintf("radius_km must be between 0 and %.0f", maxSearchRadiusKm),
+				})
+				return
+			}
+		}
+		properties, err = h.propertyService.ListPublicPropertiesNearby(c.Request.Context(), lat, lng, radiusKm, filters, opts.Limit)
+
+	case strings.TrimSpace(c.Query("q")) != "":
+		// Full-text search, ordered by relevance
+		properties, _, err = h.propertyService.SearchPublicPropertiesFullText(c.Request.Context(), strings.TrimSpace(c.Query("q")), filters, opts)
+
+	default:
+		// Get public properties from service (across all tenants)
+		properties, err = h.propertyService.ListAllPublicProperties(c.Request.Context(), filters, opts)
+	}
+
+	if err != nil {
+		if errors.Is(err, repositories.ErrInvalidInput) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "Invalid geographic search",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to list public properties",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    properties,
+		"count":   len(properties),
+	})
+}
+
+const (
+	defaultSearchRadiusKm = 5.0
+	maxSearchRadiusKm     = 100.0
+)
+
+// parsePublicPropertyFilters parses the optional public listing filters from query params
+func parsePublicPropertyFilters(c *gin.Context) *repositories.PropertyFilters {
 	filters := &repositories.PropertyFilters{}
 
 	if propertyType := c.Query("property_type"); propertyType != "" {
@@ -94,22 +186,30 @@ func (h *PublicPropertyHandler) ListPublicProperties(c *gin.Context) {
 		}
 	}
 
-	// Get public properties from service (across all tenants)
-	properties, err := h.propertyService.ListAllPublicProperties(c.Request.Context(), filters, opts)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "Failed to list public properties",
-			"details": err.Error(),
-		})
-		return
+	return filters
+}
+
+// parseBoundingBox parses "min_lat,min_lng,max_lat,max_lng"
+func parseBoundingBox(value string) (utils.GeoBounds, error) {
+	parts := strings.Split(value, ",")
+	if len(parts) != 4 {
+		return utils.GeoBounds{}, fmt.Errorf("bbox must be min_lat,min_lng,max_lat,max_lng")
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    properties,
-		"count":   len(properties),
-	})
+	coords := make([]float64, 4)
+	for i, part := range parts {
+		coord, ok := utils.ParseCoordinate(part)
+		if !ok {
+			return utils.GeoBounds{}, fmt.Errorf("bbox must be min_lat,min_lng,max_lat,max_lng")
+		}
+		coords[i] = coord
+	}
+
+	bounds := utils.GeoBounds{MinLat: coords[0], MinLng: coords[1], MaxLat: coords[2], MaxLng: coords[3]}
+	if err := bounds.Validate(); err != nil {
+		return utils.GeoBounds{}, err
+	}
+	return bounds, nil
 }
 
 // GetPublicProperty retrieves a single public property by ID (cross-tenant)
@@ -0,0 +1,360 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// maxIndexCSVSize bounds an index series upload
+const maxIndexCSVSize = 1 << 20 // 1MB
+
+// RentAdjustmentHandler handles index series and rent adjustment HTTP requests
+type RentAdjustmentHandler struct {
+	adjustmentService *services.RentAdjustmentService
+}
+
+// NewRentAdjustmentHandler creates a new rent adjustment handler
+func NewRentAdjustmentHandler(adjustmentService *services.RentAdjustmentService) *RentAdjustmentHandler {
+	return &RentAdjustmentHandler{
+		adjustmentService: adjustmentService,
+	}
+}
+
+// RegisterRoutes registers rent adjustment routes (tenant-scoped). The index
+// import is registered by RegisterAdminRoutes.
+func (h *RentAdjustmentHandler) RegisterRoutes(router *gin.RouterGroup) {
+	indices := router.Group("/indices")
+	{
+		indices.GET("/:index", h.ListIndex)
+	}
+
+	adjustments := router.Group("/rent-adjustments")
+	{
+		adjustments.GET("", h.ListAdjustments)
+		adjustments.GET("/upcoming", h.UpcomingAdjustments)
+		adjustments.GET("/calculate", h.Calculate)
+		adjustments.GET("/:id", h.GetAdjustment)
+		adjustments.POST("/:id/apply", h.ApplyAdjustment)
+		adjustments.POST("/:id/dismiss", h.DismissAdjustment)
+	}
+}
+
+// RegisterAdminRoutes registers the index series import (platform admins:
+// the series are shared by every tenant)
+func (h *RentAdjustmentHandler) RegisterAdminRoutes(router *gin.RouterGroup) {
+	router.POST("/indices/import", h.ImportIndex)
+}
+
+// DismissRentAdjustmentRequest represents the request body for discarding a rent adjustment
+type DismissRentAdjustmentRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// ImportIndex imports monthly index variations
+// @Summary Import index series
+// @Description Import monthly variations of IGP-M/IPCA/INPC from a CSV (period;rate or index;period;rate), as the "file" form field or the raw body
+// @Tags rent-adjustments
+// @Accept text/csv
+// @Accept multipart/form-data
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param index query string false "Index of period;rate files (igpm, ipca, inpc)"
+// @Param file formData file false "CSV file"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/{tenant_id}/indices/import [post]
+func (h *RentAdjustmentHandler) ImportIndex(c *gin.Context) {
+	var reader io.Reader = c.Request.Body
+	if file, _, err := c.Request.FormFile("file"); err == nil {
+		defer file.Close()
+		reader = file
+	}
+
+	data, err := io.ReadAll(io.LimitReader(reader, maxIndexCSVSize+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "failed to read file",
+		})
+		return
+	}
+	if len(data) > maxIndexCSVSize {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "file size exceeds 1MB limit",
+		})
+		return
+	}
+
+	response, err := h.adjustmentService.ImportIndexCSV(c.Request.Context(), models.IndexationType(c.Query("index")), data)
+	if err != nil {
+		h.respondAdjustmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    response,
+	})
+}
+
+// ListIndex lists the stored months of an index
+// @Summary List index series
+// @Tags rent-adjustments
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param index path string true "Index (igpm, ipca, inpc)"
+// @Param from query string false "First month (YYYY-MM)"
+// @Param to query string false "Last month (YYYY-MM)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/{tenant_id}/indices/{index} [get]
+func (h *RentAdjustmentHandler) ListIndex(c *gin.Context) {
+	rates, err := h.adjustmentService.ListIndex(c.Request.Context(), models.IndexationType(c.Param("index")), c.Query("from"), c.Query("to"))
+	if err != nil {
+		h.respondAdjustmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    rates,
+		"count":   len(rates),
+	})
+}
+
+// Calculate accumulates an index for an adjustment month
+// @Summary Calculate index variation
+// @Description Accumulated variation of the 12 months before the adjustment month
+// @Tags rent-adjustments
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param index query string true "Index (igpm, ipca, inpc)"
+// @Param period query string true "Adjustment month (YYYY-MM)"
+// @Param rent query number false "Rent to adjust"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/{tenant_id}/rent-adjustments/calculate [get]
+func (h *RentAdjustmentHandler) Calculate(c *gin.Context) {
+	effective, err := time.Parse(models.PeriodLayout, c.Query("period"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "period must be YYYY-MM",
+		})
+		return
+	}
+
+	calculation, err := h.adjustmentService.Calculate(c.Request.Context(), models.IndexationType(c.Query("index")), effective)
+	if err != nil {
+		h.respondAdjustmentError(c, err)
+		return
+	}
+
+	data := gin.H{"calculation": calculation}
+	if rent := c.Query("rent"); rent != "" {
+		value, err := strconv.ParseFloat(rent, 64)
+		if err != nil || value <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "rent must be a positive number",
+			})
+			return
+		}
+		data["current_rent"] = value
+		data["adjusted_rent"] = models.AdjustedRent(value, calculation.Variation)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    data,
+	})
+}
+
+// UpcomingAdjustments reports the adjustments due in the coming months
+// @Summary Upcoming rent adjustments
+// @Description Adjustments due from the current month, by month and tenant; estimated while the index months are not published
+// @Tags rent-adjustments
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param months query int false "Months ahead (max 12)" default(3)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/{tenant_id}/rent-adjustments/upcoming [get]
+func (h *RentAdjustmentHandler) UpcomingAdjustments(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+
+	months := 0
+	if value := c.Query("months"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "months must be a positive integer",
+			})
+			return
+		}
+		months = parsed
+	}
+
+	upcoming, err := h.adjustmentService.UpcomingAdjustments(c.Request.Context(), tenantID, months, time.Now())
+	if err != nil {
+		h.respondAdjustmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    upcoming,
+		"count":   len(upcoming),
+	})
+}
+
+// ListAdjustments lists rent adjustments
+// @Summary List rent adjustments
+// @Tags rent-adjustments
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param contract_id query string false "Contract ID filter"
+// @Param property_id query string false "Property ID filter"
+// @Param period query string false "Adjustment month filter (YYYY-MM)"
+// @Param status query string false "Status filter (proposed, applied, dismissed)"
+// @Param limit query int false "Limit" default(50)
+// @Success 200 {object} map[string]interface{}
+// @Router /api/{tenant_id}/rent-adjustments [get]
+func (h *RentAdjustmentHandler) ListAdjustments(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+
+	filters := &repositories.RentAdjustmentFilters{
+		ContractID:      c.Query("contract_id"),
+		PropertyID:      c.Query("property_id"),
+		EffectivePeriod: c.Query("period"),
+	}
+	if status := c.Query("status"); status != "" {
+		filters.Statuses = []models.RentAdjustmentStatus{models.RentAdjustmentStatus(status)}
+	}
+
+	adjustments, err := h.adjustmentService.ListAdjustments(c.Request.Context(), tenantID, filters, parsePaginationOptions(c))
+	if err != nil {
+		h.respondAdjustmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    adjustments,
+		"count":   len(adjustments),
+	})
+}
+
+// GetAdjustment retrieves a rent adjustment by ID
+// @Summary Get rent adjustment
+// @Tags rent-adjustments
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Adjustment ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/rent-adjustments/{id} [get]
+func (h *RentAdjustmentHandler) GetAdjustment(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	adjustment, err := h.adjustmentService.GetAdjustment(c.Request.Context(), tenantID, id)
+	if err != nil {
+		h.respondAdjustmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    adjustment,
+	})
+}
+
+// ApplyAdjustment sets the proposed rent on the contract
+// @Summary Apply rent adjustment
+// @Tags rent-adjustments
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Adjustment ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/rent-adjustments/{id}/apply [post]
+func (h *RentAdjustmentHandler) ApplyAdjustment(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	adjustment, err := h.adjustmentService.ApplyAdjustment(c.Request.Context(), tenantID, id, actorID(c))
+	if err != nil {
+		h.respondAdjustmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    adjustment,
+	})
+}
+
+// DismissAdjustment discards a rent adjustment
+// @Summary Dismiss rent adjustment
+// @Tags rent-adjustments
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Adjustment ID"
+// @Param body body DismissRentAdjustmentRequest true "Reason"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/rent-adjustments/{id}/dismiss [post]
+func (h *RentAdjustmentHandler) DismissAdjustment(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	var req DismissRentAdjustmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	adjustment, err := h.adjustmentService.DismissAdjustment(c.Request.Context(), tenantID, id, req.Reason, actorID(c))
+	if err != nil {
+		h.respondAdjustmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    adjustment,
+	})
+}
+
+// respondAdjustmentError maps rent adjustment errors to HTTP status codes
+func (h *RentAdjustmentHandler) respondAdjustmentError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, repositories.ErrInvalidInput):
+		status = http.StatusBadRequest
+	}
+
+	c.JSON(status, gin.H{
+		"success": false,
+		"error":   err.Error(),
+	})
+}
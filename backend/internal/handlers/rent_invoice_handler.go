@@ -0,0 +1,343 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// RentInvoiceHandler handles rent billing HTTP requests
+type RentInvoiceHandler struct {
+	billingService *services.RentBillingService
+}
+
+// NewRentInvoiceHandler creates a new rent invoice handler
+func NewRentInvoiceHandler(billingService *services.RentBillingService) *RentInvoiceHandler {
+	return &RentInvoiceHandler{
+		billingService: billingService,
+	}
+}
+
+// RegisterRoutes registers rent billing routes (tenant-scoped)
+func (h *RentInvoiceHandler) RegisterRoutes(router *gin.RouterGroup) {
+	invoices := router.Group("/rent-invoices")
+	{
+		invoices.GET("", h.ListInvoices)
+		invoices.POST("/generate", h.GenerateInvoices)
+		invoices.GET("/delinquency", h.GetDelinquencyReport)
+		invoices.GET("/owner-statement", h.GetOwnerStatement)
+		invoices.GET("/:id", h.GetInvoice)
+		invoices.POST("/:id/payments", h.RegisterPayment)
+		invoices.POST("/:id/cancel", h.CancelInvoice)
+	}
+}
+
+// GenerateRentInvoicesRequest represents the request body for issuing the invoices of a month
+type GenerateRentInvoicesRequest struct {
+	Period string `json:"period"` // YYYY-MM, default current month
+}
+
+// RegisterRentPaymentRequest represents the request body for registering a payment
+type RegisterRentPaymentRequest struct {
+	Amount float64    `json:"amount" binding:"required"`
+	PaidAt *time.Time `json:"paid_at"` // default: now
+	Note   string     `json:"note"`
+}
+
+// CancelRentInvoiceRequest represents the request body for cancelling an invoice
+type CancelRentInvoiceRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// ListInvoices lists rent invoices
+// @Summary List rent invoices
+// @Description Rent invoices with filters, latest due date first (oldest first with overdue=true)
+// @Tags rent-invoices
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param contract_id query string false "Contract ID filter"
+// @Param property_id query string false "Property ID filter"
+// @Param owner_id query string false "Owner ID filter"
+// @Param period query string false "Month charged (YYYY-MM)"
+// @Param status query string false "Status filter (open, paid, cancelled)"
+// @Param overdue query bool false "Only open invoices past their due date"
+// @Param limit query int false "Limit" default(50)
+// @Success 200 {object} map[string]interface{}
+// @Router /api/{tenant_id}/rent-invoices [get]
+func (h *RentInvoiceHandler) ListInvoices(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+
+	filters := &repositories.RentInvoiceFilters{
+		ContractID: c.Query("contract_id"),
+		PropertyID: c.Query("property_id"),
+		OwnerID:    c.Query("owner_id"),
+		Period:     c.Query("period"),
+	}
+	if status := c.Query("status"); status != "" {
+		filters.Statuses = []models.RentInvoiceStatus{models.RentInvoiceStatus(status)}
+	}
+	if c.Query("overdue") == "true" {
+		now := time.Now()
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		filters.Statuses = []models.RentInvoiceStatus{models.RentInvoiceStatusOpen}
+		filters.DueBefore = &today
+	}
+
+	invoices, err := h.billingService.ListInvoices(c.Request.Context(), tenantID, filters, parsePaginationOptions(c))
+	if err != nil {
+		h.respondInvoiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    invoices,
+		"count":   len(invoices),
+	})
+}
+
+// GetInvoice retrieves a rent invoice by ID
+// @Summary Get rent invoice
+// @Description Invoice with the amount due today (late fee and interest included)
+// @Tags rent-invoices
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Invoice ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/rent-invoices/{id} [get]
+func (h *RentInvoiceHandler) GetInvoice(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	invoice, err := h.billingService.GetInvoice(c.Request.Context(), tenantID, id)
+	if err != nil {
+		h.respondInvoiceError(c, err)
+		return
+	}
+
+	now := time.Now()
+	lateFee, interest := invoice.Charges(now)
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    invoice,
+		"amount_due": gin.H{
+			"outstanding":  invoice.Outstanding(),
+			"late_fee":     lateFee,
+			"interest":     interest,
+			"total":        invoice.AmountDue(now),
+			"days_overdue": invoice.DaysOverdue(now),
+		},
+	})
+}
+
+// GenerateInvoices issues the invoices of a month
+// @Summary Generate rent invoices
+// @Description Issue the invoice of the month for every active contract (already invoiced contracts are skipped)
+// @Tags rent-invoices
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param body body GenerateRentInvoicesRequest false "Month"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/{tenant_id}/rent-invoices/generate [post]
+func (h *RentInvoiceHandler) GenerateInvoices(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+
+	var req GenerateRentInvoicesRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+	}
+	if req.Period == "" {
+		req.Period = time.Now().Format(models.PeriodLayout)
+	}
+
+	response, err := h.billingService.GenerateInvoices(c.Request.Context(), tenantID, req.Period)
+	if err != nil {
+		h.respondInvoiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    response,
+	})
+}
+
+// RegisterPayment registers a payment received by the agency
+// @Summary Register rent payment
+// @Description Record a payment (PIX, transfer, cash); late payments settle the late fee and interest in proportion
+// @Tags rent-invoices
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Invoice ID"
+// @Param body body RegisterRentPaymentRequest true "Payment"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/rent-invoices/{id}/payments [post]
+func (h *RentInvoiceHandler) RegisterPayment(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	var req RegisterRentPaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	payment := services.RegisterPaymentRequest{Amount: req.Amount, Note: req.Note}
+	if req.PaidAt != nil {
+		payment.PaidAt = *req.PaidAt
+	}
+
+	invoice, err := h.billingService.RegisterPayment(c.Request.Context(), tenantID, id, payment, actorID(c))
+	if err != nil {
+		h.respondInvoiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    invoice,
+	})
+}
+
+// CancelInvoice cancels an invoice without payments
+// @Summary Cancel rent invoice
+// @Tags rent-invoices
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Invoice ID"
+// @Param body body CancelRentInvoiceRequest true "Reason"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/rent-invoices/{id}/cancel [post]
+func (h *RentInvoiceHandler) CancelInvoice(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	var req CancelRentInvoiceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	invoice, err := h.billingService.CancelInvoice(c.Request.Context(), tenantID, id, req.Reason, actorID(c))
+	if err != nil {
+		h.respondInvoiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    invoice,
+	})
+}
+
+// GetDelinquencyReport reports the overdue rent
+// @Summary Delinquency report
+// @Description Open invoices past their due date by contract, longest overdue first, with the late fees and interest owed today and an aging summary
+// @Tags rent-invoices
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/{tenant_id}/rent-invoices/delinquency [get]
+func (h *RentInvoiceHandler) GetDelinquencyReport(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+
+	report, err := h.billingService.DelinquencyReport(c.Request.Context(), tenantID, time.Now())
+	if err != nil {
+		h.respondInvoiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    report,
+	})
+}
+
+// GetOwnerStatement returns the owner payouts of a month
+// @Summary Owner payout statement
+// @Description Rent received in a month per owner, net of the administration fee (repasse); format=csv exports it for spreadsheets
+// @Tags rent-invoices
+// @Produce json
+// @Produce text/csv
+// @Param tenant_id path string true "Tenant ID"
+// @Param period query string false "Month of the payments (YYYY-MM, default current month)"
+// @Param owner_id query string false "Owner ID"
+// @Param format query string false "json (default) or csv"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/{tenant_id}/rent-invoices/owner-statement [get]
+func (h *RentInvoiceHandler) GetOwnerStatement(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	period := c.DefaultQuery("period", time.Now().Format(models.PeriodLayout))
+	ownerID := c.Query("owner_id")
+
+	statement, err := h.billingService.OwnerStatement(c.Request.Context(), tenantID, period, ownerID)
+	if err != nil {
+		h.respondInvoiceError(c, err)
+		return
+	}
+
+	if c.Query("format") != "csv" {
+		c.JSON(http.StatusOK, gin.H{
+			"success": true,
+			"data":    statement,
+		})
+		return
+	}
+
+	data, err := h.billingService.OwnerStatementCSV(statement)
+	if err != nil {
+		h.respondInvoiceError(c, err)
+		return
+	}
+
+	filename := fmt.Sprintf("repasses-%s.csv", period)
+	if ownerID != "" {
+		filename = fmt.Sprintf("repasses-%s-%s.csv", period, ownerID)
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", data)
+}
+
+// respondInvoiceError maps rent billing errors to HTTP status codes
+func (h *RentInvoiceHandler) respondInvoiceError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, repositories.ErrInvalidInput):
+		status = http.StatusBadRequest
+	}
+
+	c.JSON(status, gin.H{
+		"success": false,
+		"error":   err.Error(),
+	})
+}
@@ -0,0 +1,311 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// RentalContractHandler handles rental contract HTTP requests
+type RentalContractHandler struct {
+	contractService *services.RentalContractService
+}
+
+// NewRentalContractHandler creates a new rental contract handler
+func NewRentalContractHandler(contractService *services.RentalContractService) *RentalContractHandler {
+	return &RentalContractHandler{
+		contractService: contractService,
+	}
+}
+
+// RegisterRoutes registers rental contract routes (tenant-scoped)
+func (h *RentalContractHandler) RegisterRoutes(router *gin.RouterGroup) {
+	contracts := router.Group("/rental-contracts")
+	{
+		contracts.GET("", h.ListContracts)
+		contracts.POST("", h.CreateContract)
+		contracts.GET("/:id", h.GetContract)
+		contracts.POST("/:id/renew", h.RenewContract)
+		contracts.POST("/:id/terminate", h.TerminateContract)
+	}
+}
+
+// CreateRentalContractRequest represents the request body for registering a rental contract
+type CreateRentalContractRequest struct {
+	DealID           string               `json:"deal_id"`     // Closed rental deal; defaults property, lead, start and rent
+	PropertyID       string               `json:"property_id"` // required without deal_id
+	Tenant           models.RentalTenant  `json:"tenant"`      // name defaults to the lead's
+	GuaranteeType    models.GuaranteeType `json:"guarantee_type" binding:"required"`
+	GuaranteeDetails string               `json:"guarantee_details"`
+	DepositAmount    float64              `json:"deposit_amount"` // caucao: default RentalInfo.DepositMonths x rent
+	StartDate        *time.Time           `json:"start_date"`
+	EndDate          time.Time            `json:"end_date" binding:"required"`
+
+	// Monthly values, indexation and adjustment month default to the property's RentalInfo
+	MonthlyRent     float64               `json:"monthly_rent"`
+	CondoFee        float64               `json:"condo_fee"`
+	IPTUMonthly     float64               `json:"iptu_monthly"`
+	IndexationType  models.IndexationType `json:"indexation_type"`
+	AdjustmentMonth int                   `json:"adjustment_month"`
+
+	// Billing terms (default: the start day, 10% late fee, 1% a month interest, 10% administration fee)
+	DueDay              int     `json:"due_day"`
+	LateFeePercentage   float64 `json:"late_fee_percentage"`
+	MonthlyInterestRate float64 `json:"monthly_interest_rate"`
+	AdminFeePercentage  float64 `json:"admin_fee_percentage"`
+
+	Notes string `json:"notes"`
+}
+
+// RenewRentalContractRequest represents the request body for extending a rental contract
+type RenewRentalContractRequest struct {
+	EndDate     time.Time `json:"end_date" binding:"required"`
+	MonthlyRent float64   `json:"monthly_rent"` // 0 keeps the current rent
+	Note        string    `json:"note"`
+}
+
+// TerminateRentalContractRequest represents the request body for ending a rental contract early
+type TerminateRentalContractRequest struct {
+	MoveOutDate *time.Time `json:"move_out_date"` // default: today; a future date schedules the termination
+	Reason      string     `json:"reason" binding:"required"`
+}
+
+// ListContracts lists rental contracts
+// @Summary List rental contracts
+// @Description Rental contracts with filters, latest start first (soonest end first with ending_within_days)
+// @Tags rental-contracts
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param property_id query string false "Property ID filter"
+// @Param owner_id query string false "Owner ID filter"
+// @Param status query string false "Status filter (active, ended, terminated)"
+// @Param ending_within_days query int false "Only contracts ending within the next N days"
+// @Param limit query int false "Limit" default(50)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/{tenant_id}/rental-contracts [get]
+func (h *RentalContractHandler) ListContracts(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+
+	filters := &repositories.RentalContractFilters{
+		PropertyID: c.Query("property_id"),
+		OwnerID:    c.Query("owner_id"),
+	}
+	if status := c.Query("status"); status != "" {
+		filters.Statuses = []models.RentalContractStatus{models.RentalContractStatus(status)}
+	}
+	if within := c.Query("ending_within_days"); within != "" {
+		days, err := strconv.Atoi(within)
+		if err != nil || days < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "ending_within_days must be a non-negative integer",
+			})
+			return
+		}
+		endsBefore := time.Now().AddDate(0, 0, days)
+		filters.EndsBefore = &endsBefore
+	}
+
+	contracts, err := h.contractService.ListContracts(c.Request.Context(), tenantID, filters, parsePaginationOptions(c))
+	if err != nil {
+		h.respondContractError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    contracts,
+		"count":   len(contracts),
+	})
+}
+
+// GetContract retrieves a rental contract by ID
+// @Summary Get rental contract
+// @Tags rental-contracts
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Contract ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/rental-contracts/{id} [get]
+func (h *RentalContractHandler) GetContract(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	contract, err := h.contractService.GetContract(c.Request.Context(), tenantID, id)
+	if err != nil {
+		h.respondContractError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    contract,
+	})
+}
+
+// CreateContract registers a rental contract
+// @Summary Create rental contract
+// @Description Register the lease of a property; it becomes the property's current contract and the property leaves the market as rented
+// @Tags rental-contracts
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param body body CreateRentalContractRequest true "Contract"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/rental-contracts [post]
+func (h *RentalContractHandler) CreateContract(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+
+	var req CreateRentalContractRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	contract := &models.RentalContract{
+		TenantID:            tenantID,
+		DealID:              req.DealID,
+		PropertyID:          req.PropertyID,
+		Tenant:              req.Tenant,
+		GuaranteeType:       req.GuaranteeType,
+		GuaranteeDetails:    req.GuaranteeDetails,
+		DepositAmount:       req.DepositAmount,
+		EndDate:             req.EndDate,
+		MonthlyRent:         req.MonthlyRent,
+		CondoFee:            req.CondoFee,
+		IPTUMonthly:         req.IPTUMonthly,
+		IndexationType:      req.IndexationType,
+		AdjustmentMonth:     req.AdjustmentMonth,
+		DueDay:              req.DueDay,
+		LateFeePercentage:   req.LateFeePercentage,
+		MonthlyInterestRate: req.MonthlyInterestRate,
+		AdminFeePercentage:  req.AdminFeePercentage,
+		Notes:               req.Notes,
+	}
+	if req.StartDate != nil {
+		contract.StartDate = *req.StartDate
+	}
+
+	if err := h.contractService.CreateContract(c.Request.Context(), contract, actorID(c)); err != nil {
+		h.respondContractError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    contract,
+	})
+}
+
+// RenewContract extends a rental contract
+// @Summary Renew rental contract
+// @Tags rental-contracts
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Contract ID"
+// @Param body body RenewRentalContractRequest true "Renewal"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/rental-contracts/{id}/renew [post]
+func (h *RentalContractHandler) RenewContract(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	var req RenewRentalContractRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	contract, err := h.contractService.RenewContract(c.Request.Context(), tenantID, id, services.RenewContractRequest{
+		EndDate:     req.EndDate,
+		MonthlyRent: req.MonthlyRent,
+		Note:        req.Note,
+	}, actorID(c))
+	if err != nil {
+		h.respondContractError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    contract,
+	})
+}
+
+// TerminateContract ends a rental contract early
+// @Summary Terminate rental contract
+// @Description End a contract early (rescisão); the property goes back to the market on the move-out date
+// @Tags rental-contracts
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Contract ID"
+// @Param body body TerminateRentalContractRequest true "Termination"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/rental-contracts/{id}/terminate [post]
+func (h *RentalContractHandler) TerminateContract(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	var req TerminateRentalContractRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	var moveOut time.Time
+	if req.MoveOutDate != nil {
+		moveOut = *req.MoveOutDate
+	}
+
+	contract, err := h.contractService.TerminateContract(c.Request.Context(), tenantID, id, moveOut, req.Reason, actorID(c))
+	if err != nil {
+		h.respondContractError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    contract,
+	})
+}
+
+// respondContractError maps rental contract errors to HTTP status codes
+func (h *RentalContractHandler) respondContractError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, repositories.ErrInvalidInput):
+		status = http.StatusBadRequest
+	}
+
+	c.JSON(status, gin.H{
+		"success": false,
+		"error":   err.Error(),
+	})
+}
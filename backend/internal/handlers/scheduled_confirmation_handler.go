@@ -1,12 +1,14 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
 	"github.com/altatech/ecosistema-imob/backend/internal/services"
 )
 
@@ -97,6 +99,52 @@ func (h *ScheduledConfirmationHandler) ProcessPendingConfirmations(c *gin.Contex
 	})
 }
 
+// ProcessReminders runs the due follow-up steps of unanswered confirmations
+// POST /api/v1/admin/:tenant_id/scheduled-confirmations/reminders/process
+func (h *ScheduledConfirmationHandler) ProcessReminders(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+
+	result, err := h.scheduler.ProcessReminders(c.Request.Context(), tenantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
+
+// GetPropertyConfirmationStatus shows which reminder step the latest
+// confirmation of a property is on
+// GET /api/v1/admin/:tenant_id/scheduled-confirmations/property/:property_id
+func (h *ScheduledConfirmationHandler) GetPropertyConfirmationStatus(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	propertyID := c.Param("property_id")
+
+	status, err := h.scheduler.GetPropertyConfirmationStatus(c.Request.Context(), tenantID, propertyID)
+	if err != nil {
+		code := http.StatusInternalServerError
+		if errors.Is(err, repositories.ErrNotFound) {
+			code = http.StatusNotFound
+		}
+		c.JSON(code, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    status,
+	})
+}
+
 // GetScheduledConfirmations gets all scheduled confirmations for a tenant
 // GET /api/v1/admin/:tenant_id/scheduled-confirmations
 func (h *ScheduledConfirmationHandler) GetScheduledConfirmations(c *gin.Context) {
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/altatech/ecosistema-imob/backend/internal/models"
@@ -152,6 +153,90 @@ func (h *TenantHandler) UpdateTenant(c *gin.Context) {
 	})
 }
 
+// GetTenantPolicy returns the staleness and confirmation policy of the tenant
+// @Summary Get tenant policy
+// @Description Get the staleness and owner confirmation policy of the tenant (defaults applied)
+// @Tags tenants
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/admin/{tenant_id}/policy [get]
+func (h *TenantHandler) GetTenantPolicy(c *gin.Context) {
+	policy, err := h.tenantService.GetTenantPolicy(c.Request.Context(), c.Param("tenant_id"))
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "tenant not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    policy,
+	})
+}
+
+// UpdateTenantPolicy replaces the staleness and confirmation policy of the tenant
+// @Summary Update tenant policy
+// @Description Update the staleness and owner confirmation policy of the tenant; omitted fields take the default values
+// @Tags tenants
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param policy body models.TenantPolicy true "Tenant policy"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/admin/{tenant_id}/policy [put]
+func (h *TenantHandler) UpdateTenantPolicy(c *gin.Context) {
+	var policy models.TenantPolicy
+	if err := c.ShouldBindJSON(&policy); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	updated, err := h.tenantService.UpdateTenantPolicy(c.Request.Context(), c.Param("tenant_id"), policy)
+	if err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrInvalidInput):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+		case errors.Is(err, repositories.ErrNotFound):
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"error":   "tenant not found",
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    updated,
+	})
+}
+
 // DeleteTenant deletes a tenant
 // @Summary Delete tenant
 // @Description Delete a tenant
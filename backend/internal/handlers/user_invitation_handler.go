@@ -110,6 +110,16 @@ func (h *UserInvitationHandler) InviteUser(c *gin.Context) {
 		return
 	}
 
+	// Validate explicit permissions against the catalog
+	for _, permission := range req.Permissions {
+		if !models.IsValidPermission(permission) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("Invalid permission: %s", permission),
+			})
+			return
+		}
+	}
+
 	// Validate CRECI for broker roles
 	if req.Role == "broker" || req.Role == "broker_admin" {
 		if req.CRECI == "" {
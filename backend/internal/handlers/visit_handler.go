@@ -0,0 +1,664 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/ical"
+	"github.com/altatech/ecosistema-imob/backend/internal/middleware"
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// VisitHandler handles property visit and broker availability HTTP requests
+type VisitHandler struct {
+	visitService *services.VisitService
+}
+
+// NewVisitHandler creates a new visit handler
+func NewVisitHandler(visitService *services.VisitService) *VisitHandler {
+	return &VisitHandler{
+		visitService: visitService,
+	}
+}
+
+// RegisterRoutes registers visit and broker availability routes (tenant-scoped)
+func (h *VisitHandler) RegisterRoutes(router *gin.RouterGroup) {
+	visits := router.Group("/visits")
+	{
+		visits.GET("", h.ListVisits)
+		visits.POST("", h.ScheduleVisit)
+		visits.GET("/:id", h.GetVisit)
+		visits.POST("/:id/confirm", h.ConfirmVisit)
+		visits.POST("/:id/cancel", h.CancelVisit)
+		visits.POST("/:id/complete", h.CompleteVisit)
+	}
+
+	router.GET("/brokers/:id/availability", h.GetBrokerAvailability)
+	router.PUT("/brokers/:id/availability", h.SetBrokerAvailability)
+	router.POST("/brokers/:id/calendar-token", h.RotateCalendarToken)
+}
+
+// RegisterPublicRoutes registers the visit routes of the public site (no authentication)
+func (h *VisitHandler) RegisterPublicRoutes(public *gin.RouterGroup) {
+	public.GET("/properties/:id/visit-slots", h.GetVisitSlots)
+	public.POST("/properties/:property_id/visits", h.RequestVisit)
+	public.POST("/visits/:id/cancel", h.CancelVisitByLead)
+	public.GET("/brokers/:id/calendar.ics", h.GetBrokerCalendar)
+}
+
+// RequestVisitRequest represents the request body for requesting a visit from the property page
+type RequestVisitRequest struct {
+	Name         string    `json:"name" binding:"required"`
+	Email        string    `json:"email"`
+	Phone        string    `json:"phone"`
+	Message      string    `json:"message,omitempty"`
+	StartAt      time.Time `json:"start_at" binding:"required"` // One of the visit-slots
+	ConsentGiven bool      `json:"consent_given" binding:"required"`
+	ConsentText  string    `json:"consent_text" binding:"required"`
+	UTMSource    string    `json:"utm_source,omitempty"`
+	UTMCampaign  string    `json:"utm_campaign,omitempty"`
+	UTMMedium    string    `json:"utm_medium,omitempty"`
+	Referrer     string    `json:"referrer,omitempty"`
+}
+
+// ScheduleVisitRequest represents the request body for scheduling a visit from the dashboard
+type ScheduleVisitRequest struct {
+	LeadID     string     `json:"lead_id" binding:"required"`
+	PropertyID string     `json:"property_id"` // default: property of the lead
+	BrokerID   string     `json:"broker_id"`   // default: broker of the lead
+	StartAt    time.Time  `json:"start_at" binding:"required"`
+	EndAt      *time.Time `json:"end_at"` // default: start_at + visit length of the broker
+	Notes      string     `json:"notes"`
+}
+
+// CancelVisitRequest represents the request body for cancelling a visit
+type CancelVisitRequest struct {
+	Reason string `json:"reason"`
+}
+
+// CancelVisitByLeadRequest represents the request body for cancelling a visit from the lead's link
+type CancelVisitByLeadRequest struct {
+	Token  string `json:"token" binding:"required"`
+	Reason string `json:"reason"`
+}
+
+// CompleteVisitRequest represents the request body for recording the outcome of a visit
+type CompleteVisitRequest struct {
+	Status models.VisitStatus `json:"status"` // completed (default) or no_show
+}
+
+// GetVisitSlots lists the free visit slots of a property
+// @Summary Visit slots of a property
+// @Description Free slots of the broker that shows the property, in the broker's time zone
+// @Tags visits
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Property ID"
+// @Param from query string false "First day (RFC 3339 or YYYY-MM-DD, default today)"
+// @Param days query int false "Number of days (default 14, max 31)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/properties/{id}/visit-slots [get]
+func (h *VisitHandler) GetVisitSlots(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	propertyID := c.Param("id")
+
+	from := time.Now()
+	if value := c.Query("from"); value != "" {
+		parsed, err := parseVisitTime(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "from must be RFC 3339 or YYYY-MM-DD",
+			})
+			return
+		}
+		from = parsed
+	}
+	days, _ := strconv.Atoi(c.Query("days"))
+
+	slots, err := h.visitService.AvailableSlots(c.Request.Context(), tenantID, propertyID, from, days)
+	if err != nil {
+		h.respondVisitError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    slots,
+	})
+}
+
+// RequestVisit books a visit from the property page
+// @Summary Request a visit
+// @Description Create a lead and a requested visit for a free slot of the property. LGPD consent required.
+// @Description The returned cancel_token lets the lead cancel the visit.
+// @Tags visits
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param property_id path string true "Property ID"
+// @Param body body RequestVisitRequest true "Visit request"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/{tenant_id}/properties/{property_id}/visits [post]
+func (h *VisitHandler) RequestVisit(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	propertyID := c.Param("property_id")
+
+	var req RequestVisitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	// LGPD validation: consent is mandatory
+	if !req.ConsentGiven {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "consent_given must be true (LGPD compliance)",
+		})
+		return
+	}
+
+	lead := &models.Lead{
+		TenantID:     tenantID,
+		PropertyID:   propertyID,
+		Name:         req.Name,
+		Email:        req.Email,
+		Phone:        req.Phone,
+		Message:      req.Message,
+		Channel:      models.LeadChannelForm,
+		ConsentGiven: req.ConsentGiven,
+		ConsentText:  req.ConsentText,
+		ConsentIP:    c.ClientIP(),
+		UTMSource:    req.UTMSource,
+		UTMCampaign:  req.UTMCampaign,
+		UTMMedium:    req.UTMMedium,
+		Referrer:     req.Referrer,
+	}
+
+	visit, token, err := h.visitService.RequestVisit(c.Request.Context(), lead, req.StartAt)
+	if err != nil {
+		h.respondVisitError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success":      true,
+		"data":         visit,
+		"lead_id":      lead.ID,
+		"cancel_token": token,
+		"message":      "Pedido de visita enviado. O corretor confirmará em breve.",
+	})
+}
+
+// CancelVisitByLead cancels a visit from the lead's link
+// @Summary Cancel a visit (lead)
+// @Tags visits
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Visit ID"
+// @Param body body CancelVisitByLeadRequest true "Cancel token"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/visits/{id}/cancel [post]
+func (h *VisitHandler) CancelVisitByLead(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	var req CancelVisitByLeadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	visit, err := h.visitService.CancelVisitByLead(c.Request.Context(), tenantID, id, req.Token, req.Reason)
+	if err != nil {
+		h.respondVisitError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    visit,
+	})
+}
+
+// GetBrokerCalendar returns the iCalendar feed of a broker's visits
+// @Summary Broker visit calendar (.ics)
+// @Description Subscribable feed of the broker's visits; the token comes from calendar-token
+// @Tags visits
+// @Produce text/calendar
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Broker ID"
+// @Param token query string true "Calendar token"
+// @Success 200 {string} string
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/brokers/{id}/calendar.ics [get]
+func (h *VisitHandler) GetBrokerCalendar(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	calendar, err := h.visitService.BrokerCalendar(c.Request.Context(), tenantID, id, c.Query("token"))
+	if err != nil {
+		h.respondVisitError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Data(http.StatusOK, ical.ContentType, calendar)
+}
+
+// ListVisits lists the visits of a tenant
+// @Summary List visits
+// @Description Visits with filters, earliest first
+// @Tags visits
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param broker_id query string false "Broker ID filter"
+// @Param property_id query string false "Property ID filter"
+// @Param lead_id query string false "Lead ID filter"
+// @Param status query string false "Status filter (requested, confirmed, cancelled, completed, no_show)"
+// @Param from query string false "Visits starting at or after (RFC 3339 or YYYY-MM-DD)"
+// @Param until query string false "Visits starting before (RFC 3339 or YYYY-MM-DD)"
+// @Param limit query int false "Limit" default(50)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/{tenant_id}/visits [get]
+func (h *VisitHandler) ListVisits(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+
+	filters := &repositories.VisitFilters{
+		BrokerID:   c.Query("broker_id"),
+		PropertyID: c.Query("property_id"),
+		LeadID:     c.Query("lead_id"),
+	}
+	if status := c.Query("status"); status != "" {
+		filters.Statuses = []models.VisitStatus{models.VisitStatus(status)}
+	}
+	if value := c.Query("from"); value != "" {
+		from, err := parseVisitTime(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "from must be RFC 3339 or YYYY-MM-DD",
+			})
+			return
+		}
+		filters.StartFrom = &from
+	}
+	if value := c.Query("until"); value != "" {
+		until, err := parseVisitTime(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "until must be RFC 3339 or YYYY-MM-DD",
+			})
+			return
+		}
+		filters.StartBefore = &until
+	}
+
+	opts := parsePaginationOptions(c)
+	opts.OrderBy = "" // Earliest first
+
+	visits, err := h.visitService.ListVisits(c.Request.Context(), tenantID, filters, opts)
+	if err != nil {
+		h.respondVisitError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    visits,
+		"count":   len(visits),
+	})
+}
+
+// GetVisit retrieves a visit by ID
+// @Summary Get visit
+// @Tags visits
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Visit ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/visits/{id} [get]
+func (h *VisitHandler) GetVisit(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	visit, err := h.visitService.GetVisit(c.Request.Context(), tenantID, id)
+	if err != nil {
+		h.respondVisitError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    visit,
+	})
+}
+
+// ScheduleVisit books a confirmed visit for a lead
+// @Summary Schedule visit
+// @Description Book a confirmed visit for a lead; the broker's time off and other visits are checked
+// @Tags visits
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param body body ScheduleVisitRequest true "Visit"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/{tenant_id}/visits [post]
+func (h *VisitHandler) ScheduleVisit(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+
+	var req ScheduleVisitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	visit := &models.Visit{
+		TenantID:   tenantID,
+		LeadID:     req.LeadID,
+		PropertyID: req.PropertyID,
+		BrokerID:   req.BrokerID,
+		StartAt:    req.StartAt,
+		Notes:      req.Notes,
+	}
+	if req.EndAt != nil {
+		visit.EndAt = *req.EndAt
+	}
+
+	token, err := h.visitService.ScheduleVisit(c.Request.Context(), visit, actorID(c))
+	if err != nil {
+		h.respondVisitError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success":      true,
+		"data":         visit,
+		"cancel_token": token,
+	})
+}
+
+// ConfirmVisit confirms a requested visit
+// @Summary Confirm visit
+// @Tags visits
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Visit ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/visits/{id}/confirm [post]
+func (h *VisitHandler) ConfirmVisit(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	visit, err := h.visitService.ConfirmVisit(c.Request.Context(), tenantID, id, actorID(c))
+	if err != nil {
+		h.respondVisitError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    visit,
+	})
+}
+
+// CancelVisit cancels a visit
+// @Summary Cancel visit
+// @Tags visits
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Visit ID"
+// @Param body body CancelVisitRequest false "Reason"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/visits/{id}/cancel [post]
+func (h *VisitHandler) CancelVisit(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	var req CancelVisitRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+	}
+
+	visit, err := h.visitService.CancelVisit(c.Request.Context(), tenantID, id, actorID(c), req.Reason)
+	if err != nil {
+		h.respondVisitError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    visit,
+	})
+}
+
+// CompleteVisit records the outcome of a confirmed visit
+// @Summary Complete visit
+// @Description Mark a confirmed visit as completed (logged on the lead) or no_show
+// @Tags visits
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Visit ID"
+// @Param body body CompleteVisitRequest false "Outcome"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/visits/{id}/complete [post]
+func (h *VisitHandler) CompleteVisit(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	var req CompleteVisitRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+	}
+	if req.Status == "" {
+		req.Status = models.VisitStatusCompleted
+	}
+
+	visit, err := h.visitService.CompleteVisit(c.Request.Context(), tenantID, id, req.Status, actorID(c))
+	if err != nil {
+		h.respondVisitError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    visit,
+	})
+}
+
+// GetBrokerAvailability returns the visit calendar of a broker
+// @Summary Get broker availability
+// @Tags visits
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Broker ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/brokers/{id}/availability [get]
+func (h *VisitHandler) GetBrokerAvailability(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	availability, err := h.visitService.GetBrokerAvailability(c.Request.Context(), tenantID, id)
+	if err != nil {
+		h.respondVisitError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    availability,
+	})
+}
+
+// SetBrokerAvailability replaces the visit calendar of a broker
+// @Summary Set broker availability
+// @Description Weekly hours, time off and visit length. Brokers may only change their own calendar.
+// @Tags visits
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Broker ID"
+// @Param body body models.BrokerAvailability true "Availability"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/brokers/{id}/availability [put]
+func (h *VisitHandler) SetBrokerAvailability(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	if !canManageCalendar(c, id) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   "brokers may only change their own availability",
+		})
+		return
+	}
+
+	var req models.BrokerAvailability
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	availability, err := h.visitService.SetBrokerAvailability(c.Request.Context(), tenantID, id, req, actorID(c))
+	if err != nil {
+		h.respondVisitError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    availability,
+	})
+}
+
+// RotateCalendarToken issues a new .ics feed URL for a broker
+// @Summary Rotate broker calendar token
+// @Description Issue a new secret for the broker's .ics feed; the previous URL stops working
+// @Tags visits
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param id path string true "Broker ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/{tenant_id}/brokers/{id}/calendar-token [post]
+func (h *VisitHandler) RotateCalendarToken(c *gin.Context) {
+	tenantID := c.Param("tenant_id")
+	id := c.Param("id")
+
+	if !canManageCalendar(c, id) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   "brokers may only manage their own calendar",
+		})
+		return
+	}
+
+	token, err := h.visitService.RotateCalendarToken(c.Request.Context(), tenantID, id, actorID(c))
+	if err != nil {
+		h.respondVisitError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"token": token,
+			"path":  "/api/v1/" + tenantID + "/brokers/" + id + "/calendar.ics?token=" + token,
+		},
+	})
+}
+
+// canManageCalendar checks if the member may change the calendar of a broker:
+// their own, or any with brokers.manage
+func canManageCalendar(c *gin.Context, brokerID string) bool {
+	member := middleware.GetMember(c)
+	if member == nil {
+		return true // No member resolved: route permissions are not enforced
+	}
+	return (member.Type == "broker" && member.ID == brokerID) || member.HasPermission(models.PermissionBrokersManage)
+}
+
+// parseVisitTime parses an RFC 3339 time or a YYYY-MM-DD date (midnight in
+// the default visit time zone)
+func parseVisitTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation("2006-01-02", value, models.DefaultBrokerAvailability().Location())
+}
+
+// respondVisitError maps visit errors to HTTP status codes
+func (h *VisitHandler) respondVisitError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrVisitConflict):
+		status = http.StatusConflict
+	case errors.Is(err, repositories.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, repositories.ErrInvalidInput):
+		status = http.StatusBadRequest
+	}
+
+	c.JSON(status, gin.H{
+		"success": false,
+		"error":   err.Error(),
+	})
+}
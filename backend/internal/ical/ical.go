@@ -0,0 +1,145 @@
+// Package ical writes iCalendar (RFC 5545) feeds that calendar apps
+// (Google Calendar, Outlook, Apple Calendar) can subscribe to, and reads the
+// feeds exported by booking platforms (Airbnb, Booking.com).
+package ical
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ContentType is the media type of iCalendar feeds
+const ContentType = "text/calendar; charset=utf-8"
+
+// Event statuses
+const (
+	StatusTentative = "TENTATIVE"
+	StatusConfirmed = "CONFIRMED"
+	StatusCancelled = "CANCELLED"
+)
+
+// maxLineOctets is the length at which content lines are folded
+const maxLineOctets = 75
+
+// Layouts of DATE and UTC DATE-TIME values
+const (
+	dateLayout     = "20060102"
+	dateTimeLayout = "20060102T150405Z"
+)
+
+// Calendar is a feed of events
+type Calendar struct {
+	ProdID string // ex: "-//Ecosistema Imob//Visitas//PT-BR"
+	Name   string // Shown by the calendar app (X-WR-CALNAME)
+	Events []Event
+}
+
+// Event is a calendar event (VEVENT)
+type Event struct {
+	UID          string // Stable across feed refreshes
+	Start        time.Time
+	End          time.Time
+	AllDay       bool // Start and End are dates (End exclusive), as in booking calendars
+	Summary      string
+	Description  string
+	Location     string
+	Status       string // StatusTentative, StatusConfirmed or StatusCancelled
+	Sequence     int    // Revision, increased when the event changes
+	LastModified time.Time
+}
+
+// Encode renders the calendar; dtstamp is the generation time of the feed
+func (c *Calendar) Encode(dtstamp time.Time) []byte {
+	var buf bytes.Buffer
+	w := &writer{buf: &buf}
+
+	w.line("BEGIN", "VCALENDAR")
+	w.line("VERSION", "2.0")
+	w.line("PRODID", c.ProdID)
+	w.line("CALSCALE", "GREGORIAN")
+	w.line("METHOD", "PUBLISH")
+	if c.Name != "" {
+		w.line("X-WR-CALNAME", escapeText(c.Name))
+	}
+
+	for _, event := range c.Events {
+		w.line("BEGIN", "VEVENT")
+		w.line("UID", event.UID)
+		w.line("DTSTAMP", formatTime(dtstamp))
+		if event.AllDay {
+			w.line("DTSTART;VALUE=DATE", formatDate(event.Start))
+			w.line("DTEND;VALUE=DATE", formatDate(event.End))
+		} else {
+			w.line("DTSTART", formatTime(event.Start))
+			w.line("DTEND", formatTime(event.End))
+		}
+		w.line("SUMMARY", escapeText(event.Summary))
+		if event.Description != "" {
+			w.line("DESCRIPTION", escapeText(event.Description))
+		}
+		if event.Location != "" {
+			w.line("LOCATION", escapeText(event.Location))
+		}
+		if event.Status != "" {
+			w.line("STATUS", event.Status)
+		}
+		if event.Sequence > 0 {
+			w.line("SEQUENCE", strconv.Itoa(event.Sequence))
+		}
+		if !event.LastModified.IsZero() {
+			w.line("LAST-MODIFIED", formatTime(event.LastModified))
+		}
+		w.line("END", "VEVENT")
+	}
+
+	w.line("END", "VCALENDAR")
+	return buf.Bytes()
+}
+
+// writer writes CRLF-terminated content lines
+type writer struct {
+	buf *bytes.Buffer
+}
+
+// line writes "NAME:value", folded at 75 octets without splitting UTF-8
+// characters (continuation lines start with a space)
+func (w *writer) line(name, value string) {
+	content := name + ":" + value
+	limit := maxLineOctets
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		w.buf.WriteString(content[:cut])
+		w.buf.WriteString("\r\n ")
+		content = content[cut:]
+		limit = maxLineOctets - 1
+	}
+	w.buf.WriteString(content)
+	w.buf.WriteString("\r\n")
+}
+
+// escapeText escapes a TEXT value
+func escapeText(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\n", `\n`,
+	).Replace(s)
+}
+
+// formatTime formats a UTC DATE-TIME value
+func formatTime(t time.Time) string {
+	return t.UTC().Format(dateTimeLayout)
+}
+
+// formatDate formats a DATE value (the calendar date of t in its location)
+func formatDate(t time.Time) string {
+	return t.Format(dateLayout)
+}
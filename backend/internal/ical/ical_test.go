@@ -0,0 +1,143 @@
+package ical
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestCalendarEncode(t *testing.T) {
+	start := time.Date(2024, 3, 12, 14, 0, 0, 0, time.FixedZone("BRT", -3*60*60))
+	calendar := &Calendar{
+		ProdID: "-//Ecosistema Imob//Visitas//PT-BR",
+		Name:   "Visitas, Ana",
+		Events: []Event{{
+			UID:         "visit-1@tenant-1",
+			Start:       start,
+			End:         start.Add(time.Hour),
+			Summary:     "Visita AP00335; Ana Costa",
+			Description: "Lead: Ana Costa\nTelefone: (11) 98765-4321",
+			Status:      StatusConfirmed,
+			Sequence:    2,
+		}},
+	}
+
+	got := string(calendar.Encode(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)))
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"X-WR-CALNAME:Visitas\\, Ana\r\n",
+		"UID:visit-1@tenant-1\r\n",
+		"DTSTAMP:20240301T120000Z\r\n",
+		"DTSTART:20240312T170000Z\r\n",
+		"DTEND:20240312T180000Z\r\n",
+		"SUMMARY:Visita AP00335\\; Ana Costa\r\n",
+		"DESCRIPTION:Lead: Ana Costa\\nTelefone: (11) 98765-4321\r\n",
+		"STATUS:CONFIRMED\r\n",
+		"SEQUENCE:2\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Encode() is missing %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestWriterFoldsLongLines(t *testing.T) {
+	var calendar Calendar
+	calendar.ProdID = "-//Test//EN"
+	calendar.Events = []Event{{UID: "1", Summary: strings.Repeat("Visita ao imóvel ", 10)}}
+
+	for _, line := range strings.Split(string(calendar.Encode(time.Now())), "\r\n") {
+		if len(line) > maxLineOctets {
+			t.Errorf("line has %d octets, expected at most %d: %q", len(line), maxLineOctets, line)
+		}
+		if !utf8.ValidString(line) {
+			t.Errorf("line splits a UTF-8 character: %q", line)
+		}
+	}
+
+	unfolded := strings.ReplaceAll(string(calendar.Encode(time.Now())), "\r\n ", "")
+	if !strings.Contains(unfolded, "SUMMARY:"+strings.Repeat("Visita ao imóvel ", 10)) {
+		t.Errorf("unfolded summary does not match:\n%s", unfolded)
+	}
+}
+
+func TestCalendarEncodeAllDay(t *testing.T) {
+	calendar := &Calendar{
+		ProdID: "-//Ecosistema Imob//Disponibilidade//PT-BR",
+		Events: []Event{{
+			UID:     "booking-1@tenant-1",
+			Start:   time.Date(2024, 12, 27, 0, 0, 0, 0, time.UTC),
+			End:     time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC),
+			AllDay:  true,
+			Summary: "Reservado",
+		}},
+	}
+
+	got := string(calendar.Encode(time.Now()))
+	for _, want := range []string{"DTSTART;VALUE=DATE:20241227\r\n", "DTEND;VALUE=DATE:20250103\r\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Encode() is missing %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	feed := "BEGIN:VCALENDAR\r\n" +
+		"PRODID:-//Airbnb Inc//Hosting Calendar 1.0//EN\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"DTSTART;VALUE=DATE:20241227\r\n" +
+		"DTEND;VALUE=DATE:20250103\r\n" +
+		"UID:1418fb94e984-2b2ba3f5@airbnb.com\r\n" +
+		"SUMMARY:Reserved\r\n" +
+		"DESCRIPTION:Reservation URL: https://www.airbnb.com/hosting/reservations/\r\n" +
+		" details/HMABCDEF\\nPhone Number (Last 4 Digits): 4321\r\n" +
+		"BEGIN:VALARM\r\n" +
+		"UID:alarm-1\r\n" +
+		"END:VALARM\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"DTSTART;TZID=America/Sao_Paulo:20250110T140000\r\n" +
+		"UID:owner-block\r\n" +
+		"STATUS:cancelled\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"SUMMARY:No UID\r\n" +
+		"DTSTART:20250201T120000Z\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+
+	events, err := Parse([]byte(feed))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("Parse() returned %d events, want 2", len(events))
+	}
+
+	reserved := events[0]
+	if reserved.UID != "1418fb94e984-2b2ba3f5@airbnb.com" || !reserved.AllDay {
+		t.Errorf("event = %+v, want the all-day Airbnb reservation", reserved)
+	}
+	if !reserved.Start.Equal(time.Date(2024, 12, 27, 0, 0, 0, 0, time.UTC)) || !reserved.End.Equal(time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("dates = %s - %s, want 2024-12-27 - 2025-01-03", reserved.Start, reserved.End)
+	}
+	if !strings.Contains(reserved.Description, "details/HMABCDEF\nPhone") {
+		t.Errorf("description = %q, want it unfolded and unescaped", reserved.Description)
+	}
+
+	block := events[1]
+	if block.Status != StatusCancelled || block.AllDay {
+		t.Errorf("event = %+v, want a cancelled timed event", block)
+	}
+	if got := block.Start.UTC(); !got.Equal(time.Date(2025, 1, 10, 17, 0, 0, 0, time.UTC)) {
+		t.Errorf("start = %s, want 2025-01-10 17:00 UTC", got)
+	}
+
+	if _, err := Parse([]byte("<html></html>")); !errors.Is(err, ErrNotCalendar) {
+		t.Errorf("Parse() error = %v, want ErrNotCalendar", err)
+	}
+}
@@ -0,0 +1,182 @@
+package ical
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNotCalendar is returned when the data is not an iCalendar feed
+var ErrNotCalendar = errors.New("not an iCalendar feed")
+
+// Parse reads the events of a feed. Events without UID or DTSTART are
+// skipped; DATE values give all-day events in UTC, and DATE-TIME values
+// without "Z" are read in their TZID (UTC when unknown). An event without
+// DTEND lasts one day (all-day) or no time.
+func Parse(data []byte) ([]Event, error) {
+	lines := unfold(string(data))
+	if len(lines) == 0 || !strings.EqualFold(strings.TrimSpace(lines[0]), "BEGIN:VCALENDAR") {
+		return nil, ErrNotCalendar
+	}
+
+	var events []Event
+	var event *Event
+	hasEnd := false
+	depth := 0 // Components nested in the event (VALARM)
+	for _, line := range lines {
+		name, params, value, ok := splitLine(line)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT") && event == nil:
+			event = &Event{}
+			hasEnd = false
+			continue
+		case name == "BEGIN" && event != nil:
+			depth++
+			continue
+		case name == "END" && event != nil && depth > 0:
+			depth--
+			continue
+		case name == "END" && strings.EqualFold(value, "VEVENT") && event != nil:
+			if event.UID != "" && !event.Start.IsZero() {
+				if !hasEnd {
+					event.End = event.Start
+					if event.AllDay {
+						event.End = event.Start.AddDate(0, 0, 1)
+					}
+				}
+				events = append(events, *event)
+			}
+			event = nil
+			continue
+		}
+		if event == nil || depth > 0 {
+			continue
+		}
+
+		switch name {
+		case "UID":
+			event.UID = value
+		case "DTSTART":
+			start, allDay, err := parseTime(params, value)
+			if err != nil {
+				continue
+			}
+			event.Start, event.AllDay = start, allDay
+		case "DTEND":
+			end, _, err := parseTime(params, value)
+			if err != nil {
+				continue
+			}
+			event.End, hasEnd = end, true
+		case "SUMMARY":
+			event.Summary = unescapeText(value)
+		case "DESCRIPTION":
+			event.Description = unescapeText(value)
+		case "LOCATION":
+			event.Location = unescapeText(value)
+		case "STATUS":
+			event.Status = strings.ToUpper(value)
+		case "SEQUENCE":
+			event.Sequence, _ = strconv.Atoi(value)
+		case "LAST-MODIFIED":
+			event.LastModified, _, _ = parseTime(params, value)
+		}
+	}
+
+	return events, nil
+}
+
+// unfold joins folded content lines (continuations start with a space or tab)
+func unfold(data string) []string {
+	data = strings.ReplaceAll(data, "\r\n", "\n")
+	data = strings.TrimPrefix(data, "\uFEFF") // Byte order mark
+
+	var lines []string
+	for _, line := range strings.Split(data, "\n") {
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, strings.TrimRight(line, "\r"))
+		}
+	}
+	return lines
+}
+
+// splitLine splits a content line "NAME;PARAM=value:value" into its upper
+// case name, parameters and value
+func splitLine(line string) (string, map[string]string, string, bool) {
+	quoted := false
+	colon := -1
+	for i, r := range line {
+		if r == '"' {
+			quoted = !quoted
+		}
+		if r == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon <= 0 {
+		return "", nil, "", false
+	}
+
+	parts := strings.Split(line[:colon], ";")
+	params := make(map[string]string, len(parts)-1)
+	for _, part := range parts[1:] {
+		if key, value, ok := strings.Cut(part, "="); ok {
+			params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+		}
+	}
+	return strings.ToUpper(parts[0]), params, line[colon+1:], true
+}
+
+// parseTime parses a DATE or DATE-TIME value; true for DATE values
+func parseTime(params map[string]string, value string) (time.Time, bool, error) {
+	if strings.EqualFold(params["VALUE"], "DATE") || len(value) == len(dateLayout) {
+		t, err := time.ParseInLocation(dateLayout, value, time.UTC)
+		return t, true, err
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse(dateTimeLayout, value)
+		return t, false, err
+	}
+
+	location := time.UTC
+	if tzid := params["TZID"]; tzid != "" {
+		if loaded, err := time.LoadLocation(tzid); err == nil {
+			location = loaded
+		}
+	}
+	t, err := time.ParseInLocation(strings.TrimSuffix(dateTimeLayout, "Z"), value, location)
+	return t, false, err
+}
+
+// unescapeText reverses escapeText
+func unescapeText(s string) string {
+	var b strings.Builder
+	escaped := false
+	for _, r := range s {
+		if escaped {
+			if r == 'n' || r == 'N' {
+				b.WriteRune('\n')
+			} else {
+				b.WriteRune(r)
+			}
+			escaped = false
+			continue
+		}
+		if r == '\\' {
+			escaped = true
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
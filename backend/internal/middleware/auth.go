@@ -21,7 +21,8 @@ const (
 	FirebaseTokenKey ContextKey = "firebase_token"
 )
 
-// AuthMiddleware provides authentication middleware
+// AuthMiddleware provides authentication middleware. Without an auth client
+// (in-memory backend without the Auth emulator) every token is rejected.
 type AuthMiddleware struct {
 	authClient *auth.Client
 }
@@ -61,6 +62,15 @@ func (m *AuthMiddleware) AuthRequired() gin.HandlerFunc {
 
 		idToken := parts[1]
 
+		if m.authClient == nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"success": false,
+				"error":   "authentication is not configured",
+			})
+			c.Abort()
+			return
+		}
+
 		// Verify the ID token
 		token, err := m.authClient.VerifyIDToken(c.Request.Context(), idToken)
 		if err != nil {
@@ -111,6 +121,10 @@ func (m *AuthMiddleware) OptionalAuth() gin.HandlerFunc {
 		}
 
 		idToken := parts[1]
+		if m.authClient == nil {
+			c.Next()
+			return
+		}
 
 		// Try to verify the token
 		token, err := m.authClient.VerifyIDToken(c.Request.Context(), idToken)
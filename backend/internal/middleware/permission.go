@@ -0,0 +1,411 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// MemberKey is the context key for the authenticated tenant member
+	MemberKey ContextKey = "member"
+)
+
+// Member types
+const (
+	MemberTypeUser   = "user"
+	MemberTypeBroker = "broker"
+)
+
+// Member is the authenticated caller resolved within the tenant
+// (a document of /tenants/{tenantId}/users or /tenants/{tenantId}/brokers)
+type Member struct {
+	ID       string
+	Type     string // "user" or "broker"
+	Role     string
+	IsActive bool
+
+	user   *models.User
+	broker *models.Broker
+}
+
+// HasPermission checks if the member has a specific permission
+func (m *Member) HasPermission(permission string) bool {
+	switch {
+	case m.user != nil:
+		return m.user.HasPermission(permission)
+	case m.broker != nil:
+		return m.broker.HasPermission(permission)
+	default:
+		return false
+	}
+}
+
+// AdminRoutePermissions maps admin routes to the permission they require.
+// Keys are "METHOD path", with path relative to /api/v1/admin/:tenant_id.
+// Routes missing from this map are rejected.
+var AdminRoutePermissions = map[string]string{
+	// Properties
+	"POST /properties":                             models.PermissionPropertiesCreate,
+	"GET /properties":                              models.PermissionPropertiesView,
+	"GET /properties/:id":                          models.PermissionPropertiesView,
+	"GET /properties/slug/:slug":                   models.PermissionPropertiesView,
+	"GET /properties/:id/duplicates":               models.PermissionPropertiesView,
+	"GET /properties/:id/history":                  models.PermissionPropertiesView,
+	"PUT /properties/:id":                          models.PermissionPropertiesEdit,
+	"POST /properties/:id/status":                  models.PermissionPropertiesEdit,
+	"POST /properties/:id/visibility":              models.PermissionPropertiesEdit,
+	"POST /properties/:id/portals":                 models.PermissionPropertiesEdit,
+	"PATCH /properties/:id/confirmations":          models.PermissionPropertiesEdit,
+	"POST /properties/:id/owner-confirmation-link": models.PermissionPropertiesEdit,
+	"DELETE /properties/:id":                       models.PermissionPropertiesDelete,
+
+	// Listings
+	"POST /listings":                   models.PermissionPropertiesEdit,
+	"GET /listings":                    models.PermissionPropertiesView,
+	"GET /listings/:id":                models.PermissionPropertiesView,
+	"PUT /listings/:id":                models.PermissionPropertiesEdit,
+	"DELETE /listings/:id":             models.PermissionPropertiesEdit,
+	"POST /listings/:id/set-canonical": models.PermissionPropertiesEdit,
+
+	// Property broker roles
+	"POST /property-brokers/:property_id/assign":                 models.PermissionPropertiesEdit,
+	"GET /property-brokers/:property_id":                         models.PermissionPropertiesView,
+	"PUT /property-brokers/:property_id/:broker_id":              models.PermissionPropertiesEdit,
+	"DELETE /property-brokers/:property_id/:broker_id":           models.PermissionPropertiesEdit,
+	"POST /property-brokers/:property_id/:broker_id/set-primary": models.PermissionPropertiesEdit,
+
+	// Property images
+	"POST /property-images/:property_id":             models.PermissionPropertiesEdit,
+	"GET /property-images/:property_id":              models.PermissionPropertiesView,
+	"GET /property-images/:property_id/:image_id":    models.PermissionPropertiesView,
+	"DELETE /property-images/:property_id/:image_id": models.PermissionPropertiesEdit,
+
+	// Owners
+	"POST /owners":                    models.PermissionOwnersEdit,
+	"GET /owners":                     models.PermissionOwnersView,
+	"GET /owners/:id":                 models.PermissionOwnersView,
+	"PUT /owners/:id":                 models.PermissionOwnersEdit,
+	"POST /owners/:id/revoke-consent": models.PermissionOwnersEdit,
+	"DELETE /owners/:id":              models.PermissionOwnersDelete,
+	"POST /owners/:id/anonymize":      models.PermissionOwnersDelete,
+
+	// Leads
+	"POST /leads":                    models.PermissionLeadsEdit,
+	"GET /leads":                     models.PermissionLeadsView,
+	"GET /leads/sla-report":          models.PermissionLeadsView,
+	"GET /leads/:id":                 models.PermissionLeadsView,
+	"PUT /leads/:id":                 models.PermissionLeadsEdit,
+	"POST /leads/:id/status":         models.PermissionLeadsEdit,
+	"POST /leads/:id/assign":         models.PermissionLeadsEdit,
+	"POST /leads/:id/route":          models.PermissionLeadsEdit,
+	"POST /leads/:id/revoke-consent": models.PermissionLeadsEdit,
+	"DELETE /leads/:id":              models.PermissionLeadsDelete,
+	"POST /leads/:id/anonymize":      models.PermissionLeadsDelete,
+	"GET /contacts":                  models.PermissionLeadsView,
+	"GET /contacts/:id":              models.PermissionLeadsView,
+	"POST /contacts/:id/merge":       models.PermissionLeadsDelete, // Admin: rewrites lead identities
+	"POST /contacts/:id/unmerge":     models.PermissionLeadsDelete,
+
+	// Lead notes, tasks, interactions and timeline
+	"GET /leads/tasks":                     models.PermissionLeadsView,
+	"GET /leads/:id/timeline":              models.PermissionLeadsView,
+	"GET /leads/:id/notes":                 models.PermissionLeadsView,
+	"POST /leads/:id/notes":                models.PermissionLeadsEdit,
+	"PUT /leads/:id/notes/:note_id":        models.PermissionLeadsEdit,
+	"DELETE /leads/:id/notes/:note_id":     models.PermissionLeadsEdit,
+	"GET /leads/:id/tasks":                 models.PermissionLeadsView,
+	"POST /leads/:id/tasks":                models.PermissionLeadsEdit,
+	"PUT /leads/:id/tasks/:task_id":        models.PermissionLeadsEdit,
+	"POST /leads/:id/tasks/:task_id/close": models.PermissionLeadsEdit,
+	"GET /leads/:id/interactions":          models.PermissionLeadsView,
+	"POST /leads/:id/interactions":         models.PermissionLeadsEdit,
+
+	// Brokers
+	"POST /brokers":                models.PermissionBrokersManage,
+	"GET /brokers":                 models.PermissionBrokersView,
+	"GET /brokers/:id":             models.PermissionBrokersView,
+	"PUT /brokers/:id":             models.PermissionBrokersManage,
+	"DELETE /brokers/:id":          models.PermissionBrokersManage,
+	"POST /brokers/:id/activate":   models.PermissionBrokersManage,
+	"POST /brokers/:id/deactivate": models.PermissionBrokersManage,
+	"POST /brokers/:id/photo":      models.PermissionBrokersManage,
+	"DELETE /brokers/:id/photo":    models.PermissionBrokersManage,
+
+	// Visits and broker availability (brokers manage their own calendar, see VisitHandler)
+	"GET /visits":                      models.PermissionLeadsView,
+	"POST /visits":                     models.PermissionLeadsEdit,
+	"GET /visits/:id":                  models.PermissionLeadsView,
+	"POST /visits/:id/confirm":         models.PermissionLeadsEdit,
+	"POST /visits/:id/cancel":          models.PermissionLeadsEdit,
+	"POST /visits/:id/complete":        models.PermissionLeadsEdit,
+	"GET /brokers/:id/availability":    models.PermissionBrokersView,
+	"PUT /brokers/:id/availability":    models.PermissionLeadsEdit,
+	"POST /brokers/:id/calendar-token": models.PermissionLeadsEdit,
+
+	// Proposals (offers and counter-offers on properties)
+	"GET /proposals":              models.PermissionLeadsView,
+	"POST /proposals":             models.PermissionLeadsEdit,
+	"GET /proposals/:id":          models.PermissionLeadsView,
+	"POST /proposals/:id/counter": models.PermissionLeadsEdit,
+	"POST /proposals/:id/accept":  models.PermissionLeadsEdit,
+	"POST /proposals/:id/reject":  models.PermissionLeadsEdit,
+
+	// Commission ledger (brokers see their own statement, see CommissionHandler)
+	"GET /commissions":                        models.PermissionFinanceView,
+	"GET /commissions/split":                  models.PermissionFinanceView,
+	"GET /commissions/receivables":            models.PermissionFinanceView,
+	"GET /commissions/statement":              models.PermissionFinanceView,
+	"POST /commissions/deals":                 models.PermissionFinanceManage,
+	"POST /commissions/deals/:deal_id/cancel": models.PermissionFinanceManage,
+	"GET /commissions/:id":                    models.PermissionFinanceView,
+	"POST /commissions/:id/pay":               models.PermissionFinanceManage,
+	"GET /brokers/:id/commission-statement":   models.PermissionBrokersView,
+
+	// Closed deals (they record the commission, hence finance.manage)
+	"GET /deals":             models.PermissionLeadsView,
+	"POST /deals":            models.PermissionFinanceManage,
+	"GET /deals/:id":         models.PermissionLeadsView,
+	"POST /deals/:id/cancel": models.PermissionFinanceManage,
+	"POST /deals/:id/review": models.PermissionLeadsEdit,
+
+	// Rental contracts (rental administration)
+	"GET /rental-contracts":                models.PermissionFinanceView,
+	"POST /rental-contracts":               models.PermissionFinanceManage,
+	"GET /rental-contracts/:id":            models.PermissionFinanceView,
+	"POST /rental-contracts/:id/renew":     models.PermissionFinanceManage,
+	"POST /rental-contracts/:id/terminate": models.PermissionFinanceManage,
+
+	// Index series and annual rent adjustments
+	"GET /indices/:index":                models.PermissionFinanceView,
+	"POST /indices/import":               models.PermissionSettingsEdit, // platform admins only
+	"GET /rent-adjustments":              models.PermissionFinanceView,
+	"GET /rent-adjustments/upcoming":     models.PermissionFinanceView,
+	"GET /rent-adjustments/calculate":    models.PermissionFinanceView,
+	"GET /rent-adjustments/:id":          models.PermissionFinanceView,
+	"POST /rent-adjustments/:id/apply":   models.PermissionFinanceManage,
+	"POST /rent-adjustments/:id/dismiss": models.PermissionFinanceManage,
+
+	// Rent billing (invoices, payments, owner payouts)
+	"GET /rent-invoices":                 models.PermissionFinanceView,
+	"POST /rent-invoices/generate":       models.PermissionFinanceManage,
+	"GET /rent-invoices/delinquency":     models.PermissionFinanceView,
+	"GET /rent-invoices/owner-statement": models.PermissionFinanceView,
+	"GET /rent-invoices/:id":             models.PermissionFinanceView,
+	"POST /rent-invoices/:id/payments":   models.PermissionFinanceManage,
+	"POST /rent-invoices/:id/cancel":     models.PermissionFinanceManage,
+
+	// Short-stay calendars and bookings (rates and feeds are property settings)
+	"GET /stay-calendars/:property_id":               models.PermissionPropertiesView,
+	"PUT /stay-calendars/:property_id":               models.PermissionPropertiesEdit,
+	"POST /stay-calendars/:property_id/sync":         models.PermissionPropertiesEdit,
+	"POST /stay-calendars/:property_id/export-token": models.PermissionPropertiesEdit,
+	"GET /bookings":              models.PermissionLeadsView,
+	"POST /bookings":             models.PermissionLeadsEdit,
+	"GET /bookings/:id":          models.PermissionLeadsView,
+	"POST /bookings/:id/confirm": models.PermissionLeadsEdit,
+	"POST /bookings/:id/decline": models.PermissionLeadsEdit,
+	"POST /bookings/:id/cancel":  models.PermissionLeadsEdit,
+
+	// Development unit inventory (reservations are made by the brokers)
+	"GET /developments/:property_id/units":          models.PermissionPropertiesView,
+	"POST /developments/:property_id/units":         models.PermissionPropertiesEdit,
+	"POST /developments/:property_id/units/reprice": models.PermissionPropertiesEdit,
+	"GET /developments/:property_id/mirror":         models.PermissionPropertiesView,
+	"GET /development-units/:id":                    models.PermissionPropertiesView,
+	"PUT /development-units/:id":                    models.PermissionPropertiesEdit,
+	"DELETE /development-units/:id":                 models.PermissionPropertiesEdit,
+	"POST /development-units/:id/reserve":           models.PermissionLeadsEdit,
+	"POST /development-units/:id/release":           models.PermissionPropertiesEdit,
+	"POST /development-units/:id/sell":              models.PermissionPropertiesEdit,
+
+	// Users and invitations
+	"POST /users":                                   models.PermissionUsersManage,
+	"GET /users":                                    models.PermissionUsersView,
+	"GET /users/:userId":                            models.PermissionUsersView,
+	"PUT /users/:userId":                            models.PermissionUsersManage,
+	"DELETE /users/:userId":                         models.PermissionUsersManage,
+	"POST /users/:userId/permissions":               models.PermissionUsersManage,
+	"DELETE /users/:userId/permissions/:permission": models.PermissionUsersManage,
+	"POST /users/:userId/photo":                     models.PermissionUsersManage,
+	"DELETE /users/:userId/photo":                   models.PermissionUsersManage,
+	"POST /users/invite":                            models.PermissionUsersManage,
+	"GET /users/invitations":                        models.PermissionUsersView,
+	"DELETE /users/invitations/:invitation_id":      models.PermissionUsersManage,
+
+	// Activity logs
+	"GET /activity-logs":                       models.PermissionActivityLogsView,
+	"GET /activity-logs/:id":                   models.PermissionActivityLogsView,
+	"GET /activity-logs/property/:property_id": models.PermissionActivityLogsView,
+	"GET /activity-logs/lead/:lead_id":         models.PermissionActivityLogsView,
+
+	// Import
+	"POST /import/properties":             models.PermissionImportRun,
+	"GET /import/batches/:batchId":        models.PermissionImportRun,
+	"GET /import/batches/:batchId/errors": models.PermissionImportRun,
+
+	// Monthly owner confirmations
+	"POST /scheduled-confirmations/schedule":             models.PermissionConfirmationsManage,
+	"POST /scheduled-confirmations/process":              models.PermissionConfirmationsManage,
+	"POST /scheduled-confirmations/reminders/process":    models.PermissionConfirmationsManage,
+	"GET /scheduled-confirmations/metrics":               models.PermissionPropertiesView,
+	"GET /scheduled-confirmations/broker/:broker_id":     models.PermissionPropertiesView,
+	"GET /scheduled-confirmations/property/:property_id": models.PermissionPropertiesView,
+	"GET /scheduled-confirmations":                       models.PermissionPropertiesView,
+
+	// Tenant staleness and confirmation policy
+	"GET /policy": models.PermissionSettingsView,
+	"PUT /policy": models.PermissionSettingsEdit,
+
+	// Background jobs (platform admin tenants only, see RequirePlatformAdmin)
+	"GET /jobs":            models.PermissionSettingsView,
+	"GET /jobs/:name/runs": models.PermissionSettingsView,
+	"POST /jobs/:name/run": models.PermissionSettingsEdit,
+}
+
+// PermissionMiddleware provides route-level authorization for tenant members
+type PermissionMiddleware struct {
+	userRepo   repositories.UserStore
+	brokerRepo repositories.BrokerStore
+	routes     map[string]string
+}
+
+// NewPermissionMiddleware creates a new permission middleware using AdminRoutePermissions
+func NewPermissionMiddleware(userRepo repositories.UserStore, brokerRepo repositories.BrokerStore) *PermissionMiddleware {
+	return &PermissionMiddleware{
+		userRepo:   userRepo,
+		brokerRepo: brokerRepo,
+		routes:     AdminRoutePermissions,
+	}
+}
+
+// Authorize returns a middleware that resolves the authenticated Firebase user
+// to a member of the tenant and checks the permission required by the route.
+// Must run after AuthRequired and ValidateTenant.
+func (m *PermissionMiddleware) Authorize() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		permission, ok := m.routes[routeKey(c)]
+		if !ok {
+			c.JSON(http.StatusForbidden, gin.H{
+				"success": false,
+				"error":   "no permission is configured for this route",
+			})
+			c.Abort()
+			return
+		}
+
+		m.authorize(c, permission)
+	}
+}
+
+// RequirePermission returns a middleware that requires a specific permission,
+// regardless of the route table
+func (m *PermissionMiddleware) RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		m.authorize(c, permission)
+	}
+}
+
+// authorize resolves the member and aborts unless it has the permission
+func (m *PermissionMiddleware) authorize(c *gin.Context, permission string) {
+	firebaseUID := GetUserID(c)
+	if firebaseUID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"error":   "authentication required",
+		})
+		c.Abort()
+		return
+	}
+
+	tenantID := GetTenantID(c)
+	if tenantID == "" {
+		tenantID = c.Param("tenant_id")
+	}
+
+	member, err := m.resolveMember(c.Request.Context(), tenantID, firebaseUID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"success": false,
+				"error":   "user is not a member of this tenant",
+			})
+			c.Abort()
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "failed to resolve user permissions",
+		})
+		c.Abort()
+		return
+	}
+
+	if !member.IsActive {
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   "user is not active",
+		})
+		c.Abort()
+		return
+	}
+
+	if !member.HasPermission(permission) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"success":             false,
+			"error":               "insufficient permissions",
+			"required_permission": permission,
+		})
+		c.Abort()
+		return
+	}
+
+	c.Set(string(MemberKey), member)
+
+	ctx := context.WithValue(c.Request.Context(), MemberKey, member)
+	c.Request = c.Request.WithContext(ctx)
+
+	c.Next()
+}
+
+// resolveMember looks the Firebase UID up in the tenant users, then in the tenant brokers
+func (m *PermissionMiddleware) resolveMember(ctx context.Context, tenantID, firebaseUID string) (*Member, error) {
+	user, err := m.userRepo.GetByFirebaseUID(ctx, tenantID, firebaseUID)
+	if err == nil {
+		return &Member{ID: user.ID, Type: MemberTypeUser, Role: user.Role, IsActive: user.IsActive, user: user}, nil
+	}
+	if !errors.Is(err, repositories.ErrNotFound) {
+		return nil, err
+	}
+
+	broker, err := m.brokerRepo.GetByFirebaseUID(ctx, tenantID, firebaseUID)
+	if err != nil {
+		return nil, err
+	}
+	return &Member{ID: broker.ID, Type: MemberTypeBroker, Role: broker.Role, IsActive: broker.IsActive, broker: broker}, nil
+}
+
+// routeKey builds the AdminRoutePermissions key of the matched route
+func routeKey(c *gin.Context) string {
+	path := c.FullPath()
+	if i := strings.Index(path, "/:tenant_id"); i >= 0 {
+		path = path[i+len("/:tenant_id"):]
+	}
+	return c.Request.Method + " " + path
+}
+
+// GetMember retrieves the authorized tenant member from the context
+func GetMember(c *gin.Context) *Member {
+	if member, exists := c.Get(string(MemberKey)); exists {
+		if m, ok := member.(*Member); ok {
+			return m
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories/memory"
+)
+
+// newPermissionTestRouter mounts a few admin routes behind Authorize; the
+// Firebase UID is taken from the X-Test-UID header instead of a token
+func newPermissionTestRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+
+	userRepo := memory.NewUserRepository()
+	brokerRepo := memory.NewBrokerRepository()
+
+	users := []*models.User{
+		{ID: "admin-1", TenantID: "tenant-1", FirebaseUID: "uid-admin", Role: "admin", IsActive: true},
+		{ID: "manager-1", TenantID: "tenant-1", FirebaseUID: "uid-manager", Role: "manager", IsActive: true},
+		{ID: "inactive-1", TenantID: "tenant-1", FirebaseUID: "uid-inactive", Role: "admin", IsActive: false},
+	}
+	for _, user := range users {
+		if err := userRepo.Create(ctx, user); err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+	}
+	if err := brokerRepo.Create(ctx, &models.Broker{ID: "broker-1", TenantID: "tenant-1", FirebaseUID: "uid-broker", Role: "broker", IsActive: true}); err != nil {
+		t.Fatalf("failed to create broker: %v", err)
+	}
+
+	permissionMiddleware := NewPermissionMiddleware(userRepo, brokerRepo)
+
+	router := gin.New()
+	tenantScoped := router.Group("/api/v1/admin/:tenant_id")
+	tenantScoped.Use(func(c *gin.Context) {
+		if uid := c.GetHeader("X-Test-UID"); uid != "" {
+			c.Set(string(UserIDKey), uid)
+		}
+		c.Set(string(TenantIDKey), c.Param("tenant_id"))
+		c.Next()
+	})
+	tenantScoped.Use(permissionMiddleware.Authorize())
+
+	ok := func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"success": true, "member": GetMember(c).ID})
+	}
+	tenantScoped.GET("/properties", ok)
+	tenantScoped.DELETE("/properties/:id", ok)
+	tenantScoped.POST("/brokers", ok)
+	tenantScoped.GET("/unmapped", ok)
+
+	return router
+}
+
+func TestPermissionMiddleware_Authorize(t *testing.T) {
+	router := newPermissionTestRouter(t)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		uid    string
+		status int
+	}{
+		{"Admin can delete properties", http.MethodDelete, "/properties/p1", "uid-admin", http.StatusOK},
+		{"Manager cannot delete properties", http.MethodDelete, "/properties/p1", "uid-manager", http.StatusForbidden},
+		{"Manager can manage brokers", http.MethodPost, "/brokers", "uid-manager", http.StatusOK},
+		{"Broker can list properties", http.MethodGet, "/properties", "uid-broker", http.StatusOK},
+		{"Broker cannot manage brokers", http.MethodPost, "/brokers", "uid-broker", http.StatusForbidden},
+		{"Inactive user is rejected", http.MethodGet, "/properties", "uid-inactive", http.StatusForbidden},
+		{"Non-member is rejected", http.MethodGet, "/properties", "uid-stranger", http.StatusForbidden},
+		{"Missing identity is unauthorized", http.MethodGet, "/properties", "", http.StatusUnauthorized},
+		{"Unmapped route is rejected", http.MethodGet, "/unmapped", "uid-admin", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/v1/admin/tenant-1"+tt.path, nil)
+			if tt.uid != "" {
+				req.Header.Set("X-Test-UID", tt.uid)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Errorf("%s %s as %q = %d, expected %d (%s)", tt.method, tt.path, tt.uid, w.Code, tt.status, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestPermissionMiddleware_MemberIsTenantScoped(t *testing.T) {
+	router := newPermissionTestRouter(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/tenant-2/properties", nil)
+	req.Header.Set("X-Test-UID", "uid-admin")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("admin of tenant-1 on tenant-2 = %d, expected %d", w.Code, http.StatusForbidden)
+	}
+}
+
+func TestAdminRoutePermissions_UseCatalog(t *testing.T) {
+	for route, permission := range AdminRoutePermissions {
+		if !models.IsValidPermission(permission) {
+			t.Errorf("route %q requires permission outside the catalog: %s", route, permission)
+		}
+	}
+}
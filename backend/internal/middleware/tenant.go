@@ -18,11 +18,11 @@ const (
 
 // TenantMiddleware provides tenant validation middleware
 type TenantMiddleware struct {
-	tenantRepo *repositories.TenantRepository
+	tenantRepo repositories.TenantStore
 }
 
 // NewTenantMiddleware creates a new tenant middleware
-func NewTenantMiddleware(tenantRepo *repositories.TenantRepository) *TenantMiddleware {
+func NewTenantMiddleware(tenantRepo repositories.TenantStore) *TenantMiddleware {
 	return &TenantMiddleware{
 		tenantRepo: tenantRepo,
 	}
@@ -103,6 +103,34 @@ func (m *TenantMiddleware) RequireTenant() gin.HandlerFunc {
 	}
 }
 
+// RequirePlatformAdmin returns a middleware that only lets platform admin
+// tenants through; it must run after ValidateTenant
+// Used by platform-wide operations such as background jobs
+func (m *TenantMiddleware) RequirePlatformAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenant, err := m.tenantRepo.Get(c.Request.Context(), GetTenantID(c))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error":   "failed to validate tenant",
+			})
+			c.Abort()
+			return
+		}
+
+		if !tenant.IsPlatformAdmin {
+			c.JSON(http.StatusForbidden, gin.H{
+				"success": false,
+				"error":   "platform admin access required",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
 // GetTenantID retrieves the tenant ID from the context
 func GetTenantID(c *gin.Context) string {
 	if tenantID, exists := c.Get(string(TenantIDKey)); exists {
@@ -0,0 +1,100 @@
+package models
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"time"
+)
+
+// BookingRequestTTL is how long a booking request holds its dates while the
+// agency answers
+const BookingRequestTTL = 48 * time.Hour
+
+// BookingStatus defines the status of a booking
+type BookingStatus string
+
+const (
+	BookingStatusRequested BookingStatus = "requested" // Asked for from the portal, holds the dates until answered or expired
+	BookingStatusConfirmed BookingStatus = "confirmed"
+	BookingStatusBlocked   BookingStatus = "blocked" // Dates unavailable without a guest (owner use, maintenance, external calendars)
+	BookingStatusDeclined  BookingStatus = "declined"
+	BookingStatusCancelled BookingStatus = "cancelled"
+	BookingStatusExpired   BookingStatus = "expired" // Request not answered in time
+)
+
+// BookingSource tells where a booking was made
+type BookingSource string
+
+const (
+	BookingSourcePublic BookingSource = "public" // Requested from the property page
+	BookingSourceAdmin  BookingSource = "admin"  // Booked or blocked by the agency
+	BookingSourceICal   BookingSource = "ical"   // Imported from an external calendar
+)
+
+// Booking is a stay, or a period blocked, in the availability calendar of a
+// property let by the night
+// Collection: /tenants/{tenantId}/bookings/{bookingId}
+type Booking struct {
+	ID         string `firestore:"-" json:"id"`
+	TenantID   string `firestore:"tenant_id" json:"tenant_id"`
+	PropertyID string `firestore:"property_id" json:"property_id"`
+	LeadID     string `firestore:"lead_id,omitempty" json:"lead_id,omitempty"` // Public requests
+
+	Status   BookingStatus `firestore:"status" json:"status"`
+	Source   BookingSource `firestore:"source" json:"source"`
+	CheckIn  time.Time     `firestore:"check_in" json:"check_in"`   // Arrival day
+	CheckOut time.Time     `firestore:"check_out" json:"check_out"` // Departure day (not a night of the stay)
+	Nights   int           `firestore:"nights" json:"nights"`
+
+	// Hóspede (none for blocks)
+	GuestName  string `firestore:"guest_name,omitempty" json:"guest_name,omitempty"`
+	GuestEmail string `firestore:"guest_email,omitempty" json:"guest_email,omitempty"`
+	GuestPhone string `firestore:"guest_phone,omitempty" json:"guest_phone,omitempty"`
+	Guests     int    `firestore:"guests,omitempty" json:"guests,omitempty"`
+	Notes      string `firestore:"notes,omitempty" json:"notes,omitempty"`
+
+	// Price at booking
+	Quote *StayQuote `firestore:"quote,omitempty" json:"quote,omitempty"`
+
+	// External calendar of imported blocks
+	FeedID      string `firestore:"feed_id,omitempty" json:"feed_id,omitempty"`
+	ExternalUID string `firestore:"external_uid,omitempty" json:"external_uid,omitempty"`
+
+	// Status changes
+	ExpiresAt    *time.Time `firestore:"expires_at,omitempty" json:"expires_at,omitempty"` // Requests
+	ConfirmedAt  *time.Time `firestore:"confirmed_at,omitempty" json:"confirmed_at,omitempty"`
+	CancelledAt  *time.Time `firestore:"cancelled_at,omitempty" json:"cancelled_at,omitempty"` // Declined, cancelled or expired
+	CancelledBy  string     `firestore:"cancelled_by,omitempty" json:"cancelled_by,omitempty"`
+	CancelReason string     `firestore:"cancel_reason,omitempty" json:"cancel_reason,omitempty"`
+
+	// Metadata
+	CreatedAt time.Time `firestore:"created_at" json:"created_at"`
+	UpdatedAt time.Time `firestore:"updated_at" json:"updated_at"`
+}
+
+// BookingHoldingStatuses are the statuses that may hold dates
+var BookingHoldingStatuses = []BookingStatus{BookingStatusRequested, BookingStatusConfirmed, BookingStatusBlocked}
+
+// ImportedBookingID returns the document ID of an event of an external
+// calendar, stable across syncs
+func ImportedBookingID(propertyID, feedID, uid string) string {
+	return fmt.Sprintf("ical_%x", sha256.Sum256([]byte(propertyID+"|"+feedID+"|"+uid)))[:37]
+}
+
+// HoldsDates reports whether the booking makes its dates unavailable at a
+// time: confirmed stays, blocks and requests not yet expired
+func (b *Booking) HoldsDates(at time.Time) bool {
+	switch b.Status {
+	case BookingStatusConfirmed, BookingStatusBlocked:
+		return true
+	case BookingStatusRequested:
+		return b.ExpiresAt == nil || at.Before(*b.ExpiresAt)
+	}
+	return false
+}
+
+// Overlaps reports whether the booking takes a night of the stay from
+// checkIn to checkOut
+func (b *Booking) Overlaps(checkIn, checkOut time.Time) bool {
+	return b.CheckIn.Before(checkOut) && checkIn.Before(b.CheckOut)
+}
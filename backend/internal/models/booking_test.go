@@ -0,0 +1,44 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBookingHoldsDates(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	expiresAt := now.Add(BookingRequestTTL)
+
+	tests := []struct {
+		name    string
+		booking Booking
+		at      time.Time
+		want    bool
+	}{
+		{"Confirmed", Booking{Status: BookingStatusConfirmed}, now, true},
+		{"Blocked", Booking{Status: BookingStatusBlocked}, now, true},
+		{"Pending request", Booking{Status: BookingStatusRequested, ExpiresAt: &expiresAt}, now, true},
+		{"Request past its deadline", Booking{Status: BookingStatusRequested, ExpiresAt: &expiresAt}, expiresAt, false},
+		{"Cancelled", Booking{Status: BookingStatusCancelled}, now, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.booking.HoldsDates(tt.at); got != tt.want {
+				t.Errorf("HoldsDates() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBookingOverlaps(t *testing.T) {
+	booking := Booking{CheckIn: time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC), CheckOut: time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC)}
+
+	// Check-out and check-in on the same day do not overlap
+	if booking.Overlaps(time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC), time.Date(2025, 3, 16, 0, 0, 0, 0, time.UTC)) {
+		t.Error("Overlaps() = true for a stay starting on the check-out day")
+	}
+	if !booking.Overlaps(time.Date(2025, 3, 13, 0, 0, 0, 0, time.UTC), time.Date(2025, 3, 16, 0, 0, 0, 0, time.UTC)) {
+		t.Error("Overlaps() = false for a stay taking the last night")
+	}
+}
@@ -60,6 +60,10 @@ type Broker struct {
 	ServiceAreas     string  `firestore:"service_areas,omitempty" json:"service_areas,omitempty"`         // Áreas de atendimento (JSON array)
 	CertificationsAwards string `firestore:"certifications_awards,omitempty" json:"certifications_awards,omitempty"` // Certificações e prêmios
 
+	// Visit scheduling
+	Availability      *BrokerAvailability `firestore:"availability,omitempty" json:"availability,omitempty"` // Weekly hours and time off (nil = DefaultBrokerAvailability)
+	CalendarTokenHash string              `firestore:"calendar_token_hash,omitempty" json:"-"`               // SHA-256 of the secret of the .ics feed URL
+
 	// Metadata - using interface{} to handle both time.Time and string from Firestore
 	CreatedAt interface{} `firestore:"created_at" json:"created_at"`
 	UpdatedAt interface{} `firestore:"updated_at" json:"updated_at"`
@@ -111,6 +115,16 @@ func (b Broker) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// HasPermission checks if broker has a specific permission
+// Brokers have no explicit grants: permissions come from the role ("broker" when unset)
+func (b *Broker) HasPermission(permission string) bool {
+	role := b.Role
+	if role == "" {
+		role = "broker"
+	}
+	return RoleHasPermission(role, permission)
+}
+
 // BrokerPublic represents a sanitized broker profile for public display
 // This excludes sensitive information like Firebase UID and document numbers
 type BrokerPublic struct {
@@ -0,0 +1,153 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Default commission rules (CRECI table practice)
+const (
+	DefaultSaleCommissionRate   = 6.0   // % of the sale price
+	DefaultRentalCommissionRate = 100.0 // % of the first monthly rent
+)
+
+// CommissionBeneficiary is who receives a commission entry
+type CommissionBeneficiary string
+
+const (
+	CommissionBeneficiaryBroker CommissionBeneficiary = "broker"
+	CommissionBeneficiaryAgency CommissionBeneficiary = "agency" // The tenant (imobiliária)
+)
+
+// CommissionEntryStatus defines the status of a commission entry
+type CommissionEntryStatus string
+
+const (
+	CommissionEntryStatusPending   CommissionEntryStatus = "pending" // Receivable
+	CommissionEntryStatusPaid      CommissionEntryStatus = "paid"
+	CommissionEntryStatusCancelled CommissionEntryStatus = "cancelled" // Deal fell through
+)
+
+// CommissionSettings configures the commission of closed deals and its split.
+// Shares are percentages of the total commission by broker role; brokers
+// sharing a role split its share equally, unless their PropertyBrokerRole sets
+// CommissionPercentage. The agency keeps what is left.
+type CommissionSettings struct {
+	SaleRate   float64 `firestore:"sale_rate" json:"sale_rate"`     // % of the sale price (default 6)
+	RentalRate float64 `firestore:"rental_rate" json:"rental_rate"` // % of the first monthly rent (default 100)
+
+	OriginatingShare float64 `firestore:"originating_share" json:"originating_share"` // Captador
+	ListingShare     float64 `firestore:"listing_share" json:"listing_share"`         // Vendedor
+	CoBrokerShare    float64 `firestore:"co_broker_share" json:"co_broker_share"`     // Property.CoBrokerCommission overrides it
+}
+
+// DefaultCommissionSettings returns the commission rules of tenants that never configured them
+func DefaultCommissionSettings() CommissionSettings {
+	return CommissionSettings{
+		SaleRate:         DefaultSaleCommissionRate,
+		RentalRate:       DefaultRentalCommissionRate,
+		OriginatingShare: 20,
+		ListingShare:     20,
+		CoBrokerShare:    10,
+	}
+}
+
+// EffectiveCommission returns the tenant's commission rules with defaults applied
+func (t *Tenant) EffectiveCommission() CommissionSettings {
+	if t == nil || t.Commission == nil {
+		return DefaultCommissionSettings()
+	}
+	return t.Commission.WithDefaults()
+}
+
+// WithDefaults returns the settings with unset rates replaced by the defaults
+// (a zero share is a valid choice and is kept)
+func (s CommissionSettings) WithDefaults() CommissionSettings {
+	if s.SaleRate == 0 {
+		s.SaleRate = DefaultSaleCommissionRate
+	}
+	if s.RentalRate == 0 {
+		s.RentalRate = DefaultRentalCommissionRate
+	}
+	return s
+}
+
+// Rate returns the commission rate of a transaction (sale or rent)
+func (s CommissionSettings) Rate(transaction TransactionType) float64 {
+	if transaction == TransactionTypeRent {
+		return s.RentalRate
+	}
+	return s.SaleRate
+}
+
+// RoleShare returns the share of the total commission of a broker role
+func (s CommissionSettings) RoleShare(role BrokerPropertyRole) float64 {
+	switch role {
+	case BrokerPropertyRoleOriginating:
+		return s.OriginatingShare
+	case BrokerPropertyRoleListing:
+		return s.ListingShare
+	case BrokerPropertyRoleCoBroker:
+		return s.CoBrokerShare
+	}
+	return 0
+}
+
+// Validate checks the rates and shares; call it on settings with defaults applied
+func (s CommissionSettings) Validate() error {
+	if s.SaleRate <= 0 || s.SaleRate > 100 {
+		return fmt.Errorf("sale_rate must be between 0 and 100")
+	}
+	if s.RentalRate <= 0 || s.RentalRate > 200 {
+		return fmt.Errorf("rental_rate must be between 0 and 200")
+	}
+	if s.OriginatingShare < 0 || s.ListingShare < 0 || s.CoBrokerShare < 0 {
+		return fmt.Errorf("shares cannot be negative")
+	}
+	if s.OriginatingShare+s.ListingShare+s.CoBrokerShare > 100 {
+		return fmt.Errorf("originating_share, listing_share and co_broker_share exceed 100")
+	}
+	return nil
+}
+
+// CommissionEntry is one line of the commission ledger: the part of a closed
+// deal's commission owed to a broker or kept by the agency
+// Collection: /tenants/{tenantId}/commission_entries/{entryId}
+type CommissionEntry struct {
+	ID         string `firestore:"-" json:"id"`
+	TenantID   string `firestore:"tenant_id" json:"tenant_id"`
+	DealID     string `firestore:"deal_id" json:"deal_id"` // Shared by the entries of one closed deal
+	ProposalID string `firestore:"proposal_id,omitempty" json:"proposal_id,omitempty"`
+	PropertyID string `firestore:"property_id" json:"property_id"`
+
+	// Deal
+	TransactionType TransactionType `firestore:"transaction_type" json:"transaction_type"`
+	DealPrice       float64         `firestore:"deal_price" json:"deal_price"`             // Sale price or monthly rent
+	CommissionRate  float64         `firestore:"commission_rate" json:"commission_rate"`   // % of the deal price
+	TotalCommission float64         `firestore:"total_commission" json:"total_commission"` // Of the whole deal
+	ClosedAt        time.Time       `firestore:"closed_at" json:"closed_at"`
+	Period          string          `firestore:"period" json:"period"` // YYYY-MM of closed_at (monthly statements)
+
+	// Beneficiary and its part
+	Beneficiary     CommissionBeneficiary `firestore:"beneficiary" json:"beneficiary"`
+	BrokerID        string                `firestore:"broker_id,omitempty" json:"broker_id,omitempty"`
+	Role            BrokerPropertyRole    `firestore:"role,omitempty" json:"role,omitempty"`
+	SharePercentage float64               `firestore:"share_percentage" json:"share_percentage"` // % of the total commission
+	Amount          float64               `firestore:"amount" json:"amount"`
+
+	// Settlement
+	Status           CommissionEntryStatus `firestore:"status" json:"status"`
+	PaidAt           *time.Time            `firestore:"paid_at,omitempty" json:"paid_at,omitempty"`
+	PaymentReference string                `firestore:"payment_reference,omitempty" json:"payment_reference,omitempty"` // PIX/TED id, receipt
+	CancelledAt      *time.Time            `firestore:"cancelled_at,omitempty" json:"cancelled_at,omitempty"`
+	CancelReason     string                `firestore:"cancel_reason,omitempty" json:"cancel_reason,omitempty"`
+
+	// Metadata
+	CreatedAt time.Time `firestore:"created_at" json:"created_at"`
+	UpdatedAt time.Time `firestore:"updated_at" json:"updated_at"`
+}
+
+// CommissionPeriod returns the statement period (YYYY-MM) of a closing date
+func CommissionPeriod(closedAt time.Time) string {
+	return closedAt.Format(PeriodLayout)
+}
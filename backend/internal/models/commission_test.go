@@ -0,0 +1,43 @@
+package models
+
+import "testing"
+
+func TestCommissionSettingsValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings CommissionSettings
+		wantErr  bool
+	}{
+		{"Default", DefaultCommissionSettings(), false},
+		{"Unset rates take the defaults", CommissionSettings{OriginatingShare: 30}.WithDefaults(), false},
+		{"No broker shares", CommissionSettings{SaleRate: 5, RentalRate: 100}, false},
+		{"Zero sale rate", CommissionSettings{RentalRate: 100}, true},
+		{"Sale rate above 100", CommissionSettings{SaleRate: 120, RentalRate: 100}, true},
+		{"Negative share", CommissionSettings{SaleRate: 6, RentalRate: 100, ListingShare: -5}, true},
+		{"Shares above 100", CommissionSettings{SaleRate: 6, RentalRate: 100, OriginatingShare: 50, ListingShare: 40, CoBrokerShare: 20}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.settings.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCommissionSettingsRate(t *testing.T) {
+	var tenant *Tenant
+	settings := tenant.EffectiveCommission()
+
+	if got := settings.Rate(TransactionTypeSale); got != DefaultSaleCommissionRate {
+		t.Errorf("Rate(sale) = %v, want %v", got, DefaultSaleCommissionRate)
+	}
+	if got := settings.Rate(TransactionTypeRent); got != DefaultRentalCommissionRate {
+		t.Errorf("Rate(rent) = %v, want %v", got, DefaultRentalCommissionRate)
+	}
+	if got := settings.RoleShare(BrokerPropertyRoleCoBroker); got != 10 {
+		t.Errorf("RoleShare(co_broker) = %v, want 10", got)
+	}
+}
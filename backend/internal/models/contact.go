@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// Contact is the person behind one or more leads, identified by normalized
+// email and phone (E.164). Repeat inquiries from the same person attach to
+// the same contact; the leads keep their own LGPD consent records.
+// Collection: /tenants/{tenantId}/contacts/{contactId}
+type Contact struct {
+	ID       string `firestore:"-" json:"id"`
+	TenantID string `firestore:"tenant_id" json:"tenant_id"`
+	Name     string `firestore:"name,omitempty" json:"name,omitempty"` // Nome informado na inquiry mais recente
+
+	// Identidades conhecidas (derivadas dos leads)
+	Emails []string `firestore:"emails" json:"emails"` // utils.NormalizeEmail
+	Phones []string `firestore:"phones" json:"phones"` // utils.NormalizePhoneE164
+
+	// Histórico de inquiries (derivado dos leads)
+	LeadIDs        []string   `firestore:"lead_ids" json:"lead_ids"`
+	PropertyIDs    []string   `firestore:"property_ids" json:"property_ids"`
+	InquiryCount   int        `firestore:"inquiry_count" json:"inquiry_count"`
+	FirstInquiryAt *time.Time `firestore:"first_inquiry_at,omitempty" json:"first_inquiry_at,omitempty"`
+	LastInquiryAt  *time.Time `firestore:"last_inquiry_at,omitempty" json:"last_inquiry_at,omitempty"`
+
+	// Merge
+	MergedIntoID string         `firestore:"merged_into_id,omitempty" json:"merged_into_id,omitempty"` // Contact that absorbed this one
+	Merges       []ContactMerge `firestore:"merges,omitempty" json:"merges,omitempty"`                 // Contacts absorbed by this one
+
+	// Metadata
+	CreatedAt time.Time `firestore:"created_at" json:"created_at"`
+	UpdatedAt time.Time `firestore:"updated_at" json:"updated_at"`
+}
+
+// ContactMerge records a contact merged into another, so it can be undone
+type ContactMerge struct {
+	SourceContactID string    `firestore:"source_contact_id" json:"source_contact_id"`
+	LeadIDs         []string  `firestore:"lead_ids" json:"lead_ids"` // Leads moved from the source contact
+	MergedBy        string    `firestore:"merged_by,omitempty" json:"merged_by,omitempty"`
+	MergedAt        time.Time `firestore:"merged_at" json:"merged_at"`
+}
+
+// IsMerged reports whether the contact was merged into another
+func (c *Contact) IsMerged() bool {
+	return c.MergedIntoID != ""
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// DealStatus defines the status of a deal
+type DealStatus string
+
+const (
+	DealStatusClosed    DealStatus = "closed"
+	DealStatusCancelled DealStatus = "cancelled" // Registered by mistake or undone (distrato)
+)
+
+// Unavailable reasons of properties taken off the market by a deal
+const (
+	PropertyUnavailableReasonSold   = "sold"
+	PropertyUnavailableReasonRented = "rented"
+)
+
+// Deal is a closed sale or rental of a property (negócio fechado). Deals feed
+// the commission ledger and the broker statistics.
+// Collection: /tenants/{tenantId}/deals/{dealId}
+type Deal struct {
+	ID         string `firestore:"-" json:"id"`
+	TenantID   string `firestore:"tenant_id" json:"tenant_id"`
+	PropertyID string `firestore:"property_id" json:"property_id"`
+	LeadID     string `firestore:"lead_id,omitempty" json:"lead_id,omitempty"`         // Buyer or tenant, when the deal came from a lead
+	ProposalID string `firestore:"proposal_id,omitempty" json:"proposal_id,omitempty"` // Accepted proposal
+
+	TransactionType TransactionType `firestore:"transaction_type" json:"transaction_type"` // sale or rent
+	Price           float64         `firestore:"price" json:"price"`                       // Final sale price or monthly rent
+	ClosedAt        time.Time       `firestore:"closed_at" json:"closed_at"`               // Signing date
+	Status          DealStatus      `firestore:"status" json:"status"`
+
+	// Brokers involved and their roles in the deal
+	Brokers   []DealBroker `firestore:"brokers" json:"brokers"`
+	BrokerIDs []string     `firestore:"broker_ids" json:"-"` // Denormalized for array-contains queries
+
+	// Review of the brokers by the buyer or tenant
+	Review *DealReview `firestore:"review,omitempty" json:"review,omitempty"`
+
+	Notes        string     `firestore:"notes,omitempty" json:"notes,omitempty"`
+	CancelledAt  *time.Time `firestore:"cancelled_at,omitempty" json:"cancelled_at,omitempty"`
+	CancelReason string     `firestore:"cancel_reason,omitempty" json:"cancel_reason,omitempty"`
+
+	// Metadata
+	CreatedBy string    `firestore:"created_by,omitempty" json:"created_by,omitempty"`
+	CreatedAt time.Time `firestore:"created_at" json:"created_at"`
+	UpdatedAt time.Time `firestore:"updated_at" json:"updated_at"`
+}
+
+// DealBroker is a broker involved in a deal
+type DealBroker struct {
+	BrokerID             string             `firestore:"broker_id" json:"broker_id"`
+	Role                 BrokerPropertyRole `firestore:"role" json:"role"`
+	CommissionPercentage float64            `firestore:"commission_percentage,omitempty" json:"commission_percentage,omitempty"` // 0 = tenant rule
+}
+
+// DealReview is the rating the buyer or tenant gave the brokers of a deal
+type DealReview struct {
+	Rating     int       `firestore:"rating" json:"rating"` // 1 to 5
+	Comment    string    `firestore:"comment,omitempty" json:"comment,omitempty"`
+	ReviewedAt time.Time `firestore:"reviewed_at" json:"reviewed_at"`
+}
+
+// UnavailableReason returns the reason of the property leaving the market
+func (d *Deal) UnavailableReason() string {
+	if d.TransactionType == TransactionTypeRent {
+		return PropertyUnavailableReasonRented
+	}
+	return PropertyUnavailableReasonSold
+}
+
+// Validate checks the rating of a review
+func (r DealReview) Validate() error {
+	if r.Rating < 1 || r.Rating > 5 {
+		return fmt.Errorf("rating must be between 1 and 5")
+	}
+	return nil
+}
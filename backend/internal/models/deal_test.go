@@ -0,0 +1,42 @@
+package models
+
+import "testing"
+
+func TestDealReviewValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		review  DealReview
+		wantErr bool
+	}{
+		{"Lowest", DealReview{Rating: 1}, false},
+		{"Highest", DealReview{Rating: 5, Comment: "Excelente atendimento"}, false},
+		{"No rating", DealReview{}, true},
+		{"Above 5", DealReview{Rating: 6}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.review.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDealUnavailableReason(t *testing.T) {
+	tests := []struct {
+		transaction TransactionType
+		want        string
+	}{
+		{TransactionTypeSale, PropertyUnavailableReasonSold},
+		{TransactionTypeRent, PropertyUnavailableReasonRented},
+	}
+
+	for _, tt := range tests {
+		deal := &Deal{TransactionType: tt.transaction}
+		if got := deal.UnavailableReason(); got != tt.want {
+			t.Errorf("UnavailableReason(%s) = %q, want %q", tt.transaction, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// Reservation terms of development units
+const (
+	DefaultUnitReservationHours = 48
+	MaxUnitReservationHours     = 7 * 24
+)
+
+// DevelopmentUnitStatus defines the sale status of a development unit
+type DevelopmentUnitStatus string
+
+const (
+	DevelopmentUnitStatusAvailable DevelopmentUnitStatus = "available"
+	DevelopmentUnitStatusReserved  DevelopmentUnitStatus = "reserved" // Held for a buyer until ReservationExpiresAt
+	DevelopmentUnitStatusSold      DevelopmentUnitStatus = "sold"
+)
+
+// DevelopmentUnit is a unit (apartamento, sala, lote) of a real estate
+// development (lançamento). The development is a property whose
+// DevelopmentInfo counters are recomputed from its units. Units are unique
+// per tower and number: the ID is DevelopmentUnitID(property, tower, number).
+// Collection: /tenants/{tenantId}/development_units/{unitId}
+type DevelopmentUnit struct {
+	ID         string `firestore:"-" json:"id"`
+	TenantID   string `firestore:"tenant_id" json:"tenant_id"`
+	PropertyID string `firestore:"property_id" json:"property_id"` // The development
+
+	// Localização no empreendimento
+	Tower  string `firestore:"tower,omitempty" json:"tower,omitempty"` // Torre, bloco or quadra ("" for a single building)
+	Floor  int    `firestore:"floor" json:"floor"`                     // Andar (0 = térreo)
+	Number string `firestore:"number" json:"number"`                   // ex: "1204", "Lote 12"
+
+	// Tipologia
+	Typology     string  `firestore:"typology" json:"typology"` // ex: "2 dorms c/ suíte"
+	Bedrooms     int     `firestore:"bedrooms,omitempty" json:"bedrooms,omitempty"`
+	Suites       int     `firestore:"suites,omitempty" json:"suites,omitempty"`
+	ParkingSpots int     `firestore:"parking_spots,omitempty" json:"parking_spots,omitempty"`
+	PrivateArea  float64 `firestore:"private_area,omitempty" json:"private_area,omitempty"` // Área privativa (m²)
+
+	// Tabela de vendas
+	Price       float64          `firestore:"price" json:"price"`
+	PaymentPlan *UnitPaymentPlan `firestore:"payment_plan,omitempty" json:"payment_plan,omitempty"`
+
+	Status DevelopmentUnitStatus `firestore:"status" json:"status"`
+
+	// Reserva e venda
+	BrokerID             string     `firestore:"broker_id,omitempty" json:"broker_id,omitempty"` // Broker that reserved or sold the unit
+	LeadID               string     `firestore:"lead_id,omitempty" json:"lead_id,omitempty"`
+	BuyerName            string     `firestore:"buyer_name,omitempty" json:"buyer_name,omitempty"`
+	ReservedAt           *time.Time `firestore:"reserved_at,omitempty" json:"reserved_at,omitempty"`
+	ReservationExpiresAt *time.Time `firestore:"reservation_expires_at,omitempty" json:"reservation_expires_at,omitempty"`
+	SoldAt               *time.Time `firestore:"sold_at,omitempty" json:"sold_at,omitempty"`
+	SalePrice            float64    `firestore:"sale_price,omitempty" json:"sale_price,omitempty"` // Final price (default: table price)
+	Notes                string     `firestore:"notes,omitempty" json:"notes,omitempty"`
+
+	// Metadata
+	CreatedAt time.Time `firestore:"created_at" json:"created_at"`
+	UpdatedAt time.Time `firestore:"updated_at" json:"updated_at"`
+}
+
+// UnitPaymentPlan is the payment plan (fluxo de pagamento) of the price
+// table. The part of the price it does not cover is financed at delivery.
+type UnitPaymentPlan struct {
+	DownPayment         float64 `firestore:"down_payment" json:"down_payment"`                 // Entrada (ato)
+	MonthlyInstallments int     `firestore:"monthly_installments" json:"monthly_installments"` // Parcelas mensais durante a obra
+	MonthlyInstallment  float64 `firestore:"monthly_installment" json:"monthly_installment"`
+	AnnualInstallments  int     `firestore:"annual_installments" json:"annual_installments"` // Intermediárias (balões anuais)
+	AnnualInstallment   float64 `firestore:"annual_installment" json:"annual_installment"`
+	KeysPayment         float64 `firestore:"keys_payment" json:"keys_payment"` // Parcela das chaves
+}
+
+// UnitCounts are the counters of the units of a development
+type UnitCounts struct {
+	Total     int `json:"total"`
+	Available int `json:"available"`
+	Reserved  int `json:"reserved"`
+	Sold      int `json:"sold"`
+}
+
+// DevelopmentUnitID returns the document ID of a unit of a development
+func DevelopmentUnitID(propertyID, tower, number string) string {
+	key := unitKey(number)
+	if tower = unitKey(tower); tower != "" {
+		key = tower + "-" + key
+	}
+	return propertyID + "_" + key
+}
+
+// unitKey normalizes a tower or unit number for document IDs
+func unitKey(value string) string {
+	return strings.NewReplacer(" ", "", "/", "-").Replace(strings.ToUpper(strings.TrimSpace(value)))
+}
+
+// Label returns the name of the unit in the development (ex: "Torre A 1204")
+func (u *DevelopmentUnit) Label() string {
+	if u.Tower == "" {
+		return u.Number
+	}
+	return u.Tower + " " + u.Number
+}
+
+// Validate checks the identification, typology and price of the unit
+func (u *DevelopmentUnit) Validate() error {
+	if strings.TrimSpace(u.Number) == "" {
+		return fmt.Errorf("number is required")
+	}
+	if u.Bedrooms < 0 || u.Suites < 0 || u.ParkingSpots < 0 || u.PrivateArea < 0 {
+		return fmt.Errorf("bedrooms, suites, parking_spots and private_area cannot be negative")
+	}
+	if u.Price <= 0 {
+		return fmt.Errorf("price must be positive")
+	}
+	if plan := u.PaymentPlan; plan != nil {
+		if plan.DownPayment < 0 || plan.MonthlyInstallments < 0 || plan.MonthlyInstallment < 0 ||
+			plan.AnnualInstallments < 0 || plan.AnnualInstallment < 0 || plan.KeysPayment < 0 {
+			return fmt.Errorf("payment_plan amounts cannot be negative")
+		}
+		if plan.Total() > u.Price {
+			return fmt.Errorf("payment_plan totals %.2f, above the price", plan.Total())
+		}
+	}
+	return nil
+}
+
+// Total returns the amount of the plan paid to the developer
+func (p *UnitPaymentPlan) Total() float64 {
+	return roundCents(p.DownPayment + float64(p.MonthlyInstallments)*p.MonthlyInstallment +
+		float64(p.AnnualInstallments)*p.AnnualInstallment + p.KeysPayment)
+}
+
+// FinancedBalance returns the part of the price not covered by the payment
+// plan (financed at delivery)
+func (u *DevelopmentUnit) FinancedBalance() float64 {
+	if u.PaymentPlan == nil {
+		return u.Price
+	}
+	return math.Max(0, roundCents(u.Price-u.PaymentPlan.Total()))
+}
+
+// Reprice scales the price and payment plan of the unit by a percentage
+// (reajuste da tabela, ex: INCC)
+func (u *DevelopmentUnit) Reprice(percentage float64) {
+	factor := 1 + percentage/100
+	u.Price = roundCents(u.Price * factor)
+	if plan := u.PaymentPlan; plan != nil {
+		plan.DownPayment = roundCents(plan.DownPayment * factor)
+		plan.MonthlyInstallment = roundCents(plan.MonthlyInstallment * factor)
+		plan.AnnualInstallment = roundCents(plan.AnnualInstallment * factor)
+		plan.KeysPayment = roundCents(plan.KeysPayment * factor)
+	}
+}
+
+// EffectiveStatus returns the status of the unit at a time: a reservation
+// past its expiry leaves the unit available
+func (u *DevelopmentUnit) EffectiveStatus(at time.Time) DevelopmentUnitStatus {
+	if u.Status == DevelopmentUnitStatusReserved && u.ReservationExpiresAt != nil && !at.Before(*u.ReservationExpiresAt) {
+		return DevelopmentUnitStatusAvailable
+	}
+	return u.Status
+}
+
+// IsFree reports whether the unit can be reserved or sold at a time
+func (u *DevelopmentUnit) IsFree(at time.Time) bool {
+	return u.EffectiveStatus(at) == DevelopmentUnitStatusAvailable
+}
+
+// CountUnits counts the units of a development by their status at a time
+func CountUnits(units []*DevelopmentUnit, at time.Time) UnitCounts {
+	counts := UnitCounts{Total: len(units)}
+	for _, unit := range units {
+		switch unit.EffectiveStatus(at) {
+		case DevelopmentUnitStatusAvailable:
+			counts.Available++
+		case DevelopmentUnitStatusReserved:
+			counts.Reserved++
+		case DevelopmentUnitStatusSold:
+			counts.Sold++
+		}
+	}
+	return counts
+}
@@ -0,0 +1,96 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDevelopmentUnitID(t *testing.T) {
+	tests := []struct {
+		tower  string
+		number string
+		want   string
+	}{
+		{"", "1204", "dev-1_1204"},
+		{"Torre A", "1204", "dev-1_TORREA-1204"},
+		{" a ", "101", "dev-1_A-101"},
+		{"Quadra 3", "Lote 12/13", "dev-1_QUADRA3-LOTE12-13"},
+	}
+
+	for _, tt := range tests {
+		if got := DevelopmentUnitID("dev-1", tt.tower, tt.number); got != tt.want {
+			t.Errorf("DevelopmentUnitID(%q, %q) = %q, want %q", tt.tower, tt.number, got, tt.want)
+		}
+	}
+}
+
+func TestDevelopmentUnitEffectiveStatus(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	expiresAt := now.Add(DefaultUnitReservationHours * time.Hour)
+
+	tests := []struct {
+		name string
+		unit DevelopmentUnit
+		at   time.Time
+		want DevelopmentUnitStatus
+	}{
+		{"Available", DevelopmentUnit{Status: DevelopmentUnitStatusAvailable}, now, DevelopmentUnitStatusAvailable},
+		{"Reservation running", DevelopmentUnit{Status: DevelopmentUnitStatusReserved, ReservationExpiresAt: &expiresAt}, now, DevelopmentUnitStatusReserved},
+		{"Reservation past its expiry", DevelopmentUnit{Status: DevelopmentUnitStatusReserved, ReservationExpiresAt: &expiresAt}, expiresAt, DevelopmentUnitStatusAvailable},
+		{"Sold", DevelopmentUnit{Status: DevelopmentUnitStatusSold}, now, DevelopmentUnitStatusSold},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.unit.EffectiveStatus(tt.at); got != tt.want {
+				t.Errorf("EffectiveStatus() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// The reservation counts as reserved until it expires
+	units := make([]*DevelopmentUnit, len(tests))
+	for i := range tests {
+		units[i] = &tests[i].unit
+	}
+	if got := CountUnits(units[:3], now); got != (UnitCounts{Total: 3, Available: 1, Reserved: 2}) {
+		t.Errorf("CountUnits() = %+v before the expiry", got)
+	}
+	if got := CountUnits(units[1:], expiresAt); got != (UnitCounts{Total: 3, Available: 2, Sold: 1}) {
+		t.Errorf("CountUnits() = %+v after the expiry", got)
+	}
+}
+
+func TestDevelopmentUnitValidateAndReprice(t *testing.T) {
+	unit := DevelopmentUnit{
+		Number: "1204",
+		Price:  600000,
+		PaymentPlan: &UnitPaymentPlan{
+			DownPayment:         60000,
+			MonthlyInstallments: 36,
+			MonthlyInstallment:  2000,
+			AnnualInstallments:  3,
+			AnnualInstallment:   20000,
+			KeysPayment:         30000,
+		},
+	}
+	if err := unit.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if got := unit.FinancedBalance(); got != 378000 {
+		t.Errorf("FinancedBalance() = %v, want 378000", got)
+	}
+
+	unit.Reprice(5)
+	if unit.Price != 630000 || unit.PaymentPlan.MonthlyInstallment != 2100 || unit.PaymentPlan.Total() != 233100 {
+		t.Errorf("Reprice(5) = price %v, plan %+v", unit.Price, *unit.PaymentPlan)
+	}
+
+	unit.PaymentPlan.KeysPayment = 500000
+	if err := unit.Validate(); err == nil {
+		t.Error("Validate() accepted a payment plan above the price")
+	}
+	if err := (&DevelopmentUnit{Number: " ", Price: 1}).Validate(); err == nil {
+		t.Error("Validate() accepted a unit without number")
+	}
+}
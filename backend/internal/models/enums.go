@@ -132,6 +132,14 @@ const (
 	LeadStatusLost        LeadStatus = "lost"
 )
 
+// OpenLeadStatuses are the statuses of leads still being worked by their broker
+var OpenLeadStatuses = []LeadStatus{
+	LeadStatusNew,
+	LeadStatusContacted,
+	LeadStatusQualified,
+	LeadStatusNegotiating,
+}
+
 // ActorType defines the type of actor performing an action
 type ActorType string
 
@@ -139,4 +147,29 @@ const (
 	ActorTypeUser   ActorType = "user"   // Broker autenticado
 	ActorTypeSystem ActorType = "system" // Job automático
 	ActorTypeOwner  ActorType = "owner"  // Owner confirmando via link
+	ActorTypeLead   ActorType = "lead"   // Lead via link público (visitas)
+)
+
+// Portal defines a listing portal syndicated through the VRSync feed
+type Portal string
+
+const (
+	PortalZAP      Portal = "zap"      // ZAP Imóveis
+	PortalVivaReal Portal = "vivareal" // VivaReal
+	PortalOLX      Portal = "olx"      // OLX
 )
+
+// AllPortals returns every syndicated portal
+func AllPortals() []Portal {
+	return []Portal{PortalZAP, PortalVivaReal, PortalOLX}
+}
+
+// IsValidPortal checks if a portal is syndicated
+func IsValidPortal(portal Portal) bool {
+	for _, p := range AllPortals() {
+		if p == portal {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package models
+
+import "time"
+
+// JobRunStatus defines the outcome of a background job run
+type JobRunStatus string
+
+const (
+	JobRunStatusRunning   JobRunStatus = "running"
+	JobRunStatusSucceeded JobRunStatus = "succeeded"
+	JobRunStatusPartial   JobRunStatus = "partial" // Some tenants failed
+	JobRunStatusFailed    JobRunStatus = "failed"
+)
+
+// JobTrigger defines what started a background job run
+type JobTrigger string
+
+const (
+	JobTriggerSchedule JobTrigger = "schedule"
+	JobTriggerManual   JobTrigger = "manual"
+)
+
+// JobRun records one execution of a background job across every active tenant
+// Collection: /job_runs/{runId}
+type JobRun struct {
+	ID          string       `firestore:"-" json:"id"`
+	Job         string       `firestore:"job" json:"job"`
+	Status      JobRunStatus `firestore:"status" json:"status"`
+	Trigger     JobTrigger   `firestore:"trigger" json:"trigger"`
+	Instance    string       `firestore:"instance" json:"instance"` // Server instance that ran the job
+	ScheduledAt time.Time    `firestore:"scheduled_at" json:"scheduled_at"`
+	StartedAt   time.Time    `firestore:"started_at" json:"started_at"`
+	FinishedAt  *time.Time   `firestore:"finished_at,omitempty" json:"finished_at,omitempty"`
+	DurationMs  int64        `firestore:"duration_ms" json:"duration_ms"`
+
+	// Per-tenant fan-out
+	TenantsTotal     int                `firestore:"tenants_total" json:"tenants_total"`
+	TenantsSucceeded int                `firestore:"tenants_succeeded" json:"tenants_succeeded"`
+	TenantsFailed    int                `firestore:"tenants_failed" json:"tenants_failed"`
+	Failures         []JobTenantFailure `firestore:"failures,omitempty" json:"failures,omitempty"`
+
+	Error string `firestore:"error,omitempty" json:"error,omitempty"` // Failure before the fan-out (e.g. listing tenants)
+}
+
+// JobTenantFailure records the error of a job for a single tenant
+type JobTenantFailure struct {
+	TenantID string `firestore:"tenant_id" json:"tenant_id"`
+	Error    string `firestore:"error" json:"error"`
+}
+
+// JobLock is the distributed lock of a background job, so that only one
+// server instance runs each scheduled occurrence
+// Collection: /job_locks/{job}
+type JobLock struct {
+	Job         string    `firestore:"job" json:"job"`
+	Owner       string    `firestore:"owner" json:"owner"`               // Instance holding the lock
+	LockedUntil time.Time `firestore:"locked_until" json:"locked_until"` // Lease expiry (crashed holders release on expiry)
+	ScheduledAt time.Time `firestore:"scheduled_at" json:"scheduled_at"` // Last occurrence claimed
+}
+
+// CanClaim reports whether the occurrence scheduled at scheduledAt can be
+// claimed: the lease must have expired and the occurrence must be later than
+// the last one claimed, so an occurrence never runs twice
+func (l *JobLock) CanClaim(scheduledAt, now time.Time) bool {
+	if l.LockedUntil.After(now) {
+		return false
+	}
+	return scheduledAt.After(l.ScheduledAt)
+}
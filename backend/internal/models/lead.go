@@ -8,7 +8,8 @@ import "time"
 type Lead struct {
 	ID         string `firestore:"-" json:"id"`
 	TenantID   string `firestore:"tenant_id" json:"tenant_id"`
-	PropertyID string `firestore:"property_id" json:"property_id"` // ref Property (OBRIGATÓRIO)
+	PropertyID string `firestore:"property_id" json:"property_id"`                   // ref Property (OBRIGATÓRIO)
+	ContactID  string `firestore:"contact_id,omitempty" json:"contact_id,omitempty"` // ref Contact (mesma pessoa em várias inquiries)
 
 	// Dados do interessado (MÍNIMOS)
 	Name    string `firestore:"name,omitempty" json:"name,omitempty"`
@@ -24,7 +25,19 @@ type Lead struct {
 	Referrer    string      `firestore:"referrer,omitempty" json:"referrer,omitempty"` // URL da página
 
 	// Status
-	Status LeadStatus `firestore:"status" json:"status"` // new, contacted, qualified, lost
+	Status LeadStatus `firestore:"status" json:"status"` // new, contacted, qualified, negotiating, converted, lost
+
+	// Roteamento (corretor responsável)
+	AssignedBrokerID string              `firestore:"assigned_broker_id,omitempty" json:"assigned_broker_id,omitempty"`
+	AssignedAt       *time.Time          `firestore:"assigned_at,omitempty" json:"assigned_at,omitempty"`
+	RoutingStrategy  LeadRoutingStrategy `firestore:"routing_strategy,omitempty" json:"routing_strategy,omitempty"` // Strategy that chose the broker (manual = by hand)
+	RoutingReason    string              `firestore:"routing_reason,omitempty" json:"routing_reason,omitempty"`     // Why the broker was chosen (or why none was)
+
+	// SLA de primeiro atendimento
+	ResponseDueAt   *time.Time          `firestore:"response_due_at,omitempty" json:"response_due_at,omitempty"`     // First-response target of the assigned broker
+	FirstResponseAt *time.Time          `firestore:"first_response_at,omitempty" json:"first_response_at,omitempty"` // First status change away from new
+	SLAStatus       LeadSLAStatus       `firestore:"sla_status,omitempty" json:"sla_status,omitempty"`               // pending, met, missed, breached
+	SLAEscalations  []LeadSLAEscalation `firestore:"sla_escalations,omitempty" json:"sla_escalations,omitempty"`
 
 	// LGPD - Consentimento (AI_DEV_DIRECTIVE Seção 21)
 	// OBRIGATÓRIO: consent_given DEVE ser true para criar lead
@@ -0,0 +1,129 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// LeadNote is a free-text note written by a broker about a lead
+// Collection: /tenants/{tenantId}/lead_notes/{noteId}
+type LeadNote struct {
+	ID       string `firestore:"-" json:"id"`
+	TenantID string `firestore:"tenant_id" json:"tenant_id"`
+	LeadID   string `firestore:"lead_id" json:"lead_id"`
+
+	AuthorID string `firestore:"author_id,omitempty" json:"author_id,omitempty"` // Broker or user that wrote the note
+	Body     string `firestore:"body" json:"body"`
+
+	// Metadata
+	CreatedAt time.Time `firestore:"created_at" json:"created_at"`
+	UpdatedAt time.Time `firestore:"updated_at" json:"updated_at"`
+}
+
+// LeadTaskStatus defines the status of a follow-up task
+type LeadTaskStatus string
+
+const (
+	LeadTaskStatusOpen      LeadTaskStatus = "open"
+	LeadTaskStatusDone      LeadTaskStatus = "done"
+	LeadTaskStatusCancelled LeadTaskStatus = "cancelled"
+)
+
+// LeadTaskReminderStatus tracks the reminder of a follow-up task
+type LeadTaskReminderStatus string
+
+const (
+	LeadTaskReminderPending LeadTaskReminderStatus = "pending" // Sent at RemindAt
+	LeadTaskReminderSent    LeadTaskReminderStatus = "sent"
+	LeadTaskReminderFailed  LeadTaskReminderStatus = "failed" // See ReminderError
+)
+
+// LeadTask is a follow-up task on a lead, with a due date and an optional
+// reminder sent to the assigned broker
+// Collection: /tenants/{tenantId}/lead_tasks/{taskId}
+type LeadTask struct {
+	ID       string `firestore:"-" json:"id"`
+	TenantID string `firestore:"tenant_id" json:"tenant_id"`
+	LeadID   string `firestore:"lead_id" json:"lead_id"`
+
+	Title       string         `firestore:"title" json:"title"`
+	Description string         `firestore:"description,omitempty" json:"description,omitempty"`
+	AssignedTo  string         `firestore:"assigned_to,omitempty" json:"assigned_to,omitempty"` // Broker ID (default: broker of the lead)
+	CreatedBy   string         `firestore:"created_by,omitempty" json:"created_by,omitempty"`
+	Status      LeadTaskStatus `firestore:"status" json:"status"`
+	DueAt       time.Time      `firestore:"due_at" json:"due_at"`
+	CompletedAt *time.Time     `firestore:"completed_at,omitempty" json:"completed_at,omitempty"` // Done or cancelled
+
+	// Lembrete
+	RemindAt       *time.Time             `firestore:"remind_at,omitempty" json:"remind_at,omitempty"`
+	ReminderStatus LeadTaskReminderStatus `firestore:"reminder_status,omitempty" json:"reminder_status,omitempty"` // Empty without reminder
+	ReminderSentAt *time.Time             `firestore:"reminder_sent_at,omitempty" json:"reminder_sent_at,omitempty"`
+	ReminderError  string                 `firestore:"reminder_error,omitempty" json:"reminder_error,omitempty"`
+
+	// Metadata
+	CreatedAt time.Time `firestore:"created_at" json:"created_at"`
+	UpdatedAt time.Time `firestore:"updated_at" json:"updated_at"`
+}
+
+// IsOverdue reports whether an open task is past its due date
+func (t *LeadTask) IsOverdue(now time.Time) bool {
+	return t.Status == LeadTaskStatusOpen && now.After(t.DueAt)
+}
+
+// LeadInteractionType defines the kind of contact logged with a lead
+type LeadInteractionType string
+
+const (
+	LeadInteractionCall     LeadInteractionType = "call"
+	LeadInteractionVisit    LeadInteractionType = "visit"
+	LeadInteractionWhatsApp LeadInteractionType = "whatsapp"
+	LeadInteractionEmail    LeadInteractionType = "email"
+	LeadInteractionSMS      LeadInteractionType = "sms"
+	LeadInteractionMeeting  LeadInteractionType = "meeting"
+)
+
+// LeadInteractionDirection tells who started an interaction
+type LeadInteractionDirection string
+
+const (
+	LeadInteractionOutbound LeadInteractionDirection = "outbound" // Broker contacted the lead
+	LeadInteractionInbound  LeadInteractionDirection = "inbound"  // Lead contacted the broker
+)
+
+// LeadInteraction is a call, visit or message exchanged with a lead
+// Collection: /tenants/{tenantId}/lead_interactions/{interactionId}
+type LeadInteraction struct {
+	ID       string `firestore:"-" json:"id"`
+	TenantID string `firestore:"tenant_id" json:"tenant_id"`
+	LeadID   string `firestore:"lead_id" json:"lead_id"`
+
+	Type            LeadInteractionType      `firestore:"type" json:"type"`
+	Direction       LeadInteractionDirection `firestore:"direction" json:"direction"`
+	BrokerID        string                   `firestore:"broker_id,omitempty" json:"broker_id,omitempty"` // Broker or user that logged it
+	OccurredAt      time.Time                `firestore:"occurred_at" json:"occurred_at"`
+	DurationMinutes int                      `firestore:"duration_minutes,omitempty" json:"duration_minutes,omitempty"`
+	Outcome         string                   `firestore:"outcome,omitempty" json:"outcome,omitempty"` // ex: "no_answer", "scheduled_visit"
+	Summary         string                   `firestore:"summary,omitempty" json:"summary,omitempty"`
+
+	// Metadata
+	CreatedAt time.Time `firestore:"created_at" json:"created_at"`
+}
+
+// Validate checks the type, direction and duration of the interaction
+func (i *LeadInteraction) Validate() error {
+	switch i.Type {
+	case LeadInteractionCall, LeadInteractionVisit, LeadInteractionWhatsApp,
+		LeadInteractionEmail, LeadInteractionSMS, LeadInteractionMeeting:
+	default:
+		return fmt.Errorf("invalid interaction type %q (expected call, visit, whatsapp, email, sms or meeting)", i.Type)
+	}
+	switch i.Direction {
+	case LeadInteractionOutbound, LeadInteractionInbound:
+	default:
+		return fmt.Errorf("invalid interaction direction %q (expected outbound or inbound)", i.Direction)
+	}
+	if i.DurationMinutes < 0 {
+		return fmt.Errorf("duration_minutes cannot be negative")
+	}
+	return nil
+}
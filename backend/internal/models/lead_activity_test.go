@@ -0,0 +1,54 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLeadInteractionValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		interaction LeadInteraction
+		wantErr     bool
+	}{
+		{"Outbound call", LeadInteraction{Type: LeadInteractionCall, Direction: LeadInteractionOutbound, DurationMinutes: 5}, false},
+		{"Inbound WhatsApp", LeadInteraction{Type: LeadInteractionWhatsApp, Direction: LeadInteractionInbound}, false},
+		{"Unknown type", LeadInteraction{Type: "fax", Direction: LeadInteractionOutbound}, true},
+		{"Missing direction", LeadInteraction{Type: LeadInteractionVisit}, true},
+		{"Negative duration", LeadInteraction{Type: LeadInteractionCall, Direction: LeadInteractionOutbound, DurationMinutes: -1}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.interaction.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLeadTaskIsOverdue(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		status LeadTaskStatus
+		dueAt  time.Time
+		want   bool
+	}{
+		{"Open past due", LeadTaskStatusOpen, now.Add(-time.Hour), true},
+		{"Open not due", LeadTaskStatusOpen, now.Add(time.Hour), false},
+		{"Done past due", LeadTaskStatusDone, now.Add(-time.Hour), false},
+		{"Cancelled past due", LeadTaskStatusCancelled, now.Add(-time.Hour), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := &LeadTask{Status: tt.status, DueAt: tt.dueAt}
+			if got := task.IsOverdue(now); got != tt.want {
+				t.Errorf("IsOverdue() = %v, expected %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package models
+
+import "fmt"
+
+// LeadRoutingStrategy selects the broker that receives a new lead
+type LeadRoutingStrategy string
+
+const (
+	LeadRoutingPrimaryBroker  LeadRoutingStrategy = "primary_broker"   // Primary (or originating) broker of the property
+	LeadRoutingRoundRobin     LeadRoutingStrategy = "round_robin"      // Next broker of the pool, in turn
+	LeadRoutingLeastOpenLeads LeadRoutingStrategy = "least_open_leads" // Broker of the pool with fewest open leads
+	LeadRoutingTerritory      LeadRoutingStrategy = "territory"        // Broker of the rule matching the property's neighborhood/city
+	LeadRoutingManual         LeadRoutingStrategy = "manual"           // Assigned by hand (never configured)
+)
+
+// LeadRoutingSettings configures how a tenant routes new leads
+type LeadRoutingSettings struct {
+	Strategy LeadRoutingStrategy `firestore:"strategy" json:"strategy"`
+
+	// Strategy used when Strategy finds no broker (default primary_broker)
+	Fallback LeadRoutingStrategy `firestore:"fallback,omitempty" json:"fallback,omitempty"`
+
+	// Brokers taking part in round_robin and least_open_leads (empty = every active broker)
+	BrokerIDs []string `firestore:"broker_ids,omitempty" json:"broker_ids,omitempty"`
+
+	// Territory rules, checked neighborhood rules first
+	Territories []TerritoryRule `firestore:"territories,omitempty" json:"territories,omitempty"`
+}
+
+// TerritoryRule routes the leads of properties in a city (optionally a
+// neighborhood of it) to a group of brokers; the one with fewest open leads
+// receives each lead
+type TerritoryRule struct {
+	City         string   `firestore:"city" json:"city"`
+	Neighborhood string   `firestore:"neighborhood,omitempty" json:"neighborhood,omitempty"` // empty = whole city
+	BrokerIDs    []string `firestore:"broker_ids" json:"broker_ids"`
+}
+
+// DefaultLeadRouting is the routing of tenants without settings
+var DefaultLeadRouting = LeadRoutingSettings{Strategy: LeadRoutingPrimaryBroker}
+
+// EffectiveLeadRouting returns the tenant's lead routing settings, or the
+// defaults when none are configured
+func (t *Tenant) EffectiveLeadRouting() LeadRoutingSettings {
+	if t == nil || t.LeadRouting == nil || t.LeadRouting.Strategy == "" {
+		return DefaultLeadRouting
+	}
+	return *t.LeadRouting
+}
+
+// FallbackStrategy returns the strategy used when Strategy finds no broker
+func (s LeadRoutingSettings) FallbackStrategy() LeadRoutingStrategy {
+	if s.Fallback == "" {
+		return LeadRoutingPrimaryBroker
+	}
+	return s.Fallback
+}
+
+// Validate checks the strategies and territory rules of the settings
+func (s LeadRoutingSettings) Validate() error {
+	if !isRoutingStrategy(s.Strategy) {
+		return fmt.Errorf("strategy: invalid strategy %q (expected primary_broker, round_robin, least_open_leads or territory)", s.Strategy)
+	}
+	if s.Fallback != "" && (!isRoutingStrategy(s.Fallback) || s.Fallback == LeadRoutingTerritory) {
+		return fmt.Errorf("fallback: invalid strategy %q (expected primary_broker, round_robin or least_open_leads)", s.Fallback)
+	}
+
+	if s.Strategy == LeadRoutingTerritory && len(s.Territories) == 0 {
+		return fmt.Errorf("territories: at least one rule is required by the territory strategy")
+	}
+	for i, rule := range s.Territories {
+		if rule.City == "" {
+			return fmt.Errorf("territories[%d]: city is required", i)
+		}
+		if len(rule.BrokerIDs) == 0 {
+			return fmt.Errorf("territories[%d]: at least one broker is required", i)
+		}
+	}
+	return nil
+}
+
+func isRoutingStrategy(strategy LeadRoutingStrategy) bool {
+	switch strategy {
+	case LeadRoutingPrimaryBroker, LeadRoutingRoundRobin, LeadRoutingLeastOpenLeads, LeadRoutingTerritory:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,47 @@
+package models
+
+import "testing"
+
+func TestLeadRoutingSettingsValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings LeadRoutingSettings
+		wantErr  bool
+	}{
+		{"Default", DefaultLeadRouting, false},
+		{"Round-robin pool", LeadRoutingSettings{Strategy: LeadRoutingRoundRobin, BrokerIDs: []string{"b1", "b2"}}, false},
+		{"Least open leads with fallback", LeadRoutingSettings{Strategy: LeadRoutingLeastOpenLeads, Fallback: LeadRoutingRoundRobin}, false},
+		{"Territory", LeadRoutingSettings{Strategy: LeadRoutingTerritory, Territories: []TerritoryRule{{City: "São Paulo", Neighborhood: "Moema", BrokerIDs: []string{"b1"}}}}, false},
+		{"Empty strategy", LeadRoutingSettings{}, true},
+		{"Manual is not a strategy", LeadRoutingSettings{Strategy: LeadRoutingManual}, true},
+		{"Territory fallback", LeadRoutingSettings{Strategy: LeadRoutingRoundRobin, Fallback: LeadRoutingTerritory}, true},
+		{"Territory without rules", LeadRoutingSettings{Strategy: LeadRoutingTerritory}, true},
+		{"Rule without city", LeadRoutingSettings{Strategy: LeadRoutingTerritory, Territories: []TerritoryRule{{Neighborhood: "Moema", BrokerIDs: []string{"b1"}}}}, true},
+		{"Rule without brokers", LeadRoutingSettings{Strategy: LeadRoutingTerritory, Territories: []TerritoryRule{{City: "São Paulo"}}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.settings.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTenantEffectiveLeadRouting(t *testing.T) {
+	var tenant *Tenant
+	if got := tenant.EffectiveLeadRouting(); got.Strategy != LeadRoutingPrimaryBroker {
+		t.Errorf("nil tenant Strategy = %s, expected %s", got.Strategy, LeadRoutingPrimaryBroker)
+	}
+
+	tenant = &Tenant{LeadRouting: &LeadRoutingSettings{Strategy: LeadRoutingRoundRobin}}
+	got := tenant.EffectiveLeadRouting()
+	if got.Strategy != LeadRoutingRoundRobin {
+		t.Errorf("Strategy = %s, expected %s", got.Strategy, LeadRoutingRoundRobin)
+	}
+	if got.FallbackStrategy() != LeadRoutingPrimaryBroker {
+		t.Errorf("FallbackStrategy() = %s, expected %s", got.FallbackStrategy(), LeadRoutingPrimaryBroker)
+	}
+}
@@ -0,0 +1,137 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultLeadResponseMinutes is the first-response target of tenants without
+// lead SLA settings
+const DefaultLeadResponseMinutes = 60
+
+// LeadSLAStatus tracks a lead against its first-response target
+type LeadSLAStatus string
+
+const (
+	LeadSLAStatusPending  LeadSLAStatus = "pending"  // Waiting for the first response, within the target
+	LeadSLAStatusMet      LeadSLAStatus = "met"      // First response within the target
+	LeadSLAStatusMissed   LeadSLAStatus = "missed"   // First response after the target
+	LeadSLAStatusBreached LeadSLAStatus = "breached" // Target passed without a response (escalated)
+)
+
+// LeadSLAAction is what happens when a new lead is not contacted in time
+type LeadSLAAction string
+
+const (
+	LeadSLAActionNotifyManager LeadSLAAction = "notify_manager" // Message the tenant's managers
+	LeadSLAActionReassign      LeadSLAAction = "reassign"       // Route the lead to another broker, with a new target
+)
+
+// LeadSLASettings configures the first-response targets of a tenant's leads
+type LeadSLASettings struct {
+	// Target for every channel without an override (default 60)
+	ResponseMinutes int `firestore:"response_minutes" json:"response_minutes"`
+
+	// Targets by channel, e.g. {"whatsapp": 15}
+	ChannelResponseMinutes map[LeadChannel]int `firestore:"channel_response_minutes,omitempty" json:"channel_response_minutes,omitempty"`
+
+	// Actions run when a target passes (nil = notify_manager; empty = none)
+	Actions []LeadSLAAction `firestore:"actions" json:"actions"`
+
+	// Reassignments of a single lead before only managers are notified (default 1)
+	MaxReassignments int `firestore:"max_reassignments" json:"max_reassignments"`
+
+	// Users notified of breaches (empty = every active admin and manager)
+	ManagerUserIDs []string `firestore:"manager_user_ids,omitempty" json:"manager_user_ids,omitempty"`
+}
+
+// LeadSLAEscalation records a first-response target that passed
+type LeadSLAEscalation struct {
+	BrokerID      string          `firestore:"broker_id,omitempty" json:"broker_id,omitempty"` // Broker that missed the target
+	DueAt         time.Time       `firestore:"due_at" json:"due_at"`
+	EscalatedAt   time.Time       `firestore:"escalated_at" json:"escalated_at"`
+	Actions       []LeadSLAAction `firestore:"actions" json:"actions"`
+	NewBrokerID   string          `firestore:"new_broker_id,omitempty" json:"new_broker_id,omitempty"` // Set when the lead was reassigned
+	NotifiedUsers []string        `firestore:"notified_users,omitempty" json:"notified_users,omitempty"`
+	Error         string          `firestore:"error,omitempty" json:"error,omitempty"`
+}
+
+// DefaultLeadSLA returns the lead SLA settings of tenants that never configured them
+func DefaultLeadSLA() LeadSLASettings {
+	return LeadSLASettings{
+		ResponseMinutes:  DefaultLeadResponseMinutes,
+		Actions:          []LeadSLAAction{LeadSLAActionNotifyManager},
+		MaxReassignments: 1,
+	}
+}
+
+// EffectiveLeadSLA returns the tenant's lead SLA settings with defaults applied
+func (t *Tenant) EffectiveLeadSLA() LeadSLASettings {
+	if t == nil || t.LeadSLA == nil {
+		return DefaultLeadSLA()
+	}
+	return t.LeadSLA.WithDefaults()
+}
+
+// WithDefaults returns the settings with unset fields replaced by the defaults
+func (s LeadSLASettings) WithDefaults() LeadSLASettings {
+	defaults := DefaultLeadSLA()
+	if s.ResponseMinutes == 0 {
+		s.ResponseMinutes = defaults.ResponseMinutes
+	}
+	if s.Actions == nil {
+		s.Actions = defaults.Actions
+	}
+	if s.MaxReassignments == 0 {
+		s.MaxReassignments = defaults.MaxReassignments
+	}
+	return s
+}
+
+// ResponseTarget returns the first-response target of a channel
+func (s LeadSLASettings) ResponseTarget(channel LeadChannel) time.Duration {
+	minutes := s.ResponseMinutes
+	if override, ok := s.ChannelResponseMinutes[channel]; ok && override > 0 {
+		minutes = override
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// HasAction reports whether the settings run an action on breach
+func (s LeadSLASettings) HasAction(action LeadSLAAction) bool {
+	for _, a := range s.Actions {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks the targets and actions; call it on settings with defaults applied
+func (s LeadSLASettings) Validate() error {
+	const maxMinutes = 7 * 24 * 60
+	if s.ResponseMinutes < 1 || s.ResponseMinutes > maxMinutes {
+		return fmt.Errorf("response_minutes must be between 1 and %d", maxMinutes)
+	}
+	for channel, minutes := range s.ChannelResponseMinutes {
+		switch channel {
+		case LeadChannelWhatsApp, LeadChannelForm, LeadChannelPhone, LeadChannelEmail:
+		default:
+			return fmt.Errorf("channel_response_minutes: unknown channel %q", channel)
+		}
+		if minutes < 1 || minutes > maxMinutes {
+			return fmt.Errorf("channel_response_minutes[%s] must be between 1 and %d", channel, maxMinutes)
+		}
+	}
+	for i, action := range s.Actions {
+		switch action {
+		case LeadSLAActionNotifyManager, LeadSLAActionReassign:
+		default:
+			return fmt.Errorf("actions[%d]: invalid action %q (expected notify_manager or reassign)", i, action)
+		}
+	}
+	if s.MaxReassignments < 1 || s.MaxReassignments > 10 {
+		return fmt.Errorf("max_reassignments must be between 1 and 10")
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLeadSLASettingsValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings LeadSLASettings
+		wantErr  bool
+	}{
+		{"Default", DefaultLeadSLA(), false},
+		{"Channel targets", LeadSLASettings{ResponseMinutes: 30, ChannelResponseMinutes: map[LeadChannel]int{LeadChannelWhatsApp: 5}}.WithDefaults(), false},
+		{"Reassign and notify", LeadSLASettings{Actions: []LeadSLAAction{LeadSLAActionReassign, LeadSLAActionNotifyManager}}.WithDefaults(), false},
+		{"No actions", LeadSLASettings{Actions: []LeadSLAAction{}}.WithDefaults(), false},
+		{"Negative target", LeadSLASettings{ResponseMinutes: -5}.WithDefaults(), true},
+		{"Target over a week", LeadSLASettings{ResponseMinutes: 7*24*60 + 1}.WithDefaults(), true},
+		{"Unknown channel", LeadSLASettings{ChannelResponseMinutes: map[LeadChannel]int{"telegram": 10}}.WithDefaults(), true},
+		{"Zero channel target", LeadSLASettings{ChannelResponseMinutes: map[LeadChannel]int{LeadChannelPhone: 0}}.WithDefaults(), true},
+		{"Unknown action", LeadSLASettings{Actions: []LeadSLAAction{"call_owner"}}.WithDefaults(), true},
+		{"Too many reassignments", LeadSLASettings{MaxReassignments: 11}.WithDefaults(), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.settings.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLeadSLASettingsResponseTarget(t *testing.T) {
+	var tenant *Tenant
+	if got := tenant.EffectiveLeadSLA().ResponseTarget(LeadChannelForm); got != time.Hour {
+		t.Errorf("default ResponseTarget() = %s, expected 1h", got)
+	}
+
+	settings := LeadSLASettings{
+		ResponseMinutes:        30,
+		ChannelResponseMinutes: map[LeadChannel]int{LeadChannelWhatsApp: 10},
+	}.WithDefaults()
+	if got := settings.ResponseTarget(LeadChannelWhatsApp); got != 10*time.Minute {
+		t.Errorf("ResponseTarget(whatsapp) = %s, expected 10m", got)
+	}
+	if got := settings.ResponseTarget(LeadChannelEmail); got != 30*time.Minute {
+		t.Errorf("ResponseTarget(email) = %s, expected 30m", got)
+	}
+	if !settings.HasAction(LeadSLAActionNotifyManager) || settings.HasAction(LeadSLAActionReassign) {
+		t.Errorf("default Actions = %v, expected [notify_manager]", settings.Actions)
+	}
+}
@@ -0,0 +1,115 @@
+package models
+
+import (
+	"fmt"
+	"text/template"
+)
+
+// NotificationChannel defines an outbound delivery channel
+type NotificationChannel string
+
+const (
+	NotificationChannelWhatsApp NotificationChannel = "whatsapp"
+	NotificationChannelSMS      NotificationChannel = "sms"
+	NotificationChannelEmail    NotificationChannel = "email"
+)
+
+// Delivery statuses of outbound messages (ScheduledConfirmation.DeliveryStatus),
+// in the order they progress
+const (
+	DeliveryStatusManualRequired = "manual_delivery_required" // No channel available, broker sends the link
+	DeliveryStatusAccepted       = "accepted"                 // Accepted by the provider
+	DeliveryStatusSent           = "sent"                     // Sent to the carrier / mail server
+	DeliveryStatusDelivered      = "delivered"                // Delivered to the recipient
+	DeliveryStatusRead           = "read"                     // Read by the recipient (WhatsApp)
+	DeliveryStatusFailed         = "failed"
+)
+
+// Notification template names
+const (
+	NotificationTemplateOwnerConfirmation = "owner_confirmation"
+	NotificationTemplateCaptadorReminder  = "captador_reminder"  // Owner did not answer the confirmation
+	NotificationTemplateLeadSLABreach     = "lead_sla_breach"    // New lead not contacted in time
+	NotificationTemplateLeadTaskReminder  = "lead_task_reminder" // Follow-up task of a lead is due
+	NotificationTemplateVisitRequested    = "visit_requested"    // Lead asked for a visit (to the broker)
+	NotificationTemplateVisitConfirmed    = "visit_confirmed"    // Broker confirmed the visit (to the lead)
+	NotificationTemplateVisitCancelled    = "visit_cancelled"    // Visit cancelled (to the other party)
+	NotificationTemplateProposalReceived  = "proposal_received"  // Offer or buyer counter-offer (to the owner)
+	NotificationTemplateProposalAccepted  = "proposal_accepted"  // Proposal accepted (to the owner and the lead)
+)
+
+// NotificationSettings configures the outbound messages of a tenant
+type NotificationSettings struct {
+	// Channels in order of preference (default: whatsapp, sms, email)
+	Channels []NotificationChannel `firestore:"channels,omitempty" json:"channels,omitempty"`
+
+	// Message templates by name (e.g. "owner_confirmation"); missing ones use the defaults
+	Templates map[string]NotificationTemplate `firestore:"templates,omitempty" json:"templates,omitempty"`
+}
+
+// NotificationTemplate is a message template (Go text/template syntax)
+type NotificationTemplate struct {
+	Subject string `firestore:"subject,omitempty" json:"subject,omitempty"` // Email subject
+	Body    string `firestore:"body,omitempty" json:"body,omitempty"`       // Plain text body (WhatsApp, SMS, email)
+
+	// Approved WhatsApp Business template used instead of Body for
+	// business-initiated conversations. Body parameters: owner name,
+	// property reference and confirmation URL (owner_confirmation); captador
+	// name, property reference and owner name (captador_reminder); manager
+	// name, lead name and property reference (lead_sla_breach); broker name,
+	// task title and lead name (lead_task_reminder); recipient name, property
+	// reference and visit date (visit_requested, visit_confirmed,
+	// visit_cancelled); recipient name, property reference and amount
+	// (proposal_received, proposal_accepted)
+	WhatsAppTemplate string `firestore:"whatsapp_template,omitempty" json:"whatsapp_template,omitempty"`
+	WhatsAppLanguage string `firestore:"whatsapp_language,omitempty" json:"whatsapp_language,omitempty"` // default pt_BR
+}
+
+// DefaultNotificationChannels is the channel preference of tenants without settings
+var DefaultNotificationChannels = []NotificationChannel{
+	NotificationChannelWhatsApp,
+	NotificationChannelSMS,
+	NotificationChannelEmail,
+}
+
+// NotificationChannels returns the tenant's channels in order of preference
+func (t *Tenant) NotificationChannels() []NotificationChannel {
+	if t == nil || t.Notifications == nil || len(t.Notifications.Channels) == 0 {
+		return DefaultNotificationChannels
+	}
+	return t.Notifications.Channels
+}
+
+// NotificationTemplate returns the tenant's template with the given name
+func (t *Tenant) NotificationTemplate(name string) (NotificationTemplate, bool) {
+	if t == nil || t.Notifications == nil {
+		return NotificationTemplate{}, false
+	}
+	tmpl, ok := t.Notifications.Templates[name]
+	return tmpl, ok
+}
+
+// Validate checks the channels and template syntax of the settings
+func (n NotificationSettings) Validate() error {
+	seen := make(map[NotificationChannel]bool, len(n.Channels))
+	for _, channel := range n.Channels {
+		switch channel {
+		case NotificationChannelWhatsApp, NotificationChannelSMS, NotificationChannelEmail:
+		default:
+			return fmt.Errorf("unknown channel %q", channel)
+		}
+		if seen[channel] {
+			return fmt.Errorf("duplicate channel %q", channel)
+		}
+		seen[channel] = true
+	}
+
+	for name, tmpl := range n.Templates {
+		for _, text := range []string{tmpl.Subject, tmpl.Body} {
+			if _, err := template.New(name).Parse(text); err != nil {
+				return fmt.Errorf("template %s: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
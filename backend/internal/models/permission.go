@@ -0,0 +1,130 @@
+package models
+
+// Permission catalog
+// Permissions are checked per route by the admin API authorization middleware.
+// A member's effective permissions are the defaults of its role plus any
+// permissions granted explicitly (User.Permissions).
+const (
+	// Properties, listings, images and property-broker assignments
+	PermissionPropertiesView   = "properties.view"
+	PermissionPropertiesCreate = "properties.create"
+	PermissionPropertiesEdit   = "properties.edit"
+	PermissionPropertiesDelete = "properties.delete"
+
+	// Owners (proprietários)
+	PermissionOwnersView   = "owners.view"
+	PermissionOwnersEdit   = "owners.edit"
+	PermissionOwnersDelete = "owners.delete" // includes LGPD anonymization
+
+	// Leads
+	PermissionLeadsView   = "leads.view"
+	PermissionLeadsEdit   = "leads.edit"
+	PermissionLeadsDelete = "leads.delete" // includes LGPD anonymization
+
+	// Brokers and administrative users
+	PermissionBrokersView   = "brokers.view"
+	PermissionBrokersManage = "brokers.manage"
+	PermissionUsersView     = "users.view"
+	PermissionUsersManage   = "users.manage" // includes invitations and permission grants
+
+	// Operations
+	PermissionActivityLogsView    = "activity_logs.view"
+	PermissionImportRun           = "import.run"
+	PermissionConfirmationsManage = "confirmations.manage"
+	PermissionSettingsView        = "settings.view"
+	PermissionSettingsEdit        = "settings.edit"
+
+	// Finance: commission ledger
+	PermissionFinanceView   = "finance.view"
+	PermissionFinanceManage = "finance.manage" // record deals and settle payments
+)
+
+// AllPermissions returns every permission in the catalog
+func AllPermissions() []string {
+	return []string{
+		PermissionPropertiesView,
+		PermissionPropertiesCreate,
+		PermissionPropertiesEdit,
+		PermissionPropertiesDelete,
+		PermissionOwnersView,
+		PermissionOwnersEdit,
+		PermissionOwnersDelete,
+		PermissionLeadsView,
+		PermissionLeadsEdit,
+		PermissionLeadsDelete,
+		PermissionBrokersView,
+		PermissionBrokersManage,
+		PermissionUsersView,
+		PermissionUsersManage,
+		PermissionActivityLogsView,
+		PermissionImportRun,
+		PermissionConfirmationsManage,
+		PermissionSettingsView,
+		PermissionSettingsEdit,
+		PermissionFinanceView,
+		PermissionFinanceManage,
+	}
+}
+
+// IsValidPermission checks if a permission exists in the catalog
+func IsValidPermission(permission string) bool {
+	for _, p := range AllPermissions() {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultPermissionsForRole returns the permissions a role has without explicit grants
+// - "admin" and "broker_admin": every permission
+// - "manager": day-to-day operation, without deletions, user management or settings changes
+// - "broker": works properties, owners and leads, read-only elsewhere (no finance)
+func DefaultPermissionsForRole(role string) []string {
+	switch role {
+	case "admin", "broker_admin":
+		return AllPermissions()
+	case "manager":
+		return []string{
+			PermissionPropertiesView,
+			PermissionPropertiesCreate,
+			PermissionPropertiesEdit,
+			PermissionOwnersView,
+			PermissionOwnersEdit,
+			PermissionLeadsView,
+			PermissionLeadsEdit,
+			PermissionBrokersView,
+			PermissionBrokersManage,
+			PermissionUsersView,
+			PermissionActivityLogsView,
+			PermissionImportRun,
+			PermissionConfirmationsManage,
+			PermissionSettingsView,
+			PermissionFinanceView,
+		}
+	case "broker":
+		return []string{
+			PermissionPropertiesView,
+			PermissionPropertiesCreate,
+			PermissionPropertiesEdit,
+			PermissionOwnersView,
+			PermissionOwnersEdit,
+			PermissionLeadsView,
+			PermissionLeadsEdit,
+			PermissionBrokersView,
+			PermissionSettingsView,
+		}
+	default:
+		return []string{}
+	}
+}
+
+// RoleHasPermission checks if a role grants a permission by default
+func RoleHasPermission(role, permission string) bool {
+	for _, p := range DefaultPermissionsForRole(role) {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/utils"
+)
 
 // Property represents a physical real estate asset (imóvel)
 // Collection: /tenants/{tenantId}/properties/{propertyId}
@@ -33,6 +37,12 @@ type Property struct {
 	ZipCode      string       `firestore:"zip_code,omitempty" json:"zip_code,omitempty"`
 	Country      string       `firestore:"country" json:"country"` // default "BR"
 
+	// Geolocalização (busca por raio / mapa)
+	Latitude   *float64 `firestore:"latitude,omitempty" json:"latitude,omitempty"`
+	Longitude  *float64 `firestore:"longitude,omitempty" json:"longitude,omitempty"`
+	Geohash    string   `firestore:"geohash,omitempty" json:"geohash,omitempty"` // derivado de latitude/longitude (SetCoordinates)
+	DistanceKm *float64 `firestore:"-" json:"distance_km,omitempty"`             // Computed field for radius searches
+
 	// Características
 	Bedrooms      int     `firestore:"bedrooms,omitempty" json:"bedrooms,omitempty"`
 	Bathrooms     int     `firestore:"bathrooms,omitempty" json:"bathrooms,omitempty"`
@@ -48,11 +58,21 @@ type Property struct {
 	Status            PropertyStatus `firestore:"status" json:"status"` // available, unavailable, pending_confirmation
 	StatusConfirmedAt *time.Time     `firestore:"status_confirmed_at,omitempty" json:"status_confirmed_at,omitempty"`
 
+	// Última alteração de preço (histórico completo em properties/{id}/history)
+	PreviousPriceAmount float64    `firestore:"previous_price_amount,omitempty" json:"previous_price_amount,omitempty"`
+	PriceChangedAt      *time.Time `firestore:"price_changed_at,omitempty" json:"price_changed_at,omitempty"`
+	PriceReducedPercent float64    `firestore:"price_reduced_percent,omitempty" json:"price_reduced_percent,omitempty"` // "preço reduzido X%" no portal público
+
 	// Visibilidade e Co-corretagem (AI_DEV_DIRECTIVE Seção 20)
-	Visibility         PropertyVisibility `firestore:"visibility" json:"visibility"`                             // private, network, marketplace, public
-	VisibilityPublic   PropertyVisibility `firestore:"visibility_public" json:"visibility_public"`               // DEPRECATED: usar apenas Visibility
-	CoBrokerCommission float64            `firestore:"co_broker_commission" json:"co_broker_commission"`         // % oferecida para selling_broker (ex: 40.0 = 40%)
-	PendingReason      string             `firestore:"pending_reason,omitempty" json:"pending_reason,omitempty"` // stale_status, stale_price, owner_reported
+	Visibility         PropertyVisibility `firestore:"visibility" json:"visibility"`                                     // private, network, marketplace, public
+	VisibilityPublic   PropertyVisibility `firestore:"visibility_public" json:"visibility_public"`                       // DEPRECATED: usar apenas Visibility
+	CoBrokerCommission float64            `firestore:"co_broker_commission" json:"co_broker_commission"`                 // % oferecida para selling_broker (ex: 40.0 = 40%)
+	PendingReason      string             `firestore:"pending_reason,omitempty" json:"pending_reason,omitempty"`         // stale_status, stale_price, owner_reported, owner_unresponsive
+	UnavailableReason  string             `firestore:"unavailable_reason,omitempty" json:"unavailable_reason,omitempty"` // sold, rented
+
+	// Syndication em portais (feed VRSync por tenant)
+	// Publicado apenas com Visibility public/marketplace e status available
+	Portals PortalFlags `firestore:"portals" json:"portals"`
 
 	// Canonical Listing
 	CanonicalListingID string  `firestore:"canonical_listing_id,omitempty" json:"canonical_listing_id,omitempty"` // ref Listing
@@ -78,7 +98,7 @@ type Property struct {
 	TransactionType *TransactionType `firestore:"transaction_type,omitempty" json:"transaction_type,omitempty"` // sale, rent, both (default: sale no MVP)
 	RentalInfo      *RentalInfo      `firestore:"rental_info,omitempty" json:"rental_info,omitempty"`           // Informações de locação
 
-	// RESERVADO: Gestão de Contratos (MVP+4)
+	// Gestão de Contratos (mantidos pelo RentalContractService)
 	CurrentContractID  *string    `firestore:"current_contract_id,omitempty" json:"current_contract_id,omitempty"`   // ref RentalContract
 	ContractHistory    []string   `firestore:"contract_history,omitempty" json:"contract_history,omitempty"`         // IDs de contratos anteriores
 	LastRentalEndDate  *time.Time `firestore:"last_rental_end_date,omitempty" json:"last_rental_end_date,omitempty"` // Última data de término
@@ -89,6 +109,43 @@ type Property struct {
 	UpdatedAt time.Time `firestore:"updated_at" json:"updated_at"`
 }
 
+// PortalFlags selects the portals a property is syndicated to
+type PortalFlags struct {
+	ZAP      bool `firestore:"zap" json:"zap"`
+	VivaReal bool `firestore:"vivareal" json:"vivareal"`
+	OLX      bool `firestore:"olx" json:"olx"`
+}
+
+// Includes checks if the property is syndicated to the portal
+func (f PortalFlags) Includes(portal Portal) bool {
+	switch portal {
+	case PortalZAP:
+		return f.ZAP
+	case PortalVivaReal:
+		return f.VivaReal
+	case PortalOLX:
+		return f.OLX
+	default:
+		return false
+	}
+}
+
+// Any checks if the property is syndicated to at least one portal
+func (f PortalFlags) Any() bool {
+	return f.ZAP || f.VivaReal || f.OLX
+}
+
+// IsSyndicated checks if the property belongs in the portal feed
+func (p *Property) IsSyndicated(portal Portal) bool {
+	if p.Visibility != PropertyVisibilityPublic && p.Visibility != PropertyVisibilityMarketplace {
+		return false
+	}
+	if p.Status != PropertyStatusAvailable {
+		return false
+	}
+	return p.Portals.Includes(portal)
+}
+
 // DevelopmentInfo contains information about real estate developments (lançamentos imobiliários)
 // Used by developers and land developers for new projects (MVP+2)
 type DevelopmentInfo struct {
@@ -144,6 +201,7 @@ type RentalInfo struct {
 	// ===== TIPO DE LOCAÇÃO =====
 	RentalType         RentalType `firestore:"rental_type" json:"rental_type"`                                     // traditional, corporate, short_term, vacation
 	MinRentalPeriod    int        `firestore:"min_rental_period" json:"min_rental_period"`                         // Período mínimo (meses, ex: 12)
+	DailyRate          float64    `firestore:"daily_rate,omitempty" json:"daily_rate,omitempty"`                   // Diária de temporada (R$ 450)
 	Furnished          bool       `firestore:"furnished" json:"furnished"`                                         // Mobiliado? (true/false)
 	PartiallyFurnished bool       `firestore:"partially_furnished,omitempty" json:"partially_furnished,omitempty"` // Semi-mobiliado
 
@@ -160,3 +218,21 @@ type RentalInfo struct {
 	AvailableFrom      *time.Time `firestore:"available_from,omitempty" json:"available_from,omitempty"` // Disponível a partir de (ex: 2025-02-01)
 	ImmediateOccupancy bool       `firestore:"immediate_occupancy" json:"immediate_occupancy"`           // Ocupação imediata?
 }
+
+// IsShortStay reports whether the property is let by the night (temporada or
+// vacation), with an availability calendar and bookings
+func (r *RentalInfo) IsShortStay() bool {
+	return r != nil && (r.RentalType == RentalTypeShortTerm || r.RentalType == RentalTypeVacation)
+}
+
+// HasCoordinates returns true if the property has a latitude/longitude pair
+func (p *Property) HasCoordinates() bool {
+	return p.Latitude != nil && p.Longitude != nil
+}
+
+// SetCoordinates sets latitude/longitude and keeps the geohash in sync
+func (p *Property) SetCoordinates(lat, lng float64) {
+	p.Latitude = &lat
+	p.Longitude = &lng
+	p.Geohash = utils.EncodeGeohash(lat, lng, utils.DefaultGeohashPrecision)
+}
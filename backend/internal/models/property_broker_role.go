@@ -11,7 +11,7 @@ import "time"
 // 2. Todo Listing DEVE criar 1 listing_broker (vendedor)
 // 3. Pode haver N co_broker adicionados durante negociação
 // 4. Apenas 1 PropertyBrokerRole pode ter is_primary: true (roteamento de leads)
-// 5. Comissão é apenas registro, SEM cálculo ou split no MVP
+// 5. CommissionPercentage sobrepõe a regra do tenant no split da comissão (CommissionService)
 type PropertyBrokerRole struct {
 	ID         string `firestore:"-" json:"id"`
 	TenantID   string `firestore:"tenant_id" json:"tenant_id"`
@@ -24,7 +24,7 @@ type PropertyBrokerRole struct {
 	// - co_broker: corretor adicional na negociação (comum no Brasil)
 	Role BrokerPropertyRole `firestore:"role" json:"role"`
 
-	// Comissão (apenas registro, SEM processamento no MVP)
+	// Participação na comissão total (%); 0 = regra do tenant (CommissionSettings)
 	CommissionPercentage float64 `firestore:"commission_percentage,omitempty" json:"commission_percentage,omitempty"`
 
 	// Primary (para roteamento de leads)
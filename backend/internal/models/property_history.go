@@ -0,0 +1,57 @@
+package models
+
+import (
+	"math"
+	"time"
+)
+
+// PropertyHistoryField identifies the property field a history entry tracks
+type PropertyHistoryField string
+
+const (
+	PropertyHistoryFieldPrice  PropertyHistoryField = "price"
+	PropertyHistoryFieldStatus PropertyHistoryField = "status"
+)
+
+// PropertyChangeSource identifies where a price or status change came from
+type PropertyChangeSource string
+
+const (
+	PropertyChangeSourceOwnerLink PropertyChangeSource = "owner_link" // Owner confirmando via link
+	PropertyChangeSourceBroker    PropertyChangeSource = "broker"     // Painel administrativo
+	PropertyChangeSourceImport    PropertyChangeSource = "import"     // Importação de feed/CRM
+	PropertyChangeSourceSystem    PropertyChangeSource = "system"     // Job automático
+)
+
+// PropertyHistoryEntry records a single price or status change of a property
+// Collection: /properties/{propertyId}/history/{entryId}
+// Entries are append-only; the first entry of a field has no old value.
+type PropertyHistoryEntry struct {
+	ID         string               `firestore:"-" json:"id"`
+	TenantID   string               `firestore:"tenant_id" json:"tenant_id"`
+	PropertyID string               `firestore:"property_id" json:"property_id"`
+	Field      PropertyHistoryField `firestore:"field" json:"field"`
+
+	// Price changes
+	OldPrice *float64 `firestore:"old_price,omitempty" json:"old_price,omitempty"`
+	NewPrice *float64 `firestore:"new_price,omitempty" json:"new_price,omitempty"`
+
+	// Status changes
+	OldStatus PropertyStatus `firestore:"old_status,omitempty" json:"old_status,omitempty"`
+	NewStatus PropertyStatus `firestore:"new_status,omitempty" json:"new_status,omitempty"`
+
+	ActorType ActorType            `firestore:"actor_type" json:"actor_type"`
+	ActorID   string               `firestore:"actor_id,omitempty" json:"actor_id,omitempty"`
+	Source    PropertyChangeSource `firestore:"source" json:"source"`
+
+	ChangedAt time.Time `firestore:"changed_at" json:"changed_at"`
+}
+
+// PriceReductionPercent returns how much lower current is than previous, in
+// percent rounded to one decimal, or 0 when the price did not go down
+func PriceReductionPercent(previous, current float64) float64 {
+	if previous <= 0 || current <= 0 || current >= previous {
+		return 0
+	}
+	return math.Round((previous-current)/previous*1000) / 10
+}
@@ -0,0 +1,26 @@
+package models
+
+import "testing"
+
+func TestPriceReductionPercent(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous float64
+		current  float64
+		expected float64
+	}{
+		{"Reduction", 800000, 700000, 12.5},
+		{"Rounded to one decimal", 300000, 200000, 33.3},
+		{"Increase", 700000, 800000, 0},
+		{"No previous price", 0, 800000, 0},
+		{"Price cleared", 800000, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PriceReductionPercent(tt.previous, tt.current); got != tt.expected {
+				t.Errorf("PriceReductionPercent(%v, %v) = %v, expected %v", tt.previous, tt.current, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// ProposalStatus defines the status of a proposal (proposta)
+type ProposalStatus string
+
+const (
+	ProposalStatusSubmitted ProposalStatus = "submitted" // Waiting for the owner
+	ProposalStatusCountered ProposalStatus = "countered" // Owner counter-offer, waiting for the buyer
+	ProposalStatusAccepted  ProposalStatus = "accepted"
+	ProposalStatusRejected  ProposalStatus = "rejected"
+	ProposalStatusExpired   ProposalStatus = "expired" // Not answered within its validity
+)
+
+// ProposalParty is a side of the negotiation
+type ProposalParty string
+
+const (
+	ProposalPartyBuyer ProposalParty = "buyer" // The lead (buyer or tenant)
+	ProposalPartyOwner ProposalParty = "owner"
+)
+
+// Proposal is an offer of a lead for a property, with its counter-offers
+// Collection: /tenants/{tenantId}/proposals/{proposalId}
+type Proposal struct {
+	ID         string `firestore:"-" json:"id"`
+	TenantID   string `firestore:"tenant_id" json:"tenant_id"`
+	PropertyID string `firestore:"property_id" json:"property_id"`
+	LeadID     string `firestore:"lead_id" json:"lead_id"`
+	BrokerID   string `firestore:"broker_id,omitempty" json:"broker_id,omitempty"` // Broker negotiating for the lead
+
+	TransactionType TransactionType `firestore:"transaction_type" json:"transaction_type"` // sale or rent
+	Status          ProposalStatus  `firestore:"status" json:"status"`
+	ListPrice       float64         `firestore:"list_price" json:"list_price"` // Asking price when submitted
+
+	// Current terms (of the last round)
+	Terms ProposalTerms `firestore:"terms" json:"terms"`
+
+	// Offer and counter-offers, oldest first
+	Rounds []ProposalRound `firestore:"rounds" json:"rounds"`
+
+	// Outcome (accepted, rejected or expired)
+	DecidedAt      *time.Time `firestore:"decided_at,omitempty" json:"decided_at,omitempty"`
+	DecidedBy      string     `firestore:"decided_by,omitempty" json:"decided_by,omitempty"` // Member ID; empty when expired
+	DecisionReason string     `firestore:"decision_reason,omitempty" json:"decision_reason,omitempty"`
+
+	// Metadata
+	CreatedAt time.Time `firestore:"created_at" json:"created_at"`
+	UpdatedAt time.Time `firestore:"updated_at" json:"updated_at"`
+}
+
+// ProposalTerms are the amount and payment conditions of an offer
+type ProposalTerms struct {
+	Amount       float64 `firestore:"amount" json:"amount"`                                 // Total price (sale) or monthly rent (rent)
+	DownPayment  float64 `firestore:"down_payment,omitempty" json:"down_payment,omitempty"` // Entrada
+	Installments int     `firestore:"installments,omitempty" json:"installments,omitempty"` // Parcelas direto com o proprietário
+
+	// Bank financing and FGTS (sale)
+	Financing       bool    `firestore:"financing" json:"financing"`
+	FinancingAmount float64 `firestore:"financing_amount,omitempty" json:"financing_amount,omitempty"`
+	FinancingBank   string  `firestore:"financing_bank,omitempty" json:"financing_bank,omitempty"`
+	FGTSAmount      float64 `firestore:"fgts_amount,omitempty" json:"fgts_amount,omitempty"`
+
+	PaymentConditions string    `firestore:"payment_conditions,omitempty" json:"payment_conditions,omitempty"` // Free text (permuta, prazos...)
+	ValidUntil        time.Time `firestore:"valid_until" json:"valid_until"`                                   // Answer deadline
+}
+
+// ProposalRound is one offer or counter-offer of a negotiation
+type ProposalRound struct {
+	By        ProposalParty `firestore:"by" json:"by"`
+	Terms     ProposalTerms `firestore:"terms" json:"terms"`
+	Note      string        `firestore:"note,omitempty" json:"note,omitempty"`
+	CreatedBy string        `firestore:"created_by,omitempty" json:"created_by,omitempty"` // Member ID that recorded it
+	CreatedAt time.Time     `firestore:"created_at" json:"created_at"`
+}
+
+// IsOpen reports whether the proposal is still being negotiated
+func (p *Proposal) IsOpen() bool {
+	return p.Status == ProposalStatusSubmitted || p.Status == ProposalStatusCountered
+}
+
+// AwaitingParty returns the side that must answer an open proposal
+func (p *Proposal) AwaitingParty() ProposalParty {
+	if p.Status == ProposalStatusCountered {
+		return ProposalPartyBuyer
+	}
+	return ProposalPartyOwner
+}
+
+// IsExpired reports whether an open proposal is past its answer deadline at now
+func (p *Proposal) IsExpired(now time.Time) bool {
+	return p.IsOpen() && !p.Terms.ValidUntil.IsZero() && now.After(p.Terms.ValidUntil)
+}
+
+// Validate checks the amounts of the terms: the down payment, financing and
+// FGTS cannot exceed the amount
+func (t ProposalTerms) Validate() error {
+	if t.Amount <= 0 {
+		return fmt.Errorf("amount must be positive")
+	}
+	if t.DownPayment < 0 || t.FinancingAmount < 0 || t.FGTSAmount < 0 || t.Installments < 0 {
+		return fmt.Errorf("down_payment, financing_amount, fgts_amount and installments cannot be negative")
+	}
+	if t.FinancingAmount > 0 && !t.Financing {
+		return fmt.Errorf("financing_amount requires financing")
+	}
+	if t.DownPayment+t.FinancingAmount+t.FGTSAmount > t.Amount {
+		return fmt.Errorf("down_payment, financing_amount and fgts_amount exceed the amount")
+	}
+	if t.ValidUntil.IsZero() {
+		return fmt.Errorf("valid_until is required")
+	}
+	return nil
+}
+
+// IsValidProposalParty checks if a party is valid
+func IsValidProposalParty(party ProposalParty) bool {
+	return party == ProposalPartyBuyer || party == ProposalPartyOwner
+}
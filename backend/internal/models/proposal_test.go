@@ -0,0 +1,63 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestProposalTermsValidate(t *testing.T) {
+	validUntil := time.Date(2024, 7, 8, 18, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		terms   ProposalTerms
+		wantErr bool
+	}{
+		{"Cash offer", ProposalTerms{Amount: 500000, ValidUntil: validUntil}, false},
+		{"Financed offer", ProposalTerms{Amount: 500000, DownPayment: 100000, Financing: true, FinancingAmount: 350000, FGTSAmount: 50000, ValidUntil: validUntil}, false},
+		{"Zero amount", ProposalTerms{ValidUntil: validUntil}, true},
+		{"Negative down payment", ProposalTerms{Amount: 500000, DownPayment: -1, ValidUntil: validUntil}, true},
+		{"Financing amount without financing", ProposalTerms{Amount: 500000, FinancingAmount: 300000, ValidUntil: validUntil}, true},
+		{"Parts exceed the amount", ProposalTerms{Amount: 500000, DownPayment: 200000, Financing: true, FinancingAmount: 350000, ValidUntil: validUntil}, true},
+		{"No deadline", ProposalTerms{Amount: 500000}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.terms.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestProposalAwaitingPartyAndExpiry(t *testing.T) {
+	validUntil := time.Date(2024, 7, 8, 18, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		status       ProposalStatus
+		wantAwaiting ProposalParty
+		wantExpired  bool
+	}{
+		{ProposalStatusSubmitted, ProposalPartyOwner, true},
+		{ProposalStatusCountered, ProposalPartyBuyer, true},
+		{ProposalStatusAccepted, ProposalPartyOwner, false},
+		{ProposalStatusRejected, ProposalPartyOwner, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			p := &Proposal{Status: tt.status, Terms: ProposalTerms{ValidUntil: validUntil}}
+			if got := p.AwaitingParty(); got != tt.wantAwaiting {
+				t.Errorf("AwaitingParty() = %v, want %v", got, tt.wantAwaiting)
+			}
+			if got := p.IsExpired(validUntil.Add(time.Minute)); got != tt.wantExpired {
+				t.Errorf("IsExpired() = %v, want %v", got, tt.wantExpired)
+			}
+			if p.IsExpired(validUntil.Add(-time.Minute)) {
+				t.Error("IsExpired() before the deadline = true, want false")
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// PeriodLayout is the layout of monthly periods (YYYY-MM)
+const PeriodLayout = "2006-01"
+
+// IndexRate is the monthly variation of a price index (IGP-M, IPCA, INPC).
+// The series are public data shared by every tenant.
+// Collection: /index_rates/{index}_{period}
+type IndexRate struct {
+	ID        string         `firestore:"-" json:"id"`
+	Index     IndexationType `firestore:"index" json:"index"`
+	Period    string         `firestore:"period" json:"period"` // YYYY-MM
+	Rate      float64        `firestore:"rate" json:"rate"`     // Monthly variation in % (0.52 = 0,52%)
+	UpdatedAt time.Time      `firestore:"updated_at" json:"updated_at"`
+}
+
+// IndexRateID returns the document ID of an index month
+func IndexRateID(index IndexationType, period string) string {
+	return fmt.Sprintf("%s_%s", index, period)
+}
+
+// RentAdjustmentStatus defines the status of a rent adjustment
+type RentAdjustmentStatus string
+
+const (
+	RentAdjustmentStatusProposed  RentAdjustmentStatus = "proposed"
+	RentAdjustmentStatusApplied   RentAdjustmentStatus = "applied"
+	RentAdjustmentStatusDismissed RentAdjustmentStatus = "dismissed" // e.g. negotiated with the tenant
+)
+
+// RentAdjustment is the annual adjustment (reajuste) of the rent of a
+// contract, proposed from the accumulated variation of its index over the 12
+// months before the adjustment month
+// Collection: /tenants/{tenantId}/rent_adjustments/{adjustmentId}
+type RentAdjustment struct {
+	ID              string         `firestore:"-" json:"id"`
+	TenantID        string         `firestore:"tenant_id" json:"tenant_id"`
+	ContractID      string         `firestore:"contract_id" json:"contract_id"`
+	PropertyID      string         `firestore:"property_id" json:"property_id"`
+	Index           IndexationType `firestore:"index" json:"index"`
+	EffectivePeriod string         `firestore:"effective_period" json:"effective_period"` // YYYY-MM of the new rent
+	ReferenceFrom   string         `firestore:"reference_from" json:"reference_from"`     // First index month (YYYY-MM)
+	ReferenceTo     string         `firestore:"reference_to" json:"reference_to"`         // Last index month (YYYY-MM)
+
+	Variation    float64 `firestore:"variation" json:"variation"` // Accumulated variation in %
+	CurrentRent  float64 `firestore:"current_rent" json:"current_rent"`
+	ProposedRent float64 `firestore:"proposed_rent" json:"proposed_rent"`
+
+	Status        RentAdjustmentStatus `firestore:"status" json:"status"`
+	DecidedAt     *time.Time           `firestore:"decided_at,omitempty" json:"decided_at,omitempty"`
+	DecidedBy     string               `firestore:"decided_by,omitempty" json:"decided_by,omitempty"`
+	DismissReason string               `firestore:"dismiss_reason,omitempty" json:"dismiss_reason,omitempty"`
+
+	// Metadata
+	CreatedAt time.Time `firestore:"created_at" json:"created_at"`
+	UpdatedAt time.Time `firestore:"updated_at" json:"updated_at"`
+}
+
+// AdjustmentReference returns the 12 index months (first and last, YYYY-MM)
+// accumulated for an adjustment effective in the given month
+func AdjustmentReference(effective time.Time) (string, string) {
+	month := time.Date(effective.Year(), effective.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return month.AddDate(0, -12, 0).Format(PeriodLayout), month.AddDate(0, -1, 0).Format(PeriodLayout)
+}
+
+// AccumulatedVariation compounds monthly variations (in %) into the variation
+// of the whole period, rounded to 4 decimals
+func AccumulatedVariation(rates []float64) float64 {
+	factor := 1.0
+	for _, rate := range rates {
+		factor *= 1 + rate/100
+	}
+	return math.Round((factor-1)*100*10000) / 10000
+}
+
+// AdjustedRent applies an accumulated variation to the rent, rounded to
+// cents. A negative variation keeps the rent: adjustments never reduce it.
+func AdjustedRent(rent, variation float64) float64 {
+	if variation <= 0 {
+		return rent
+	}
+	return roundCents(rent * (1 + variation/100))
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAdjustmentReference(t *testing.T) {
+	from, to := AdjustmentReference(time.Date(2025, 3, 20, 15, 0, 0, 0, time.UTC))
+	if from != "2024-03" || to != "2025-02" {
+		t.Errorf("AdjustmentReference() = %s, %s, want 2024-03, 2025-02", from, to)
+	}
+
+	from, to = AdjustmentReference(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	if from != "2024-01" || to != "2024-12" {
+		t.Errorf("AdjustmentReference() = %s, %s, want 2024-01, 2024-12", from, to)
+	}
+}
+
+func TestAccumulatedVariation(t *testing.T) {
+	tests := []struct {
+		name  string
+		rates []float64
+		want  float64
+	}{
+		{"No months", nil, 0},
+		{"Single month", []float64{0.52}, 0.52},
+		{"Compounded", []float64{1, 1}, 2.01},
+		{"Deflation", []float64{-0.5, 0.2}, -0.301},
+		{"Twelve months", []float64{0.5, 0.5, 0.5, 0.5, 0.5, 0.5, 0.5, 0.5, 0.5, 0.5, 0.5, 0.5}, 6.1678},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AccumulatedVariation(tt.rates); got != tt.want {
+				t.Errorf("AccumulatedVariation() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdjustedRent(t *testing.T) {
+	tests := []struct {
+		name      string
+		rent      float64
+		variation float64
+		want      float64
+	}{
+		{"Rounded to cents", 3000, 6.1678, 3185.03},
+		{"No variation", 3000, 0, 3000},
+		{"Negative variation keeps the rent", 3000, -3.2, 3000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AdjustedRent(tt.rent, tt.variation); got != tt.want {
+				t.Errorf("AdjustedRent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"time"
+)
+
+// Default billing terms of rental contracts
+const (
+	DefaultLateFeePercentage   = 10.0 // Multa (% of the overdue amount)
+	DefaultMonthlyInterestRate = 1.0  // Juros de mora (% a month)
+	DefaultAdminFeePercentage  = 10.0 // Taxa de administração (% of the rent collected)
+
+	// MaxDueDay keeps due dates valid in every month
+	MaxDueDay = 28
+)
+
+// RentInvoiceStatus defines the status of a rent invoice
+type RentInvoiceStatus string
+
+const (
+	RentInvoiceStatusOpen      RentInvoiceStatus = "open" // Overdue once past its due date
+	RentInvoiceStatusPaid      RentInvoiceStatus = "paid"
+	RentInvoiceStatusCancelled RentInvoiceStatus = "cancelled"
+)
+
+// InvoiceItemKind defines the kind of a rent invoice item
+type InvoiceItemKind string
+
+const (
+	InvoiceItemKindRent     InvoiceItemKind = "rent"
+	InvoiceItemKindCondoFee InvoiceItemKind = "condo_fee" // Passed through to the owner
+	InvoiceItemKindIPTU     InvoiceItemKind = "iptu"      // Passed through to the owner
+)
+
+// RentPaymentMethod defines how a rent payment was received
+type RentPaymentMethod string
+
+const (
+	RentPaymentMethodManual   RentPaymentMethod = "manual"   // Registered by the agency (PIX, transfer, cash)
+	RentPaymentMethodProvider RentPaymentMethod = "provider" // Confirmed by a payment provider webhook
+)
+
+// RentInvoice is the monthly charge (boleto/cobrança) of a rental contract.
+// The rent of a month is paid in arrears, on the contract's due day of the
+// following month. One invoice per contract and month: its ID is
+// RentInvoiceID(contract, period).
+// Collection: /tenants/{tenantId}/rent_invoices/{invoiceId}
+type RentInvoice struct {
+	ID         string `firestore:"-" json:"id"`
+	TenantID   string `firestore:"tenant_id" json:"tenant_id"`
+	ContractID string `firestore:"contract_id" json:"contract_id"`
+	PropertyID string `firestore:"property_id" json:"property_id"`
+	OwnerID    string `firestore:"owner_id,omitempty" json:"owner_id,omitempty"`
+	Reference  string `firestore:"reference" json:"reference"` // External reference sent to payment providers (matches their webhooks)
+	Period     string `firestore:"period" json:"period"`       // YYYY-MM of the rent charged
+
+	// Inquilino at issue
+	Tenant RentalTenant `firestore:"tenant" json:"tenant"`
+
+	Items   []InvoiceItem `firestore:"items" json:"items"`
+	Amount  float64       `firestore:"amount" json:"amount"` // Sum of the items
+	DueDate time.Time     `firestore:"due_date" json:"due_date"`
+
+	// Contract terms at issue
+	LateFeePercentage   float64 `firestore:"late_fee_percentage" json:"late_fee_percentage"`
+	MonthlyInterestRate float64 `firestore:"monthly_interest_rate" json:"monthly_interest_rate"`
+	AdminFeePercentage  float64 `firestore:"admin_fee_percentage" json:"admin_fee_percentage"`
+
+	// Settlement
+	Status         RentInvoiceStatus `firestore:"status" json:"status"`
+	Payments       []RentPayment     `firestore:"payments,omitempty" json:"payments,omitempty"`
+	PaymentPeriods []string          `firestore:"payment_periods,omitempty" json:"-"` // YYYY-MM of the payments, for array-contains queries
+	PrincipalPaid  float64           `firestore:"principal_paid" json:"principal_paid"`
+	ChargesPaid    float64           `firestore:"charges_paid" json:"charges_paid"` // Late fees and interest received
+	PaidAt         *time.Time        `firestore:"paid_at,omitempty" json:"paid_at,omitempty"`
+	CancelledAt    *time.Time        `firestore:"cancelled_at,omitempty" json:"cancelled_at,omitempty"`
+	CancelReason   string            `firestore:"cancel_reason,omitempty" json:"cancel_reason,omitempty"`
+
+	// Metadata
+	CreatedAt time.Time `firestore:"created_at" json:"created_at"`
+	UpdatedAt time.Time `firestore:"updated_at" json:"updated_at"`
+}
+
+// InvoiceItem is a line of a rent invoice
+type InvoiceItem struct {
+	Kind        InvoiceItemKind `firestore:"kind" json:"kind"`
+	Description string          `firestore:"description" json:"description"`
+	Amount      float64         `firestore:"amount" json:"amount"`
+}
+
+// RentPayment is a payment received on a rent invoice. Late payments settle
+// principal, late fee and interest in proportion.
+type RentPayment struct {
+	Amount    float64 `firestore:"amount" json:"amount"`       // Received
+	Principal float64 `firestore:"principal" json:"principal"` // Applied to the invoice amount
+	LateFee   float64 `firestore:"late_fee" json:"late_fee"`
+	Interest  float64 `firestore:"interest" json:"interest"`
+
+	PaidAt            time.Time         `firestore:"paid_at" json:"paid_at"`
+	Method            RentPaymentMethod `firestore:"method" json:"method"`
+	Provider          string            `firestore:"provider,omitempty" json:"provider,omitempty"`
+	ProviderPaymentID string            `firestore:"provider_payment_id,omitempty" json:"provider_payment_id,omitempty"` // Makes webhook retries idempotent
+	Note              string            `firestore:"note,omitempty" json:"note,omitempty"`
+	RecordedBy        string            `firestore:"recorded_by,omitempty" json:"recorded_by,omitempty"`
+	RecordedAt        time.Time         `firestore:"recorded_at" json:"recorded_at"`
+}
+
+// RentInvoiceID returns the document ID of the invoice of a contract for a month
+func RentInvoiceID(contractID, period string) string {
+	return fmt.Sprintf("%s_%s", contractID, period)
+}
+
+// Outstanding returns the part of the invoice amount not paid yet
+func (i *RentInvoice) Outstanding() float64 {
+	return math.Max(0, roundCents(i.Amount-i.PrincipalPaid))
+}
+
+// DaysOverdue returns the days an open invoice is past its due date at a time
+func (i *RentInvoice) DaysOverdue(at time.Time) int {
+	if i.Status != RentInvoiceStatusOpen {
+		return 0
+	}
+	return max(0, CalendarDays(i.DueDate, at))
+}
+
+// Charges returns the late fee and interest owed on the outstanding amount
+// at a time: the late fee once overdue, interest pro rata die (30-day months)
+func (i *RentInvoice) Charges(at time.Time) (float64, float64) {
+	lateFee, interest := i.chargeRates(at)
+	outstanding := i.Outstanding()
+	return roundCents(outstanding * lateFee), roundCents(outstanding * interest)
+}
+
+// AmountDue returns the outstanding amount plus its charges at a time
+func (i *RentInvoice) AmountDue(at time.Time) float64 {
+	lateFee, interest := i.Charges(at)
+	return roundCents(i.Outstanding() + lateFee + interest)
+}
+
+// ApplyPayment allocates a payment to principal, late fee and interest and
+// updates the invoice totals; the invoice is paid once nothing is outstanding.
+// Every real of principal paid late carries its own late fee and interest, so
+// partial payments never charge the late fee twice. Overpayments are kept as
+// principal.
+func (i *RentInvoice) ApplyPayment(payment RentPayment) RentPayment {
+	lateFeeRate, interestRate := i.chargeRates(payment.PaidAt)
+
+	payment.Amount = roundCents(payment.Amount)
+	if payment.Amount >= i.AmountDue(payment.PaidAt) {
+		payment.LateFee, payment.Interest = i.Charges(payment.PaidAt)
+		payment.Principal = roundCents(payment.Amount - payment.LateFee - payment.Interest)
+	} else {
+		payment.Principal = roundCents(payment.Amount / (1 + lateFeeRate + interestRate))
+		payment.LateFee = roundCents(payment.Principal * lateFeeRate)
+		payment.Interest = roundCents(payment.Amount - payment.Principal - payment.LateFee)
+	}
+
+	i.Payments = append(i.Payments, payment)
+	i.PrincipalPaid = roundCents(i.PrincipalPaid + payment.Principal)
+	i.ChargesPaid = roundCents(i.ChargesPaid + payment.LateFee + payment.Interest)
+	if period := payment.PaidAt.Format(PeriodLayout); !slices.Contains(i.PaymentPeriods, period) {
+		i.PaymentPeriods = append(i.PaymentPeriods, period)
+	}
+	if i.Outstanding() == 0 {
+		i.Status = RentInvoiceStatusPaid
+		paidAt := payment.PaidAt
+		i.PaidAt = &paidAt
+	}
+
+	return payment
+}
+
+// HasProviderPayment reports whether a provider payment was already applied
+func (i *RentInvoice) HasProviderPayment(provider, providerPaymentID string) bool {
+	for _, payment := range i.Payments {
+		if payment.Provider == provider && payment.ProviderPaymentID == providerPaymentID {
+			return true
+		}
+	}
+	return false
+}
+
+// chargeRates returns the late fee and interest rates (fractions) of the
+// outstanding amount at a time
+func (i *RentInvoice) chargeRates(at time.Time) (float64, float64) {
+	days := i.DaysOverdue(at)
+	if days == 0 {
+		return 0, 0
+	}
+	return i.LateFeePercentage / 100, i.MonthlyInterestRate / 100 * float64(days) / 30
+}
+
+// roundCents rounds an amount to cents
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,100 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestInvoice() *RentInvoice {
+	return &RentInvoice{
+		Amount:              3950,
+		DueDate:             time.Date(2024, 4, 10, 0, 0, 0, 0, time.UTC),
+		LateFeePercentage:   10,
+		MonthlyInterestRate: 1,
+		Status:              RentInvoiceStatusOpen,
+	}
+}
+
+func TestRentInvoiceCharges(t *testing.T) {
+	tests := []struct {
+		name         string
+		at           time.Time
+		wantLateFee  float64
+		wantInterest float64
+	}{
+		{"On the due date", time.Date(2024, 4, 10, 18, 0, 0, 0, time.UTC), 0, 0},
+		{"One day late", time.Date(2024, 4, 11, 9, 0, 0, 0, time.UTC), 395, 1.32},
+		{"Fifteen days late", time.Date(2024, 4, 25, 9, 0, 0, 0, time.UTC), 395, 19.75},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lateFee, interest := newTestInvoice().Charges(tt.at)
+			if lateFee != tt.wantLateFee || interest != tt.wantInterest {
+				t.Errorf("Charges() = %v, %v, want %v, %v", lateFee, interest, tt.wantLateFee, tt.wantInterest)
+			}
+		})
+	}
+}
+
+func TestRentInvoiceApplyPayment(t *testing.T) {
+	late := time.Date(2024, 4, 25, 0, 0, 0, 0, time.UTC)
+
+	// Full payment on time
+	invoice := newTestInvoice()
+	payment := invoice.ApplyPayment(RentPayment{Amount: 3950, PaidAt: invoice.DueDate})
+	if payment.Principal != 3950 || payment.LateFee != 0 || payment.Interest != 0 {
+		t.Errorf("ApplyPayment() on time = %+v, want principal only", payment)
+	}
+	if invoice.Status != RentInvoiceStatusPaid || invoice.PaidAt == nil {
+		t.Errorf("Status = %s, want paid", invoice.Status)
+	}
+
+	// Full payment late settles the charges
+	invoice = newTestInvoice()
+	payment = invoice.ApplyPayment(RentPayment{Amount: 4364.75, PaidAt: late})
+	if payment.Principal != 3950 || payment.LateFee != 395 || payment.Interest != 19.75 {
+		t.Errorf("ApplyPayment() late = %+v, want 3950 + 395 + 19.75", payment)
+	}
+	if invoice.Status != RentInvoiceStatusPaid || invoice.ChargesPaid != 414.75 {
+		t.Errorf("Status = %s, charges paid = %v, want paid with 414.75", invoice.Status, invoice.ChargesPaid)
+	}
+
+	// Partial payment late splits in proportion and keeps the invoice open
+	invoice = newTestInvoice()
+	payment = invoice.ApplyPayment(RentPayment{Amount: 1105, PaidAt: late})
+	if payment.Principal != 1000 || payment.LateFee != 100 || payment.Interest != 5 {
+		t.Errorf("ApplyPayment() partial = %+v, want 1000 + 100 + 5", payment)
+	}
+	if invoice.Status != RentInvoiceStatusOpen || invoice.Outstanding() != 2950 {
+		t.Errorf("Status = %s, outstanding = %v, want open with 2950", invoice.Status, invoice.Outstanding())
+	}
+	if got := invoice.AmountDue(late); got != 3259.75 {
+		t.Errorf("AmountDue() = %v, want 3259.75", got)
+	}
+	if len(invoice.PaymentPeriods) != 1 || invoice.PaymentPeriods[0] != "2024-04" {
+		t.Errorf("PaymentPeriods = %v, want [2024-04]", invoice.PaymentPeriods)
+	}
+}
+
+func TestRentalContractEffectiveDueDay(t *testing.T) {
+	tests := []struct {
+		name   string
+		dueDay int
+		start  time.Time
+		want   int
+	}{
+		{"Set", 10, time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC), 10},
+		{"Start day", 0, time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC), 15},
+		{"Start day past 28", 0, time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC), 28},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contract := RentalContract{DueDay: tt.dueDay, StartDate: tt.start}
+			if got := contract.EffectiveDueDay(); got != tt.want {
+				t.Errorf("EffectiveDueDay() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RentalContractStatus defines the status of a rental contract
+type RentalContractStatus string
+
+const (
+	RentalContractStatusActive     RentalContractStatus = "active"
+	RentalContractStatusEnded      RentalContractStatus = "ended"      // Reached its end date
+	RentalContractStatusTerminated RentalContractStatus = "terminated" // Ended early (rescisão)
+)
+
+// RentalContract is the lease of a property to a tenant (contrato de locação).
+// While active it is the property's CurrentContractID; once it ends it joins
+// the property's ContractHistory and feeds its vacancy average.
+// Collection: /tenants/{tenantId}/rental_contracts/{contractId}
+type RentalContract struct {
+	ID         string               `firestore:"-" json:"id"`
+	TenantID   string               `firestore:"tenant_id" json:"tenant_id"`
+	PropertyID string               `firestore:"property_id" json:"property_id"`
+	OwnerID    string               `firestore:"owner_id,omitempty" json:"owner_id,omitempty"` // Locador, from the property
+	DealID     string               `firestore:"deal_id,omitempty" json:"deal_id,omitempty"`   // Rental deal that originated the contract
+	Status     RentalContractStatus `firestore:"status" json:"status"`
+
+	// Inquilino (locatário)
+	Tenant RentalTenant `firestore:"tenant" json:"tenant"`
+
+	// Garantia locatícia
+	GuaranteeType    GuaranteeType `firestore:"guarantee_type" json:"guarantee_type"`                           // fiador, caucao, seguro_fianca, fianca_bancaria
+	GuaranteeDetails string        `firestore:"guarantee_details,omitempty" json:"guarantee_details,omitempty"` // Fiador, apólice, banco
+	DepositAmount    float64       `firestore:"deposit_amount,omitempty" json:"deposit_amount,omitempty"`       // Caução (R$)
+
+	// Vigência
+	StartDate time.Time `firestore:"start_date" json:"start_date"`
+	EndDate   time.Time `firestore:"end_date" json:"end_date"` // Moves with renewals
+
+	// Valores mensais (default: the property's RentalInfo)
+	MonthlyRent float64 `firestore:"monthly_rent" json:"monthly_rent"`
+	CondoFee    float64 `firestore:"condo_fee,omitempty" json:"condo_fee,omitempty"`
+	IPTUMonthly float64 `firestore:"iptu_monthly,omitempty" json:"iptu_monthly,omitempty"`
+
+	// Reajuste anual (default: the property's RentalInfo, on the start month)
+	IndexationType  IndexationType `firestore:"indexation_type" json:"indexation_type"`   // igpm, ipca, inpc
+	AdjustmentMonth int            `firestore:"adjustment_month" json:"adjustment_month"` // 1-12
+
+	// Cobrança (default: the Default* billing terms; due day: the start day)
+	DueDay              int     `firestore:"due_day" json:"due_day"`                             // Day of the month after the reference month (1-28)
+	LateFeePercentage   float64 `firestore:"late_fee_percentage" json:"late_fee_percentage"`     // Multa (% of the overdue amount)
+	MonthlyInterestRate float64 `firestore:"monthly_interest_rate" json:"monthly_interest_rate"` // Juros de mora (% a month, pro rata die)
+	AdminFeePercentage  float64 `firestore:"admin_fee_percentage" json:"admin_fee_percentage"`   // Taxa de administração (% of the rent collected)
+
+	Renewals []ContractRenewal `firestore:"renewals,omitempty" json:"renewals,omitempty"`
+
+	// Days the property stood empty before this contract (nil for its first contract)
+	VacancyDaysBefore *int `firestore:"vacancy_days_before,omitempty" json:"vacancy_days_before,omitempty"`
+
+	// Encerramento
+	EndedAt           *time.Time `firestore:"ended_at,omitempty" json:"ended_at,omitempty"` // Move-out date
+	TerminationReason string     `firestore:"termination_reason,omitempty" json:"termination_reason,omitempty"`
+
+	Notes string `firestore:"notes,omitempty" json:"notes,omitempty"`
+
+	// Metadata
+	CreatedBy string    `firestore:"created_by,omitempty" json:"created_by,omitempty"`
+	CreatedAt time.Time `firestore:"created_at" json:"created_at"`
+	UpdatedAt time.Time `firestore:"updated_at" json:"updated_at"`
+}
+
+// RentalTenant is the tenant (inquilino) of a rental contract
+type RentalTenant struct {
+	Name     string `firestore:"name" json:"name"`
+	Document string `firestore:"document,omitempty" json:"document,omitempty"` // CPF or CNPJ
+	Email    string `firestore:"email,omitempty" json:"email,omitempty"`
+	Phone    string `firestore:"phone,omitempty" json:"phone,omitempty"`
+	LeadID   string `firestore:"lead_id,omitempty" json:"lead_id,omitempty"`
+}
+
+// ContractRenewal records an extension of a rental contract (aditivo)
+type ContractRenewal struct {
+	PreviousEndDate time.Time `firestore:"previous_end_date" json:"previous_end_date"`
+	NewEndDate      time.Time `firestore:"new_end_date" json:"new_end_date"`
+	PreviousRent    float64   `firestore:"previous_rent" json:"previous_rent"`
+	MonthlyRent     float64   `firestore:"monthly_rent" json:"monthly_rent"`
+	Note            string    `firestore:"note,omitempty" json:"note,omitempty"`
+	RenewedBy       string    `firestore:"renewed_by,omitempty" json:"renewed_by,omitempty"`
+	RenewedAt       time.Time `firestore:"renewed_at" json:"renewed_at"`
+}
+
+// IsActive reports whether the contract is in force
+func (c *RentalContract) IsActive() bool {
+	return c.Status == RentalContractStatusActive
+}
+
+// EffectiveDueDay returns the due day of the contract's invoices: its DueDay,
+// or the start day (at most MaxDueDay) for contracts without one
+func (c *RentalContract) EffectiveDueDay() int {
+	if c.DueDay > 0 {
+		return c.DueDay
+	}
+	return min(c.StartDate.Day(), MaxDueDay)
+}
+
+// TotalMonthly returns the rent plus the condo fee and IPTU
+func (c *RentalContract) TotalMonthly() float64 {
+	return c.MonthlyRent + c.CondoFee + c.IPTUMonthly
+}
+
+// Validate checks the contract data
+func (c *RentalContract) Validate() error {
+	if strings.TrimSpace(c.Tenant.Name) == "" {
+		return fmt.Errorf("tenant name is required")
+	}
+	if !IsValidGuaranteeType(c.GuaranteeType) {
+		return fmt.Errorf("invalid guarantee_type: %s", c.GuaranteeType)
+	}
+	if c.StartDate.IsZero() || c.EndDate.IsZero() {
+		return fmt.Errorf("start_date and end_date are required")
+	}
+	if !c.EndDate.After(c.StartDate) {
+		return fmt.Errorf("end_date must be after start_date")
+	}
+	if c.MonthlyRent <= 0 {
+		return fmt.Errorf("monthly_rent must be positive")
+	}
+	if c.CondoFee < 0 || c.IPTUMonthly < 0 || c.DepositAmount < 0 {
+		return fmt.Errorf("values cannot be negative")
+	}
+	if !IsValidIndexationType(c.IndexationType) {
+		return fmt.Errorf("invalid indexation_type: %s", c.IndexationType)
+	}
+	if c.AdjustmentMonth < 1 || c.AdjustmentMonth > 12 {
+		return fmt.Errorf("adjustment_month must be between 1 and 12")
+	}
+	if c.DueDay < 0 || c.DueDay > MaxDueDay {
+		return fmt.Errorf("due_day must be between 1 and %d", MaxDueDay)
+	}
+	if c.LateFeePercentage < 0 || c.LateFeePercentage > 100 || c.AdminFeePercentage < 0 || c.AdminFeePercentage > 100 {
+		return fmt.Errorf("late_fee_percentage and admin_fee_percentage must be between 0 and 100")
+	}
+	if c.MonthlyInterestRate < 0 || c.MonthlyInterestRate > 10 {
+		return fmt.Errorf("monthly_interest_rate must be between 0 and 10")
+	}
+	return nil
+}
+
+// IsValidGuaranteeType checks if a guarantee type is known
+func IsValidGuaranteeType(guarantee GuaranteeType) bool {
+	switch guarantee {
+	case GuaranteeTypeFiador, GuaranteeTypeCaucao, GuaranteeTypeSeguroFianca, GuaranteeTypeFiancaBancaria:
+		return true
+	}
+	return false
+}
+
+// IsValidIndexationType checks if an indexation type is known
+func IsValidIndexationType(indexation IndexationType) bool {
+	switch indexation {
+	case IndexationTypeIGPM, IndexationTypeIPCA, IndexationTypeINPC:
+		return true
+	}
+	return false
+}
+
+// CalendarDays returns the calendar days from one date to another, ignoring
+// the time of day (negative when to is before from)
+func CalendarDays(from, to time.Time) int {
+	fromDate := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	toDate := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(toDate.Sub(fromDate).Hours() / 24)
+}
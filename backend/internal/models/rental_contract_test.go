@@ -0,0 +1,60 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRentalContractValidate(t *testing.T) {
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	valid := func() RentalContract {
+		return RentalContract{
+			Tenant:          RentalTenant{Name: "João Pereira"},
+			GuaranteeType:   GuaranteeTypeSeguroFianca,
+			StartDate:       start,
+			EndDate:         start.AddDate(2, 0, -1),
+			MonthlyRent:     3000,
+			IndexationType:  IndexationTypeIGPM,
+			AdjustmentMonth: 3,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		mutate  func(*RentalContract)
+		wantErr bool
+	}{
+		{"Valid", func(c *RentalContract) {}, false},
+		{"No tenant name", func(c *RentalContract) { c.Tenant.Name = " " }, true},
+		{"Unknown guarantee", func(c *RentalContract) { c.GuaranteeType = "cheque" }, true},
+		{"End before start", func(c *RentalContract) { c.EndDate = start.AddDate(0, 0, -1) }, true},
+		{"No rent", func(c *RentalContract) { c.MonthlyRent = 0 }, true},
+		{"Negative condo fee", func(c *RentalContract) { c.CondoFee = -10 }, true},
+		{"Unknown index", func(c *RentalContract) { c.IndexationType = "selic" }, true},
+		{"Adjustment month 13", func(c *RentalContract) { c.AdjustmentMonth = 13 }, true},
+		{"Due day 29", func(c *RentalContract) { c.DueDay = 29 }, true},
+		{"Late fee over 100%", func(c *RentalContract) { c.LateFeePercentage = 120 }, true},
+		{"Interest over 10% a month", func(c *RentalContract) { c.MonthlyInterestRate = 12 }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contract := valid()
+			tt.mutate(&contract)
+			err := contract.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCalendarDays(t *testing.T) {
+	from := time.Date(2024, 6, 30, 18, 0, 0, 0, time.UTC)
+	if got := CalendarDays(from, time.Date(2024, 7, 20, 9, 0, 0, 0, time.UTC)); got != 20 {
+		t.Errorf("CalendarDays() = %d, want 20", got)
+	}
+	if got := CalendarDays(from, time.Date(2024, 6, 29, 23, 0, 0, 0, time.UTC)); got != -1 {
+		t.Errorf("CalendarDays() = %d, want -1", got)
+	}
+}
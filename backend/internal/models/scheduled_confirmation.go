@@ -24,9 +24,17 @@ type ScheduledConfirmation struct {
 	Status       ScheduledConfirmationStatus `firestore:"status" json:"status"` // pending, sent, failed, cancelled
 
 	// Delivery info
-	DeliveryMethod string `firestore:"delivery_method" json:"delivery_method"` // whatsapp, email, sms, manual
-	DeliveryStatus string `firestore:"delivery_status,omitempty" json:"delivery_status,omitempty"`
-	DeliveryError  string `firestore:"delivery_error,omitempty" json:"delivery_error,omitempty"`
+	DeliveryMethod    string     `firestore:"delivery_method" json:"delivery_method"`                             // whatsapp, email, sms, manual
+	DeliveryStatus    string     `firestore:"delivery_status,omitempty" json:"delivery_status,omitempty"`         // See DeliveryStatus constants
+	DeliveryError     string     `firestore:"delivery_error,omitempty" json:"delivery_error,omitempty"`           // Provider error
+	DeliveryAttempts  int        `firestore:"delivery_attempts,omitempty" json:"delivery_attempts,omitempty"`     // Send attempts (retries included)
+	ProviderMessageID string     `firestore:"provider_message_id,omitempty" json:"provider_message_id,omitempty"` // Provider ID, matched by delivery webhooks
+	DeliveredAt       *time.Time `firestore:"delivered_at,omitempty" json:"delivered_at,omitempty"`
+
+	// Follow-up sequence (TenantPolicy.ReminderSteps), stopped once the owner responds
+	ReminderStep   int                    `firestore:"reminder_step" json:"reminder_step"`                           // Steps already run (0 = initial message only)
+	NextReminderAt *time.Time             `firestore:"next_reminder_at,omitempty" json:"next_reminder_at,omitempty"` // nil when the sequence is over
+	Reminders      []ConfirmationReminder `firestore:"reminders,omitempty" json:"reminders,omitempty"`               // Steps run so far
 
 	// Owner response tracking
 	RespondedAt *time.Time `firestore:"responded_at,omitempty" json:"responded_at,omitempty"`
@@ -37,6 +45,15 @@ type ScheduledConfirmation struct {
 	UpdatedAt time.Time `firestore:"updated_at" json:"updated_at"`
 }
 
+// ConfirmationReminder records a follow-up step run on a scheduled confirmation
+type ConfirmationReminder struct {
+	Step           int            `firestore:"step" json:"step"` // 1-based index in the tenant's reminder steps
+	Action         ReminderAction `firestore:"action" json:"action"`
+	RanAt          time.Time      `firestore:"ran_at" json:"ran_at"`
+	DeliveryMethod string         `firestore:"delivery_method,omitempty" json:"delivery_method,omitempty"` // Channel used (resend, notify_captador)
+	Error          string         `firestore:"error,omitempty" json:"error,omitempty"`
+}
+
 // ScheduledConfirmationStatus represents the status of a scheduled confirmation
 type ScheduledConfirmationStatus string
 
@@ -0,0 +1,250 @@
+package models
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Limits of short stays
+const (
+	DefaultMinNights = 1
+	MaxStayNights    = 90 // Locação para temporada: up to 90 days (Lei 8.245/91, art. 48)
+	MaxCalendarFeeds = 5
+)
+
+// StayCalendar holds the short-stay terms of a property let by the night:
+// nightly rates, seasons, minimum stay and the external calendars (Airbnb,
+// Booking.com) whose reservations block its dates. The document ID is the
+// property ID.
+// Collection: /tenants/{tenantId}/stay_calendars/{propertyId}
+type StayCalendar struct {
+	PropertyID string `firestore:"-" json:"property_id"`
+	TenantID   string `firestore:"tenant_id" json:"tenant_id"`
+
+	// Rates
+	NightlyRate float64        `firestore:"nightly_rate" json:"nightly_rate"`                     // Diária padrão
+	CleaningFee float64        `firestore:"cleaning_fee,omitempty" json:"cleaning_fee,omitempty"` // Taxa de limpeza (per stay)
+	Seasons     []SeasonalRate `firestore:"seasons,omitempty" json:"seasons,omitempty"`           // Override the nightly rate and minimum stay
+
+	// Rules
+	MinNights int `firestore:"min_nights" json:"min_nights"`
+	MaxGuests int `firestore:"max_guests,omitempty" json:"max_guests,omitempty"` // 0 = no limit
+
+	// External calendars imported periodically
+	Feeds []CalendarFeed `firestore:"feeds,omitempty" json:"feeds,omitempty"`
+
+	// SHA-256 of the token of the exported .ics feed
+	ExportTokenHash string `firestore:"export_token_hash,omitempty" json:"-"`
+
+	// Metadata
+	CreatedAt time.Time `firestore:"created_at" json:"created_at"`
+	UpdatedAt time.Time `firestore:"updated_at" json:"updated_at"`
+}
+
+// SeasonalRate is the nightly rate of a season (alta temporada, réveillon,
+// carnaval)
+type SeasonalRate struct {
+	Name        string    `firestore:"name" json:"name"`
+	StartDate   time.Time `firestore:"start_date" json:"start_date"` // First night
+	EndDate     time.Time `firestore:"end_date" json:"end_date"`     // Last night (inclusive)
+	NightlyRate float64   `firestore:"nightly_rate" json:"nightly_rate"`
+	MinNights   int       `firestore:"min_nights,omitempty" json:"min_nights,omitempty"` // Stays starting in the season (0 = the calendar's)
+}
+
+// CalendarFeed is an external .ics calendar synced into the property's
+// availability
+type CalendarFeed struct {
+	ID           string     `firestore:"id" json:"id"`     // Derived from the URL (CalendarFeedID)
+	Name         string     `firestore:"name" json:"name"` // ex: "Airbnb"
+	URL          string     `firestore:"url" json:"url"`
+	LastSyncedAt *time.Time `firestore:"last_synced_at,omitempty" json:"last_synced_at,omitempty"`
+	LastError    string     `firestore:"last_error,omitempty" json:"last_error,omitempty"`
+	Events       int        `firestore:"events" json:"events"` // Blocks imported by the last sync
+}
+
+// StayQuote is the price of a stay
+type StayQuote struct {
+	CheckIn     time.Time       `firestore:"check_in" json:"check_in"`
+	CheckOut    time.Time       `firestore:"check_out" json:"check_out"`
+	Nights      int             `firestore:"nights" json:"nights"`
+	Lines       []StayQuoteLine `firestore:"lines" json:"lines"`
+	Lodging     float64         `firestore:"lodging" json:"lodging"` // Sum of the nights
+	CleaningFee float64         `firestore:"cleaning_fee" json:"cleaning_fee"`
+	Total       float64         `firestore:"total" json:"total"`
+}
+
+// StayQuoteLine groups consecutive nights at the same rate
+type StayQuoteLine struct {
+	Description string  `firestore:"description" json:"description"` // Season name or "Diária"
+	Nights      int     `firestore:"nights" json:"nights"`
+	NightlyRate float64 `firestore:"nightly_rate" json:"nightly_rate"`
+	Amount      float64 `firestore:"amount" json:"amount"`
+}
+
+// CalendarFeedID returns the stable ID of an external calendar URL
+func CalendarFeedID(feedURL string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(strings.TrimSpace(feedURL))))[:12]
+}
+
+// StayDate returns the calendar date of t as UTC midnight, the form of every
+// check-in, check-out and season date
+func StayDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// WithDefaults fills the unset minimum stay and feed IDs and truncates the
+// season dates to days
+func (c StayCalendar) WithDefaults() StayCalendar {
+	if c.MinNights == 0 {
+		c.MinNights = DefaultMinNights
+	}
+	seasons := make([]SeasonalRate, len(c.Seasons))
+	for i, season := range c.Seasons {
+		season.Name = strings.TrimSpace(season.Name)
+		season.StartDate, season.EndDate = StayDate(season.StartDate), StayDate(season.EndDate)
+		seasons[i] = season
+	}
+	c.Seasons = seasons
+	feeds := make([]CalendarFeed, len(c.Feeds))
+	for i, feed := range c.Feeds {
+		feed.Name = strings.TrimSpace(feed.Name)
+		feed.URL = strings.TrimSpace(feed.URL)
+		feed.ID = CalendarFeedID(feed.URL)
+		feeds[i] = feed
+	}
+	c.Feeds = feeds
+	return c
+}
+
+// Validate checks the rates, rules, seasons and feeds
+func (c StayCalendar) Validate() error {
+	if c.NightlyRate <= 0 {
+		return fmt.Errorf("nightly_rate must be positive")
+	}
+	if c.CleaningFee < 0 {
+		return fmt.Errorf("cleaning_fee cannot be negative")
+	}
+	if c.MinNights < 1 || c.MinNights > MaxStayNights {
+		return fmt.Errorf("min_nights must be between 1 and %d", MaxStayNights)
+	}
+	if c.MaxGuests < 0 {
+		return fmt.Errorf("max_guests cannot be negative")
+	}
+
+	for i, season := range c.Seasons {
+		if season.Name == "" {
+			return fmt.Errorf("seasons[%d]: name is required", i)
+		}
+		if season.EndDate.Before(season.StartDate) {
+			return fmt.Errorf("seasons[%d]: end_date must not be before start_date", i)
+		}
+		if season.NightlyRate <= 0 {
+			return fmt.Errorf("seasons[%d]: nightly_rate must be positive", i)
+		}
+		if season.MinNights < 0 || season.MinNights > MaxStayNights {
+			return fmt.Errorf("seasons[%d]: min_nights must be between 0 and %d", i, MaxStayNights)
+		}
+		for j := range i {
+			other := c.Seasons[j]
+			if !season.StartDate.After(other.EndDate) && !other.StartDate.After(season.EndDate) {
+				return fmt.Errorf("seasons[%d]: overlaps %q", i, other.Name)
+			}
+		}
+	}
+
+	if len(c.Feeds) > MaxCalendarFeeds {
+		return fmt.Errorf("at most %d feeds", MaxCalendarFeeds)
+	}
+	for i, feed := range c.Feeds {
+		if feed.Name == "" {
+			return fmt.Errorf("feeds[%d]: name is required", i)
+		}
+		parsed, err := url.Parse(feed.URL)
+		if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+			return fmt.Errorf("feeds[%d]: url must be an http(s) URL", i)
+		}
+		for j := range i {
+			if c.Feeds[j].ID == feed.ID {
+				return fmt.Errorf("feeds[%d]: duplicate url", i)
+			}
+		}
+	}
+	return nil
+}
+
+// SeasonOn returns the season of a night, nil outside every season
+func (c StayCalendar) SeasonOn(night time.Time) *SeasonalRate {
+	night = StayDate(night)
+	for i := range c.Seasons {
+		season := &c.Seasons[i]
+		if !night.Before(season.StartDate) && !night.After(season.EndDate) {
+			return season
+		}
+	}
+	return nil
+}
+
+// MinNightsFor returns the minimum stay of a check-in date: the season's
+// when it sets one
+func (c StayCalendar) MinNightsFor(checkIn time.Time) int {
+	if season := c.SeasonOn(checkIn); season != nil && season.MinNights > 0 {
+		return season.MinNights
+	}
+	return max(c.MinNights, DefaultMinNights)
+}
+
+// CheckStay checks the length of a stay from check-in to check-out
+// (departure day) against the limit of short stays and the minimum stay
+func (c StayCalendar) CheckStay(checkIn, checkOut time.Time) error {
+	checkIn = StayDate(checkIn)
+	nights := CalendarDays(checkIn, StayDate(checkOut))
+	if nights < 1 {
+		return fmt.Errorf("check_out must be after check_in")
+	}
+	if nights > MaxStayNights {
+		return fmt.Errorf("stays are limited to %d nights", MaxStayNights)
+	}
+	if minNights := c.MinNightsFor(checkIn); nights < minNights {
+		return fmt.Errorf("minimum stay from %s is %d nights", checkIn.Format("02/01/2006"), minNights)
+	}
+	return nil
+}
+
+// Quote checks and prices a stay
+func (c StayCalendar) Quote(checkIn, checkOut time.Time) (*StayQuote, error) {
+	if err := c.CheckStay(checkIn, checkOut); err != nil {
+		return nil, err
+	}
+	return c.Price(checkIn, checkOut), nil
+}
+
+// Price prices the nights from check-in to check-out, each at the rate of its
+// season, without checking the stay rules
+func (c StayCalendar) Price(checkIn, checkOut time.Time) *StayQuote {
+	checkIn, checkOut = StayDate(checkIn), StayDate(checkOut)
+	nights := max(0, CalendarDays(checkIn, checkOut))
+
+	quote := &StayQuote{CheckIn: checkIn, CheckOut: checkOut, Nights: nights, CleaningFee: c.CleaningFee}
+	for night := checkIn; night.Before(checkOut); night = night.AddDate(0, 0, 1) {
+		line := StayQuoteLine{Description: "Diária", Nights: 1, NightlyRate: c.NightlyRate}
+		if season := c.SeasonOn(night); season != nil {
+			line.Description, line.NightlyRate = season.Name, season.NightlyRate
+		}
+
+		if last := len(quote.Lines) - 1; last >= 0 && quote.Lines[last].Description == line.Description && quote.Lines[last].NightlyRate == line.NightlyRate {
+			quote.Lines[last].Nights++
+			continue
+		}
+		quote.Lines = append(quote.Lines, line)
+	}
+	for i := range quote.Lines {
+		quote.Lines[i].Amount = roundCents(float64(quote.Lines[i].Nights) * quote.Lines[i].NightlyRate)
+		quote.Lodging = roundCents(quote.Lodging + quote.Lines[i].Amount)
+	}
+	quote.Total = roundCents(quote.Lodging + quote.CleaningFee)
+
+	return quote
+}
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func stayDay(month time.Month, d int) time.Time {
+	return time.Date(2025, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func newTestStayCalendar() StayCalendar {
+	return StayCalendar{
+		NightlyRate: 400,
+		CleaningFee: 150,
+		MinNights:   2,
+		Seasons: []SeasonalRate{
+			{Name: "Réveillon", StartDate: stayDay(time.December, 28), EndDate: stayDay(time.December, 31), NightlyRate: 900, MinNights: 4},
+		},
+	}.WithDefaults()
+}
+
+func TestStayCalendarQuote(t *testing.T) {
+	calendar := newTestStayCalendar()
+
+	// Two nights at the standard rate, two in the season
+	quote, err := calendar.Quote(stayDay(time.December, 26), stayDay(time.December, 30))
+	if err != nil {
+		t.Fatalf("Quote() error = %v", err)
+	}
+	if quote.Nights != 4 || len(quote.Lines) != 2 {
+		t.Fatalf("Quote() = %d nights in %d lines, want 4 in 2", quote.Nights, len(quote.Lines))
+	}
+	if quote.Lines[0].Description != "Diária" || quote.Lines[0].Amount != 800 {
+		t.Errorf("Lines[0] = %+v, want 2 x 400", quote.Lines[0])
+	}
+	if quote.Lines[1].Description != "Réveillon" || quote.Lines[1].Amount != 1800 {
+		t.Errorf("Lines[1] = %+v, want 2 x 900", quote.Lines[1])
+	}
+	if quote.Lodging != 2600 || quote.Total != 2750 {
+		t.Errorf("Lodging = %v, Total = %v, want 2600 and 2750", quote.Lodging, quote.Total)
+	}
+}
+
+func TestStayCalendarCheckStay(t *testing.T) {
+	calendar := newTestStayCalendar()
+
+	tests := []struct {
+		name     string
+		checkIn  time.Time
+		checkOut time.Time
+		wantErr  bool
+	}{
+		{"Minimum stay", stayDay(time.November, 10), stayDay(time.November, 12), false},
+		{"Below the minimum stay", stayDay(time.November, 10), stayDay(time.November, 11), true},
+		{"Below the season's minimum stay", stayDay(time.December, 28), stayDay(time.December, 31), true},
+		{"Season's minimum stay", stayDay(time.December, 28), stayDay(time.January, 1).AddDate(1, 0, 0), false},
+		{"Check-out before check-in", stayDay(time.November, 12), stayDay(time.November, 10), true},
+		{"Longer than a short stay", stayDay(time.January, 1), stayDay(time.January, 1).AddDate(0, 0, MaxStayNights+1), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := calendar.CheckStay(tt.checkIn, tt.checkOut)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckStay() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestStayCalendarValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(c *StayCalendar)
+		wantErr bool
+	}{
+		{"Valid", func(c *StayCalendar) {}, false},
+		{"No nightly rate", func(c *StayCalendar) { c.NightlyRate = 0 }, true},
+		{"Overlapping seasons", func(c *StayCalendar) {
+			c.Seasons = append(c.Seasons, SeasonalRate{Name: "Ano novo", StartDate: stayDay(time.December, 31), EndDate: stayDay(time.December, 31), NightlyRate: 1000})
+		}, true},
+		{"Feed without http URL", func(c *StayCalendar) {
+			c.Feeds = []CalendarFeed{{Name: "Airbnb", URL: "webcal://airbnb.com/calendar/ical/1.ics"}}
+		}, true},
+		{"Duplicate feeds", func(c *StayCalendar) {
+			feed := CalendarFeed{Name: "Airbnb", URL: "https://www.airbnb.com/calendar/ical/1.ics"}
+			c.Feeds = []CalendarFeed{feed, feed}
+		}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calendar := newTestStayCalendar()
+			tt.mutate(&calendar)
+			err := calendar.WithDefaults().Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
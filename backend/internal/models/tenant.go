@@ -35,6 +35,21 @@ type Tenant struct {
 	IsActive        bool                   `firestore:"is_active" json:"is_active"`
 	IsPlatformAdmin bool                   `firestore:"is_platform_admin,omitempty" json:"is_platform_admin,omitempty"`
 
+	// Staleness and owner confirmation policy (nil = defaults, see EffectivePolicy)
+	Policy *TenantPolicy `firestore:"policy,omitempty" json:"policy,omitempty"`
+
+	// Outbound message channels and templates (nil = defaults)
+	Notifications *NotificationSettings `firestore:"notifications,omitempty" json:"notifications,omitempty"`
+
+	// Broker assignment of new leads (nil = primary broker of the property)
+	LeadRouting *LeadRoutingSettings `firestore:"lead_routing,omitempty" json:"lead_routing,omitempty"`
+
+	// First-response targets of new leads and their escalation (nil = defaults)
+	LeadSLA *LeadSLASettings `firestore:"lead_sla,omitempty" json:"lead_sla,omitempty"`
+
+	// Commission rate and split of closed deals (nil = defaults, see EffectiveCommission)
+	Commission *CommissionSettings `firestore:"commission,omitempty" json:"commission,omitempty"`
+
 	// Subscription
 	SubscriptionPlan      string     `firestore:"subscription_plan,omitempty" json:"subscription_plan,omitempty"`           // "free", "full"
 	SubscriptionStatus    string     `firestore:"subscription_status,omitempty" json:"subscription_status,omitempty"`       // "active", "trial", "expired", "cancelled"
@@ -0,0 +1,160 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Default tenant policy values (used for tenants without a policy)
+const (
+	DefaultStatusTTLDays            = 15 // status becomes pending_confirmation after this many days
+	DefaultHideAfterDays            = 30 // property hidden after this many days without confirmation
+	DefaultConfirmationTokenTTLDays = 7  // owner confirmation links expire after this many days
+	DefaultReminderIntervalDays     = 15 // owners are not asked again within this many days of a confirmation
+)
+
+// TenantPolicy configures property staleness and owner confirmations for a tenant
+// Stored in Tenant.Policy; zero values fall back to the defaults
+type TenantPolicy struct {
+	StatusTTLDays            int `firestore:"status_ttl_days" json:"status_ttl_days"`                         // Days until status becomes pending_confirmation
+	HideAfterDays            int `firestore:"hide_after_days" json:"hide_after_days"`                         // Days until the property is auto-hidden
+	ConfirmationTokenTTLDays int `firestore:"confirmation_token_ttl_days" json:"confirmation_token_ttl_days"` // Owner confirmation link lifetime
+	ReminderIntervalDays     int `firestore:"reminder_interval_days" json:"reminder_interval_days"`           // Minimum days since the last confirmation before the owner is asked again
+
+	// Visibilities that are switched to private when the property goes stale
+	// (nil = public and marketplace; empty = never auto-hide)
+	AutoHideVisibilities []PropertyVisibility `firestore:"auto_hide_visibilities" json:"auto_hide_visibilities"`
+
+	// Follow-ups of unanswered owner confirmations, in order (nil = default
+	// sequence; empty = no follow-ups)
+	ReminderSteps []ReminderStep `firestore:"reminder_steps" json:"reminder_steps"`
+}
+
+// ReminderAction is a follow-up of an unanswered owner confirmation
+type ReminderAction string
+
+const (
+	ReminderActionResend         ReminderAction = "resend"          // Send the confirmation link to the owner again
+	ReminderActionNotifyCaptador ReminderAction = "notify_captador" // Ask the captador to contact the owner
+	ReminderActionFlagPending    ReminderAction = "flag_pending"    // Mark the property as pending_confirmation
+)
+
+// ReminderStep runs an action when the owner has not responded AfterDays
+// days after the confirmation was sent
+type ReminderStep struct {
+	AfterDays int            `firestore:"after_days" json:"after_days"`
+	Action    ReminderAction `firestore:"action" json:"action"`
+}
+
+// DueAt returns when the step runs for a confirmation sent at sentAt
+func (s ReminderStep) DueAt(sentAt time.Time) time.Time {
+	return sentAt.AddDate(0, 0, s.AfterDays)
+}
+
+// DefaultReminderSteps is the follow-up sequence of tenants without one
+var DefaultReminderSteps = []ReminderStep{
+	{AfterDays: 3, Action: ReminderActionResend},
+	{AfterDays: 5, Action: ReminderActionNotifyCaptador},
+	{AfterDays: 7, Action: ReminderActionFlagPending},
+}
+
+// DefaultTenantPolicy returns the policy used by tenants that never configured one
+func DefaultTenantPolicy() TenantPolicy {
+	return TenantPolicy{
+		StatusTTLDays:            DefaultStatusTTLDays,
+		HideAfterDays:            DefaultHideAfterDays,
+		ConfirmationTokenTTLDays: DefaultConfirmationTokenTTLDays,
+		ReminderIntervalDays:     DefaultReminderIntervalDays,
+		AutoHideVisibilities:     []PropertyVisibility{PropertyVisibilityPublic, PropertyVisibilityMarketplace},
+		ReminderSteps:            DefaultReminderSteps,
+	}
+}
+
+// WithDefaults returns the policy with unset fields replaced by the defaults
+func (p TenantPolicy) WithDefaults() TenantPolicy {
+	defaults := DefaultTenantPolicy()
+	if p.StatusTTLDays == 0 {
+		p.StatusTTLDays = defaults.StatusTTLDays
+	}
+	if p.HideAfterDays == 0 {
+		p.HideAfterDays = defaults.HideAfterDays
+	}
+	if p.ConfirmationTokenTTLDays == 0 {
+		p.ConfirmationTokenTTLDays = defaults.ConfirmationTokenTTLDays
+	}
+	if p.ReminderIntervalDays == 0 {
+		p.ReminderIntervalDays = defaults.ReminderIntervalDays
+	}
+	if p.AutoHideVisibilities == nil {
+		p.AutoHideVisibilities = defaults.AutoHideVisibilities
+	}
+	if p.ReminderSteps == nil {
+		p.ReminderSteps = defaults.ReminderSteps
+	}
+	return p
+}
+
+// Validate checks the policy ranges; call it on a policy with defaults applied
+func (p TenantPolicy) Validate() error {
+	if p.StatusTTLDays < 1 || p.StatusTTLDays > 365 {
+		return fmt.Errorf("status_ttl_days must be between 1 and 365")
+	}
+	if p.HideAfterDays < p.StatusTTLDays || p.HideAfterDays > 365 {
+		return fmt.Errorf("hide_after_days must be between status_ttl_days and 365")
+	}
+	if p.ConfirmationTokenTTLDays < 1 || p.ConfirmationTokenTTLDays > 30 {
+		return fmt.Errorf("confirmation_token_ttl_days must be between 1 and 30")
+	}
+	if p.ReminderIntervalDays < 1 || p.ReminderIntervalDays > 365 {
+		return fmt.Errorf("reminder_interval_days must be between 1 and 365")
+	}
+	for _, visibility := range p.AutoHideVisibilities {
+		switch visibility {
+		case PropertyVisibilityNetwork, PropertyVisibilityMarketplace, PropertyVisibilityPublic:
+		default:
+			return fmt.Errorf("auto_hide_visibilities: invalid visibility %q (expected network, marketplace or public)", visibility)
+		}
+	}
+	previous := 0
+	for i, step := range p.ReminderSteps {
+		if step.AfterDays <= previous || step.AfterDays > 60 {
+			return fmt.Errorf("reminder_steps[%d]: after_days must be increasing and at most 60", i)
+		}
+		switch step.Action {
+		case ReminderActionResend, ReminderActionNotifyCaptador, ReminderActionFlagPending:
+		default:
+			return fmt.Errorf("reminder_steps[%d]: invalid action %q (expected resend, notify_captador or flag_pending)", i, step.Action)
+		}
+		previous = step.AfterDays
+	}
+	return nil
+}
+
+// AutoHides reports whether stale properties with the visibility are hidden
+func (p TenantPolicy) AutoHides(visibility PropertyVisibility) bool {
+	for _, v := range p.AutoHideVisibilities {
+		if v == visibility {
+			return true
+		}
+	}
+	return false
+}
+
+// ConfirmationTokenTTL returns the lifetime of owner confirmation links
+func (p TenantPolicy) ConfirmationTokenTTL() time.Duration {
+	return time.Duration(p.ConfirmationTokenTTLDays) * 24 * time.Hour
+}
+
+// ReminderInterval returns the minimum time since the last confirmation
+// before a new owner confirmation request is scheduled
+func (p TenantPolicy) ReminderInterval() time.Duration {
+	return time.Duration(p.ReminderIntervalDays) * 24 * time.Hour
+}
+
+// EffectivePolicy returns the tenant policy with defaults applied
+func (t *Tenant) EffectivePolicy() TenantPolicy {
+	if t == nil || t.Policy == nil {
+		return DefaultTenantPolicy()
+	}
+	return t.Policy.WithDefaults()
+}
@@ -0,0 +1,51 @@
+package models
+
+import "testing"
+
+func TestTenantPolicyValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  TenantPolicy
+		wantErr bool
+	}{
+		{"Defaults", TenantPolicy{}, false},
+		{"Custom", TenantPolicy{StatusTTLDays: 10, HideAfterDays: 10, ConfirmationTokenTTLDays: 3}, false},
+		{"Never auto-hide", TenantPolicy{AutoHideVisibilities: []PropertyVisibility{}}, false},
+		{"Negative status TTL", TenantPolicy{StatusTTLDays: -1}, true},
+		{"Hide before status TTL", TenantPolicy{StatusTTLDays: 20, HideAfterDays: 10}, true},
+		{"Token TTL too long", TenantPolicy{ConfirmationTokenTTLDays: 31}, true},
+		{"Reminder interval too long", TenantPolicy{ReminderIntervalDays: 400}, true},
+		{"Private cannot be auto-hidden", TenantPolicy{AutoHideVisibilities: []PropertyVisibility{PropertyVisibilityPrivate}}, true},
+		{"No reminders", TenantPolicy{ReminderSteps: []ReminderStep{}}, false},
+		{"Reminders out of order", TenantPolicy{ReminderSteps: []ReminderStep{{AfterDays: 5, Action: ReminderActionResend}, {AfterDays: 3, Action: ReminderActionFlagPending}}}, true},
+		{"Unknown reminder action", TenantPolicy{ReminderSteps: []ReminderStep{{AfterDays: 3, Action: "call"}}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.WithDefaults().Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTenantEffectivePolicy(t *testing.T) {
+	var tenant *Tenant
+	if got := tenant.EffectivePolicy(); got.HideAfterDays != DefaultHideAfterDays {
+		t.Errorf("nil tenant HideAfterDays = %d, expected %d", got.HideAfterDays, DefaultHideAfterDays)
+	}
+
+	tenant = &Tenant{Policy: &TenantPolicy{HideAfterDays: 60, AutoHideVisibilities: []PropertyVisibility{}}}
+	policy := tenant.EffectivePolicy()
+	if policy.HideAfterDays != 60 || policy.StatusTTLDays != DefaultStatusTTLDays {
+		t.Errorf("EffectivePolicy() = %+v, expected hide_after_days 60 and default status TTL", policy)
+	}
+	if policy.AutoHides(PropertyVisibilityPublic) {
+		t.Errorf("empty AutoHideVisibilities should never auto-hide")
+	}
+	if !DefaultTenantPolicy().AutoHides(PropertyVisibilityMarketplace) {
+		t.Errorf("default policy should auto-hide marketplace properties")
+	}
+}
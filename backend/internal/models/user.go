@@ -40,6 +40,9 @@ type User struct {
 	// Examples: "properties.view", "properties.edit", "brokers.manage", "settings.edit"
 	Permissions []string `firestore:"permissions,omitempty" json:"permissions,omitempty"`
 
+	// Role default permissions revoked from this user
+	DeniedPermissions []string `firestore:"denied_permissions,omitempty" json:"denied_permissions,omitempty"`
+
 	// Profile
 	PhotoURL string `firestore:"photo_url,omitempty" json:"photo_url,omitempty"`
 
@@ -95,37 +98,69 @@ func (u User) MarshalJSON() ([]byte, error) {
 }
 
 // HasPermission checks if user has a specific permission
+// (granted by its role unless revoked, or explicitly)
 func (u *User) HasPermission(permission string) bool {
 	// Admin role has all permissions
 	if u.Role == "admin" {
 		return true
 	}
 
-	// Check in permissions array
-	for _, p := range u.Permissions {
+	if u.isDenied(permission) {
+		return false
+	}
+
+	if RoleHasPermission(u.Role, permission) {
+		return true
+	}
+
+	return u.hasExplicitPermission(permission)
+}
+
+// isDenied checks the revoked role defaults only
+func (u *User) isDenied(permission string) bool {
+	for _, p := range u.DeniedPermissions {
 		if p == permission {
 			return true
 		}
 	}
+	return false
+}
 
+// hasExplicitPermission checks the permissions array only
+func (u *User) hasExplicitPermission(permission string) bool {
+	for _, p := range u.Permissions {
+		if p == permission {
+			return true
+		}
+	}
 	return false
 }
 
-// AddPermission adds a permission to the user
+// AddPermission adds a permission to the user, lifting any revocation of it
 func (u *User) AddPermission(permission string) {
-	if !u.HasPermission(permission) {
+	u.DeniedPermissions = removePermission(u.DeniedPermissions, permission)
+	if !u.hasExplicitPermission(permission) {
 		u.Permissions = append(u.Permissions, permission)
 	}
 }
 
-// RemovePermission removes a permission from the user
+// RemovePermission removes a permission from the user. A permission its role
+// grants by default is recorded as denied, so the removal takes effect.
 func (u *User) RemovePermission(permission string) {
-	for i, p := range u.Permissions {
+	u.Permissions = removePermission(u.Permissions, permission)
+	if RoleHasPermission(u.Role, permission) && !u.isDenied(permission) {
+		u.DeniedPermissions = append(u.DeniedPermissions, permission)
+	}
+}
+
+// removePermission returns permissions without permission
+func removePermission(permissions []string, permission string) []string {
+	for i, p := range permissions {
 		if p == permission {
-			u.Permissions = append(u.Permissions[:i], u.Permissions[i+1:]...)
-			break
+			return append(permissions[:i], permissions[i+1:]...)
 		}
 	}
+	return permissions
 }
 
 // ValidRoles returns the list of valid roles for users
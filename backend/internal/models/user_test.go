@@ -30,6 +30,31 @@ func TestUser_HasPermission(t *testing.T) {
 			permission: "properties.edit",
 			expected:   true,
 		},
+		{
+			name: "Broker has role default permission",
+			user: &User{
+				Role: "broker",
+			},
+			permission: PermissionLeadsEdit,
+			expected:   true,
+		},
+		{
+			name: "Broker lacks permission outside role defaults",
+			user: &User{
+				Role: "broker",
+			},
+			permission: PermissionBrokersManage,
+			expected:   false,
+		},
+		{
+			name: "Broker with explicit grant",
+			user: &User{
+				Role:        "broker",
+				Permissions: []string{PermissionImportRun},
+			},
+			permission: PermissionImportRun,
+			expected:   true,
+		},
 		{
 			name: "Manager does not have permission",
 			user: &User{
@@ -97,6 +122,24 @@ func TestUser_RemovePermission(t *testing.T) {
 	}
 }
 
+// Test revoking and granting back a role default permission
+func TestUser_RevokeRoleDefault(t *testing.T) {
+	user := &User{Role: "broker"}
+
+	user.RemovePermission(PermissionLeadsEdit)
+	if user.HasPermission(PermissionLeadsEdit) {
+		t.Error("Expected broker to NOT have leads.edit after revoking it")
+	}
+	if !user.HasPermission(PermissionLeadsView) {
+		t.Error("Expected broker to keep the other role defaults")
+	}
+
+	user.AddPermission(PermissionLeadsEdit)
+	if !user.HasPermission(PermissionLeadsEdit) || len(user.DeniedPermissions) != 0 {
+		t.Errorf("Expected granting leads.edit to lift the revocation, denied = %v", user.DeniedPermissions)
+	}
+}
+
 // Test IsValidUserRole
 func TestIsValidUserRole(t *testing.T) {
 	tests := []struct {
@@ -105,8 +148,8 @@ func TestIsValidUserRole(t *testing.T) {
 	}{
 		{"admin", true},
 		{"manager", true},
-		{"broker", false},
-		{"broker_admin", false},
+		{"broker", true},
+		{"broker_admin", true},
 		{"invalid", false},
 		{"", false},
 	}
@@ -125,13 +168,15 @@ func TestIsValidUserRole(t *testing.T) {
 func TestValidUserRoles(t *testing.T) {
 	roles := ValidUserRoles()
 
-	if len(roles) != 2 {
-		t.Errorf("Expected 2 valid roles, got %d", len(roles))
+	if len(roles) != 4 {
+		t.Errorf("Expected 4 valid roles, got %d", len(roles))
 	}
 
 	expectedRoles := map[string]bool{
-		"admin":   true,
-		"manager": true,
+		"admin":        true,
+		"manager":      true,
+		"broker":       true,
+		"broker_admin": true,
 	}
 
 	for _, role := range roles {
@@ -140,3 +185,55 @@ func TestValidUserRoles(t *testing.T) {
 		}
 	}
 }
+
+// Test DefaultPermissionsForRole
+func TestDefaultPermissionsForRole(t *testing.T) {
+	tests := []struct {
+		role       string
+		permission string
+		expected   bool
+	}{
+		{"admin", PermissionSettingsEdit, true},
+		{"broker_admin", PermissionUsersManage, true},
+		{"manager", PermissionBrokersManage, true},
+		{"manager", PermissionPropertiesDelete, false},
+		{"manager", PermissionUsersManage, false},
+		{"broker", PermissionPropertiesEdit, true},
+		{"broker", PermissionImportRun, false},
+		{"unknown", PermissionPropertiesView, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.role+"/"+tt.permission, func(t *testing.T) {
+			result := RoleHasPermission(tt.role, tt.permission)
+			if result != tt.expected {
+				t.Errorf("RoleHasPermission(%s, %s) = %v, expected %v", tt.role, tt.permission, result, tt.expected)
+			}
+		})
+	}
+
+	// Every role default must be in the catalog
+	for _, role := range ValidUserRoles() {
+		for _, permission := range DefaultPermissionsForRole(role) {
+			if !IsValidPermission(permission) {
+				t.Errorf("Role %s has permission outside the catalog: %s", role, permission)
+			}
+		}
+	}
+}
+
+// Test Broker.HasPermission
+func TestBroker_HasPermission(t *testing.T) {
+	broker := &Broker{}
+	if !broker.HasPermission(PermissionLeadsView) {
+		t.Error("Expected broker without role to have broker defaults")
+	}
+	if broker.HasPermission(PermissionUsersManage) {
+		t.Error("Expected broker without role to NOT have users.manage")
+	}
+
+	brokerAdmin := &Broker{Role: "broker_admin"}
+	if !brokerAdmin.HasPermission(PermissionUsersManage) {
+		t.Error("Expected broker_admin to have users.manage")
+	}
+}
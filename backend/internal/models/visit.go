@@ -0,0 +1,280 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// VisitStatus defines the status of a property visit
+type VisitStatus string
+
+const (
+	VisitStatusRequested VisitStatus = "requested" // Asked for by the lead, waiting for the broker
+	VisitStatusConfirmed VisitStatus = "confirmed"
+	VisitStatusCancelled VisitStatus = "cancelled"
+	VisitStatusCompleted VisitStatus = "completed"
+	VisitStatusNoShow    VisitStatus = "no_show" // The lead did not show up
+)
+
+// VisitSource tells where a visit was booked
+type VisitSource string
+
+const (
+	VisitSourcePublic VisitSource = "public" // Requested from the property page
+	VisitSourceAdmin  VisitSource = "admin"  // Scheduled by the broker
+)
+
+// Visit is a visit of a lead to a property, shown by a broker
+// Collection: /tenants/{tenantId}/visits/{visitId}
+type Visit struct {
+	ID         string `firestore:"-" json:"id"`
+	TenantID   string `firestore:"tenant_id" json:"tenant_id"`
+	PropertyID string `firestore:"property_id" json:"property_id"`
+	LeadID     string `firestore:"lead_id" json:"lead_id"`
+	BrokerID   string `firestore:"broker_id" json:"broker_id"`
+
+	Status  VisitStatus `firestore:"status" json:"status"`
+	Source  VisitSource `firestore:"source" json:"source"`
+	StartAt time.Time   `firestore:"start_at" json:"start_at"`
+	EndAt   time.Time   `firestore:"end_at" json:"end_at"`
+	Notes   string      `firestore:"notes,omitempty" json:"notes,omitempty"` // Message of the lead or notes of the broker
+
+	// SHA-256 of the token that lets the lead cancel from a public link
+	CancelTokenHash string `firestore:"cancel_token_hash,omitempty" json:"-"`
+
+	// Status changes
+	ConfirmedAt  *time.Time `firestore:"confirmed_at,omitempty" json:"confirmed_at,omitempty"`
+	CancelledAt  *time.Time `firestore:"cancelled_at,omitempty" json:"cancelled_at,omitempty"`
+	CancelledBy  string     `firestore:"cancelled_by,omitempty" json:"cancelled_by,omitempty"` // Member ID, or "lead"
+	CancelReason string     `firestore:"cancel_reason,omitempty" json:"cancel_reason,omitempty"`
+	CompletedAt  *time.Time `firestore:"completed_at,omitempty" json:"completed_at,omitempty"` // Completed or no-show
+
+	// Metadata
+	CreatedAt time.Time `firestore:"created_at" json:"created_at"`
+	UpdatedAt time.Time `firestore:"updated_at" json:"updated_at"`
+}
+
+// VisitCancelledByLead is the CancelledBy of visits cancelled from the public link
+const VisitCancelledByLead = "lead"
+
+// HoldsSlot reports whether the visit takes the broker's time (requested or confirmed)
+func (v *Visit) HoldsSlot() bool {
+	return v.Status == VisitStatusRequested || v.Status == VisitStatusConfirmed
+}
+
+// Overlaps reports whether the visit overlaps the period [start, end)
+func (v *Visit) Overlaps(start, end time.Time) bool {
+	return v.StartAt.Before(end) && start.Before(v.EndAt)
+}
+
+// BrokerAvailability is the weekly calendar of a broker for visits
+// (Broker.Availability)
+type BrokerAvailability struct {
+	// IANA time zone of the windows (default America/Sao_Paulo)
+	Timezone string `firestore:"timezone,omitempty" json:"timezone,omitempty"`
+
+	// Weekly hours available for visits
+	Windows []AvailabilityWindow `firestore:"windows" json:"windows"`
+
+	// Time off (vacations, appointments)
+	Blocks []AvailabilityBlock `firestore:"blocks,omitempty" json:"blocks,omitempty"`
+
+	// Length of a visit slot in minutes (default 60)
+	VisitMinutes int `firestore:"visit_minutes,omitempty" json:"visit_minutes,omitempty"`
+}
+
+// AvailabilityWindow is a period of a weekday, in "HH:MM" local time
+type AvailabilityWindow struct {
+	Weekday time.Weekday `firestore:"weekday" json:"weekday"` // 0 = Sunday
+	Start   string       `firestore:"start" json:"start"`     // ex: "09:00"
+	End     string       `firestore:"end" json:"end"`         // ex: "18:00"
+}
+
+// AvailabilityBlock is a period in which the broker is not available
+type AvailabilityBlock struct {
+	StartAt time.Time `firestore:"start_at" json:"start_at"`
+	EndAt   time.Time `firestore:"end_at" json:"end_at"`
+	Reason  string    `firestore:"reason,omitempty" json:"reason,omitempty"`
+}
+
+// Defaults and limits of broker availability
+const (
+	DefaultVisitTimezone = "America/Sao_Paulo"
+	DefaultVisitMinutes  = 60
+	MaxVisitMinutes      = 4 * 60
+)
+
+// DefaultBrokerAvailability returns the calendar of brokers without one:
+// Monday to Friday 09:00-18:00 and Saturday 09:00-13:00
+func DefaultBrokerAvailability() BrokerAvailability {
+	availability := BrokerAvailability{
+		Timezone:     DefaultVisitTimezone,
+		VisitMinutes: DefaultVisitMinutes,
+	}
+	for day := time.Monday; day <= time.Friday; day++ {
+		availability.Windows = append(availability.Windows, AvailabilityWindow{Weekday: day, Start: "09:00", End: "18:00"})
+	}
+	availability.Windows = append(availability.Windows, AvailabilityWindow{Weekday: time.Saturday, Start: "09:00", End: "13:00"})
+	return availability
+}
+
+// EffectiveAvailability returns the broker's calendar, or the default one
+// when none is configured
+func (b *Broker) EffectiveAvailability() BrokerAvailability {
+	if b == nil || b.Availability == nil {
+		return DefaultBrokerAvailability()
+	}
+	return b.Availability.WithDefaults()
+}
+
+// WithDefaults fills the unset time zone and visit length
+func (a BrokerAvailability) WithDefaults() BrokerAvailability {
+	if a.Timezone == "" {
+		a.Timezone = DefaultVisitTimezone
+	}
+	if a.VisitMinutes == 0 {
+		a.VisitMinutes = DefaultVisitMinutes
+	}
+	return a
+}
+
+// Validate checks the time zone, windows, blocks and visit length
+func (a BrokerAvailability) Validate() error {
+	if _, err := time.LoadLocation(a.Timezone); err != nil {
+		return fmt.Errorf("timezone: unknown time zone %q", a.Timezone)
+	}
+	if a.VisitMinutes < 15 || a.VisitMinutes > MaxVisitMinutes {
+		return fmt.Errorf("visit_minutes must be between 15 and %d", MaxVisitMinutes)
+	}
+	for i, window := range a.Windows {
+		if window.Weekday < time.Sunday || window.Weekday > time.Saturday {
+			return fmt.Errorf("windows[%d]: weekday must be between 0 (Sunday) and 6 (Saturday)", i)
+		}
+		start, err := parseClock(window.Start)
+		if err != nil {
+			return fmt.Errorf("windows[%d]: start: %w", i, err)
+		}
+		end, err := parseClock(window.End)
+		if err != nil {
+			return fmt.Errorf("windows[%d]: end: %w", i, err)
+		}
+		if end <= start {
+			return fmt.Errorf("windows[%d]: end must be after start", i)
+		}
+	}
+	for i, block := range a.Blocks {
+		if !block.EndAt.After(block.StartAt) {
+			return fmt.Errorf("blocks[%d]: end_at must be after start_at", i)
+		}
+	}
+	return nil
+}
+
+// Location returns the time zone of the windows (Brasília time when unknown)
+func (a BrokerAvailability) Location() *time.Location {
+	if location, err := time.LoadLocation(a.Timezone); err == nil {
+		return location
+	}
+	return time.FixedZone("BRT", -3*60*60)
+}
+
+// VisitLength returns the length of a visit slot
+func (a BrokerAvailability) VisitLength() time.Duration {
+	return time.Duration(a.WithDefaults().VisitMinutes) * time.Minute
+}
+
+// Covers reports whether the period [start, end) falls within a window of its
+// day and outside every block
+func (a BrokerAvailability) Covers(start, end time.Time) bool {
+	location := a.Location()
+	localStart, localEnd := start.In(location), end.In(location)
+	if localEnd.After(midnightAfter(localStart)) {
+		return false // Windows never cross midnight
+	}
+
+	dayStart := midnightOf(localStart)
+	covered := false
+	for _, window := range a.Windows {
+		if window.Weekday != localStart.Weekday() {
+			continue
+		}
+		windowStart, errStart := parseClock(window.Start)
+		windowEnd, errEnd := parseClock(window.End)
+		if errStart != nil || errEnd != nil {
+			continue
+		}
+		if !localStart.Before(dayStart.Add(windowStart)) && !localEnd.After(dayStart.Add(windowEnd)) {
+			covered = true
+			break
+		}
+	}
+	return covered && !a.Blocked(start, end)
+}
+
+// Blocked reports whether the period [start, end) overlaps a block
+func (a BrokerAvailability) Blocked(start, end time.Time) bool {
+	for _, block := range a.Blocks {
+		if block.StartAt.Before(end) && start.Before(block.EndAt) {
+			return true
+		}
+	}
+	return false
+}
+
+// VisitSlot is a period offered for a visit
+type VisitSlot struct {
+	StartAt time.Time `json:"start_at"`
+	EndAt   time.Time `json:"end_at"`
+}
+
+// Slots returns the visit slots of the windows that start within [from,
+// until), outside every block, earliest first
+func (a BrokerAvailability) Slots(from, until time.Time) []VisitSlot {
+	location := a.Location()
+	length := a.VisitLength()
+
+	var slots []VisitSlot
+	for day := midnightOf(from.In(location)); day.Before(until); day = midnightAfter(day) {
+		var daySlots []VisitSlot
+		for _, window := range a.Windows {
+			if window.Weekday != day.Weekday() {
+				continue
+			}
+			windowStart, errStart := parseClock(window.Start)
+			windowEnd, errEnd := parseClock(window.End)
+			if errStart != nil || errEnd != nil {
+				continue
+			}
+			for start := day.Add(windowStart); !start.Add(length).After(day.Add(windowEnd)); start = start.Add(length) {
+				end := start.Add(length)
+				if start.Before(from) || !start.Before(until) || a.Blocked(start, end) {
+					continue
+				}
+				daySlots = append(daySlots, VisitSlot{StartAt: start, EndAt: end})
+			}
+		}
+		sort.Slice(daySlots, func(i, j int) bool { return daySlots[i].StartAt.Before(daySlots[j].StartAt) })
+		slots = append(slots, daySlots...)
+	}
+	return slots
+}
+
+// parseClock parses a "HH:MM" time of day into the duration since midnight
+func parseClock(clock string) (time.Duration, error) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q (expected HH:MM)", clock)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// midnightOf returns the start of the day of t, in its location
+func midnightOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// midnightAfter returns the start of the day after t, in its location
+func midnightAfter(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+}
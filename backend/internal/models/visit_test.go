@@ -0,0 +1,96 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBrokerAvailabilityValidate(t *testing.T) {
+	start := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		availability BrokerAvailability
+		wantErr      bool
+	}{
+		{"Default", DefaultBrokerAvailability(), false},
+		{"Unknown time zone", BrokerAvailability{Timezone: "Mars/Olympus", VisitMinutes: 60}, true},
+		{"Visit too short", BrokerAvailability{Timezone: "UTC", VisitMinutes: 10}, true},
+		{"Visit too long", BrokerAvailability{Timezone: "UTC", VisitMinutes: 300}, true},
+		{"Invalid clock", BrokerAvailability{Timezone: "UTC", VisitMinutes: 60, Windows: []AvailabilityWindow{{Weekday: time.Monday, Start: "9h", End: "18:00"}}}, true},
+		{"Window ends before start", BrokerAvailability{Timezone: "UTC", VisitMinutes: 60, Windows: []AvailabilityWindow{{Weekday: time.Monday, Start: "18:00", End: "09:00"}}}, true},
+		{"Invalid weekday", BrokerAvailability{Timezone: "UTC", VisitMinutes: 60, Windows: []AvailabilityWindow{{Weekday: 7, Start: "09:00", End: "18:00"}}}, true},
+		{"Empty block", BrokerAvailability{Timezone: "UTC", VisitMinutes: 60, Blocks: []AvailabilityBlock{{StartAt: start, EndAt: start}}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.availability.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBrokerAvailabilityCovers(t *testing.T) {
+	availability := DefaultBrokerAvailability()
+	location := availability.Location()
+	monday := time.Date(2024, 7, 1, 0, 0, 0, 0, location)
+	availability.Blocks = []AvailabilityBlock{{StartAt: monday.Add(14 * time.Hour), EndAt: monday.Add(16 * time.Hour)}}
+
+	tests := []struct {
+		name   string
+		start  time.Time
+		length time.Duration
+		want   bool
+	}{
+		{"Within Monday hours", monday.Add(10 * time.Hour), time.Hour, true},
+		{"Ends at closing time", monday.Add(17 * time.Hour), time.Hour, true},
+		{"Runs past closing time", monday.Add(17*time.Hour + 30*time.Minute), time.Hour, false},
+		{"Before opening", monday.Add(8 * time.Hour), time.Hour, false},
+		{"Overlaps a block", monday.Add(15 * time.Hour), time.Hour, false},
+		{"Saturday morning", monday.AddDate(0, 0, 5).Add(10 * time.Hour), time.Hour, true},
+		{"Saturday afternoon", monday.AddDate(0, 0, 5).Add(14 * time.Hour), time.Hour, false},
+		{"Sunday", monday.AddDate(0, 0, 6).Add(10 * time.Hour), time.Hour, false},
+		{"Same time in UTC", monday.Add(10 * time.Hour).UTC(), time.Hour, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := availability.Covers(tt.start, tt.start.Add(tt.length)); got != tt.want {
+				t.Errorf("Covers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBrokerAvailabilitySlots(t *testing.T) {
+	availability := DefaultBrokerAvailability()
+	location := availability.Location()
+	monday := time.Date(2024, 7, 1, 0, 0, 0, 0, location)
+	availability.Blocks = []AvailabilityBlock{{StartAt: monday.Add(12 * time.Hour), EndAt: monday.Add(14 * time.Hour)}}
+
+	// Monday from 11:30: 12:00-14:00 blocked, so 14:00 to 17:00 (4 slots); Tuesday 9 slots
+	slots := availability.Slots(monday.Add(11*time.Hour+30*time.Minute), monday.AddDate(0, 0, 2))
+	if len(slots) != 13 {
+		t.Fatalf("Slots() returned %d slots, want 13", len(slots))
+	}
+	if want := monday.Add(14 * time.Hour); !slots[0].StartAt.Equal(want) {
+		t.Errorf("first slot starts at %v, want %v", slots[0].StartAt, want)
+	}
+	if want := monday.AddDate(0, 0, 1).Add(17 * time.Hour); !slots[len(slots)-1].StartAt.Equal(want) {
+		t.Errorf("last slot starts at %v, want %v", slots[len(slots)-1].StartAt, want)
+	}
+	for i := 1; i < len(slots); i++ {
+		if !slots[i].StartAt.After(slots[i-1].StartAt) {
+			t.Fatalf("slots are not sorted at %d", i)
+		}
+	}
+
+	// Weekend: Saturday morning only
+	saturday := monday.AddDate(0, 0, 5)
+	if got := len(availability.Slots(saturday, saturday.AddDate(0, 0, 2))); got != 4 {
+		t.Errorf("weekend Slots() returned %d slots, want 4", got)
+	}
+}
@@ -0,0 +1,122 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+// RetryPolicy configures the exponential backoff between send attempts
+type RetryPolicy struct {
+	MaxAttempts  int           // Total attempts (1 = no retry)
+	InitialDelay time.Duration // Delay before the second attempt
+	MaxDelay     time.Duration // Upper bound of the delay
+}
+
+// DefaultRetryPolicy retries 3 times over roughly 7 seconds
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:  4,
+		InitialDelay: time.Second,
+		MaxDelay:     30 * time.Second,
+	}
+}
+
+// delay returns the wait before the given attempt (attempt >= 2)
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	d := p.InitialDelay
+	for i := 2; i < attempt; i++ {
+		d *= 2
+		if p.MaxDelay > 0 && d >= p.MaxDelay {
+			return p.MaxDelay
+		}
+	}
+	return d
+}
+
+// Dispatcher routes messages to the notifier of their channel, retrying
+// transient provider errors with exponential backoff
+type Dispatcher struct {
+	notifiers map[models.NotificationChannel]Notifier
+	retry     RetryPolicy
+	sleep     func(ctx context.Context, d time.Duration) error
+}
+
+// NewDispatcher creates a dispatcher for the given notifiers
+func NewDispatcher(retry RetryPolicy, notifiers ...Notifier) *Dispatcher {
+	if retry.MaxAttempts < 1 {
+		retry.MaxAttempts = 1
+	}
+
+	d := &Dispatcher{
+		notifiers: make(map[models.NotificationChannel]Notifier),
+		retry:     retry,
+		sleep:     sleepContext,
+	}
+	for _, n := range notifiers {
+		d.Register(n)
+	}
+	return d
+}
+
+// Register adds (or replaces) the notifier of a channel
+func (d *Dispatcher) Register(n Notifier) {
+	d.notifiers[n.Channel()] = n
+}
+
+// Supports reports whether a notifier is registered for the channel
+func (d *Dispatcher) Supports(channel models.NotificationChannel) bool {
+	if d == nil {
+		return false
+	}
+	_, ok := d.notifiers[channel]
+	return ok
+}
+
+// Send delivers a message through the notifier of its channel. Transient
+// errors are retried; permanent errors and context cancellation stop early.
+func (d *Dispatcher) Send(ctx context.Context, msg Message) (*Result, error) {
+	notifier, ok := d.notifiers[msg.Channel]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNoNotifier, msg.Channel)
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= d.retry.MaxAttempts; attempt++ {
+		if attempt > 1 {
+			if err := d.sleep(ctx, d.retry.delay(attempt)); err != nil {
+				return &Result{Attempts: attempt - 1}, fmt.Errorf("%w (last error: %v)", err, lastErr)
+			}
+		}
+
+		result, err := notifier.Send(ctx, msg)
+		if err == nil {
+			result.Attempts = attempt
+			return result, nil
+		}
+
+		lastErr = err
+		if IsPermanent(err) {
+			return &Result{Attempts: attempt}, err
+		}
+		log.Printf("⚠️  %s delivery attempt %d/%d failed: %v", msg.Channel, attempt, d.retry.MaxAttempts, err)
+	}
+
+	return &Result{Attempts: d.retry.MaxAttempts}, lastErr
+}
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,104 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+// newTestDispatcher returns a dispatcher that records its backoff delays
+// instead of sleeping
+func newTestDispatcher(retry RetryPolicy, notifiers ...Notifier) (*Dispatcher, *[]time.Duration) {
+	d := NewDispatcher(retry, notifiers...)
+	var delays []time.Duration
+	d.sleep = func(ctx context.Context, delay time.Duration) error {
+		delays = append(delays, delay)
+		return ctx.Err()
+	}
+	return d, &delays
+}
+
+func TestDispatcher_RetriesWithBackoff(t *testing.T) {
+	fake := NewFakeNotifier(models.NotificationChannelSMS)
+	fake.FailNext(errors.New("timeout"), errors.New("HTTP 503"))
+
+	d, delays := newTestDispatcher(RetryPolicy{MaxAttempts: 4, InitialDelay: time.Second, MaxDelay: 30 * time.Second}, fake)
+
+	result, err := d.Send(context.Background(), Message{Channel: models.NotificationChannelSMS, To: "11987654321", Body: "oi"})
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Attempts)
+	assert.Equal(t, "fake-sms-1", result.ProviderMessageID)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, *delays)
+	assert.Len(t, fake.Sent(), 1)
+}
+
+func TestDispatcher_GivesUpAfterMaxAttempts(t *testing.T) {
+	fake := NewFakeNotifier(models.NotificationChannelSMS)
+	fake.FailNext(errors.New("a"), errors.New("b"), errors.New("c"))
+
+	d, delays := newTestDispatcher(RetryPolicy{MaxAttempts: 3, InitialDelay: time.Second, MaxDelay: 1500 * time.Millisecond}, fake)
+
+	result, err := d.Send(context.Background(), Message{Channel: models.NotificationChannelSMS, To: "11987654321"})
+	require.EqualError(t, err, "c")
+	assert.Equal(t, 3, result.Attempts)
+	assert.Equal(t, []time.Duration{time.Second, 1500 * time.Millisecond}, *delays)
+	assert.Empty(t, fake.Sent())
+}
+
+func TestDispatcher_PermanentErrorStopsRetries(t *testing.T) {
+	fake := NewFakeNotifier(models.NotificationChannelWhatsApp)
+	fake.FailNext(Permanent(errors.New("invalid number")))
+
+	d, delays := newTestDispatcher(DefaultRetryPolicy(), fake)
+
+	result, err := d.Send(context.Background(), Message{Channel: models.NotificationChannelWhatsApp, To: "11987654321"})
+	require.Error(t, err)
+	assert.True(t, IsPermanent(err))
+	assert.Equal(t, 1, result.Attempts)
+	assert.Empty(t, *delays)
+}
+
+func TestDispatcher_CancelledContextStopsRetries(t *testing.T) {
+	fake := NewFakeNotifier(models.NotificationChannelSMS)
+	fake.FailNext(errors.New("timeout"))
+
+	d, _ := newTestDispatcher(DefaultRetryPolicy(), fake)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result, err := d.Send(ctx, Message{Channel: models.NotificationChannelSMS, To: "11987654321"})
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, result.Attempts)
+}
+
+func TestDispatcher_UnknownChannel(t *testing.T) {
+	d := NewDispatcher(DefaultRetryPolicy(), NewFakeNotifier(models.NotificationChannelEmail))
+
+	assert.True(t, d.Supports(models.NotificationChannelEmail))
+	assert.False(t, d.Supports(models.NotificationChannelSMS))
+
+	_, err := d.Send(context.Background(), Message{Channel: models.NotificationChannelSMS})
+	assert.ErrorIs(t, err, ErrNoNotifier)
+}
+
+func TestPhoneE164(t *testing.T) {
+	assert.Equal(t, "+5511987654321", PhoneE164("(11) 98765-4321"))
+	assert.Equal(t, "+551133334444", PhoneE164("11 3333-4444"))
+	assert.Equal(t, "+5511987654321", PhoneE164("+55 11 98765-4321"))
+	assert.Equal(t, "", PhoneE164(""))
+}
+
+func TestAdvances(t *testing.T) {
+	assert.True(t, Advances("", models.DeliveryStatusSent))
+	assert.True(t, Advances(models.DeliveryStatusAccepted, models.DeliveryStatusDelivered))
+	assert.False(t, Advances(models.DeliveryStatusRead, models.DeliveryStatusDelivered))
+	assert.False(t, Advances(models.DeliveryStatusDelivered, models.DeliveryStatusSent))
+	assert.True(t, Advances(models.DeliveryStatusSent, models.DeliveryStatusFailed))
+	assert.False(t, Advances(models.DeliveryStatusDelivered, models.DeliveryStatusFailed))
+}
@@ -0,0 +1,123 @@
+package notify
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+// EmailConfig configures the SMTP server
+type EmailConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string // Sender address
+	FromName string
+}
+
+// EmailNotifier sends emails through SMTP
+type EmailNotifier struct {
+	config   EmailConfig
+	sendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewEmailNotifier creates an SMTP email notifier
+func NewEmailNotifier(config EmailConfig) *EmailNotifier {
+	if config.Port == 0 {
+		config.Port = 587
+	}
+	return &EmailNotifier{
+		config:   config,
+		sendMail: smtp.SendMail,
+	}
+}
+
+// Channel returns models.NotificationChannelEmail
+func (n *EmailNotifier) Channel() models.NotificationChannel {
+	return models.NotificationChannelEmail
+}
+
+// Send sends the message as a plain text (and optional HTML) email. SMTP
+// has no delivery webhooks: an accepted message is reported as sent.
+func (n *EmailNotifier) Send(ctx context.Context, msg Message) (*Result, error) {
+	if msg.To == "" {
+		return nil, Permanent(fmt.Errorf("email: recipient address is required"))
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	messageID := n.messageID()
+	data := n.buildMessage(msg, messageID)
+
+	var auth smtp.Auth
+	if n.config.Username != "" {
+		auth = smtp.PlainAuth("", n.config.Username, n.config.Password, n.config.Host)
+	}
+
+	addr := fmt.Sprintf("%s:%d", n.config.Host, n.config.Port)
+	if err := n.sendMail(addr, auth, n.config.From, []string{msg.To}, data); err != nil {
+		// 5xx replies (unknown mailbox, rejected sender) are permanent
+		var protoErr *textproto.Error
+		if errors.As(err, &protoErr) && protoErr.Code >= 500 {
+			return nil, Permanent(fmt.Errorf("email: %w", err))
+		}
+		return nil, fmt.Errorf("email: %w", err)
+	}
+
+	return &Result{ProviderMessageID: messageID, Status: models.DeliveryStatusSent}, nil
+}
+
+// buildMessage renders the MIME message
+func (n *EmailNotifier) buildMessage(msg Message, messageID string) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", formatAddress(n.config.FromName, n.config.From))
+	fmt.Fprintf(&b, "To: %s\r\n", formatAddress(msg.ToName, msg.To))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", msg.Subject))
+	fmt.Fprintf(&b, "Message-ID: %s\r\n", messageID)
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+
+	if msg.HTML == "" {
+		b.WriteString("Content-Type: text/plain; charset=\"UTF-8\"\r\n\r\n")
+		b.WriteString(msg.Body)
+		return []byte(b.String())
+	}
+
+	boundary := "alt-" + strings.Trim(messageID, "<>")
+	fmt.Fprintf(&b, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", boundary)
+	fmt.Fprintf(&b, "--%s\r\nContent-Type: text/plain; charset=\"UTF-8\"\r\n\r\n%s\r\n", boundary, msg.Body)
+	fmt.Fprintf(&b, "--%s\r\nContent-Type: text/html; charset=\"UTF-8\"\r\n\r\n%s\r\n", boundary, msg.HTML)
+	fmt.Fprintf(&b, "--%s--\r\n", boundary)
+	return []byte(b.String())
+}
+
+// messageID generates a unique Message-ID header value
+func (n *EmailNotifier) messageID() string {
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+
+	domain := "localhost"
+	if i := strings.LastIndex(n.config.From, "@"); i >= 0 {
+		domain = n.config.From[i+1:]
+	}
+	return fmt.Sprintf("<%s@%s>", hex.EncodeToString(b), domain)
+}
+
+// formatAddress formats a "Name <address>" header value
+func formatAddress(name, address string) string {
+	if name == "" {
+		return address
+	}
+	return fmt.Sprintf("%s <%s>", mime.QEncoding.Encode("UTF-8", name), address)
+}
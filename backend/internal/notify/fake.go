@@ -0,0 +1,63 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+// FakeNotifier records messages instead of sending them. It is used by
+// tests and by local runs without provider credentials.
+type FakeNotifier struct {
+	channel models.NotificationChannel
+
+	mu       sync.Mutex
+	sent     []Message
+	failures []error // Errors returned by the next sends, in order
+	count    int
+}
+
+// NewFakeNotifier creates a fake notifier for a channel
+func NewFakeNotifier(channel models.NotificationChannel) *FakeNotifier {
+	return &FakeNotifier{channel: channel}
+}
+
+// Channel returns the channel of the fake
+func (n *FakeNotifier) Channel() models.NotificationChannel {
+	return n.channel
+}
+
+// FailNext makes the next sends fail with the given errors, in order
+func (n *FakeNotifier) FailNext(errs ...error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.failures = append(n.failures, errs...)
+}
+
+// Send records the message and returns a sequential provider message ID
+func (n *FakeNotifier) Send(ctx context.Context, msg Message) (*Result, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if len(n.failures) > 0 {
+		err := n.failures[0]
+		n.failures = n.failures[1:]
+		return nil, err
+	}
+
+	n.count++
+	n.sent = append(n.sent, msg)
+	return &Result{
+		ProviderMessageID: fmt.Sprintf("fake-%s-%d", n.channel, n.count),
+		Status:            models.DeliveryStatusAccepted,
+	}, nil
+}
+
+// Sent returns the messages sent so far
+func (n *FakeNotifier) Sent() []Message {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]Message(nil), n.sent...)
+}
@@ -0,0 +1,111 @@
+// Package notify sends outbound messages (WhatsApp, SMS, email) through
+// provider APIs behind a common Notifier interface, and parses the delivery
+// status webhooks of those providers.
+package notify
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+// Message is an outbound message
+type Message struct {
+	Channel models.NotificationChannel
+	To      string // Phone number (WhatsApp, SMS) or email address
+	ToName  string
+	Subject string // Email only
+	Body    string // Plain text body
+	HTML    string // Email only (optional)
+
+	// Approved WhatsApp Business template, sent instead of Body when set
+	WhatsAppTemplate   string
+	WhatsAppLanguage   string
+	WhatsAppParameters []string
+}
+
+// Result describes a message accepted by a provider
+type Result struct {
+	ProviderMessageID string // Matches the delivery webhooks (empty for SMTP)
+	Status            string // models.DeliveryStatus* reported by the provider
+	Attempts          int    // Set by Dispatcher
+}
+
+// Notifier sends messages through one channel
+type Notifier interface {
+	Channel() models.NotificationChannel
+	Send(ctx context.Context, msg Message) (*Result, error)
+}
+
+// StatusUpdate is a delivery status reported by a provider webhook
+type StatusUpdate struct {
+	Channel           models.NotificationChannel
+	ProviderMessageID string
+	Status            string // models.DeliveryStatus*
+	Error             string
+	Timestamp         time.Time
+}
+
+// ErrNoNotifier is returned when no notifier is registered for a channel
+var ErrNoNotifier = errors.New("no notifier for channel")
+
+// PermanentError marks a provider error that retrying cannot fix (invalid
+// number, rejected recipient, bad credentials)
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string { return e.Err.Error() }
+func (e *PermanentError) Unwrap() error { return e.Err }
+
+// Permanent wraps err as a PermanentError
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &PermanentError{Err: err}
+}
+
+// IsPermanent reports whether err must not be retried
+func IsPermanent(err error) bool {
+	var permanent *PermanentError
+	return errors.As(err, &permanent)
+}
+
+var nonDigits = regexp.MustCompile(`[^\d]`)
+
+// PhoneE164 formats a Brazilian phone number as E.164 ("+5511987654321");
+// numbers already carrying the country code are kept
+func PhoneE164(phone string) string {
+	digits := nonDigits.ReplaceAllString(phone, "")
+	if digits == "" {
+		return ""
+	}
+	if len(digits) == 10 || len(digits) == 11 {
+		digits = "55" + digits
+	}
+	return "+" + digits
+}
+
+// deliveryRank orders the delivery statuses so webhooks arriving out of
+// order never move a message backwards
+var deliveryRank = map[string]int{
+	models.DeliveryStatusManualRequired: 0,
+	models.DeliveryStatusAccepted:       1,
+	models.DeliveryStatusSent:           2,
+	models.DeliveryStatusDelivered:      3,
+	models.DeliveryStatusRead:           4,
+	models.DeliveryStatusFailed:         5,
+}
+
+// Advances reports whether moving from the current delivery status to next
+// is progress (failures always apply, except over a delivered message)
+func Advances(current, next string) bool {
+	if next == models.DeliveryStatusFailed {
+		return current != models.DeliveryStatusDelivered && current != models.DeliveryStatusRead
+	}
+	return deliveryRank[next] > deliveryRank[current]
+}
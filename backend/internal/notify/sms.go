@@ -0,0 +1,179 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+// DefaultTwilioAPIURL is the Twilio REST API base URL
+const DefaultTwilioAPIURL = "https://api.twilio.com/2010-04-01"
+
+// SMSConfig configures the SMS provider (Twilio Programmable Messaging)
+type SMSConfig struct {
+	APIURL            string // Default DefaultTwilioAPIURL
+	AccountSID        string
+	AuthToken         string // Also signs the status callbacks (X-Twilio-Signature)
+	From              string // Sender number (E.164) or messaging service SID ("MG...")
+	StatusCallbackURL string // Public URL of the SMS delivery webhook
+}
+
+// SMSNotifier sends SMS messages through Twilio
+type SMSNotifier struct {
+	config SMSConfig
+	client *http.Client
+}
+
+// NewSMSNotifier creates an SMS notifier
+func NewSMSNotifier(config SMSConfig) *SMSNotifier {
+	if config.APIURL == "" {
+		config.APIURL = DefaultTwilioAPIURL
+	}
+	return &SMSNotifier{
+		config: config,
+		client: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// Channel returns models.NotificationChannelSMS
+func (n *SMSNotifier) Channel() models.NotificationChannel {
+	return models.NotificationChannelSMS
+}
+
+type twilioResponse struct {
+	SID     string `json:"sid"`
+	Status  string `json:"status"`
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Send sends the message body as an SMS
+func (n *SMSNotifier) Send(ctx context.Context, msg Message) (*Result, error) {
+	to := PhoneE164(msg.To)
+	if to == "" {
+		return nil, Permanent(fmt.Errorf("sms: recipient phone is required"))
+	}
+
+	form := url.Values{}
+	form.Set("To", to)
+	form.Set("Body", msg.Body)
+	if strings.HasPrefix(n.config.From, "MG") {
+		form.Set("MessagingServiceSid", n.config.From)
+	} else {
+		form.Set("From", n.config.From)
+	}
+	if n.config.StatusCallbackURL != "" {
+		form.Set("StatusCallback", n.config.StatusCallbackURL)
+	}
+
+	endpoint := fmt.Sprintf("%s/Accounts/%s/Messages.json", strings.TrimRight(n.config.APIURL, "/"), n.config.AccountSID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, Permanent(fmt.Errorf("sms: %w", err))
+	}
+	req.SetBasicAuth(n.config.AccountSID, n.config.AuthToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("sms: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var body twilioResponse
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	_ = json.Unmarshal(raw, &body)
+
+	if resp.StatusCode >= 300 {
+		reason := strings.TrimSpace(string(raw))
+		if body.Message != "" {
+			reason = fmt.Sprintf("%s (code %d)", body.Message, body.Code)
+		}
+		return nil, httpError("sms", resp.StatusCode, reason)
+	}
+	if body.SID == "" {
+		return nil, fmt.Errorf("sms: response without message sid")
+	}
+
+	status, ok := twilioStatuses[body.Status]
+	if !ok || status == models.DeliveryStatusFailed {
+		status = models.DeliveryStatusAccepted
+	}
+
+	return &Result{ProviderMessageID: body.SID, Status: status}, nil
+}
+
+// VerifySignature checks the X-Twilio-Signature of a status callback posted
+// to the configured StatusCallbackURL. Without an auth token every callback
+// is rejected.
+func (n *SMSNotifier) VerifySignature(params url.Values, signature string) bool {
+	if n.config.AuthToken == "" {
+		return false
+	}
+
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(n.config.StatusCallbackURL)
+	for _, k := range keys {
+		for _, v := range params[k] {
+			b.WriteString(k)
+			b.WriteString(v)
+		}
+	}
+
+	mac := hmac.New(sha1.New, []byte(n.config.AuthToken))
+	mac.Write([]byte(b.String()))
+	expected := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// ParseSMSStatusCallback converts a Twilio status callback into a status
+// update; intermediate statuses (queued, sending) yield ok == false
+func ParseSMSStatusCallback(params url.Values) (StatusUpdate, bool) {
+	status, ok := twilioStatuses[params.Get("MessageStatus")]
+	sid := params.Get("MessageSid")
+	if !ok || sid == "" {
+		return StatusUpdate{}, false
+	}
+
+	update := StatusUpdate{
+		Channel:           models.NotificationChannelSMS,
+		ProviderMessageID: sid,
+		Status:            status,
+		Timestamp:         time.Now(),
+	}
+	if code := params.Get("ErrorCode"); code != "" {
+		update.Error = fmt.Sprintf("twilio error %s", code)
+		if message := params.Get("ErrorMessage"); message != "" {
+			update.Error = fmt.Sprintf("%s (twilio error %s)", message, code)
+		}
+	}
+	return update, true
+}
+
+// twilioStatuses maps Twilio message statuses to delivery statuses
+var twilioStatuses = map[string]string{
+	"accepted":    models.DeliveryStatusAccepted,
+	"queued":      models.DeliveryStatusAccepted,
+	"sent":        models.DeliveryStatusSent,
+	"delivered":   models.DeliveryStatusDelivered,
+	"read":        models.DeliveryStatusRead,
+	"undelivered": models.DeliveryStatusFailed,
+	"failed":      models.DeliveryStatusFailed,
+}
@@ -0,0 +1,75 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+func TestSMSNotifier_Send(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/Accounts/AC1/Messages.json", r.URL.Path)
+		user, pass, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "AC1", user)
+		assert.Equal(t, "auth", pass)
+
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "+5511987654321", r.PostForm.Get("To"))
+		assert.Equal(t, "+15550001111", r.PostForm.Get("From"))
+		assert.Equal(t, "https://api.example.com/api/v1/webhooks/sms", r.PostForm.Get("StatusCallback"))
+
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"sid":"SM123","status":"queued"}`))
+	}))
+	defer server.Close()
+
+	n := NewSMSNotifier(SMSConfig{
+		APIURL:            server.URL,
+		AccountSID:        "AC1",
+		AuthToken:         "auth",
+		From:              "+15550001111",
+		StatusCallbackURL: "https://api.example.com/api/v1/webhooks/sms",
+	})
+
+	result, err := n.Send(context.Background(), Message{Channel: models.NotificationChannelSMS, To: "11987654321", Body: "oi"})
+	require.NoError(t, err)
+	assert.Equal(t, "SM123", result.ProviderMessageID)
+	assert.Equal(t, models.DeliveryStatusAccepted, result.Status)
+}
+
+func TestSMSNotifier_StatusCallback(t *testing.T) {
+	callbackURL := "https://api.example.com/api/v1/webhooks/sms"
+	n := NewSMSNotifier(SMSConfig{AuthToken: "auth", StatusCallbackURL: callbackURL})
+
+	params := url.Values{
+		"MessageSid":    {"SM123"},
+		"MessageStatus": {"undelivered"},
+		"ErrorCode":     {"30003"},
+	}
+
+	mac := hmac.New(sha1.New, []byte("auth"))
+	mac.Write([]byte(callbackURL + "ErrorCode30003MessageSidSM123MessageStatusundelivered"))
+	assert.True(t, n.VerifySignature(params, base64.StdEncoding.EncodeToString(mac.Sum(nil))))
+	assert.False(t, n.VerifySignature(params, "invalid"))
+	assert.False(t, NewSMSNotifier(SMSConfig{StatusCallbackURL: callbackURL}).VerifySignature(params, ""), "no auth token")
+
+	update, ok := ParseSMSStatusCallback(params)
+	require.True(t, ok)
+	assert.Equal(t, "SM123", update.ProviderMessageID)
+	assert.Equal(t, models.DeliveryStatusFailed, update.Status)
+	assert.Equal(t, "twilio error 30003", update.Error)
+
+	_, ok = ParseSMSStatusCallback(url.Values{"MessageSid": {"SM123"}, "MessageStatus": {"sending"}})
+	assert.False(t, ok)
+}
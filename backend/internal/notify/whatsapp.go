@@ -0,0 +1,272 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+// DefaultWhatsAppAPIURL is the WhatsApp Business Cloud API (Graph API) base URL
+const DefaultWhatsAppAPIURL = "https://graph.facebook.com/v19.0"
+
+// WhatsAppConfig configures the WhatsApp Business Cloud API
+type WhatsAppConfig struct {
+	APIURL        string // Default DefaultWhatsAppAPIURL
+	PhoneNumberID string // Sender phone number ID
+	AccessToken   string // System user access token
+	AppSecret     string // Verifies webhook signatures (X-Hub-Signature-256)
+	VerifyToken   string // Webhook subscription verification token
+}
+
+// WhatsAppNotifier sends messages through the WhatsApp Business Cloud API
+type WhatsAppNotifier struct {
+	config WhatsAppConfig
+	client *http.Client
+}
+
+// NewWhatsAppNotifier creates a WhatsApp notifier
+func NewWhatsAppNotifier(config WhatsAppConfig) *WhatsAppNotifier {
+	if config.APIURL == "" {
+		config.APIURL = DefaultWhatsAppAPIURL
+	}
+	return &WhatsAppNotifier{
+		config: config,
+		client: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// Channel returns models.NotificationChannelWhatsApp
+func (n *WhatsAppNotifier) Channel() models.NotificationChannel {
+	return models.NotificationChannelWhatsApp
+}
+
+type whatsAppMessage struct {
+	MessagingProduct string            `json:"messaging_product"`
+	To               string            `json:"to"`
+	Type             string            `json:"type"`
+	Text             *whatsAppText     `json:"text,omitempty"`
+	Template         *whatsAppTemplate `json:"template,omitempty"`
+}
+
+type whatsAppText struct {
+	Body       string `json:"body"`
+	PreviewURL bool   `json:"preview_url"`
+}
+
+type whatsAppTemplate struct {
+	Name       string              `json:"name"`
+	Language   whatsAppLanguage    `json:"language"`
+	Components []whatsAppComponent `json:"components,omitempty"`
+}
+
+type whatsAppLanguage struct {
+	Code string `json:"code"`
+}
+
+type whatsAppComponent struct {
+	Type       string              `json:"type"`
+	Parameters []whatsAppParameter `json:"parameters"`
+}
+
+type whatsAppParameter struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type whatsAppResponse struct {
+	Messages []struct {
+		ID            string `json:"id"`
+		MessageStatus string `json:"message_status"`
+	} `json:"messages"`
+	Error *struct {
+		Message string `json:"message"`
+		Code    int    `json:"code"`
+	} `json:"error"`
+}
+
+// Send sends a text message, or the approved template when msg.WhatsAppTemplate is set
+func (n *WhatsAppNotifier) Send(ctx context.Context, msg Message) (*Result, error) {
+	to := strings.TrimPrefix(PhoneE164(msg.To), "+")
+	if to == "" {
+		return nil, Permanent(fmt.Errorf("whatsapp: recipient phone is required"))
+	}
+
+	payload := whatsAppMessage{MessagingProduct: "whatsapp", To: to}
+	if msg.WhatsAppTemplate != "" {
+		language := msg.WhatsAppLanguage
+		if language == "" {
+			language = "pt_BR"
+		}
+		payload.Type = "template"
+		payload.Template = &whatsAppTemplate{Name: msg.WhatsAppTemplate, Language: whatsAppLanguage{Code: language}}
+		if len(msg.WhatsAppParameters) > 0 {
+			body := whatsAppComponent{Type: "body"}
+			for _, p := range msg.WhatsAppParameters {
+				body.Parameters = append(body.Parameters, whatsAppParameter{Type: "text", Text: p})
+			}
+			payload.Template.Components = []whatsAppComponent{body}
+		}
+	} else {
+		payload.Type = "text"
+		payload.Text = &whatsAppText{Body: msg.Body, PreviewURL: true}
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, Permanent(fmt.Errorf("whatsapp: failed to encode message: %w", err))
+	}
+
+	url := fmt.Sprintf("%s/%s/messages", strings.TrimRight(n.config.APIURL, "/"), n.config.PhoneNumberID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return nil, Permanent(fmt.Errorf("whatsapp: %w", err))
+	}
+	req.Header.Set("Authorization", "Bearer "+n.config.AccessToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("whatsapp: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var body whatsAppResponse
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	_ = json.Unmarshal(raw, &body)
+
+	if resp.StatusCode >= 300 {
+		reason := strings.TrimSpace(string(raw))
+		if body.Error != nil {
+			reason = fmt.Sprintf("%s (code %d)", body.Error.Message, body.Error.Code)
+		}
+		return nil, httpError("whatsapp", resp.StatusCode, reason)
+	}
+	if len(body.Messages) == 0 || body.Messages[0].ID == "" {
+		return nil, fmt.Errorf("whatsapp: response without message id")
+	}
+
+	return &Result{
+		ProviderMessageID: body.Messages[0].ID,
+		Status:            models.DeliveryStatusAccepted,
+	}, nil
+}
+
+// VerifySubscription answers the webhook subscription handshake: it returns
+// the challenge to echo when mode and token match the configuration
+func (n *WhatsAppNotifier) VerifySubscription(mode, token, challenge string) (string, bool) {
+	if mode != "subscribe" || n.config.VerifyToken == "" || !hmac.Equal([]byte(token), []byte(n.config.VerifyToken)) {
+		return "", false
+	}
+	return challenge, true
+}
+
+// VerifySignature checks the X-Hub-Signature-256 header of a webhook body.
+// Without an app secret configured every body is rejected: the webhook route
+// is public, so unsigned statuses could be forged by anyone.
+func (n *WhatsAppNotifier) VerifySignature(body []byte, signature string) bool {
+	if n.config.AppSecret == "" {
+		return false
+	}
+	expected, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(n.config.AppSecret))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+type whatsAppWebhook struct {
+	Entry []struct {
+		Changes []struct {
+			Value struct {
+				Statuses []struct {
+					ID        string `json:"id"`
+					Status    string `json:"status"`
+					Timestamp string `json:"timestamp"`
+					Errors    []struct {
+						Code    int    `json:"code"`
+						Title   string `json:"title"`
+						Message string `json:"message"`
+					} `json:"errors"`
+				} `json:"statuses"`
+			} `json:"value"`
+		} `json:"changes"`
+	} `json:"entry"`
+}
+
+// ParseWhatsAppWebhook extracts the message status updates of a WhatsApp
+// webhook notification (other notifications yield no updates)
+func ParseWhatsAppWebhook(body []byte) ([]StatusUpdate, error) {
+	var hook whatsAppWebhook
+	if err := json.Unmarshal(body, &hook); err != nil {
+		return nil, fmt.Errorf("invalid whatsapp webhook: %w", err)
+	}
+
+	var updates []StatusUpdate
+	for _, entry := range hook.Entry {
+		for _, change := range entry.Changes {
+			for _, st := range change.Value.Statuses {
+				status, ok := whatsAppStatuses[st.Status]
+				if !ok || st.ID == "" {
+					continue
+				}
+
+				update := StatusUpdate{
+					Channel:           models.NotificationChannelWhatsApp,
+					ProviderMessageID: st.ID,
+					Status:            status,
+					Timestamp:         time.Now(),
+				}
+				if ts, err := strconv.ParseInt(st.Timestamp, 10, 64); err == nil {
+					update.Timestamp = time.Unix(ts, 0)
+				}
+				if len(st.Errors) > 0 {
+					e := st.Errors[0]
+					update.Error = fmt.Sprintf("%s (code %d)", firstNonEmpty(e.Message, e.Title), e.Code)
+				}
+				updates = append(updates, update)
+			}
+		}
+	}
+
+	return updates, nil
+}
+
+// whatsAppStatuses maps WhatsApp message statuses to delivery statuses
+var whatsAppStatuses = map[string]string{
+	"sent":      models.DeliveryStatusSent,
+	"delivered": models.DeliveryStatusDelivered,
+	"read":      models.DeliveryStatusRead,
+	"failed":    models.DeliveryStatusFailed,
+}
+
+// httpError classifies a provider HTTP error: 4xx responses other than 408
+// and 429 cannot succeed on retry
+func httpError(provider string, statusCode int, reason string) error {
+	err := fmt.Errorf("%s: HTTP %d: %s", provider, statusCode, reason)
+	if statusCode >= 400 && statusCode < 500 && statusCode != http.StatusRequestTimeout && statusCode != http.StatusTooManyRequests {
+		return Permanent(err)
+	}
+	return err
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
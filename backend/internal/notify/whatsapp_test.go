@@ -0,0 +1,100 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+func TestWhatsAppNotifier_SendTemplate(t *testing.T) {
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/123/messages", r.URL.Path)
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		_, _ = w.Write([]byte(`{"messaging_product":"whatsapp","messages":[{"id":"wamid.ABC"}]}`))
+	}))
+	defer server.Close()
+
+	n := NewWhatsAppNotifier(WhatsAppConfig{APIURL: server.URL, PhoneNumberID: "123", AccessToken: "token"})
+	result, err := n.Send(context.Background(), Message{
+		Channel:            models.NotificationChannelWhatsApp,
+		To:                 "(11) 98765-4321",
+		WhatsAppTemplate:   "confirmacao_imovel",
+		WhatsAppParameters: []string{"Maria", "AP001", "https://x/c/abc"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "wamid.ABC", result.ProviderMessageID)
+	assert.Equal(t, models.DeliveryStatusAccepted, result.Status)
+
+	assert.Equal(t, "5511987654321", received["to"])
+	assert.Equal(t, "template", received["type"])
+	template := received["template"].(map[string]interface{})
+	assert.Equal(t, "confirmacao_imovel", template["name"])
+	assert.Equal(t, "pt_BR", template["language"].(map[string]interface{})["code"])
+	params := template["components"].([]interface{})[0].(map[string]interface{})["parameters"].([]interface{})
+	assert.Len(t, params, 3)
+}
+
+func TestWhatsAppNotifier_ClientErrorIsPermanent(t *testing.T) {
+	status := http.StatusBadRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"error":{"message":"Recipient phone number not in allowed list","code":131030}}`))
+	}))
+	defer server.Close()
+
+	n := NewWhatsAppNotifier(WhatsAppConfig{APIURL: server.URL, PhoneNumberID: "123", AccessToken: "token"})
+	msg := Message{Channel: models.NotificationChannelWhatsApp, To: "11987654321", Body: "oi"}
+
+	_, err := n.Send(context.Background(), msg)
+	require.Error(t, err)
+	assert.True(t, IsPermanent(err))
+	assert.Contains(t, err.Error(), "131030")
+
+	// Rate limiting is retried
+	status = http.StatusTooManyRequests
+	_, err = n.Send(context.Background(), msg)
+	require.Error(t, err)
+	assert.False(t, IsPermanent(err))
+}
+
+func TestWhatsAppNotifier_Webhook(t *testing.T) {
+	n := NewWhatsAppNotifier(WhatsAppConfig{AppSecret: "secret", VerifyToken: "verify"})
+
+	challenge, ok := n.VerifySubscription("subscribe", "verify", "42")
+	assert.True(t, ok)
+	assert.Equal(t, "42", challenge)
+	_, ok = n.VerifySubscription("subscribe", "wrong", "42")
+	assert.False(t, ok)
+
+	body := []byte(`{"entry":[{"changes":[{"value":{"statuses":[
+		{"id":"wamid.1","status":"delivered","timestamp":"1760000000"},
+		{"id":"wamid.2","status":"failed","timestamp":"1760000000","errors":[{"code":131026,"title":"Message undeliverable"}]}
+	]}}]}]}`)
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(body)
+	assert.True(t, n.VerifySignature(body, "sha256="+hex.EncodeToString(mac.Sum(nil))))
+	assert.False(t, n.VerifySignature(body, "sha256=00"))
+	assert.False(t, NewWhatsAppNotifier(WhatsAppConfig{AccessToken: "token"}).VerifySignature(body, "sha256="+hex.EncodeToString(mac.Sum(nil))), "no app secret")
+
+	updates, err := ParseWhatsAppWebhook(body)
+	require.NoError(t, err)
+	require.Len(t, updates, 2)
+	assert.Equal(t, "wamid.1", updates[0].ProviderMessageID)
+	assert.Equal(t, models.DeliveryStatusDelivered, updates[0].Status)
+	assert.Equal(t, int64(1760000000), updates[0].Timestamp.Unix())
+	assert.Equal(t, models.DeliveryStatusFailed, updates[1].Status)
+	assert.Equal(t, "Message undeliverable (code 131026)", updates[1].Error)
+}
@@ -0,0 +1,24 @@
+// Package payments receives the payment confirmations of payment providers
+// (boleto and PIX gateways) behind a common Provider interface.
+package payments
+
+import (
+	"net/http"
+	"time"
+)
+
+// Event is a payment confirmed by a provider webhook
+type Event struct {
+	Provider  string
+	Reference string // External reference of the charge (RentInvoice.Reference)
+	PaymentID string // Provider payment ID; repeated webhooks carry the same one
+	Amount    float64
+	PaidAt    time.Time
+}
+
+// Provider verifies and parses the webhooks of one payment provider
+type Provider interface {
+	Name() string
+	VerifySignature(body []byte, header http.Header) bool
+	ParseWebhook(body []byte) ([]Event, error)
+}
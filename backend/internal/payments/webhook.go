@@ -0,0 +1,99 @@
+package payments
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// WebhookConfig configures a WebhookProvider
+type WebhookConfig struct {
+	Name   string // Path segment of the webhook (/webhooks/payments/{name})
+	Secret string // Signs the webhook bodies (X-Signature: sha256=<hex HMAC>)
+}
+
+// WebhookProvider is a provider posting signed JSON notifications:
+//
+//	{"events": [{"reference": "...", "payment_id": "...", "amount": 3950.00,
+//	  "paid_at": "2025-04-10T14:00:00-03:00", "status": "paid"}]}
+//
+// Gateways without this format are adapted by a relay or their own Provider.
+type WebhookProvider struct {
+	config WebhookConfig
+}
+
+// NewWebhookProvider creates a new signed webhook provider
+func NewWebhookProvider(config WebhookConfig) *WebhookProvider {
+	return &WebhookProvider{config: config}
+}
+
+// Name returns the provider name
+func (p *WebhookProvider) Name() string {
+	return p.config.Name
+}
+
+// VerifySignature checks the X-Signature header of a webhook body. Unlike
+// delivery webhooks, payments are never accepted unsigned.
+func (p *WebhookProvider) VerifySignature(body []byte, header http.Header) bool {
+	if p.config.Secret == "" {
+		return false
+	}
+	expected, err := hex.DecodeString(strings.TrimPrefix(header.Get("X-Signature"), "sha256="))
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(p.config.Secret))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+type webhookBody struct {
+	Events []struct {
+		Reference string    `json:"reference"`
+		PaymentID string    `json:"payment_id"`
+		Amount    float64   `json:"amount"`
+		PaidAt    time.Time `json:"paid_at"`
+		Status    string    `json:"status"`
+	} `json:"events"`
+}
+
+// paidStatuses are the event statuses of confirmed payments
+var paidStatuses = map[string]bool{
+	"paid":      true,
+	"confirmed": true,
+	"received":  true,
+}
+
+// ParseWebhook extracts the confirmed payments of a notification (other
+// events, such as created or expired charges, yield none)
+func (p *WebhookProvider) ParseWebhook(body []byte) ([]Event, error) {
+	var hook webhookBody
+	if err := json.Unmarshal(body, &hook); err != nil {
+		return nil, fmt.Errorf("invalid payment webhook: %w", err)
+	}
+
+	var events []Event
+	for _, e := range hook.Events {
+		if !paidStatuses[strings.ToLower(e.Status)] || e.Reference == "" || e.PaymentID == "" || e.Amount <= 0 {
+			continue
+		}
+
+		event := Event{
+			Provider:  p.config.Name,
+			Reference: e.Reference,
+			PaymentID: e.PaymentID,
+			Amount:    e.Amount,
+			PaidAt:    e.PaidAt,
+		}
+		if event.PaidAt.IsZero() {
+			event.PaidAt = time.Now()
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
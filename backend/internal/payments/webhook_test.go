@@ -0,0 +1,48 @@
+package payments
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookProvider_VerifySignature(t *testing.T) {
+	body := []byte(`{"events":[]}`)
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(body)
+	header := http.Header{}
+	header.Set("X-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+
+	p := NewWebhookProvider(WebhookConfig{Name: "gateway", Secret: "secret"})
+	assert.True(t, p.VerifySignature(body, header))
+	assert.False(t, p.VerifySignature([]byte(`{"events":[{}]}`), header))
+	assert.False(t, p.VerifySignature(body, http.Header{}))
+
+	// Without a secret nothing is accepted
+	assert.False(t, NewWebhookProvider(WebhookConfig{Name: "gateway"}).VerifySignature(body, header))
+}
+
+func TestWebhookProvider_ParseWebhook(t *testing.T) {
+	p := NewWebhookProvider(WebhookConfig{Name: "gateway", Secret: "secret"})
+	events, err := p.ParseWebhook([]byte(`{"events":[
+		{"reference":"c1_2025-03","payment_id":"pay_1","amount":3950,"paid_at":"2025-04-10T14:00:00-03:00","status":"PAID"},
+		{"reference":"c1_2025-04","payment_id":"pay_2","amount":3950,"status":"expired"},
+		{"reference":"","payment_id":"pay_3","amount":10,"status":"paid"}
+	]}`))
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "gateway", events[0].Provider)
+	assert.Equal(t, "c1_2025-03", events[0].Reference)
+	assert.Equal(t, "pay_1", events[0].PaymentID)
+	assert.Equal(t, 3950.0, events[0].Amount)
+	assert.True(t, events[0].PaidAt.Equal(time.Date(2025, 4, 10, 17, 0, 0, 0, time.UTC)))
+
+	_, err = p.ParseWebhook([]byte(`not json`))
+	assert.Error(t, err)
+}
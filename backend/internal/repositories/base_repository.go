@@ -44,8 +44,11 @@ type PaginationOptions struct {
 	Limit      int
 	Offset     int         // Offset for pagination (alternative to cursor-based)
 	StartAfter interface{} // Cursor for pagination
-	OrderBy    string
-	Direction  firestore.Direction
+	// StartAfterID is the document ID of the cursor document; it breaks ties
+	// between documents sharing the StartAfter value (see ApplyCursor)
+	StartAfterID string
+	OrderBy      string
+	Direction    firestore.Direction
 }
 
 // DefaultPaginationOptions returns default pagination options
@@ -200,7 +203,10 @@ func (r *BaseRepository) ApplyPagination(query firestore.Query, opts PaginationO
 		query = query.OrderBy(opts.OrderBy, opts.Direction)
 	}
 
-	if opts.StartAfter != nil {
+	if opts.StartAfterID != "" && opts.OrderBy != "" {
+		// Keyset cursor: order by document ID as well so pages never overlap
+		query = query.OrderBy(firestore.DocumentID, opts.Direction).StartAfter(opts.StartAfter, opts.StartAfterID)
+	} else if opts.StartAfter != nil {
 		query = query.StartAfter(opts.StartAfter)
 	}
 
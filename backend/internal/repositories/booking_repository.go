@@ -0,0 +1,285 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+// BookingFilters contains filters for listing bookings
+type BookingFilters struct {
+	PropertyID    string
+	Statuses      []models.BookingStatus // Any of them
+	Source        models.BookingSource
+	FeedID        string
+	CheckOutAfter *time.Time // check_out > CheckOutAfter
+	ExpiresBefore *time.Time // expires_at < ExpiresBefore
+}
+
+// BookingRepository handles Firestore operations for the bookings and
+// blocks of stay calendars
+type BookingRepository struct {
+	*BaseRepository
+}
+
+// NewBookingRepository creates a new booking repository
+func NewBookingRepository(client *firestore.Client) *BookingRepository {
+	return &BookingRepository{
+		BaseRepository: NewBaseRepository(client),
+	}
+}
+
+// getBookingsCollection returns the collection path for bookings within a tenant
+func (r *BookingRepository) getBookingsCollection(tenantID string) string {
+	return fmt.Sprintf("tenants/%s/bookings", tenantID)
+}
+
+// prepare validates a new booking and sets its ID and timestamps
+func (r *BookingRepository) prepare(booking *models.Booking) error {
+	if booking.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if booking.PropertyID == "" {
+		return fmt.Errorf("%w: property_id is required", ErrInvalidInput)
+	}
+
+	if booking.ID == "" {
+		booking.ID = r.GenerateID(r.getBookingsCollection(booking.TenantID))
+	}
+
+	now := time.Now()
+	booking.CreatedAt = now
+	booking.UpdatedAt = now
+	return nil
+}
+
+// Create creates a new booking without checking the calendar (imported
+// blocks)
+func (r *BookingRepository) Create(ctx context.Context, booking *models.Booking) error {
+	if err := r.prepare(booking); err != nil {
+		return err
+	}
+
+	if err := r.CreateDocument(ctx, r.getBookingsCollection(booking.TenantID), booking.ID, booking); err != nil {
+		return fmt.Errorf("failed to create booking: %w", err)
+	}
+
+	return nil
+}
+
+// CreateIfAvailable creates a booking unless another booking holding its
+// dates at a time overlaps the stay. The check and the write run in one
+// transaction, so concurrent requests for the same nights cannot both
+// succeed. It returns false when the dates are taken.
+func (r *BookingRepository) CreateIfAvailable(ctx context.Context, booking *models.Booking, at time.Time) (bool, error) {
+	if err := r.prepare(booking); err != nil {
+		return false, err
+	}
+
+	collection := r.Client().Collection(r.getBookingsCollection(booking.TenantID))
+	statuses := make([]string, len(models.BookingHoldingStatuses))
+	for i, status := range models.BookingHoldingStatuses {
+		statuses[i] = string(status)
+	}
+	query := collection.
+		Where("property_id", "==", booking.PropertyID).
+		Where("status", "in", statuses).
+		Where("check_out", ">", booking.CheckIn)
+
+	created := false
+	err := r.Client().RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		created = false
+
+		docs, err := tx.Documents(query).GetAll()
+		if err != nil {
+			return err
+		}
+		for _, doc := range docs {
+			var other models.Booking
+			if err := doc.DataTo(&other); err != nil {
+				return fmt.Errorf("failed to decode booking: %w", err)
+			}
+			if other.HoldsDates(at) && other.Overlaps(booking.CheckIn, booking.CheckOut) {
+				return nil
+			}
+		}
+
+		created = true
+		return tx.Create(collection.Doc(booking.ID), booking)
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to create booking: %w", err)
+	}
+
+	return created, nil
+}
+
+// Get retrieves a booking by ID
+func (r *BookingRepository) Get(ctx context.Context, tenantID, id string) (*models.Booking, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	var booking models.Booking
+	if err := r.GetDocument(ctx, r.getBookingsCollection(tenantID), id, &booking); err != nil {
+		return nil, err
+	}
+
+	booking.ID = id
+	return &booking, nil
+}
+
+// Update updates specific fields of a booking
+func (r *BookingRepository) Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	updates["updated_at"] = time.Now()
+
+	firestoreUpdates := make([]firestore.Update, 0, len(updates))
+	for key, value := range updates {
+		firestoreUpdates = append(firestoreUpdates, firestore.Update{Path: key, Value: value})
+	}
+
+	if err := r.UpdateDocument(ctx, r.getBookingsCollection(tenantID), id, firestoreUpdates); err != nil {
+		return fmt.Errorf("failed to update booking: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateIf updates a booking only when check accepts its current state. The
+// read and the write run in one transaction, so a request cannot be confirmed
+// once it expired or was answered. It returns false when check refused the
+// booking.
+func (r *BookingRepository) UpdateIf(ctx context.Context, tenantID, id string, check func(booking *models.Booking) bool, updates map[string]interface{}) (bool, error) {
+	if tenantID == "" {
+		return false, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if id == "" {
+		return false, fmt.Errorf("%w: document ID is required", ErrInvalidInput)
+	}
+
+	updates["updated_at"] = time.Now()
+	firestoreUpdates := make([]firestore.Update, 0, len(updates))
+	for key, value := range updates {
+		firestoreUpdates = append(firestoreUpdates, firestore.Update{Path: key, Value: value})
+	}
+
+	ref := r.Client().Collection(r.getBookingsCollection(tenantID)).Doc(id)
+	updated := false
+	err := r.Client().RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		updated = false
+
+		doc, err := tx.Get(ref)
+		if err != nil {
+			if status.Code(err) == codes.NotFound {
+				return ErrNotFound
+			}
+			return err
+		}
+
+		var booking models.Booking
+		if err := doc.DataTo(&booking); err != nil {
+			return fmt.Errorf("failed to decode booking: %w", err)
+		}
+		booking.ID = id
+		if !check(&booking) {
+			return nil
+		}
+
+		updated = true
+		return tx.Update(ref, firestoreUpdates)
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to update booking: %w", err)
+	}
+
+	return updated, nil
+}
+
+// List retrieves bookings with filters, latest check-in first (earliest
+// check-out first with CheckOutAfter, earliest expiry first with
+// ExpiresBefore) unless opts sets another order
+func (r *BookingRepository) List(ctx context.Context, tenantID string, filters *BookingFilters, opts PaginationOptions) ([]*models.Booking, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	if opts.Limit == 0 {
+		opts.Limit = DefaultPaginationOptions().Limit
+	}
+	if opts.OrderBy == "" {
+		opts.OrderBy, opts.Direction = bookingOrder(filters)
+	}
+
+	query := r.Client().Collection(r.getBookingsCollection(tenantID)).Query
+	if filters != nil {
+		if filters.PropertyID != "" {
+			query = query.Where("property_id", "==", filters.PropertyID)
+		}
+		if len(filters.Statuses) > 0 {
+			statuses := make([]string, len(filters.Statuses))
+			for i, status := range filters.Statuses {
+				statuses[i] = string(status)
+			}
+			query = query.Where("status", "in", statuses)
+		}
+		if filters.Source != "" {
+			query = query.Where("source", "==", string(filters.Source))
+		}
+		if filters.FeedID != "" {
+			query = query.Where("feed_id", "==", filters.FeedID)
+		}
+		if filters.CheckOutAfter != nil {
+			query = query.Where("check_out", ">", *filters.CheckOutAfter)
+		}
+		if filters.ExpiresBefore != nil {
+			query = query.Where("expires_at", "<", *filters.ExpiresBefore)
+		}
+	}
+
+	iter := r.ApplyPagination(query, opts).Documents(ctx)
+	defer iter.Stop()
+
+	bookings := make([]*models.Booking, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate bookings: %w", err)
+		}
+
+		var booking models.Booking
+		if err := doc.DataTo(&booking); err != nil {
+			return nil, fmt.Errorf("failed to decode booking: %w", err)
+		}
+
+		booking.ID = doc.Ref.ID
+		bookings = append(bookings, &booking)
+	}
+
+	return bookings, nil
+}
+
+// bookingOrder returns the default order of a booking listing: the range
+// filter's field, which Firestore requires to be ordered first
+func bookingOrder(filters *BookingFilters) (string, firestore.Direction) {
+	switch {
+	case filters != nil && filters.CheckOutAfter != nil:
+		return "check_out", firestore.Asc
+	case filters != nil && filters.ExpiresBefore != nil:
+		return "expires_at", firestore.Asc
+	}
+	return "check_in", firestore.Desc
+}
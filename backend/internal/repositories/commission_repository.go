@@ -0,0 +1,173 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+// CommissionEntryFilters contains filters for listing commission entries
+type CommissionEntryFilters struct {
+	DealID      string
+	BrokerID    string
+	PropertyID  string
+	Period      string // YYYY-MM
+	Beneficiary models.CommissionBeneficiary
+	Statuses    []models.CommissionEntryStatus // Any of them
+}
+
+// CommissionRepository handles Firestore operations for the commission ledger
+type CommissionRepository struct {
+	*BaseRepository
+}
+
+// NewCommissionRepository creates a new commission repository
+func NewCommissionRepository(client *firestore.Client) *CommissionRepository {
+	return &CommissionRepository{
+		BaseRepository: NewBaseRepository(client),
+	}
+}
+
+// getEntriesCollection returns the collection path for commission entries within a tenant
+func (r *CommissionRepository) getEntriesCollection(tenantID string) string {
+	return fmt.Sprintf("tenants/%s/commission_entries", tenantID)
+}
+
+// CreateEntries creates the entries of a deal in a single batch (all or none)
+func (r *CommissionRepository) CreateEntries(ctx context.Context, entries []*models.CommissionEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	batch := r.Client().Batch()
+	for _, entry := range entries {
+		if entry.TenantID == "" {
+			return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+		}
+		if entry.DealID == "" {
+			return fmt.Errorf("%w: deal_id is required", ErrInvalidInput)
+		}
+
+		collectionPath := r.getEntriesCollection(entry.TenantID)
+		if entry.ID == "" {
+			entry.ID = r.GenerateID(collectionPath)
+		}
+		entry.CreatedAt = now
+		entry.UpdatedAt = now
+
+		batch.Create(r.Client().Collection(collectionPath).Doc(entry.ID), entry)
+	}
+
+	if _, err := batch.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to create commission entries: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves a commission entry by ID
+func (r *CommissionRepository) Get(ctx context.Context, tenantID, id string) (*models.CommissionEntry, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	var entry models.CommissionEntry
+	if err := r.GetDocument(ctx, r.getEntriesCollection(tenantID), id, &entry); err != nil {
+		return nil, err
+	}
+
+	entry.ID = id
+	return &entry, nil
+}
+
+// Update updates specific fields of a commission entry
+func (r *CommissionRepository) Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	updates["updated_at"] = time.Now()
+
+	firestoreUpdates := make([]firestore.Update, 0, len(updates))
+	for key, value := range updates {
+		firestoreUpdates = append(firestoreUpdates, firestore.Update{Path: key, Value: value})
+	}
+
+	if err := r.UpdateDocument(ctx, r.getEntriesCollection(tenantID), id, firestoreUpdates); err != nil {
+		return fmt.Errorf("failed to update commission entry: %w", err)
+	}
+
+	return nil
+}
+
+// List retrieves commission entries with filters, most recently closed first
+// unless opts sets another order
+func (r *CommissionRepository) List(ctx context.Context, tenantID string, filters *CommissionEntryFilters, opts PaginationOptions) ([]*models.CommissionEntry, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	if opts.Limit == 0 {
+		opts.Limit = DefaultPaginationOptions().Limit
+	}
+	if opts.OrderBy == "" {
+		opts.OrderBy = "closed_at"
+		opts.Direction = firestore.Desc
+	}
+
+	query := r.Client().Collection(r.getEntriesCollection(tenantID)).Query
+	if filters != nil {
+		if filters.DealID != "" {
+			query = query.Where("deal_id", "==", filters.DealID)
+		}
+		if filters.BrokerID != "" {
+			query = query.Where("broker_id", "==", filters.BrokerID)
+		}
+		if filters.PropertyID != "" {
+			query = query.Where("property_id", "==", filters.PropertyID)
+		}
+		if filters.Period != "" {
+			query = query.Where("period", "==", filters.Period)
+		}
+		if filters.Beneficiary != "" {
+			query = query.Where("beneficiary", "==", string(filters.Beneficiary))
+		}
+		if len(filters.Statuses) > 0 {
+			statuses := make([]string, len(filters.Statuses))
+			for i, status := range filters.Statuses {
+				statuses[i] = string(status)
+			}
+			query = query.Where("status", "in", statuses)
+		}
+	}
+
+	iter := r.ApplyPagination(query, opts).Documents(ctx)
+	defer iter.Stop()
+
+	entries := make([]*models.CommissionEntry, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate commission entries: %w", err)
+		}
+
+		var entry models.CommissionEntry
+		if err := doc.DataTo(&entry); err != nil {
+			return nil, fmt.Errorf("failed to decode commission entry: %w", err)
+		}
+
+		entry.ID = doc.Ref.ID
+		entries = append(entries, &entry)
+	}
+
+	return entries, nil
+}
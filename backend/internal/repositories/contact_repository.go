@@ -0,0 +1,180 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+// ContactRepository handles Firestore operations for contacts
+type ContactRepository struct {
+	*BaseRepository
+}
+
+// NewContactRepository creates a new contact repository
+func NewContactRepository(client *firestore.Client) *ContactRepository {
+	return &ContactRepository{
+		BaseRepository: NewBaseRepository(client),
+	}
+}
+
+// getContactsCollection returns the collection path for contacts within a tenant
+func (r *ContactRepository) getContactsCollection(tenantID string) string {
+	return fmt.Sprintf("tenants/%s/contacts", tenantID)
+}
+
+// Create creates a new contact
+func (r *ContactRepository) Create(ctx context.Context, contact *models.Contact) error {
+	if contact.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	collectionPath := r.getContactsCollection(contact.TenantID)
+	if contact.ID == "" {
+		contact.ID = r.GenerateID(collectionPath)
+	}
+
+	now := time.Now()
+	contact.CreatedAt = now
+	contact.UpdatedAt = now
+
+	if err := r.CreateDocument(ctx, collectionPath, contact.ID, contact); err != nil {
+		return fmt.Errorf("failed to create contact: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves a contact by ID
+func (r *ContactRepository) Get(ctx context.Context, tenantID, id string) (*models.Contact, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	var contact models.Contact
+	if err := r.GetDocument(ctx, r.getContactsCollection(tenantID), id, &contact); err != nil {
+		return nil, err
+	}
+
+	contact.ID = id
+	return &contact, nil
+}
+
+// Update saves the identities, inquiry history and merges of a contact
+func (r *ContactRepository) Update(ctx context.Context, contact *models.Contact) error {
+	if contact.TenantID == "" || contact.ID == "" {
+		return fmt.Errorf("%w: tenant_id and id are required", ErrInvalidInput)
+	}
+
+	contact.UpdatedAt = time.Now()
+
+	updates := []firestore.Update{
+		{Path: "name", Value: contact.Name},
+		{Path: "emails", Value: contact.Emails},
+		{Path: "phones", Value: contact.Phones},
+		{Path: "lead_ids", Value: contact.LeadIDs},
+		{Path: "property_ids", Value: contact.PropertyIDs},
+		{Path: "inquiry_count", Value: contact.InquiryCount},
+		{Path: "first_inquiry_at", Value: contact.FirstInquiryAt},
+		{Path: "last_inquiry_at", Value: contact.LastInquiryAt},
+		{Path: "merged_into_id", Value: contact.MergedIntoID},
+		{Path: "merges", Value: contact.Merges},
+		{Path: "updated_at", Value: contact.UpdatedAt},
+	}
+
+	if err := r.UpdateDocument(ctx, r.getContactsCollection(contact.TenantID), contact.ID, updates); err != nil {
+		return fmt.Errorf("failed to update contact: %w", err)
+	}
+
+	return nil
+}
+
+// List retrieves the contacts of a tenant, merged contacts included
+func (r *ContactRepository) List(ctx context.Context, tenantID string, opts PaginationOptions) ([]*models.Contact, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	if opts.Limit == 0 {
+		opts = DefaultPaginationOptions()
+	}
+
+	query := r.ApplyPagination(r.Client().Collection(r.getContactsCollection(tenantID)).Query, opts)
+	return r.list(ctx, query)
+}
+
+// FindByEmail retrieves the contact with a normalized email, skipping
+// contacts merged into another
+func (r *ContactRepository) FindByEmail(ctx context.Context, tenantID, email string) (*models.Contact, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if email == "" {
+		return nil, fmt.Errorf("%w: email is required", ErrInvalidInput)
+	}
+
+	return r.findActive(ctx, tenantID, "emails", email)
+}
+
+// FindByPhone retrieves the contact with an E.164 phone, skipping contacts
+// merged into another
+func (r *ContactRepository) FindByPhone(ctx context.Context, tenantID, phone string) (*models.Contact, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if phone == "" {
+		return nil, fmt.Errorf("%w: phone is required", ErrInvalidInput)
+	}
+
+	return r.findActive(ctx, tenantID, "phones", phone)
+}
+
+// findActive returns the first unmerged contact whose identity field contains value
+func (r *ContactRepository) findActive(ctx context.Context, tenantID, field, value string) (*models.Contact, error) {
+	query := r.Client().Collection(r.getContactsCollection(tenantID)).
+		Where(field, "array-contains", value)
+
+	contacts, err := r.list(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	for _, contact := range contacts {
+		if !contact.IsMerged() {
+			return contact, nil
+		}
+	}
+
+	return nil, ErrNotFound
+}
+
+// list runs a contact query
+func (r *ContactRepository) list(ctx context.Context, query firestore.Query) ([]*models.Contact, error) {
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	contacts := make([]*models.Contact, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate contacts: %w", err)
+		}
+
+		var contact models.Contact
+		if err := doc.DataTo(&contact); err != nil {
+			return nil, fmt.Errorf("failed to decode contact: %w", err)
+		}
+
+		contact.ID = doc.Ref.ID
+		contacts = append(contacts, &contact)
+	}
+
+	return contacts, nil
+}
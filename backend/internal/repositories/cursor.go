@@ -0,0 +1,164 @@
+package repositories
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+)
+
+// cursorToken is the payload of an opaque pagination cursor. It records the
+// ordering of the page that produced it plus the sort value and document ID
+// of the last document, so the next page starts strictly after it even when
+// several documents share the same sort value.
+type cursorToken struct {
+	OrderBy string     `json:"o"`
+	Desc    bool       `json:"d,omitempty"`
+	ID      string     `json:"i"`
+	Time    *time.Time `json:"t,omitempty"`
+	Number  *float64   `json:"n,omitempty"`
+	String  *string    `json:"s,omitempty"`
+}
+
+// value returns the sort value stored in the token (nil for missing values)
+func (t cursorToken) value() interface{} {
+	switch {
+	case t.Time != nil:
+		return *t.Time
+	case t.Number != nil:
+		return *t.Number
+	case t.String != nil:
+		return *t.String
+	default:
+		return nil
+	}
+}
+
+// EncodeCursor builds the opaque cursor pointing after a document with the
+// given sort value and ID under the ordering of opts
+func EncodeCursor(opts PaginationOptions, value interface{}, id string) (string, error) {
+	token := cursorToken{
+		OrderBy: opts.OrderBy,
+		Desc:    opts.Direction == firestore.Desc,
+		ID:      id,
+	}
+
+	v := reflect.ValueOf(value)
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			v = reflect.Value{}
+			break
+		}
+		v = v.Elem()
+	}
+
+	if v.IsValid() {
+		switch val := v.Interface().(type) {
+		case time.Time:
+			token.Time = &val
+		default:
+			switch v.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				n := float64(v.Int())
+				token.Number = &n
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				n := float64(v.Uint())
+				token.Number = &n
+			case reflect.Float32, reflect.Float64:
+				n := v.Float()
+				token.Number = &n
+			case reflect.String:
+				s := v.String()
+				token.String = &s
+			default:
+				return "", fmt.Errorf("%w: cannot build cursor for %s values", ErrInvalidInput, v.Type())
+			}
+		}
+	}
+
+	raw, err := json.Marshal(token)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// ApplyCursor decodes an opaque cursor into opts. The cursor's ordering
+// replaces the ordering of opts, so every page of a listing is sorted the
+// same way as the first one.
+func (opts *PaginationOptions) ApplyCursor(cursor string) error {
+	if cursor == "" {
+		return nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return fmt.Errorf("%w: malformed cursor", ErrInvalidInput)
+	}
+
+	var token cursorToken
+	if err := json.Unmarshal(raw, &token); err != nil || token.OrderBy == "" || token.ID == "" {
+		return fmt.Errorf("%w: malformed cursor", ErrInvalidInput)
+	}
+
+	opts.OrderBy = token.OrderBy
+	opts.Direction = firestore.Asc
+	if token.Desc {
+		opts.Direction = firestore.Desc
+	}
+	opts.StartAfter = token.value()
+	opts.StartAfterID = token.ID
+	opts.Offset = 0
+	return nil
+}
+
+// NextCursor returns the cursor of the page following docs, or "" when docs
+// is the last page (fewer documents than the limit) or the listing is unordered.
+// T must be a struct with an ID field; the sort value is read from the field
+// whose firestore tag matches opts.OrderBy.
+func NextCursor[T any](docs []*T, opts PaginationOptions) string {
+	if opts.OrderBy == "" || opts.Limit <= 0 || len(docs) < opts.Limit {
+		return ""
+	}
+
+	last := reflect.ValueOf(docs[len(docs)-1])
+	if last.IsNil() {
+		return ""
+	}
+	last = last.Elem()
+
+	idField := last.FieldByName("ID")
+	if !idField.IsValid() || idField.Kind() != reflect.String {
+		return ""
+	}
+
+	value, ok := fieldByFirestoreTag(last, opts.OrderBy)
+	if !ok {
+		return ""
+	}
+
+	cursor, err := EncodeCursor(opts, value, idField.String())
+	if err != nil {
+		return ""
+	}
+	return cursor
+}
+
+// fieldByFirestoreTag returns the value of the top-level field tagged name
+func fieldByFirestoreTag(v reflect.Value, name string) (interface{}, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		if tag := strings.Split(sf.Tag.Get("firestore"), ",")[0]; tag == name {
+			return v.Field(i).Interface(), true
+		}
+	}
+	return nil, false
+}
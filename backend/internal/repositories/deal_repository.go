@@ -0,0 +1,165 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+// DealFilters contains filters for listing deals
+type DealFilters struct {
+	PropertyID   string
+	LeadID       string
+	BrokerID     string // Any role in the deal
+	Statuses     []models.DealStatus
+	ClosedFrom   *time.Time // closed_at >= ClosedFrom
+	ClosedBefore *time.Time // closed_at < ClosedBefore
+}
+
+// DealRepository handles Firestore operations for closed deals
+type DealRepository struct {
+	*BaseRepository
+}
+
+// NewDealRepository creates a new deal repository
+func NewDealRepository(client *firestore.Client) *DealRepository {
+	return &DealRepository{
+		BaseRepository: NewBaseRepository(client),
+	}
+}
+
+// getDealsCollection returns the collection path for deals within a tenant
+func (r *DealRepository) getDealsCollection(tenantID string) string {
+	return fmt.Sprintf("tenants/%s/deals", tenantID)
+}
+
+// Create creates a new deal
+func (r *DealRepository) Create(ctx context.Context, deal *models.Deal) error {
+	if deal.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if deal.PropertyID == "" {
+		return fmt.Errorf("%w: property_id is required", ErrInvalidInput)
+	}
+
+	collectionPath := r.getDealsCollection(deal.TenantID)
+	if deal.ID == "" {
+		deal.ID = r.GenerateID(collectionPath)
+	}
+
+	now := time.Now()
+	deal.CreatedAt = now
+	deal.UpdatedAt = now
+
+	if err := r.CreateDocument(ctx, collectionPath, deal.ID, deal); err != nil {
+		return fmt.Errorf("failed to create deal: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves a deal by ID
+func (r *DealRepository) Get(ctx context.Context, tenantID, id string) (*models.Deal, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	var deal models.Deal
+	if err := r.GetDocument(ctx, r.getDealsCollection(tenantID), id, &deal); err != nil {
+		return nil, err
+	}
+
+	deal.ID = id
+	return &deal, nil
+}
+
+// Update updates specific fields of a deal
+func (r *DealRepository) Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	updates["updated_at"] = time.Now()
+
+	firestoreUpdates := make([]firestore.Update, 0, len(updates))
+	for key, value := range updates {
+		firestoreUpdates = append(firestoreUpdates, firestore.Update{Path: key, Value: value})
+	}
+
+	if err := r.UpdateDocument(ctx, r.getDealsCollection(tenantID), id, firestoreUpdates); err != nil {
+		return fmt.Errorf("failed to update deal: %w", err)
+	}
+
+	return nil
+}
+
+// List retrieves deals with filters, most recently closed first unless opts
+// sets another order
+func (r *DealRepository) List(ctx context.Context, tenantID string, filters *DealFilters, opts PaginationOptions) ([]*models.Deal, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	if opts.Limit == 0 {
+		opts.Limit = DefaultPaginationOptions().Limit
+	}
+	if opts.OrderBy == "" {
+		opts.OrderBy = "closed_at"
+		opts.Direction = firestore.Desc
+	}
+
+	query := r.Client().Collection(r.getDealsCollection(tenantID)).Query
+	if filters != nil {
+		if filters.PropertyID != "" {
+			query = query.Where("property_id", "==", filters.PropertyID)
+		}
+		if filters.LeadID != "" {
+			query = query.Where("lead_id", "==", filters.LeadID)
+		}
+		if filters.BrokerID != "" {
+			query = query.Where("broker_ids", "array-contains", filters.BrokerID)
+		}
+		if len(filters.Statuses) > 0 {
+			statuses := make([]string, len(filters.Statuses))
+			for i, status := range filters.Statuses {
+				statuses[i] = string(status)
+			}
+			query = query.Where("status", "in", statuses)
+		}
+		if filters.ClosedFrom != nil {
+			query = query.Where("closed_at", ">=", *filters.ClosedFrom)
+		}
+		if filters.ClosedBefore != nil {
+			query = query.Where("closed_at", "<", *filters.ClosedBefore)
+		}
+	}
+
+	iter := r.ApplyPagination(query, opts).Documents(ctx)
+	defer iter.Stop()
+
+	deals := make([]*models.Deal, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate deals: %w", err)
+		}
+
+		var deal models.Deal
+		if err := doc.DataTo(&deal); err != nil {
+			return nil, fmt.Errorf("failed to decode deal: %w", err)
+		}
+
+		deal.ID = doc.Ref.ID
+		deals = append(deals, &deal)
+	}
+
+	return deals, nil
+}
@@ -0,0 +1,223 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+// DevelopmentUnitFilters contains filters for listing development units
+type DevelopmentUnitFilters struct {
+	PropertyID               string
+	Tower                    string
+	Typology                 string
+	Statuses                 []models.DevelopmentUnitStatus // Any of them
+	ReservationExpiresBefore *time.Time                     // reservation_expires_at < ReservationExpiresBefore
+}
+
+// DevelopmentUnitRepository handles Firestore operations for the units of
+// real estate developments
+type DevelopmentUnitRepository struct {
+	*BaseRepository
+}
+
+// NewDevelopmentUnitRepository creates a new development unit repository
+func NewDevelopmentUnitRepository(client *firestore.Client) *DevelopmentUnitRepository {
+	return &DevelopmentUnitRepository{
+		BaseRepository: NewBaseRepository(client),
+	}
+}
+
+// getUnitsCollection returns the collection path for development units within a tenant
+func (r *DevelopmentUnitRepository) getUnitsCollection(tenantID string) string {
+	return fmt.Sprintf("tenants/%s/development_units", tenantID)
+}
+
+// Create creates a new unit; ErrAlreadyExists when the development already
+// has a unit with its tower and number
+func (r *DevelopmentUnitRepository) Create(ctx context.Context, unit *models.DevelopmentUnit) error {
+	if unit.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if unit.PropertyID == "" {
+		return fmt.Errorf("%w: property_id is required", ErrInvalidInput)
+	}
+
+	unit.ID = models.DevelopmentUnitID(unit.PropertyID, unit.Tower, unit.Number)
+
+	now := time.Now()
+	unit.CreatedAt = now
+	unit.UpdatedAt = now
+
+	if err := r.CreateDocument(ctx, r.getUnitsCollection(unit.TenantID), unit.ID, unit); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Get retrieves a unit by ID
+func (r *DevelopmentUnitRepository) Get(ctx context.Context, tenantID, id string) (*models.DevelopmentUnit, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	var unit models.DevelopmentUnit
+	if err := r.GetDocument(ctx, r.getUnitsCollection(tenantID), id, &unit); err != nil {
+		return nil, err
+	}
+
+	unit.ID = id
+	return &unit, nil
+}
+
+// Update updates specific fields of a unit
+func (r *DevelopmentUnitRepository) Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	updates["updated_at"] = time.Now()
+
+	firestoreUpdates := make([]firestore.Update, 0, len(updates))
+	for key, value := range updates {
+		firestoreUpdates = append(firestoreUpdates, firestore.Update{Path: key, Value: value})
+	}
+
+	if err := r.UpdateDocument(ctx, r.getUnitsCollection(tenantID), id, firestoreUpdates); err != nil {
+		return fmt.Errorf("failed to update development unit: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateIf updates a unit only when check accepts its current state. The
+// read and the write run in one transaction, so two brokers cannot reserve
+// the same unit. It returns false when check refused the unit.
+func (r *DevelopmentUnitRepository) UpdateIf(ctx context.Context, tenantID, id string, check func(unit *models.DevelopmentUnit) bool, updates map[string]interface{}) (bool, error) {
+	if tenantID == "" {
+		return false, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if id == "" {
+		return false, fmt.Errorf("%w: document ID is required", ErrInvalidInput)
+	}
+
+	updates["updated_at"] = time.Now()
+	firestoreUpdates := make([]firestore.Update, 0, len(updates))
+	for key, value := range updates {
+		firestoreUpdates = append(firestoreUpdates, firestore.Update{Path: key, Value: value})
+	}
+
+	ref := r.Client().Collection(r.getUnitsCollection(tenantID)).Doc(id)
+	updated := false
+	err := r.Client().RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		updated = false
+
+		doc, err := tx.Get(ref)
+		if err != nil {
+			if status.Code(err) == codes.NotFound {
+				return ErrNotFound
+			}
+			return err
+		}
+
+		var unit models.DevelopmentUnit
+		if err := doc.DataTo(&unit); err != nil {
+			return fmt.Errorf("failed to decode development unit: %w", err)
+		}
+		unit.ID = id
+		if !check(&unit) {
+			return nil
+		}
+
+		updated = true
+		return tx.Update(ref, firestoreUpdates)
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to update development unit: %w", err)
+	}
+
+	return updated, nil
+}
+
+// Delete deletes a unit
+func (r *DevelopmentUnitRepository) Delete(ctx context.Context, tenantID, id string) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	return r.DeleteDocument(ctx, r.getUnitsCollection(tenantID), id)
+}
+
+// List retrieves units with filters, lowest floor first (earliest
+// reservation expiry first with ReservationExpiresBefore) unless opts sets
+// another order
+func (r *DevelopmentUnitRepository) List(ctx context.Context, tenantID string, filters *DevelopmentUnitFilters, opts PaginationOptions) ([]*models.DevelopmentUnit, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	if opts.Limit == 0 {
+		opts.Limit = DefaultPaginationOptions().Limit
+	}
+	if opts.OrderBy == "" {
+		opts.OrderBy, opts.Direction = "floor", firestore.Asc
+		if filters != nil && filters.ReservationExpiresBefore != nil {
+			opts.OrderBy = "reservation_expires_at"
+		}
+	}
+
+	query := r.Client().Collection(r.getUnitsCollection(tenantID)).Query
+	if filters != nil {
+		if filters.PropertyID != "" {
+			query = query.Where("property_id", "==", filters.PropertyID)
+		}
+		if filters.Tower != "" {
+			query = query.Where("tower", "==", filters.Tower)
+		}
+		if filters.Typology != "" {
+			query = query.Where("typology", "==", filters.Typology)
+		}
+		if len(filters.Statuses) > 0 {
+			statuses := make([]string, len(filters.Statuses))
+			for i, status := range filters.Statuses {
+				statuses[i] = string(status)
+			}
+			query = query.Where("status", "in", statuses)
+		}
+		if filters.ReservationExpiresBefore != nil {
+			query = query.Where("reservation_expires_at", "<", *filters.ReservationExpiresBefore)
+		}
+	}
+
+	iter := r.ApplyPagination(query, opts).Documents(ctx)
+	defer iter.Stop()
+
+	units := make([]*models.DevelopmentUnit, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate development units: %w", err)
+		}
+
+		var unit models.DevelopmentUnit
+		if err := doc.DataTo(&unit); err != nil {
+			return nil, fmt.Errorf("failed to decode development unit: %w", err)
+		}
+
+		unit.ID = doc.Ref.ID
+		units = append(units, &unit)
+	}
+
+	return units, nil
+}
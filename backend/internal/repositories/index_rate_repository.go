@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+const (
+	indexRatesCollection = "index_rates"
+	indexRateBatchSize   = 500 // Firestore batch write limit
+)
+
+// IndexRateRepository handles Firestore operations for the price index series
+// (shared by every tenant)
+type IndexRateRepository struct {
+	*BaseRepository
+}
+
+// NewIndexRateRepository creates a new index rate repository
+func NewIndexRateRepository(client *firestore.Client) *IndexRateRepository {
+	return &IndexRateRepository{
+		BaseRepository: NewBaseRepository(client),
+	}
+}
+
+// Upsert creates or replaces index months, in batches
+func (r *IndexRateRepository) Upsert(ctx context.Context, rates []*models.IndexRate) error {
+	now := time.Now()
+	for start := 0; start < len(rates); start += indexRateBatchSize {
+		end := start + indexRateBatchSize
+		if end > len(rates) {
+			end = len(rates)
+		}
+
+		batch := r.Client().Batch()
+		for _, rate := range rates[start:end] {
+			if rate.Index == "" || rate.Period == "" {
+				return fmt.Errorf("%w: index and period are required", ErrInvalidInput)
+			}
+			rate.ID = models.IndexRateID(rate.Index, rate.Period)
+			rate.UpdatedAt = now
+			batch.Set(r.Client().Collection(indexRatesCollection).Doc(rate.ID), rate)
+		}
+		if _, err := batch.Commit(ctx); err != nil {
+			return fmt.Errorf("failed to save index rates: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// List retrieves the months of an index between two periods (YYYY-MM,
+// inclusive; empty is unbounded), oldest first
+func (r *IndexRateRepository) List(ctx context.Context, index models.IndexationType, from, to string) ([]*models.IndexRate, error) {
+	if index == "" {
+		return nil, fmt.Errorf("%w: index is required", ErrInvalidInput)
+	}
+
+	query := r.Client().Collection(indexRatesCollection).Where("index", "==", index)
+	if from != "" {
+		query = query.Where("period", ">=", from)
+	}
+	if to != "" {
+		query = query.Where("period", "<=", to)
+	}
+
+	iter := query.OrderBy("period", firestore.Asc).Documents(ctx)
+	defer iter.Stop()
+
+	rates := make([]*models.IndexRate, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate index rates: %w", err)
+		}
+
+		var rate models.IndexRate
+		if err := doc.DataTo(&rate); err != nil {
+			return nil, fmt.Errorf("failed to decode index rate: %w", err)
+		}
+
+		rate.ID = doc.Ref.ID
+		rates = append(rates, &rate)
+	}
+
+	return rates, nil
+}
@@ -0,0 +1,362 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/utils"
+)
+
+// The interfaces below describe the persistence contract consumed by the
+// services layer. The Firestore repositories in this package implement them,
+// and so does the in-memory backend in internal/repositories/memory, which
+// allows the services to run and be tested without a live Firestore.
+
+// TenantStore persists tenants
+type TenantStore interface {
+	Create(ctx context.Context, tenant *models.Tenant) error
+	Get(ctx context.Context, id string) (*models.Tenant, error)
+	GetBySlug(ctx context.Context, slug string) (*models.Tenant, error)
+	Update(ctx context.Context, id string, updates map[string]interface{}) error
+	Delete(ctx context.Context, id string) error
+	List(ctx context.Context, opts PaginationOptions) ([]*models.Tenant, error)
+	ListActive(ctx context.Context, opts PaginationOptions) ([]*models.Tenant, error)
+}
+
+// BrokerStore persists brokers within a tenant
+type BrokerStore interface {
+	Create(ctx context.Context, broker *models.Broker) error
+	Get(ctx context.Context, tenantID, id string) (*models.Broker, error)
+	GetByFirebaseUID(ctx context.Context, tenantID, firebaseUID string) (*models.Broker, error)
+	GetByEmail(ctx context.Context, tenantID, email string) (*models.Broker, error)
+	Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error
+	Delete(ctx context.Context, tenantID, id string) error
+	List(ctx context.Context, tenantID string, opts PaginationOptions) ([]*models.Broker, error)
+	ListActive(ctx context.Context, tenantID string, opts PaginationOptions) ([]*models.Broker, error)
+	ListByRole(ctx context.Context, tenantID, role string, opts PaginationOptions) ([]*models.Broker, error)
+}
+
+// UserStore persists administrative users within a tenant
+type UserStore interface {
+	Create(ctx context.Context, user *models.User) error
+	Get(ctx context.Context, tenantID, userID string) (*models.User, error)
+	GetByEmail(ctx context.Context, tenantID, email string) (*models.User, error)
+	GetByFirebaseUID(ctx context.Context, tenantID, firebaseUID string) (*models.User, error)
+	List(ctx context.Context, tenantID string) ([]*models.User, error)
+	Update(ctx context.Context, tenantID, userID string, updates map[string]interface{}) error
+	Delete(ctx context.Context, tenantID, userID string) error
+	ListByRole(ctx context.Context, tenantID, role string) ([]*models.User, error)
+	ListActive(ctx context.Context, tenantID string) ([]*models.User, error)
+}
+
+// OwnerStore persists property owners within a tenant
+type OwnerStore interface {
+	Create(ctx context.Context, owner *models.Owner) error
+	Get(ctx context.Context, tenantID, id string) (*models.Owner, error)
+	GetByEmail(ctx context.Context, tenantID, email string) (*models.Owner, error)
+	GetByDocument(ctx context.Context, tenantID, document string) (*models.Owner, error)
+	Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error
+	Delete(ctx context.Context, tenantID, id string) error
+	List(ctx context.Context, tenantID string, opts PaginationOptions) ([]*models.Owner, error)
+	ListByStatus(ctx context.Context, tenantID string, status models.OwnerStatus, opts PaginationOptions) ([]*models.Owner, error)
+	ListWithoutConsent(ctx context.Context, tenantID string, opts PaginationOptions) ([]*models.Owner, error)
+}
+
+// PropertyStore persists properties
+type PropertyStore interface {
+	Create(ctx context.Context, property *models.Property) error
+	Get(ctx context.Context, tenantID, id string) (*models.Property, error)
+	GetMany(ctx context.Context, ids []string) ([]*models.Property, error)
+	GetBySlug(ctx context.Context, tenantID, slug string) (*models.Property, error)
+	GetBySlugPublic(ctx context.Context, slug string) (*models.Property, error)
+	GetByExternalID(ctx context.Context, tenantID, externalSource, externalID string) (*models.Property, error)
+	Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error
+	Delete(ctx context.Context, tenantID, id string) error
+	List(ctx context.Context, tenantID string, filters *PropertyFilters, opts PaginationOptions) ([]*models.Property, error)
+	ListAllPublic(ctx context.Context, filters *PropertyFilters, opts PaginationOptions) ([]*models.Property, error)
+	Count(ctx context.Context, tenantID string, filters *PropertyFilters) (int, error)
+	ListByOwner(ctx context.Context, tenantID, ownerID string, opts PaginationOptions) ([]*models.Property, error)
+	ListByCaptador(ctx context.Context, tenantID, captadorID string, opts PaginationOptions) ([]*models.Property, error)
+	ListByStatus(ctx context.Context, tenantID string, status models.PropertyStatus, opts PaginationOptions) ([]*models.Property, error)
+	ListByVisibility(ctx context.Context, tenantID string, visibility models.PropertyVisibility, opts PaginationOptions) ([]*models.Property, error)
+	ListPossibleDuplicates(ctx context.Context, tenantID string, opts PaginationOptions) ([]*models.Property, error)
+	ListByFingerprint(ctx context.Context, tenantID, fingerprint string) ([]*models.Property, error)
+	SearchByLocation(ctx context.Context, tenantID, city, neighborhood string, opts PaginationOptions) ([]*models.Property, error)
+	ListPublicInBounds(ctx context.Context, bounds utils.GeoBounds, filters *PropertyFilters, limit int) ([]*models.Property, error)
+	ListPublicNearby(ctx context.Context, lat, lng, radiusKm float64, filters *PropertyFilters, limit int) ([]*models.Property, error)
+}
+
+// ListingStore persists listings
+type ListingStore interface {
+	Create(ctx context.Context, listing *models.Listing) error
+	Get(ctx context.Context, tenantID, id string) (*models.Listing, error)
+	GetMany(ctx context.Context, tenantID string, ids []string) ([]*models.Listing, error)
+	Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error
+	Delete(ctx context.Context, tenantID, id string) error
+	List(ctx context.Context, tenantID string, opts PaginationOptions) ([]*models.Listing, error)
+	ListByProperty(ctx context.Context, tenantID, propertyID string, opts PaginationOptions) ([]*models.Listing, error)
+	ListByBroker(ctx context.Context, tenantID, brokerID string, opts PaginationOptions) ([]*models.Listing, error)
+	ListActive(ctx context.Context, tenantID string, opts PaginationOptions) ([]*models.Listing, error)
+	GetCanonicalForProperty(ctx context.Context, tenantID, propertyID string) (*models.Listing, error)
+	UnsetCanonicalForProperty(ctx context.Context, tenantID, propertyID string) error
+}
+
+// PropertyBrokerRoleStore persists broker roles on properties
+type PropertyBrokerRoleStore interface {
+	Create(ctx context.Context, role *models.PropertyBrokerRole) error
+	Get(ctx context.Context, tenantID, id string) (*models.PropertyBrokerRole, error)
+	GetByPropertyAndBroker(ctx context.Context, tenantID, propertyID, brokerID string, roleType models.BrokerPropertyRole) (*models.PropertyBrokerRole, error)
+	Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error
+	Delete(ctx context.Context, tenantID, id string) error
+	ListByProperty(ctx context.Context, tenantID, propertyID string, opts PaginationOptions) ([]*models.PropertyBrokerRole, error)
+	ListByBroker(ctx context.Context, tenantID, brokerID string, opts PaginationOptions) ([]*models.PropertyBrokerRole, error)
+	ListByRole(ctx context.Context, tenantID string, roleType models.BrokerPropertyRole, opts PaginationOptions) ([]*models.PropertyBrokerRole, error)
+	GetOriginatingBroker(ctx context.Context, tenantID, propertyID string) (*models.PropertyBrokerRole, error)
+	GetPrimaryBroker(ctx context.Context, tenantID, propertyID string) (*models.PropertyBrokerRole, error)
+	UnsetPrimaryForProperty(ctx context.Context, tenantID, propertyID string) error
+}
+
+// LeadStore persists leads within a tenant
+type LeadStore interface {
+	Create(ctx context.Context, lead *models.Lead) error
+	Get(ctx context.Context, tenantID, id string) (*models.Lead, error)
+	Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error
+	Delete(ctx context.Context, tenantID, id string) error
+	List(ctx context.Context, tenantID string, filters *LeadFilters, opts PaginationOptions) ([]*models.Lead, error)
+	ListByProperty(ctx context.Context, tenantID, propertyID string, opts PaginationOptions) ([]*models.Lead, error)
+	ListByStatus(ctx context.Context, tenantID string, status models.LeadStatus, opts PaginationOptions) ([]*models.Lead, error)
+	ListByChannel(ctx context.Context, tenantID string, channel models.LeadChannel, opts PaginationOptions) ([]*models.Lead, error)
+	GetByEmail(ctx context.Context, tenantID, propertyID, email string) (*models.Lead, error)
+	GetByPhone(ctx context.Context, tenantID, propertyID, phone string) (*models.Lead, error)
+	ListWithRevokedConsent(ctx context.Context, tenantID string, opts PaginationOptions) ([]*models.Lead, error)
+	CountOpenByBroker(ctx context.Context, tenantID, brokerID string) (int, error)
+	ListOverdueResponses(ctx context.Context, tenantID string, dueBy time.Time, limit int) ([]*models.Lead, error)
+	RevokeConsent(ctx context.Context, tenantID, id string) error
+	Anonymize(ctx context.Context, tenantID, id string, reason string) error
+}
+
+// ContactStore persists the contacts (lead identities) of a tenant
+type ContactStore interface {
+	Create(ctx context.Context, contact *models.Contact) error
+	Get(ctx context.Context, tenantID, id string) (*models.Contact, error)
+	Update(ctx context.Context, contact *models.Contact) error
+	List(ctx context.Context, tenantID string, opts PaginationOptions) ([]*models.Contact, error)
+	FindByEmail(ctx context.Context, tenantID, email string) (*models.Contact, error)
+	FindByPhone(ctx context.Context, tenantID, phone string) (*models.Contact, error)
+}
+
+// LeadNoteStore persists the notes brokers write on leads
+type LeadNoteStore interface {
+	Create(ctx context.Context, note *models.LeadNote) error
+	Get(ctx context.Context, tenantID, id string) (*models.LeadNote, error)
+	Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error
+	Delete(ctx context.Context, tenantID, id string) error
+	ListByLead(ctx context.Context, tenantID, leadID string, opts PaginationOptions) ([]*models.LeadNote, error)
+}
+
+// LeadTaskStore persists the follow-up tasks of leads
+type LeadTaskStore interface {
+	Create(ctx context.Context, task *models.LeadTask) error
+	Get(ctx context.Context, tenantID, id string) (*models.LeadTask, error)
+	Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error
+	List(ctx context.Context, tenantID string, filters *LeadTaskFilters, opts PaginationOptions) ([]*models.LeadTask, error)
+	ListDueReminders(ctx context.Context, tenantID string, dueBy time.Time, limit int) ([]*models.LeadTask, error)
+}
+
+// LeadInteractionStore persists the calls, visits and messages logged on leads
+type LeadInteractionStore interface {
+	Create(ctx context.Context, interaction *models.LeadInteraction) error
+	Get(ctx context.Context, tenantID, id string) (*models.LeadInteraction, error)
+	ListByLead(ctx context.Context, tenantID, leadID string, opts PaginationOptions) ([]*models.LeadInteraction, error)
+}
+
+// VisitStore persists property visits
+type VisitStore interface {
+	Create(ctx context.Context, visit *models.Visit) error
+	CreateIfAvailable(ctx context.Context, visit *models.Visit) (bool, error)
+	Get(ctx context.Context, tenantID, id string) (*models.Visit, error)
+	Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error
+	List(ctx context.Context, tenantID string, filters *VisitFilters, opts PaginationOptions) ([]*models.Visit, error)
+}
+
+// ProposalStore persists proposals (offers and counter-offers)
+type ProposalStore interface {
+	Create(ctx context.Context, proposal *models.Proposal) error
+	Get(ctx context.Context, tenantID, id string) (*models.Proposal, error)
+	Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error
+	List(ctx context.Context, tenantID string, filters *ProposalFilters, opts PaginationOptions) ([]*models.Proposal, error)
+}
+
+// CommissionStore persists the commission ledger
+type CommissionStore interface {
+	CreateEntries(ctx context.Context, entries []*models.CommissionEntry) error
+	Get(ctx context.Context, tenantID, id string) (*models.CommissionEntry, error)
+	Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error
+	List(ctx context.Context, tenantID string, filters *CommissionEntryFilters, opts PaginationOptions) ([]*models.CommissionEntry, error)
+}
+
+// DealStore persists closed deals
+type DealStore interface {
+	Create(ctx context.Context, deal *models.Deal) error
+	Get(ctx context.Context, tenantID, id string) (*models.Deal, error)
+	Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error
+	List(ctx context.Context, tenantID string, filters *DealFilters, opts PaginationOptions) ([]*models.Deal, error)
+}
+
+// RentalContractStore persists rental contracts
+type RentalContractStore interface {
+	Create(ctx context.Context, contract *models.RentalContract) error
+	Get(ctx context.Context, tenantID, id string) (*models.RentalContract, error)
+	Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error
+	List(ctx context.Context, tenantID string, filters *RentalContractFilters, opts PaginationOptions) ([]*models.RentalContract, error)
+}
+
+// IndexRateStore persists the monthly price index series (shared by every tenant)
+type IndexRateStore interface {
+	Upsert(ctx context.Context, rates []*models.IndexRate) error
+	List(ctx context.Context, index models.IndexationType, from, to string) ([]*models.IndexRate, error)
+}
+
+// RentAdjustmentStore persists the annual rent adjustments of rental contracts
+type RentAdjustmentStore interface {
+	Create(ctx context.Context, adjustment *models.RentAdjustment) error
+	Get(ctx context.Context, tenantID, id string) (*models.RentAdjustment, error)
+	Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error
+	List(ctx context.Context, tenantID string, filters *RentAdjustmentFilters, opts PaginationOptions) ([]*models.RentAdjustment, error)
+}
+
+// RentInvoiceStore persists the monthly invoices of rental contracts
+type RentInvoiceStore interface {
+	Create(ctx context.Context, invoice *models.RentInvoice) error
+	Get(ctx context.Context, tenantID, id string) (*models.RentInvoice, error)
+	GetByReference(ctx context.Context, reference string) (*models.RentInvoice, error)
+	Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error
+	UpdateIf(ctx context.Context, tenantID, id string, check func(invoice *models.RentInvoice) bool, updates map[string]interface{}) (bool, error)
+	List(ctx context.Context, tenantID string, filters *RentInvoiceFilters, opts PaginationOptions) ([]*models.RentInvoice, error)
+}
+
+// StayCalendarStore persists the short-stay terms of properties let by the night
+type StayCalendarStore interface {
+	Get(ctx context.Context, tenantID, propertyID string) (*models.StayCalendar, error)
+	Save(ctx context.Context, calendar *models.StayCalendar) error
+	Update(ctx context.Context, tenantID, propertyID string, updates map[string]interface{}) error
+	List(ctx context.Context, tenantID string, opts PaginationOptions) ([]*models.StayCalendar, error)
+}
+
+// BookingStore persists the bookings and blocks of stay calendars
+type BookingStore interface {
+	Create(ctx context.Context, booking *models.Booking) error
+	CreateIfAvailable(ctx context.Context, booking *models.Booking, at time.Time) (bool, error)
+	Get(ctx context.Context, tenantID, id string) (*models.Booking, error)
+	Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error
+	UpdateIf(ctx context.Context, tenantID, id string, check func(booking *models.Booking) bool, updates map[string]interface{}) (bool, error)
+	List(ctx context.Context, tenantID string, filters *BookingFilters, opts PaginationOptions) ([]*models.Booking, error)
+}
+
+// DevelopmentUnitStore persists the units of real estate developments
+type DevelopmentUnitStore interface {
+	Create(ctx context.Context, unit *models.DevelopmentUnit) error
+	Get(ctx context.Context, tenantID, id string) (*models.DevelopmentUnit, error)
+	Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error
+	UpdateIf(ctx context.Context, tenantID, id string, check func(unit *models.DevelopmentUnit) bool, updates map[string]interface{}) (bool, error)
+	Delete(ctx context.Context, tenantID, id string) error
+	List(ctx context.Context, tenantID string, filters *DevelopmentUnitFilters, opts PaginationOptions) ([]*models.DevelopmentUnit, error)
+}
+
+// LeadRoutingCursorStore keeps the round-robin turn of each tenant's lead routing
+type LeadRoutingCursorStore interface {
+	NextTurn(ctx context.Context, tenantID string) (int64, error)
+}
+
+// ActivityLogStore persists the audit trail of a tenant
+type ActivityLogStore interface {
+	Create(ctx context.Context, log *models.ActivityLog) error
+	Get(ctx context.Context, tenantID, id string) (*models.ActivityLog, error)
+	GetByEventID(ctx context.Context, tenantID, eventID string) (*models.ActivityLog, error)
+	GetByRequestID(ctx context.Context, tenantID, requestID string) ([]*models.ActivityLog, error)
+	List(ctx context.Context, tenantID string, filters *ActivityLogFilters, opts PaginationOptions) ([]*models.ActivityLog, error)
+	ListByEventType(ctx context.Context, tenantID, eventType string, opts PaginationOptions) ([]*models.ActivityLog, error)
+	ListByActor(ctx context.Context, tenantID string, actorType models.ActorType, actorID string, opts PaginationOptions) ([]*models.ActivityLog, error)
+	ListByDateRange(ctx context.Context, tenantID string, startDate, endDate time.Time, opts PaginationOptions) ([]*models.ActivityLog, error)
+	ListForEntity(ctx context.Context, tenantID, entityID string, opts PaginationOptions) ([]*models.ActivityLog, error)
+	ListPropertyLogs(ctx context.Context, tenantID, propertyID string, opts PaginationOptions) ([]*models.ActivityLog, error)
+	ListLeadLogs(ctx context.Context, tenantID, leadID string, opts PaginationOptions) ([]*models.ActivityLog, error)
+	Delete(ctx context.Context, tenantID, id string) error
+}
+
+// OwnerConfirmationTokenStore persists owner confirmation tokens
+type OwnerConfirmationTokenStore interface {
+	Create(ctx context.Context, token *models.OwnerConfirmationToken) error
+	Get(ctx context.Context, tenantID, tokenID string) (*models.OwnerConfirmationToken, error)
+	GetByTokenHash(ctx context.Context, tenantID, tokenHash string) (*models.OwnerConfirmationToken, error)
+	Update(ctx context.Context, tenantID, tokenID string, updates map[string]interface{}) error
+	ListByProperty(ctx context.Context, tenantID, propertyID string, opts *PaginationOptions) ([]*models.OwnerConfirmationToken, error)
+}
+
+// ScheduledConfirmationStore persists scheduled owner confirmations
+type ScheduledConfirmationStore interface {
+	Create(ctx context.Context, sc *models.ScheduledConfirmation) error
+	Get(ctx context.Context, tenantID, id string) (*models.ScheduledConfirmation, error)
+	Update(ctx context.Context, sc *models.ScheduledConfirmation) error
+	GetPendingForDate(ctx context.Context, tenantID string, targetDate time.Time) ([]*models.ScheduledConfirmation, error)
+	GetByPropertyAndMonth(ctx context.Context, tenantID, propertyID string, year int, month time.Month) ([]*models.ScheduledConfirmation, error)
+	GetByProviderMessageID(ctx context.Context, providerMessageID string) (*models.ScheduledConfirmation, error)
+	GetByTokenID(ctx context.Context, tenantID, tokenID string) (*models.ScheduledConfirmation, error)
+	ListDueReminders(ctx context.Context, tenantID string, dueBy time.Time) ([]*models.ScheduledConfirmation, error)
+	ListByProperty(ctx context.Context, tenantID, propertyID string, limit int) ([]*models.ScheduledConfirmation, error)
+	ListByTenant(ctx context.Context, tenantID string, status *models.ScheduledConfirmationStatus, limit int) ([]*models.ScheduledConfirmation, error)
+}
+
+// PropertyHistoryStore persists the price and status history of properties
+type PropertyHistoryStore interface {
+	Create(ctx context.Context, entry *models.PropertyHistoryEntry) error
+	ListByProperty(ctx context.Context, tenantID, propertyID string, opts PaginationOptions) ([]*models.PropertyHistoryEntry, error)
+}
+
+// JobRunStore persists the run history of background jobs
+type JobRunStore interface {
+	Create(ctx context.Context, run *models.JobRun) error
+	Update(ctx context.Context, run *models.JobRun) error
+	ListByJob(ctx context.Context, job string, limit int) ([]*models.JobRun, error)
+}
+
+// JobLockStore provides the distributed lock of background jobs
+type JobLockStore interface {
+	Acquire(ctx context.Context, job, owner string, scheduledAt time.Time, ttl time.Duration) (bool, error)
+	Release(ctx context.Context, job, owner string) error
+}
+
+// Compile-time checks that the Firestore repositories satisfy the interfaces
+var (
+	_ TenantStore                 = (*TenantRepository)(nil)
+	_ BrokerStore                 = (*BrokerRepository)(nil)
+	_ UserStore                   = (*UserRepository)(nil)
+	_ OwnerStore                  = (*OwnerRepository)(nil)
+	_ PropertyStore               = (*PropertyRepository)(nil)
+	_ ListingStore                = (*ListingRepository)(nil)
+	_ PropertyBrokerRoleStore     = (*PropertyBrokerRoleRepository)(nil)
+	_ LeadStore                   = (*LeadRepository)(nil)
+	_ ContactStore                = (*ContactRepository)(nil)
+	_ LeadNoteStore               = (*LeadNoteRepository)(nil)
+	_ LeadTaskStore               = (*LeadTaskRepository)(nil)
+	_ LeadInteractionStore        = (*LeadInteractionRepository)(nil)
+	_ VisitStore                  = (*VisitRepository)(nil)
+	_ ProposalStore               = (*ProposalRepository)(nil)
+	_ CommissionStore             = (*CommissionRepository)(nil)
+	_ DealStore                   = (*DealRepository)(nil)
+	_ RentalContractStore         = (*RentalContractRepository)(nil)
+	_ IndexRateStore              = (*IndexRateRepository)(nil)
+	_ RentAdjustmentStore         = (*RentAdjustmentRepository)(nil)
+	_ RentInvoiceStore            = (*RentInvoiceRepository)(nil)
+	_ StayCalendarStore           = (*StayCalendarRepository)(nil)
+	_ BookingStore                = (*BookingRepository)(nil)
+	_ DevelopmentUnitStore        = (*DevelopmentUnitRepository)(nil)
+	_ LeadRoutingCursorStore      = (*LeadRoutingCursorRepository)(nil)
+	_ ActivityLogStore            = (*ActivityLogRepository)(nil)
+	_ OwnerConfirmationTokenStore = (*OwnerConfirmationTokenRepository)(nil)
+	_ ScheduledConfirmationStore  = (*ScheduledConfirmationRepository)(nil)
+	_ PropertyHistoryStore        = (*PropertyHistoryRepository)(nil)
+	_ JobRunStore                 = (*JobRunRepository)(nil)
+	_ JobLockStore                = (*JobLockRepository)(nil)
+)
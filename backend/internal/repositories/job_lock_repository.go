@@ -0,0 +1,110 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+const (
+	jobLocksCollection = "job_locks"
+)
+
+// JobLockRepository implements the distributed lock of background jobs with
+// Firestore transactions, so several server instances can run the scheduler
+type JobLockRepository struct {
+	*BaseRepository
+}
+
+// NewJobLockRepository creates a new job lock repository
+func NewJobLockRepository(client *firestore.Client) *JobLockRepository {
+	return &JobLockRepository{
+		BaseRepository: NewBaseRepository(client),
+	}
+}
+
+// Acquire claims the occurrence of a job scheduled at scheduledAt for owner,
+// holding the lock for ttl. It returns false when another owner holds an
+// unexpired lock or the occurrence (or a later one) was already claimed.
+func (r *JobLockRepository) Acquire(ctx context.Context, job, owner string, scheduledAt time.Time, ttl time.Duration) (bool, error) {
+	if job == "" || owner == "" {
+		return false, fmt.Errorf("%w: job and owner are required", ErrInvalidInput)
+	}
+
+	ref := r.Client().Collection(jobLocksCollection).Doc(job)
+	acquired := false
+
+	err := r.Client().RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		acquired = false
+		now := time.Now()
+
+		doc, err := tx.Get(ref)
+		if err != nil && status.Code(err) != codes.NotFound {
+			return err
+		}
+		if err == nil {
+			var lock models.JobLock
+			if err := doc.DataTo(&lock); err != nil {
+				return fmt.Errorf("failed to decode job lock: %w", err)
+			}
+			if !lock.CanClaim(scheduledAt, now) {
+				return nil
+			}
+		}
+
+		acquired = true
+		return tx.Set(ref, &models.JobLock{
+			Job:         job,
+			Owner:       owner,
+			LockedUntil: now.Add(ttl),
+			ScheduledAt: scheduledAt,
+		})
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire job lock: %w", err)
+	}
+
+	return acquired, nil
+}
+
+// Release ends the lease held by owner; the claimed occurrence is kept
+func (r *JobLockRepository) Release(ctx context.Context, job, owner string) error {
+	if job == "" || owner == "" {
+		return fmt.Errorf("%w: job and owner are required", ErrInvalidInput)
+	}
+
+	ref := r.Client().Collection(jobLocksCollection).Doc(job)
+
+	err := r.Client().RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		doc, err := tx.Get(ref)
+		if err != nil {
+			if status.Code(err) == codes.NotFound {
+				return nil
+			}
+			return err
+		}
+
+		var lock models.JobLock
+		if err := doc.DataTo(&lock); err != nil {
+			return fmt.Errorf("failed to decode job lock: %w", err)
+		}
+		if lock.Owner != owner {
+			return nil
+		}
+
+		return tx.Update(ref, []firestore.Update{
+			{Path: "locked_until", Value: time.Now()},
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to release job lock: %w", err)
+	}
+
+	return nil
+}
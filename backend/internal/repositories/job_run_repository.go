@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+const (
+	jobRunsCollection = "job_runs"
+)
+
+// JobRunRepository handles Firestore operations for background job run history
+type JobRunRepository struct {
+	*BaseRepository
+}
+
+// NewJobRunRepository creates a new job run repository
+func NewJobRunRepository(client *firestore.Client) *JobRunRepository {
+	return &JobRunRepository{
+		BaseRepository: NewBaseRepository(client),
+	}
+}
+
+// Create records a new job run
+func (r *JobRunRepository) Create(ctx context.Context, run *models.JobRun) error {
+	if run.Job == "" {
+		return fmt.Errorf("%w: job is required", ErrInvalidInput)
+	}
+
+	if run.ID == "" {
+		run.ID = r.GenerateID(jobRunsCollection)
+	}
+
+	if err := r.CreateDocument(ctx, jobRunsCollection, run.ID, run); err != nil {
+		return fmt.Errorf("failed to create job run: %w", err)
+	}
+
+	return nil
+}
+
+// Update replaces a job run (used to record its outcome)
+func (r *JobRunRepository) Update(ctx context.Context, run *models.JobRun) error {
+	if run.ID == "" {
+		return fmt.Errorf("%w: id is required", ErrInvalidInput)
+	}
+
+	if err := r.SetDocument(ctx, jobRunsCollection, run.ID, run); err != nil {
+		return fmt.Errorf("failed to update job run: %w", err)
+	}
+
+	return nil
+}
+
+// ListByJob retrieves the latest runs of a job, newest first
+func (r *JobRunRepository) ListByJob(ctx context.Context, job string, limit int) ([]*models.JobRun, error) {
+	if job == "" {
+		return nil, fmt.Errorf("%w: job is required", ErrInvalidInput)
+	}
+
+	if limit <= 0 {
+		limit = DefaultPaginationOptions().Limit
+	}
+
+	iter := r.Client().Collection(jobRunsCollection).
+		Where("job", "==", job).
+		OrderBy("started_at", firestore.Desc).
+		Limit(limit).
+		Documents(ctx)
+	defer iter.Stop()
+
+	runs := make([]*models.JobRun, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate job runs: %w", err)
+		}
+
+		var run models.JobRun
+		if err := doc.DataTo(&run); err != nil {
+			return nil, fmt.Errorf("failed to decode job run: %w", err)
+		}
+
+		run.ID = doc.Ref.ID
+		runs = append(runs, &run)
+	}
+
+	return runs, nil
+}
@@ -0,0 +1,114 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+// LeadInteractionRepository handles Firestore operations for the calls,
+// visits and messages logged on leads
+type LeadInteractionRepository struct {
+	*BaseRepository
+}
+
+// NewLeadInteractionRepository creates a new lead interaction repository
+func NewLeadInteractionRepository(client *firestore.Client) *LeadInteractionRepository {
+	return &LeadInteractionRepository{
+		BaseRepository: NewBaseRepository(client),
+	}
+}
+
+// getLeadInteractionsCollection returns the collection path for lead interactions within a tenant
+func (r *LeadInteractionRepository) getLeadInteractionsCollection(tenantID string) string {
+	return fmt.Sprintf("tenants/%s/lead_interactions", tenantID)
+}
+
+// Create creates a new lead interaction
+func (r *LeadInteractionRepository) Create(ctx context.Context, interaction *models.LeadInteraction) error {
+	if interaction.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if interaction.LeadID == "" {
+		return fmt.Errorf("%w: lead_id is required", ErrInvalidInput)
+	}
+
+	collectionPath := r.getLeadInteractionsCollection(interaction.TenantID)
+	if interaction.ID == "" {
+		interaction.ID = r.GenerateID(collectionPath)
+	}
+
+	interaction.CreatedAt = time.Now()
+
+	if err := r.CreateDocument(ctx, collectionPath, interaction.ID, interaction); err != nil {
+		return fmt.Errorf("failed to create lead interaction: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves a lead interaction by ID
+func (r *LeadInteractionRepository) Get(ctx context.Context, tenantID, id string) (*models.LeadInteraction, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	var interaction models.LeadInteraction
+	if err := r.GetDocument(ctx, r.getLeadInteractionsCollection(tenantID), id, &interaction); err != nil {
+		return nil, err
+	}
+
+	interaction.ID = id
+	return &interaction, nil
+}
+
+// ListByLead retrieves the interactions of a lead, most recent first unless
+// opts sets another order
+func (r *LeadInteractionRepository) ListByLead(ctx context.Context, tenantID, leadID string, opts PaginationOptions) ([]*models.LeadInteraction, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if leadID == "" {
+		return nil, fmt.Errorf("%w: lead_id is required", ErrInvalidInput)
+	}
+
+	if opts.Limit == 0 {
+		opts.Limit = DefaultPaginationOptions().Limit
+	}
+	if opts.OrderBy == "" {
+		opts.OrderBy = "occurred_at"
+		opts.Direction = firestore.Desc
+	}
+
+	query := r.Client().Collection(r.getLeadInteractionsCollection(tenantID)).Where("lead_id", "==", leadID)
+	query = r.ApplyPagination(query, opts)
+
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	interactions := make([]*models.LeadInteraction, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate lead interactions: %w", err)
+		}
+
+		var interaction models.LeadInteraction
+		if err := doc.DataTo(&interaction); err != nil {
+			return nil, fmt.Errorf("failed to decode lead interaction: %w", err)
+		}
+
+		interaction.ID = doc.Ref.ID
+		interactions = append(interactions, &interaction)
+	}
+
+	return interactions, nil
+}
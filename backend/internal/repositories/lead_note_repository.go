@@ -0,0 +1,143 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+// LeadNoteRepository handles Firestore operations for lead notes
+type LeadNoteRepository struct {
+	*BaseRepository
+}
+
+// NewLeadNoteRepository creates a new lead note repository
+func NewLeadNoteRepository(client *firestore.Client) *LeadNoteRepository {
+	return &LeadNoteRepository{
+		BaseRepository: NewBaseRepository(client),
+	}
+}
+
+// getLeadNotesCollection returns the collection path for lead notes within a tenant
+func (r *LeadNoteRepository) getLeadNotesCollection(tenantID string) string {
+	return fmt.Sprintf("tenants/%s/lead_notes", tenantID)
+}
+
+// Create creates a new lead note
+func (r *LeadNoteRepository) Create(ctx context.Context, note *models.LeadNote) error {
+	if note.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if note.LeadID == "" {
+		return fmt.Errorf("%w: lead_id is required", ErrInvalidInput)
+	}
+
+	collectionPath := r.getLeadNotesCollection(note.TenantID)
+	if note.ID == "" {
+		note.ID = r.GenerateID(collectionPath)
+	}
+
+	now := time.Now()
+	note.CreatedAt = now
+	note.UpdatedAt = now
+
+	if err := r.CreateDocument(ctx, collectionPath, note.ID, note); err != nil {
+		return fmt.Errorf("failed to create lead note: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves a lead note by ID
+func (r *LeadNoteRepository) Get(ctx context.Context, tenantID, id string) (*models.LeadNote, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	var note models.LeadNote
+	if err := r.GetDocument(ctx, r.getLeadNotesCollection(tenantID), id, &note); err != nil {
+		return nil, err
+	}
+
+	note.ID = id
+	return &note, nil
+}
+
+// Update updates specific fields of a lead note
+func (r *LeadNoteRepository) Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	updates["updated_at"] = time.Now()
+
+	firestoreUpdates := make([]firestore.Update, 0, len(updates))
+	for key, value := range updates {
+		firestoreUpdates = append(firestoreUpdates, firestore.Update{Path: key, Value: value})
+	}
+
+	if err := r.UpdateDocument(ctx, r.getLeadNotesCollection(tenantID), id, firestoreUpdates); err != nil {
+		return fmt.Errorf("failed to update lead note: %w", err)
+	}
+
+	return nil
+}
+
+// Delete deletes a lead note
+func (r *LeadNoteRepository) Delete(ctx context.Context, tenantID, id string) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	if err := r.DeleteDocument(ctx, r.getLeadNotesCollection(tenantID), id); err != nil {
+		return fmt.Errorf("failed to delete lead note: %w", err)
+	}
+
+	return nil
+}
+
+// ListByLead retrieves the notes of a lead
+func (r *LeadNoteRepository) ListByLead(ctx context.Context, tenantID, leadID string, opts PaginationOptions) ([]*models.LeadNote, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if leadID == "" {
+		return nil, fmt.Errorf("%w: lead_id is required", ErrInvalidInput)
+	}
+
+	if opts.Limit == 0 {
+		opts = DefaultPaginationOptions()
+	}
+
+	query := r.Client().Collection(r.getLeadNotesCollection(tenantID)).Where("lead_id", "==", leadID)
+	query = r.ApplyPagination(query, opts)
+
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	notes := make([]*models.LeadNote, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate lead notes: %w", err)
+		}
+
+		var note models.LeadNote
+		if err := doc.DataTo(&note); err != nil {
+			return nil, fmt.Errorf("failed to decode lead note: %w", err)
+		}
+
+		note.ID = doc.Ref.ID
+		notes = append(notes, &note)
+	}
+
+	return notes, nil
+}
@@ -30,9 +30,13 @@ func (r *LeadRepository) getLeadsCollection(tenantID string) string {
 
 // LeadFilters contains optional filters for lead queries
 type LeadFilters struct {
-	PropertyID string
-	Status     *models.LeadStatus
-	Channel    *models.LeadChannel
+	PropertyID       string
+	ContactID        string
+	AssignedBrokerID string
+	Status           *models.LeadStatus
+	Channel          *models.LeadChannel
+	CreatedFrom      *time.Time // created_at >= CreatedFrom
+	CreatedTo        *time.Time // created_at < CreatedTo
 }
 
 // Create creates a new lead
@@ -144,12 +148,24 @@ func (r *LeadRepository) List(ctx context.Context, tenantID string, filters *Lea
 		if filters.PropertyID != "" {
 			query = query.Where("property_id", "==", filters.PropertyID)
 		}
+		if filters.ContactID != "" {
+			query = query.Where("contact_id", "==", filters.ContactID)
+		}
+		if filters.AssignedBrokerID != "" {
+			query = query.Where("assigned_broker_id", "==", filters.AssignedBrokerID)
+		}
 		if filters.Status != nil {
 			query = query.Where("status", "==", string(*filters.Status))
 		}
 		if filters.Channel != nil {
 			query = query.Where("channel", "==", string(*filters.Channel))
 		}
+		if filters.CreatedFrom != nil {
+			query = query.Where("created_at", ">=", *filters.CreatedFrom)
+		}
+		if filters.CreatedTo != nil {
+			query = query.Where("created_at", "<", *filters.CreatedTo)
+		}
 	}
 
 	query = r.ApplyPagination(query, opts)
@@ -329,6 +345,76 @@ func (r *LeadRepository) ListWithRevokedConsent(ctx context.Context, tenantID st
 	return leads, nil
 }
 
+// CountOpenByBroker counts the leads assigned to a broker that are still open
+// (see models.OpenLeadStatuses)
+func (r *LeadRepository) CountOpenByBroker(ctx context.Context, tenantID, brokerID string) (int, error) {
+	if tenantID == "" {
+		return 0, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if brokerID == "" {
+		return 0, fmt.Errorf("%w: broker_id is required", ErrInvalidInput)
+	}
+
+	statuses := make([]string, len(models.OpenLeadStatuses))
+	for i, status := range models.OpenLeadStatuses {
+		statuses[i] = string(status)
+	}
+
+	collectionPath := r.getLeadsCollection(tenantID)
+	query := r.Client().Collection(collectionPath).
+		Where("assigned_broker_id", "==", brokerID).
+		Where("status", "in", statuses)
+
+	// Use Select() to only fetch document IDs for counting
+	docs, err := query.Select().Documents(ctx).GetAll()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count open leads: %w", err)
+	}
+
+	return len(docs), nil
+}
+
+// ListOverdueResponses retrieves leads still waiting for their first
+// response whose target passed by dueBy, oldest target first
+func (r *LeadRepository) ListOverdueResponses(ctx context.Context, tenantID string, dueBy time.Time, limit int) ([]*models.Lead, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	collectionPath := r.getLeadsCollection(tenantID)
+	query := r.Client().Collection(collectionPath).
+		Where("sla_status", "==", string(models.LeadSLAStatusPending)).
+		Where("response_due_at", "<=", dueBy).
+		OrderBy("response_due_at", firestore.Asc)
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	leads := make([]*models.Lead, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate overdue leads: %w", err)
+		}
+
+		var lead models.Lead
+		if err := doc.DataTo(&lead); err != nil {
+			return nil, fmt.Errorf("failed to decode lead: %w", err)
+		}
+
+		lead.ID = doc.Ref.ID
+		leads = append(leads, &lead)
+	}
+
+	return leads, nil
+}
+
 // RevokeConsent marks a lead's consent as revoked
 func (r *LeadRepository) RevokeConsent(ctx context.Context, tenantID, id string) error {
 	if tenantID == "" {
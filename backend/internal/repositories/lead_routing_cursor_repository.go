@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// LeadRoutingCursorRepository keeps the round-robin turn of lead routing in a
+// Firestore counter, so concurrent leads of a tenant never share a turn.
+// Document: /tenants/{tenantId}/lead_routing/round_robin
+type LeadRoutingCursorRepository struct {
+	*BaseRepository
+}
+
+// NewLeadRoutingCursorRepository creates a new lead routing cursor repository
+func NewLeadRoutingCursorRepository(client *firestore.Client) *LeadRoutingCursorRepository {
+	return &LeadRoutingCursorRepository{
+		BaseRepository: NewBaseRepository(client),
+	}
+}
+
+// NextTurn claims the next round-robin turn of a tenant (0 on the first call)
+func (r *LeadRoutingCursorRepository) NextTurn(ctx context.Context, tenantID string) (int64, error) {
+	if tenantID == "" {
+		return 0, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	ref := r.Client().Collection(fmt.Sprintf("tenants/%s/lead_routing", tenantID)).Doc("round_robin")
+	var turn int64
+
+	err := r.Client().RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		turn = 0
+
+		doc, err := tx.Get(ref)
+		if err != nil && status.Code(err) != codes.NotFound {
+			return err
+		}
+		if err == nil {
+			if value, err := doc.DataAt("turn"); err == nil {
+				if next, ok := value.(int64); ok {
+					turn = next
+				}
+			}
+		}
+
+		return tx.Set(ref, map[string]interface{}{
+			"turn":       turn + 1,
+			"updated_at": time.Now(),
+		})
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to advance lead routing turn: %w", err)
+	}
+
+	return turn, nil
+}
@@ -0,0 +1,176 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+// LeadTaskFilters contains filters for listing lead tasks
+type LeadTaskFilters struct {
+	LeadID     string
+	AssignedTo string
+	Status     *models.LeadTaskStatus
+	DueBefore  *time.Time // due_at < DueBefore
+}
+
+// LeadTaskRepository handles Firestore operations for lead follow-up tasks
+type LeadTaskRepository struct {
+	*BaseRepository
+}
+
+// NewLeadTaskRepository creates a new lead task repository
+func NewLeadTaskRepository(client *firestore.Client) *LeadTaskRepository {
+	return &LeadTaskRepository{
+		BaseRepository: NewBaseRepository(client),
+	}
+}
+
+// getLeadTasksCollection returns the collection path for lead tasks within a tenant
+func (r *LeadTaskRepository) getLeadTasksCollection(tenantID string) string {
+	return fmt.Sprintf("tenants/%s/lead_tasks", tenantID)
+}
+
+// Create creates a new lead task
+func (r *LeadTaskRepository) Create(ctx context.Context, task *models.LeadTask) error {
+	if task.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if task.LeadID == "" {
+		return fmt.Errorf("%w: lead_id is required", ErrInvalidInput)
+	}
+
+	collectionPath := r.getLeadTasksCollection(task.TenantID)
+	if task.ID == "" {
+		task.ID = r.GenerateID(collectionPath)
+	}
+
+	now := time.Now()
+	task.CreatedAt = now
+	task.UpdatedAt = now
+
+	if err := r.CreateDocument(ctx, collectionPath, task.ID, task); err != nil {
+		return fmt.Errorf("failed to create lead task: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves a lead task by ID
+func (r *LeadTaskRepository) Get(ctx context.Context, tenantID, id string) (*models.LeadTask, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	var task models.LeadTask
+	if err := r.GetDocument(ctx, r.getLeadTasksCollection(tenantID), id, &task); err != nil {
+		return nil, err
+	}
+
+	task.ID = id
+	return &task, nil
+}
+
+// Update updates specific fields of a lead task
+func (r *LeadTaskRepository) Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	updates["updated_at"] = time.Now()
+
+	firestoreUpdates := make([]firestore.Update, 0, len(updates))
+	for key, value := range updates {
+		firestoreUpdates = append(firestoreUpdates, firestore.Update{Path: key, Value: value})
+	}
+
+	if err := r.UpdateDocument(ctx, r.getLeadTasksCollection(tenantID), id, firestoreUpdates); err != nil {
+		return fmt.Errorf("failed to update lead task: %w", err)
+	}
+
+	return nil
+}
+
+// List retrieves lead tasks with filters, by due date (soonest first) unless
+// opts sets another order
+func (r *LeadTaskRepository) List(ctx context.Context, tenantID string, filters *LeadTaskFilters, opts PaginationOptions) ([]*models.LeadTask, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	if opts.Limit == 0 {
+		opts.Limit = DefaultPaginationOptions().Limit
+	}
+	if opts.OrderBy == "" {
+		opts.OrderBy = "due_at"
+		opts.Direction = firestore.Asc
+	}
+
+	query := r.Client().Collection(r.getLeadTasksCollection(tenantID)).Query
+	if filters != nil {
+		if filters.LeadID != "" {
+			query = query.Where("lead_id", "==", filters.LeadID)
+		}
+		if filters.AssignedTo != "" {
+			query = query.Where("assigned_to", "==", filters.AssignedTo)
+		}
+		if filters.Status != nil {
+			query = query.Where("status", "==", string(*filters.Status))
+		}
+		if filters.DueBefore != nil {
+			query = query.Where("due_at", "<", *filters.DueBefore)
+		}
+	}
+
+	return r.list(ctx, r.ApplyPagination(query, opts))
+}
+
+// ListDueReminders retrieves the tasks whose pending reminder is due by dueBy,
+// oldest first
+func (r *LeadTaskRepository) ListDueReminders(ctx context.Context, tenantID string, dueBy time.Time, limit int) ([]*models.LeadTask, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	query := r.Client().Collection(r.getLeadTasksCollection(tenantID)).
+		Where("reminder_status", "==", string(models.LeadTaskReminderPending)).
+		Where("remind_at", "<=", dueBy).
+		OrderBy("remind_at", firestore.Asc)
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	return r.list(ctx, query)
+}
+
+// list runs a lead task query
+func (r *LeadTaskRepository) list(ctx context.Context, query firestore.Query) ([]*models.LeadTask, error) {
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	tasks := make([]*models.LeadTask, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate lead tasks: %w", err)
+		}
+
+		var task models.LeadTask
+		if err := doc.DataTo(&task); err != nil {
+			return nil, fmt.Errorf("failed to decode lead task: %w", err)
+		}
+
+		task.ID = doc.Ref.ID
+		tasks = append(tasks, &task)
+	}
+
+	return tasks, nil
+}
@@ -77,6 +77,49 @@ func (r *ListingRepository) Get(ctx context.Context, tenantID, id string) (*mode
 	return &listing, nil
 }
 
+// GetMany retrieves listings of a tenant by ID in a single read. The result
+// is aligned with ids; listings not found (or of another tenant) are nil.
+func (r *ListingRepository) GetMany(ctx context.Context, tenantID string, ids []string) ([]*models.Listing, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	listings := make([]*models.Listing, len(ids))
+	if len(ids) == 0 {
+		return listings, nil
+	}
+
+	refs := make([]*firestore.DocumentRef, len(ids))
+	for i, id := range ids {
+		if id == "" {
+			return nil, fmt.Errorf("%w: document ID is required", ErrInvalidInput)
+		}
+		refs[i] = r.Client().Collection(r.getListingsCollection(tenantID)).Doc(id)
+	}
+
+	docs, err := r.Client().GetAll(ctx, refs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get listings: %w", err)
+	}
+
+	for i, doc := range docs {
+		if !doc.Exists() {
+			continue
+		}
+		var listing models.Listing
+		if err := doc.DataTo(&listing); err != nil {
+			return nil, fmt.Errorf("failed to decode listing: %w", err)
+		}
+		if listing.TenantID != tenantID {
+			continue
+		}
+		listing.ID = doc.Ref.ID
+		listings[i] = &listing
+	}
+
+	return listings, nil
+}
+
 // Update updates a listing
 func (r *ListingRepository) Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error {
 	if tenantID == "" {
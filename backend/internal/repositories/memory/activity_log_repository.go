@@ -0,0 +1,206 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// ActivityLogRepository is an in-memory repositories.ActivityLogStore
+type ActivityLogRepository struct {
+	logs *collection[models.ActivityLog]
+}
+
+// NewActivityLogRepository creates a new in-memory activity log repository
+func NewActivityLogRepository() *ActivityLogRepository {
+	return &ActivityLogRepository{
+		logs: newCollection[models.ActivityLog](),
+	}
+}
+
+// Create creates a new activity log entry. Like the Firestore repository it
+// overwrites an entry with the same ID, keeping event writes idempotent.
+func (r *ActivityLogRepository) Create(ctx context.Context, log *models.ActivityLog) error {
+	if log.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if log.EventType == "" {
+		return fmt.Errorf("%w: event_type is required", repositories.ErrInvalidInput)
+	}
+
+	if log.ID == "" {
+		log.ID = newID()
+	}
+
+	if log.Timestamp.IsZero() {
+		log.Timestamp = time.Now()
+	}
+
+	if err := r.logs.put(log.TenantID, log.ID, log); err != nil {
+		return fmt.Errorf("failed to create activity log: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves an activity log by ID
+func (r *ActivityLogRepository) Get(ctx context.Context, tenantID, id string) (*models.ActivityLog, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	return r.logs.get(tenantID, id)
+}
+
+// GetByEventID retrieves an activity log by event ID (for deduplication)
+func (r *ActivityLogRepository) GetByEventID(ctx context.Context, tenantID, eventID string) (*models.ActivityLog, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if eventID == "" {
+		return nil, fmt.Errorf("%w: event_id is required", repositories.ErrInvalidInput)
+	}
+
+	return r.logs.first(tenantID, func(l *models.ActivityLog) bool {
+		return l.EventID == eventID
+	})
+}
+
+// GetByRequestID retrieves activity logs by request ID, newest first
+func (r *ActivityLogRepository) GetByRequestID(ctx context.Context, tenantID, requestID string) ([]*models.ActivityLog, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if requestID == "" {
+		return nil, fmt.Errorf("%w: request_id is required", repositories.ErrInvalidInput)
+	}
+
+	logs := r.logs.find(tenantID, func(l *models.ActivityLog) bool {
+		return l.RequestID == requestID
+	})
+
+	return paginate(logs, repositories.PaginationOptions{OrderBy: "timestamp", Direction: firestore.Desc}), nil
+}
+
+// List retrieves activity logs for a tenant with optional filters and pagination
+func (r *ActivityLogRepository) List(ctx context.Context, tenantID string, filters *repositories.ActivityLogFilters, opts repositories.PaginationOptions) ([]*models.ActivityLog, error) {
+	return r.list(tenantID, opts, func(l *models.ActivityLog) bool {
+		return matchActivityLogFilters(l, filters)
+	})
+}
+
+// ListByEventType retrieves activity logs by event type
+func (r *ActivityLogRepository) ListByEventType(ctx context.Context, tenantID, eventType string, opts repositories.PaginationOptions) ([]*models.ActivityLog, error) {
+	if eventType == "" {
+		return nil, fmt.Errorf("%w: event_type is required", repositories.ErrInvalidInput)
+	}
+
+	return r.List(ctx, tenantID, &repositories.ActivityLogFilters{EventType: eventType}, opts)
+}
+
+// ListByActor retrieves activity logs by actor
+func (r *ActivityLogRepository) ListByActor(ctx context.Context, tenantID string, actorType models.ActorType, actorID string, opts repositories.PaginationOptions) ([]*models.ActivityLog, error) {
+	return r.List(ctx, tenantID, &repositories.ActivityLogFilters{
+		ActorType: &actorType,
+		ActorID:   actorID,
+	}, opts)
+}
+
+// ListByDateRange retrieves activity logs within a date range
+func (r *ActivityLogRepository) ListByDateRange(ctx context.Context, tenantID string, startDate, endDate time.Time, opts repositories.PaginationOptions) ([]*models.ActivityLog, error) {
+	return r.List(ctx, tenantID, &repositories.ActivityLogFilters{
+		StartDate: &startDate,
+		EndDate:   &endDate,
+	}, opts)
+}
+
+// ListForEntity retrieves activity logs whose metadata references entityID
+func (r *ActivityLogRepository) ListForEntity(ctx context.Context, tenantID, entityID string, opts repositories.PaginationOptions) ([]*models.ActivityLog, error) {
+	if entityID == "" {
+		return nil, fmt.Errorf("%w: entity_id is required", repositories.ErrInvalidInput)
+	}
+
+	return r.list(tenantID, opts, func(l *models.ActivityLog) bool {
+		for _, value := range l.Metadata {
+			if s, ok := value.(string); ok && s == entityID {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// ListPropertyLogs retrieves activity logs for a specific property
+func (r *ActivityLogRepository) ListPropertyLogs(ctx context.Context, tenantID, propertyID string, opts repositories.PaginationOptions) ([]*models.ActivityLog, error) {
+	if propertyID == "" {
+		return nil, fmt.Errorf("%w: property_id is required", repositories.ErrInvalidInput)
+	}
+
+	return r.list(tenantID, opts, func(l *models.ActivityLog) bool {
+		return l.Metadata["property_id"] == propertyID
+	})
+}
+
+// ListLeadLogs retrieves activity logs for a specific lead
+func (r *ActivityLogRepository) ListLeadLogs(ctx context.Context, tenantID, leadID string, opts repositories.PaginationOptions) ([]*models.ActivityLog, error) {
+	if leadID == "" {
+		return nil, fmt.Errorf("%w: lead_id is required", repositories.ErrInvalidInput)
+	}
+
+	return r.list(tenantID, opts, func(l *models.ActivityLog) bool {
+		return l.Metadata["lead_id"] == leadID
+	})
+}
+
+// Delete deletes an activity log
+func (r *ActivityLogRepository) Delete(ctx context.Context, tenantID, id string) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	if err := r.logs.remove(tenantID, id); err != nil {
+		return fmt.Errorf("failed to delete activity log: %w", err)
+	}
+	return nil
+}
+
+// list applies the tenant check, default pagination and match to the collection
+func (r *ActivityLogRepository) list(tenantID string, opts repositories.PaginationOptions, match func(*models.ActivityLog) bool) ([]*models.ActivityLog, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	if opts.Limit == 0 {
+		opts = repositories.DefaultPaginationOptions()
+	}
+
+	return paginate(r.logs.find(tenantID, match), opts), nil
+}
+
+// matchActivityLogFilters reports whether a log satisfies every set filter
+func matchActivityLogFilters(l *models.ActivityLog, filters *repositories.ActivityLogFilters) bool {
+	if filters == nil {
+		return true
+	}
+	if filters.EventType != "" && l.EventType != filters.EventType {
+		return false
+	}
+	if filters.ActorType != nil && l.ActorType != *filters.ActorType {
+		return false
+	}
+	if filters.ActorID != "" && l.ActorID != filters.ActorID {
+		return false
+	}
+	if filters.StartDate != nil && l.Timestamp.Before(*filters.StartDate) {
+		return false
+	}
+	if filters.EndDate != nil && l.Timestamp.After(*filters.EndDate) {
+		return false
+	}
+	return true
+}
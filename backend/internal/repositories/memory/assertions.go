@@ -0,0 +1,37 @@
+package memory
+
+import "github.com/altatech/ecosistema-imob/backend/internal/repositories"
+
+// Compile-time checks that the in-memory repositories satisfy the interfaces
+var (
+	_ repositories.TenantStore                 = (*TenantRepository)(nil)
+	_ repositories.BrokerStore                 = (*BrokerRepository)(nil)
+	_ repositories.UserStore                   = (*UserRepository)(nil)
+	_ repositories.OwnerStore                  = (*OwnerRepository)(nil)
+	_ repositories.PropertyStore               = (*PropertyRepository)(nil)
+	_ repositories.ListingStore                = (*ListingRepository)(nil)
+	_ repositories.PropertyBrokerRoleStore     = (*PropertyBrokerRoleRepository)(nil)
+	_ repositories.LeadStore                   = (*LeadRepository)(nil)
+	_ repositories.ContactStore                = (*ContactRepository)(nil)
+	_ repositories.LeadNoteStore               = (*LeadNoteRepository)(nil)
+	_ repositories.LeadTaskStore               = (*LeadTaskRepository)(nil)
+	_ repositories.LeadInteractionStore        = (*LeadInteractionRepository)(nil)
+	_ repositories.VisitStore                  = (*VisitRepository)(nil)
+	_ repositories.ProposalStore               = (*ProposalRepository)(nil)
+	_ repositories.CommissionStore             = (*CommissionRepository)(nil)
+	_ repositories.DealStore                   = (*DealRepository)(nil)
+	_ repositories.RentalContractStore         = (*RentalContractRepository)(nil)
+	_ repositories.IndexRateStore              = (*IndexRateRepository)(nil)
+	_ repositories.RentAdjustmentStore         = (*RentAdjustmentRepository)(nil)
+	_ repositories.RentInvoiceStore            = (*RentInvoiceRepository)(nil)
+	_ repositories.StayCalendarStore           = (*StayCalendarRepository)(nil)
+	_ repositories.BookingStore                = (*BookingRepository)(nil)
+	_ repositories.DevelopmentUnitStore        = (*DevelopmentUnitRepository)(nil)
+	_ repositories.LeadRoutingCursorStore      = (*LeadRoutingCursorRepository)(nil)
+	_ repositories.ActivityLogStore            = (*ActivityLogRepository)(nil)
+	_ repositories.OwnerConfirmationTokenStore = (*OwnerConfirmationTokenRepository)(nil)
+	_ repositories.ScheduledConfirmationStore  = (*ScheduledConfirmationRepository)(nil)
+	_ repositories.PropertyHistoryStore        = (*PropertyHistoryRepository)(nil)
+	_ repositories.JobRunStore                 = (*JobRunRepository)(nil)
+	_ repositories.JobLockStore                = (*JobLockRepository)(nil)
+)
@@ -0,0 +1,181 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/firestore"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// BookingRepository is an in-memory repositories.BookingStore
+type BookingRepository struct {
+	bookings *collection[models.Booking]
+	mu       sync.Mutex // Serializes the checks of CreateIfAvailable and UpdateIf
+}
+
+// NewBookingRepository creates a new in-memory booking repository
+func NewBookingRepository() *BookingRepository {
+	return &BookingRepository{
+		bookings: newCollection[models.Booking](),
+	}
+}
+
+// prepare validates a new booking and sets its ID and timestamps
+func (r *BookingRepository) prepare(booking *models.Booking) error {
+	if booking.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if booking.PropertyID == "" {
+		return fmt.Errorf("%w: property_id is required", repositories.ErrInvalidInput)
+	}
+
+	if booking.ID == "" {
+		booking.ID = newID()
+	}
+
+	now := time.Now()
+	booking.CreatedAt = now
+	booking.UpdatedAt = now
+	return nil
+}
+
+// Create creates a new booking without checking the calendar (imported
+// blocks)
+func (r *BookingRepository) Create(ctx context.Context, booking *models.Booking) error {
+	if err := r.prepare(booking); err != nil {
+		return err
+	}
+
+	if err := r.bookings.insert(booking.TenantID, booking.ID, booking); err != nil {
+		return fmt.Errorf("failed to create booking: %w", err)
+	}
+
+	return nil
+}
+
+// CreateIfAvailable creates a booking unless another booking holding its
+// dates at a time overlaps the stay; false when the dates are taken
+func (r *BookingRepository) CreateIfAvailable(ctx context.Context, booking *models.Booking, at time.Time) (bool, error) {
+	if err := r.prepare(booking); err != nil {
+		return false, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, err := r.bookings.first(booking.TenantID, func(other *models.Booking) bool {
+		return other.PropertyID == booking.PropertyID && other.HoldsDates(at) && other.Overlaps(booking.CheckIn, booking.CheckOut)
+	}); err == nil {
+		return false, nil
+	}
+
+	if err := r.bookings.insert(booking.TenantID, booking.ID, booking); err != nil {
+		return false, fmt.Errorf("failed to create booking: %w", err)
+	}
+
+	return true, nil
+}
+
+// Get retrieves a booking by ID
+func (r *BookingRepository) Get(ctx context.Context, tenantID, id string) (*models.Booking, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	return r.bookings.get(tenantID, id)
+}
+
+// Update updates specific fields of a booking
+func (r *BookingRepository) Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	updates["updated_at"] = time.Now()
+
+	if err := r.bookings.update(tenantID, id, updates); err != nil {
+		return fmt.Errorf("failed to update booking: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateIf updates a booking only when check accepts its current state;
+// false when check refused the booking
+func (r *BookingRepository) UpdateIf(ctx context.Context, tenantID, id string, check func(booking *models.Booking) bool, updates map[string]interface{}) (bool, error) {
+	if tenantID == "" {
+		return false, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	booking, err := r.bookings.get(tenantID, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to update booking: %w", err)
+	}
+	if !check(booking) {
+		return false, nil
+	}
+
+	updates["updated_at"] = time.Now()
+
+	if err := r.bookings.update(tenantID, id, updates); err != nil {
+		return false, fmt.Errorf("failed to update booking: %w", err)
+	}
+
+	return true, nil
+}
+
+// List retrieves bookings with filters, latest check-in first (earliest
+// check-out first with CheckOutAfter, earliest expiry first with
+// ExpiresBefore) unless opts sets another order
+func (r *BookingRepository) List(ctx context.Context, tenantID string, filters *repositories.BookingFilters, opts repositories.PaginationOptions) ([]*models.Booking, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	if opts.Limit == 0 {
+		opts.Limit = repositories.DefaultPaginationOptions().Limit
+	}
+	if opts.OrderBy == "" {
+		opts.OrderBy, opts.Direction = "check_in", firestore.Desc
+		if filters != nil && filters.CheckOutAfter != nil {
+			opts.OrderBy, opts.Direction = "check_out", firestore.Asc
+		} else if filters != nil && filters.ExpiresBefore != nil {
+			opts.OrderBy, opts.Direction = "expires_at", firestore.Asc
+		}
+	}
+
+	bookings := r.bookings.find(tenantID, func(b *models.Booking) bool {
+		if filters == nil {
+			return true
+		}
+		if filters.PropertyID != "" && b.PropertyID != filters.PropertyID {
+			return false
+		}
+		if len(filters.Statuses) > 0 && !slices.Contains(filters.Statuses, b.Status) {
+			return false
+		}
+		if filters.Source != "" && b.Source != filters.Source {
+			return false
+		}
+		if filters.FeedID != "" && b.FeedID != filters.FeedID {
+			return false
+		}
+		if filters.CheckOutAfter != nil && !b.CheckOut.After(*filters.CheckOutAfter) {
+			return false
+		}
+		if filters.ExpiresBefore != nil && (b.ExpiresAt == nil || !b.ExpiresAt.Before(*filters.ExpiresBefore)) {
+			return false
+		}
+		return true
+	})
+	return paginate(bookings, opts), nil
+}
@@ -0,0 +1,146 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// BrokerRepository is an in-memory repositories.BrokerStore
+type BrokerRepository struct {
+	brokers *collection[models.Broker]
+}
+
+// NewBrokerRepository creates a new in-memory broker repository
+func NewBrokerRepository() *BrokerRepository {
+	return &BrokerRepository{
+		brokers: newCollection[models.Broker](),
+	}
+}
+
+// Create creates a new broker
+func (r *BrokerRepository) Create(ctx context.Context, broker *models.Broker) error {
+	if broker.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	if broker.ID == "" {
+		broker.ID = newID()
+	}
+
+	now := time.Now()
+	broker.CreatedAt = now
+	broker.UpdatedAt = now
+
+	if err := r.brokers.insert(broker.TenantID, broker.ID, broker); err != nil {
+		return fmt.Errorf("failed to create broker: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves a broker by ID
+func (r *BrokerRepository) Get(ctx context.Context, tenantID, id string) (*models.Broker, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	return r.brokers.get(tenantID, id)
+}
+
+// GetByFirebaseUID retrieves a broker by Firebase UID
+func (r *BrokerRepository) GetByFirebaseUID(ctx context.Context, tenantID, firebaseUID string) (*models.Broker, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if firebaseUID == "" {
+		return nil, fmt.Errorf("%w: firebase_uid is required", repositories.ErrInvalidInput)
+	}
+
+	return r.brokers.first(tenantID, func(b *models.Broker) bool {
+		return b.FirebaseUID == firebaseUID
+	})
+}
+
+// GetByEmail retrieves a broker by email
+func (r *BrokerRepository) GetByEmail(ctx context.Context, tenantID, email string) (*models.Broker, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if email == "" {
+		return nil, fmt.Errorf("%w: email is required", repositories.ErrInvalidInput)
+	}
+
+	return r.brokers.first(tenantID, func(b *models.Broker) bool {
+		return b.Email == email
+	})
+}
+
+// Update updates a broker
+func (r *BrokerRepository) Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if id == "" {
+		return fmt.Errorf("%w: broker ID is required", repositories.ErrInvalidInput)
+	}
+
+	updates["updated_at"] = time.Now()
+
+	if err := r.brokers.update(tenantID, id, updates); err != nil {
+		return fmt.Errorf("failed to update broker: %w", err)
+	}
+
+	return nil
+}
+
+// Delete deletes a broker
+func (r *BrokerRepository) Delete(ctx context.Context, tenantID, id string) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	if err := r.brokers.remove(tenantID, id); err != nil {
+		return fmt.Errorf("failed to delete broker: %w", err)
+	}
+	return nil
+}
+
+// List retrieves brokers for a tenant with pagination
+func (r *BrokerRepository) List(ctx context.Context, tenantID string, opts repositories.PaginationOptions) ([]*models.Broker, error) {
+	return r.list(tenantID, opts, nil)
+}
+
+// ListActive retrieves active brokers for a tenant
+func (r *BrokerRepository) ListActive(ctx context.Context, tenantID string, opts repositories.PaginationOptions) ([]*models.Broker, error) {
+	return r.list(tenantID, opts, func(b *models.Broker) bool {
+		return b.IsActive
+	})
+}
+
+// ListByRole retrieves brokers by role
+func (r *BrokerRepository) ListByRole(ctx context.Context, tenantID, role string, opts repositories.PaginationOptions) ([]*models.Broker, error) {
+	if role == "" {
+		return nil, fmt.Errorf("%w: role is required", repositories.ErrInvalidInput)
+	}
+
+	return r.list(tenantID, opts, func(b *models.Broker) bool {
+		return b.Role == role
+	})
+}
+
+// list applies the tenant check, default pagination and match to the collection
+func (r *BrokerRepository) list(tenantID string, opts repositories.PaginationOptions, match func(*models.Broker) bool) ([]*models.Broker, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	if opts.Limit == 0 {
+		opts = repositories.DefaultPaginationOptions()
+	}
+
+	return paginate(r.brokers.find(tenantID, match), opts), nil
+}
@@ -0,0 +1,118 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"cloud.google.com/go/firestore"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// CommissionRepository is an in-memory repositories.CommissionStore
+type CommissionRepository struct {
+	entries *collection[models.CommissionEntry]
+}
+
+// NewCommissionRepository creates a new in-memory commission repository
+func NewCommissionRepository() *CommissionRepository {
+	return &CommissionRepository{
+		entries: newCollection[models.CommissionEntry](),
+	}
+}
+
+// CreateEntries creates the entries of a deal (all or none)
+func (r *CommissionRepository) CreateEntries(ctx context.Context, entries []*models.CommissionEntry) error {
+	for _, entry := range entries {
+		if entry.TenantID == "" {
+			return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+		}
+		if entry.DealID == "" {
+			return fmt.Errorf("%w: deal_id is required", repositories.ErrInvalidInput)
+		}
+	}
+
+	now := time.Now()
+	for _, entry := range entries {
+		if entry.ID == "" {
+			entry.ID = newID()
+		}
+		entry.CreatedAt = now
+		entry.UpdatedAt = now
+
+		if err := r.entries.insert(entry.TenantID, entry.ID, entry); err != nil {
+			return fmt.Errorf("failed to create commission entries: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Get retrieves a commission entry by ID
+func (r *CommissionRepository) Get(ctx context.Context, tenantID, id string) (*models.CommissionEntry, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	return r.entries.get(tenantID, id)
+}
+
+// Update updates specific fields of a commission entry
+func (r *CommissionRepository) Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	updates["updated_at"] = time.Now()
+
+	if err := r.entries.update(tenantID, id, updates); err != nil {
+		return fmt.Errorf("failed to update commission entry: %w", err)
+	}
+
+	return nil
+}
+
+// List retrieves commission entries with filters, most recently closed first
+// unless opts sets another order
+func (r *CommissionRepository) List(ctx context.Context, tenantID string, filters *repositories.CommissionEntryFilters, opts repositories.PaginationOptions) ([]*models.CommissionEntry, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	if opts.Limit == 0 {
+		opts.Limit = repositories.DefaultPaginationOptions().Limit
+	}
+	if opts.OrderBy == "" {
+		opts.OrderBy = "closed_at"
+		opts.Direction = firestore.Desc
+	}
+
+	entries := r.entries.find(tenantID, func(e *models.CommissionEntry) bool {
+		if filters == nil {
+			return true
+		}
+		if filters.DealID != "" && e.DealID != filters.DealID {
+			return false
+		}
+		if filters.BrokerID != "" && e.BrokerID != filters.BrokerID {
+			return false
+		}
+		if filters.PropertyID != "" && e.PropertyID != filters.PropertyID {
+			return false
+		}
+		if filters.Period != "" && e.Period != filters.Period {
+			return false
+		}
+		if filters.Beneficiary != "" && e.Beneficiary != filters.Beneficiary {
+			return false
+		}
+		if len(filters.Statuses) > 0 && !slices.Contains(filters.Statuses, e.Status) {
+			return false
+		}
+		return true
+	})
+	return paginate(entries, opts), nil
+}
@@ -0,0 +1,125 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// ContactRepository is an in-memory repositories.ContactStore
+type ContactRepository struct {
+	contacts *collection[models.Contact]
+}
+
+// NewContactRepository creates a new in-memory contact repository
+func NewContactRepository() *ContactRepository {
+	return &ContactRepository{
+		contacts: newCollection[models.Contact](),
+	}
+}
+
+// Create creates a new contact
+func (r *ContactRepository) Create(ctx context.Context, contact *models.Contact) error {
+	if contact.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	if contact.ID == "" {
+		contact.ID = newID()
+	}
+
+	now := time.Now()
+	contact.CreatedAt = now
+	contact.UpdatedAt = now
+
+	if err := r.contacts.insert(contact.TenantID, contact.ID, contact); err != nil {
+		return fmt.Errorf("failed to create contact: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves a contact by ID
+func (r *ContactRepository) Get(ctx context.Context, tenantID, id string) (*models.Contact, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	return r.contacts.get(tenantID, id)
+}
+
+// Update saves the identities, inquiry history and merges of a contact
+func (r *ContactRepository) Update(ctx context.Context, contact *models.Contact) error {
+	if contact.TenantID == "" || contact.ID == "" {
+		return fmt.Errorf("%w: tenant_id and id are required", repositories.ErrInvalidInput)
+	}
+
+	contact.UpdatedAt = time.Now()
+
+	updates := map[string]interface{}{
+		"name":             contact.Name,
+		"emails":           contact.Emails,
+		"phones":           contact.Phones,
+		"lead_ids":         contact.LeadIDs,
+		"property_ids":     contact.PropertyIDs,
+		"inquiry_count":    contact.InquiryCount,
+		"first_inquiry_at": contact.FirstInquiryAt,
+		"last_inquiry_at":  contact.LastInquiryAt,
+		"merged_into_id":   contact.MergedIntoID,
+		"merges":           contact.Merges,
+		"updated_at":       contact.UpdatedAt,
+	}
+
+	if err := r.contacts.update(contact.TenantID, contact.ID, updates); err != nil {
+		return fmt.Errorf("failed to update contact: %w", err)
+	}
+
+	return nil
+}
+
+// List retrieves the contacts of a tenant, merged contacts included
+func (r *ContactRepository) List(ctx context.Context, tenantID string, opts repositories.PaginationOptions) ([]*models.Contact, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	if opts.Limit == 0 {
+		opts = repositories.DefaultPaginationOptions()
+	}
+
+	return paginate(r.contacts.find(tenantID, nil), opts), nil
+}
+
+// FindByEmail retrieves the contact with a normalized email, skipping
+// contacts merged into another
+func (r *ContactRepository) FindByEmail(ctx context.Context, tenantID, email string) (*models.Contact, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if email == "" {
+		return nil, fmt.Errorf("%w: email is required", repositories.ErrInvalidInput)
+	}
+
+	return r.contacts.first(tenantID, func(c *models.Contact) bool {
+		return !c.IsMerged() && slices.Contains(c.Emails, email)
+	})
+}
+
+// FindByPhone retrieves the contact with an E.164 phone, skipping contacts
+// merged into another
+func (r *ContactRepository) FindByPhone(ctx context.Context, tenantID, phone string) (*models.Contact, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if phone == "" {
+		return nil, fmt.Errorf("%w: phone is required", repositories.ErrInvalidInput)
+	}
+
+	return r.contacts.first(tenantID, func(c *models.Contact) bool {
+		return !c.IsMerged() && slices.Contains(c.Phones, phone)
+	})
+}
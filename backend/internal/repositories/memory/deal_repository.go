@@ -0,0 +1,115 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"cloud.google.com/go/firestore"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// DealRepository is an in-memory repositories.DealStore
+type DealRepository struct {
+	deals *collection[models.Deal]
+}
+
+// NewDealRepository creates a new in-memory deal repository
+func NewDealRepository() *DealRepository {
+	return &DealRepository{
+		deals: newCollection[models.Deal](),
+	}
+}
+
+// Create creates a new deal
+func (r *DealRepository) Create(ctx context.Context, deal *models.Deal) error {
+	if deal.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if deal.PropertyID == "" {
+		return fmt.Errorf("%w: property_id is required", repositories.ErrInvalidInput)
+	}
+
+	if deal.ID == "" {
+		deal.ID = newID()
+	}
+
+	now := time.Now()
+	deal.CreatedAt = now
+	deal.UpdatedAt = now
+
+	if err := r.deals.insert(deal.TenantID, deal.ID, deal); err != nil {
+		return fmt.Errorf("failed to create deal: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves a deal by ID
+func (r *DealRepository) Get(ctx context.Context, tenantID, id string) (*models.Deal, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	return r.deals.get(tenantID, id)
+}
+
+// Update updates specific fields of a deal
+func (r *DealRepository) Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	updates["updated_at"] = time.Now()
+
+	if err := r.deals.update(tenantID, id, updates); err != nil {
+		return fmt.Errorf("failed to update deal: %w", err)
+	}
+
+	return nil
+}
+
+// List retrieves deals with filters, most recently closed first unless opts
+// sets another order
+func (r *DealRepository) List(ctx context.Context, tenantID string, filters *repositories.DealFilters, opts repositories.PaginationOptions) ([]*models.Deal, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	if opts.Limit == 0 {
+		opts.Limit = repositories.DefaultPaginationOptions().Limit
+	}
+	if opts.OrderBy == "" {
+		opts.OrderBy = "closed_at"
+		opts.Direction = firestore.Desc
+	}
+
+	deals := r.deals.find(tenantID, func(d *models.Deal) bool {
+		if filters == nil {
+			return true
+		}
+		if filters.PropertyID != "" && d.PropertyID != filters.PropertyID {
+			return false
+		}
+		if filters.LeadID != "" && d.LeadID != filters.LeadID {
+			return false
+		}
+		if filters.BrokerID != "" && !slices.Contains(d.BrokerIDs, filters.BrokerID) {
+			return false
+		}
+		if len(filters.Statuses) > 0 && !slices.Contains(filters.Statuses, d.Status) {
+			return false
+		}
+		if filters.ClosedFrom != nil && d.ClosedAt.Before(*filters.ClosedFrom) {
+			return false
+		}
+		if filters.ClosedBefore != nil && !d.ClosedAt.Before(*filters.ClosedBefore) {
+			return false
+		}
+		return true
+	})
+	return paginate(deals, opts), nil
+}
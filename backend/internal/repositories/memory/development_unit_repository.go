@@ -0,0 +1,148 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/firestore"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// DevelopmentUnitRepository is an in-memory repositories.DevelopmentUnitStore
+type DevelopmentUnitRepository struct {
+	units *collection[models.DevelopmentUnit]
+	mu    sync.Mutex // Serializes the check and write of UpdateIf
+}
+
+// NewDevelopmentUnitRepository creates a new in-memory development unit repository
+func NewDevelopmentUnitRepository() *DevelopmentUnitRepository {
+	return &DevelopmentUnitRepository{
+		units: newCollection[models.DevelopmentUnit](),
+	}
+}
+
+// Create creates a new unit; ErrAlreadyExists when the development already
+// has a unit with its tower and number
+func (r *DevelopmentUnitRepository) Create(ctx context.Context, unit *models.DevelopmentUnit) error {
+	if unit.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if unit.PropertyID == "" {
+		return fmt.Errorf("%w: property_id is required", repositories.ErrInvalidInput)
+	}
+
+	unit.ID = models.DevelopmentUnitID(unit.PropertyID, unit.Tower, unit.Number)
+
+	now := time.Now()
+	unit.CreatedAt = now
+	unit.UpdatedAt = now
+
+	return r.units.insert(unit.TenantID, unit.ID, unit)
+}
+
+// Get retrieves a unit by ID
+func (r *DevelopmentUnitRepository) Get(ctx context.Context, tenantID, id string) (*models.DevelopmentUnit, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	return r.units.get(tenantID, id)
+}
+
+// Update updates specific fields of a unit
+func (r *DevelopmentUnitRepository) Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	updates["updated_at"] = time.Now()
+
+	if err := r.units.update(tenantID, id, updates); err != nil {
+		return fmt.Errorf("failed to update development unit: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateIf updates a unit only when check accepts its current state; false
+// when check refused the unit
+func (r *DevelopmentUnitRepository) UpdateIf(ctx context.Context, tenantID, id string, check func(unit *models.DevelopmentUnit) bool, updates map[string]interface{}) (bool, error) {
+	if tenantID == "" {
+		return false, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	unit, err := r.units.get(tenantID, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to update development unit: %w", err)
+	}
+	if !check(unit) {
+		return false, nil
+	}
+
+	updates["updated_at"] = time.Now()
+
+	if err := r.units.update(tenantID, id, updates); err != nil {
+		return false, fmt.Errorf("failed to update development unit: %w", err)
+	}
+
+	return true, nil
+}
+
+// Delete deletes a unit
+func (r *DevelopmentUnitRepository) Delete(ctx context.Context, tenantID, id string) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	return r.units.remove(tenantID, id)
+}
+
+// List retrieves units with filters, lowest floor first (earliest
+// reservation expiry first with ReservationExpiresBefore) unless opts sets
+// another order
+func (r *DevelopmentUnitRepository) List(ctx context.Context, tenantID string, filters *repositories.DevelopmentUnitFilters, opts repositories.PaginationOptions) ([]*models.DevelopmentUnit, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	if opts.Limit == 0 {
+		opts.Limit = repositories.DefaultPaginationOptions().Limit
+	}
+	if opts.OrderBy == "" {
+		opts.OrderBy, opts.Direction = "floor", firestore.Asc
+		if filters != nil && filters.ReservationExpiresBefore != nil {
+			opts.OrderBy = "reservation_expires_at"
+		}
+	}
+
+	units := r.units.find(tenantID, func(u *models.DevelopmentUnit) bool {
+		if filters == nil {
+			return true
+		}
+		if filters.PropertyID != "" && u.PropertyID != filters.PropertyID {
+			return false
+		}
+		if filters.Tower != "" && u.Tower != filters.Tower {
+			return false
+		}
+		if filters.Typology != "" && u.Typology != filters.Typology {
+			return false
+		}
+		if len(filters.Statuses) > 0 && !slices.Contains(filters.Statuses, u.Status) {
+			return false
+		}
+		if filters.ReservationExpiresBefore != nil && (u.ReservationExpiresAt == nil || !u.ReservationExpiresAt.Before(*filters.ReservationExpiresBefore)) {
+			return false
+		}
+		return true
+	})
+	return paginate(units, opts), nil
+}
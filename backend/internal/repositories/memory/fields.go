@@ -0,0 +1,235 @@
+package memory
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// applyUpdates sets the fields of doc (a pointer to a struct) addressed by the
+// Firestore field paths in updates, e.g. "status" or "rental_info.monthly_rent"
+func applyUpdates(doc interface{}, updates map[string]interface{}) error {
+	root := reflect.ValueOf(doc).Elem()
+	for path, value := range updates {
+		field, err := resolveField(root, path, true)
+		if err != nil {
+			return err
+		}
+		if err := assign(field, value); err != nil {
+			return fmt.Errorf("%w: field %s: %v", repositories.ErrInvalidInput, path, err)
+		}
+	}
+	return nil
+}
+
+// fieldValue returns the value stored at a Firestore field path of doc
+func fieldValue(doc interface{}, path string) (interface{}, bool) {
+	v := reflect.ValueOf(doc)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, false
+		}
+		v = v.Elem()
+	}
+
+	field, err := resolveField(v, path, false)
+	if err != nil || !field.IsValid() {
+		return nil, false
+	}
+	return field.Interface(), true
+}
+
+// resolveField walks a dotted Firestore path through nested structs. When
+// alloc is true nil struct pointers along the path are allocated.
+func resolveField(v reflect.Value, path string, alloc bool) (reflect.Value, error) {
+	for _, name := range strings.Split(path, ".") {
+		for v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !alloc {
+					return reflect.Value{}, fmt.Errorf("%w: unknown field %s", repositories.ErrInvalidInput, path)
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		if v.Kind() != reflect.Struct {
+			return reflect.Value{}, fmt.Errorf("%w: unknown field %s", repositories.ErrInvalidInput, path)
+		}
+
+		next, ok := structField(v, name)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("%w: unknown field %s", repositories.ErrInvalidInput, path)
+		}
+		v = next
+	}
+	return v, nil
+}
+
+// structField finds the field whose firestore tag (or Go name) matches name
+func structField(v reflect.Value, name string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		tag := strings.Split(sf.Tag.Get("firestore"), ",")[0]
+		if tag == "-" {
+			continue
+		}
+		if tag == "" {
+			tag = sf.Name
+		}
+		if tag == name {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// assign stores value into field, converting between compatible types the way
+// Firestore would when decoding the updated document
+func assign(field reflect.Value, value interface{}) error {
+	if value == nil {
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	}
+
+	val := reflect.ValueOf(value)
+
+	// Dereference pointers when the field holds the value itself
+	for val.Kind() == reflect.Ptr && field.Kind() != reflect.Ptr && field.Kind() != reflect.Interface {
+		if val.IsNil() {
+			field.Set(reflect.Zero(field.Type()))
+			return nil
+		}
+		val = val.Elem()
+	}
+
+	if val.Type().AssignableTo(field.Type()) {
+		field.Set(val)
+		return nil
+	}
+
+	// Allocate pointer fields for plain values
+	if field.Kind() == reflect.Ptr {
+		if val.Kind() == reflect.Ptr && val.IsNil() {
+			field.Set(reflect.Zero(field.Type()))
+			return nil
+		}
+		elem := reflect.New(field.Type().Elem())
+		if err := assign(elem.Elem(), val.Interface()); err != nil {
+			return err
+		}
+		field.Set(elem)
+		return nil
+	}
+
+	// Named scalar types (e.g. models.PropertyStatus) and numeric widening
+	if sameScalarFamily(val.Kind(), field.Kind()) {
+		field.Set(val.Convert(field.Type()))
+		return nil
+	}
+
+	// Fall back to a JSON round trip for maps and slices of composite values
+	raw, err := json.Marshal(val.Interface())
+	if err != nil {
+		return err
+	}
+	target := reflect.New(field.Type())
+	if err := json.Unmarshal(raw, target.Interface()); err != nil {
+		return fmt.Errorf("cannot assign %T to %s", value, field.Type())
+	}
+	field.Set(target.Elem())
+	return nil
+}
+
+// compareValues orders two field values the way Firestore orders values of
+// the same type. It returns -1, 0 or 1.
+func compareValues(a, b interface{}) int {
+	a, b = unwrap(a), unwrap(b)
+
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+
+	if at, ok := a.(time.Time); ok {
+		if bt, ok := b.(time.Time); ok {
+			return at.Compare(bt)
+		}
+	}
+
+	av, bv := reflect.ValueOf(a), reflect.ValueOf(b)
+	if af, ok := asFloat(av); ok {
+		if bf, ok := asFloat(bv); ok {
+			switch {
+			case af < bf:
+				return -1
+			case af > bf:
+				return 1
+			}
+			return 0
+		}
+	}
+
+	if av.Kind() == reflect.Bool && bv.Kind() == reflect.Bool {
+		switch {
+		case av.Bool() == bv.Bool():
+			return 0
+		case !av.Bool():
+			return -1
+		}
+		return 1
+	}
+
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+// unwrap dereferences pointers and interfaces
+func unwrap(v interface{}) interface{} {
+	rv := reflect.ValueOf(v)
+	for rv.IsValid() && (rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface) {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return nil
+	}
+	return rv.Interface()
+}
+
+func sameScalarFamily(a, b reflect.Kind) bool {
+	switch {
+	case a == reflect.String && b == reflect.String, a == reflect.Bool && b == reflect.Bool:
+		return true
+	}
+	return isNumeric(a) && isNumeric(b)
+}
+
+func isNumeric(k reflect.Kind) bool {
+	return (k >= reflect.Int && k <= reflect.Uint64) || k == reflect.Float32 || k == reflect.Float64
+}
+
+func asFloat(v reflect.Value) (float64, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
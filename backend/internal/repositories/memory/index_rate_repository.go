@@ -0,0 +1,61 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// IndexRateRepository is an in-memory repositories.IndexRateStore
+type IndexRateRepository struct {
+	rates *collection[models.IndexRate]
+}
+
+// NewIndexRateRepository creates a new in-memory index rate repository
+func NewIndexRateRepository() *IndexRateRepository {
+	return &IndexRateRepository{
+		rates: newCollection[models.IndexRate](),
+	}
+}
+
+// Upsert creates or replaces index months
+func (r *IndexRateRepository) Upsert(ctx context.Context, rates []*models.IndexRate) error {
+	now := time.Now()
+	for _, rate := range rates {
+		if rate.Index == "" || rate.Period == "" {
+			return fmt.Errorf("%w: index and period are required", repositories.ErrInvalidInput)
+		}
+		rate.ID = models.IndexRateID(rate.Index, rate.Period)
+		rate.UpdatedAt = now
+		if err := r.rates.put("", rate.ID, rate); err != nil {
+			return fmt.Errorf("failed to save index rates: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// List retrieves the months of an index between two periods (YYYY-MM,
+// inclusive; empty is unbounded), oldest first
+func (r *IndexRateRepository) List(ctx context.Context, index models.IndexationType, from, to string) ([]*models.IndexRate, error) {
+	if index == "" {
+		return nil, fmt.Errorf("%w: index is required", repositories.ErrInvalidInput)
+	}
+
+	// IDs are {index}_{period}: document order is period order
+	return r.rates.find("", func(rate *models.IndexRate) bool {
+		if rate.Index != index {
+			return false
+		}
+		if from != "" && rate.Period < from {
+			return false
+		}
+		if to != "" && rate.Period > to {
+			return false
+		}
+		return true
+	}), nil
+}
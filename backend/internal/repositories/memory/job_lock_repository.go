@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// JobLockRepository is an in-memory repositories.JobLockStore. It only
+// coordinates schedulers sharing the same process (tests, local runs).
+type JobLockRepository struct {
+	mu    sync.Mutex
+	locks map[string]*models.JobLock
+}
+
+// NewJobLockRepository creates a new in-memory job lock repository
+func NewJobLockRepository() *JobLockRepository {
+	return &JobLockRepository{
+		locks: make(map[string]*models.JobLock),
+	}
+}
+
+// Acquire claims the occurrence of a job scheduled at scheduledAt for owner
+func (r *JobLockRepository) Acquire(ctx context.Context, job, owner string, scheduledAt time.Time, ttl time.Duration) (bool, error) {
+	if job == "" || owner == "" {
+		return false, fmt.Errorf("%w: job and owner are required", repositories.ErrInvalidInput)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if lock, ok := r.locks[job]; ok && !lock.CanClaim(scheduledAt, now) {
+		return false, nil
+	}
+
+	r.locks[job] = &models.JobLock{
+		Job:         job,
+		Owner:       owner,
+		LockedUntil: now.Add(ttl),
+		ScheduledAt: scheduledAt,
+	}
+	return true, nil
+}
+
+// Release ends the lease held by owner; the claimed occurrence is kept
+func (r *JobLockRepository) Release(ctx context.Context, job, owner string) error {
+	if job == "" || owner == "" {
+		return fmt.Errorf("%w: job and owner are required", repositories.ErrInvalidInput)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if lock, ok := r.locks[job]; ok && lock.Owner == owner {
+		lock.LockedUntil = time.Now()
+	}
+	return nil
+}
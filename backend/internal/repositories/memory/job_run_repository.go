@@ -0,0 +1,74 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"cloud.google.com/go/firestore"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// JobRunRepository is an in-memory repositories.JobRunStore
+type JobRunRepository struct {
+	runs *collection[models.JobRun]
+}
+
+// NewJobRunRepository creates a new in-memory job run repository
+func NewJobRunRepository() *JobRunRepository {
+	return &JobRunRepository{
+		runs: newCollection[models.JobRun](),
+	}
+}
+
+// Create records a new job run. Job runs are global, not tenant-scoped.
+func (r *JobRunRepository) Create(ctx context.Context, run *models.JobRun) error {
+	if run.Job == "" {
+		return fmt.Errorf("%w: job is required", repositories.ErrInvalidInput)
+	}
+
+	if run.ID == "" {
+		run.ID = newID()
+	}
+
+	if err := r.runs.insert("", run.ID, run); err != nil {
+		return fmt.Errorf("failed to create job run: %w", err)
+	}
+
+	return nil
+}
+
+// Update replaces a job run (used to record its outcome)
+func (r *JobRunRepository) Update(ctx context.Context, run *models.JobRun) error {
+	if run.ID == "" {
+		return fmt.Errorf("%w: id is required", repositories.ErrInvalidInput)
+	}
+
+	if err := r.runs.put("", run.ID, run); err != nil {
+		return fmt.Errorf("failed to update job run: %w", err)
+	}
+
+	return nil
+}
+
+// ListByJob retrieves the latest runs of a job, newest first
+func (r *JobRunRepository) ListByJob(ctx context.Context, job string, limit int) ([]*models.JobRun, error) {
+	if job == "" {
+		return nil, fmt.Errorf("%w: job is required", repositories.ErrInvalidInput)
+	}
+
+	if limit <= 0 {
+		limit = repositories.DefaultPaginationOptions().Limit
+	}
+
+	runs := r.runs.find("", func(run *models.JobRun) bool {
+		return run.Job == job
+	})
+
+	return paginate(runs, repositories.PaginationOptions{
+		Limit:     limit,
+		OrderBy:   "started_at",
+		Direction: firestore.Desc,
+	}), nil
+}
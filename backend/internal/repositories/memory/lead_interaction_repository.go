@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// LeadInteractionRepository is an in-memory repositories.LeadInteractionStore
+type LeadInteractionRepository struct {
+	interactions *collection[models.LeadInteraction]
+}
+
+// NewLeadInteractionRepository creates a new in-memory lead interaction repository
+func NewLeadInteractionRepository() *LeadInteractionRepository {
+	return &LeadInteractionRepository{
+		interactions: newCollection[models.LeadInteraction](),
+	}
+}
+
+// Create creates a new lead interaction
+func (r *LeadInteractionRepository) Create(ctx context.Context, interaction *models.LeadInteraction) error {
+	if interaction.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if interaction.LeadID == "" {
+		return fmt.Errorf("%w: lead_id is required", repositories.ErrInvalidInput)
+	}
+
+	if interaction.ID == "" {
+		interaction.ID = newID()
+	}
+
+	interaction.CreatedAt = time.Now()
+
+	if err := r.interactions.insert(interaction.TenantID, interaction.ID, interaction); err != nil {
+		return fmt.Errorf("failed to create lead interaction: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves a lead interaction by ID
+func (r *LeadInteractionRepository) Get(ctx context.Context, tenantID, id string) (*models.LeadInteraction, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	return r.interactions.get(tenantID, id)
+}
+
+// ListByLead retrieves the interactions of a lead, most recent first unless
+// opts sets another order
+func (r *LeadInteractionRepository) ListByLead(ctx context.Context, tenantID, leadID string, opts repositories.PaginationOptions) ([]*models.LeadInteraction, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if leadID == "" {
+		return nil, fmt.Errorf("%w: lead_id is required", repositories.ErrInvalidInput)
+	}
+
+	if opts.Limit == 0 {
+		opts.Limit = repositories.DefaultPaginationOptions().Limit
+	}
+	if opts.OrderBy == "" {
+		opts.OrderBy = "occurred_at"
+		opts.Direction = firestore.Desc
+	}
+
+	interactions := r.interactions.find(tenantID, func(i *models.LeadInteraction) bool {
+		return i.LeadID == leadID
+	})
+	return paginate(interactions, opts), nil
+}
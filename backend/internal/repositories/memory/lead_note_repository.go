@@ -0,0 +1,102 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// LeadNoteRepository is an in-memory repositories.LeadNoteStore
+type LeadNoteRepository struct {
+	notes *collection[models.LeadNote]
+}
+
+// NewLeadNoteRepository creates a new in-memory lead note repository
+func NewLeadNoteRepository() *LeadNoteRepository {
+	return &LeadNoteRepository{
+		notes: newCollection[models.LeadNote](),
+	}
+}
+
+// Create creates a new lead note
+func (r *LeadNoteRepository) Create(ctx context.Context, note *models.LeadNote) error {
+	if note.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if note.LeadID == "" {
+		return fmt.Errorf("%w: lead_id is required", repositories.ErrInvalidInput)
+	}
+
+	if note.ID == "" {
+		note.ID = newID()
+	}
+
+	now := time.Now()
+	note.CreatedAt = now
+	note.UpdatedAt = now
+
+	if err := r.notes.insert(note.TenantID, note.ID, note); err != nil {
+		return fmt.Errorf("failed to create lead note: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves a lead note by ID
+func (r *LeadNoteRepository) Get(ctx context.Context, tenantID, id string) (*models.LeadNote, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	return r.notes.get(tenantID, id)
+}
+
+// Update updates specific fields of a lead note
+func (r *LeadNoteRepository) Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	updates["updated_at"] = time.Now()
+
+	if err := r.notes.update(tenantID, id, updates); err != nil {
+		return fmt.Errorf("failed to update lead note: %w", err)
+	}
+
+	return nil
+}
+
+// Delete deletes a lead note
+func (r *LeadNoteRepository) Delete(ctx context.Context, tenantID, id string) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	if err := r.notes.remove(tenantID, id); err != nil {
+		return fmt.Errorf("failed to delete lead note: %w", err)
+	}
+
+	return nil
+}
+
+// ListByLead retrieves the notes of a lead
+func (r *LeadNoteRepository) ListByLead(ctx context.Context, tenantID, leadID string, opts repositories.PaginationOptions) ([]*models.LeadNote, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if leadID == "" {
+		return nil, fmt.Errorf("%w: lead_id is required", repositories.ErrInvalidInput)
+	}
+
+	if opts.Limit == 0 {
+		opts = repositories.DefaultPaginationOptions()
+	}
+
+	notes := r.notes.find(tenantID, func(n *models.LeadNote) bool {
+		return n.LeadID == leadID
+	})
+	return paginate(notes, opts), nil
+}
@@ -0,0 +1,290 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"cloud.google.com/go/firestore"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// LeadRepository is an in-memory repositories.LeadStore
+type LeadRepository struct {
+	leads *collection[models.Lead]
+}
+
+// NewLeadRepository creates a new in-memory lead repository
+func NewLeadRepository() *LeadRepository {
+	return &LeadRepository{
+		leads: newCollection[models.Lead](),
+	}
+}
+
+// Create creates a new lead
+func (r *LeadRepository) Create(ctx context.Context, lead *models.Lead) error {
+	if lead.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if lead.PropertyID == "" {
+		return fmt.Errorf("%w: property_id is required", repositories.ErrInvalidInput)
+	}
+	if !lead.ConsentGiven {
+		return fmt.Errorf("%w: consent_given must be true to create a lead", repositories.ErrInvalidInput)
+	}
+
+	if lead.ID == "" {
+		lead.ID = newID()
+	}
+
+	now := time.Now()
+	lead.CreatedAt = now
+	lead.UpdatedAt = now
+
+	if lead.ConsentDate.IsZero() {
+		lead.ConsentDate = now
+	}
+
+	if err := r.leads.insert(lead.TenantID, lead.ID, lead); err != nil {
+		return fmt.Errorf("failed to create lead: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves a lead by ID
+func (r *LeadRepository) Get(ctx context.Context, tenantID, id string) (*models.Lead, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	return r.leads.get(tenantID, id)
+}
+
+// Update updates a lead
+func (r *LeadRepository) Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if id == "" {
+		return fmt.Errorf("%w: lead ID is required", repositories.ErrInvalidInput)
+	}
+
+	updates["updated_at"] = time.Now()
+
+	if err := r.leads.update(tenantID, id, updates); err != nil {
+		return fmt.Errorf("failed to update lead: %w", err)
+	}
+
+	return nil
+}
+
+// Delete deletes a lead
+func (r *LeadRepository) Delete(ctx context.Context, tenantID, id string) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	if err := r.leads.remove(tenantID, id); err != nil {
+		return fmt.Errorf("failed to delete lead: %w", err)
+	}
+	return nil
+}
+
+// List retrieves leads for a tenant with optional filters and pagination
+func (r *LeadRepository) List(ctx context.Context, tenantID string, filters *repositories.LeadFilters, opts repositories.PaginationOptions) ([]*models.Lead, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	if opts.Limit == 0 {
+		opts = repositories.DefaultPaginationOptions()
+	}
+
+	leads := r.leads.find(tenantID, func(l *models.Lead) bool {
+		return matchLeadFilters(l, filters)
+	})
+
+	return paginate(leads, opts), nil
+}
+
+// ListByProperty retrieves all leads for a property
+func (r *LeadRepository) ListByProperty(ctx context.Context, tenantID, propertyID string, opts repositories.PaginationOptions) ([]*models.Lead, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if propertyID == "" {
+		return nil, fmt.Errorf("%w: property_id is required", repositories.ErrInvalidInput)
+	}
+
+	return r.List(ctx, tenantID, &repositories.LeadFilters{PropertyID: propertyID}, opts)
+}
+
+// ListByStatus retrieves leads by status
+func (r *LeadRepository) ListByStatus(ctx context.Context, tenantID string, status models.LeadStatus, opts repositories.PaginationOptions) ([]*models.Lead, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	return r.List(ctx, tenantID, &repositories.LeadFilters{Status: &status}, opts)
+}
+
+// ListByChannel retrieves leads by channel
+func (r *LeadRepository) ListByChannel(ctx context.Context, tenantID string, channel models.LeadChannel, opts repositories.PaginationOptions) ([]*models.Lead, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	return r.List(ctx, tenantID, &repositories.LeadFilters{Channel: &channel}, opts)
+}
+
+// GetByEmail retrieves a lead by email within a property context
+func (r *LeadRepository) GetByEmail(ctx context.Context, tenantID, propertyID, email string) (*models.Lead, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if propertyID == "" {
+		return nil, fmt.Errorf("%w: property_id is required", repositories.ErrInvalidInput)
+	}
+	if email == "" {
+		return nil, fmt.Errorf("%w: email is required", repositories.ErrInvalidInput)
+	}
+
+	return r.leads.first(tenantID, func(l *models.Lead) bool {
+		return l.PropertyID == propertyID && l.Email == email
+	})
+}
+
+// GetByPhone retrieves a lead by phone within a property context
+func (r *LeadRepository) GetByPhone(ctx context.Context, tenantID, propertyID, phone string) (*models.Lead, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if propertyID == "" {
+		return nil, fmt.Errorf("%w: property_id is required", repositories.ErrInvalidInput)
+	}
+	if phone == "" {
+		return nil, fmt.Errorf("%w: phone is required", repositories.ErrInvalidInput)
+	}
+
+	return r.leads.first(tenantID, func(l *models.Lead) bool {
+		return l.PropertyID == propertyID && l.Phone == phone
+	})
+}
+
+// ListWithRevokedConsent retrieves leads with revoked consent
+func (r *LeadRepository) ListWithRevokedConsent(ctx context.Context, tenantID string, opts repositories.PaginationOptions) ([]*models.Lead, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	if opts.Limit == 0 {
+		opts = repositories.DefaultPaginationOptions()
+	}
+
+	leads := r.leads.find(tenantID, func(l *models.Lead) bool {
+		return l.ConsentRevoked && !l.IsAnonymized
+	})
+
+	return paginate(leads, opts), nil
+}
+
+// CountOpenByBroker counts the leads assigned to a broker that are still open
+func (r *LeadRepository) CountOpenByBroker(ctx context.Context, tenantID, brokerID string) (int, error) {
+	if tenantID == "" {
+		return 0, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if brokerID == "" {
+		return 0, fmt.Errorf("%w: broker_id is required", repositories.ErrInvalidInput)
+	}
+
+	leads := r.leads.find(tenantID, func(l *models.Lead) bool {
+		return l.AssignedBrokerID == brokerID && slices.Contains(models.OpenLeadStatuses, l.Status)
+	})
+
+	return len(leads), nil
+}
+
+// ListOverdueResponses retrieves leads still waiting for their first
+// response whose target passed by dueBy, oldest target first
+func (r *LeadRepository) ListOverdueResponses(ctx context.Context, tenantID string, dueBy time.Time, limit int) ([]*models.Lead, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	leads := r.leads.find(tenantID, func(l *models.Lead) bool {
+		return l.SLAStatus == models.LeadSLAStatusPending && l.ResponseDueAt != nil && !l.ResponseDueAt.After(dueBy)
+	})
+
+	return paginate(leads, repositories.PaginationOptions{OrderBy: "response_due_at", Direction: firestore.Asc, Limit: limit}), nil
+}
+
+// RevokeConsent marks a lead's consent as revoked
+func (r *LeadRepository) RevokeConsent(ctx context.Context, tenantID, id string) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if id == "" {
+		return fmt.Errorf("%w: lead ID is required", repositories.ErrInvalidInput)
+	}
+
+	now := time.Now()
+	return r.Update(ctx, tenantID, id, map[string]interface{}{
+		"consent_revoked": true,
+		"revoked_at":      now,
+	})
+}
+
+// Anonymize anonymizes a lead's personal data (LGPD compliance)
+func (r *LeadRepository) Anonymize(ctx context.Context, tenantID, id string, reason string) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if id == "" {
+		return fmt.Errorf("%w: lead ID is required", repositories.ErrInvalidInput)
+	}
+
+	now := time.Now()
+	return r.Update(ctx, tenantID, id, map[string]interface{}{
+		"name":                 "ANONYMIZED",
+		"email":                "",
+		"phone":                "",
+		"message":              "",
+		"consent_ip":           "",
+		"is_anonymized":        true,
+		"anonymized_at":        now,
+		"anonymization_reason": reason,
+	})
+}
+
+// matchLeadFilters reports whether a lead satisfies every set filter
+func matchLeadFilters(l *models.Lead, filters *repositories.LeadFilters) bool {
+	if filters == nil {
+		return true
+	}
+	if filters.PropertyID != "" && l.PropertyID != filters.PropertyID {
+		return false
+	}
+	if filters.ContactID != "" && l.ContactID != filters.ContactID {
+		return false
+	}
+	if filters.AssignedBrokerID != "" && l.AssignedBrokerID != filters.AssignedBrokerID {
+		return false
+	}
+	if filters.Status != nil && l.Status != *filters.Status {
+		return false
+	}
+	if filters.Channel != nil && l.Channel != *filters.Channel {
+		return false
+	}
+	if filters.CreatedFrom != nil && l.CreatedAt.Before(*filters.CreatedFrom) {
+		return false
+	}
+	if filters.CreatedTo != nil && !l.CreatedAt.Before(*filters.CreatedTo) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,36 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// LeadRoutingCursorRepository is an in-memory repositories.LeadRoutingCursorStore
+type LeadRoutingCursorRepository struct {
+	mu    sync.Mutex
+	turns map[string]int64
+}
+
+// NewLeadRoutingCursorRepository creates a new in-memory lead routing cursor repository
+func NewLeadRoutingCursorRepository() *LeadRoutingCursorRepository {
+	return &LeadRoutingCursorRepository{
+		turns: make(map[string]int64),
+	}
+}
+
+// NextTurn claims the next round-robin turn of a tenant (0 on the first call)
+func (r *LeadRoutingCursorRepository) NextTurn(ctx context.Context, tenantID string) (int64, error) {
+	if tenantID == "" {
+		return 0, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	turn := r.turns[tenantID]
+	r.turns[tenantID] = turn + 1
+	return turn, nil
+}
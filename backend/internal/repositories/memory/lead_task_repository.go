@@ -0,0 +1,125 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// LeadTaskRepository is an in-memory repositories.LeadTaskStore
+type LeadTaskRepository struct {
+	tasks *collection[models.LeadTask]
+}
+
+// NewLeadTaskRepository creates a new in-memory lead task repository
+func NewLeadTaskRepository() *LeadTaskRepository {
+	return &LeadTaskRepository{
+		tasks: newCollection[models.LeadTask](),
+	}
+}
+
+// Create creates a new lead task
+func (r *LeadTaskRepository) Create(ctx context.Context, task *models.LeadTask) error {
+	if task.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if task.LeadID == "" {
+		return fmt.Errorf("%w: lead_id is required", repositories.ErrInvalidInput)
+	}
+
+	if task.ID == "" {
+		task.ID = newID()
+	}
+
+	now := time.Now()
+	task.CreatedAt = now
+	task.UpdatedAt = now
+
+	if err := r.tasks.insert(task.TenantID, task.ID, task); err != nil {
+		return fmt.Errorf("failed to create lead task: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves a lead task by ID
+func (r *LeadTaskRepository) Get(ctx context.Context, tenantID, id string) (*models.LeadTask, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	return r.tasks.get(tenantID, id)
+}
+
+// Update updates specific fields of a lead task
+func (r *LeadTaskRepository) Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	updates["updated_at"] = time.Now()
+
+	if err := r.tasks.update(tenantID, id, updates); err != nil {
+		return fmt.Errorf("failed to update lead task: %w", err)
+	}
+
+	return nil
+}
+
+// List retrieves lead tasks with filters, by due date (soonest first) unless
+// opts sets another order
+func (r *LeadTaskRepository) List(ctx context.Context, tenantID string, filters *repositories.LeadTaskFilters, opts repositories.PaginationOptions) ([]*models.LeadTask, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	if opts.Limit == 0 {
+		opts.Limit = repositories.DefaultPaginationOptions().Limit
+	}
+	if opts.OrderBy == "" {
+		opts.OrderBy = "due_at"
+		opts.Direction = firestore.Asc
+	}
+
+	tasks := r.tasks.find(tenantID, func(t *models.LeadTask) bool {
+		if filters == nil {
+			return true
+		}
+		if filters.LeadID != "" && t.LeadID != filters.LeadID {
+			return false
+		}
+		if filters.AssignedTo != "" && t.AssignedTo != filters.AssignedTo {
+			return false
+		}
+		if filters.Status != nil && t.Status != *filters.Status {
+			return false
+		}
+		if filters.DueBefore != nil && !t.DueAt.Before(*filters.DueBefore) {
+			return false
+		}
+		return true
+	})
+	return paginate(tasks, opts), nil
+}
+
+// ListDueReminders retrieves the tasks whose pending reminder is due by dueBy,
+// oldest first
+func (r *LeadTaskRepository) ListDueReminders(ctx context.Context, tenantID string, dueBy time.Time, limit int) ([]*models.LeadTask, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	tasks := r.tasks.find(tenantID, func(t *models.LeadTask) bool {
+		return t.ReminderStatus == models.LeadTaskReminderPending && t.RemindAt != nil && !t.RemindAt.After(dueBy)
+	})
+	return paginate(tasks, repositories.PaginationOptions{
+		OrderBy:   "remind_at",
+		Direction: firestore.Asc,
+		Limit:     limit,
+	}), nil
+}
@@ -0,0 +1,196 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// ListingRepository is an in-memory repositories.ListingStore
+type ListingRepository struct {
+	listings *collection[models.Listing]
+}
+
+// NewListingRepository creates a new in-memory listing repository
+func NewListingRepository() *ListingRepository {
+	return &ListingRepository{
+		listings: newCollection[models.Listing](),
+	}
+}
+
+// Create creates a new listing
+func (r *ListingRepository) Create(ctx context.Context, listing *models.Listing) error {
+	if listing.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if listing.PropertyID == "" {
+		return fmt.Errorf("%w: property_id is required", repositories.ErrInvalidInput)
+	}
+	if listing.BrokerID == "" {
+		return fmt.Errorf("%w: broker_id is required", repositories.ErrInvalidInput)
+	}
+
+	if listing.ID == "" {
+		listing.ID = newID()
+	}
+
+	now := time.Now()
+	listing.CreatedAt = now
+	listing.UpdatedAt = now
+
+	if err := r.listings.insert(listing.TenantID, listing.ID, listing); err != nil {
+		return fmt.Errorf("failed to create listing: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves a listing by ID
+func (r *ListingRepository) Get(ctx context.Context, tenantID, id string) (*models.Listing, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	return r.listings.get(tenantID, id)
+}
+
+// GetMany retrieves listings of a tenant by ID. The result is aligned with
+// ids; listings not found are nil.
+func (r *ListingRepository) GetMany(ctx context.Context, tenantID string, ids []string) ([]*models.Listing, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	listings := make([]*models.Listing, len(ids))
+	for i, id := range ids {
+		listing, err := r.listings.get(tenantID, id)
+		if errors.Is(err, repositories.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		listings[i] = listing
+	}
+	return listings, nil
+}
+
+// Update updates a listing
+func (r *ListingRepository) Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if id == "" {
+		return fmt.Errorf("%w: listing ID is required", repositories.ErrInvalidInput)
+	}
+
+	updates["updated_at"] = time.Now()
+
+	if err := r.listings.update(tenantID, id, updates); err != nil {
+		return fmt.Errorf("failed to update listing: %w", err)
+	}
+
+	return nil
+}
+
+// Delete deletes a listing
+func (r *ListingRepository) Delete(ctx context.Context, tenantID, id string) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	if err := r.listings.remove(tenantID, id); err != nil {
+		return fmt.Errorf("failed to delete listing: %w", err)
+	}
+	return nil
+}
+
+// List retrieves listings for a tenant with pagination
+func (r *ListingRepository) List(ctx context.Context, tenantID string, opts repositories.PaginationOptions) ([]*models.Listing, error) {
+	return r.list(tenantID, opts, nil)
+}
+
+// ListByProperty retrieves all listings for a property
+func (r *ListingRepository) ListByProperty(ctx context.Context, tenantID, propertyID string, opts repositories.PaginationOptions) ([]*models.Listing, error) {
+	if propertyID == "" {
+		return nil, fmt.Errorf("%w: property_id is required", repositories.ErrInvalidInput)
+	}
+
+	return r.list(tenantID, opts, func(l *models.Listing) bool {
+		return l.PropertyID == propertyID
+	})
+}
+
+// ListByBroker retrieves all listings for a broker
+func (r *ListingRepository) ListByBroker(ctx context.Context, tenantID, brokerID string, opts repositories.PaginationOptions) ([]*models.Listing, error) {
+	if brokerID == "" {
+		return nil, fmt.Errorf("%w: broker_id is required", repositories.ErrInvalidInput)
+	}
+
+	return r.list(tenantID, opts, func(l *models.Listing) bool {
+		return l.BrokerID == brokerID
+	})
+}
+
+// ListActive retrieves all active listings for a tenant
+func (r *ListingRepository) ListActive(ctx context.Context, tenantID string, opts repositories.PaginationOptions) ([]*models.Listing, error) {
+	return r.list(tenantID, opts, func(l *models.Listing) bool {
+		return l.IsActive
+	})
+}
+
+// GetCanonicalForProperty retrieves the canonical listing for a property
+func (r *ListingRepository) GetCanonicalForProperty(ctx context.Context, tenantID, propertyID string) (*models.Listing, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if propertyID == "" {
+		return nil, fmt.Errorf("%w: property_id is required", repositories.ErrInvalidInput)
+	}
+
+	return r.listings.first(tenantID, func(l *models.Listing) bool {
+		return l.PropertyID == propertyID && l.IsCanonical
+	})
+}
+
+// UnsetCanonicalForProperty unsets the canonical flag for all listings of a property
+func (r *ListingRepository) UnsetCanonicalForProperty(ctx context.Context, tenantID, propertyID string) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if propertyID == "" {
+		return fmt.Errorf("%w: property_id is required", repositories.ErrInvalidInput)
+	}
+
+	canonical := r.listings.find(tenantID, func(l *models.Listing) bool {
+		return l.PropertyID == propertyID && l.IsCanonical
+	})
+
+	for _, listing := range canonical {
+		if err := r.listings.update(tenantID, listing.ID, map[string]interface{}{
+			"is_canonical": false,
+			"updated_at":   time.Now(),
+		}); err != nil {
+			return fmt.Errorf("failed to commit batch update: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// list applies the tenant check, default pagination and match to the collection
+func (r *ListingRepository) list(tenantID string, opts repositories.PaginationOptions, match func(*models.Listing) bool) ([]*models.Listing, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	if opts.Limit == 0 {
+		opts = repositories.DefaultPaginationOptions()
+	}
+
+	return paginate(r.listings.find(tenantID, match), opts), nil
+}
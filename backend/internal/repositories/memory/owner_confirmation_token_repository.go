@@ -0,0 +1,113 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// OwnerConfirmationTokenRepository is an in-memory repositories.OwnerConfirmationTokenStore
+type OwnerConfirmationTokenRepository struct {
+	tokens *collection[models.OwnerConfirmationToken]
+}
+
+// NewOwnerConfirmationTokenRepository creates a new in-memory owner confirmation token repository
+func NewOwnerConfirmationTokenRepository() *OwnerConfirmationTokenRepository {
+	return &OwnerConfirmationTokenRepository{
+		tokens: newCollection[models.OwnerConfirmationToken](),
+	}
+}
+
+// Create creates a new owner confirmation token (the ID is always generated)
+func (r *OwnerConfirmationTokenRepository) Create(ctx context.Context, token *models.OwnerConfirmationToken) error {
+	if token.TenantID == "" {
+		return fmt.Errorf("tenant_id is required")
+	}
+	if token.PropertyID == "" {
+		return fmt.Errorf("property_id is required")
+	}
+	if token.TokenHash == "" {
+		return fmt.Errorf("token_hash is required")
+	}
+
+	token.CreatedAt = time.Now()
+	token.ID = newID()
+
+	if err := r.tokens.insert(token.TenantID, token.ID, token); err != nil {
+		return fmt.Errorf("failed to create owner confirmation token: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves an owner confirmation token by ID
+func (r *OwnerConfirmationTokenRepository) Get(ctx context.Context, tenantID, tokenID string) (*models.OwnerConfirmationToken, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+	if tokenID == "" {
+		return nil, fmt.Errorf("token_id is required")
+	}
+
+	return r.tokens.get(tenantID, tokenID)
+}
+
+// GetByTokenHash retrieves an owner confirmation token by its hash
+func (r *OwnerConfirmationTokenRepository) GetByTokenHash(ctx context.Context, tenantID, tokenHash string) (*models.OwnerConfirmationToken, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+	if tokenHash == "" {
+		return nil, fmt.Errorf("token_hash is required")
+	}
+
+	return r.tokens.first(tenantID, func(t *models.OwnerConfirmationToken) bool {
+		return t.TokenHash == tokenHash
+	})
+}
+
+// Update updates an owner confirmation token
+func (r *OwnerConfirmationTokenRepository) Update(ctx context.Context, tenantID, tokenID string, updates map[string]interface{}) error {
+	if tenantID == "" {
+		return fmt.Errorf("tenant_id is required")
+	}
+	if tokenID == "" {
+		return fmt.Errorf("token_id is required")
+	}
+
+	if err := r.tokens.update(tenantID, tokenID, updates); err != nil {
+		return fmt.Errorf("failed to update owner confirmation token: %w", err)
+	}
+
+	return nil
+}
+
+// ListByProperty lists all confirmation tokens for a specific property, newest first
+func (r *OwnerConfirmationTokenRepository) ListByProperty(ctx context.Context, tenantID, propertyID string, opts *repositories.PaginationOptions) ([]*models.OwnerConfirmationToken, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+	if propertyID == "" {
+		return nil, fmt.Errorf("property_id is required")
+	}
+
+	if opts == nil {
+		defaultOpts := repositories.DefaultPaginationOptions()
+		opts = &defaultOpts
+	}
+
+	tokens := r.tokens.find(tenantID, func(t *models.OwnerConfirmationToken) bool {
+		return t.PropertyID == propertyID
+	})
+
+	return paginate(tokens, repositories.PaginationOptions{
+		Limit:     opts.Limit,
+		OrderBy:   "created_at",
+		Direction: firestore.Desc,
+	}), nil
+}
@@ -0,0 +1,142 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// OwnerRepository is an in-memory repositories.OwnerStore
+type OwnerRepository struct {
+	owners *collection[models.Owner]
+}
+
+// NewOwnerRepository creates a new in-memory owner repository
+func NewOwnerRepository() *OwnerRepository {
+	return &OwnerRepository{
+		owners: newCollection[models.Owner](),
+	}
+}
+
+// Create creates a new owner
+func (r *OwnerRepository) Create(ctx context.Context, owner *models.Owner) error {
+	if owner.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	if owner.ID == "" {
+		owner.ID = newID()
+	}
+
+	now := time.Now()
+	owner.CreatedAt = now
+	owner.UpdatedAt = now
+
+	if err := r.owners.insert(owner.TenantID, owner.ID, owner); err != nil {
+		return fmt.Errorf("failed to create owner: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves an owner by ID
+func (r *OwnerRepository) Get(ctx context.Context, tenantID, id string) (*models.Owner, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	return r.owners.get(tenantID, id)
+}
+
+// GetByEmail retrieves an owner by email
+func (r *OwnerRepository) GetByEmail(ctx context.Context, tenantID, email string) (*models.Owner, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if email == "" {
+		return nil, fmt.Errorf("%w: email is required", repositories.ErrInvalidInput)
+	}
+
+	return r.owners.first(tenantID, func(o *models.Owner) bool {
+		return o.Email == email
+	})
+}
+
+// GetByDocument retrieves an owner by CPF/CNPJ
+func (r *OwnerRepository) GetByDocument(ctx context.Context, tenantID, document string) (*models.Owner, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if document == "" {
+		return nil, fmt.Errorf("%w: document is required", repositories.ErrInvalidInput)
+	}
+
+	return r.owners.first(tenantID, func(o *models.Owner) bool {
+		return o.Document == document
+	})
+}
+
+// Update updates an owner
+func (r *OwnerRepository) Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if id == "" {
+		return fmt.Errorf("%w: owner ID is required", repositories.ErrInvalidInput)
+	}
+
+	updates["updated_at"] = time.Now()
+
+	if err := r.owners.update(tenantID, id, updates); err != nil {
+		return fmt.Errorf("failed to update owner: %w", err)
+	}
+
+	return nil
+}
+
+// Delete deletes an owner
+func (r *OwnerRepository) Delete(ctx context.Context, tenantID, id string) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	if err := r.owners.remove(tenantID, id); err != nil {
+		return fmt.Errorf("failed to delete owner: %w", err)
+	}
+	return nil
+}
+
+// List retrieves owners for a tenant with pagination
+func (r *OwnerRepository) List(ctx context.Context, tenantID string, opts repositories.PaginationOptions) ([]*models.Owner, error) {
+	return r.list(tenantID, opts, nil)
+}
+
+// ListByStatus retrieves owners by status
+func (r *OwnerRepository) ListByStatus(ctx context.Context, tenantID string, status models.OwnerStatus, opts repositories.PaginationOptions) ([]*models.Owner, error) {
+	return r.list(tenantID, opts, func(o *models.Owner) bool {
+		return o.OwnerStatus == status
+	})
+}
+
+// ListWithoutConsent retrieves owners that have not given consent
+func (r *OwnerRepository) ListWithoutConsent(ctx context.Context, tenantID string, opts repositories.PaginationOptions) ([]*models.Owner, error) {
+	return r.list(tenantID, opts, func(o *models.Owner) bool {
+		return !o.ConsentGiven && !o.IsAnonymized
+	})
+}
+
+// list applies the tenant check, default pagination and match to the collection
+func (r *OwnerRepository) list(tenantID string, opts repositories.PaginationOptions, match func(*models.Owner) bool) ([]*models.Owner, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	if opts.Limit == 0 {
+		opts = repositories.DefaultPaginationOptions()
+	}
+
+	return paginate(r.owners.find(tenantID, match), opts), nil
+}
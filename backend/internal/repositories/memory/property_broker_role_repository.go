@@ -0,0 +1,218 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// PropertyBrokerRoleRepository is an in-memory repositories.PropertyBrokerRoleStore
+type PropertyBrokerRoleRepository struct {
+	roles *collection[models.PropertyBrokerRole]
+}
+
+// NewPropertyBrokerRoleRepository creates a new in-memory property broker role repository
+func NewPropertyBrokerRoleRepository() *PropertyBrokerRoleRepository {
+	return &PropertyBrokerRoleRepository{
+		roles: newCollection[models.PropertyBrokerRole](),
+	}
+}
+
+// Create creates a new property broker role
+func (r *PropertyBrokerRoleRepository) Create(ctx context.Context, role *models.PropertyBrokerRole) error {
+	if role.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if role.PropertyID == "" {
+		return fmt.Errorf("%w: property_id is required", repositories.ErrInvalidInput)
+	}
+	if role.BrokerID == "" {
+		return fmt.Errorf("%w: broker_id is required", repositories.ErrInvalidInput)
+	}
+
+	if role.ID == "" {
+		role.ID = newID()
+	}
+
+	now := time.Now()
+	role.CreatedAt = now
+	role.UpdatedAt = now
+
+	// If setting as primary, unset other primary roles for the property
+	if role.IsPrimary {
+		if err := r.UnsetPrimaryForProperty(ctx, role.TenantID, role.PropertyID); err != nil {
+			return fmt.Errorf("failed to unset existing primary roles: %w", err)
+		}
+	}
+
+	if err := r.roles.insert(role.TenantID, role.ID, role); err != nil {
+		return fmt.Errorf("failed to create property broker role: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves a property broker role by ID
+func (r *PropertyBrokerRoleRepository) Get(ctx context.Context, tenantID, id string) (*models.PropertyBrokerRole, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	return r.roles.get(tenantID, id)
+}
+
+// GetByPropertyAndBroker retrieves a specific role for a property-broker pair
+func (r *PropertyBrokerRoleRepository) GetByPropertyAndBroker(ctx context.Context, tenantID, propertyID, brokerID string, roleType models.BrokerPropertyRole) (*models.PropertyBrokerRole, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if propertyID == "" {
+		return nil, fmt.Errorf("%w: property_id is required", repositories.ErrInvalidInput)
+	}
+	if brokerID == "" {
+		return nil, fmt.Errorf("%w: broker_id is required", repositories.ErrInvalidInput)
+	}
+
+	return r.roles.first(tenantID, func(role *models.PropertyBrokerRole) bool {
+		return role.PropertyID == propertyID && role.BrokerID == brokerID && role.Role == roleType
+	})
+}
+
+// Update updates a property broker role
+func (r *PropertyBrokerRoleRepository) Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if id == "" {
+		return fmt.Errorf("%w: role ID is required", repositories.ErrInvalidInput)
+	}
+
+	if isPrimary, ok := updates["is_primary"].(bool); ok && isPrimary {
+		currentRole, err := r.Get(ctx, tenantID, id)
+		if err != nil {
+			return fmt.Errorf("failed to get current role: %w", err)
+		}
+
+		if err := r.UnsetPrimaryForProperty(ctx, tenantID, currentRole.PropertyID); err != nil {
+			return fmt.Errorf("failed to unset existing primary roles: %w", err)
+		}
+	}
+
+	updates["updated_at"] = time.Now()
+
+	if err := r.roles.update(tenantID, id, updates); err != nil {
+		return fmt.Errorf("failed to update property broker role: %w", err)
+	}
+
+	return nil
+}
+
+// Delete deletes a property broker role
+func (r *PropertyBrokerRoleRepository) Delete(ctx context.Context, tenantID, id string) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	if err := r.roles.remove(tenantID, id); err != nil {
+		return fmt.Errorf("failed to delete property broker role: %w", err)
+	}
+	return nil
+}
+
+// ListByProperty retrieves all broker roles for a property
+func (r *PropertyBrokerRoleRepository) ListByProperty(ctx context.Context, tenantID, propertyID string, opts repositories.PaginationOptions) ([]*models.PropertyBrokerRole, error) {
+	if propertyID == "" {
+		return nil, fmt.Errorf("%w: property_id is required", repositories.ErrInvalidInput)
+	}
+
+	return r.list(tenantID, opts, func(role *models.PropertyBrokerRole) bool {
+		return role.PropertyID == propertyID
+	})
+}
+
+// ListByBroker retrieves all property roles for a broker
+func (r *PropertyBrokerRoleRepository) ListByBroker(ctx context.Context, tenantID, brokerID string, opts repositories.PaginationOptions) ([]*models.PropertyBrokerRole, error) {
+	if brokerID == "" {
+		return nil, fmt.Errorf("%w: broker_id is required", repositories.ErrInvalidInput)
+	}
+
+	return r.list(tenantID, opts, func(role *models.PropertyBrokerRole) bool {
+		return role.BrokerID == brokerID
+	})
+}
+
+// ListByRole retrieves all property broker roles of a given type
+func (r *PropertyBrokerRoleRepository) ListByRole(ctx context.Context, tenantID string, roleType models.BrokerPropertyRole, opts repositories.PaginationOptions) ([]*models.PropertyBrokerRole, error) {
+	return r.list(tenantID, opts, func(role *models.PropertyBrokerRole) bool {
+		return role.Role == roleType
+	})
+}
+
+// GetOriginatingBroker retrieves the originating broker role for a property
+func (r *PropertyBrokerRoleRepository) GetOriginatingBroker(ctx context.Context, tenantID, propertyID string) (*models.PropertyBrokerRole, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if propertyID == "" {
+		return nil, fmt.Errorf("%w: property_id is required", repositories.ErrInvalidInput)
+	}
+
+	return r.roles.first(tenantID, func(role *models.PropertyBrokerRole) bool {
+		return role.PropertyID == propertyID && role.Role == models.BrokerPropertyRoleOriginating
+	})
+}
+
+// GetPrimaryBroker retrieves the primary broker role for a property
+func (r *PropertyBrokerRoleRepository) GetPrimaryBroker(ctx context.Context, tenantID, propertyID string) (*models.PropertyBrokerRole, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if propertyID == "" {
+		return nil, fmt.Errorf("%w: property_id is required", repositories.ErrInvalidInput)
+	}
+
+	return r.roles.first(tenantID, func(role *models.PropertyBrokerRole) bool {
+		return role.PropertyID == propertyID && role.IsPrimary
+	})
+}
+
+// UnsetPrimaryForProperty unsets the primary flag for all roles of a property
+func (r *PropertyBrokerRoleRepository) UnsetPrimaryForProperty(ctx context.Context, tenantID, propertyID string) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if propertyID == "" {
+		return fmt.Errorf("%w: property_id is required", repositories.ErrInvalidInput)
+	}
+
+	primary := r.roles.find(tenantID, func(role *models.PropertyBrokerRole) bool {
+		return role.PropertyID == propertyID && role.IsPrimary
+	})
+
+	for _, role := range primary {
+		if err := r.roles.update(tenantID, role.ID, map[string]interface{}{
+			"is_primary": false,
+			"updated_at": time.Now(),
+		}); err != nil {
+			return fmt.Errorf("failed to commit batch update: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// list applies the tenant check, default pagination and match to the collection
+func (r *PropertyBrokerRoleRepository) list(tenantID string, opts repositories.PaginationOptions, match func(*models.PropertyBrokerRole) bool) ([]*models.PropertyBrokerRole, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	if opts.Limit == 0 {
+		opts = repositories.DefaultPaginationOptions()
+	}
+
+	return paginate(r.roles.find(tenantID, match), opts), nil
+}
@@ -0,0 +1,72 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// PropertyHistoryRepository is an in-memory repositories.PropertyHistoryStore
+type PropertyHistoryRepository struct {
+	entries *collection[models.PropertyHistoryEntry]
+}
+
+// NewPropertyHistoryRepository creates a new in-memory property history repository
+func NewPropertyHistoryRepository() *PropertyHistoryRepository {
+	return &PropertyHistoryRepository{
+		entries: newCollection[models.PropertyHistoryEntry](),
+	}
+}
+
+// Create appends a history entry to the property (the ID is always generated)
+func (r *PropertyHistoryRepository) Create(ctx context.Context, entry *models.PropertyHistoryEntry) error {
+	if entry.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if entry.PropertyID == "" {
+		return fmt.Errorf("%w: property_id is required", repositories.ErrInvalidInput)
+	}
+	if entry.Field == "" {
+		return fmt.Errorf("%w: field is required", repositories.ErrInvalidInput)
+	}
+
+	if entry.ChangedAt.IsZero() {
+		entry.ChangedAt = time.Now()
+	}
+	entry.ID = newID()
+
+	if err := r.entries.insert(entry.TenantID, entry.ID, entry); err != nil {
+		return fmt.Errorf("failed to create property history entry: %w", err)
+	}
+
+	return nil
+}
+
+// ListByProperty retrieves the history of a property, newest first by default
+func (r *PropertyHistoryRepository) ListByProperty(ctx context.Context, tenantID, propertyID string, opts repositories.PaginationOptions) ([]*models.PropertyHistoryEntry, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if propertyID == "" {
+		return nil, fmt.Errorf("%w: property_id is required", repositories.ErrInvalidInput)
+	}
+
+	if opts.Limit == 0 {
+		opts.Limit = repositories.DefaultPaginationOptions().Limit
+	}
+	if opts.OrderBy == "" {
+		opts.OrderBy = "changed_at"
+		opts.Direction = firestore.Desc
+	}
+
+	entries := r.entries.find(tenantID, func(e *models.PropertyHistoryEntry) bool {
+		return e.PropertyID == propertyID
+	})
+
+	return paginate(entries, opts), nil
+}
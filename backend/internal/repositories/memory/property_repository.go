@@ -0,0 +1,325 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/utils"
+)
+
+// PropertyRepository is an in-memory repositories.PropertyStore
+type PropertyRepository struct {
+	properties *collection[models.Property]
+}
+
+// NewPropertyRepository creates a new in-memory property repository
+func NewPropertyRepository() *PropertyRepository {
+	return &PropertyRepository{
+		properties: newCollection[models.Property](),
+	}
+}
+
+// Create creates a new property
+func (r *PropertyRepository) Create(ctx context.Context, property *models.Property) error {
+	if property.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	if property.ID == "" {
+		property.ID = newID()
+	}
+
+	now := time.Now()
+	property.CreatedAt = now
+	property.UpdatedAt = now
+
+	if err := r.properties.insert(property.TenantID, property.ID, property); err != nil {
+		return fmt.Errorf("failed to create property: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves a property by ID
+// If tenantID is empty, skips tenant verification (used for public endpoints)
+func (r *PropertyRepository) Get(ctx context.Context, tenantID, id string) (*models.Property, error) {
+	if tenantID == "" {
+		return r.properties.getAnyTenant(id)
+	}
+	return r.properties.get(tenantID, id)
+}
+
+// GetMany retrieves properties by ID across tenants. The result is aligned
+// with ids; properties not found are nil.
+func (r *PropertyRepository) GetMany(ctx context.Context, ids []string) ([]*models.Property, error) {
+	properties := make([]*models.Property, len(ids))
+	for i, id := range ids {
+		property, err := r.properties.getAnyTenant(id)
+		if errors.Is(err, repositories.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		properties[i] = property
+	}
+	return properties, nil
+}
+
+// GetBySlug retrieves a property by slug
+func (r *PropertyRepository) GetBySlug(ctx context.Context, tenantID, slug string) (*models.Property, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if slug == "" {
+		return nil, fmt.Errorf("%w: slug is required", repositories.ErrInvalidInput)
+	}
+
+	return r.properties.first(tenantID, func(p *models.Property) bool {
+		return p.Slug == slug
+	})
+}
+
+// GetBySlugPublic retrieves a PUBLIC property by slug (across all tenants)
+func (r *PropertyRepository) GetBySlugPublic(ctx context.Context, slug string) (*models.Property, error) {
+	if slug == "" {
+		return nil, fmt.Errorf("%w: slug is required", repositories.ErrInvalidInput)
+	}
+
+	return r.properties.first("", func(p *models.Property) bool {
+		return p.Slug == slug && isPubliclyListed(p)
+	})
+}
+
+// GetByExternalID retrieves a property by external source and ID
+func (r *PropertyRepository) GetByExternalID(ctx context.Context, tenantID, externalSource, externalID string) (*models.Property, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if externalSource == "" || externalID == "" {
+		return nil, fmt.Errorf("%w: external_source and external_id are required", repositories.ErrInvalidInput)
+	}
+
+	return r.properties.first(tenantID, func(p *models.Property) bool {
+		return p.ExternalSource == externalSource && p.ExternalID == externalID
+	})
+}
+
+// Update updates a property
+func (r *PropertyRepository) Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if id == "" {
+		return fmt.Errorf("%w: property ID is required", repositories.ErrInvalidInput)
+	}
+
+	updates["updated_at"] = time.Now()
+
+	if err := r.properties.update(tenantID, id, updates); err != nil {
+		return fmt.Errorf("failed to update property: %w", err)
+	}
+
+	return nil
+}
+
+// Delete deletes a property
+func (r *PropertyRepository) Delete(ctx context.Context, tenantID, id string) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	if err := r.properties.remove(tenantID, id); err != nil {
+		return fmt.Errorf("failed to delete property: %w", err)
+	}
+	return nil
+}
+
+// List retrieves properties for a tenant with optional filters and pagination
+func (r *PropertyRepository) List(ctx context.Context, tenantID string, filters *repositories.PropertyFilters, opts repositories.PaginationOptions) ([]*models.Property, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	if opts.Limit == 0 {
+		opts = repositories.DefaultPaginationOptions()
+	}
+
+	properties := r.properties.find(tenantID, func(p *models.Property) bool {
+		return filters.Matches(p)
+	})
+
+	return paginate(properties, opts), nil
+}
+
+// ListAllPublic retrieves PUBLIC properties across ALL tenants with optional filters and pagination
+func (r *PropertyRepository) ListAllPublic(ctx context.Context, filters *repositories.PropertyFilters, opts repositories.PaginationOptions) ([]*models.Property, error) {
+	if opts.Limit == 0 {
+		opts = repositories.DefaultPaginationOptions()
+	}
+
+	// Status and visibility are always forced, mirroring the Firestore repository
+	var publicFilters *repositories.PropertyFilters
+	if filters != nil {
+		f := *filters
+		f.Status = nil
+		f.Visibility = nil
+		publicFilters = &f
+	}
+
+	properties := r.properties.find("", func(p *models.Property) bool {
+		return isPubliclyListed(p) && publicFilters.Matches(p)
+	})
+
+	return paginate(properties, opts), nil
+}
+
+// Count returns the total number of properties for a tenant with optional filters
+func (r *PropertyRepository) Count(ctx context.Context, tenantID string, filters *repositories.PropertyFilters) (int, error) {
+	if tenantID == "" {
+		return 0, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	properties := r.properties.find(tenantID, func(p *models.Property) bool {
+		return filters.Matches(p)
+	})
+
+	return len(properties), nil
+}
+
+// ListByOwner retrieves all properties for an owner
+func (r *PropertyRepository) ListByOwner(ctx context.Context, tenantID, ownerID string, opts repositories.PaginationOptions) ([]*models.Property, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if ownerID == "" {
+		return nil, fmt.Errorf("%w: owner_id is required", repositories.ErrInvalidInput)
+	}
+
+	return r.List(ctx, tenantID, &repositories.PropertyFilters{OwnerID: ownerID}, opts)
+}
+
+// ListByCaptador retrieves all properties for a captador (broker)
+func (r *PropertyRepository) ListByCaptador(ctx context.Context, tenantID, captadorID string, opts repositories.PaginationOptions) ([]*models.Property, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if captadorID == "" {
+		return nil, fmt.Errorf("%w: captador_id is required", repositories.ErrInvalidInput)
+	}
+
+	if opts.Limit == 0 {
+		opts = repositories.DefaultPaginationOptions()
+	}
+
+	properties := r.properties.find(tenantID, func(p *models.Property) bool {
+		return p.CaptadorID == captadorID
+	})
+
+	return paginate(properties, opts), nil
+}
+
+// ListByStatus retrieves properties by status
+func (r *PropertyRepository) ListByStatus(ctx context.Context, tenantID string, status models.PropertyStatus, opts repositories.PaginationOptions) ([]*models.Property, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	return r.List(ctx, tenantID, &repositories.PropertyFilters{Status: &status}, opts)
+}
+
+// ListByVisibility retrieves properties by visibility level
+func (r *PropertyRepository) ListByVisibility(ctx context.Context, tenantID string, visibility models.PropertyVisibility, opts repositories.PaginationOptions) ([]*models.Property, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	return r.List(ctx, tenantID, &repositories.PropertyFilters{Visibility: &visibility}, opts)
+}
+
+// ListPossibleDuplicates retrieves properties marked as possible duplicates
+func (r *PropertyRepository) ListPossibleDuplicates(ctx context.Context, tenantID string, opts repositories.PaginationOptions) ([]*models.Property, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	if opts.Limit == 0 {
+		opts = repositories.DefaultPaginationOptions()
+	}
+
+	properties := r.properties.find(tenantID, func(p *models.Property) bool {
+		return p.PossibleDuplicate
+	})
+
+	return paginate(properties, opts), nil
+}
+
+// ListByFingerprint retrieves properties by fingerprint (for deduplication)
+func (r *PropertyRepository) ListByFingerprint(ctx context.Context, tenantID, fingerprint string) ([]*models.Property, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if fingerprint == "" {
+		return nil, fmt.Errorf("%w: fingerprint is required", repositories.ErrInvalidInput)
+	}
+
+	return r.properties.find(tenantID, func(p *models.Property) bool {
+		return p.Fingerprint == fingerprint
+	}), nil
+}
+
+// SearchByLocation searches properties by city, neighborhood, or both
+func (r *PropertyRepository) SearchByLocation(ctx context.Context, tenantID, city, neighborhood string, opts repositories.PaginationOptions) ([]*models.Property, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if city == "" && neighborhood == "" {
+		return nil, fmt.Errorf("%w: at least one of city or neighborhood is required", repositories.ErrInvalidInput)
+	}
+
+	filters := &repositories.PropertyFilters{
+		City:         city,
+		Neighborhood: neighborhood,
+	}
+
+	return r.List(ctx, tenantID, filters, opts)
+}
+
+// isPubliclyListed reports whether a property may be shown on the public portal
+func isPubliclyListed(p *models.Property) bool {
+	return p.Visibility == models.PropertyVisibilityPublic && p.Status == models.PropertyStatusAvailable
+}
+
+// ListPublicInBounds retrieves PUBLIC geolocated properties inside a map bounding box (cross-tenant)
+func (r *PropertyRepository) ListPublicInBounds(ctx context.Context, bounds utils.GeoBounds, filters *repositories.PropertyFilters, limit int) ([]*models.Property, error) {
+	if err := bounds.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", repositories.ErrInvalidInput, err)
+	}
+
+	candidates := r.properties.find("", isPubliclyListed)
+
+	return repositories.SelectPropertiesInBounds(candidates, bounds, filters, limit), nil
+}
+
+// ListPublicNearby retrieves PUBLIC geolocated properties within radiusKm of a point (cross-tenant),
+// nearest first
+func (r *PropertyRepository) ListPublicNearby(ctx context.Context, lat, lng, radiusKm float64, filters *repositories.PropertyFilters, limit int) ([]*models.Property, error) {
+	if err := utils.ValidateCoordinates(lat, lng); err != nil {
+		return nil, fmt.Errorf("%w: %v", repositories.ErrInvalidInput, err)
+	}
+	if radiusKm <= 0 {
+		return nil, fmt.Errorf("%w: radius must be positive", repositories.ErrInvalidInput)
+	}
+	bounds := utils.BoundsAroundPoint(lat, lng, radiusKm)
+	if err := bounds.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", repositories.ErrInvalidInput, err)
+	}
+
+	candidates := r.properties.find("", isPubliclyListed)
+
+	return repositories.SelectPropertiesNearby(candidates, lat, lng, radiusKm, filters, limit), nil
+}
@@ -0,0 +1,235 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/utils"
+)
+
+func TestPropertyRepository_TenantIsolation(t *testing.T) {
+	ctx := context.Background()
+	repo := NewPropertyRepository()
+
+	property := &models.Property{TenantID: "tenant-a", City: "Curitiba"}
+	require.NoError(t, repo.Create(ctx, property))
+	assert.NotEmpty(t, property.ID)
+
+	_, err := repo.Get(ctx, "tenant-b", property.ID)
+	assert.True(t, errors.Is(err, repositories.ErrNotFound))
+
+	got, err := repo.Get(ctx, "tenant-a", property.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Curitiba", got.City)
+
+	list, err := repo.List(ctx, "tenant-b", nil, repositories.PaginationOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, list)
+}
+
+func TestPropertyRepository_UpdateReturnsCopies(t *testing.T) {
+	ctx := context.Background()
+	repo := NewPropertyRepository()
+
+	property := &models.Property{TenantID: "tenant-a", City: "Curitiba"}
+	require.NoError(t, repo.Create(ctx, property))
+
+	// Mutating a returned document must not change the stored one
+	got, err := repo.Get(ctx, "tenant-a", property.ID)
+	require.NoError(t, err)
+	got.City = "Londrina"
+
+	got, err = repo.Get(ctx, "tenant-a", property.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Curitiba", got.City)
+
+	require.NoError(t, repo.Update(ctx, "tenant-a", property.ID, map[string]interface{}{
+		"city":   "Maringá",
+		"status": models.PropertyStatusUnavailable,
+	}))
+
+	got, err = repo.Get(ctx, "tenant-a", property.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Maringá", got.City)
+	assert.Equal(t, models.PropertyStatusUnavailable, got.Status)
+
+	err = repo.Update(ctx, "tenant-a", "missing", map[string]interface{}{"city": "x"})
+	assert.True(t, errors.Is(err, repositories.ErrNotFound))
+}
+
+func TestPropertyRepository_CopiesNestedFields(t *testing.T) {
+	ctx := context.Background()
+	repo := NewPropertyRepository()
+
+	property := &models.Property{
+		TenantID:        "tenant-a",
+		RentalInfo:      &models.RentalInfo{MonthlyRent: 2500},
+		ContractHistory: []string{"contract-1"},
+	}
+	require.NoError(t, repo.Create(ctx, property))
+
+	// Neither the created document nor a returned one shares nested values
+	// with the stored document
+	property.RentalInfo.MonthlyRent = 1
+	got, err := repo.Get(ctx, "tenant-a", property.ID)
+	require.NoError(t, err)
+	got.ContractHistory[0] = "contract-2"
+
+	list, err := repo.List(ctx, "tenant-a", nil, repositories.PaginationOptions{})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	list[0].RentalInfo.MonthlyRent = 2
+
+	// A failed update leaves the nested fields untouched
+	err = repo.Update(ctx, "tenant-a", property.ID, map[string]interface{}{
+		"rental_info.monthly_rent": 3000.0,
+		"unknown_field":            true,
+	})
+	require.Error(t, err)
+
+	got, err = repo.Get(ctx, "tenant-a", property.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2500.0, got.RentalInfo.MonthlyRent)
+	assert.Equal(t, []string{"contract-1"}, got.ContractHistory)
+}
+
+func TestPropertyRepository_ListFiltersAndPagination(t *testing.T) {
+	ctx := context.Background()
+	repo := NewPropertyRepository()
+
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, city := range []string{"Curitiba", "Curitiba", "Curitiba", "Londrina"} {
+		property := &models.Property{
+			TenantID: "tenant-a",
+			City:     city,
+			Status:   models.PropertyStatusAvailable,
+		}
+		require.NoError(t, repo.Create(ctx, property))
+
+		// Create stamps created_at with time.Now; spread them out for ordering
+		require.NoError(t, repo.Update(ctx, "tenant-a", property.ID, map[string]interface{}{
+			"created_at": base.Add(time.Duration(i) * time.Hour),
+		}))
+	}
+
+	filtered, err := repo.List(ctx, "tenant-a", &repositories.PropertyFilters{City: "Curitiba"}, repositories.PaginationOptions{})
+	require.NoError(t, err)
+	assert.Len(t, filtered, 3)
+
+	count, err := repo.Count(ctx, "tenant-a", &repositories.PropertyFilters{City: "Londrina"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	page, err := repo.List(ctx, "tenant-a", nil, repositories.PaginationOptions{
+		Limit:     2,
+		Offset:    1,
+		OrderBy:   "created_at",
+		Direction: firestore.Asc,
+	})
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	assert.Equal(t, base.Add(1*time.Hour), page[0].CreatedAt)
+	assert.Equal(t, base.Add(2*time.Hour), page[1].CreatedAt)
+}
+
+func TestPropertyRepository_CursorPagination(t *testing.T) {
+	ctx := context.Background()
+	repo := NewPropertyRepository()
+
+	// Repeated prices: pages must neither skip nor repeat documents on ties
+	prices := []float64{300000, 150000, 300000, 450000, 150000, 300000, 200000}
+	for _, price := range prices {
+		require.NoError(t, repo.Create(ctx, &models.Property{TenantID: "tenant-a", PriceAmount: price}))
+	}
+
+	opts := repositories.PaginationOptions{Limit: 3, OrderBy: "price_amount", Direction: firestore.Desc}
+	seen := make(map[string]bool)
+	var got []float64
+
+	for pages := 0; ; pages++ {
+		require.Less(t, pages, len(prices), "pagination did not terminate")
+
+		page, err := repo.List(ctx, "tenant-a", nil, opts)
+		require.NoError(t, err)
+		for _, p := range page {
+			assert.False(t, seen[p.ID], "property %s returned twice", p.ID)
+			seen[p.ID] = true
+			got = append(got, p.PriceAmount)
+		}
+
+		cursor := repositories.NextCursor(page, opts)
+		if cursor == "" {
+			break
+		}
+
+		next := repositories.PaginationOptions{Limit: 3}
+		require.NoError(t, next.ApplyCursor(cursor))
+		assert.Equal(t, "price_amount", next.OrderBy)
+		assert.Equal(t, firestore.Desc, next.Direction)
+		opts = next
+	}
+
+	assert.Equal(t, []float64{450000, 300000, 300000, 300000, 200000, 150000, 150000}, got)
+}
+
+func TestPaginationOptions_ApplyCursorRejectsGarbage(t *testing.T) {
+	opts := repositories.DefaultPaginationOptions()
+	err := opts.ApplyCursor("not-a-cursor")
+	assert.True(t, errors.Is(err, repositories.ErrInvalidInput))
+}
+
+func TestPropertyRepository_RequiresTenant(t *testing.T) {
+	repo := NewPropertyRepository()
+
+	err := repo.Create(context.Background(), &models.Property{})
+	assert.True(t, errors.Is(err, repositories.ErrInvalidInput))
+}
+
+func TestPropertyRepository_GeoSearch(t *testing.T) {
+	ctx := context.Background()
+	repo := NewPropertyRepository()
+
+	newPublic := func(tenantID string, lat, lng float64) *models.Property {
+		p := &models.Property{
+			TenantID:   tenantID,
+			City:       "São Paulo",
+			Status:     models.PropertyStatusAvailable,
+			Visibility: models.PropertyVisibilityPublic,
+		}
+		p.SetCoordinates(lat, lng)
+		require.NoError(t, repo.Create(ctx, p))
+		return p
+	}
+
+	near := newPublic("tenant-a", -23.5510, -46.6340)    // ~0.1 km from the center
+	farther := newPublic("tenant-b", -23.5600, -46.6500) // ~2 km from the center
+	newPublic("tenant-a", -22.9111, -43.1763)            // Rio de Janeiro
+
+	private := &models.Property{TenantID: "tenant-a", Status: models.PropertyStatusAvailable, Visibility: models.PropertyVisibilityPrivate}
+	private.SetCoordinates(-23.5505, -46.6333)
+	require.NoError(t, repo.Create(ctx, private))
+
+	nearby, err := repo.ListPublicNearby(ctx, -23.5505, -46.6333, 5, nil, 0)
+	require.NoError(t, err)
+	require.Len(t, nearby, 2)
+	assert.Equal(t, near.ID, nearby[0].ID)
+	assert.Equal(t, farther.ID, nearby[1].ID)
+	require.NotNil(t, nearby[0].DistanceKm)
+	assert.Less(t, *nearby[0].DistanceKm, *nearby[1].DistanceKm)
+
+	inBox, err := repo.ListPublicInBounds(ctx, utils.GeoBounds{MinLat: -23.555, MinLng: -46.64, MaxLat: -23.55, MaxLng: -46.63}, nil, 0)
+	require.NoError(t, err)
+	require.Len(t, inBox, 1)
+	assert.Equal(t, near.ID, inBox[0].ID)
+
+	_, err = repo.ListPublicNearby(ctx, -23.5505, -46.6333, 0, nil, 0)
+	assert.True(t, errors.Is(err, repositories.ErrInvalidInput))
+}
@@ -0,0 +1,116 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"cloud.google.com/go/firestore"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// ProposalRepository is an in-memory repositories.ProposalStore
+type ProposalRepository struct {
+	proposals *collection[models.Proposal]
+}
+
+// NewProposalRepository creates a new in-memory proposal repository
+func NewProposalRepository() *ProposalRepository {
+	return &ProposalRepository{
+		proposals: newCollection[models.Proposal](),
+	}
+}
+
+// Create creates a new proposal
+func (r *ProposalRepository) Create(ctx context.Context, proposal *models.Proposal) error {
+	if proposal.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if proposal.PropertyID == "" {
+		return fmt.Errorf("%w: property_id is required", repositories.ErrInvalidInput)
+	}
+
+	if proposal.ID == "" {
+		proposal.ID = newID()
+	}
+
+	now := time.Now()
+	proposal.CreatedAt = now
+	proposal.UpdatedAt = now
+
+	if err := r.proposals.insert(proposal.TenantID, proposal.ID, proposal); err != nil {
+		return fmt.Errorf("failed to create proposal: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves a proposal by ID
+func (r *ProposalRepository) Get(ctx context.Context, tenantID, id string) (*models.Proposal, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	return r.proposals.get(tenantID, id)
+}
+
+// Update updates specific fields of a proposal
+func (r *ProposalRepository) Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	updates["updated_at"] = time.Now()
+
+	if err := r.proposals.update(tenantID, id, updates); err != nil {
+		return fmt.Errorf("failed to update proposal: %w", err)
+	}
+
+	return nil
+}
+
+// List retrieves proposals with filters, most recent first (by answer deadline
+// with ValidBefore) unless opts sets another order
+func (r *ProposalRepository) List(ctx context.Context, tenantID string, filters *repositories.ProposalFilters, opts repositories.PaginationOptions) ([]*models.Proposal, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	if opts.Limit == 0 {
+		opts.Limit = repositories.DefaultPaginationOptions().Limit
+	}
+	if opts.OrderBy == "" {
+		opts.OrderBy = "created_at"
+		opts.Direction = firestore.Desc
+		if filters != nil && filters.ValidBefore != nil {
+			opts.OrderBy = "terms.valid_until"
+			opts.Direction = firestore.Asc
+		}
+	}
+
+	proposals := r.proposals.find(tenantID, func(p *models.Proposal) bool {
+		if filters == nil {
+			return true
+		}
+		if filters.BrokerID != "" && p.BrokerID != filters.BrokerID {
+			return false
+		}
+		if filters.PropertyID != "" && p.PropertyID != filters.PropertyID {
+			return false
+		}
+		if filters.LeadID != "" && p.LeadID != filters.LeadID {
+			return false
+		}
+		if len(filters.Statuses) > 0 && !slices.Contains(filters.Statuses, p.Status) {
+			return false
+		}
+		if filters.ValidBefore != nil && !p.Terms.ValidUntil.Before(*filters.ValidBefore) {
+			return false
+		}
+		return true
+	})
+	return paginate(proposals, opts), nil
+}
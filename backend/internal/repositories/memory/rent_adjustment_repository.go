@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"cloud.google.com/go/firestore"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// RentAdjustmentRepository is an in-memory repositories.RentAdjustmentStore
+type RentAdjustmentRepository struct {
+	adjustments *collection[models.RentAdjustment]
+}
+
+// NewRentAdjustmentRepository creates a new in-memory rent adjustment repository
+func NewRentAdjustmentRepository() *RentAdjustmentRepository {
+	return &RentAdjustmentRepository{
+		adjustments: newCollection[models.RentAdjustment](),
+	}
+}
+
+// Create creates a new rent adjustment
+func (r *RentAdjustmentRepository) Create(ctx context.Context, adjustment *models.RentAdjustment) error {
+	if adjustment.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if adjustment.ContractID == "" {
+		return fmt.Errorf("%w: contract_id is required", repositories.ErrInvalidInput)
+	}
+
+	if adjustment.ID == "" {
+		adjustment.ID = newID()
+	}
+
+	now := time.Now()
+	adjustment.CreatedAt = now
+	adjustment.UpdatedAt = now
+
+	if err := r.adjustments.insert(adjustment.TenantID, adjustment.ID, adjustment); err != nil {
+		return fmt.Errorf("failed to create rent adjustment: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves a rent adjustment by ID
+func (r *RentAdjustmentRepository) Get(ctx context.Context, tenantID, id string) (*models.RentAdjustment, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	return r.adjustments.get(tenantID, id)
+}
+
+// Update updates specific fields of a rent adjustment
+func (r *RentAdjustmentRepository) Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	updates["updated_at"] = time.Now()
+
+	if err := r.adjustments.update(tenantID, id, updates); err != nil {
+		return fmt.Errorf("failed to update rent adjustment: %w", err)
+	}
+
+	return nil
+}
+
+// List retrieves rent adjustments with filters, latest effective month first
+// unless opts sets another order
+func (r *RentAdjustmentRepository) List(ctx context.Context, tenantID string, filters *repositories.RentAdjustmentFilters, opts repositories.PaginationOptions) ([]*models.RentAdjustment, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	if opts.Limit == 0 {
+		opts.Limit = repositories.DefaultPaginationOptions().Limit
+	}
+	if opts.OrderBy == "" {
+		opts.OrderBy = "effective_period"
+		opts.Direction = firestore.Desc
+	}
+
+	adjustments := r.adjustments.find(tenantID, func(a *models.RentAdjustment) bool {
+		if filters == nil {
+			return true
+		}
+		if filters.ContractID != "" && a.ContractID != filters.ContractID {
+			return false
+		}
+		if filters.PropertyID != "" && a.PropertyID != filters.PropertyID {
+			return false
+		}
+		if filters.EffectivePeriod != "" && a.EffectivePeriod != filters.EffectivePeriod {
+			return false
+		}
+		if len(filters.Statuses) > 0 && !slices.Contains(filters.Statuses, a.Status) {
+			return false
+		}
+		return true
+	})
+	return paginate(adjustments, opts), nil
+}
@@ -0,0 +1,164 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/firestore"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// RentInvoiceRepository is an in-memory repositories.RentInvoiceStore
+type RentInvoiceRepository struct {
+	invoices *collection[models.RentInvoice]
+	mu       sync.Mutex // Serializes the checks of UpdateIf
+}
+
+// NewRentInvoiceRepository creates a new in-memory rent invoice repository
+func NewRentInvoiceRepository() *RentInvoiceRepository {
+	return &RentInvoiceRepository{
+		invoices: newCollection[models.RentInvoice](),
+	}
+}
+
+// Create creates a new rent invoice; ErrAlreadyExists when the contract
+// already has the invoice of the month
+func (r *RentInvoiceRepository) Create(ctx context.Context, invoice *models.RentInvoice) error {
+	if invoice.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if invoice.ContractID == "" || invoice.Period == "" {
+		return fmt.Errorf("%w: contract_id and period are required", repositories.ErrInvalidInput)
+	}
+
+	invoice.ID = models.RentInvoiceID(invoice.ContractID, invoice.Period)
+	if invoice.Reference == "" {
+		invoice.Reference = invoice.ID
+	}
+
+	now := time.Now()
+	invoice.CreatedAt = now
+	invoice.UpdatedAt = now
+
+	if err := r.invoices.insert(invoice.TenantID, invoice.ID, invoice); err != nil {
+		return fmt.Errorf("failed to create rent invoice: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves a rent invoice by ID
+func (r *RentInvoiceRepository) Get(ctx context.Context, tenantID, id string) (*models.RentInvoice, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	return r.invoices.get(tenantID, id)
+}
+
+// GetByReference retrieves the rent invoice charged with the given external
+// reference, in any tenant
+func (r *RentInvoiceRepository) GetByReference(ctx context.Context, reference string) (*models.RentInvoice, error) {
+	if reference == "" {
+		return nil, fmt.Errorf("%w: reference is required", repositories.ErrInvalidInput)
+	}
+
+	return r.invoices.first("", func(invoice *models.RentInvoice) bool {
+		return invoice.Reference == reference
+	})
+}
+
+// Update updates specific fields of a rent invoice
+func (r *RentInvoiceRepository) Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	updates["updated_at"] = time.Now()
+
+	if err := r.invoices.update(tenantID, id, updates); err != nil {
+		return fmt.Errorf("failed to update rent invoice: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateIf updates a rent invoice only when check accepts its current
+// state; false when check refused the invoice
+func (r *RentInvoiceRepository) UpdateIf(ctx context.Context, tenantID, id string, check func(invoice *models.RentInvoice) bool, updates map[string]interface{}) (bool, error) {
+	if tenantID == "" {
+		return false, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	invoice, err := r.invoices.get(tenantID, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to update rent invoice: %w", err)
+	}
+	if !check(invoice) {
+		return false, nil
+	}
+
+	updates["updated_at"] = time.Now()
+
+	if err := r.invoices.update(tenantID, id, updates); err != nil {
+		return false, fmt.Errorf("failed to update rent invoice: %w", err)
+	}
+
+	return true, nil
+}
+
+// List retrieves rent invoices with filters, latest due date first (oldest
+// first with DueBefore) unless opts sets another order
+func (r *RentInvoiceRepository) List(ctx context.Context, tenantID string, filters *repositories.RentInvoiceFilters, opts repositories.PaginationOptions) ([]*models.RentInvoice, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	if opts.Limit == 0 {
+		opts.Limit = repositories.DefaultPaginationOptions().Limit
+	}
+	if opts.OrderBy == "" {
+		opts.OrderBy = "due_date"
+		opts.Direction = firestore.Desc
+		if filters != nil && filters.DueBefore != nil {
+			opts.Direction = firestore.Asc
+		}
+	}
+
+	invoices := r.invoices.find(tenantID, func(i *models.RentInvoice) bool {
+		if filters == nil {
+			return true
+		}
+		if filters.ContractID != "" && i.ContractID != filters.ContractID {
+			return false
+		}
+		if filters.PropertyID != "" && i.PropertyID != filters.PropertyID {
+			return false
+		}
+		if filters.OwnerID != "" && i.OwnerID != filters.OwnerID {
+			return false
+		}
+		if filters.Period != "" && i.Period != filters.Period {
+			return false
+		}
+		if len(filters.Statuses) > 0 && !slices.Contains(filters.Statuses, i.Status) {
+			return false
+		}
+		if filters.DueBefore != nil && !i.DueDate.Before(*filters.DueBefore) {
+			return false
+		}
+		if filters.PaymentPeriod != "" && !slices.Contains(i.PaymentPeriods, filters.PaymentPeriod) {
+			return false
+		}
+		return true
+	})
+	return paginate(invoices, opts), nil
+}
@@ -0,0 +1,113 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"cloud.google.com/go/firestore"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// RentalContractRepository is an in-memory repositories.RentalContractStore
+type RentalContractRepository struct {
+	contracts *collection[models.RentalContract]
+}
+
+// NewRentalContractRepository creates a new in-memory rental contract repository
+func NewRentalContractRepository() *RentalContractRepository {
+	return &RentalContractRepository{
+		contracts: newCollection[models.RentalContract](),
+	}
+}
+
+// Create creates a new rental contract
+func (r *RentalContractRepository) Create(ctx context.Context, contract *models.RentalContract) error {
+	if contract.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if contract.PropertyID == "" {
+		return fmt.Errorf("%w: property_id is required", repositories.ErrInvalidInput)
+	}
+
+	if contract.ID == "" {
+		contract.ID = newID()
+	}
+
+	now := time.Now()
+	contract.CreatedAt = now
+	contract.UpdatedAt = now
+
+	if err := r.contracts.insert(contract.TenantID, contract.ID, contract); err != nil {
+		return fmt.Errorf("failed to create rental contract: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves a rental contract by ID
+func (r *RentalContractRepository) Get(ctx context.Context, tenantID, id string) (*models.RentalContract, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	return r.contracts.get(tenantID, id)
+}
+
+// Update updates specific fields of a rental contract
+func (r *RentalContractRepository) Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	updates["updated_at"] = time.Now()
+
+	if err := r.contracts.update(tenantID, id, updates); err != nil {
+		return fmt.Errorf("failed to update rental contract: %w", err)
+	}
+
+	return nil
+}
+
+// List retrieves rental contracts with filters, latest start first (by end
+// date with EndsBefore) unless opts sets another order
+func (r *RentalContractRepository) List(ctx context.Context, tenantID string, filters *repositories.RentalContractFilters, opts repositories.PaginationOptions) ([]*models.RentalContract, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	if opts.Limit == 0 {
+		opts.Limit = repositories.DefaultPaginationOptions().Limit
+	}
+	if opts.OrderBy == "" {
+		opts.OrderBy = "start_date"
+		opts.Direction = firestore.Desc
+		if filters != nil && filters.EndsBefore != nil {
+			opts.OrderBy = "end_date"
+			opts.Direction = firestore.Asc
+		}
+	}
+
+	contracts := r.contracts.find(tenantID, func(c *models.RentalContract) bool {
+		if filters == nil {
+			return true
+		}
+		if filters.PropertyID != "" && c.PropertyID != filters.PropertyID {
+			return false
+		}
+		if filters.OwnerID != "" && c.OwnerID != filters.OwnerID {
+			return false
+		}
+		if len(filters.Statuses) > 0 && !slices.Contains(filters.Statuses, c.Status) {
+			return false
+		}
+		if filters.EndsBefore != nil && !c.EndDate.Before(*filters.EndsBefore) {
+			return false
+		}
+		return true
+	})
+	return paginate(contracts, opts), nil
+}
@@ -0,0 +1,183 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// ScheduledConfirmationRepository is an in-memory repositories.ScheduledConfirmationStore
+type ScheduledConfirmationRepository struct {
+	confirmations *collection[models.ScheduledConfirmation]
+}
+
+// NewScheduledConfirmationRepository creates a new in-memory scheduled confirmation repository
+func NewScheduledConfirmationRepository() *ScheduledConfirmationRepository {
+	return &ScheduledConfirmationRepository{
+		confirmations: newCollection[models.ScheduledConfirmation](),
+	}
+}
+
+// Create creates a new scheduled confirmation
+func (r *ScheduledConfirmationRepository) Create(ctx context.Context, sc *models.ScheduledConfirmation) error {
+	if sc.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	if sc.ID == "" {
+		sc.ID = newID()
+	}
+
+	now := time.Now()
+	sc.CreatedAt = now
+	sc.UpdatedAt = now
+
+	if err := r.confirmations.insert(sc.TenantID, sc.ID, sc); err != nil {
+		return fmt.Errorf("failed to create scheduled confirmation: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves a scheduled confirmation by ID
+func (r *ScheduledConfirmationRepository) Get(ctx context.Context, tenantID, id string) (*models.ScheduledConfirmation, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	return r.confirmations.get(tenantID, id)
+}
+
+// Update persists the delivery and response fields of a scheduled confirmation
+func (r *ScheduledConfirmationRepository) Update(ctx context.Context, sc *models.ScheduledConfirmation) error {
+	if sc.TenantID == "" || sc.ID == "" {
+		return fmt.Errorf("%w: tenant_id and id are required", repositories.ErrInvalidInput)
+	}
+
+	sc.UpdatedAt = time.Now()
+
+	updates := map[string]interface{}{
+		"status":              sc.Status,
+		"sent_at":             sc.SentAt,
+		"delivery_method":     sc.DeliveryMethod,
+		"delivery_status":     sc.DeliveryStatus,
+		"delivery_error":      sc.DeliveryError,
+		"delivery_attempts":   sc.DeliveryAttempts,
+		"provider_message_id": sc.ProviderMessageID,
+		"delivered_at":        sc.DeliveredAt,
+		"reminder_step":       sc.ReminderStep,
+		"next_reminder_at":    sc.NextReminderAt,
+		"reminders":           sc.Reminders,
+		"responded_at":        sc.RespondedAt,
+		"response":            sc.Response,
+		"updated_at":          sc.UpdatedAt,
+	}
+
+	if err := r.confirmations.update(sc.TenantID, sc.ID, updates); err != nil {
+		return fmt.Errorf("failed to update scheduled confirmation: %w", err)
+	}
+
+	return nil
+}
+
+// GetPendingForDate retrieves all pending scheduled confirmations for a specific date
+func (r *ScheduledConfirmationRepository) GetPendingForDate(ctx context.Context, tenantID string, targetDate time.Time) ([]*models.ScheduledConfirmation, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	startOfDay := time.Date(targetDate.Year(), targetDate.Month(), targetDate.Day(), 0, 0, 0, 0, targetDate.Location())
+	endOfDay := startOfDay.Add(24 * time.Hour)
+
+	results := r.confirmations.find(tenantID, func(sc *models.ScheduledConfirmation) bool {
+		return sc.Status == models.ScheduledConfirmationStatusPending &&
+			!sc.ScheduledFor.Before(startOfDay) && sc.ScheduledFor.Before(endOfDay)
+	})
+
+	return paginate(results, repositories.PaginationOptions{OrderBy: "scheduled_for", Direction: firestore.Asc}), nil
+}
+
+// GetByPropertyAndMonth retrieves scheduled confirmations for a property in a specific month
+func (r *ScheduledConfirmationRepository) GetByPropertyAndMonth(ctx context.Context, tenantID, propertyID string, year int, month time.Month) ([]*models.ScheduledConfirmation, error) {
+	if tenantID == "" || propertyID == "" {
+		return nil, fmt.Errorf("%w: tenant_id and property_id are required", repositories.ErrInvalidInput)
+	}
+
+	startOfMonth := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	endOfMonth := startOfMonth.AddDate(0, 1, 0)
+
+	results := r.confirmations.find(tenantID, func(sc *models.ScheduledConfirmation) bool {
+		return sc.PropertyID == propertyID &&
+			!sc.ScheduledFor.Before(startOfMonth) && sc.ScheduledFor.Before(endOfMonth)
+	})
+
+	return paginate(results, repositories.PaginationOptions{OrderBy: "scheduled_for", Direction: firestore.Desc}), nil
+}
+
+// GetByTokenID retrieves the scheduled confirmation that sent a confirmation token
+func (r *ScheduledConfirmationRepository) GetByTokenID(ctx context.Context, tenantID, tokenID string) (*models.ScheduledConfirmation, error) {
+	if tenantID == "" || tokenID == "" {
+		return nil, fmt.Errorf("%w: tenant_id and token_id are required", repositories.ErrInvalidInput)
+	}
+
+	return r.confirmations.first(tenantID, func(sc *models.ScheduledConfirmation) bool {
+		return sc.TokenID == tokenID
+	})
+}
+
+// ListDueReminders retrieves the scheduled confirmations whose next follow-up
+// step is due at or before the given time
+func (r *ScheduledConfirmationRepository) ListDueReminders(ctx context.Context, tenantID string, dueBy time.Time) ([]*models.ScheduledConfirmation, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	results := r.confirmations.find(tenantID, func(sc *models.ScheduledConfirmation) bool {
+		return sc.NextReminderAt != nil && !sc.NextReminderAt.After(dueBy)
+	})
+
+	return paginate(results, repositories.PaginationOptions{OrderBy: "next_reminder_at", Direction: firestore.Asc}), nil
+}
+
+// ListByProperty retrieves the latest scheduled confirmations of a property, newest first
+func (r *ScheduledConfirmationRepository) ListByProperty(ctx context.Context, tenantID, propertyID string, limit int) ([]*models.ScheduledConfirmation, error) {
+	if tenantID == "" || propertyID == "" {
+		return nil, fmt.Errorf("%w: tenant_id and property_id are required", repositories.ErrInvalidInput)
+	}
+
+	results := r.confirmations.find(tenantID, func(sc *models.ScheduledConfirmation) bool {
+		return sc.PropertyID == propertyID
+	})
+
+	return paginate(results, repositories.PaginationOptions{OrderBy: "scheduled_for", Direction: firestore.Desc, Limit: limit}), nil
+}
+
+// GetByProviderMessageID retrieves the scheduled confirmation delivered as the
+// given provider message, across every tenant
+func (r *ScheduledConfirmationRepository) GetByProviderMessageID(ctx context.Context, providerMessageID string) (*models.ScheduledConfirmation, error) {
+	if providerMessageID == "" {
+		return nil, fmt.Errorf("%w: provider_message_id is required", repositories.ErrInvalidInput)
+	}
+
+	return r.confirmations.first("", func(sc *models.ScheduledConfirmation) bool {
+		return sc.ProviderMessageID == providerMessageID
+	})
+}
+
+// ListByTenant retrieves all scheduled confirmations for a tenant with optional status filter
+func (r *ScheduledConfirmationRepository) ListByTenant(ctx context.Context, tenantID string, status *models.ScheduledConfirmationStatus, limit int) ([]*models.ScheduledConfirmation, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	results := r.confirmations.find(tenantID, func(sc *models.ScheduledConfirmation) bool {
+		return status == nil || sc.Status == *status
+	})
+
+	return paginate(results, repositories.PaginationOptions{Limit: limit}), nil
+}
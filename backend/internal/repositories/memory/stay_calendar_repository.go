@@ -0,0 +1,82 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// StayCalendarRepository is an in-memory repositories.StayCalendarStore
+type StayCalendarRepository struct {
+	calendars *collection[models.StayCalendar]
+}
+
+// NewStayCalendarRepository creates a new in-memory stay calendar repository
+func NewStayCalendarRepository() *StayCalendarRepository {
+	return &StayCalendarRepository{
+		calendars: newCollection[models.StayCalendar](),
+	}
+}
+
+// Get retrieves the stay calendar of a property
+func (r *StayCalendarRepository) Get(ctx context.Context, tenantID, propertyID string) (*models.StayCalendar, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	return r.calendars.get(tenantID, propertyID)
+}
+
+// Save creates or replaces the stay calendar of a property
+func (r *StayCalendarRepository) Save(ctx context.Context, calendar *models.StayCalendar) error {
+	if calendar.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if calendar.PropertyID == "" {
+		return fmt.Errorf("%w: property_id is required", repositories.ErrInvalidInput)
+	}
+
+	now := time.Now()
+	if calendar.CreatedAt.IsZero() {
+		calendar.CreatedAt = now
+	}
+	calendar.UpdatedAt = now
+
+	if err := r.calendars.put(calendar.TenantID, calendar.PropertyID, calendar); err != nil {
+		return fmt.Errorf("failed to save stay calendar: %w", err)
+	}
+
+	return nil
+}
+
+// Update updates specific fields of a stay calendar
+func (r *StayCalendarRepository) Update(ctx context.Context, tenantID, propertyID string, updates map[string]interface{}) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	updates["updated_at"] = time.Now()
+
+	if err := r.calendars.update(tenantID, propertyID, updates); err != nil {
+		return fmt.Errorf("failed to update stay calendar: %w", err)
+	}
+
+	return nil
+}
+
+// List retrieves the stay calendars of a tenant
+func (r *StayCalendarRepository) List(ctx context.Context, tenantID string, opts repositories.PaginationOptions) ([]*models.StayCalendar, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	if opts.Limit == 0 {
+		opts.Limit = repositories.DefaultPaginationOptions().Limit
+	}
+
+	calendars := r.calendars.find(tenantID, func(*models.StayCalendar) bool { return true })
+	return paginate(calendars, opts), nil
+}
@@ -0,0 +1,333 @@
+// Package memory provides an in-memory implementation of every repository
+// interface declared in internal/repositories. It mirrors the validation and
+// tenant isolation rules of the Firestore repositories so the services can be
+// exercised offline (local development and unit tests).
+package memory
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"cloud.google.com/go/firestore"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// docKey identifies a document inside a collection. Subcollections under
+// tenants/{tenantId} and root collections carrying a tenant_id field are both
+// modelled by scoping every document to its tenant.
+type docKey struct {
+	tenantID string
+	id       string
+}
+
+// collection is a thread-safe set of documents of type T
+type collection[T any] struct {
+	mu   sync.RWMutex
+	docs map[docKey]*T
+}
+
+func newCollection[T any]() *collection[T] {
+	return &collection[T]{
+		docs: make(map[docKey]*T),
+	}
+}
+
+// insert stores a deep copy of doc, failing if the document already exists
+func (c *collection[T]) insert(tenantID, id string, doc *T) error {
+	if id == "" {
+		return fmt.Errorf("%w: document ID is required", repositories.ErrInvalidInput)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := docKey{tenantID: tenantID, id: id}
+	if _, exists := c.docs[key]; exists {
+		return repositories.ErrAlreadyExists
+	}
+
+	c.docs[key] = deepCopy(doc)
+	return nil
+}
+
+// put stores a deep copy of doc, replacing any existing document
+func (c *collection[T]) put(tenantID, id string, doc *T) error {
+	if id == "" {
+		return fmt.Errorf("%w: document ID is required", repositories.ErrInvalidInput)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.docs[docKey{tenantID: tenantID, id: id}] = deepCopy(doc)
+	return nil
+}
+
+// get returns a deep copy of the document
+func (c *collection[T]) get(tenantID, id string) (*T, error) {
+	if id == "" {
+		return nil, fmt.Errorf("%w: document ID is required", repositories.ErrInvalidInput)
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	doc, ok := c.docs[docKey{tenantID: tenantID, id: id}]
+	if !ok {
+		return nil, repositories.ErrNotFound
+	}
+
+	return deepCopy(doc), nil
+}
+
+// getAnyTenant returns a deep copy of the document regardless of its tenant.
+// Only root collections (properties) are looked up this way.
+func (c *collection[T]) getAnyTenant(id string) (*T, error) {
+	if id == "" {
+		return nil, fmt.Errorf("%w: document ID is required", repositories.ErrInvalidInput)
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for key, doc := range c.docs {
+		if key.id == id {
+			return deepCopy(doc), nil
+		}
+	}
+
+	return nil, repositories.ErrNotFound
+}
+
+// update applies Firestore-style field updates to the stored document
+func (c *collection[T]) update(tenantID, id string, updates map[string]interface{}) error {
+	if id == "" {
+		return fmt.Errorf("%w: document ID is required", repositories.ErrInvalidInput)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	doc, ok := c.docs[docKey{tenantID: tenantID, id: id}]
+	if !ok {
+		return repositories.ErrNotFound
+	}
+
+	// Work on a copy so a failed update leaves the document untouched, and
+	// store a copy of the result so it shares nothing with the update values
+	cp := deepCopy(doc)
+	if err := applyUpdates(cp, updates); err != nil {
+		return err
+	}
+
+	c.docs[docKey{tenantID: tenantID, id: id}] = deepCopy(cp)
+	return nil
+}
+
+// remove deletes the document
+func (c *collection[T]) remove(tenantID, id string) error {
+	if id == "" {
+		return fmt.Errorf("%w: document ID is required", repositories.ErrInvalidInput)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := docKey{tenantID: tenantID, id: id}
+	if _, ok := c.docs[key]; !ok {
+		return repositories.ErrNotFound
+	}
+
+	delete(c.docs, key)
+	return nil
+}
+
+// find returns deep copies of the documents of a tenant accepted by match, ordered
+// by document ID (Firestore's default order). An empty tenantID searches every
+// tenant and a nil match accepts every document.
+func (c *collection[T]) find(tenantID string, match func(*T) bool) []*T {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	keys := make([]docKey, 0, len(c.docs))
+	for key := range c.docs {
+		if tenantID != "" && key.tenantID != tenantID {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].id == keys[j].id {
+			return keys[i].tenantID < keys[j].tenantID
+		}
+		return keys[i].id < keys[j].id
+	})
+
+	results := make([]*T, 0, len(keys))
+	for _, key := range keys {
+		doc := c.docs[key]
+		if match != nil && !match(doc) {
+			continue
+		}
+		results = append(results, deepCopy(doc))
+	}
+
+	return results
+}
+
+// first returns the first document of a tenant accepted by match
+func (c *collection[T]) first(tenantID string, match func(*T) bool) (*T, error) {
+	docs := c.find(tenantID, match)
+	if len(docs) == 0 {
+		return nil, repositories.ErrNotFound
+	}
+	return docs[0], nil
+}
+
+// deepCopy returns a copy of doc that shares no pointers, slices, maps or
+// interface values with it, so callers can't change stored documents (or be
+// changed by them) through nested fields.
+func deepCopy[T any](doc *T) *T {
+	cp := new(T)
+	copyValue(reflect.ValueOf(cp).Elem(), reflect.ValueOf(doc).Elem())
+	return cp
+}
+
+// copyValue deep-copies src into dst, which must be settable. Unexported
+// struct fields (e.g. those of time.Time) are copied as they are.
+func copyValue(dst, src reflect.Value) {
+	switch src.Kind() {
+	case reflect.Ptr:
+		if src.IsNil() {
+			return
+		}
+		p := reflect.New(src.Type().Elem())
+		copyValue(p.Elem(), src.Elem())
+		dst.Set(p)
+	case reflect.Interface:
+		if src.IsNil() {
+			return
+		}
+		v := reflect.New(src.Elem().Type()).Elem()
+		copyValue(v, src.Elem())
+		dst.Set(v)
+	case reflect.Slice:
+		if src.IsNil() {
+			return
+		}
+		s := reflect.MakeSlice(src.Type(), src.Len(), src.Len())
+		for i := 0; i < src.Len(); i++ {
+			copyValue(s.Index(i), src.Index(i))
+		}
+		dst.Set(s)
+	case reflect.Array:
+		for i := 0; i < src.Len(); i++ {
+			copyValue(dst.Index(i), src.Index(i))
+		}
+	case reflect.Map:
+		if src.IsNil() {
+			return
+		}
+		m := reflect.MakeMapWithSize(src.Type(), src.Len())
+		iter := src.MapRange()
+		for iter.Next() {
+			v := reflect.New(src.Type().Elem()).Elem()
+			copyValue(v, iter.Value())
+			m.SetMapIndex(iter.Key(), v)
+		}
+		dst.Set(m)
+	case reflect.Struct:
+		dst.Set(src)
+		for i := 0; i < src.NumField(); i++ {
+			if dst.Field(i).CanSet() {
+				copyValue(dst.Field(i), src.Field(i))
+			}
+		}
+	default:
+		dst.Set(src)
+	}
+}
+
+// paginate orders docs and applies the cursor, offset and limit of opts the
+// same way BaseRepository.ApplyPagination does for Firestore queries.
+// Documents sharing a sort value are ordered by ID, like Firestore's implicit
+// __name__ ordering, which keeps keyset cursors (StartAfterID) stable.
+func paginate[T any](docs []*T, opts repositories.PaginationOptions) []*T {
+	if opts.OrderBy != "" {
+		desc := opts.Direction == firestore.Desc
+		sort.SliceStable(docs, func(i, j int) bool {
+			a, _ := fieldValue(docs[i], opts.OrderBy)
+			b, _ := fieldValue(docs[j], opts.OrderBy)
+			cmp := compareValues(a, b)
+			if cmp == 0 {
+				cmp = strings.Compare(docID(docs[i]), docID(docs[j]))
+			}
+			if desc {
+				return cmp > 0
+			}
+			return cmp < 0
+		})
+
+		if opts.StartAfter != nil || opts.StartAfterID != "" {
+			start := len(docs)
+			for i, doc := range docs {
+				v, _ := fieldValue(doc, opts.OrderBy)
+				cmp := compareValues(v, opts.StartAfter)
+				if cmp == 0 && opts.StartAfterID != "" {
+					cmp = strings.Compare(docID(doc), opts.StartAfterID)
+				}
+				if (desc && cmp < 0) || (!desc && cmp > 0) {
+					start = i
+					break
+				}
+			}
+			docs = docs[start:]
+		}
+	}
+
+	if opts.Offset > 0 {
+		if opts.Offset >= len(docs) {
+			return []*T{}
+		}
+		docs = docs[opts.Offset:]
+	}
+
+	if opts.Limit > 0 && len(docs) > opts.Limit {
+		docs = docs[:opts.Limit]
+	}
+
+	return docs
+}
+
+// docID returns the ID field of a document
+func docID(doc interface{}) string {
+	v := reflect.ValueOf(doc)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return ""
+	}
+	if id := v.FieldByName("ID"); id.IsValid() && id.Kind() == reflect.String {
+		return id.String()
+	}
+	return ""
+}
+
+// newID generates a random document ID with the same length as Firestore's
+func newID() string {
+	b := make([]byte, 10)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("memory: failed to generate document ID: %v", err))
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,101 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// TenantRepository is an in-memory repositories.TenantStore. Tenants are the
+// root of the hierarchy, so they are all stored under an empty tenant scope.
+type TenantRepository struct {
+	tenants *collection[models.Tenant]
+}
+
+// NewTenantRepository creates a new in-memory tenant repository
+func NewTenantRepository() *TenantRepository {
+	return &TenantRepository{
+		tenants: newCollection[models.Tenant](),
+	}
+}
+
+// Create creates a new tenant
+func (r *TenantRepository) Create(ctx context.Context, tenant *models.Tenant) error {
+	if tenant.ID == "" {
+		tenant.ID = newID()
+	}
+
+	now := time.Now()
+	tenant.CreatedAt = now
+	tenant.UpdatedAt = now
+
+	if err := r.tenants.insert("", tenant.ID, tenant); err != nil {
+		return fmt.Errorf("failed to create tenant: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves a tenant by ID
+func (r *TenantRepository) Get(ctx context.Context, id string) (*models.Tenant, error) {
+	return r.tenants.get("", id)
+}
+
+// GetBySlug retrieves a tenant by slug
+func (r *TenantRepository) GetBySlug(ctx context.Context, slug string) (*models.Tenant, error) {
+	if slug == "" {
+		return nil, fmt.Errorf("%w: slug is required", repositories.ErrInvalidInput)
+	}
+
+	return r.tenants.first("", func(t *models.Tenant) bool {
+		return t.Slug == slug
+	})
+}
+
+// Update updates a tenant
+func (r *TenantRepository) Update(ctx context.Context, id string, updates map[string]interface{}) error {
+	if id == "" {
+		return fmt.Errorf("%w: tenant ID is required", repositories.ErrInvalidInput)
+	}
+
+	updates["updated_at"] = time.Now()
+
+	if err := r.tenants.update("", id, updates); err != nil {
+		return fmt.Errorf("failed to update tenant: %w", err)
+	}
+
+	return nil
+}
+
+// Delete deletes a tenant
+func (r *TenantRepository) Delete(ctx context.Context, id string) error {
+	if err := r.tenants.remove("", id); err != nil {
+		return fmt.Errorf("failed to delete tenant: %w", err)
+	}
+	return nil
+}
+
+// List retrieves all tenants with pagination
+func (r *TenantRepository) List(ctx context.Context, opts repositories.PaginationOptions) ([]*models.Tenant, error) {
+	if opts.Limit == 0 {
+		opts = repositories.DefaultPaginationOptions()
+	}
+
+	return paginate(r.tenants.find("", nil), opts), nil
+}
+
+// ListActive retrieves all active tenants with pagination
+func (r *TenantRepository) ListActive(ctx context.Context, opts repositories.PaginationOptions) ([]*models.Tenant, error) {
+	if opts.Limit == 0 {
+		opts = repositories.DefaultPaginationOptions()
+	}
+
+	tenants := r.tenants.find("", func(t *models.Tenant) bool {
+		return t.IsActive
+	})
+
+	return paginate(tenants, opts), nil
+}
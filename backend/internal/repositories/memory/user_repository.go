@@ -0,0 +1,154 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// UserRepository is an in-memory repositories.UserStore
+type UserRepository struct {
+	users *collection[models.User]
+}
+
+// NewUserRepository creates a new in-memory user repository
+func NewUserRepository() *UserRepository {
+	return &UserRepository{
+		users: newCollection[models.User](),
+	}
+}
+
+// Create creates a new user (replacing any user with the same ID, like Firestore's Set)
+func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
+	if user.ID == "" {
+		return fmt.Errorf("user ID is required")
+	}
+	if user.TenantID == "" {
+		return fmt.Errorf("tenant ID is required")
+	}
+
+	user.CreatedAt = time.Now()
+	user.UpdatedAt = time.Now()
+
+	return r.users.put(user.TenantID, user.ID, user)
+}
+
+// Get retrieves a user by ID
+func (r *UserRepository) Get(ctx context.Context, tenantID, userID string) (*models.User, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant ID is required")
+	}
+	if userID == "" {
+		return nil, fmt.Errorf("user ID is required")
+	}
+
+	return r.users.get(tenantID, userID)
+}
+
+// GetByEmail retrieves a user by email within a tenant
+func (r *UserRepository) GetByEmail(ctx context.Context, tenantID, email string) (*models.User, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant ID is required")
+	}
+	if email == "" {
+		return nil, fmt.Errorf("email is required")
+	}
+
+	return r.users.first(tenantID, func(u *models.User) bool {
+		return u.Email == email
+	})
+}
+
+// GetByFirebaseUID retrieves a user by Firebase UID within a tenant
+func (r *UserRepository) GetByFirebaseUID(ctx context.Context, tenantID, firebaseUID string) (*models.User, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant ID is required")
+	}
+	if firebaseUID == "" {
+		return nil, fmt.Errorf("firebase UID is required")
+	}
+
+	return r.users.first(tenantID, func(u *models.User) bool {
+		return u.FirebaseUID == firebaseUID
+	})
+}
+
+// List retrieves all users for a tenant
+func (r *UserRepository) List(ctx context.Context, tenantID string) ([]*models.User, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant ID is required")
+	}
+
+	return r.users.find(tenantID, nil), nil
+}
+
+// Update merges updates into a user, creating the document when it does not
+// exist (mirrors Firestore's Set with MergeAll)
+func (r *UserRepository) Update(ctx context.Context, tenantID, userID string, updates map[string]interface{}) error {
+	if tenantID == "" {
+		return fmt.Errorf("tenant ID is required")
+	}
+	if userID == "" {
+		return fmt.Errorf("user ID is required")
+	}
+
+	updates["updated_at"] = time.Now()
+
+	err := r.users.update(tenantID, userID, updates)
+	if errors.Is(err, repositories.ErrNotFound) {
+		user := &models.User{ID: userID, TenantID: tenantID}
+		if err = applyUpdates(user, updates); err == nil {
+			err = r.users.put(tenantID, userID, user)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+
+	return nil
+}
+
+// Delete deletes a user. Deleting a missing user is not an error.
+func (r *UserRepository) Delete(ctx context.Context, tenantID, userID string) error {
+	if tenantID == "" {
+		return fmt.Errorf("tenant ID is required")
+	}
+	if userID == "" {
+		return fmt.Errorf("user ID is required")
+	}
+
+	if err := r.users.remove(tenantID, userID); err != nil && !errors.Is(err, repositories.ErrNotFound) {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+
+	return nil
+}
+
+// ListByRole retrieves users by role
+func (r *UserRepository) ListByRole(ctx context.Context, tenantID, role string) ([]*models.User, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant ID is required")
+	}
+	if role == "" {
+		return nil, fmt.Errorf("role is required")
+	}
+
+	return r.users.find(tenantID, func(u *models.User) bool {
+		return u.Role == role
+	}), nil
+}
+
+// ListActive retrieves all active users for a tenant
+func (r *UserRepository) ListActive(ctx context.Context, tenantID string) ([]*models.User, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant ID is required")
+	}
+
+	return r.users.find(tenantID, func(u *models.User) bool {
+		return u.IsActive
+	}), nil
+}
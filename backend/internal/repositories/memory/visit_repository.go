@@ -0,0 +1,148 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/firestore"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// VisitRepository is an in-memory repositories.VisitStore
+type VisitRepository struct {
+	visits *collection[models.Visit]
+	mu     sync.Mutex // Serializes the checks of CreateIfAvailable
+}
+
+// NewVisitRepository creates a new in-memory visit repository
+func NewVisitRepository() *VisitRepository {
+	return &VisitRepository{
+		visits: newCollection[models.Visit](),
+	}
+}
+
+// prepare validates a new visit and sets its ID and timestamps
+func (r *VisitRepository) prepare(visit *models.Visit) error {
+	if visit.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+	if visit.PropertyID == "" {
+		return fmt.Errorf("%w: property_id is required", repositories.ErrInvalidInput)
+	}
+
+	if visit.ID == "" {
+		visit.ID = newID()
+	}
+
+	now := time.Now()
+	visit.CreatedAt = now
+	visit.UpdatedAt = now
+	return nil
+}
+
+// Create creates a new visit
+func (r *VisitRepository) Create(ctx context.Context, visit *models.Visit) error {
+	if err := r.prepare(visit); err != nil {
+		return err
+	}
+
+	if err := r.visits.insert(visit.TenantID, visit.ID, visit); err != nil {
+		return fmt.Errorf("failed to create visit: %w", err)
+	}
+
+	return nil
+}
+
+// CreateIfAvailable creates a visit unless another visit of the same broker
+// holding its slot overlaps it; false when the slot is taken
+func (r *VisitRepository) CreateIfAvailable(ctx context.Context, visit *models.Visit) (bool, error) {
+	if err := r.prepare(visit); err != nil {
+		return false, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, err := r.visits.first(visit.TenantID, func(other *models.Visit) bool {
+		return other.BrokerID == visit.BrokerID && other.HoldsSlot() && other.Overlaps(visit.StartAt, visit.EndAt)
+	}); err == nil {
+		return false, nil
+	}
+
+	if err := r.visits.insert(visit.TenantID, visit.ID, visit); err != nil {
+		return false, fmt.Errorf("failed to create visit: %w", err)
+	}
+
+	return true, nil
+}
+
+// Get retrieves a visit by ID
+func (r *VisitRepository) Get(ctx context.Context, tenantID, id string) (*models.Visit, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	return r.visits.get(tenantID, id)
+}
+
+// Update updates specific fields of a visit
+func (r *VisitRepository) Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	updates["updated_at"] = time.Now()
+
+	if err := r.visits.update(tenantID, id, updates); err != nil {
+		return fmt.Errorf("failed to update visit: %w", err)
+	}
+
+	return nil
+}
+
+// List retrieves visits with filters, by start time (earliest first) unless
+// opts sets another order
+func (r *VisitRepository) List(ctx context.Context, tenantID string, filters *repositories.VisitFilters, opts repositories.PaginationOptions) ([]*models.Visit, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", repositories.ErrInvalidInput)
+	}
+
+	if opts.Limit == 0 {
+		opts.Limit = repositories.DefaultPaginationOptions().Limit
+	}
+	if opts.OrderBy == "" {
+		opts.OrderBy = "start_at"
+		opts.Direction = firestore.Asc
+	}
+
+	visits := r.visits.find(tenantID, func(v *models.Visit) bool {
+		if filters == nil {
+			return true
+		}
+		if filters.BrokerID != "" && v.BrokerID != filters.BrokerID {
+			return false
+		}
+		if filters.PropertyID != "" && v.PropertyID != filters.PropertyID {
+			return false
+		}
+		if filters.LeadID != "" && v.LeadID != filters.LeadID {
+			return false
+		}
+		if len(filters.Statuses) > 0 && !slices.Contains(filters.Statuses, v.Status) {
+			return false
+		}
+		if filters.StartFrom != nil && v.StartAt.Before(*filters.StartFrom) {
+			return false
+		}
+		if filters.StartBefore != nil && !v.StartAt.Before(*filters.StartBefore) {
+			return false
+		}
+		return true
+	})
+	return paginate(visits, opts), nil
+}
@@ -0,0 +1,136 @@
+package repositories
+
+import (
+	"sort"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/utils"
+)
+
+// MaxGeoResults caps the number of properties returned by a map/radius search
+const MaxGeoResults = 500
+
+// Matches reports whether a property satisfies every set filter.
+// A nil filter matches every property.
+func (f *PropertyFilters) Matches(p *models.Property) bool {
+	if f == nil {
+		return true
+	}
+	if f.Status != nil && p.Status != *f.Status {
+		return false
+	}
+	if f.PropertyType != nil && p.PropertyType != *f.PropertyType {
+		return false
+	}
+	if f.TransactionType != nil && (p.TransactionType == nil || *p.TransactionType != *f.TransactionType) {
+		return false
+	}
+	if f.Visibility != nil && p.Visibility != *f.Visibility {
+		return false
+	}
+	if f.OwnerID != "" && p.OwnerID != f.OwnerID {
+		return false
+	}
+	if f.City != "" && p.City != f.City {
+		return false
+	}
+	if f.Neighborhood != "" && p.Neighborhood != f.Neighborhood {
+		return false
+	}
+	if f.MinPrice != nil && p.PriceAmount < *f.MinPrice {
+		return false
+	}
+	if f.MaxPrice != nil && p.PriceAmount > *f.MaxPrice {
+		return false
+	}
+	if f.MinBedrooms != nil && p.Bedrooms < *f.MinBedrooms {
+		return false
+	}
+	if f.MinBathrooms != nil && p.Bathrooms < *f.MinBathrooms {
+		return false
+	}
+	return true
+}
+
+// SelectPropertiesInBounds keeps the geolocated candidates inside bounds that
+// match filters, newest first, up to limit (DefaultPaginationOptions limit when 0).
+// Geohash range queries return a superset of the box, so every backend runs its
+// candidates through this function.
+func SelectPropertiesInBounds(candidates []*models.Property, bounds utils.GeoBounds, filters *PropertyFilters, limit int) []*models.Property {
+	selected := make([]*models.Property, 0)
+	for _, p := range candidates {
+		if !p.HasCoordinates() || !bounds.Contains(*p.Latitude, *p.Longitude) {
+			continue
+		}
+		if !filters.Matches(p) {
+			continue
+		}
+		selected = append(selected, p)
+	}
+
+	sort.SliceStable(selected, func(i, j int) bool {
+		return selected[i].CreatedAt.After(selected[j].CreatedAt)
+	})
+
+	return truncateGeoResults(selected, limit)
+}
+
+// SelectPropertiesNearby keeps the geolocated candidates within radiusKm of the
+// point that match filters, nearest first, up to limit. DistanceKm is set on
+// every returned property.
+func SelectPropertiesNearby(candidates []*models.Property, lat, lng, radiusKm float64, filters *PropertyFilters, limit int) []*models.Property {
+	selected := make([]*models.Property, 0)
+	for _, p := range candidates {
+		if !p.HasCoordinates() || !filters.Matches(p) {
+			continue
+		}
+
+		distance := utils.HaversineKm(lat, lng, *p.Latitude, *p.Longitude)
+		if distance > radiusKm {
+			continue
+		}
+
+		p.DistanceKm = &distance
+		selected = append(selected, p)
+	}
+
+	sort.SliceStable(selected, func(i, j int) bool {
+		return *selected[i].DistanceKm < *selected[j].DistanceKm
+	})
+
+	return truncateGeoResults(selected, limit)
+}
+
+// truncateGeoResults applies the default and maximum geo result limits
+func truncateGeoResults(properties []*models.Property, limit int) []*models.Property {
+	limit = geoResultLimit(limit)
+	if len(properties) > limit {
+		properties = properties[:limit]
+	}
+	return properties
+}
+
+// geoResultLimit returns the number of properties a map/radius search returns
+// for the requested limit
+func geoResultLimit(limit int) int {
+	if limit <= 0 {
+		limit = DefaultPaginationOptions().Limit
+	}
+	if limit > MaxGeoResults {
+		limit = MaxGeoResults
+	}
+	return limit
+}
+
+// geoReadSlack is how many candidates each geohash range query reads per
+// result: cells overlap the box only partly and the remaining filters run in
+// memory, so more candidates than results are needed
+const geoReadSlack = 3
+
+// GeoReadsPerRange caps the documents read by each geohash range query of a
+// map/radius search returning limit properties, so a large box never reads
+// every public property of its cells. Past the cap the results are a partial
+// view of the area; zooming in narrows the cells and completes them.
+func GeoReadsPerRange(limit int) int {
+	return geoResultLimit(limit) * geoReadSlack
+}
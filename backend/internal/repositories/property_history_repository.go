@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"google.golang.org/api/iterator"
+)
+
+// PropertyHistoryRepository handles Firestore operations for property price/status history
+type PropertyHistoryRepository struct {
+	*BaseRepository
+}
+
+// NewPropertyHistoryRepository creates a new property history repository
+func NewPropertyHistoryRepository(client *firestore.Client) *PropertyHistoryRepository {
+	return &PropertyHistoryRepository{
+		BaseRepository: NewBaseRepository(client),
+	}
+}
+
+// getHistoryCollection returns the history subcollection path of a property
+func (r *PropertyHistoryRepository) getHistoryCollection(propertyID string) string {
+	return fmt.Sprintf("properties/%s/history", propertyID)
+}
+
+// Create appends a history entry to the property
+func (r *PropertyHistoryRepository) Create(ctx context.Context, entry *models.PropertyHistoryEntry) error {
+	if entry.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if entry.PropertyID == "" {
+		return fmt.Errorf("%w: property_id is required", ErrInvalidInput)
+	}
+	if entry.Field == "" {
+		return fmt.Errorf("%w: field is required", ErrInvalidInput)
+	}
+
+	if entry.ChangedAt.IsZero() {
+		entry.ChangedAt = time.Now()
+	}
+
+	ref := r.Client().Collection(r.getHistoryCollection(entry.PropertyID)).NewDoc()
+	entry.ID = ref.ID
+
+	if _, err := ref.Create(ctx, entry); err != nil {
+		return fmt.Errorf("failed to create property history entry: %w", err)
+	}
+
+	return nil
+}
+
+// ListByProperty retrieves the history of a property, newest first by default
+func (r *PropertyHistoryRepository) ListByProperty(ctx context.Context, tenantID, propertyID string, opts PaginationOptions) ([]*models.PropertyHistoryEntry, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if propertyID == "" {
+		return nil, fmt.Errorf("%w: property_id is required", ErrInvalidInput)
+	}
+
+	if opts.Limit == 0 {
+		opts.Limit = DefaultPaginationOptions().Limit
+	}
+	if opts.OrderBy == "" {
+		opts.OrderBy = "changed_at"
+		opts.Direction = firestore.Desc
+	}
+
+	query := r.Client().Collection(r.getHistoryCollection(propertyID)).
+		Where("tenant_id", "==", tenantID)
+	query = r.ApplyPagination(query, opts)
+
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	entries := make([]*models.PropertyHistoryEntry, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate property history: %w", err)
+		}
+
+		var entry models.PropertyHistoryEntry
+		if err := doc.DataTo(&entry); err != nil {
+			return nil, fmt.Errorf("failed to decode property history entry: %w", err)
+		}
+
+		entry.ID = doc.Ref.ID
+		entries = append(entries, &entry)
+	}
+
+	return entries, nil
+}
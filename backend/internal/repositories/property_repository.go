@@ -9,6 +9,7 @@ import (
 	"google.golang.org/api/iterator"
 
 	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/utils"
 )
 
 // PropertyRepository handles Firestore operations for properties
@@ -83,6 +84,42 @@ func (r *PropertyRepository) Get(ctx context.Context, tenantID, id string) (*mod
 	return &property, nil
 }
 
+// GetMany retrieves properties by ID across tenants in a single read. The
+// result is aligned with ids; properties not found are nil.
+func (r *PropertyRepository) GetMany(ctx context.Context, ids []string) ([]*models.Property, error) {
+	properties := make([]*models.Property, len(ids))
+	if len(ids) == 0 {
+		return properties, nil
+	}
+
+	refs := make([]*firestore.DocumentRef, len(ids))
+	for i, id := range ids {
+		if id == "" {
+			return nil, fmt.Errorf("%w: document ID is required", ErrInvalidInput)
+		}
+		refs[i] = r.Client().Collection("properties").Doc(id)
+	}
+
+	docs, err := r.Client().GetAll(ctx, refs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get properties: %w", err)
+	}
+
+	for i, doc := range docs {
+		if !doc.Exists() {
+			continue
+		}
+		var property models.Property
+		if err := doc.DataTo(&property); err != nil {
+			return nil, fmt.Errorf("failed to decode property: %w", err)
+		}
+		property.ID = doc.Ref.ID
+		properties[i] = &property
+	}
+
+	return properties, nil
+}
+
 // GetBySlug retrieves a property by slug
 func (r *PropertyRepository) GetBySlug(ctx context.Context, tenantID, slug string) (*models.Property, error) {
 	if tenantID == "" {
@@ -285,12 +322,8 @@ func (r *PropertyRepository) List(ctx context.Context, tenantID string, filters
 		}
 	}
 
-	// Apply pagination limit only (skip ordering to avoid index requirement)
-	if opts.Limit > 0 {
-		query = query.Limit(opts.Limit)
-	}
-	// TODO: Re-enable ordering after creating composite indexes
-	// query = r.ApplyPagination(query, opts)
+	// Ordering, cursor and limit (composite indexes in firestore.indexes.json)
+	query = r.ApplyPagination(query, opts)
 
 	iter := query.Documents(ctx)
 	defer iter.Stop()
@@ -613,3 +646,88 @@ func (r *PropertyRepository) SearchByLocation(ctx context.Context, tenantID, cit
 
 	return r.List(ctx, tenantID, filters, opts)
 }
+
+// ListPublicInBounds retrieves PUBLIC geolocated properties inside a map bounding box (cross-tenant)
+func (r *PropertyRepository) ListPublicInBounds(ctx context.Context, bounds utils.GeoBounds, filters *PropertyFilters, limit int) ([]*models.Property, error) {
+	if err := bounds.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+
+	candidates, err := r.listPublicByGeohash(ctx, bounds, GeoReadsPerRange(limit))
+	if err != nil {
+		return nil, err
+	}
+
+	return SelectPropertiesInBounds(candidates, bounds, filters, limit), nil
+}
+
+// ListPublicNearby retrieves PUBLIC geolocated properties within radiusKm of a point (cross-tenant),
+// nearest first
+func (r *PropertyRepository) ListPublicNearby(ctx context.Context, lat, lng, radiusKm float64, filters *PropertyFilters, limit int) ([]*models.Property, error) {
+	if err := utils.ValidateCoordinates(lat, lng); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	if radiusKm <= 0 {
+		return nil, fmt.Errorf("%w: radius must be positive", ErrInvalidInput)
+	}
+	bounds := utils.BoundsAroundPoint(lat, lng, radiusKm)
+	if err := bounds.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+
+	candidates, err := r.listPublicByGeohash(ctx, bounds, GeoReadsPerRange(limit))
+	if err != nil {
+		return nil, err
+	}
+
+	return SelectPropertiesNearby(candidates, lat, lng, radiusKm, filters, limit), nil
+}
+
+// listPublicByGeohash runs one geohash range query per cell covering bounds,
+// which the callers validated (capped at utils.MaxBoundsSpanDegrees), reading
+// at most readsPerRange documents each.
+// Only visibility/status are filtered in Firestore (index: visibility, status, geohash);
+// the remaining filters are applied in memory by the callers.
+func (r *PropertyRepository) listPublicByGeohash(ctx context.Context, bounds utils.GeoBounds, readsPerRange int) ([]*models.Property, error) {
+	seen := make(map[string]bool)
+	properties := make([]*models.Property, 0)
+
+	for _, rng := range bounds.GeohashRanges() {
+		query := r.Client().Collection("properties").
+			Where("visibility", "==", string(models.PropertyVisibilityPublic)).
+			Where("status", "==", string(models.PropertyStatusAvailable)).
+			Where("geohash", ">=", rng.Start).
+			Where("geohash", "<=", rng.End).
+			OrderBy("geohash", firestore.Asc).
+			Limit(readsPerRange)
+
+		iter := query.Documents(ctx)
+		for {
+			doc, err := iter.Next()
+			if err == iterator.Done {
+				break
+			}
+			if err != nil {
+				iter.Stop()
+				return nil, fmt.Errorf("failed to iterate geolocated properties: %w", err)
+			}
+
+			if seen[doc.Ref.ID] {
+				continue
+			}
+			seen[doc.Ref.ID] = true
+
+			var property models.Property
+			if err := doc.DataTo(&property); err != nil {
+				iter.Stop()
+				return nil, fmt.Errorf("failed to decode geolocated property: %w", err)
+			}
+
+			property.ID = doc.Ref.ID
+			properties = append(properties, &property)
+		}
+		iter.Stop()
+	}
+
+	return properties, nil
+}
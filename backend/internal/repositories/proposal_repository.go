@@ -0,0 +1,165 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+// ProposalFilters contains filters for listing proposals
+type ProposalFilters struct {
+	BrokerID    string
+	PropertyID  string
+	LeadID      string
+	Statuses    []models.ProposalStatus // Any of them
+	ValidBefore *time.Time              // terms.valid_until < ValidBefore
+}
+
+// ProposalRepository handles Firestore operations for property proposals
+type ProposalRepository struct {
+	*BaseRepository
+}
+
+// NewProposalRepository creates a new proposal repository
+func NewProposalRepository(client *firestore.Client) *ProposalRepository {
+	return &ProposalRepository{
+		BaseRepository: NewBaseRepository(client),
+	}
+}
+
+// getProposalsCollection returns the collection path for proposals within a tenant
+func (r *ProposalRepository) getProposalsCollection(tenantID string) string {
+	return fmt.Sprintf("tenants/%s/proposals", tenantID)
+}
+
+// Create creates a new proposal
+func (r *ProposalRepository) Create(ctx context.Context, proposal *models.Proposal) error {
+	if proposal.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if proposal.PropertyID == "" {
+		return fmt.Errorf("%w: property_id is required", ErrInvalidInput)
+	}
+
+	collectionPath := r.getProposalsCollection(proposal.TenantID)
+	if proposal.ID == "" {
+		proposal.ID = r.GenerateID(collectionPath)
+	}
+
+	now := time.Now()
+	proposal.CreatedAt = now
+	proposal.UpdatedAt = now
+
+	if err := r.CreateDocument(ctx, collectionPath, proposal.ID, proposal); err != nil {
+		return fmt.Errorf("failed to create proposal: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves a proposal by ID
+func (r *ProposalRepository) Get(ctx context.Context, tenantID, id string) (*models.Proposal, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	var proposal models.Proposal
+	if err := r.GetDocument(ctx, r.getProposalsCollection(tenantID), id, &proposal); err != nil {
+		return nil, err
+	}
+
+	proposal.ID = id
+	return &proposal, nil
+}
+
+// Update updates specific fields of a proposal
+func (r *ProposalRepository) Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	updates["updated_at"] = time.Now()
+
+	firestoreUpdates := make([]firestore.Update, 0, len(updates))
+	for key, value := range updates {
+		firestoreUpdates = append(firestoreUpdates, firestore.Update{Path: key, Value: value})
+	}
+
+	if err := r.UpdateDocument(ctx, r.getProposalsCollection(tenantID), id, firestoreUpdates); err != nil {
+		return fmt.Errorf("failed to update proposal: %w", err)
+	}
+
+	return nil
+}
+
+// List retrieves proposals with filters, most recent first (by answer deadline
+// with ValidBefore) unless opts sets another order
+func (r *ProposalRepository) List(ctx context.Context, tenantID string, filters *ProposalFilters, opts PaginationOptions) ([]*models.Proposal, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	if opts.Limit == 0 {
+		opts.Limit = DefaultPaginationOptions().Limit
+	}
+	if opts.OrderBy == "" {
+		opts.OrderBy = "created_at"
+		opts.Direction = firestore.Desc
+		if filters != nil && filters.ValidBefore != nil {
+			opts.OrderBy = "terms.valid_until"
+			opts.Direction = firestore.Asc
+		}
+	}
+
+	query := r.Client().Collection(r.getProposalsCollection(tenantID)).Query
+	if filters != nil {
+		if filters.BrokerID != "" {
+			query = query.Where("broker_id", "==", filters.BrokerID)
+		}
+		if filters.PropertyID != "" {
+			query = query.Where("property_id", "==", filters.PropertyID)
+		}
+		if filters.LeadID != "" {
+			query = query.Where("lead_id", "==", filters.LeadID)
+		}
+		if len(filters.Statuses) > 0 {
+			statuses := make([]string, len(filters.Statuses))
+			for i, status := range filters.Statuses {
+				statuses[i] = string(status)
+			}
+			query = query.Where("status", "in", statuses)
+		}
+		if filters.ValidBefore != nil {
+			query = query.Where("terms.valid_until", "<", *filters.ValidBefore)
+		}
+	}
+
+	iter := r.ApplyPagination(query, opts).Documents(ctx)
+	defer iter.Stop()
+
+	proposals := make([]*models.Proposal, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate proposals: %w", err)
+		}
+
+		var proposal models.Proposal
+		if err := doc.DataTo(&proposal); err != nil {
+			return nil, fmt.Errorf("failed to decode proposal: %w", err)
+		}
+
+		proposal.ID = doc.Ref.ID
+		proposals = append(proposals, &proposal)
+	}
+
+	return proposals, nil
+}
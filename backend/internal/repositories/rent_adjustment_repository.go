@@ -0,0 +1,157 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+// RentAdjustmentFilters contains filters for listing rent adjustments
+type RentAdjustmentFilters struct {
+	ContractID      string
+	PropertyID      string
+	EffectivePeriod string // YYYY-MM
+	Statuses        []models.RentAdjustmentStatus
+}
+
+// RentAdjustmentRepository handles Firestore operations for rent adjustments
+type RentAdjustmentRepository struct {
+	*BaseRepository
+}
+
+// NewRentAdjustmentRepository creates a new rent adjustment repository
+func NewRentAdjustmentRepository(client *firestore.Client) *RentAdjustmentRepository {
+	return &RentAdjustmentRepository{
+		BaseRepository: NewBaseRepository(client),
+	}
+}
+
+// getAdjustmentsCollection returns the collection path for rent adjustments within a tenant
+func (r *RentAdjustmentRepository) getAdjustmentsCollection(tenantID string) string {
+	return fmt.Sprintf("tenants/%s/rent_adjustments", tenantID)
+}
+
+// Create creates a new rent adjustment
+func (r *RentAdjustmentRepository) Create(ctx context.Context, adjustment *models.RentAdjustment) error {
+	if adjustment.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if adjustment.ContractID == "" {
+		return fmt.Errorf("%w: contract_id is required", ErrInvalidInput)
+	}
+
+	collectionPath := r.getAdjustmentsCollection(adjustment.TenantID)
+	if adjustment.ID == "" {
+		adjustment.ID = r.GenerateID(collectionPath)
+	}
+
+	now := time.Now()
+	adjustment.CreatedAt = now
+	adjustment.UpdatedAt = now
+
+	if err := r.CreateDocument(ctx, collectionPath, adjustment.ID, adjustment); err != nil {
+		return fmt.Errorf("failed to create rent adjustment: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves a rent adjustment by ID
+func (r *RentAdjustmentRepository) Get(ctx context.Context, tenantID, id string) (*models.RentAdjustment, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	var adjustment models.RentAdjustment
+	if err := r.GetDocument(ctx, r.getAdjustmentsCollection(tenantID), id, &adjustment); err != nil {
+		return nil, err
+	}
+
+	adjustment.ID = id
+	return &adjustment, nil
+}
+
+// Update updates specific fields of a rent adjustment
+func (r *RentAdjustmentRepository) Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	updates["updated_at"] = time.Now()
+
+	firestoreUpdates := make([]firestore.Update, 0, len(updates))
+	for key, value := range updates {
+		firestoreUpdates = append(firestoreUpdates, firestore.Update{Path: key, Value: value})
+	}
+
+	if err := r.UpdateDocument(ctx, r.getAdjustmentsCollection(tenantID), id, firestoreUpdates); err != nil {
+		return fmt.Errorf("failed to update rent adjustment: %w", err)
+	}
+
+	return nil
+}
+
+// List retrieves rent adjustments with filters, latest effective month first
+// unless opts sets another order
+func (r *RentAdjustmentRepository) List(ctx context.Context, tenantID string, filters *RentAdjustmentFilters, opts PaginationOptions) ([]*models.RentAdjustment, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	if opts.Limit == 0 {
+		opts.Limit = DefaultPaginationOptions().Limit
+	}
+	if opts.OrderBy == "" {
+		opts.OrderBy = "effective_period"
+		opts.Direction = firestore.Desc
+	}
+
+	query := r.Client().Collection(r.getAdjustmentsCollection(tenantID)).Query
+	if filters != nil {
+		if filters.ContractID != "" {
+			query = query.Where("contract_id", "==", filters.ContractID)
+		}
+		if filters.PropertyID != "" {
+			query = query.Where("property_id", "==", filters.PropertyID)
+		}
+		if filters.EffectivePeriod != "" {
+			query = query.Where("effective_period", "==", filters.EffectivePeriod)
+		}
+		if len(filters.Statuses) > 0 {
+			statuses := make([]string, len(filters.Statuses))
+			for i, status := range filters.Statuses {
+				statuses[i] = string(status)
+			}
+			query = query.Where("status", "in", statuses)
+		}
+	}
+
+	iter := r.ApplyPagination(query, opts).Documents(ctx)
+	defer iter.Stop()
+
+	adjustments := make([]*models.RentAdjustment, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate rent adjustments: %w", err)
+		}
+
+		var adjustment models.RentAdjustment
+		if err := doc.DataTo(&adjustment); err != nil {
+			return nil, fmt.Errorf("failed to decode rent adjustment: %w", err)
+		}
+
+		adjustment.ID = doc.Ref.ID
+		adjustments = append(adjustments, &adjustment)
+	}
+
+	return adjustments, nil
+}
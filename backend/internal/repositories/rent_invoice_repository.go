@@ -0,0 +1,256 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+// RentInvoiceFilters contains filters for listing rent invoices
+type RentInvoiceFilters struct {
+	ContractID    string
+	PropertyID    string
+	OwnerID       string
+	Period        string // YYYY-MM of the rent charged
+	Statuses      []models.RentInvoiceStatus
+	DueBefore     *time.Time // due_date < DueBefore
+	PaymentPeriod string     // Invoices with payments in the month (YYYY-MM)
+}
+
+// RentInvoiceRepository handles Firestore operations for rent invoices
+type RentInvoiceRepository struct {
+	*BaseRepository
+}
+
+// NewRentInvoiceRepository creates a new rent invoice repository
+func NewRentInvoiceRepository(client *firestore.Client) *RentInvoiceRepository {
+	return &RentInvoiceRepository{
+		BaseRepository: NewBaseRepository(client),
+	}
+}
+
+// getInvoicesCollection returns the collection path for rent invoices within a tenant
+func (r *RentInvoiceRepository) getInvoicesCollection(tenantID string) string {
+	return fmt.Sprintf("tenants/%s/rent_invoices", tenantID)
+}
+
+// Create creates a new rent invoice; ErrAlreadyExists when the contract
+// already has the invoice of the month
+func (r *RentInvoiceRepository) Create(ctx context.Context, invoice *models.RentInvoice) error {
+	if invoice.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if invoice.ContractID == "" || invoice.Period == "" {
+		return fmt.Errorf("%w: contract_id and period are required", ErrInvalidInput)
+	}
+
+	invoice.ID = models.RentInvoiceID(invoice.ContractID, invoice.Period)
+	if invoice.Reference == "" {
+		invoice.Reference = invoice.ID
+	}
+
+	now := time.Now()
+	invoice.CreatedAt = now
+	invoice.UpdatedAt = now
+
+	if err := r.CreateDocument(ctx, r.getInvoicesCollection(invoice.TenantID), invoice.ID, invoice); err != nil {
+		return fmt.Errorf("failed to create rent invoice: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves a rent invoice by ID
+func (r *RentInvoiceRepository) Get(ctx context.Context, tenantID, id string) (*models.RentInvoice, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	var invoice models.RentInvoice
+	if err := r.GetDocument(ctx, r.getInvoicesCollection(tenantID), id, &invoice); err != nil {
+		return nil, err
+	}
+
+	invoice.ID = id
+	return &invoice, nil
+}
+
+// GetByReference retrieves the rent invoice charged with the given external
+// reference. Payment webhooks carry no tenant, so the lookup spans every
+// tenant.
+func (r *RentInvoiceRepository) GetByReference(ctx context.Context, reference string) (*models.RentInvoice, error) {
+	if reference == "" {
+		return nil, fmt.Errorf("%w: reference is required", ErrInvalidInput)
+	}
+
+	iter := r.Client().CollectionGroup("rent_invoices").
+		Where("reference", "==", reference).
+		Limit(1).
+		Documents(ctx)
+	defer iter.Stop()
+
+	doc, err := iter.Next()
+	if err == iterator.Done {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rent invoices: %w", err)
+	}
+
+	var invoice models.RentInvoice
+	if err := doc.DataTo(&invoice); err != nil {
+		return nil, fmt.Errorf("failed to decode rent invoice: %w", err)
+	}
+
+	invoice.ID = doc.Ref.ID
+	return &invoice, nil
+}
+
+// Update updates specific fields of a rent invoice
+func (r *RentInvoiceRepository) Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	updates["updated_at"] = time.Now()
+
+	firestoreUpdates := make([]firestore.Update, 0, len(updates))
+	for key, value := range updates {
+		firestoreUpdates = append(firestoreUpdates, firestore.Update{Path: key, Value: value})
+	}
+
+	if err := r.UpdateDocument(ctx, r.getInvoicesCollection(tenantID), id, firestoreUpdates); err != nil {
+		return fmt.Errorf("failed to update rent invoice: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateIf updates a rent invoice only when check accepts its current state.
+// The read and the write run in one transaction, so a payment cannot
+// overwrite another recorded meanwhile. It returns false when check refused
+// the invoice.
+func (r *RentInvoiceRepository) UpdateIf(ctx context.Context, tenantID, id string, check func(invoice *models.RentInvoice) bool, updates map[string]interface{}) (bool, error) {
+	if tenantID == "" {
+		return false, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if id == "" {
+		return false, fmt.Errorf("%w: document ID is required", ErrInvalidInput)
+	}
+
+	updates["updated_at"] = time.Now()
+	firestoreUpdates := make([]firestore.Update, 0, len(updates))
+	for key, value := range updates {
+		firestoreUpdates = append(firestoreUpdates, firestore.Update{Path: key, Value: value})
+	}
+
+	ref := r.Client().Collection(r.getInvoicesCollection(tenantID)).Doc(id)
+	updated := false
+	err := r.Client().RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		updated = false
+
+		doc, err := tx.Get(ref)
+		if err != nil {
+			if status.Code(err) == codes.NotFound {
+				return ErrNotFound
+			}
+			return err
+		}
+
+		var invoice models.RentInvoice
+		if err := doc.DataTo(&invoice); err != nil {
+			return fmt.Errorf("failed to decode rent invoice: %w", err)
+		}
+		invoice.ID = id
+		if !check(&invoice) {
+			return nil
+		}
+
+		updated = true
+		return tx.Update(ref, firestoreUpdates)
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to update rent invoice: %w", err)
+	}
+
+	return updated, nil
+}
+
+// List retrieves rent invoices with filters, latest due date first (oldest
+// first with DueBefore) unless opts sets another order
+func (r *RentInvoiceRepository) List(ctx context.Context, tenantID string, filters *RentInvoiceFilters, opts PaginationOptions) ([]*models.RentInvoice, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	if opts.Limit == 0 {
+		opts.Limit = DefaultPaginationOptions().Limit
+	}
+	if opts.OrderBy == "" {
+		opts.OrderBy = "due_date"
+		opts.Direction = firestore.Desc
+		if filters != nil && filters.DueBefore != nil {
+			opts.Direction = firestore.Asc
+		}
+	}
+
+	query := r.Client().Collection(r.getInvoicesCollection(tenantID)).Query
+	if filters != nil {
+		if filters.ContractID != "" {
+			query = query.Where("contract_id", "==", filters.ContractID)
+		}
+		if filters.PropertyID != "" {
+			query = query.Where("property_id", "==", filters.PropertyID)
+		}
+		if filters.OwnerID != "" {
+			query = query.Where("owner_id", "==", filters.OwnerID)
+		}
+		if filters.Period != "" {
+			query = query.Where("period", "==", filters.Period)
+		}
+		if len(filters.Statuses) > 0 {
+			statuses := make([]string, len(filters.Statuses))
+			for i, status := range filters.Statuses {
+				statuses[i] = string(status)
+			}
+			query = query.Where("status", "in", statuses)
+		}
+		if filters.DueBefore != nil {
+			query = query.Where("due_date", "<", *filters.DueBefore)
+		}
+		if filters.PaymentPeriod != "" {
+			query = query.Where("payment_periods", "array-contains", filters.PaymentPeriod)
+		}
+	}
+
+	iter := r.ApplyPagination(query, opts).Documents(ctx)
+	defer iter.Stop()
+
+	invoices := make([]*models.RentInvoice, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate rent invoices: %w", err)
+		}
+
+		var invoice models.RentInvoice
+		if err := doc.DataTo(&invoice); err != nil {
+			return nil, fmt.Errorf("failed to decode rent invoice: %w", err)
+		}
+
+		invoice.ID = doc.Ref.ID
+		invoices = append(invoices, &invoice)
+	}
+
+	return invoices, nil
+}
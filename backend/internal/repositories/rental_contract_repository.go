@@ -0,0 +1,161 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+// RentalContractFilters contains filters for listing rental contracts
+type RentalContractFilters struct {
+	PropertyID string
+	OwnerID    string
+	Statuses   []models.RentalContractStatus
+	EndsBefore *time.Time // end_date < EndsBefore
+}
+
+// RentalContractRepository handles Firestore operations for rental contracts
+type RentalContractRepository struct {
+	*BaseRepository
+}
+
+// NewRentalContractRepository creates a new rental contract repository
+func NewRentalContractRepository(client *firestore.Client) *RentalContractRepository {
+	return &RentalContractRepository{
+		BaseRepository: NewBaseRepository(client),
+	}
+}
+
+// getContractsCollection returns the collection path for rental contracts within a tenant
+func (r *RentalContractRepository) getContractsCollection(tenantID string) string {
+	return fmt.Sprintf("tenants/%s/rental_contracts", tenantID)
+}
+
+// Create creates a new rental contract
+func (r *RentalContractRepository) Create(ctx context.Context, contract *models.RentalContract) error {
+	if contract.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if contract.PropertyID == "" {
+		return fmt.Errorf("%w: property_id is required", ErrInvalidInput)
+	}
+
+	collectionPath := r.getContractsCollection(contract.TenantID)
+	if contract.ID == "" {
+		contract.ID = r.GenerateID(collectionPath)
+	}
+
+	now := time.Now()
+	contract.CreatedAt = now
+	contract.UpdatedAt = now
+
+	if err := r.CreateDocument(ctx, collectionPath, contract.ID, contract); err != nil {
+		return fmt.Errorf("failed to create rental contract: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves a rental contract by ID
+func (r *RentalContractRepository) Get(ctx context.Context, tenantID, id string) (*models.RentalContract, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	var contract models.RentalContract
+	if err := r.GetDocument(ctx, r.getContractsCollection(tenantID), id, &contract); err != nil {
+		return nil, err
+	}
+
+	contract.ID = id
+	return &contract, nil
+}
+
+// Update updates specific fields of a rental contract
+func (r *RentalContractRepository) Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	updates["updated_at"] = time.Now()
+
+	firestoreUpdates := make([]firestore.Update, 0, len(updates))
+	for key, value := range updates {
+		firestoreUpdates = append(firestoreUpdates, firestore.Update{Path: key, Value: value})
+	}
+
+	if err := r.UpdateDocument(ctx, r.getContractsCollection(tenantID), id, firestoreUpdates); err != nil {
+		return fmt.Errorf("failed to update rental contract: %w", err)
+	}
+
+	return nil
+}
+
+// List retrieves rental contracts with filters, latest start first (by end
+// date with EndsBefore) unless opts sets another order
+func (r *RentalContractRepository) List(ctx context.Context, tenantID string, filters *RentalContractFilters, opts PaginationOptions) ([]*models.RentalContract, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	if opts.Limit == 0 {
+		opts.Limit = DefaultPaginationOptions().Limit
+	}
+	if opts.OrderBy == "" {
+		opts.OrderBy = "start_date"
+		opts.Direction = firestore.Desc
+		if filters != nil && filters.EndsBefore != nil {
+			opts.OrderBy = "end_date"
+			opts.Direction = firestore.Asc
+		}
+	}
+
+	query := r.Client().Collection(r.getContractsCollection(tenantID)).Query
+	if filters != nil {
+		if filters.PropertyID != "" {
+			query = query.Where("property_id", "==", filters.PropertyID)
+		}
+		if filters.OwnerID != "" {
+			query = query.Where("owner_id", "==", filters.OwnerID)
+		}
+		if len(filters.Statuses) > 0 {
+			statuses := make([]string, len(filters.Statuses))
+			for i, status := range filters.Statuses {
+				statuses[i] = string(status)
+			}
+			query = query.Where("status", "in", statuses)
+		}
+		if filters.EndsBefore != nil {
+			query = query.Where("end_date", "<", *filters.EndsBefore)
+		}
+	}
+
+	iter := r.ApplyPagination(query, opts).Documents(ctx)
+	defer iter.Stop()
+
+	contracts := make([]*models.RentalContract, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate rental contracts: %w", err)
+		}
+
+		var contract models.RentalContract
+		if err := doc.DataTo(&contract); err != nil {
+			return nil, fmt.Errorf("failed to decode rental contract: %w", err)
+		}
+
+		contract.ID = doc.Ref.ID
+		contracts = append(contracts, &contract)
+	}
+
+	return contracts, nil
+}
@@ -75,8 +75,15 @@ func (r *ScheduledConfirmationRepository) Update(ctx context.Context, sc *models
 	updates := []firestore.Update{
 		{Path: "status", Value: sc.Status},
 		{Path: "sent_at", Value: sc.SentAt},
+		{Path: "delivery_method", Value: sc.DeliveryMethod},
 		{Path: "delivery_status", Value: sc.DeliveryStatus},
 		{Path: "delivery_error", Value: sc.DeliveryError},
+		{Path: "delivery_attempts", Value: sc.DeliveryAttempts},
+		{Path: "provider_message_id", Value: sc.ProviderMessageID},
+		{Path: "delivered_at", Value: sc.DeliveredAt},
+		{Path: "reminder_step", Value: sc.ReminderStep},
+		{Path: "next_reminder_at", Value: sc.NextReminderAt},
+		{Path: "reminders", Value: sc.Reminders},
 		{Path: "responded_at", Value: sc.RespondedAt},
 		{Path: "response", Value: sc.Response},
 		{Path: "updated_at", Value: sc.UpdatedAt},
@@ -172,6 +179,126 @@ func (r *ScheduledConfirmationRepository) GetByPropertyAndMonth(ctx context.Cont
 	return results, nil
 }
 
+// GetByTokenID retrieves the scheduled confirmation that sent a confirmation token
+func (r *ScheduledConfirmationRepository) GetByTokenID(ctx context.Context, tenantID, tokenID string) (*models.ScheduledConfirmation, error) {
+	if tenantID == "" || tokenID == "" {
+		return nil, fmt.Errorf("%w: tenant_id and token_id are required", ErrInvalidInput)
+	}
+
+	iter := r.Client().Collection("scheduled_confirmations").
+		Where("tenant_id", "==", tenantID).
+		Where("token_id", "==", tokenID).
+		Limit(1).
+		Documents(ctx)
+	defer iter.Stop()
+
+	doc, err := iter.Next()
+	if err == iterator.Done {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query scheduled confirmations: %w", err)
+	}
+
+	var sc models.ScheduledConfirmation
+	if err := doc.DataTo(&sc); err != nil {
+		return nil, fmt.Errorf("failed to decode scheduled confirmation: %w", err)
+	}
+
+	sc.ID = doc.Ref.ID
+	return &sc, nil
+}
+
+// ListDueReminders retrieves the scheduled confirmations whose next follow-up
+// step is due at or before the given time
+func (r *ScheduledConfirmationRepository) ListDueReminders(ctx context.Context, tenantID string, dueBy time.Time) ([]*models.ScheduledConfirmation, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	query := r.Client().Collection("scheduled_confirmations").
+		Where("tenant_id", "==", tenantID).
+		Where("next_reminder_at", "<=", dueBy).
+		OrderBy("next_reminder_at", firestore.Asc)
+
+	return r.list(ctx, query)
+}
+
+// ListByProperty retrieves the latest scheduled confirmations of a property, newest first
+func (r *ScheduledConfirmationRepository) ListByProperty(ctx context.Context, tenantID, propertyID string, limit int) ([]*models.ScheduledConfirmation, error) {
+	if tenantID == "" || propertyID == "" {
+		return nil, fmt.Errorf("%w: tenant_id and property_id are required", ErrInvalidInput)
+	}
+
+	query := r.Client().Collection("scheduled_confirmations").
+		Where("tenant_id", "==", tenantID).
+		Where("property_id", "==", propertyID).
+		OrderBy("scheduled_for", firestore.Desc)
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	return r.list(ctx, query)
+}
+
+// list decodes every scheduled confirmation returned by query
+func (r *ScheduledConfirmationRepository) list(ctx context.Context, query firestore.Query) ([]*models.ScheduledConfirmation, error) {
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	var results []*models.ScheduledConfirmation
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate scheduled confirmations: %w", err)
+		}
+
+		var sc models.ScheduledConfirmation
+		if err := doc.DataTo(&sc); err != nil {
+			return nil, fmt.Errorf("failed to decode scheduled confirmation: %w", err)
+		}
+
+		sc.ID = doc.Ref.ID
+		results = append(results, &sc)
+	}
+
+	return results, nil
+}
+
+// GetByProviderMessageID retrieves the scheduled confirmation delivered as the
+// given provider message. Delivery webhooks carry no tenant, so the lookup
+// spans every tenant.
+func (r *ScheduledConfirmationRepository) GetByProviderMessageID(ctx context.Context, providerMessageID string) (*models.ScheduledConfirmation, error) {
+	if providerMessageID == "" {
+		return nil, fmt.Errorf("%w: provider_message_id is required", ErrInvalidInput)
+	}
+
+	iter := r.Client().Collection("scheduled_confirmations").
+		Where("provider_message_id", "==", providerMessageID).
+		Limit(1).
+		Documents(ctx)
+	defer iter.Stop()
+
+	doc, err := iter.Next()
+	if err == iterator.Done {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query scheduled confirmations: %w", err)
+	}
+
+	var sc models.ScheduledConfirmation
+	if err := doc.DataTo(&sc); err != nil {
+		return nil, fmt.Errorf("failed to decode scheduled confirmation: %w", err)
+	}
+
+	sc.ID = doc.Ref.ID
+	return &sc, nil
+}
+
 // ListByTenant retrieves all scheduled confirmations for a tenant with optional status filter
 func (r *ScheduledConfirmationRepository) ListByTenant(ctx context.Context, tenantID string, status *models.ScheduledConfirmationStatus, limit int) ([]*models.ScheduledConfirmation, error) {
 	if tenantID == "" {
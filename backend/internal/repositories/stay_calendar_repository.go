@@ -0,0 +1,123 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+// StayCalendarRepository handles Firestore operations for the stay calendars
+// of properties let by the night
+type StayCalendarRepository struct {
+	*BaseRepository
+}
+
+// NewStayCalendarRepository creates a new stay calendar repository
+func NewStayCalendarRepository(client *firestore.Client) *StayCalendarRepository {
+	return &StayCalendarRepository{
+		BaseRepository: NewBaseRepository(client),
+	}
+}
+
+// getCalendarsCollection returns the collection path for stay calendars within a tenant
+func (r *StayCalendarRepository) getCalendarsCollection(tenantID string) string {
+	return fmt.Sprintf("tenants/%s/stay_calendars", tenantID)
+}
+
+// Get retrieves the stay calendar of a property
+func (r *StayCalendarRepository) Get(ctx context.Context, tenantID, propertyID string) (*models.StayCalendar, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	var calendar models.StayCalendar
+	if err := r.GetDocument(ctx, r.getCalendarsCollection(tenantID), propertyID, &calendar); err != nil {
+		return nil, err
+	}
+
+	calendar.PropertyID = propertyID
+	return &calendar, nil
+}
+
+// Save creates or replaces the stay calendar of a property
+func (r *StayCalendarRepository) Save(ctx context.Context, calendar *models.StayCalendar) error {
+	if calendar.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if calendar.PropertyID == "" {
+		return fmt.Errorf("%w: property_id is required", ErrInvalidInput)
+	}
+
+	now := time.Now()
+	if calendar.CreatedAt.IsZero() {
+		calendar.CreatedAt = now
+	}
+	calendar.UpdatedAt = now
+
+	if err := r.SetDocument(ctx, r.getCalendarsCollection(calendar.TenantID), calendar.PropertyID, calendar); err != nil {
+		return fmt.Errorf("failed to save stay calendar: %w", err)
+	}
+
+	return nil
+}
+
+// Update updates specific fields of a stay calendar
+func (r *StayCalendarRepository) Update(ctx context.Context, tenantID, propertyID string, updates map[string]interface{}) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	updates["updated_at"] = time.Now()
+
+	firestoreUpdates := make([]firestore.Update, 0, len(updates))
+	for key, value := range updates {
+		firestoreUpdates = append(firestoreUpdates, firestore.Update{Path: key, Value: value})
+	}
+
+	if err := r.UpdateDocument(ctx, r.getCalendarsCollection(tenantID), propertyID, firestoreUpdates); err != nil {
+		return fmt.Errorf("failed to update stay calendar: %w", err)
+	}
+
+	return nil
+}
+
+// List retrieves the stay calendars of a tenant
+func (r *StayCalendarRepository) List(ctx context.Context, tenantID string, opts PaginationOptions) ([]*models.StayCalendar, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	if opts.Limit == 0 {
+		opts.Limit = DefaultPaginationOptions().Limit
+	}
+
+	query := r.Client().Collection(r.getCalendarsCollection(tenantID)).Query
+	iter := r.ApplyPagination(query, opts).Documents(ctx)
+	defer iter.Stop()
+
+	calendars := make([]*models.StayCalendar, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate stay calendars: %w", err)
+		}
+
+		var calendar models.StayCalendar
+		if err := doc.DataTo(&calendar); err != nil {
+			return nil, fmt.Errorf("failed to decode stay calendar: %w", err)
+		}
+
+		calendar.PropertyID = doc.Ref.ID
+		calendars = append(calendars, &calendar)
+	}
+
+	return calendars, nil
+}
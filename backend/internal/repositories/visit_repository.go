@@ -0,0 +1,216 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+)
+
+// VisitFilters contains filters for listing visits
+type VisitFilters struct {
+	BrokerID    string
+	PropertyID  string
+	LeadID      string
+	Statuses    []models.VisitStatus // Any of them
+	StartFrom   *time.Time           // start_at >= StartFrom
+	StartBefore *time.Time           // start_at < StartBefore
+}
+
+// VisitRepository handles Firestore operations for property visits
+type VisitRepository struct {
+	*BaseRepository
+}
+
+// NewVisitRepository creates a new visit repository
+func NewVisitRepository(client *firestore.Client) *VisitRepository {
+	return &VisitRepository{
+		BaseRepository: NewBaseRepository(client),
+	}
+}
+
+// getVisitsCollection returns the collection path for visits within a tenant
+func (r *VisitRepository) getVisitsCollection(tenantID string) string {
+	return fmt.Sprintf("tenants/%s/visits", tenantID)
+}
+
+// prepare validates a new visit and sets its ID and timestamps
+func (r *VisitRepository) prepare(visit *models.Visit) error {
+	if visit.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+	if visit.PropertyID == "" {
+		return fmt.Errorf("%w: property_id is required", ErrInvalidInput)
+	}
+
+	if visit.ID == "" {
+		visit.ID = r.GenerateID(r.getVisitsCollection(visit.TenantID))
+	}
+
+	now := time.Now()
+	visit.CreatedAt = now
+	visit.UpdatedAt = now
+	return nil
+}
+
+// Create creates a new visit
+func (r *VisitRepository) Create(ctx context.Context, visit *models.Visit) error {
+	if err := r.prepare(visit); err != nil {
+		return err
+	}
+
+	if err := r.CreateDocument(ctx, r.getVisitsCollection(visit.TenantID), visit.ID, visit); err != nil {
+		return fmt.Errorf("failed to create visit: %w", err)
+	}
+
+	return nil
+}
+
+// CreateIfAvailable creates a visit unless another visit of the same broker
+// holding its slot overlaps it. The check and the write run in one
+// transaction, so concurrent requests for the same slot cannot both
+// succeed. It returns false when the slot is taken.
+func (r *VisitRepository) CreateIfAvailable(ctx context.Context, visit *models.Visit) (bool, error) {
+	if err := r.prepare(visit); err != nil {
+		return false, err
+	}
+
+	collection := r.Client().Collection(r.getVisitsCollection(visit.TenantID))
+	query := collection.
+		Where("broker_id", "==", visit.BrokerID).
+		Where("status", "in", []string{string(models.VisitStatusRequested), string(models.VisitStatusConfirmed)}).
+		Where("start_at", ">=", visit.StartAt.Add(-models.MaxVisitMinutes*time.Minute)).
+		Where("start_at", "<", visit.EndAt)
+
+	created := false
+	err := r.Client().RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		created = false
+
+		docs, err := tx.Documents(query).GetAll()
+		if err != nil {
+			return err
+		}
+		for _, doc := range docs {
+			var other models.Visit
+			if err := doc.DataTo(&other); err != nil {
+				return fmt.Errorf("failed to decode visit: %w", err)
+			}
+			if other.HoldsSlot() && other.Overlaps(visit.StartAt, visit.EndAt) {
+				return nil
+			}
+		}
+
+		created = true
+		return tx.Create(collection.Doc(visit.ID), visit)
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to create visit: %w", err)
+	}
+
+	return created, nil
+}
+
+// Get retrieves a visit by ID
+func (r *VisitRepository) Get(ctx context.Context, tenantID, id string) (*models.Visit, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	var visit models.Visit
+	if err := r.GetDocument(ctx, r.getVisitsCollection(tenantID), id, &visit); err != nil {
+		return nil, err
+	}
+
+	visit.ID = id
+	return &visit, nil
+}
+
+// Update updates specific fields of a visit
+func (r *VisitRepository) Update(ctx context.Context, tenantID, id string, updates map[string]interface{}) error {
+	if tenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	updates["updated_at"] = time.Now()
+
+	firestoreUpdates := make([]firestore.Update, 0, len(updates))
+	for key, value := range updates {
+		firestoreUpdates = append(firestoreUpdates, firestore.Update{Path: key, Value: value})
+	}
+
+	if err := r.UpdateDocument(ctx, r.getVisitsCollection(tenantID), id, firestoreUpdates); err != nil {
+		return fmt.Errorf("failed to update visit: %w", err)
+	}
+
+	return nil
+}
+
+// List retrieves visits with filters, by start time (earliest first) unless
+// opts sets another order
+func (r *VisitRepository) List(ctx context.Context, tenantID string, filters *VisitFilters, opts PaginationOptions) ([]*models.Visit, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("%w: tenant_id is required", ErrInvalidInput)
+	}
+
+	if opts.Limit == 0 {
+		opts.Limit = DefaultPaginationOptions().Limit
+	}
+	if opts.OrderBy == "" {
+		opts.OrderBy = "start_at"
+		opts.Direction = firestore.Asc
+	}
+
+	query := r.Client().Collection(r.getVisitsCollection(tenantID)).Query
+	if filters != nil {
+		if filters.BrokerID != "" {
+			query = query.Where("broker_id", "==", filters.BrokerID)
+		}
+		if filters.PropertyID != "" {
+			query = query.Where("property_id", "==", filters.PropertyID)
+		}
+		if filters.LeadID != "" {
+			query = query.Where("lead_id", "==", filters.LeadID)
+		}
+		if len(filters.Statuses) > 0 {
+			statuses := make([]string, len(filters.Statuses))
+			for i, status := range filters.Statuses {
+				statuses[i] = string(status)
+			}
+			query = query.Where("status", "in", statuses)
+		}
+		if filters.StartFrom != nil {
+			query = query.Where("start_at", ">=", *filters.StartFrom)
+		}
+		if filters.StartBefore != nil {
+			query = query.Where("start_at", "<", *filters.StartBefore)
+		}
+	}
+
+	iter := r.ApplyPagination(query, opts).Documents(ctx)
+	defer iter.Stop()
+
+	visits := make([]*models.Visit, 0)
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to iterate visits: %w", err)
+		}
+
+		var visit models.Visit
+		if err := doc.DataTo(&visit); err != nil {
+			return nil, fmt.Errorf("failed to decode visit: %w", err)
+		}
+
+		visit.ID = doc.Ref.ID
+		visits = append(visits, &visit)
+	}
+
+	return visits, nil
+}
@@ -0,0 +1,78 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron_Invalid(t *testing.T) {
+	tests := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"@every",
+	}
+
+	for _, expr := range tests {
+		t.Run(expr, func(t *testing.T) {
+			if _, err := ParseCron(expr); err == nil {
+				t.Errorf("ParseCron(%q) expected error", expr)
+			}
+		})
+	}
+}
+
+func TestSchedule_Next(t *testing.T) {
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+
+	// Friday, 2026-01-16 10:30 in São Paulo
+	from := time.Date(2026, 1, 16, 10, 30, 0, 0, saoPaulo)
+
+	tests := []struct {
+		name string
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		{"every minute", "* * * * *", from, time.Date(2026, 1, 16, 10, 31, 0, 0, saoPaulo)},
+		{"strictly after", "30 10 * * *", from, time.Date(2026, 1, 17, 10, 30, 0, 0, saoPaulo)},
+		{"daily later today", "0 15 * * *", from, time.Date(2026, 1, 16, 15, 0, 0, 0, saoPaulo)},
+		{"daily tomorrow", "0 3 * * *", from, time.Date(2026, 1, 17, 3, 0, 0, 0, saoPaulo)},
+		{"step minutes", "*/20 * * * *", from, time.Date(2026, 1, 16, 10, 40, 0, 0, saoPaulo)},
+		{"range and step", "0 8-18/4 * * *", from, time.Date(2026, 1, 16, 12, 0, 0, 0, saoPaulo)},
+		{"list", "0 9 1,25 * *", from, time.Date(2026, 1, 25, 9, 0, 0, 0, saoPaulo)},
+		{"month rollover", "0 8 25 * *", time.Date(2026, 1, 26, 0, 0, 0, 0, saoPaulo), time.Date(2026, 2, 25, 8, 0, 0, 0, saoPaulo)},
+		{"year rollover", "0 0 1 1 *", from, time.Date(2027, 1, 1, 0, 0, 0, 0, saoPaulo)},
+		{"weekday", "0 9 * * 1-5", time.Date(2026, 1, 17, 0, 0, 0, 0, saoPaulo), time.Date(2026, 1, 19, 9, 0, 0, 0, saoPaulo)},
+		{"sunday as 7", "0 9 * * 7", from, time.Date(2026, 1, 18, 9, 0, 0, 0, saoPaulo)},
+		{"day of month or week", "0 9 13 * 1", from, time.Date(2026, 1, 19, 9, 0, 0, 0, saoPaulo)},
+		{"leap day", "0 0 29 2 *", from, time.Date(2028, 2, 29, 0, 0, 0, 0, saoPaulo)},
+		{"descriptor", "@monthly", from, time.Date(2026, 2, 1, 0, 0, 0, 0, saoPaulo)},
+		{"never matches", "0 0 31 2 *", from, time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseCron(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseCron(%q) error = %v", tt.expr, err)
+			}
+
+			got := schedule.Next(tt.from)
+			if !got.Equal(tt.want) {
+				t.Errorf("Next(%s) = %s, want %s", tt.from, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,421 @@
+// Package scheduler runs background jobs on cron schedules inside the API
+// server. Every job is fanned out to the active tenants, a distributed lock
+// makes each scheduled occurrence run on a single server instance, and every
+// run is recorded with its status and duration.
+package scheduler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+var (
+	// ErrUnknownJob is returned for job names that were never registered
+	ErrUnknownJob = errors.New("unknown job")
+
+	// ErrJobLocked is returned when the job is already running (here or on another instance)
+	ErrJobLocked = errors.New("job is already running")
+)
+
+// DefaultJobTimeout bounds a run (and its lock lease) when Job.Timeout is zero
+const DefaultJobTimeout = 30 * time.Minute
+
+// tenantPageSize is the page size used to list the active tenants
+const tenantPageSize = 100
+
+// TenantFunc runs a job for a single tenant
+type TenantFunc func(ctx context.Context, tenantID string) error
+
+// Job is a background job run for every active tenant on a cron schedule
+type Job struct {
+	Name        string
+	Description string
+	Schedule    string        // Cron expression (see ParseCron)
+	Timeout     time.Duration // Run deadline and lock lease (default DefaultJobTimeout)
+	Run         TenantFunc
+}
+
+// JobStatus describes a registered job and its latest run
+type JobStatus struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Schedule    string         `json:"schedule"`
+	NextRunAt   *time.Time     `json:"next_run_at,omitempty"`
+	Running     bool           `json:"running"` // Running on this instance
+	LastRun     *models.JobRun `json:"last_run,omitempty"`
+}
+
+type registeredJob struct {
+	Job
+	schedule *Schedule
+}
+
+// Scheduler runs the registered jobs on their schedules
+type Scheduler struct {
+	tenantRepo repositories.TenantStore
+	lockRepo   repositories.JobLockStore
+	runRepo    repositories.JobRunStore
+	instance   string
+	location   *time.Location
+
+	mu      sync.Mutex
+	jobs    map[string]*registeredJob
+	order   []string
+	running map[string]bool
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// NewScheduler creates a scheduler; jobs must be registered before Start
+func NewScheduler(
+	tenantRepo repositories.TenantStore,
+	lockRepo repositories.JobLockStore,
+	runRepo repositories.JobRunStore,
+) *Scheduler {
+	return &Scheduler{
+		tenantRepo: tenantRepo,
+		lockRepo:   lockRepo,
+		runRepo:    runRepo,
+		instance:   newInstanceID(),
+		location:   time.Local,
+		jobs:       make(map[string]*registeredJob),
+		running:    make(map[string]bool),
+		ctx:        context.Background(),
+	}
+}
+
+// SetLocation sets the time zone cron expressions are evaluated in (default: local time)
+func (s *Scheduler) SetLocation(location *time.Location) {
+	s.location = location
+}
+
+// Register adds a job to the scheduler
+func (s *Scheduler) Register(job Job) error {
+	if job.Name == "" {
+		return fmt.Errorf("job name is required")
+	}
+	if job.Run == nil {
+		return fmt.Errorf("job %s: run function is required", job.Name)
+	}
+
+	schedule, err := ParseCron(job.Schedule)
+	if err != nil {
+		return fmt.Errorf("job %s: %w", job.Name, err)
+	}
+
+	if job.Timeout <= 0 {
+		job.Timeout = DefaultJobTimeout
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.jobs[job.Name]; exists {
+		return fmt.Errorf("job %s is already registered", job.Name)
+	}
+
+	s.jobs[job.Name] = &registeredJob{Job: job, schedule: schedule}
+	s.order = append(s.order, job.Name)
+	return nil
+}
+
+// Start runs every registered job on its schedule until Stop is called
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	s.ctx, s.cancel = context.WithCancel(ctx)
+	jobs := make([]*registeredJob, 0, len(s.order))
+	for _, name := range s.order {
+		jobs = append(jobs, s.jobs[name])
+	}
+	s.mu.Unlock()
+
+	for _, job := range jobs {
+		s.wg.Add(1)
+		go s.loop(s.ctx, job)
+	}
+
+	log.Printf("⏰ Scheduler started with %d jobs (instance %s)", len(jobs), s.instance)
+}
+
+// Stop stops the schedules and waits for the runs in progress
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	cancel := s.cancel
+	s.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	s.wg.Wait()
+}
+
+// loop waits for each occurrence of the job and runs it
+func (s *Scheduler) loop(ctx context.Context, job *registeredJob) {
+	defer s.wg.Done()
+
+	for {
+		next := job.schedule.Next(time.Now().In(s.location))
+		if next.IsZero() {
+			log.Printf("⚠️  Job %s: schedule %q never matches, not scheduling", job.Name, job.Schedule)
+			return
+		}
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		run, err := s.claim(ctx, job, next, models.JobTriggerSchedule)
+		if err != nil {
+			if !errors.Is(err, ErrJobLocked) {
+				log.Printf("❌ Job %s: failed to start scheduled run: %v", job.Name, err)
+			}
+			continue
+		}
+		s.execute(ctx, job, run)
+	}
+}
+
+// RunNow starts a manual run of the job in the background and returns the run record
+func (s *Scheduler) RunNow(ctx context.Context, name string) (*models.JobRun, error) {
+	job, err := s.job(name)
+	if err != nil {
+		return nil, err
+	}
+
+	run, err := s.claim(ctx, job, time.Now(), models.JobTriggerManual)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	runCtx := s.ctx
+	s.mu.Unlock()
+
+	result := *run
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.execute(runCtx, job, run)
+	}()
+
+	return &result, nil
+}
+
+// Jobs returns the registered jobs with their next and latest runs
+func (s *Scheduler) Jobs(ctx context.Context) ([]JobStatus, error) {
+	s.mu.Lock()
+	jobs := make([]*registeredJob, 0, len(s.order))
+	for _, name := range s.order {
+		jobs = append(jobs, s.jobs[name])
+	}
+	s.mu.Unlock()
+
+	statuses := make([]JobStatus, 0, len(jobs))
+	for _, job := range jobs {
+		status := JobStatus{
+			Name:        job.Name,
+			Description: job.Description,
+			Schedule:    job.Schedule,
+			Running:     s.isRunning(job.Name),
+		}
+		if next := job.schedule.Next(time.Now().In(s.location)); !next.IsZero() {
+			status.NextRunAt = &next
+		}
+
+		runs, err := s.runRepo.ListByJob(ctx, job.Name, 1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load runs of job %s: %w", job.Name, err)
+		}
+		if len(runs) > 0 {
+			status.LastRun = runs[0]
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// Runs returns the latest runs of a job, newest first
+func (s *Scheduler) Runs(ctx context.Context, name string, limit int) ([]*models.JobRun, error) {
+	if _, err := s.job(name); err != nil {
+		return nil, err
+	}
+	return s.runRepo.ListByJob(ctx, name, limit)
+}
+
+// job returns a registered job by name
+func (s *Scheduler) job(name string) (*registeredJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownJob, name)
+	}
+	return job, nil
+}
+
+// isRunning reports whether the job is running on this instance
+func (s *Scheduler) isRunning(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.running[name]
+}
+
+// claim takes the job lock for the occurrence scheduled at scheduledAt and
+// records the run as started. It fails with ErrJobLocked when the job is
+// running on this instance or the occurrence was claimed by another one.
+func (s *Scheduler) claim(ctx context.Context, job *registeredJob, scheduledAt time.Time, trigger models.JobTrigger) (*models.JobRun, error) {
+	s.mu.Lock()
+	if s.running[job.Name] {
+		s.mu.Unlock()
+		return nil, ErrJobLocked
+	}
+	s.running[job.Name] = true
+	s.mu.Unlock()
+
+	acquired, err := s.lockRepo.Acquire(ctx, job.Name, s.instance, scheduledAt, job.Timeout)
+	if err != nil || !acquired {
+		s.setRunning(job.Name, false)
+		if err != nil {
+			return nil, err
+		}
+		return nil, ErrJobLocked
+	}
+
+	run := &models.JobRun{
+		Job:         job.Name,
+		Status:      models.JobRunStatusRunning,
+		Trigger:     trigger,
+		Instance:    s.instance,
+		ScheduledAt: scheduledAt,
+		StartedAt:   time.Now(),
+	}
+	if err := s.runRepo.Create(ctx, run); err != nil {
+		log.Printf("⚠️  Job %s: failed to record run: %v", job.Name, err)
+	}
+
+	return run, nil
+}
+
+// execute fans the job out to every active tenant, records the outcome and
+// releases the lock taken by claim
+func (s *Scheduler) execute(ctx context.Context, job *registeredJob, run *models.JobRun) {
+	defer s.setRunning(job.Name, false)
+
+	ctx, cancel := context.WithTimeout(ctx, job.Timeout)
+	defer cancel()
+
+	log.Printf("🚀 Job %s started (%s)", job.Name, run.Trigger)
+
+	tenantIDs, err := s.activeTenantIDs(ctx)
+	if err != nil {
+		run.Error = err.Error()
+	}
+
+	run.TenantsTotal = len(tenantIDs)
+	for _, tenantID := range tenantIDs {
+		if err := s.runTenant(ctx, job, tenantID); err != nil {
+			run.TenantsFailed++
+			run.Failures = append(run.Failures, models.JobTenantFailure{TenantID: tenantID, Error: err.Error()})
+			log.Printf("❌ Job %s failed for tenant %s: %v", job.Name, tenantID, err)
+			continue
+		}
+		run.TenantsSucceeded++
+	}
+
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	run.DurationMs = finishedAt.Sub(run.StartedAt).Milliseconds()
+	switch {
+	case run.Error != "" || (run.TenantsTotal > 0 && run.TenantsFailed == run.TenantsTotal):
+		run.Status = models.JobRunStatusFailed
+	case run.TenantsFailed > 0:
+		run.Status = models.JobRunStatusPartial
+	default:
+		run.Status = models.JobRunStatusSucceeded
+	}
+
+	// Record the outcome even when the run was cancelled by shutdown
+	recordCtx := context.WithoutCancel(ctx)
+	if err := s.runRepo.Update(recordCtx, run); err != nil {
+		log.Printf("⚠️  Job %s: failed to record run outcome: %v", job.Name, err)
+	}
+	if err := s.lockRepo.Release(recordCtx, job.Name, s.instance); err != nil {
+		log.Printf("⚠️  Job %s: failed to release lock: %v", job.Name, err)
+	}
+
+	log.Printf("📊 Job %s %s: %d/%d tenants succeeded in %dms", job.Name, run.Status, run.TenantsSucceeded, run.TenantsTotal, run.DurationMs)
+}
+
+// runTenant runs the job for one tenant, turning panics into errors so a
+// single tenant cannot stop the fan-out
+func (s *Scheduler) runTenant(ctx context.Context, job *registeredJob, tenantID string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return job.Run(ctx, tenantID)
+}
+
+// activeTenantIDs lists the IDs of every active tenant
+func (s *Scheduler) activeTenantIDs(ctx context.Context) ([]string, error) {
+	var ids []string
+	opts := repositories.PaginationOptions{Limit: tenantPageSize}
+
+	for {
+		tenants, err := s.tenantRepo.ListActive(ctx, opts)
+		if err != nil {
+			return ids, fmt.Errorf("failed to list active tenants: %w", err)
+		}
+		for _, tenant := range tenants {
+			ids = append(ids, tenant.ID)
+		}
+		if len(tenants) < opts.Limit {
+			return ids, nil
+		}
+		opts.Offset += len(tenants)
+	}
+}
+
+// setRunning marks the job as running (or not) on this instance
+func (s *Scheduler) setRunning(name string, running bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.running[name] = running
+}
+
+// newInstanceID identifies this server instance in locks and run history
+func newInstanceID() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "instance"
+	}
+
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return host
+	}
+	return host + "-" + hex.EncodeToString(b)
+}
@@ -0,0 +1,151 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories/memory"
+)
+
+// tenantRecorder records the tenants a job ran for
+type tenantRecorder struct {
+	mu      sync.Mutex
+	tenants []string
+}
+
+func (r *tenantRecorder) run(ctx context.Context, tenantID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tenants = append(r.tenants, tenantID)
+	if tenantID == "tenant-b" {
+		return errors.New("boom")
+	}
+	return nil
+}
+
+func newTestTenants(t *testing.T) *memory.TenantRepository {
+	t.Helper()
+	ctx := context.Background()
+
+	tenantRepo := memory.NewTenantRepository()
+	require.NoError(t, tenantRepo.Create(ctx, &models.Tenant{ID: "tenant-a", Name: "A", IsActive: true}))
+	require.NoError(t, tenantRepo.Create(ctx, &models.Tenant{ID: "tenant-b", Name: "B", IsActive: true}))
+	require.NoError(t, tenantRepo.Create(ctx, &models.Tenant{ID: "tenant-c", Name: "C", IsActive: false}))
+	return tenantRepo
+}
+
+func TestScheduler_RunRecordsHistory(t *testing.T) {
+	ctx := context.Background()
+	recorder := &tenantRecorder{}
+
+	s := NewScheduler(newTestTenants(t), memory.NewJobLockRepository(), memory.NewJobRunRepository())
+	require.NoError(t, s.Register(Job{Name: "staleness", Schedule: "@daily", Run: recorder.run}))
+
+	job, err := s.job("staleness")
+	require.NoError(t, err)
+
+	run, err := s.claim(ctx, job, time.Now(), models.JobTriggerSchedule)
+	require.NoError(t, err)
+	s.execute(ctx, job, run)
+
+	// Inactive tenants are skipped
+	assert.ElementsMatch(t, []string{"tenant-a", "tenant-b"}, recorder.tenants)
+
+	runs, err := s.Runs(ctx, "staleness", 10)
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	assert.Equal(t, models.JobRunStatusPartial, runs[0].Status)
+	assert.Equal(t, models.JobTriggerSchedule, runs[0].Trigger)
+	assert.Equal(t, 2, runs[0].TenantsTotal)
+	assert.Equal(t, 1, runs[0].TenantsSucceeded)
+	assert.Equal(t, 1, runs[0].TenantsFailed)
+	require.Len(t, runs[0].Failures, 1)
+	assert.Equal(t, "tenant-b", runs[0].Failures[0].TenantID)
+	assert.NotNil(t, runs[0].FinishedAt)
+
+	jobs, err := s.Jobs(ctx)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.False(t, jobs[0].Running)
+	assert.NotNil(t, jobs[0].NextRunAt)
+	require.NotNil(t, jobs[0].LastRun)
+	assert.Equal(t, runs[0].ID, jobs[0].LastRun.ID)
+}
+
+func TestScheduler_OccurrenceRunsOnce(t *testing.T) {
+	ctx := context.Background()
+	tenantRepo := newTestTenants(t)
+	lockRepo := memory.NewJobLockRepository()
+	runRepo := memory.NewJobRunRepository()
+	recorder := &tenantRecorder{}
+
+	// Two server instances sharing the lock store
+	first := NewScheduler(tenantRepo, lockRepo, runRepo)
+	second := NewScheduler(tenantRepo, lockRepo, runRepo)
+	for _, s := range []*Scheduler{first, second} {
+		require.NoError(t, s.Register(Job{Name: "staleness", Schedule: "@daily", Run: recorder.run}))
+	}
+
+	occurrence := time.Date(2026, 1, 16, 3, 0, 0, 0, time.UTC)
+	firstJob, _ := first.job("staleness")
+	secondJob, _ := second.job("staleness")
+
+	run, err := first.claim(ctx, firstJob, occurrence, models.JobTriggerSchedule)
+	require.NoError(t, err)
+
+	// Held lock
+	_, err = second.claim(ctx, secondJob, occurrence, models.JobTriggerSchedule)
+	assert.ErrorIs(t, err, ErrJobLocked)
+
+	first.execute(ctx, firstJob, run)
+
+	// Released, but the occurrence was already claimed
+	_, err = second.claim(ctx, secondJob, occurrence, models.JobTriggerSchedule)
+	assert.ErrorIs(t, err, ErrJobLocked)
+
+	// The next occurrence can be claimed by any instance
+	run, err = second.claim(ctx, secondJob, occurrence.AddDate(0, 0, 1), models.JobTriggerSchedule)
+	require.NoError(t, err)
+	second.execute(ctx, secondJob, run)
+
+	runs, err := first.Runs(ctx, "staleness", 10)
+	require.NoError(t, err)
+	assert.Len(t, runs, 2)
+	assert.Len(t, recorder.tenants, 4)
+}
+
+func TestScheduler_RunNow(t *testing.T) {
+	ctx := context.Background()
+	s := NewScheduler(newTestTenants(t), memory.NewJobLockRepository(), memory.NewJobRunRepository())
+
+	panics := func(ctx context.Context, tenantID string) error {
+		panic("unexpected")
+	}
+	require.NoError(t, s.Register(Job{Name: "confirmations", Schedule: "0 9 * * *", Run: panics}))
+	assert.Error(t, s.Register(Job{Name: "confirmations", Schedule: "0 9 * * *", Run: panics}))
+	assert.Error(t, s.Register(Job{Name: "invalid", Schedule: "0 25 * * *", Run: panics}))
+
+	_, err := s.RunNow(ctx, "missing")
+	assert.ErrorIs(t, err, ErrUnknownJob)
+
+	run, err := s.RunNow(ctx, "confirmations")
+	require.NoError(t, err)
+	assert.Equal(t, models.JobTriggerManual, run.Trigger)
+	assert.Equal(t, models.JobRunStatusRunning, run.Status)
+
+	s.Stop() // waits for the manual run
+
+	runs, err := s.Runs(ctx, "confirmations", 1)
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	assert.Equal(t, models.JobRunStatusFailed, runs[0].Status)
+	assert.Equal(t, 2, runs[0].TenantsFailed)
+	assert.Contains(t, runs[0].Failures[0].Error, "panic")
+}
@@ -0,0 +1,110 @@
+// Package search provides the embedded full-text index used for property search.
+//
+// Text is analyzed the same way at index and query time: lowercased,
+// accent-folded, split on anything that is not a letter or digit, stripped of
+// Portuguese stopwords and reduced with a light Portuguese stemmer.
+package search
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// stopwords are common Portuguese words that carry no search value
+var stopwords = map[string]bool{
+	"a": true, "o": true, "as": true, "os": true, "e": true, "ou": true,
+	"de": true, "da": true, "do": true, "das": true, "dos": true,
+	"em": true, "no": true, "na": true, "nos": true, "nas": true,
+	"um": true, "uma": true, "uns": true, "umas": true,
+	"ao": true, "aos": true, "com": true, "sem": true, "sob": true,
+	"para": true, "pra": true, "por": true, "pelo": true, "pela": true, "pelos": true, "pelas": true,
+	"que": true, "se": true, "mais": true, "muito": true, "ate": true,
+}
+
+// pluralSuffixes maps Portuguese plural endings to their singular form
+// (applied after accent folding, longest match first)
+var pluralSuffixes = []struct{ suffix, replacement string }{
+	{"oes", "ao"}, // apartamentões -> apartamentão
+	{"aes", "ao"}, // pães -> pão
+	{"ais", "al"}, // comerciais -> comercial
+	{"eis", "el"}, // imóveis -> imóvel
+	{"ois", "ol"}, // lençóis -> lençol
+	{"ns", "m"},   // jardins -> jardim
+	{"res", "r"},  // andares -> andar
+	{"zes", "z"},  // luzes -> luz
+}
+
+// Normalize lowercases and removes accents from text
+func Normalize(text string) string {
+	t := transform.Chain(norm.NFD, transform.RemoveFunc(func(r rune) bool {
+		return unicode.Is(unicode.Mn, r)
+	}), norm.NFC)
+
+	result, _, err := transform.String(t, text)
+	if err != nil {
+		result = text
+	}
+
+	return strings.ToLower(result)
+}
+
+// Tokenize splits normalized text into words (letters and digits only)
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(Normalize(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Compact removes separators from codes such as property references, so that
+// "AP-00335" can also be found as "AP00335"
+func Compact(text string) string {
+	return strings.Join(Tokenize(text), "")
+}
+
+// Analyze tokenizes text, drops stopwords and stems the remaining terms
+func Analyze(text string) []string {
+	tokens := Tokenize(text)
+	terms := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		if stopwords[token] {
+			continue
+		}
+		terms = append(terms, Stem(token))
+	}
+	return terms
+}
+
+// Stem reduces a normalized Portuguese word to a search stem: plural endings
+// are singularized and the final gender vowel is dropped, so "casa", "casas",
+// "mobiliado" and "mobiliada" share stems. Tokens containing digits (property
+// references such as "ap00335") and short words are returned unchanged.
+func Stem(token string) string {
+	if len(token) <= 3 || strings.IndexFunc(token, unicode.IsDigit) >= 0 {
+		return token
+	}
+
+	stem := token
+	singular := false
+	for _, rule := range pluralSuffixes {
+		if strings.HasSuffix(stem, rule.suffix) && len(stem)-len(rule.suffix) >= 2 {
+			stem = stem[:len(stem)-len(rule.suffix)] + rule.replacement
+			singular = true
+			break
+		}
+	}
+	if !singular && strings.HasSuffix(stem, "s") && !strings.HasSuffix(stem, "ss") && !strings.HasSuffix(stem, "us") {
+		stem = stem[:len(stem)-1]
+	}
+
+	if len(stem) > 4 {
+		switch stem[len(stem)-1] {
+		case 'a', 'e', 'o':
+			stem = stem[:len(stem)-1]
+		}
+	}
+
+	return stem
+}
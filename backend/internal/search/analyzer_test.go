@@ -0,0 +1,48 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStem(t *testing.T) {
+	tests := []struct {
+		name  string
+		token string
+		want  string
+	}{
+		{"Plural -eis", "imoveis", "imovel"},
+		{"Plural -ns", "jardins", "jardim"},
+		{"Plural -res", "andares", "andar"},
+		{"Plural -ais", "comerciais", "comercial"},
+		{"Plural -s and gender vowel", "apartamentos", "apartament"},
+		{"Singular gender vowel", "apartamento", "apartament"},
+		{"Feminine", "mobiliada", "mobiliad"},
+		{"Masculine", "mobiliado", "mobiliad"},
+		{"Short word unchanged", "sol", "sol"},
+		{"Reference unchanged", "ap00335", "ap00335"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Stem(tt.token); got != tt.want {
+				t.Errorf("Stem(%q) = %q, want %q", tt.token, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	got := Analyze("Apartamento com 3 Quartos na Vila Mariana - São Paulo (AP-00335)")
+	want := []string{"apartament", "3", "quart", "vila", "marian", "sao", "paul", "ap", "00335"}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Analyze() = %v, want %v", got, want)
+	}
+}
+
+func TestAnalyze_AccentInsensitive(t *testing.T) {
+	if a, b := Analyze("Imóveis em Maringá"), Analyze("imoveis em maringa"); !reflect.DeepEqual(a, b) {
+		t.Errorf("Analyze() accented = %v, unaccented = %v", a, b)
+	}
+}
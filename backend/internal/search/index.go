@@ -0,0 +1,305 @@
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Field weights used when building property documents
+const (
+	WeightReference   = 4.0
+	WeightTitle       = 3.0
+	WeightAddress     = 2.0
+	WeightDescription = 1.0
+)
+
+// Match quality factors applied to a term's score
+const (
+	exactMatchFactor  = 1.0
+	prefixMatchFactor = 0.8
+	fuzzyMatchFactor  = 0.5
+)
+
+// Field is a piece of text indexed with a weight
+type Field struct {
+	Text   string
+	Weight float64
+}
+
+// Document is a searchable entity scoped to a tenant
+type Document struct {
+	ID       string
+	TenantID string
+	Fields   []Field
+	Payload  interface{} // Returned with the document's hits (ex: attributes to filter on)
+}
+
+// Hit is a search result, ordered by descending Score
+type Hit struct {
+	ID       string
+	TenantID string
+	Score    float64
+	Payload  interface{}
+}
+
+// docRef identifies a document across tenants
+type docRef struct {
+	tenantID string
+	id       string
+}
+
+// Index is a thread-safe in-memory inverted index.
+// Every query term must match a document (AND semantics); a term matches
+// exactly, as a prefix (last query term only) or within a small edit distance.
+type Index struct {
+	mu         sync.RWMutex
+	postings   map[string]map[docRef]float64 // term -> doc -> weighted term frequency
+	docTerms   map[docRef][]string           // doc -> distinct terms (for removal)
+	payloads   map[docRef]interface{}        // doc -> payload
+	docGens    map[docRef]uint64             // doc -> generation of its last upsert
+	generation uint64
+}
+
+// NewIndex creates an empty index
+func NewIndex() *Index {
+	return &Index{
+		postings: make(map[string]map[docRef]float64),
+		docTerms: make(map[docRef][]string),
+		payloads: make(map[docRef]interface{}),
+		docGens:  make(map[docRef]uint64),
+	}
+}
+
+// Upsert indexes a document, replacing any previous version
+func (idx *Index) Upsert(doc Document) {
+	ref := docRef{tenantID: doc.TenantID, id: doc.ID}
+
+	weights := make(map[string]float64)
+	for _, field := range doc.Fields {
+		for _, term := range Analyze(field.Text) {
+			weights[term] += field.Weight
+		}
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.removeLocked(ref)
+
+	terms := make([]string, 0, len(weights))
+	for term, weight := range weights {
+		docs, ok := idx.postings[term]
+		if !ok {
+			docs = make(map[docRef]float64)
+			idx.postings[term] = docs
+		}
+		docs[ref] = weight
+		terms = append(terms, term)
+	}
+	idx.docTerms[ref] = terms
+	idx.docGens[ref] = idx.generation
+	if doc.Payload != nil {
+		idx.payloads[ref] = doc.Payload
+	}
+}
+
+// Remove deletes a document from the index
+func (idx *Index) Remove(tenantID, id string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.removeLocked(docRef{tenantID: tenantID, id: id})
+}
+
+// BeginGeneration starts a rebuild: documents upserted from now on belong to
+// the returned generation, so Sweep can drop the ones the rebuild did not see
+func (idx *Index) BeginGeneration() uint64 {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.generation++
+	return idx.generation
+}
+
+// Sweep removes the documents last upserted before generation and returns how many were removed
+func (idx *Index) Sweep(generation uint64) int {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	removed := 0
+	for ref, gen := range idx.docGens {
+		if gen < generation {
+			idx.removeLocked(ref)
+			removed++
+		}
+	}
+	return removed
+}
+
+// Len returns the number of indexed documents
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	return len(idx.docTerms)
+}
+
+// Search returns documents matching every term of query, best first.
+// An empty tenantID searches all tenants. limit <= 0 returns every hit.
+func (idx *Index) Search(tenantID, query string, limit int) []Hit {
+	terms := Analyze(query)
+	if len(terms) == 0 {
+		return []Hit{}
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	total := float64(len(idx.docTerms))
+	var scores map[docRef]float64
+
+	for i, term := range terms {
+		allowPrefix := i == len(terms)-1 && len(term) >= 3
+		termScores := make(map[docRef]float64)
+
+		for candidate, factor := range idx.expandLocked(term, allowPrefix) {
+			docs := idx.postings[candidate]
+			idf := math.Log(1 + total/float64(len(docs)))
+			for ref, weight := range docs {
+				if tenantID != "" && ref.tenantID != tenantID {
+					continue
+				}
+				if score := weight * factor * idf; score > termScores[ref] {
+					termScores[ref] = score
+				}
+			}
+		}
+
+		if scores == nil {
+			scores = termScores
+			continue
+		}
+		for ref := range scores {
+			termScore, ok := termScores[ref]
+			if !ok {
+				delete(scores, ref)
+				continue
+			}
+			scores[ref] += termScore
+		}
+	}
+
+	hits := make([]Hit, 0, len(scores))
+	for ref, score := range scores {
+		hits = append(hits, Hit{ID: ref.id, TenantID: ref.tenantID, Score: score, Payload: idx.payloads[ref]})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		if hits[i].TenantID != hits[j].TenantID {
+			return hits[i].TenantID < hits[j].TenantID
+		}
+		return hits[i].ID < hits[j].ID
+	})
+
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
+
+// expandLocked returns the indexed terms matching a query term with their match factor
+func (idx *Index) expandLocked(term string, allowPrefix bool) map[string]float64 {
+	matches := make(map[string]float64)
+	if _, ok := idx.postings[term]; ok {
+		matches[term] = exactMatchFactor
+	}
+
+	maxEdits := maxTypos(term)
+	if !allowPrefix && maxEdits == 0 {
+		return matches
+	}
+
+	for candidate := range idx.postings {
+		if candidate == term {
+			continue
+		}
+		if allowPrefix && strings.HasPrefix(candidate, term) {
+			matches[candidate] = prefixMatchFactor
+			continue
+		}
+		if maxEdits > 0 && withinEditDistance(term, candidate, maxEdits) {
+			matches[candidate] = fuzzyMatchFactor
+		}
+	}
+	return matches
+}
+
+// removeLocked deletes a document's postings; the caller must hold the write lock
+func (idx *Index) removeLocked(ref docRef) {
+	for _, term := range idx.docTerms[ref] {
+		docs := idx.postings[term]
+		delete(docs, ref)
+		if len(docs) == 0 {
+			delete(idx.postings, term)
+		}
+	}
+	delete(idx.docTerms, ref)
+	delete(idx.payloads, ref)
+	delete(idx.docGens, ref)
+}
+
+// maxTypos returns how many edits a query term tolerates
+func maxTypos(term string) int {
+	switch n := len([]rune(term)); {
+	case n >= 8:
+		return 2
+	case n >= 4:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// withinEditDistance reports whether the Levenshtein distance between a and b is at most maxEdits
+func withinEditDistance(a, b string, maxEdits int) bool {
+	ra, rb := []rune(a), []rune(b)
+	if abs(len(ra)-len(rb)) > maxEdits {
+		return false
+	}
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			rowMin = min(rowMin, curr[j])
+		}
+		if rowMin > maxEdits {
+			return false
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)] <= maxEdits
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
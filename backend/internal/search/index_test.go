@@ -0,0 +1,115 @@
+package search
+
+import "testing"
+
+func newTestIndex() *Index {
+	idx := NewIndex()
+	idx.Upsert(Document{ID: "p1", TenantID: "t1", Fields: []Field{
+		{Text: "Apartamento mobiliado com vista para o parque", Weight: WeightTitle},
+		{Text: "Rua das Flores Vila Mariana São Paulo", Weight: WeightAddress},
+		{Text: "AP00335", Weight: WeightReference},
+	}})
+	idx.Upsert(Document{ID: "p2", TenantID: "t1", Fields: []Field{
+		{Text: "Casa térrea com jardim", Weight: WeightTitle},
+		{Text: "Moema São Paulo", Weight: WeightAddress},
+	}})
+	idx.Upsert(Document{ID: "p3", TenantID: "t2", Fields: []Field{
+		{Text: "Apartamentos na planta", Weight: WeightTitle},
+		{Text: "Centro Curitiba", Weight: WeightAddress},
+	}})
+	return idx
+}
+
+func hitIDs(hits []Hit) []string {
+	ids := make([]string, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.ID
+	}
+	return ids
+}
+
+func TestIndex_Search(t *testing.T) {
+	idx := newTestIndex()
+
+	tests := []struct {
+		name     string
+		tenantID string
+		query    string
+		want     []string
+	}{
+		{"Accent insensitive", "t1", "terrea", []string{"p2"}},
+		{"Stemming plural", "t1", "apartamentos", []string{"p1"}},
+		{"Typo tolerance", "t1", "mobilado", []string{"p1"}},
+		{"Prefix on last term", "t1", "vila mar", []string{"p1"}},
+		{"Reference", "t1", "ap00335", []string{"p1"}},
+		{"All terms must match", "t1", "casa parque", []string{}},
+		{"Tenant isolation", "t2", "jardim", []string{}},
+		{"Cross-tenant search", "", "apartamento", []string{"p1", "p3"}},
+		{"Stopwords only", "t1", "de para com", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := hitIDs(idx.Search(tt.tenantID, tt.query, 0))
+			if len(got) != len(tt.want) {
+				t.Fatalf("Search(%q, %q) = %v, want %v", tt.tenantID, tt.query, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("Search(%q, %q) = %v, want %v", tt.tenantID, tt.query, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestIndex_UpsertReplacesAndRemove(t *testing.T) {
+	idx := newTestIndex()
+
+	idx.Upsert(Document{ID: "p2", TenantID: "t1", Fields: []Field{{Text: "Sobrado reformado", Weight: WeightTitle}}, Payload: "sold"})
+	if hits := idx.Search("t1", "jardim", 0); len(hits) != 0 {
+		t.Errorf("old terms still indexed after upsert: %v", hitIDs(hits))
+	}
+	if hits := idx.Search("t1", "sobrado", 0); len(hits) != 1 {
+		t.Errorf("new terms not indexed after upsert: %v", hitIDs(hits))
+	} else if hits[0].Payload != "sold" {
+		t.Errorf("hit payload = %v, want the document's", hits[0].Payload)
+	}
+
+	idx.Remove("t1", "p2")
+	if hits := idx.Search("t1", "sobrado", 0); len(hits) != 0 {
+		t.Errorf("document still indexed after remove: %v", hitIDs(hits))
+	}
+	if idx.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", idx.Len())
+	}
+}
+
+func TestIndex_SweepDropsDocumentsNotRebuilt(t *testing.T) {
+	idx := newTestIndex()
+
+	gen := idx.BeginGeneration()
+	idx.Upsert(Document{ID: "p1", TenantID: "t1", Fields: []Field{{Text: "Apartamento mobiliado", Weight: WeightTitle}}})
+	idx.Upsert(Document{ID: "p3", TenantID: "t2", Fields: []Field{{Text: "Apartamentos na planta", Weight: WeightTitle}}})
+
+	if removed := idx.Sweep(gen); removed != 1 {
+		t.Errorf("Sweep() = %d, want 1", removed)
+	}
+	if hits := idx.Search("t1", "jardim", 0); len(hits) != 0 {
+		t.Errorf("document not rebuilt still indexed: %v", hitIDs(hits))
+	}
+	if idx.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", idx.Len())
+	}
+}
+
+func TestIndex_RanksTitleAboveDescription(t *testing.T) {
+	idx := NewIndex()
+	idx.Upsert(Document{ID: "desc", TenantID: "t1", Fields: []Field{{Text: "cobertura", Weight: WeightDescription}}})
+	idx.Upsert(Document{ID: "title", TenantID: "t1", Fields: []Field{{Text: "cobertura", Weight: WeightTitle}}})
+
+	hits := idx.Search("t1", "cobertura", 0)
+	if len(hits) != 2 || hits[0].ID != "title" {
+		t.Errorf("Search() = %v, want title match first", hitIDs(hits))
+	}
+}
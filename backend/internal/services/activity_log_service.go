@@ -13,14 +13,14 @@ import (
 
 // ActivityLogService handles business logic for activity logging
 type ActivityLogService struct {
-	activityLogRepo *repositories.ActivityLogRepository
-	tenantRepo      *repositories.TenantRepository
+	activityLogRepo repositories.ActivityLogStore
+	tenantRepo      repositories.TenantStore
 }
 
 // NewActivityLogService creates a new activity log service
 func NewActivityLogService(
-	activityLogRepo *repositories.ActivityLogRepository,
-	tenantRepo *repositories.TenantRepository,
+	activityLogRepo repositories.ActivityLogStore,
+	tenantRepo repositories.TenantStore,
 ) *ActivityLogService {
 	return &ActivityLogService{
 		activityLogRepo: activityLogRepo,
@@ -0,0 +1,1072 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/ical"
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// Limits of stay calendars
+const (
+	defaultAvailabilityDays = 90
+	maxAvailabilityDays     = 366
+	maxBlockNights          = 366  // Blocks (owner use, maintenance) are not stays
+	bookingBatch            = 1000 // Bookings read per calendar, sync or expiry run
+	stayCalendarBatch       = 500  // Calendars synced per run
+	stayCalendarPastDays    = 30   // Exported feed starts this many days ago
+	stayCalendarAheadDays   = 540  // Exported and imported feeds end this many days ahead
+	calendarFeedTimeout     = 20 * time.Second
+	maxCalendarFeedBytes    = 2 << 20
+)
+
+// ErrBookingConflict is returned when the nights of a stay are taken by
+// another booking or block
+var ErrBookingConflict = errors.New("dates not available")
+
+// BookingService handles the availability calendars, bookings and external
+// calendar sync of properties let by the night
+type BookingService struct {
+	calendarRepo    repositories.StayCalendarStore
+	bookingRepo     repositories.BookingStore
+	leadService     *LeadService
+	propertyRepo    repositories.PropertyStore
+	activityLogRepo repositories.ActivityLogStore
+	httpClient      *http.Client
+}
+
+// NewBookingService creates a new booking service
+func NewBookingService(
+	calendarRepo repositories.StayCalendarStore,
+	bookingRepo repositories.BookingStore,
+	leadService *LeadService,
+	propertyRepo repositories.PropertyStore,
+	activityLogRepo repositories.ActivityLogStore,
+) *BookingService {
+	return &BookingService{
+		calendarRepo:    calendarRepo,
+		bookingRepo:     bookingRepo,
+		leadService:     leadService,
+		propertyRepo:    propertyRepo,
+		activityLogRepo: activityLogRepo,
+		httpClient:      &http.Client{Timeout: calendarFeedTimeout},
+	}
+}
+
+// ============================================================================
+// Calendar
+// ============================================================================
+
+// GetCalendar returns the stay calendar of a property (a default one, priced
+// at the property's daily rate, when not configured)
+func (s *BookingService) GetCalendar(ctx context.Context, tenantID, propertyID string) (*models.StayCalendar, error) {
+	_, calendar, err := s.stayCalendar(ctx, tenantID, propertyID)
+	return calendar, err
+}
+
+// SetCalendar replaces the rates, rules and external calendars of a property.
+// Bookings are kept; the blocks imported from feeds no longer listed are
+// released.
+func (s *BookingService) SetCalendar(ctx context.Context, tenantID, propertyID string, calendar models.StayCalendar, actorID string) (*models.StayCalendar, error) {
+	if _, err := s.shortStayProperty(ctx, tenantID, propertyID); err != nil {
+		return nil, err
+	}
+
+	calendar = calendar.WithDefaults()
+	if err := calendar.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", repositories.ErrInvalidInput, err)
+	}
+	calendar.TenantID = tenantID
+	calendar.PropertyID = propertyID
+
+	// Keep the export token and the sync state of the feeds still listed
+	var removed []models.CalendarFeed
+	current, err := s.calendarRepo.Get(ctx, tenantID, propertyID)
+	switch {
+	case err == nil:
+		calendar.ExportTokenHash = current.ExportTokenHash
+		calendar.CreatedAt = current.CreatedAt
+		for _, previous := range current.Feeds {
+			kept := false
+			for i := range calendar.Feeds {
+				if calendar.Feeds[i].ID == previous.ID {
+					calendar.Feeds[i].LastSyncedAt = previous.LastSyncedAt
+					calendar.Feeds[i].LastError = previous.LastError
+					calendar.Feeds[i].Events = previous.Events
+					kept = true
+				}
+			}
+			if !kept {
+				removed = append(removed, previous)
+			}
+		}
+	case errors.Is(err, repositories.ErrNotFound):
+		calendar.CreatedAt = time.Time{}
+	default:
+		return nil, fmt.Errorf("failed to get stay calendar: %w", err)
+	}
+
+	if err := s.calendarRepo.Save(ctx, &calendar); err != nil {
+		return nil, err
+	}
+
+	for _, feed := range removed {
+		blocks, err := s.feedBlocks(ctx, tenantID, propertyID, feed.ID, models.StayDate(time.Now()))
+		if err != nil {
+			log.Printf("⚠️  Failed to release the blocks of feed %s of property %s: %v", feed.ID, propertyID, err)
+			continue
+		}
+		s.releaseBlocks(ctx, blocks, "feed removed from the calendar")
+	}
+
+	_ = s.logActivity(ctx, tenantID, "stay_calendar_updated", models.ActorTypeUser, actorID, map[string]interface{}{
+		"property_id":  propertyID,
+		"nightly_rate": calendar.NightlyRate,
+		"seasons":      len(calendar.Seasons),
+		"feeds":        len(calendar.Feeds),
+	})
+
+	return &calendar, nil
+}
+
+// StayAvailability is the public calendar of a property let by the night,
+// one entry per night. Bookings are not disclosed, only the nights taken.
+type StayAvailability struct {
+	PropertyID  string      `json:"property_id"`
+	From        time.Time   `json:"from"`
+	Until       time.Time   `json:"until"` // Exclusive
+	NightlyRate float64     `json:"nightly_rate"`
+	CleaningFee float64     `json:"cleaning_fee,omitempty"`
+	MinNights   int         `json:"min_nights"`
+	MaxGuests   int         `json:"max_guests,omitempty"`
+	Nights      []StayNight `json:"nights"`
+}
+
+// StayNight is a night of the availability calendar
+type StayNight struct {
+	Date        time.Time `json:"date"`
+	Available   bool      `json:"available"`
+	NightlyRate float64   `json:"nightly_rate"`
+	MinNights   int       `json:"min_nights"` // Of a stay checking in on this date
+	Season      string    `json:"season,omitempty"`
+}
+
+// Availability lists the nights of a property over the given number of days
+// from from (never earlier than today)
+func (s *BookingService) Availability(ctx context.Context, tenantID, propertyID string, from time.Time, days int) (*StayAvailability, error) {
+	_, calendar, err := s.bookableCalendar(ctx, tenantID, propertyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if days <= 0 {
+		days = defaultAvailabilityDays
+	}
+	if days > maxAvailabilityDays {
+		days = maxAvailabilityDays
+	}
+	now := time.Now()
+	from = models.StayDate(from)
+	if today := models.StayDate(now); from.Before(today) {
+		from = today
+	}
+	until := from.AddDate(0, 0, days)
+
+	bookings, err := s.holdingBookings(ctx, tenantID, propertyID, from, now)
+	if err != nil {
+		return nil, err
+	}
+
+	availability := &StayAvailability{
+		PropertyID:  propertyID,
+		From:        from,
+		Until:       until,
+		NightlyRate: calendar.NightlyRate,
+		CleaningFee: calendar.CleaningFee,
+		MinNights:   calendar.MinNights,
+		MaxGuests:   calendar.MaxGuests,
+		Nights:      make([]StayNight, 0, days),
+	}
+	for night := from; night.Before(until); night = night.AddDate(0, 0, 1) {
+		entry := StayNight{
+			Date:        night,
+			Available:   takenBy(bookings, night, night.AddDate(0, 0, 1)) == nil,
+			NightlyRate: calendar.NightlyRate,
+			MinNights:   calendar.MinNightsFor(night),
+		}
+		if season := calendar.SeasonOn(night); season != nil {
+			entry.NightlyRate, entry.Season = season.NightlyRate, season.Name
+		}
+		availability.Nights = append(availability.Nights, entry)
+	}
+
+	return availability, nil
+}
+
+// Quote prices a stay and checks that its nights are free
+func (s *BookingService) Quote(ctx context.Context, tenantID, propertyID string, checkIn, checkOut time.Time, guests int) (*models.StayQuote, error) {
+	_, calendar, err := s.bookableCalendar(ctx, tenantID, propertyID)
+	if err != nil {
+		return nil, err
+	}
+
+	quote, err := s.checkStay(calendar, checkIn, checkOut, guests)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkAvailable(ctx, tenantID, propertyID, quote.CheckIn, quote.CheckOut); err != nil {
+		return nil, err
+	}
+	return quote, nil
+}
+
+// ============================================================================
+// Bookings
+// ============================================================================
+
+// RequestBooking books a stay asked for from the property page: it creates
+// a requested booking that holds the nights for models.BookingRequestTTL
+// while the agency confirms it, then the lead. The lead is only created once
+// the nights are held, so refused requests leave no lead behind.
+func (s *BookingService) RequestBooking(ctx context.Context, lead *models.Lead, checkIn, checkOut time.Time, guests int) (*models.Booking, error) {
+	property, calendar, err := s.bookableCalendar(ctx, lead.TenantID, lead.PropertyID)
+	if err != nil {
+		return nil, err
+	}
+	if property.Status != models.PropertyStatusAvailable {
+		return nil, fmt.Errorf("%w: property is not available", repositories.ErrInvalidInput)
+	}
+
+	now := time.Now()
+	if models.StayDate(checkIn).Before(models.StayDate(now)) {
+		return nil, fmt.Errorf("%w: check_in must not be in the past", repositories.ErrInvalidInput)
+	}
+	quote, err := s.checkStay(calendar, checkIn, checkOut, guests)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkAvailable(ctx, lead.TenantID, property.ID, quote.CheckIn, quote.CheckOut); err != nil {
+		return nil, err
+	}
+
+	expiresAt := now.Add(models.BookingRequestTTL)
+	booking := &models.Booking{
+		TenantID:   lead.TenantID,
+		PropertyID: property.ID,
+		Status:     models.BookingStatusRequested,
+		Source:     models.BookingSourcePublic,
+		CheckIn:    quote.CheckIn,
+		CheckOut:   quote.CheckOut,
+		Nights:     quote.Nights,
+		GuestName:  lead.Name,
+		GuestEmail: lead.Email,
+		GuestPhone: lead.Phone,
+		Guests:     guests,
+		Notes:      lead.Message,
+		Quote:      quote,
+		ExpiresAt:  &expiresAt,
+	}
+	created, err := s.bookingRepo.CreateIfAvailable(ctx, booking, now)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, fmt.Errorf("%w: the dates were just taken", ErrBookingConflict)
+	}
+
+	if lead.Channel == "" {
+		lead.Channel = models.LeadChannelForm
+	}
+	if err := s.leadService.CreateLead(ctx, lead); err != nil {
+		// Release the nights held for a request nobody can follow up
+		if closeErr := s.close(ctx, booking, models.BookingStatusCancelled, "", "lead could not be created"); closeErr != nil {
+			log.Printf("⚠️  Failed to release booking %s after its lead failed: %v", booking.ID, closeErr)
+		}
+		return nil, err
+	}
+
+	booking.LeadID = lead.ID
+	if err := s.bookingRepo.Update(ctx, booking.TenantID, booking.ID, map[string]interface{}{
+		"lead_id": booking.LeadID,
+	}); err != nil {
+		log.Printf("⚠️  Failed to link booking %s to lead %s: %v", booking.ID, lead.ID, err)
+	}
+
+	_ = s.logActivity(ctx, booking.TenantID, "booking_requested", models.ActorTypeLead, lead.ID, bookingLogMetadata(booking))
+
+	return booking, nil
+}
+
+// CreateBooking books a confirmed stay, or blocks dates (owner use,
+// maintenance), from the dashboard. The minimum stay is not enforced, but
+// the nights must be free.
+func (s *BookingService) CreateBooking(ctx context.Context, booking *models.Booking, actorID string) error {
+	_, calendar, err := s.stayCalendar(ctx, booking.TenantID, booking.PropertyID)
+	if err != nil {
+		return err
+	}
+
+	if booking.Status == "" {
+		booking.Status = models.BookingStatusConfirmed
+	}
+	if booking.Status != models.BookingStatusConfirmed && booking.Status != models.BookingStatusBlocked {
+		return fmt.Errorf("%w: status must be 'confirmed' or 'blocked'", repositories.ErrInvalidInput)
+	}
+
+	now := time.Now()
+	booking.CheckIn, booking.CheckOut = models.StayDate(booking.CheckIn), models.StayDate(booking.CheckOut)
+	booking.Nights = models.CalendarDays(booking.CheckIn, booking.CheckOut)
+	maxNights := models.MaxStayNights
+	if booking.Status == models.BookingStatusBlocked {
+		maxNights = maxBlockNights
+	}
+	if booking.CheckIn.IsZero() || booking.Nights < 1 || booking.Nights > maxNights {
+		return fmt.Errorf("%w: check_out must be after check_in and within %d nights", repositories.ErrInvalidInput, maxNights)
+	}
+	if !booking.CheckOut.After(models.StayDate(now)) {
+		return fmt.Errorf("%w: check_out must be in the future", repositories.ErrInvalidInput)
+	}
+
+	booking.Quote = nil
+	if booking.Status == models.BookingStatusConfirmed {
+		booking.GuestName = strings.TrimSpace(booking.GuestName)
+		if booking.GuestName == "" {
+			return fmt.Errorf("%w: guest_name is required", repositories.ErrInvalidInput)
+		}
+		if calendar.MaxGuests > 0 && booking.Guests > calendar.MaxGuests {
+			return fmt.Errorf("%w: at most %d guests", repositories.ErrInvalidInput, calendar.MaxGuests)
+		}
+		if booking.LeadID != "" {
+			if _, err := s.leadService.activeLead(ctx, booking.TenantID, booking.LeadID); err != nil {
+				return err
+			}
+		}
+		if calendar.NightlyRate > 0 {
+			booking.Quote = calendar.Price(booking.CheckIn, booking.CheckOut)
+		}
+		booking.ConfirmedAt = &now
+	} else {
+		booking.LeadID, booking.GuestName, booking.GuestEmail, booking.GuestPhone, booking.Guests = "", "", "", "", 0
+		booking.ConfirmedAt = nil
+	}
+	booking.ID = ""
+	booking.Source = models.BookingSourceAdmin
+	booking.FeedID, booking.ExternalUID, booking.ExpiresAt = "", "", nil
+	booking.CancelledAt, booking.CancelledBy, booking.CancelReason = nil, "", ""
+
+	created, err := s.bookingRepo.CreateIfAvailable(ctx, booking, now)
+	if err != nil {
+		return err
+	}
+	if !created {
+		return fmt.Errorf("%w: another booking or block takes these nights", ErrBookingConflict)
+	}
+
+	_ = s.logActivity(ctx, booking.TenantID, "booking_created", models.ActorTypeUser, actorID, bookingLogMetadata(booking))
+
+	return nil
+}
+
+// ConfirmBooking confirms a booking request that still holds its nights
+func (s *BookingService) ConfirmBooking(ctx context.Context, tenantID, id, actorID string) (*models.Booking, error) {
+	booking, err := s.GetBooking(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if booking.Status != models.BookingStatusRequested {
+		return nil, fmt.Errorf("%w: booking is %s", repositories.ErrInvalidInput, booking.Status)
+	}
+
+	now := time.Now()
+	if !booking.HoldsDates(now) {
+		return nil, fmt.Errorf("%w: booking request has expired", repositories.ErrInvalidInput)
+	}
+
+	// The request may have been answered or expired since it was read
+	updated, err := s.bookingRepo.UpdateIf(ctx, tenantID, id, func(current *models.Booking) bool {
+		return current.Status == models.BookingStatusRequested && current.HoldsDates(now)
+	}, map[string]interface{}{
+		"status":       models.BookingStatusConfirmed,
+		"confirmed_at": now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, fmt.Errorf("%w: booking request was answered or has expired", repositories.ErrInvalidInput)
+	}
+
+	booking.Status = models.BookingStatusConfirmed
+	booking.ConfirmedAt = &now
+
+	_ = s.logActivity(ctx, tenantID, "booking_confirmed", models.ActorTypeUser, actorID, bookingLogMetadata(booking))
+
+	return booking, nil
+}
+
+// DeclineBooking declines a booking request, releasing its nights
+func (s *BookingService) DeclineBooking(ctx context.Context, tenantID, id, actorID, reason string) (*models.Booking, error) {
+	booking, err := s.GetBooking(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if booking.Status != models.BookingStatusRequested {
+		return nil, fmt.Errorf("%w: booking is %s", repositories.ErrInvalidInput, booking.Status)
+	}
+	if err := s.close(ctx, booking, models.BookingStatusDeclined, actorID, reason); err != nil {
+		return nil, err
+	}
+
+	_ = s.logActivity(ctx, tenantID, "booking_declined", models.ActorTypeUser, actorID, bookingLogMetadata(booking))
+
+	return booking, nil
+}
+
+// CancelBooking cancels a booking or block of the agency, releasing its
+// nights. Imported blocks follow their external calendar.
+func (s *BookingService) CancelBooking(ctx context.Context, tenantID, id, actorID, reason string) (*models.Booking, error) {
+	booking, err := s.GetBooking(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if booking.Source == models.BookingSourceICal {
+		return nil, fmt.Errorf("%w: imported blocks are removed from their external calendar", repositories.ErrInvalidInput)
+	}
+	switch booking.Status {
+	case models.BookingStatusRequested, models.BookingStatusConfirmed, models.BookingStatusBlocked:
+	default:
+		return nil, fmt.Errorf("%w: booking is %s", repositories.ErrInvalidInput, booking.Status)
+	}
+	if err := s.close(ctx, booking, models.BookingStatusCancelled, actorID, reason); err != nil {
+		return nil, err
+	}
+
+	_ = s.logActivity(ctx, tenantID, "booking_cancelled", models.ActorTypeUser, actorID, bookingLogMetadata(booking))
+
+	return booking, nil
+}
+
+// close ends a booking as declined, cancelled or expired, unless its status
+// changed since it was read (ex: a request confirmed while it expired)
+func (s *BookingService) close(ctx context.Context, booking *models.Booking, status models.BookingStatus, by, reason string) error {
+	now := time.Now()
+	reason = strings.TrimSpace(reason)
+	previous := booking.Status
+	updated, err := s.bookingRepo.UpdateIf(ctx, booking.TenantID, booking.ID, func(current *models.Booking) bool {
+		return current.Status == previous
+	}, map[string]interface{}{
+		"status":        status,
+		"cancelled_at":  now,
+		"cancelled_by":  by,
+		"cancel_reason": reason,
+	})
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return fmt.Errorf("%w: booking is no longer %s", repositories.ErrInvalidInput, previous)
+	}
+
+	booking.Status = status
+	booking.CancelledAt = &now
+	booking.CancelledBy = by
+	booking.CancelReason = reason
+	return nil
+}
+
+// ProcessExpiredBookingsResponse summarizes a run of the booking request
+// expiry job
+type ProcessExpiredBookingsResponse struct {
+	Expired int `json:"expired"`
+	Failed  int `json:"failed"`
+}
+
+// ProcessExpiredBookings expires the booking requests not confirmed in time.
+// Their nights are free from the deadline on; this records it.
+func (s *BookingService) ProcessExpiredBookings(ctx context.Context, tenantID string) (*ProcessExpiredBookingsResponse, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+
+	now := time.Now()
+	bookings, err := s.bookingRepo.List(ctx, tenantID, &repositories.BookingFilters{
+		Statuses:      []models.BookingStatus{models.BookingStatusRequested},
+		ExpiresBefore: &now,
+	}, repositories.PaginationOptions{Limit: bookingBatch})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expired booking requests: %w", err)
+	}
+
+	response := &ProcessExpiredBookingsResponse{}
+	for _, booking := range bookings {
+		if err := s.close(ctx, booking, models.BookingStatusExpired, "", "not confirmed in time"); err != nil {
+			log.Printf("⚠️  Failed to expire booking %s: %v", booking.ID, err)
+			response.Failed++
+			continue
+		}
+		_ = s.logActivity(ctx, tenantID, "booking_expired", models.ActorTypeSystem, "", bookingLogMetadata(booking))
+		response.Expired++
+	}
+
+	return response, nil
+}
+
+// GetBooking retrieves a booking by ID
+func (s *BookingService) GetBooking(ctx context.Context, tenantID, id string) (*models.Booking, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+
+	booking, err := s.bookingRepo.Get(ctx, tenantID, id)
+	if err != nil {
+		return nil, fmt.Errorf("booking not found: %w", err)
+	}
+	return booking, nil
+}
+
+// ListBookings lists bookings with filters
+func (s *BookingService) ListBookings(ctx context.Context, tenantID string, filters *repositories.BookingFilters, opts repositories.PaginationOptions) ([]*models.Booking, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+
+	bookings, err := s.bookingRepo.List(ctx, tenantID, filters, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bookings: %w", err)
+	}
+	return bookings, nil
+}
+
+// ============================================================================
+// External calendars
+// ============================================================================
+
+// CalendarSyncResult summarizes the import of the external calendars of a
+// property
+type CalendarSyncResult struct {
+	PropertyID string           `json:"property_id"`
+	Feeds      []FeedSyncResult `json:"feeds"`
+}
+
+// FeedSyncResult summarizes the import of an external calendar
+type FeedSyncResult struct {
+	FeedID    string `json:"feed_id"`
+	Name      string `json:"name"`
+	Events    int    `json:"events"` // Upcoming blocks in the feed
+	Created   int    `json:"created"`
+	Updated   int    `json:"updated"`
+	Removed   int    `json:"removed"`   // Blocks no longer in the feed
+	Conflicts int    `json:"conflicts"` // Blocks overlapping bookings of the agency
+	Error     string `json:"error,omitempty"`
+}
+
+// SyncCalendar imports the reservations of the external calendars (Airbnb,
+// Booking.com) of a property as blocks: new events block their nights,
+// changed ones move and removed ones are released. A feed that cannot be
+// read keeps its previous blocks and records the error.
+func (s *BookingService) SyncCalendar(ctx context.Context, tenantID, propertyID string) (*CalendarSyncResult, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+
+	calendar, err := s.calendarRepo.Get(ctx, tenantID, propertyID)
+	if err != nil {
+		return nil, fmt.Errorf("stay calendar not found: %w", err)
+	}
+	return s.syncCalendar(ctx, calendar)
+}
+
+// SyncCalendarsResponse summarizes a run of the external calendar sync job
+type SyncCalendarsResponse struct {
+	Calendars int `json:"calendars"` // Calendars with feeds
+	Feeds     int `json:"feeds"`
+	Failed    int `json:"failed"` // Feeds that could not be imported
+}
+
+// SyncCalendars imports the external calendars of every property of a
+// tenant
+func (s *BookingService) SyncCalendars(ctx context.Context, tenantID string) (*SyncCalendarsResponse, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+
+	calendars, err := s.calendarRepo.List(ctx, tenantID, repositories.PaginationOptions{Limit: stayCalendarBatch})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stay calendars: %w", err)
+	}
+
+	response := &SyncCalendarsResponse{}
+	for _, calendar := range calendars {
+		if len(calendar.Feeds) == 0 {
+			continue
+		}
+		response.Calendars++
+
+		result, err := s.syncCalendar(ctx, calendar)
+		if err != nil {
+			log.Printf("⚠️  Failed to sync the calendar of property %s: %v", calendar.PropertyID, err)
+			response.Feeds += len(calendar.Feeds)
+			response.Failed += len(calendar.Feeds)
+			continue
+		}
+		for _, feed := range result.Feeds {
+			response.Feeds++
+			if feed.Error != "" {
+				response.Failed++
+			}
+		}
+	}
+
+	return response, nil
+}
+
+// syncCalendar imports every feed of a calendar and records their state
+func (s *BookingService) syncCalendar(ctx context.Context, calendar *models.StayCalendar) (*CalendarSyncResult, error) {
+	now := time.Now()
+	ours, err := s.holdingBookings(ctx, calendar.TenantID, calendar.PropertyID, models.StayDate(now), now)
+	if err != nil {
+		return nil, err
+	}
+	own := ours[:0]
+	for _, booking := range ours {
+		if booking.Source != models.BookingSourceICal {
+			own = append(own, booking)
+		}
+	}
+
+	result := &CalendarSyncResult{PropertyID: calendar.PropertyID, Feeds: make([]FeedSyncResult, 0, len(calendar.Feeds))}
+	feeds := make([]models.CalendarFeed, len(calendar.Feeds))
+	for i, feed := range calendar.Feeds {
+		feedResult, err := s.syncFeed(ctx, calendar, feed, own, now)
+		syncedAt := now
+		feed.LastSyncedAt = &syncedAt
+		if err != nil {
+			log.Printf("⚠️  Failed to sync feed %s of property %s: %v", feed.ID, calendar.PropertyID, err)
+			feedResult.Error = err.Error()
+			feed.LastError = feedResult.Error
+		} else {
+			feed.LastError = ""
+			feed.Events = feedResult.Events
+		}
+		feeds[i] = feed
+		result.Feeds = append(result.Feeds, feedResult)
+	}
+
+	calendar.Feeds = feeds
+	if err := s.calendarRepo.Update(ctx, calendar.TenantID, calendar.PropertyID, map[string]interface{}{"feeds": feeds}); err != nil {
+		return nil, fmt.Errorf("failed to update stay calendar: %w", err)
+	}
+
+	return result, nil
+}
+
+// syncFeed imports the upcoming events of an external calendar as blocks.
+// Blocks are keyed by the event UID (models.ImportedBookingID), so a sync
+// can run again without duplicating them. Overlaps with the agency's own
+// bookings are imported anyway (the guest is already booked there) and
+// counted as conflicts.
+func (s *BookingService) syncFeed(ctx context.Context, calendar *models.StayCalendar, feed models.CalendarFeed, own []*models.Booking, now time.Time) (FeedSyncResult, error) {
+	result := FeedSyncResult{FeedID: feed.ID, Name: feed.Name}
+
+	data, err := s.fetchFeed(ctx, feed.URL)
+	if err != nil {
+		return result, err
+	}
+	events, err := ical.Parse(data)
+	if err != nil {
+		return result, err
+	}
+
+	today := models.StayDate(now)
+	horizon := today.AddDate(0, 0, stayCalendarAheadDays)
+	blocks, err := s.feedBlocks(ctx, calendar.TenantID, calendar.PropertyID, feed.ID, today)
+	if err != nil {
+		return result, err
+	}
+	current := make(map[string]*models.Booking, len(blocks))
+	for _, block := range blocks {
+		current[block.ID] = block
+	}
+
+	seen := map[string]bool{}
+	for _, event := range events {
+		if strings.EqualFold(event.Status, ical.StatusCancelled) {
+			continue
+		}
+		checkIn, checkOut := models.StayDate(event.Start), models.StayDate(event.End)
+		if !checkOut.After(checkIn) {
+			checkOut = checkIn.AddDate(0, 0, 1)
+		}
+		if !checkOut.After(today) || !checkIn.Before(horizon) {
+			continue
+		}
+
+		id := models.ImportedBookingID(calendar.PropertyID, feed.ID, event.UID)
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		result.Events++
+
+		notes := feed.Name
+		if summary := strings.TrimSpace(event.Summary); summary != "" {
+			notes += ": " + summary
+		}
+		updates := map[string]interface{}{
+			"check_in":  checkIn,
+			"check_out": checkOut,
+			"nights":    models.CalendarDays(checkIn, checkOut),
+			"notes":     notes,
+		}
+
+		if block, ok := current[id]; ok {
+			if !block.CheckIn.Equal(checkIn) || !block.CheckOut.Equal(checkOut) || block.Notes != notes {
+				if err := s.bookingRepo.Update(ctx, calendar.TenantID, id, updates); err != nil {
+					return result, fmt.Errorf("failed to update block: %w", err)
+				}
+				result.Updated++
+			}
+		} else if _, err := s.bookingRepo.Get(ctx, calendar.TenantID, id); err == nil {
+			// Released by an earlier sync, or past the window of current blocks
+			updates["status"] = models.BookingStatusBlocked
+			updates["cancelled_at"] = nil
+			updates["cancelled_by"] = ""
+			updates["cancel_reason"] = ""
+			if err := s.bookingRepo.Update(ctx, calendar.TenantID, id, updates); err != nil {
+				return result, fmt.Errorf("failed to update block: %w", err)
+			}
+			result.Created++
+		} else if errors.Is(err, repositories.ErrNotFound) {
+			block := &models.Booking{
+				ID:          id,
+				TenantID:    calendar.TenantID,
+				PropertyID:  calendar.PropertyID,
+				Status:      models.BookingStatusBlocked,
+				Source:      models.BookingSourceICal,
+				CheckIn:     checkIn,
+				CheckOut:    checkOut,
+				Nights:      models.CalendarDays(checkIn, checkOut),
+				Notes:       notes,
+				FeedID:      feed.ID,
+				ExternalUID: event.UID,
+			}
+			if err := s.bookingRepo.Create(ctx, block); err != nil {
+				return result, fmt.Errorf("failed to create block: %w", err)
+			}
+			result.Created++
+		} else {
+			return result, fmt.Errorf("failed to get block: %w", err)
+		}
+
+		if booking := takenBy(own, checkIn, checkOut); booking != nil {
+			log.Printf("⚠️  Feed %s of property %s blocks %s to %s, overlapping booking %s", feed.Name, calendar.PropertyID,
+				checkIn.Format("2006-01-02"), checkOut.Format("2006-01-02"), booking.ID)
+			result.Conflicts++
+		}
+	}
+
+	var gone []*models.Booking
+	for id, block := range current {
+		if !seen[id] {
+			gone = append(gone, block)
+		}
+	}
+	result.Removed = s.releaseBlocks(ctx, gone, "removed from the external calendar")
+
+	return result, nil
+}
+
+// fetchFeed downloads an external calendar
+func (s *BookingService) fetchFeed(ctx context.Context, feedURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feedURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/calendar")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxCalendarFeedBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxCalendarFeedBytes {
+		return nil, fmt.Errorf("calendar larger than %d bytes", maxCalendarFeedBytes)
+	}
+	return data, nil
+}
+
+// feedBlocks returns the current blocks imported from a feed that end after from
+func (s *BookingService) feedBlocks(ctx context.Context, tenantID, propertyID, feedID string, from time.Time) ([]*models.Booking, error) {
+	blocks, err := s.bookingRepo.List(ctx, tenantID, &repositories.BookingFilters{
+		PropertyID:    propertyID,
+		Statuses:      []models.BookingStatus{models.BookingStatusBlocked},
+		Source:        models.BookingSourceICal,
+		FeedID:        feedID,
+		CheckOutAfter: &from,
+	}, repositories.PaginationOptions{Limit: bookingBatch})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list imported blocks: %w", err)
+	}
+	return blocks, nil
+}
+
+// releaseBlocks cancels imported blocks and returns how many were released
+func (s *BookingService) releaseBlocks(ctx context.Context, blocks []*models.Booking, reason string) int {
+	released := 0
+	for _, block := range blocks {
+		if err := s.close(ctx, block, models.BookingStatusCancelled, "", reason); err != nil {
+			log.Printf("⚠️  Failed to release block %s: %v", block.ID, err)
+			continue
+		}
+		released++
+	}
+	return released
+}
+
+// RotateExportToken issues a new secret for the .ics feed of a property's
+// availability, to be added to Airbnb and Booking.com. The previous feed URL
+// stops working.
+func (s *BookingService) RotateExportToken(ctx context.Context, tenantID, propertyID, actorID string) (string, error) {
+	if tenantID == "" {
+		return "", fmt.Errorf("tenant_id is required")
+	}
+	if _, err := s.calendarRepo.Get(ctx, tenantID, propertyID); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return "", fmt.Errorf("%w: configure the stay calendar first", repositories.ErrInvalidInput)
+		}
+		return "", fmt.Errorf("failed to get stay calendar: %w", err)
+	}
+
+	token, tokenHash, err := newVisitToken()
+	if err != nil {
+		return "", err
+	}
+	if err := s.calendarRepo.Update(ctx, tenantID, propertyID, map[string]interface{}{"export_token_hash": tokenHash}); err != nil {
+		return "", fmt.Errorf("failed to update stay calendar: %w", err)
+	}
+
+	_ = s.logActivity(ctx, tenantID, "stay_calendar_token_rotated", models.ActorTypeUser, actorID, map[string]interface{}{
+		"property_id": propertyID,
+	})
+
+	return token, nil
+}
+
+// ExportCalendar renders the iCalendar feed of the nights taken at a
+// property, as all-day events from 30 days ago to 18 months ahead. Guests
+// are not disclosed. An unknown property or wrong token is ErrNotFound.
+func (s *BookingService) ExportCalendar(ctx context.Context, tenantID, propertyID, token string) ([]byte, error) {
+	calendar, err := s.calendarRepo.Get(ctx, tenantID, propertyID)
+	if err != nil {
+		return nil, fmt.Errorf("stay calendar not found: %w", err)
+	}
+	if token == "" || !tokenMatches(calendar.ExportTokenHash, token) {
+		return nil, fmt.Errorf("stay calendar not found: %w", repositories.ErrNotFound)
+	}
+
+	now := time.Now()
+	from := models.StayDate(now).AddDate(0, 0, -stayCalendarPastDays)
+	until := models.StayDate(now).AddDate(0, 0, stayCalendarAheadDays)
+	bookings, err := s.holdingBookings(ctx, tenantID, propertyID, from, now)
+	if err != nil {
+		return nil, err
+	}
+
+	name := propertyID
+	if property, err := s.propertyRepo.Get(ctx, tenantID, propertyID); err == nil {
+		name = propertyReference(property)
+	}
+	feed := &ical.Calendar{
+		ProdID: "-//Ecosistema Imob//Disponibilidade//PT-BR",
+		Name:   "Disponibilidade - " + name,
+	}
+	for _, booking := range bookings {
+		if !booking.CheckIn.Before(until) {
+			continue
+		}
+		feed.Events = append(feed.Events, bookingEvent(booking))
+	}
+
+	return feed.Encode(now), nil
+}
+
+// bookingEvent builds the all-day calendar event of a booking or block
+func bookingEvent(booking *models.Booking) ical.Event {
+	event := ical.Event{
+		UID:          booking.ID + "@" + booking.TenantID + ".bookings",
+		Start:        booking.CheckIn,
+		End:          booking.CheckOut,
+		AllDay:       true,
+		Summary:      "Reservado",
+		Status:       ical.StatusConfirmed,
+		LastModified: booking.UpdatedAt,
+	}
+	switch booking.Status {
+	case models.BookingStatusRequested:
+		event.Summary = "Pré-reserva"
+		event.Status = ical.StatusTentative
+	case models.BookingStatusBlocked:
+		event.Summary = "Indisponível"
+	}
+	return event
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+// shortStayProperty returns a property let by the night
+func (s *BookingService) shortStayProperty(ctx context.Context, tenantID, propertyID string) (*models.Property, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+
+	property, err := s.propertyRepo.Get(ctx, tenantID, propertyID)
+	if err != nil {
+		return nil, fmt.Errorf("property not found: %w", err)
+	}
+	if !property.RentalInfo.IsShortStay() {
+		return nil, fmt.Errorf("%w: property is not a short-term or vacation rental", repositories.ErrInvalidInput)
+	}
+	return property, nil
+}
+
+// stayCalendar returns a property let by the night and its stay calendar
+// (the default one when not configured)
+func (s *BookingService) stayCalendar(ctx context.Context, tenantID, propertyID string) (*models.Property, *models.StayCalendar, error) {
+	property, err := s.shortStayProperty(ctx, tenantID, propertyID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	calendar, err := s.calendarRepo.Get(ctx, tenantID, propertyID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		calendar = &models.StayCalendar{
+			PropertyID:  propertyID,
+			TenantID:    tenantID,
+			NightlyRate: property.RentalInfo.DailyRate,
+			MinNights:   models.DefaultMinNights,
+		}
+	} else if err != nil {
+		return nil, nil, fmt.Errorf("failed to get stay calendar: %w", err)
+	}
+	return property, calendar, nil
+}
+
+// bookableCalendar returns a property let by the night and its stay
+// calendar, which must have a nightly rate
+func (s *BookingService) bookableCalendar(ctx context.Context, tenantID, propertyID string) (*models.Property, *models.StayCalendar, error) {
+	property, calendar, err := s.stayCalendar(ctx, tenantID, propertyID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if calendar.NightlyRate <= 0 {
+		return nil, nil, fmt.Errorf("%w: property has no nightly rate", repositories.ErrInvalidInput)
+	}
+	return property, calendar, nil
+}
+
+// checkStay checks a stay against the calendar rules and prices it
+func (s *BookingService) checkStay(calendar *models.StayCalendar, checkIn, checkOut time.Time, guests int) (*models.StayQuote, error) {
+	if checkIn.IsZero() || checkOut.IsZero() {
+		return nil, fmt.Errorf("%w: check_in and check_out are required", repositories.ErrInvalidInput)
+	}
+	if guests < 0 || (calendar.MaxGuests > 0 && guests > calendar.MaxGuests) {
+		return nil, fmt.Errorf("%w: at most %d guests", repositories.ErrInvalidInput, calendar.MaxGuests)
+	}
+
+	quote, err := calendar.Quote(checkIn, checkOut)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", repositories.ErrInvalidInput, err)
+	}
+	return quote, nil
+}
+
+// checkAvailable verifies that no booking or block takes a night of the stay
+func (s *BookingService) checkAvailable(ctx context.Context, tenantID, propertyID string, checkIn, checkOut time.Time) error {
+	now := time.Now()
+	bookings, err := s.holdingBookings(ctx, tenantID, propertyID, checkIn, now)
+	if err != nil {
+		return err
+	}
+	if takenBy(bookings, checkIn, checkOut) != nil {
+		return fmt.Errorf("%w: the property is booked on these dates", ErrBookingConflict)
+	}
+	return nil
+}
+
+// holdingBookings returns the bookings and blocks of a property that end
+// after from and hold their nights at a time
+func (s *BookingService) holdingBookings(ctx context.Context, tenantID, propertyID string, from, at time.Time) ([]*models.Booking, error) {
+	bookings, err := s.bookingRepo.List(ctx, tenantID, &repositories.BookingFilters{
+		PropertyID:    propertyID,
+		Statuses:      models.BookingHoldingStatuses,
+		CheckOutAfter: &from,
+	}, repositories.PaginationOptions{Limit: bookingBatch})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list property bookings: %w", err)
+	}
+
+	holding := bookings[:0]
+	for _, booking := range bookings {
+		if booking.HoldsDates(at) {
+			holding = append(holding, booking)
+		}
+	}
+	return holding, nil
+}
+
+// takenBy returns the first booking that takes a night from checkIn to
+// checkOut, nil when they are free. The bookings must hold their nights.
+func takenBy(bookings []*models.Booking, checkIn, checkOut time.Time) *models.Booking {
+	for _, booking := range bookings {
+		if booking.Overlaps(checkIn, checkOut) {
+			return booking
+		}
+	}
+	return nil
+}
+
+// bookingLogMetadata returns the activity log metadata of a booking
+func bookingLogMetadata(booking *models.Booking) map[string]interface{} {
+	return map[string]interface{}{
+		"booking_id":  booking.ID,
+		"property_id": booking.PropertyID,
+		"lead_id":     booking.LeadID,
+		"check_in":    booking.CheckIn,
+		"check_out":   booking.CheckOut,
+		"status":      booking.Status,
+		"source":      booking.Source,
+	}
+}
+
+// logActivity logs an activity
+func (s *BookingService) logActivity(ctx context.Context, tenantID, eventType string, actorType models.ActorType, actorID string, metadata map[string]interface{}) error {
+	log := &models.ActivityLog{
+		TenantID:  tenantID,
+		EventType: eventType,
+		ActorType: actorType,
+		ActorID:   actorID,
+		Metadata:  metadata,
+		Timestamp: time.Now(),
+	}
+
+	return s.activityLogRepo.Create(ctx, log)
+}
@@ -0,0 +1,375 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories/memory"
+)
+
+// newBookingTestService returns a booking service over the routing test
+// data: property "moema" (available) is a vacation rental at R$ 400 a night
+func newBookingTestService(t *testing.T) *BookingService {
+	t.Helper()
+
+	leadService, _ := newActivityTestService(t)
+	require.NoError(t, leadService.propertyRepo.Update(context.Background(), "tenant-1", "moema", map[string]interface{}{
+		"status":    models.PropertyStatusAvailable,
+		"reference": "AP00335",
+		"rental_info": &models.RentalInfo{
+			RentalType: models.RentalTypeVacation,
+			DailyRate:  400,
+		},
+	}))
+
+	return NewBookingService(
+		memory.NewStayCalendarRepository(),
+		memory.NewBookingRepository(),
+		leadService,
+		leadService.propertyRepo,
+		leadService.activityLogRepo,
+	)
+}
+
+// stayNight returns the date days from today
+func stayNight(days int) time.Time {
+	return models.StayDate(time.Now()).AddDate(0, 0, days)
+}
+
+func bookingTestLead(name, phone string) *models.Lead {
+	return &models.Lead{
+		TenantID:     "tenant-1",
+		PropertyID:   "moema",
+		Name:         name,
+		Phone:        phone,
+		Channel:      models.LeadChannelForm,
+		ConsentGiven: true,
+	}
+}
+
+func TestBookingCalendar_SeasonalQuoteAndAvailability(t *testing.T) {
+	ctx := context.Background()
+	service := newBookingTestService(t)
+
+	// Defaults to the property's daily rate until configured
+	calendar, err := service.GetCalendar(ctx, "tenant-1", "moema")
+	require.NoError(t, err)
+	assert.Equal(t, 400.0, calendar.NightlyRate)
+
+	_, err = service.SetCalendar(ctx, "tenant-1", "moema", models.StayCalendar{
+		NightlyRate: 400,
+		CleaningFee: 150,
+		MinNights:   2,
+		Seasons: []models.SeasonalRate{
+			{Name: "Alta temporada", StartDate: stayNight(20), EndDate: stayNight(29), NightlyRate: 700, MinNights: 5},
+		},
+	}, "admin-1")
+	require.NoError(t, err)
+
+	quote, err := service.Quote(ctx, "tenant-1", "moema", stayNight(18), stayNight(23), 2)
+	require.NoError(t, err)
+	assert.Equal(t, 5, quote.Nights)
+	assert.Equal(t, 2900.0, quote.Lodging) // 2 x 400 + 3 x 700
+	assert.Equal(t, 3050.0, quote.Total)
+
+	// Minimum stay of the season
+	_, err = service.Quote(ctx, "tenant-1", "moema", stayNight(20), stayNight(23), 2)
+	assert.True(t, errors.Is(err, repositories.ErrInvalidInput))
+
+	availability, err := service.Availability(ctx, "tenant-1", "moema", stayNight(19), 3)
+	require.NoError(t, err)
+	require.Len(t, availability.Nights, 3)
+	assert.Equal(t, 400.0, availability.Nights[0].NightlyRate)
+	assert.Equal(t, "Alta temporada", availability.Nights[1].Season)
+	assert.Equal(t, 5, availability.Nights[1].MinNights)
+
+	// Only short-stay properties have a calendar
+	_, err = service.SetCalendar(ctx, "tenant-1", "centro", models.StayCalendar{NightlyRate: 300}, "admin-1")
+	assert.True(t, errors.Is(err, repositories.ErrInvalidInput))
+}
+
+func TestRequestBooking_HoldsNightsUntilAnswered(t *testing.T) {
+	ctx := context.Background()
+	service := newBookingTestService(t)
+
+	lead := bookingTestLead("Ana Costa", "(11) 98888-0000")
+	booking, err := service.RequestBooking(ctx, lead, stayNight(10), stayNight(13), 2)
+	require.NoError(t, err)
+	assert.NotEmpty(t, lead.ID)
+	assert.Equal(t, lead.ID, booking.LeadID)
+	assert.Equal(t, models.BookingStatusRequested, booking.Status)
+	assert.Equal(t, 3, booking.Nights)
+	require.NotNil(t, booking.Quote)
+	assert.Equal(t, 1200.0, booking.Quote.Total)
+	require.NotNil(t, booking.ExpiresAt)
+
+	// Overlapping requests are refused without creating a lead, back-to-back stays are not
+	refused := bookingTestLead("Bruno Lima", "(11) 96666-0000")
+	_, err = service.RequestBooking(ctx, refused, stayNight(12), stayNight(15), 2)
+	assert.True(t, errors.Is(err, ErrBookingConflict))
+	assert.Empty(t, refused.ID)
+	_, err = service.RequestBooking(ctx, bookingTestLead("Bruno Lima", "(11) 96666-0000"), stayNight(13), stayNight(15), 2)
+	require.NoError(t, err)
+
+	availability, err := service.Availability(ctx, "tenant-1", "moema", stayNight(9), 7)
+	require.NoError(t, err)
+	free := []bool{}
+	for _, night := range availability.Nights {
+		free = append(free, night.Available)
+	}
+	assert.Equal(t, []bool{true, false, false, false, false, false, true}, free)
+
+	confirmed, err := service.ConfirmBooking(ctx, "tenant-1", booking.ID, "admin-1")
+	require.NoError(t, err)
+	assert.Equal(t, models.BookingStatusConfirmed, confirmed.Status)
+
+	// Confirmed stays are cancelled from the dashboard, freeing the nights
+	_, err = service.CancelBooking(ctx, "tenant-1", booking.ID, "admin-1", "Hóspede desistiu")
+	require.NoError(t, err)
+	_, err = service.Quote(ctx, "tenant-1", "moema", stayNight(10), stayNight(13), 2)
+	assert.NoError(t, err)
+}
+
+func TestRequestBooking_ReleasesNightsWhenTheLeadFails(t *testing.T) {
+	ctx := context.Background()
+	service := newBookingTestService(t)
+
+	// Leads need a valid contact
+	_, err := service.RequestBooking(ctx, bookingTestLead("Ana Costa", "123"), stayNight(10), stayNight(13), 2)
+	require.Error(t, err)
+
+	bookings, err := service.ListBookings(ctx, "tenant-1", nil, repositories.PaginationOptions{})
+	require.NoError(t, err)
+	require.Len(t, bookings, 1)
+	assert.Equal(t, models.BookingStatusCancelled, bookings[0].Status)
+	assert.Empty(t, bookings[0].LeadID)
+
+	_, err = service.RequestBooking(ctx, bookingTestLead("Ana Costa", "(11) 98888-0000"), stayNight(10), stayNight(13), 2)
+	require.NoError(t, err)
+}
+
+// staleBookingStore returns the bookings as they were first read, like a
+// request answered by another broker between the read and the write
+type staleBookingStore struct {
+	repositories.BookingStore
+	read map[string]*models.Booking
+}
+
+func (s *staleBookingStore) Get(ctx context.Context, tenantID, id string) (*models.Booking, error) {
+	if booking, ok := s.read[id]; ok {
+		copied := *booking
+		return &copied, nil
+	}
+	booking, err := s.BookingStore.Get(ctx, tenantID, id)
+	if err == nil {
+		copied := *booking
+		s.read[id] = &copied
+	}
+	return booking, err
+}
+
+func TestConfirmBooking_RefusesRequestsAnsweredMeanwhile(t *testing.T) {
+	ctx := context.Background()
+	service := newBookingTestService(t)
+
+	booking, err := service.RequestBooking(ctx, bookingTestLead("Ana Costa", "(11) 98888-0000"), stayNight(10), stayNight(13), 2)
+	require.NoError(t, err)
+
+	store := &staleBookingStore{BookingStore: service.bookingRepo, read: make(map[string]*models.Booking)}
+	_, err = store.Get(ctx, "tenant-1", booking.ID)
+	require.NoError(t, err)
+
+	// Declined by another broker after this one read the request
+	_, err = service.DeclineBooking(ctx, "tenant-1", booking.ID, "admin-2", "Datas indisponíveis")
+	require.NoError(t, err)
+
+	service.bookingRepo = store
+	_, err = service.ConfirmBooking(ctx, "tenant-1", booking.ID, "admin-1")
+	assert.ErrorIs(t, err, repositories.ErrInvalidInput)
+
+	stored, err := store.BookingStore.Get(ctx, "tenant-1", booking.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.BookingStatusDeclined, stored.Status)
+}
+
+func TestCreateBooking_BlocksAndConflicts(t *testing.T) {
+	ctx := context.Background()
+	service := newBookingTestService(t)
+
+	block := &models.Booking{
+		TenantID:   "tenant-1",
+		PropertyID: "moema",
+		Status:     models.BookingStatusBlocked,
+		CheckIn:    stayNight(5),
+		CheckOut:   stayNight(8),
+		Notes:      "Uso do proprietário",
+	}
+	require.NoError(t, service.CreateBooking(ctx, block, "admin-1"))
+	assert.Equal(t, models.BookingSourceAdmin, block.Source)
+	assert.Nil(t, block.Quote)
+
+	// A one-night stay ignores the minimum stay but not the block
+	stay := &models.Booking{TenantID: "tenant-1", PropertyID: "moema", CheckIn: stayNight(7), CheckOut: stayNight(8), GuestName: "Carla Dias"}
+	err := service.CreateBooking(ctx, stay, "admin-1")
+	assert.True(t, errors.Is(err, ErrBookingConflict))
+
+	stay = &models.Booking{TenantID: "tenant-1", PropertyID: "moema", CheckIn: stayNight(8), CheckOut: stayNight(9), GuestName: "Carla Dias"}
+	require.NoError(t, service.CreateBooking(ctx, stay, "admin-1"))
+	assert.Equal(t, models.BookingStatusConfirmed, stay.Status)
+	require.NotNil(t, stay.Quote)
+	assert.Equal(t, 400.0, stay.Quote.Total)
+
+	// Guests are required for stays
+	err = service.CreateBooking(ctx, &models.Booking{TenantID: "tenant-1", PropertyID: "moema", CheckIn: stayNight(20), CheckOut: stayNight(22)}, "admin-1")
+	assert.True(t, errors.Is(err, repositories.ErrInvalidInput))
+}
+
+func TestProcessExpiredBookings(t *testing.T) {
+	ctx := context.Background()
+	service := newBookingTestService(t)
+
+	booking, err := service.RequestBooking(ctx, bookingTestLead("Ana Costa", "(11) 98888-0000"), stayNight(10), stayNight(12), 2)
+	require.NoError(t, err)
+	require.NoError(t, service.bookingRepo.Update(ctx, "tenant-1", booking.ID, map[string]interface{}{
+		"expires_at": time.Now().Add(-time.Minute),
+	}))
+
+	// The nights are free from the deadline on, even before the job runs
+	_, err = service.ConfirmBooking(ctx, "tenant-1", booking.ID, "admin-1")
+	assert.True(t, errors.Is(err, repositories.ErrInvalidInput))
+	_, err = service.Quote(ctx, "tenant-1", "moema", stayNight(10), stayNight(12), 2)
+	assert.NoError(t, err)
+
+	response, err := service.ProcessExpiredBookings(ctx, "tenant-1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, response.Expired)
+
+	expired, err := service.GetBooking(ctx, "tenant-1", booking.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.BookingStatusExpired, expired.Status)
+	assert.NotNil(t, expired.CancelledAt)
+}
+
+// icsFeed is an external calendar served over HTTP whose content can change
+type icsFeed struct {
+	mu   sync.Mutex
+	body string
+}
+
+func (f *icsFeed) set(events ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.body = "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//Airbnb Inc//Hosting Calendar//EN\r\n" + strings.Join(events, "") + "END:VCALENDAR\r\n"
+}
+
+func (f *icsFeed) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w.Header().Set("Content-Type", "text/calendar")
+	_, _ = w.Write([]byte(f.body))
+}
+
+func icsEvent(uid string, checkIn, checkOut time.Time) string {
+	return "BEGIN:VEVENT\r\nUID:" + uid + "\r\nDTSTART;VALUE=DATE:" + checkIn.Format("20060102") +
+		"\r\nDTEND;VALUE=DATE:" + checkOut.Format("20060102") + "\r\nSUMMARY:Reserved\r\nEND:VEVENT\r\n"
+}
+
+func TestSyncCalendar_ImportsExternalReservations(t *testing.T) {
+	ctx := context.Background()
+	service := newBookingTestService(t)
+
+	feed := &icsFeed{}
+	feed.set(
+		icsEvent("a1@airbnb.com", stayNight(3), stayNight(6)),
+		icsEvent("a2@airbnb.com", stayNight(20), stayNight(25)),
+		icsEvent("old@airbnb.com", stayNight(-10), stayNight(-5)),
+	)
+	server := httptest.NewServer(feed)
+	defer server.Close()
+
+	_, err := service.SetCalendar(ctx, "tenant-1", "moema", models.StayCalendar{
+		NightlyRate: 400,
+		Feeds:       []models.CalendarFeed{{Name: "Airbnb", URL: server.URL + "/calendar.ics"}},
+	}, "admin-1")
+	require.NoError(t, err)
+
+	result, err := service.SyncCalendar(ctx, "tenant-1", "moema")
+	require.NoError(t, err)
+	require.Len(t, result.Feeds, 1)
+	assert.Empty(t, result.Feeds[0].Error)
+	assert.Equal(t, 2, result.Feeds[0].Events)
+	assert.Equal(t, 2, result.Feeds[0].Created)
+
+	_, err = service.Quote(ctx, "tenant-1", "moema", stayNight(4), stayNight(7), 1)
+	assert.True(t, errors.Is(err, ErrBookingConflict))
+
+	// Imported blocks follow their calendar
+	blocks, err := service.ListBookings(ctx, "tenant-1", &repositories.BookingFilters{Source: models.BookingSourceICal}, repositories.PaginationOptions{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, blocks, 2)
+	_, err = service.CancelBooking(ctx, "tenant-1", blocks[0].ID, "admin-1", "")
+	assert.True(t, errors.Is(err, repositories.ErrInvalidInput))
+
+	// Moved and removed reservations
+	feed.set(icsEvent("a1@airbnb.com", stayNight(4), stayNight(6)))
+	result, err = service.SyncCalendar(ctx, "tenant-1", "moema")
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Feeds[0].Updated)
+	assert.Equal(t, 1, result.Feeds[0].Removed)
+
+	_, err = service.Quote(ctx, "tenant-1", "moema", stayNight(20), stayNight(25), 1)
+	assert.NoError(t, err)
+
+	calendar, err := service.GetCalendar(ctx, "tenant-1", "moema")
+	require.NoError(t, err)
+	require.Len(t, calendar.Feeds, 1)
+	assert.NotNil(t, calendar.Feeds[0].LastSyncedAt)
+	assert.Equal(t, 1, calendar.Feeds[0].Events)
+
+	// An unreachable calendar keeps its blocks and records the error
+	server.Close()
+	result, err = service.SyncCalendar(ctx, "tenant-1", "moema")
+	require.NoError(t, err)
+	assert.NotEmpty(t, result.Feeds[0].Error)
+	_, err = service.Quote(ctx, "tenant-1", "moema", stayNight(4), stayNight(6), 1)
+	assert.True(t, errors.Is(err, ErrBookingConflict))
+}
+
+func TestExportCalendar(t *testing.T) {
+	ctx := context.Background()
+	service := newBookingTestService(t)
+
+	_, err := service.RotateExportToken(ctx, "tenant-1", "moema", "admin-1")
+	assert.True(t, errors.Is(err, repositories.ErrInvalidInput), "calendar not configured yet")
+
+	_, err = service.SetCalendar(ctx, "tenant-1", "moema", models.StayCalendar{NightlyRate: 400}, "admin-1")
+	require.NoError(t, err)
+	token, err := service.RotateExportToken(ctx, "tenant-1", "moema", "admin-1")
+	require.NoError(t, err)
+
+	_, err = service.RequestBooking(ctx, bookingTestLead("Ana Costa", "(11) 98888-0000"), stayNight(10), stayNight(12), 2)
+	require.NoError(t, err)
+
+	data, err := service.ExportCalendar(ctx, "tenant-1", "moema", token)
+	require.NoError(t, err)
+	ics := string(data)
+	assert.Contains(t, ics, "X-WR-CALNAME:Disponibilidade - AP00335")
+	assert.Contains(t, ics, "DTSTART;VALUE=DATE:"+stayNight(10).Format("20060102"))
+	assert.Contains(t, ics, "DTEND;VALUE=DATE:"+stayNight(12).Format("20060102"))
+	assert.Contains(t, ics, "STATUS:TENTATIVE")
+	assert.NotContains(t, ics, "Ana Costa")
+
+	_, err = service.ExportCalendar(ctx, "tenant-1", "moema", "wrong")
+	assert.True(t, errors.Is(err, repositories.ErrNotFound))
+}
@@ -13,22 +13,22 @@ import (
 
 // BrokerService handles business logic for broker management
 type BrokerService struct {
-	brokerRepo             *repositories.BrokerRepository
-	tenantRepo             *repositories.TenantRepository
-	activityLogRepo        *repositories.ActivityLogRepository
-	propertyBrokerRoleRepo *repositories.PropertyBrokerRoleRepository
-	propertyRepo           *repositories.PropertyRepository
-	listingRepo            *repositories.ListingRepository
+	brokerRepo             repositories.BrokerStore
+	tenantRepo             repositories.TenantStore
+	activityLogRepo        repositories.ActivityLogStore
+	propertyBrokerRoleRepo repositories.PropertyBrokerRoleStore
+	propertyRepo           repositories.PropertyStore
+	listingRepo            repositories.ListingStore
 }
 
 // NewBrokerService creates a new broker service
 func NewBrokerService(
-	brokerRepo *repositories.BrokerRepository,
-	tenantRepo *repositories.TenantRepository,
-	activityLogRepo *repositories.ActivityLogRepository,
-	propertyBrokerRoleRepo *repositories.PropertyBrokerRoleRepository,
-	propertyRepo *repositories.PropertyRepository,
-	listingRepo *repositories.ListingRepository,
+	brokerRepo repositories.BrokerStore,
+	tenantRepo repositories.TenantStore,
+	activityLogRepo repositories.ActivityLogStore,
+	propertyBrokerRoleRepo repositories.PropertyBrokerRoleStore,
+	propertyRepo repositories.PropertyStore,
+	listingRepo repositories.ListingStore,
 ) *BrokerService {
 	return &BrokerService{
 		brokerRepo:             brokerRepo,
@@ -307,6 +307,10 @@ func (s *BrokerService) UpdateBroker(ctx context.Context, tenantID, id string, u
 	// Prevent updating tenant_id
 	delete(updates, "tenant_id")
 
+	// Availability and calendar token have their own endpoints (see VisitService)
+	delete(updates, "availability")
+	delete(updates, "calendar_token_hash")
+
 	// Update broker in repository
 	if err := s.brokerRepo.Update(ctx, tenantID, id, updates); err != nil {
 		return fmt.Errorf("failed to update broker: %w", err)
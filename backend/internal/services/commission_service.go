@@ -0,0 +1,579 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// commissionReportLimit caps the entries read by receivables and statements
+const commissionReportLimit = 2000
+
+// CommissionService calculates the commission of closed deals and keeps the
+// commission ledger (receivables per broker and monthly statements)
+type CommissionService struct {
+	commissionRepo  repositories.CommissionStore
+	proposalRepo    repositories.ProposalStore
+	propertyRepo    repositories.PropertyStore
+	roleRepo        repositories.PropertyBrokerRoleStore
+	brokerRepo      repositories.BrokerStore
+	tenantRepo      repositories.TenantStore
+	activityLogRepo repositories.ActivityLogStore
+}
+
+// NewCommissionService creates a new commission service
+func NewCommissionService(
+	commissionRepo repositories.CommissionStore,
+	proposalRepo repositories.ProposalStore,
+	propertyRepo repositories.PropertyStore,
+	roleRepo repositories.PropertyBrokerRoleStore,
+	brokerRepo repositories.BrokerStore,
+	tenantRepo repositories.TenantStore,
+	activityLogRepo repositories.ActivityLogStore,
+) *CommissionService {
+	return &CommissionService{
+		commissionRepo:  commissionRepo,
+		proposalRepo:    proposalRepo,
+		propertyRepo:    propertyRepo,
+		roleRepo:        roleRepo,
+		brokerRepo:      brokerRepo,
+		tenantRepo:      tenantRepo,
+		activityLogRepo: activityLogRepo,
+	}
+}
+
+// RecordCommissionRequest describes a closed deal
+type RecordCommissionRequest struct {
+	TenantID        string
+	DealID          string // default: the proposal ID
+	ProposalID      string // Accepted proposal; defaults the property, transaction and price
+	PropertyID      string
+	TransactionType models.TransactionType // sale (default) or rent
+	Price           float64                // Sale price or monthly rent
+	ClosedAt        time.Time              // default: now
+
+	// Brokers of the deal; default: the property's broker roles
+	Roles []*models.PropertyBrokerRole
+}
+
+// CalculateSplit returns the commission entries a deal on the property would
+// produce under the tenant's rules, without recording them
+func (s *CommissionService) CalculateSplit(ctx context.Context, tenantID, propertyID string, transaction models.TransactionType, price float64) ([]*models.CommissionEntry, error) {
+	return s.calculateSplit(ctx, tenantID, propertyID, nil, transaction, price)
+}
+
+// calculateSplit splits the commission among the given brokers, or the
+// property's broker roles when none are given
+func (s *CommissionService) calculateSplit(ctx context.Context, tenantID, propertyID string, roles []*models.PropertyBrokerRole, transaction models.TransactionType, price float64) ([]*models.CommissionEntry, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+	if transaction == "" {
+		transaction = models.TransactionTypeSale
+	}
+	if transaction != models.TransactionTypeSale && transaction != models.TransactionTypeRent {
+		return nil, fmt.Errorf("%w: transaction_type must be 'sale' or 'rent'", repositories.ErrInvalidInput)
+	}
+	if price <= 0 {
+		return nil, fmt.Errorf("%w: price must be positive", repositories.ErrInvalidInput)
+	}
+
+	tenant, err := s.tenantRepo.Get(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("tenant not found: %w", err)
+	}
+	property, err := s.propertyRepo.Get(ctx, tenantID, propertyID)
+	if err != nil {
+		return nil, fmt.Errorf("property not found: %w", err)
+	}
+	if roles == nil {
+		roles, err = s.roleRepo.ListByProperty(ctx, tenantID, propertyID, repositories.PaginationOptions{Limit: 100})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get property roles: %w", err)
+		}
+	}
+
+	return splitCommission(tenant.EffectiveCommission(), property, roles, transaction, price)
+}
+
+// RecordCommission records the commission entries of a closed deal, all
+// pending. A deal is recorded once; cancel it to record it again.
+func (s *CommissionService) RecordCommission(ctx context.Context, req RecordCommissionRequest, actorID string) ([]*models.CommissionEntry, error) {
+	if req.TenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+
+	if req.ProposalID != "" {
+		proposal, err := s.proposalRepo.Get(ctx, req.TenantID, req.ProposalID)
+		if err != nil {
+			return nil, fmt.Errorf("proposal not found: %w", err)
+		}
+		if proposal.Status != models.ProposalStatusAccepted {
+			return nil, fmt.Errorf("%w: proposal is %s, not accepted", repositories.ErrInvalidInput, proposal.Status)
+		}
+		if req.DealID == "" {
+			req.DealID = proposal.ID
+		}
+		if req.PropertyID == "" {
+			req.PropertyID = proposal.PropertyID
+		}
+		if req.TransactionType == "" {
+			req.TransactionType = proposal.TransactionType
+		}
+		if req.Price == 0 {
+			req.Price = proposal.Terms.Amount
+		}
+	}
+	if req.DealID == "" {
+		return nil, fmt.Errorf("%w: deal_id or proposal_id is required", repositories.ErrInvalidInput)
+	}
+	if req.PropertyID == "" {
+		return nil, fmt.Errorf("%w: property_id is required", repositories.ErrInvalidInput)
+	}
+	if req.ClosedAt.IsZero() {
+		req.ClosedAt = time.Now()
+	}
+
+	existing, err := s.commissionRepo.List(ctx, req.TenantID, &repositories.CommissionEntryFilters{
+		DealID:   req.DealID,
+		Statuses: []models.CommissionEntryStatus{models.CommissionEntryStatusPending, models.CommissionEntryStatusPaid},
+	}, repositories.PaginationOptions{Limit: 1})
+	if err != nil {
+		return nil, fmt.Errorf("failed to check deal commission: %w", err)
+	}
+	if len(existing) > 0 {
+		return nil, fmt.Errorf("%w: commission of deal %s already recorded", repositories.ErrInvalidInput, req.DealID)
+	}
+
+	entries, err := s.calculateSplit(ctx, req.TenantID, req.PropertyID, req.Roles, req.TransactionType, req.Price)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		entry.DealID = req.DealID
+		entry.ProposalID = req.ProposalID
+		entry.ClosedAt = req.ClosedAt
+		entry.Period = models.CommissionPeriod(req.ClosedAt)
+	}
+
+	if err := s.commissionRepo.CreateEntries(ctx, entries); err != nil {
+		return nil, fmt.Errorf("failed to record commission: %w", err)
+	}
+
+	total := 0.0
+	if len(entries) > 0 {
+		total = entries[0].TotalCommission
+	}
+	_ = s.logActivity(ctx, req.TenantID, "commission_recorded", actorID, map[string]interface{}{
+		"deal_id":          req.DealID,
+		"property_id":      req.PropertyID,
+		"price":            req.Price,
+		"total_commission": total,
+		"entries":          len(entries),
+	})
+
+	return entries, nil
+}
+
+// MarkPaid settles a pending commission entry
+func (s *CommissionService) MarkPaid(ctx context.Context, tenantID, id, reference, actorID string) (*models.CommissionEntry, error) {
+	entry, err := s.GetEntry(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if entry.Status != models.CommissionEntryStatusPending {
+		return nil, fmt.Errorf("%w: commission entry is %s", repositories.ErrInvalidInput, entry.Status)
+	}
+
+	now := time.Now()
+	entry.Status = models.CommissionEntryStatusPaid
+	entry.PaidAt = &now
+	entry.PaymentReference = strings.TrimSpace(reference)
+
+	if err := s.commissionRepo.Update(ctx, tenantID, id, map[string]interface{}{
+		"status":            entry.Status,
+		"paid_at":           entry.PaidAt,
+		"payment_reference": entry.PaymentReference,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to update commission entry: %w", err)
+	}
+
+	_ = s.logActivity(ctx, tenantID, "commission_paid", actorID, map[string]interface{}{
+		"entry_id":  id,
+		"deal_id":   entry.DealID,
+		"broker_id": entry.BrokerID,
+		"amount":    entry.Amount,
+	})
+
+	return entry, nil
+}
+
+// CancelDeal cancels the pending entries of a deal that fell through. Paid
+// entries are kept and must be settled outside the ledger.
+func (s *CommissionService) CancelDeal(ctx context.Context, tenantID, dealID, reason, actorID string) (int, error) {
+	if tenantID == "" {
+		return 0, fmt.Errorf("tenant_id is required")
+	}
+
+	entries, err := s.commissionRepo.List(ctx, tenantID, &repositories.CommissionEntryFilters{DealID: dealID}, repositories.PaginationOptions{Limit: 100})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list deal commission: %w", err)
+	}
+	if len(entries) == 0 {
+		return 0, fmt.Errorf("commission of deal %s: %w", dealID, repositories.ErrNotFound)
+	}
+
+	now := time.Now()
+	cancelled := 0
+	for _, entry := range entries {
+		if entry.Status != models.CommissionEntryStatusPending {
+			continue
+		}
+		if err := s.commissionRepo.Update(ctx, tenantID, entry.ID, map[string]interface{}{
+			"status":        models.CommissionEntryStatusCancelled,
+			"cancelled_at":  now,
+			"cancel_reason": strings.TrimSpace(reason),
+		}); err != nil {
+			return cancelled, fmt.Errorf("failed to cancel commission entry: %w", err)
+		}
+		cancelled++
+	}
+
+	_ = s.logActivity(ctx, tenantID, "commission_cancelled", actorID, map[string]interface{}{
+		"deal_id":   dealID,
+		"cancelled": cancelled,
+		"reason":    reason,
+	})
+
+	return cancelled, nil
+}
+
+// GetEntry retrieves a commission entry by ID
+func (s *CommissionService) GetEntry(ctx context.Context, tenantID, id string) (*models.CommissionEntry, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+
+	entry, err := s.commissionRepo.Get(ctx, tenantID, id)
+	if err != nil {
+		return nil, fmt.Errorf("commission entry not found: %w", err)
+	}
+	return entry, nil
+}
+
+// ListEntries lists commission entries with filters, most recently closed first
+func (s *CommissionService) ListEntries(ctx context.Context, tenantID string, filters *repositories.CommissionEntryFilters, opts repositories.PaginationOptions) ([]*models.CommissionEntry, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+
+	entries, err := s.commissionRepo.List(ctx, tenantID, filters, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commission entries: %w", err)
+	}
+	return entries, nil
+}
+
+// BrokerReceivable sums the pending commission of a broker
+type BrokerReceivable struct {
+	BrokerID       string    `json:"broker_id"`
+	BrokerName     string    `json:"broker_name,omitempty"`
+	Pending        float64   `json:"pending"`
+	Entries        int       `json:"entries"`
+	OldestClosedAt time.Time `json:"oldest_closed_at"`
+}
+
+// Receivables returns the pending commission of each broker, largest first
+func (s *CommissionService) Receivables(ctx context.Context, tenantID string) ([]*BrokerReceivable, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+
+	entries, err := s.commissionRepo.List(ctx, tenantID, &repositories.CommissionEntryFilters{
+		Beneficiary: models.CommissionBeneficiaryBroker,
+		Statuses:    []models.CommissionEntryStatus{models.CommissionEntryStatusPending},
+	}, repositories.PaginationOptions{Limit: commissionReportLimit})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending commission: %w", err)
+	}
+
+	byBroker := make(map[string]*BrokerReceivable)
+	for _, entry := range entries {
+		receivable := byBroker[entry.BrokerID]
+		if receivable == nil {
+			receivable = &BrokerReceivable{BrokerID: entry.BrokerID, OldestClosedAt: entry.ClosedAt}
+			byBroker[entry.BrokerID] = receivable
+		}
+		receivable.Pending = roundCents(receivable.Pending + entry.Amount)
+		receivable.Entries++
+		if entry.ClosedAt.Before(receivable.OldestClosedAt) {
+			receivable.OldestClosedAt = entry.ClosedAt
+		}
+	}
+
+	receivables := make([]*BrokerReceivable, 0, len(byBroker))
+	for _, receivable := range byBroker {
+		if broker, err := s.brokerRepo.Get(ctx, tenantID, receivable.BrokerID); err == nil {
+			receivable.BrokerName = broker.Name
+		}
+		receivables = append(receivables, receivable)
+	}
+	sort.Slice(receivables, func(i, j int) bool {
+		if receivables[i].Pending != receivables[j].Pending {
+			return receivables[i].Pending > receivables[j].Pending
+		}
+		return receivables[i].BrokerID < receivables[j].BrokerID
+	})
+
+	return receivables, nil
+}
+
+// CommissionStatement is the commission of a month, of one broker or of the
+// whole agency (cancelled entries left out)
+type CommissionStatement struct {
+	Period      string                     `json:"period"` // YYYY-MM
+	BrokerID    string                     `json:"broker_id,omitempty"`
+	BrokerName  string                     `json:"broker_name,omitempty"`
+	Lines       []*CommissionStatementLine `json:"lines"`
+	Total       float64                    `json:"total"`
+	Paid        float64                    `json:"paid"`
+	Pending     float64                    `json:"pending"`
+	AgencyTotal float64                    `json:"agency_total,omitempty"` // Agency statements only
+}
+
+// CommissionStatementLine is a ledger entry with the names a statement shows
+type CommissionStatementLine struct {
+	models.CommissionEntry
+	BrokerName        string `json:"broker_name,omitempty"`
+	PropertyReference string `json:"property_reference,omitempty"`
+}
+
+// Statement returns the commission statement of a month (YYYY-MM); without a
+// broker it covers every entry, agency share included
+func (s *CommissionService) Statement(ctx context.Context, tenantID, period, brokerID string) (*CommissionStatement, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+	if _, err := time.Parse("2006-01", period); err != nil {
+		return nil, fmt.Errorf("%w: period must be YYYY-MM", repositories.ErrInvalidInput)
+	}
+
+	entries, err := s.commissionRepo.List(ctx, tenantID, &repositories.CommissionEntryFilters{
+		Period:   period,
+		BrokerID: brokerID,
+		Statuses: []models.CommissionEntryStatus{models.CommissionEntryStatusPending, models.CommissionEntryStatusPaid},
+	}, repositories.PaginationOptions{Limit: commissionReportLimit, OrderBy: "closed_at", Direction: firestore.Asc})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commission entries: %w", err)
+	}
+
+	statement := &CommissionStatement{Period: period, BrokerID: brokerID, Lines: make([]*CommissionStatementLine, 0, len(entries))}
+	brokerNames := make(map[string]string)
+	references := make(map[string]string)
+	for _, entry := range entries {
+		line := &CommissionStatementLine{CommissionEntry: *entry}
+		if entry.BrokerID != "" {
+			if _, ok := brokerNames[entry.BrokerID]; !ok {
+				if broker, err := s.brokerRepo.Get(ctx, tenantID, entry.BrokerID); err == nil {
+					brokerNames[entry.BrokerID] = broker.Name
+				}
+			}
+			line.BrokerName = brokerNames[entry.BrokerID]
+		}
+		if _, ok := references[entry.PropertyID]; !ok {
+			if property, err := s.propertyRepo.Get(ctx, tenantID, entry.PropertyID); err == nil {
+				references[entry.PropertyID] = propertyReference(property)
+			}
+		}
+		line.PropertyReference = references[entry.PropertyID]
+		statement.Lines = append(statement.Lines, line)
+
+		statement.Total = roundCents(statement.Total + entry.Amount)
+		if entry.Status == models.CommissionEntryStatusPaid {
+			statement.Paid = roundCents(statement.Paid + entry.Amount)
+		} else {
+			statement.Pending = roundCents(statement.Pending + entry.Amount)
+		}
+		if entry.Beneficiary == models.CommissionBeneficiaryAgency {
+			statement.AgencyTotal = roundCents(statement.AgencyTotal + entry.Amount)
+		}
+	}
+	if brokerID != "" {
+		statement.BrokerName = brokerNames[brokerID]
+	}
+
+	return statement, nil
+}
+
+// StatementCSV exports a statement as CSV for spreadsheets (";" separated,
+// decimal comma)
+func (s *CommissionService) StatementCSV(statement *CommissionStatement) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Comma = ';'
+
+	_ = w.Write([]string{"fechamento", "negocio", "imovel", "tipo", "valor_negocio", "comissao_total", "beneficiario", "papel", "participacao_pct", "valor", "status", "pago_em"})
+	for _, line := range statement.Lines {
+		beneficiary := line.BrokerName
+		if beneficiary == "" {
+			beneficiary = line.BrokerID
+		}
+		if line.Beneficiary == models.CommissionBeneficiaryAgency {
+			beneficiary = "Imobiliária"
+		}
+		transaction := "venda"
+		if line.TransactionType == models.TransactionTypeRent {
+			transaction = "locação"
+		}
+		paidAt := ""
+		if line.PaidAt != nil {
+			paidAt = line.PaidAt.Format("02/01/2006")
+		}
+		reference := line.PropertyReference
+		if reference == "" {
+			reference = line.PropertyID
+		}
+
+		_ = w.Write([]string{
+			line.ClosedAt.Format("02/01/2006"),
+			line.DealID,
+			reference,
+			transaction,
+			decimalBR(line.DealPrice),
+			decimalBR(line.TotalCommission),
+			beneficiary,
+			string(line.Role),
+			decimalBR(line.SharePercentage),
+			decimalBR(line.Amount),
+			string(line.Status),
+			paidAt,
+		})
+	}
+	_ = w.Write([]string{"total", "", "", "", "", "", "", "", "", decimalBR(statement.Total), "", ""})
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("failed to write statement: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// splitCommission splits the commission of a deal between the brokers of the
+// property and the agency. Each role takes its share of the tenant rules
+// (Property.CoBrokerCommission overrides the co_broker share); brokers
+// sharing a role split it equally unless their role sets CommissionPercentage.
+// The agency keeps the rest, rounding included.
+func splitCommission(settings models.CommissionSettings, property *models.Property, roles []*models.PropertyBrokerRole, transaction models.TransactionType, price float64) ([]*models.CommissionEntry, error) {
+	rate := settings.Rate(transaction)
+	total := roundCents(price * rate / 100)
+
+	newEntry := func(beneficiary models.CommissionBeneficiary, brokerID string, role models.BrokerPropertyRole, share, amount float64) *models.CommissionEntry {
+		return &models.CommissionEntry{
+			TenantID:        property.TenantID,
+			PropertyID:      property.ID,
+			TransactionType: transaction,
+			DealPrice:       price,
+			CommissionRate:  rate,
+			TotalCommission: total,
+			Beneficiary:     beneficiary,
+			BrokerID:        brokerID,
+			Role:            role,
+			SharePercentage: share,
+			Amount:          amount,
+			Status:          models.CommissionEntryStatusPending,
+		}
+	}
+
+	byRole := make(map[models.BrokerPropertyRole][]*models.PropertyBrokerRole)
+	for _, role := range roles {
+		byRole[role.Role] = append(byRole[role.Role], role)
+	}
+
+	var entries []*models.CommissionEntry
+	brokerShare, brokerAmount := 0.0, 0.0
+	for _, role := range []models.BrokerPropertyRole{models.BrokerPropertyRoleOriginating, models.BrokerPropertyRoleListing, models.BrokerPropertyRoleCoBroker} {
+		holders := byRole[role]
+		if len(holders) == 0 {
+			continue
+		}
+
+		roleShare := settings.RoleShare(role)
+		if role == models.BrokerPropertyRoleCoBroker && property.CoBrokerCommission > 0 {
+			roleShare = property.CoBrokerCommission
+		}
+
+		// Explicit percentages first; the other holders split what is left
+		explicit, implicit := 0.0, 0
+		for _, holder := range holders {
+			if holder.CommissionPercentage > 0 {
+				explicit += holder.CommissionPercentage
+			} else {
+				implicit++
+			}
+		}
+		rest := math.Max(roleShare-explicit, 0)
+
+		for _, holder := range holders {
+			share := holder.CommissionPercentage
+			if share <= 0 {
+				share = rest / float64(implicit)
+			}
+			if share <= 0 {
+				continue
+			}
+			amount := roundCents(total * share / 100)
+			brokerShare += share
+			brokerAmount += amount
+			entries = append(entries, newEntry(models.CommissionBeneficiaryBroker, holder.BrokerID, role, share, amount))
+		}
+	}
+
+	if brokerShare > 100+1e-9 {
+		return nil, fmt.Errorf("%w: broker shares add up to %.2f%% of the commission", repositories.ErrInvalidInput, brokerShare)
+	}
+	if agencyAmount := roundCents(total - brokerAmount); agencyAmount > 0 {
+		entries = append(entries, newEntry(models.CommissionBeneficiaryAgency, "", "", roundCents(100-brokerShare), agencyAmount))
+	}
+
+	return entries, nil
+}
+
+// roundCents rounds an amount to cents
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// decimalBR formats a number with two decimals and a decimal comma (1234,56)
+func decimalBR(value float64) string {
+	return strings.Replace(strconv.FormatFloat(value, 'f', 2, 64), ".", ",", 1)
+}
+
+// logActivity logs an activity; actions without an actor are the system's
+func (s *CommissionService) logActivity(ctx context.Context, tenantID, eventType, actorID string, metadata map[string]interface{}) error {
+	actorType := models.ActorTypeUser
+	if actorID == "" {
+		actorType = models.ActorTypeSystem
+	}
+
+	log := &models.ActivityLog{
+		TenantID:  tenantID,
+		EventType: eventType,
+		ActorType: actorType,
+		ActorID:   actorID,
+		Metadata:  metadata,
+		Timestamp: time.Now(),
+	}
+
+	return s.activityLogRepo.Create(ctx, log)
+}
@@ -0,0 +1,207 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories/memory"
+)
+
+// newCommissionTestService returns a commission service over the routing test
+// data, with broker-3 originating, broker-1 listing and broker-2 co-brokering
+// property "moema" (default tenant rules: 6%, shares 20/20/10)
+func newCommissionTestService(t *testing.T) *CommissionService {
+	t.Helper()
+	ctx := context.Background()
+
+	leadService := newRoutingTestService(t, nil)
+	for brokerID, role := range map[string]models.BrokerPropertyRole{
+		"broker-1": models.BrokerPropertyRoleListing,
+		"broker-2": models.BrokerPropertyRoleCoBroker,
+	} {
+		require.NoError(t, leadService.roleRepo.Create(ctx, &models.PropertyBrokerRole{TenantID: "tenant-1", PropertyID: "moema", BrokerID: brokerID, Role: role}))
+	}
+	require.NoError(t, leadService.propertyRepo.Update(ctx, "tenant-1", "moema", map[string]interface{}{"reference": "AP00335"}))
+
+	return NewCommissionService(
+		memory.NewCommissionRepository(),
+		memory.NewProposalRepository(),
+		leadService.propertyRepo,
+		leadService.roleRepo,
+		leadService.brokerRepo,
+		leadService.tenantRepo,
+		leadService.activityLogRepo,
+	)
+}
+
+// commissionAmounts maps the entries to broker ID ("agency" for the agency) and amount
+func commissionAmounts(entries []*models.CommissionEntry) map[string]float64 {
+	amounts := make(map[string]float64)
+	for _, entry := range entries {
+		key := entry.BrokerID
+		if entry.Beneficiary == models.CommissionBeneficiaryAgency {
+			key = "agency"
+		}
+		amounts[key] += entry.Amount
+	}
+	return amounts
+}
+
+func TestSplitCommission(t *testing.T) {
+	settings := models.DefaultCommissionSettings()
+	property := &models.Property{ID: "moema", TenantID: "tenant-1"}
+	roles := []*models.PropertyBrokerRole{
+		{BrokerID: "broker-3", Role: models.BrokerPropertyRoleOriginating},
+		{BrokerID: "broker-1", Role: models.BrokerPropertyRoleListing},
+		{BrokerID: "broker-2", Role: models.BrokerPropertyRoleCoBroker, CommissionPercentage: 15},
+		{BrokerID: "broker-4", Role: models.BrokerPropertyRoleCoBroker},
+	}
+
+	// The property offers 25% to co-brokers: broker-2 keeps its 15%, broker-4 the other 10%
+	property.CoBrokerCommission = 25
+	entries, err := splitCommission(settings, property, roles, models.TransactionTypeSale, 1000000)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{
+		"broker-3": 12000,
+		"broker-1": 12000,
+		"broker-2": 9000,
+		"broker-4": 6000,
+		"agency":   21000,
+	}, commissionAmounts(entries))
+	for _, entry := range entries {
+		assert.Equal(t, 60000.0, entry.TotalCommission)
+		assert.Equal(t, models.CommissionEntryStatusPending, entry.Status)
+	}
+
+	// Rent: one month; the agency absorbs the rounding
+	entries, err = splitCommission(settings, property, roles[:2], models.TransactionTypeRent, 3333.33)
+	require.NoError(t, err)
+	amounts := commissionAmounts(entries)
+	assert.Equal(t, 666.67, amounts["broker-3"])
+	assert.InDelta(t, 3333.33, amounts["broker-3"]+amounts["broker-1"]+amounts["agency"], 0.001)
+
+	// Explicit percentages cannot give away more than the whole commission
+	roles[0].CommissionPercentage = 95
+	_, err = splitCommission(settings, property, roles, models.TransactionTypeSale, 1000000)
+	assert.True(t, errors.Is(err, repositories.ErrInvalidInput))
+}
+
+func TestRecordCommission_FromAcceptedProposal(t *testing.T) {
+	ctx := context.Background()
+	service := newCommissionTestService(t)
+
+	proposal := &models.Proposal{
+		TenantID:        "tenant-1",
+		PropertyID:      "moema",
+		LeadID:          "lead-1",
+		TransactionType: models.TransactionTypeSale,
+		Status:          models.ProposalStatusSubmitted,
+		Terms:           models.ProposalTerms{Amount: 800000},
+	}
+	require.NoError(t, service.proposalRepo.Create(ctx, proposal))
+
+	// Only accepted proposals close a deal
+	_, err := service.RecordCommission(ctx, RecordCommissionRequest{TenantID: "tenant-1", ProposalID: proposal.ID}, "user-1")
+	assert.True(t, errors.Is(err, repositories.ErrInvalidInput))
+
+	require.NoError(t, service.proposalRepo.Update(ctx, "tenant-1", proposal.ID, map[string]interface{}{"status": models.ProposalStatusAccepted}))
+	closedAt := time.Date(2024, 7, 15, 14, 0, 0, 0, time.UTC)
+	entries, err := service.RecordCommission(ctx, RecordCommissionRequest{TenantID: "tenant-1", ProposalID: proposal.ID, ClosedAt: closedAt}, "user-1")
+	require.NoError(t, err)
+	require.Len(t, entries, 4)
+	assert.Equal(t, map[string]float64{"broker-3": 9600, "broker-1": 9600, "broker-2": 4800, "agency": 24000}, commissionAmounts(entries))
+	for _, entry := range entries {
+		assert.NotEmpty(t, entry.ID)
+		assert.Equal(t, proposal.ID, entry.DealID)
+		assert.Equal(t, "2024-07", entry.Period)
+	}
+
+	// A deal is recorded once
+	_, err = service.RecordCommission(ctx, RecordCommissionRequest{TenantID: "tenant-1", ProposalID: proposal.ID}, "user-1")
+	assert.True(t, errors.Is(err, repositories.ErrInvalidInput))
+
+	// Cancelling keeps paid entries and frees the deal
+	var paid *models.CommissionEntry
+	for _, entry := range entries {
+		if entry.BrokerID == "broker-3" {
+			paid = entry
+		}
+	}
+	_, err = service.MarkPaid(ctx, "tenant-1", paid.ID, "PIX 123", "user-1")
+	require.NoError(t, err)
+	cancelled, err := service.CancelDeal(ctx, "tenant-1", proposal.ID, "financiamento negado", "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, 3, cancelled)
+
+	stored, err := service.GetEntry(ctx, "tenant-1", paid.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.CommissionEntryStatusPaid, stored.Status)
+	assert.Equal(t, "PIX 123", stored.PaymentReference)
+}
+
+func TestCommissionReceivablesAndStatement(t *testing.T) {
+	ctx := context.Background()
+	service := newCommissionTestService(t)
+
+	july := time.Date(2024, 7, 10, 12, 0, 0, 0, time.UTC)
+	for i, price := range []float64{1000000, 500000} {
+		_, err := service.RecordCommission(ctx, RecordCommissionRequest{
+			TenantID:   "tenant-1",
+			DealID:     []string{"deal-1", "deal-2"}[i],
+			PropertyID: "moema",
+			Price:      price,
+			ClosedAt:   july.AddDate(0, 0, i),
+		}, "user-1")
+		require.NoError(t, err)
+	}
+	august, err := service.RecordCommission(ctx, RecordCommissionRequest{TenantID: "tenant-1", DealID: "deal-3", PropertyID: "moema", Price: 1000000, ClosedAt: july.AddDate(0, 1, 0)}, "user-1")
+	require.NoError(t, err)
+	for _, entry := range august {
+		if entry.BrokerID == "broker-3" {
+			_, err := service.MarkPaid(ctx, "tenant-1", entry.ID, "", "user-1")
+			require.NoError(t, err)
+		}
+	}
+
+	// Pending per broker: broker-3 got paid for August only
+	receivables, err := service.Receivables(ctx, "tenant-1")
+	require.NoError(t, err)
+	require.Len(t, receivables, 3)
+	pending := make(map[string]float64)
+	for _, receivable := range receivables {
+		pending[receivable.BrokerID] = receivable.Pending
+	}
+	assert.Equal(t, map[string]float64{"broker-1": 30000, "broker-2": 15000, "broker-3": 18000}, pending)
+	assert.Equal(t, "broker-1", receivables[0].BrokerID)
+
+	statement, err := service.Statement(ctx, "tenant-1", "2024-07", "broker-3")
+	require.NoError(t, err)
+	require.Len(t, statement.Lines, 2)
+	assert.Equal(t, 18000.0, statement.Total)
+	assert.Equal(t, 18000.0, statement.Pending)
+	assert.Equal(t, "AP00335", statement.Lines[0].PropertyReference)
+
+	agency, err := service.Statement(ctx, "tenant-1", "2024-07", "")
+	require.NoError(t, err)
+	assert.Len(t, agency.Lines, 8)
+	assert.Equal(t, 90000.0, agency.Total)
+	assert.Equal(t, 45000.0, agency.AgencyTotal)
+
+	csv, err := service.StatementCSV(statement)
+	require.NoError(t, err)
+	rows := strings.Split(strings.TrimSpace(string(csv)), "\n")
+	require.Len(t, rows, 4)
+	assert.Equal(t, "10/07/2024;deal-1;AP00335;venda;1000000,00;60000,00;broker-3;originating_broker;20,00;12000,00;pending;", rows[1])
+	assert.Equal(t, "total;;;;;;;;;18000,00;;", rows[3])
+
+	_, err = service.Statement(ctx, "tenant-1", "julho", "")
+	assert.True(t, errors.Is(err, repositories.ErrInvalidInput))
+}
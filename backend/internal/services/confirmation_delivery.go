@@ -0,0 +1,371 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/notify"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// Default messages (pt-BR), used when the tenant has no template of the name
+var defaultNotificationTemplates = map[string]models.NotificationTemplate{
+	models.NotificationTemplateOwnerConfirmation: {
+		Subject: "{{.TenantName}}: confirme a disponibilidade do imóvel {{.PropertyReference}}",
+		Body: "Olá{{if .OwnerName}} {{.OwnerName}}{{end}}! Aqui é da {{.TenantName}}. " +
+			"O imóvel {{.PropertyReference}}{{if .PropertyAddress}} ({{.PropertyAddress}}){{end}} continua disponível? " +
+			"Confirme ou atualize as informações pelo link: {{.ConfirmationURL}}",
+	},
+	models.NotificationTemplateCaptadorReminder: {
+		Subject: "Proprietário sem resposta: imóvel {{.PropertyReference}}",
+		Body: "Olá{{if .BrokerName}} {{.BrokerName}}{{end}}! O proprietário{{if .OwnerName}} {{.OwnerName}}{{end}} " +
+			"ainda não respondeu à confirmação do imóvel {{.PropertyReference}}{{if .PropertyAddress}} ({{.PropertyAddress}}){{end}}, " +
+			"enviada há {{.DaysWaiting}} dias. Entre em contato e compartilhe o link: {{.ConfirmationURL}}",
+	},
+	models.NotificationTemplateLeadSLABreach: {
+		Subject: "Lead sem atendimento: {{.LeadName}} (imóvel {{.PropertyReference}})",
+		Body: "Olá{{if .ManagerName}} {{.ManagerName}}{{end}}! O lead {{.LeadName}} ({{.Channel}}) do imóvel {{.PropertyReference}} " +
+			"não foi atendido em {{.TargetMinutes}} minutos{{if .BrokerName}} por {{.BrokerName}}{{end}}." +
+			"{{if .NewBrokerName}} Ele foi redistribuído para {{.NewBrokerName}}.{{end}}",
+	},
+	models.NotificationTemplateLeadTaskReminder: {
+		Subject: "Lembrete: {{.TaskTitle}}",
+		Body: "Olá{{if .BrokerName}} {{.BrokerName}}{{end}}! Lembrete da tarefa \"{{.TaskTitle}}\" do lead {{.LeadName}}, " +
+			"com vencimento em {{.DueAt}}.{{if .Description}} {{.Description}}{{end}}",
+	},
+	models.NotificationTemplateVisitRequested: {
+		Subject: "Pedido de visita: imóvel {{.PropertyReference}} em {{.StartAt}}",
+		Body: "Olá{{if .RecipientName}} {{.RecipientName}}{{end}}! {{.LeadName}} pediu uma visita ao imóvel {{.PropertyReference}}" +
+			"{{if .PropertyAddress}} ({{.PropertyAddress}}){{end}} em {{.StartAt}}. Confirme ou cancele a visita no painel." +
+			"{{if .Notes}} Mensagem: {{.Notes}}{{end}}",
+	},
+	models.NotificationTemplateVisitConfirmed: {
+		Subject: "{{.TenantName}}: visita confirmada em {{.StartAt}}",
+		Body: "Olá{{if .RecipientName}} {{.RecipientName}}{{end}}! Sua visita ao imóvel {{.PropertyReference}}" +
+			"{{if .PropertyAddress}} ({{.PropertyAddress}}){{end}} está confirmada para {{.StartAt}}" +
+			"{{if .BrokerName}} com {{.BrokerName}}{{end}}. {{.TenantName}}",
+	},
+	models.NotificationTemplateVisitCancelled: {
+		Subject: "Visita cancelada: imóvel {{.PropertyReference}} em {{.StartAt}}",
+		Body: "Olá{{if .RecipientName}} {{.RecipientName}}{{end}}! A visita ao imóvel {{.PropertyReference}} " +
+			"marcada para {{.StartAt}} foi cancelada.{{if .Reason}} Motivo: {{.Reason}}{{end}}",
+	},
+	models.NotificationTemplateProposalReceived: {
+		Subject: "{{.TenantName}}: proposta de {{.Amount}} para o imóvel {{.PropertyReference}}",
+		Body: "Olá{{if .RecipientName}} {{.RecipientName}}{{end}}! Recebemos uma {{if .Counter}}contraproposta{{else}}proposta{{end}} " +
+			"de {{.Amount}} para o imóvel {{.PropertyReference}}{{if .PropertyAddress}} ({{.PropertyAddress}}){{end}}" +
+			"{{if .PaymentConditions}}. Condições: {{.PaymentConditions}}{{end}}. Válida até {{.ValidUntil}}. " +
+			"{{if .BrokerName}}Fale com {{.BrokerName}} para responder.{{end}}",
+	},
+	models.NotificationTemplateProposalAccepted: {
+		Subject: "{{.TenantName}}: proposta aceita para o imóvel {{.PropertyReference}}",
+		Body: "Olá{{if .RecipientName}} {{.RecipientName}}{{end}}! A proposta de {{.Amount}} para o imóvel {{.PropertyReference}}" +
+			"{{if .PropertyAddress}} ({{.PropertyAddress}}){{end}} foi aceita. " +
+			"{{if .BrokerName}}{{.BrokerName}} entrará em contato com os próximos passos.{{end}}",
+	},
+}
+
+// ConfirmationMessageData is the data available to the owner confirmation
+// and captador reminder templates
+type ConfirmationMessageData struct {
+	OwnerName         string
+	BrokerName        string
+	TenantName        string
+	PropertyReference string
+	PropertyAddress   string
+	ConfirmationURL   string
+	DaysWaiting       int // Days since the confirmation was sent
+}
+
+// recipient is the addressee of a message
+type recipient struct {
+	Name  string
+	Phone string
+	Email string
+}
+
+// contact returns the recipient's address on a channel
+func (r recipient) contact(channel models.NotificationChannel) string {
+	switch channel {
+	case models.NotificationChannelWhatsApp, models.NotificationChannelSMS:
+		return r.Phone
+	case models.NotificationChannelEmail:
+		return r.Email
+	}
+	return ""
+}
+
+// SetNotifier enables automatic delivery of scheduled confirmations. Without
+// a notifier they are left for the broker to send manually.
+func (s *MonthlyConfirmationScheduler) SetNotifier(notifier *notify.Dispatcher) {
+	s.notifier = notifier
+}
+
+// SetTenantRepository sets the tenant repository used to load the channel
+// preference and message templates of each tenant
+func (s *MonthlyConfirmationScheduler) SetTenantRepository(tenantRepo repositories.TenantStore) {
+	s.tenantRepo = tenantRepo
+}
+
+// deliver sends a scheduled confirmation to the owner and records the
+// outcome on sc; without a notifier or owner contact it is left for manual
+// delivery
+func (s *MonthlyConfirmationScheduler) deliver(ctx context.Context, tenant *models.Tenant, sc *models.ScheduledConfirmation, owner *models.Owner) {
+	now := time.Now()
+	sc.SentAt = &now
+
+	if owner == nil {
+		s.markManualDelivery(sc)
+		return
+	}
+
+	data := s.messageData(ctx, tenant, sc, owner.Name)
+	channel, result, attempts, err := s.send(ctx, tenant, models.NotificationTemplateOwnerConfirmation,
+		recipient{Name: owner.Name, Phone: owner.Phone, Email: owner.Email}, data,
+		[]string{data.OwnerName, data.PropertyReference, data.ConfirmationURL})
+
+	switch {
+	case err != nil:
+		sc.Status = models.ScheduledConfirmationStatusFailed
+		sc.DeliveryStatus = models.DeliveryStatusFailed
+		sc.DeliveryError = err.Error()
+		sc.DeliveryAttempts += attempts
+	case channel == "":
+		// No channel configured or no contact for any of them
+		s.markManualDelivery(sc)
+	default:
+		recordDelivery(sc, channel, result, attempts)
+	}
+}
+
+// send renders the named template and sends it to the recipient (see
+// sendNotification)
+func (s *MonthlyConfirmationScheduler) send(ctx context.Context, tenant *models.Tenant, name string, to recipient, data ConfirmationMessageData, whatsAppParameters []string) (models.NotificationChannel, *notify.Result, int, error) {
+	return sendNotification(ctx, s.notifier, tenant, name, to, data, whatsAppParameters)
+}
+
+// sendNotification renders the named template and sends it to the recipient
+// through the first of the tenant's channels it can be reached on, falling
+// back to the next channel when one fails. An empty channel with a nil error
+// means no channel was available (or notifier is nil).
+func sendNotification(ctx context.Context, notifier *notify.Dispatcher, tenant *models.Tenant, name string, to recipient, data interface{}, whatsAppParameters []string) (models.NotificationChannel, *notify.Result, int, error) {
+	if notifier == nil {
+		return "", nil, 0, nil
+	}
+
+	tmpl := notificationTemplate(tenant, name)
+
+	var lastErr error
+	attempts := 0
+	for _, channel := range tenant.NotificationChannels() {
+		address := to.contact(channel)
+		if address == "" || !notifier.Supports(channel) {
+			continue
+		}
+
+		msg, err := buildMessage(channel, name, tmpl, data)
+		if err != nil {
+			return "", nil, attempts, err
+		}
+		msg.To = address
+		msg.ToName = to.Name
+		if channel == models.NotificationChannelWhatsApp && tmpl.WhatsAppTemplate != "" {
+			msg.WhatsAppParameters = whatsAppParameters
+		}
+
+		result, err := notifier.Send(ctx, msg)
+		if result != nil {
+			attempts += result.Attempts
+		}
+		if err != nil {
+			log.Printf("⚠️  Failed to send %s message via %s: %v", name, channel, err)
+			lastErr = err
+			continue
+		}
+		return channel, result, attempts, nil
+	}
+
+	return "", nil, attempts, lastErr
+}
+
+// recordDelivery records a message accepted by a provider on sc, replacing
+// the tracking of any previous message
+func recordDelivery(sc *models.ScheduledConfirmation, channel models.NotificationChannel, result *notify.Result, attempts int) {
+	sc.Status = models.ScheduledConfirmationStatusSent
+	sc.DeliveryMethod = string(channel)
+	sc.DeliveryStatus = result.Status
+	sc.DeliveryError = ""
+	sc.DeliveryAttempts += attempts
+	sc.ProviderMessageID = result.ProviderMessageID
+	sc.DeliveredAt = nil
+}
+
+// markManualDelivery leaves the confirmation link for the broker to send
+func (s *MonthlyConfirmationScheduler) markManualDelivery(sc *models.ScheduledConfirmation) {
+	sc.Status = models.ScheduledConfirmationStatusSent
+	sc.DeliveryMethod = "manual"
+	sc.DeliveryStatus = models.DeliveryStatusManualRequired
+}
+
+// messageData collects the template data of a scheduled confirmation
+func (s *MonthlyConfirmationScheduler) messageData(ctx context.Context, tenant *models.Tenant, sc *models.ScheduledConfirmation, ownerName string) ConfirmationMessageData {
+	data := ConfirmationMessageData{
+		OwnerName:       firstName(ownerName),
+		ConfirmationURL: sc.ConfirmationURL,
+	}
+	if tenant != nil {
+		data.TenantName = tenant.Name
+	}
+
+	property, err := s.propertyRepo.Get(ctx, sc.TenantID, sc.PropertyID)
+	if err != nil {
+		log.Printf("⚠️  Warning: could not get property %s for confirmation message: %v", sc.PropertyID, err)
+		data.PropertyReference = sc.PropertyID
+		return data
+	}
+
+	data.PropertyReference = propertyReference(property)
+	data.PropertyAddress = propertyAddress(property)
+	return data
+}
+
+// loadTenant returns the tenant, or nil (defaults) when it cannot be loaded
+func (s *MonthlyConfirmationScheduler) loadTenant(ctx context.Context, tenantID string) *models.Tenant {
+	if s.tenantRepo == nil {
+		return nil
+	}
+	tenant, err := s.tenantRepo.Get(ctx, tenantID)
+	if err != nil {
+		log.Printf("⚠️  Failed to load notification settings of tenant %s, using defaults: %v", tenantID, err)
+		return nil
+	}
+	return tenant
+}
+
+// ApplyDeliveryStatus records a delivery status reported by a provider
+// webhook on the matching scheduled confirmation. Updates arriving out of
+// order never move the delivery status backwards.
+func (s *MonthlyConfirmationScheduler) ApplyDeliveryStatus(ctx context.Context, update notify.StatusUpdate) error {
+	sc, err := s.scheduledConfirmationRepo.GetByProviderMessageID(ctx, update.ProviderMessageID)
+	if err != nil {
+		return err
+	}
+
+	if !notify.Advances(sc.DeliveryStatus, update.Status) {
+		return nil
+	}
+
+	sc.DeliveryStatus = update.Status
+	switch update.Status {
+	case models.DeliveryStatusDelivered, models.DeliveryStatusRead:
+		if sc.DeliveredAt == nil {
+			deliveredAt := update.Timestamp
+			sc.DeliveredAt = &deliveredAt
+		}
+	case models.DeliveryStatusFailed:
+		sc.DeliveryError = update.Error
+		if sc.Status == models.ScheduledConfirmationStatusSent {
+			sc.Status = models.ScheduledConfirmationStatusFailed
+		}
+	}
+
+	if err := s.scheduledConfirmationRepo.Update(ctx, sc); err != nil {
+		return fmt.Errorf("failed to update delivery status: %w", err)
+	}
+	return nil
+}
+
+// notificationTemplate returns the tenant's template of the given name,
+// completed with the default subject and body
+func notificationTemplate(tenant *models.Tenant, name string) models.NotificationTemplate {
+	defaults := defaultNotificationTemplates[name]
+	tmpl, ok := tenant.NotificationTemplate(name)
+	if !ok {
+		return defaults
+	}
+	if tmpl.Subject == "" {
+		tmpl.Subject = defaults.Subject
+	}
+	if tmpl.Body == "" {
+		tmpl.Body = defaults.Body
+	}
+	return tmpl
+}
+
+// buildMessage renders a template for a channel
+func buildMessage(channel models.NotificationChannel, name string, tmpl models.NotificationTemplate, data interface{}) (notify.Message, error) {
+	msg := notify.Message{Channel: channel}
+
+	var err error
+	if msg.Body, err = notify.Render(name, tmpl.Body, data); err != nil {
+		return msg, err
+	}
+
+	switch channel {
+	case models.NotificationChannelEmail:
+		if msg.Subject, err = notify.Render(name+"_subject", tmpl.Subject, data); err != nil {
+			return msg, err
+		}
+	case models.NotificationChannelWhatsApp:
+		msg.WhatsAppTemplate = tmpl.WhatsAppTemplate
+		msg.WhatsAppLanguage = tmpl.WhatsAppLanguage
+	}
+
+	return msg, nil
+}
+
+// propertyAddress formats the short address of a property ("Rua X, 10 - Bairro")
+func propertyAddress(property *models.Property) string {
+	address := property.Street
+	if address != "" && property.Number != "" {
+		address += ", " + property.Number
+	}
+	if property.Neighborhood != "" {
+		if address != "" {
+			address += " - "
+		}
+		address += property.Neighborhood
+	}
+	return address
+}
+
+// propertyReference returns the reference code of a property, or its ID
+func propertyReference(property *models.Property) string {
+	if property.Reference != "" {
+		return property.Reference
+	}
+	return property.ID
+}
+
+// firstName returns the first word of a name
+func firstName(name string) string {
+	if fields := strings.Fields(name); len(fields) > 0 {
+		return fields[0]
+	}
+	return ""
+}
+
+// formatBRL formats an amount in reais ("R$ 1.234.567,89")
+func formatBRL(amount float64) string {
+	sign := ""
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	cents := int64(math.Round(amount * 100))
+	whole := strconv.FormatInt(cents/100, 10)
+
+	var grouped strings.Builder
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			grouped.WriteByte('.')
+		}
+		grouped.WriteRune(digit)
+	}
+	return fmt.Sprintf("%sR$ %s,%02d", sign, grouped.String(), cents%100)
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/notify"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories/memory"
+)
+
+// newDeliveryTestScheduler schedules one confirmation (captured by broker-1)
+// for today and returns the scheduler with its repository
+func newDeliveryTestScheduler(t *testing.T, notifiers ...notify.Notifier) (*MonthlyConfirmationScheduler, *memory.ScheduledConfirmationRepository) {
+	t.Helper()
+	ctx := context.Background()
+
+	propertyService, ownerConfirmationService := newHistoryTestServices(t)
+	property := createHistoryTestProperty(t, propertyService)
+	require.NoError(t, propertyService.propertyRepo.Update(ctx, "tenant-1", property.ID, map[string]interface{}{"captador_id": "broker-1"}))
+
+	ownerRepo := memory.NewOwnerRepository()
+	require.NoError(t, ownerRepo.Create(ctx, &models.Owner{
+		ID:       "owner-1",
+		TenantID: "tenant-1",
+		Name:     "Maria Souza",
+		Phone:    "(11) 98765-4321",
+		Email:    "maria@example.com",
+	}))
+
+	tenantRepo := memory.NewTenantRepository()
+	require.NoError(t, tenantRepo.Create(ctx, &models.Tenant{ID: "tenant-1", Name: "Imobiliária Teste", IsActive: true}))
+
+	confirmationRepo := memory.NewScheduledConfirmationRepository()
+	scheduler := NewMonthlyConfirmationScheduler(confirmationRepo, propertyService.propertyRepo, ownerRepo, ownerConfirmationService)
+	scheduler.SetTenantRepository(tenantRepo)
+	scheduler.SetPropertyService(propertyService)
+	ownerConfirmationService.SetScheduledConfirmationRepository(confirmationRepo)
+	if len(notifiers) > 0 {
+		scheduler.SetNotifier(notify.NewDispatcher(notify.RetryPolicy{MaxAttempts: 1}, notifiers...))
+	}
+
+	response, err := scheduler.ScheduleMonthlyConfirmations(ctx, ScheduleMonthlyConfirmationsRequest{
+		TenantID:     "tenant-1",
+		ScheduledFor: time.Now(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, response.ScheduledCount, "property %s should be scheduled", property.ID)
+
+	return scheduler, confirmationRepo
+}
+
+func processedConfirmation(t *testing.T, repo *memory.ScheduledConfirmationRepository) *models.ScheduledConfirmation {
+	t.Helper()
+	confirmations, err := repo.ListByTenant(context.Background(), "tenant-1", nil, 0)
+	require.NoError(t, err)
+	require.Len(t, confirmations, 1)
+	return confirmations[0]
+}
+
+func TestProcessPendingConfirmations_SendsThroughPreferredChannel(t *testing.T) {
+	ctx := context.Background()
+	whatsapp := notify.NewFakeNotifier(models.NotificationChannelWhatsApp)
+	scheduler, repo := newDeliveryTestScheduler(t, whatsapp, notify.NewFakeNotifier(models.NotificationChannelEmail))
+
+	require.NoError(t, scheduler.ProcessPendingConfirmations(ctx, "tenant-1"))
+
+	sc := processedConfirmation(t, repo)
+	assert.Equal(t, models.ScheduledConfirmationStatusSent, sc.Status)
+	assert.Equal(t, "whatsapp", sc.DeliveryMethod)
+	assert.Equal(t, models.DeliveryStatusAccepted, sc.DeliveryStatus)
+	assert.Equal(t, "fake-whatsapp-1", sc.ProviderMessageID)
+	assert.Equal(t, 1, sc.DeliveryAttempts)
+
+	sent := whatsapp.Sent()
+	require.Len(t, sent, 1)
+	assert.Equal(t, "(11) 98765-4321", sent[0].To)
+	assert.Contains(t, sent[0].Body, "Olá Maria!")
+	assert.Contains(t, sent[0].Body, "Imobiliária Teste")
+	assert.Contains(t, sent[0].Body, sc.ConfirmationURL)
+
+	// Delivery webhooks move the status forward only
+	require.NoError(t, scheduler.ApplyDeliveryStatus(ctx, notify.StatusUpdate{
+		ProviderMessageID: "fake-whatsapp-1",
+		Status:            models.DeliveryStatusDelivered,
+		Timestamp:         time.Now(),
+	}))
+	require.NoError(t, scheduler.ApplyDeliveryStatus(ctx, notify.StatusUpdate{
+		ProviderMessageID: "fake-whatsapp-1",
+		Status:            models.DeliveryStatusSent,
+	}))
+
+	sc = processedConfirmation(t, repo)
+	assert.Equal(t, models.DeliveryStatusDelivered, sc.DeliveryStatus)
+	assert.NotNil(t, sc.DeliveredAt)
+}
+
+func TestProcessPendingConfirmations_FallsBackToNextChannel(t *testing.T) {
+	whatsapp := notify.NewFakeNotifier(models.NotificationChannelWhatsApp)
+	whatsapp.FailNext(notify.Permanent(errors.New("not a whatsapp number")))
+	sms := notify.NewFakeNotifier(models.NotificationChannelSMS)
+	scheduler, repo := newDeliveryTestScheduler(t, whatsapp, sms)
+
+	require.NoError(t, scheduler.ProcessPendingConfirmations(context.Background(), "tenant-1"))
+
+	sc := processedConfirmation(t, repo)
+	assert.Equal(t, models.ScheduledConfirmationStatusSent, sc.Status)
+	assert.Equal(t, "sms", sc.DeliveryMethod)
+	assert.Equal(t, 2, sc.DeliveryAttempts)
+	assert.Len(t, sms.Sent(), 1)
+}
+
+func TestProcessPendingConfirmations_RecordsProviderFailure(t *testing.T) {
+	ctx := context.Background()
+	sms := notify.NewFakeNotifier(models.NotificationChannelSMS)
+	sms.FailNext(notify.Permanent(errors.New("sms: HTTP 400: invalid number")))
+	scheduler, repo := newDeliveryTestScheduler(t, sms)
+
+	require.NoError(t, scheduler.ProcessPendingConfirmations(ctx, "tenant-1"))
+
+	sc := processedConfirmation(t, repo)
+	assert.Equal(t, models.ScheduledConfirmationStatusFailed, sc.Status)
+	assert.Equal(t, models.DeliveryStatusFailed, sc.DeliveryStatus)
+	assert.Equal(t, "sms: HTTP 400: invalid number", sc.DeliveryError)
+}
+
+func TestProcessPendingConfirmations_ManualWithoutNotifier(t *testing.T) {
+	scheduler, repo := newDeliveryTestScheduler(t)
+
+	require.NoError(t, scheduler.ProcessPendingConfirmations(context.Background(), "tenant-1"))
+
+	sc := processedConfirmation(t, repo)
+	assert.Equal(t, models.ScheduledConfirmationStatusSent, sc.Status)
+	assert.Equal(t, "manual", sc.DeliveryMethod)
+	assert.Equal(t, models.DeliveryStatusManualRequired, sc.DeliveryStatus)
+	assert.NotNil(t, sc.SentAt)
+}
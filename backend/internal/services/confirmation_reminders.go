@@ -0,0 +1,283 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// PendingReasonOwnerUnresponsive is the pending_reason of properties flagged
+// by the reminder sequence
+const PendingReasonOwnerUnresponsive = "owner_unresponsive"
+
+// SetBrokerRepository sets the broker repository used to reach the captador
+// in the notify_captador reminder step
+func (s *MonthlyConfirmationScheduler) SetBrokerRepository(brokerRepo repositories.BrokerStore) {
+	s.brokerRepo = brokerRepo
+}
+
+// SetPropertyService sets the property service used by the flag_pending
+// reminder step (status history and portal feeds stay in sync)
+func (s *MonthlyConfirmationScheduler) SetPropertyService(propertyService *PropertyService) {
+	s.propertyService = propertyService
+}
+
+// ProcessRemindersResponse summarizes a run of the reminder sequence
+type ProcessRemindersResponse struct {
+	Due     int `json:"due"`     // Confirmations with a step due
+	Ran     int `json:"ran"`     // Steps run
+	Stopped int `json:"stopped"` // Sequences stopped (owner responded or confirmation cancelled)
+	Failed  int `json:"failed"`  // Steps that failed (recorded on the confirmation)
+}
+
+// ProcessReminders runs the due follow-up steps of unanswered owner
+// confirmations. Each confirmation advances at most one step per run; the
+// sequence stops as soon as the owner responds.
+func (s *MonthlyConfirmationScheduler) ProcessReminders(ctx context.Context, tenantID string) (*ProcessRemindersResponse, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+
+	now := time.Now()
+	due, err := s.scheduledConfirmationRepo.ListDueReminders(ctx, tenantID, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due reminders: %w", err)
+	}
+
+	tenant := s.loadTenant(ctx, tenantID)
+	policy := tenant.EffectivePolicy()
+	response := &ProcessRemindersResponse{Due: len(due)}
+
+	for _, sc := range due {
+		reminder := s.runReminderStep(ctx, tenant, policy, sc, now)
+		switch {
+		case reminder == nil:
+			response.Stopped++
+		case reminder.Error != "":
+			response.Failed++
+			response.Ran++
+		default:
+			response.Ran++
+		}
+
+		if err := s.scheduledConfirmationRepo.Update(ctx, sc); err != nil {
+			log.Printf("❌ Failed to update scheduled confirmation %s: %v", sc.ID, err)
+		}
+	}
+
+	log.Printf("🔔 Reminders for tenant %s: %d due, %d run, %d stopped, %d failed",
+		tenantID, response.Due, response.Ran, response.Stopped, response.Failed)
+	return response, nil
+}
+
+// runReminderStep runs the next step of sc and schedules the following one.
+// It returns nil when the sequence is over instead.
+func (s *MonthlyConfirmationScheduler) runReminderStep(ctx context.Context, tenant *models.Tenant, policy models.TenantPolicy, sc *models.ScheduledConfirmation, now time.Time) *models.ConfirmationReminder {
+	if !remindable(sc) || sc.ReminderStep >= len(policy.ReminderSteps) {
+		sc.NextReminderAt = nil
+		return nil
+	}
+
+	step := policy.ReminderSteps[sc.ReminderStep]
+	reminder := models.ConfirmationReminder{
+		Step:   sc.ReminderStep + 1,
+		Action: step.Action,
+		RanAt:  now,
+	}
+
+	var err error
+	switch step.Action {
+	case models.ReminderActionResend:
+		reminder.DeliveryMethod, err = s.resendToOwner(ctx, tenant, sc)
+	case models.ReminderActionNotifyCaptador:
+		reminder.DeliveryMethod, err = s.notifyCaptador(ctx, tenant, sc, now)
+	case models.ReminderActionFlagPending:
+		err = s.flagPending(ctx, sc)
+	default:
+		err = fmt.Errorf("unknown reminder action %q", step.Action)
+	}
+	if err != nil {
+		log.Printf("⚠️  Reminder step %d (%s) of confirmation %s failed: %v", reminder.Step, step.Action, sc.ID, err)
+		reminder.Error = err.Error()
+	}
+
+	sc.Reminders = append(sc.Reminders, reminder)
+	sc.ReminderStep++
+	scheduleNextReminder(sc, policy)
+	return &reminder
+}
+
+// resendToOwner sends the confirmation link to the owner again, returning the
+// channel used ("manual" when the broker has to resend it)
+func (s *MonthlyConfirmationScheduler) resendToOwner(ctx context.Context, tenant *models.Tenant, sc *models.ScheduledConfirmation) (string, error) {
+	owner, err := s.ownerRepo.Get(ctx, sc.TenantID, sc.OwnerID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get owner: %w", err)
+	}
+
+	data := s.messageData(ctx, tenant, sc, owner.Name)
+	channel, result, attempts, err := s.send(ctx, tenant, models.NotificationTemplateOwnerConfirmation,
+		recipient{Name: owner.Name, Phone: owner.Phone, Email: owner.Email}, data,
+		[]string{data.OwnerName, data.PropertyReference, data.ConfirmationURL})
+	if err != nil {
+		sc.DeliveryAttempts += attempts
+		return "", err
+	}
+	if channel == "" {
+		return "manual", nil
+	}
+
+	recordDelivery(sc, channel, result, attempts)
+	return string(channel), nil
+}
+
+// notifyCaptador asks the captador of the property to contact the owner,
+// returning the channel used
+func (s *MonthlyConfirmationScheduler) notifyCaptador(ctx context.Context, tenant *models.Tenant, sc *models.ScheduledConfirmation, now time.Time) (string, error) {
+	if s.brokerRepo == nil || sc.BrokerID == "" {
+		return "", fmt.Errorf("property has no captador to notify")
+	}
+
+	broker, err := s.brokerRepo.Get(ctx, sc.TenantID, sc.BrokerID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get captador: %w", err)
+	}
+
+	ownerName := ""
+	if owner, err := s.ownerRepo.Get(ctx, sc.TenantID, sc.OwnerID); err == nil {
+		ownerName = owner.Name
+	}
+
+	data := s.messageData(ctx, tenant, sc, ownerName)
+	data.BrokerName = firstName(broker.Name)
+	if sc.SentAt != nil {
+		data.DaysWaiting = int(now.Sub(*sc.SentAt).Hours() / 24)
+	}
+
+	channel, _, _, err := s.send(ctx, tenant, models.NotificationTemplateCaptadorReminder,
+		recipient{Name: broker.Name, Phone: broker.Phone, Email: broker.Email}, data,
+		[]string{data.BrokerName, data.PropertyReference, data.OwnerName})
+	if err != nil {
+		return "", err
+	}
+	if channel == "" {
+		return "", fmt.Errorf("no channel available to reach the captador")
+	}
+	return string(channel), nil
+}
+
+// flagPending marks the property as pending_confirmation
+func (s *MonthlyConfirmationScheduler) flagPending(ctx context.Context, sc *models.ScheduledConfirmation) error {
+	if s.propertyService == nil {
+		return fmt.Errorf("property service not configured")
+	}
+	_, err := s.propertyService.MarkPendingConfirmation(ctx, sc.TenantID, sc.PropertyID, PendingReasonOwnerUnresponsive)
+	return err
+}
+
+// scheduleNextReminder sets when the next step of sc is due (nil when the
+// sequence is over). Steps are relative to the first send.
+func scheduleNextReminder(sc *models.ScheduledConfirmation, policy models.TenantPolicy) {
+	if sc.SentAt == nil || !remindable(sc) || sc.ReminderStep >= len(policy.ReminderSteps) {
+		sc.NextReminderAt = nil
+		return
+	}
+	next := policy.ReminderSteps[sc.ReminderStep].DueAt(*sc.SentAt)
+	sc.NextReminderAt = &next
+}
+
+// remindable reports whether the owner may still be reminded
+func remindable(sc *models.ScheduledConfirmation) bool {
+	if sc.RespondedAt != nil {
+		return false
+	}
+	return sc.Status == models.ScheduledConfirmationStatusSent || sc.Status == models.ScheduledConfirmationStatusFailed
+}
+
+// PropertyConfirmationStatus shows where the latest owner confirmation of a
+// property stands in the reminder sequence
+type PropertyConfirmationStatus struct {
+	PropertyID     string                        `json:"property_id"`
+	Confirmation   *models.ScheduledConfirmation `json:"confirmation,omitempty"` // nil when none was ever scheduled
+	Responded      bool                          `json:"responded"`
+	CurrentStep    int                           `json:"current_step"` // Steps already run
+	TotalSteps     int                           `json:"total_steps"`
+	NextStep       *models.ReminderStep          `json:"next_step,omitempty"`
+	NextReminderAt *time.Time                    `json:"next_reminder_at,omitempty"`
+	Steps          []models.ReminderStep         `json:"steps"`
+}
+
+// GetPropertyConfirmationStatus returns the reminder sequence state of the
+// latest scheduled confirmation of a property
+func (s *MonthlyConfirmationScheduler) GetPropertyConfirmationStatus(ctx context.Context, tenantID, propertyID string) (*PropertyConfirmationStatus, error) {
+	if tenantID == "" || propertyID == "" {
+		return nil, fmt.Errorf("tenant_id and property_id are required")
+	}
+
+	if _, err := s.propertyRepo.Get(ctx, tenantID, propertyID); err != nil {
+		return nil, err
+	}
+
+	confirmations, err := s.scheduledConfirmationRepo.ListByProperty(ctx, tenantID, propertyID, 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scheduled confirmations: %w", err)
+	}
+
+	policy := s.loadTenant(ctx, tenantID).EffectivePolicy()
+	status := &PropertyConfirmationStatus{
+		PropertyID: propertyID,
+		TotalSteps: len(policy.ReminderSteps),
+		Steps:      policy.ReminderSteps,
+	}
+	if len(confirmations) == 0 {
+		return status, nil
+	}
+
+	sc := confirmations[0]
+	status.Confirmation = sc
+	status.Responded = sc.RespondedAt != nil
+	status.CurrentStep = sc.ReminderStep
+	status.NextReminderAt = sc.NextReminderAt
+	if sc.NextReminderAt != nil && sc.ReminderStep < len(policy.ReminderSteps) {
+		next := policy.ReminderSteps[sc.ReminderStep]
+		status.NextStep = &next
+	}
+	return status, nil
+}
+
+// confirmationResponses maps owner actions to ScheduledConfirmation.Response
+var confirmationResponses = map[models.ConfirmationAction]string{
+	models.ConfirmationActionAvailable:   "available",
+	models.ConfirmationActionUnavailable: "unavailable",
+	models.ConfirmationActionPrice:       "price_updated",
+}
+
+// markScheduledConfirmationResponded records the owner's answer on the
+// scheduled confirmation that sent the token, which stops its reminders.
+// Tokens generated by hand have no scheduled confirmation.
+func markScheduledConfirmationResponded(ctx context.Context, repo repositories.ScheduledConfirmationStore, tenantID, tokenID string, action models.ConfirmationAction, respondedAt time.Time) {
+	if repo == nil {
+		return
+	}
+
+	sc, err := repo.GetByTokenID(ctx, tenantID, tokenID)
+	if err != nil {
+		if !errors.Is(err, repositories.ErrNotFound) {
+			log.Printf("⚠️  Failed to find scheduled confirmation of token %s: %v", tokenID, err)
+		}
+		return
+	}
+
+	sc.Status = models.ScheduledConfirmationStatusResponded
+	sc.RespondedAt = &respondedAt
+	sc.Response = confirmationResponses[action]
+	sc.NextReminderAt = nil
+	if err := repo.Update(ctx, sc); err != nil {
+		log.Printf("⚠️  Failed to record owner response on scheduled confirmation %s: %v", sc.ID, err)
+	}
+}
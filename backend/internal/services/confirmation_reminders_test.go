@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/notify"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories/memory"
+)
+
+// backdateConfirmation moves the send date of the processed confirmation
+// back, making its next reminder step due
+func backdateConfirmation(t *testing.T, repo *memory.ScheduledConfirmationRepository, days int) *models.ScheduledConfirmation {
+	t.Helper()
+	sc := processedConfirmation(t, repo)
+	sentAt := time.Now().AddDate(0, 0, -days)
+	sc.SentAt = &sentAt
+	scheduleNextReminder(sc, models.DefaultTenantPolicy())
+	require.NoError(t, repo.Update(context.Background(), sc))
+	return sc
+}
+
+func TestProcessReminders_RunsEscalationSteps(t *testing.T) {
+	ctx := context.Background()
+	whatsapp := notify.NewFakeNotifier(models.NotificationChannelWhatsApp)
+	scheduler, repo := newDeliveryTestScheduler(t, whatsapp)
+
+	brokerRepo := memory.NewBrokerRepository()
+	require.NoError(t, brokerRepo.Create(ctx, &models.Broker{ID: "broker-1", TenantID: "tenant-1", Name: "João Lima", Phone: "(11) 91234-5678"}))
+	scheduler.SetBrokerRepository(brokerRepo)
+
+	require.NoError(t, scheduler.ProcessPendingConfirmations(ctx, "tenant-1"))
+	sc := processedConfirmation(t, repo)
+	require.NotNil(t, sc.NextReminderAt)
+	assert.Equal(t, sc.SentAt.AddDate(0, 0, 3), *sc.NextReminderAt)
+
+	// Nothing is due right after sending
+	response, err := scheduler.ProcessReminders(ctx, "tenant-1")
+	require.NoError(t, err)
+	assert.Equal(t, 0, response.Due)
+
+	backdateConfirmation(t, repo, 8)
+
+	// One step per run: resend, notify the captador, flag the property
+	for i := 0; i < 3; i++ {
+		response, err = scheduler.ProcessReminders(ctx, "tenant-1")
+		require.NoError(t, err)
+		assert.Equal(t, 1, response.Ran)
+		assert.Equal(t, 0, response.Failed)
+	}
+
+	sc = processedConfirmation(t, repo)
+	require.Len(t, sc.Reminders, 3)
+	assert.Equal(t, models.ReminderActionResend, sc.Reminders[0].Action)
+	assert.Equal(t, "whatsapp", sc.Reminders[0].DeliveryMethod)
+	assert.Equal(t, models.ReminderActionNotifyCaptador, sc.Reminders[1].Action)
+	assert.Equal(t, models.ReminderActionFlagPending, sc.Reminders[2].Action)
+	assert.Equal(t, 3, sc.ReminderStep)
+	assert.Nil(t, sc.NextReminderAt)
+
+	sent := whatsapp.Sent()
+	require.Len(t, sent, 3)
+	assert.Equal(t, "(11) 98765-4321", sent[1].To)
+	assert.Equal(t, "(11) 91234-5678", sent[2].To)
+	assert.Contains(t, sent[2].Body, "Olá João!")
+	assert.Contains(t, sent[2].Body, "enviada há 8 dias")
+
+	property, err := scheduler.propertyRepo.Get(ctx, "tenant-1", sc.PropertyID)
+	require.NoError(t, err)
+	assert.Equal(t, models.PropertyStatusPendingConfirmation, property.Status)
+	assert.Equal(t, PendingReasonOwnerUnresponsive, property.PendingReason)
+}
+
+func TestProcessReminders_StopsWhenOwnerResponds(t *testing.T) {
+	ctx := context.Background()
+	whatsapp := notify.NewFakeNotifier(models.NotificationChannelWhatsApp)
+	scheduler, repo := newDeliveryTestScheduler(t, whatsapp)
+
+	require.NoError(t, scheduler.ProcessPendingConfirmations(ctx, "tenant-1"))
+	sc := backdateConfirmation(t, repo, 4)
+
+	// The token is the last path segment of the confirmation link
+	token := strings.SplitN(sc.ConfirmationURL[strings.LastIndex(sc.ConfirmationURL, "/")+1:], "?", 2)[0]
+	require.NoError(t, scheduler.ownerConfirmationService.SubmitOwnerConfirmation(ctx, "tenant-1", token, models.ConfirmationActionAvailable, nil))
+
+	sc = processedConfirmation(t, repo)
+	assert.Equal(t, models.ScheduledConfirmationStatusResponded, sc.Status)
+	assert.Equal(t, "available", sc.Response)
+	assert.NotNil(t, sc.RespondedAt)
+	assert.Nil(t, sc.NextReminderAt)
+
+	response, err := scheduler.ProcessReminders(ctx, "tenant-1")
+	require.NoError(t, err)
+	assert.Equal(t, 0, response.Ran)
+	assert.Len(t, whatsapp.Sent(), 1)
+
+	status, err := scheduler.GetPropertyConfirmationStatus(ctx, "tenant-1", sc.PropertyID)
+	require.NoError(t, err)
+	assert.True(t, status.Responded)
+	assert.Equal(t, 0, status.CurrentStep)
+	assert.Equal(t, 3, status.TotalSteps)
+	assert.Nil(t, status.NextStep)
+}
+
+func TestProcessReminders_RecordsFailedStep(t *testing.T) {
+	ctx := context.Background()
+	scheduler, repo := newDeliveryTestScheduler(t, notify.NewFakeNotifier(models.NotificationChannelWhatsApp))
+
+	require.NoError(t, scheduler.ProcessPendingConfirmations(ctx, "tenant-1"))
+	backdateConfirmation(t, repo, 5)
+
+	// Resend succeeds; the captador cannot be found
+	_, err := scheduler.ProcessReminders(ctx, "tenant-1")
+	require.NoError(t, err)
+	response, err := scheduler.ProcessReminders(ctx, "tenant-1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, response.Failed)
+
+	sc := processedConfirmation(t, repo)
+	require.Len(t, sc.Reminders, 2)
+	assert.NotEmpty(t, sc.Reminders[1].Error)
+
+	// The sequence goes on with the next step
+	status, err := scheduler.GetPropertyConfirmationStatus(ctx, "tenant-1", sc.PropertyID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, status.CurrentStep)
+	require.NotNil(t, status.NextStep)
+	assert.Equal(t, models.ReminderActionFlagPending, status.NextStep.Action)
+}
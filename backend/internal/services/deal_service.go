@@ -0,0 +1,614 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// dealStatsPageSize is the page size used to read the deals and brokers of a
+// statistics run
+const dealStatsPageSize = 500
+
+// DealService handles closed deals and the broker statistics they feed
+type DealService struct {
+	dealRepo          repositories.DealStore
+	proposalRepo      repositories.ProposalStore
+	leadService       *LeadService
+	propertyService   *PropertyService
+	propertyRepo      repositories.PropertyStore
+	roleRepo          repositories.PropertyBrokerRoleStore
+	brokerRepo        repositories.BrokerStore
+	activityLogRepo   repositories.ActivityLogStore
+	commissionService *CommissionService // optional, see SetCommissionService
+}
+
+// NewDealService creates a new deal service
+func NewDealService(
+	dealRepo repositories.DealStore,
+	proposalRepo repositories.ProposalStore,
+	leadService *LeadService,
+	propertyService *PropertyService,
+	propertyRepo repositories.PropertyStore,
+	roleRepo repositories.PropertyBrokerRoleStore,
+	brokerRepo repositories.BrokerStore,
+	activityLogRepo repositories.ActivityLogStore,
+) *DealService {
+	return &DealService{
+		dealRepo:        dealRepo,
+		proposalRepo:    proposalRepo,
+		leadService:     leadService,
+		propertyService: propertyService,
+		propertyRepo:    propertyRepo,
+		roleRepo:        roleRepo,
+		brokerRepo:      brokerRepo,
+		activityLogRepo: activityLogRepo,
+	}
+}
+
+// SetCommissionService records the commission of every deal closed (and
+// cancels it with the deal)
+func (s *DealService) SetCommissionService(commissionService *CommissionService) {
+	s.commissionService = commissionService
+}
+
+// CloseDeal registers the sale or rental of a property. An accepted proposal
+// defaults the property, lead, transaction and price; the brokers default to
+// the property's broker roles plus the lead's broker as co-broker. The
+// property leaves the market as sold or rented, the lead is converted and the
+// statistics of the brokers involved are recomputed.
+func (s *DealService) CloseDeal(ctx context.Context, deal *models.Deal, actorID string) error {
+	if deal.TenantID == "" {
+		return fmt.Errorf("tenant_id is required")
+	}
+
+	if deal.ProposalID != "" {
+		proposal, err := s.proposalRepo.Get(ctx, deal.TenantID, deal.ProposalID)
+		if err != nil {
+			return fmt.Errorf("proposal not found: %w", err)
+		}
+		if proposal.Status != models.ProposalStatusAccepted {
+			return fmt.Errorf("%w: proposal is %s, not accepted", repositories.ErrInvalidInput, proposal.Status)
+		}
+		existing, err := s.dealRepo.List(ctx, deal.TenantID, &repositories.DealFilters{
+			PropertyID: proposal.PropertyID,
+			Statuses:   []models.DealStatus{models.DealStatusClosed},
+		}, repositories.PaginationOptions{Limit: 100})
+		if err != nil {
+			return fmt.Errorf("failed to check property deals: %w", err)
+		}
+		for _, other := range existing {
+			if other.ProposalID == proposal.ID {
+				return fmt.Errorf("%w: proposal %s already closed deal %s", repositories.ErrInvalidInput, proposal.ID, other.ID)
+			}
+		}
+
+		if deal.PropertyID == "" {
+			deal.PropertyID = proposal.PropertyID
+		}
+		if deal.LeadID == "" {
+			deal.LeadID = proposal.LeadID
+		}
+		if deal.TransactionType == "" {
+			deal.TransactionType = proposal.TransactionType
+		}
+		if deal.Price == 0 {
+			deal.Price = proposal.Terms.Amount
+		}
+	}
+
+	if deal.PropertyID == "" {
+		return fmt.Errorf("%w: property_id or proposal_id is required", repositories.ErrInvalidInput)
+	}
+	if deal.TransactionType == "" {
+		deal.TransactionType = models.TransactionTypeSale
+	}
+	if deal.TransactionType != models.TransactionTypeSale && deal.TransactionType != models.TransactionTypeRent {
+		return fmt.Errorf("%w: transaction_type must be 'sale' or 'rent'", repositories.ErrInvalidInput)
+	}
+	if deal.Price <= 0 {
+		return fmt.Errorf("%w: price must be positive", repositories.ErrInvalidInput)
+	}
+	now := time.Now()
+	if deal.ClosedAt.IsZero() {
+		deal.ClosedAt = now
+	}
+	if deal.ClosedAt.After(now) {
+		return fmt.Errorf("%w: closed_at cannot be in the future", repositories.ErrInvalidInput)
+	}
+
+	property, err := s.propertyRepo.Get(ctx, deal.TenantID, deal.PropertyID)
+	if err != nil {
+		return fmt.Errorf("property not found: %w", err)
+	}
+	if property.Status == models.PropertyStatusUnavailable && property.UnavailableReason != "" {
+		return fmt.Errorf("%w: property is already %s", repositories.ErrInvalidInput, property.UnavailableReason)
+	}
+
+	var lead *models.Lead
+	if deal.LeadID != "" {
+		if lead, err = s.leadService.activeLead(ctx, deal.TenantID, deal.LeadID); err != nil {
+			return err
+		}
+	}
+
+	if len(deal.Brokers) == 0 {
+		if deal.Brokers, err = s.defaultDealBrokers(ctx, deal.TenantID, deal.PropertyID, lead); err != nil {
+			return err
+		}
+	}
+	if err := s.validateDealBrokers(ctx, deal); err != nil {
+		return err
+	}
+
+	deal.ID = ""
+	deal.Status = models.DealStatusClosed
+	deal.Review = nil
+	deal.CancelledAt = nil
+	deal.CancelReason = ""
+	deal.Notes = strings.TrimSpace(deal.Notes)
+	deal.CreatedBy = actorID
+
+	if err := s.dealRepo.Create(ctx, deal); err != nil {
+		return fmt.Errorf("failed to create deal: %w", err)
+	}
+	_ = s.logActivity(ctx, deal.TenantID, "deal_closed", actorID, dealLogMetadata(deal))
+
+	if _, err := s.propertyService.MarkUnavailable(ctx, deal.TenantID, deal.PropertyID, deal.UnavailableReason()); err != nil {
+		log.Printf("⚠️  Failed to take property %s off the market after deal %s: %v", deal.PropertyID, deal.ID, err)
+	}
+	if lead != nil && lead.Status != models.LeadStatusConverted {
+		if err := s.leadService.UpdateStatus(ctx, deal.TenantID, lead.ID, models.LeadStatusConverted); err != nil {
+			log.Printf("⚠️  Failed to convert lead %s after deal %s: %v", lead.ID, deal.ID, err)
+		}
+	}
+	if s.commissionService != nil {
+		roles := make([]*models.PropertyBrokerRole, len(deal.Brokers))
+		for i, broker := range deal.Brokers {
+			roles[i] = &models.PropertyBrokerRole{
+				TenantID:             deal.TenantID,
+				PropertyID:           deal.PropertyID,
+				BrokerID:             broker.BrokerID,
+				Role:                 broker.Role,
+				CommissionPercentage: broker.CommissionPercentage,
+			}
+		}
+		if _, err := s.commissionService.RecordCommission(ctx, RecordCommissionRequest{
+			TenantID:        deal.TenantID,
+			DealID:          deal.ID,
+			ProposalID:      deal.ProposalID,
+			PropertyID:      deal.PropertyID,
+			TransactionType: deal.TransactionType,
+			Price:           deal.Price,
+			ClosedAt:        deal.ClosedAt,
+			Roles:           roles,
+		}, actorID); err != nil {
+			log.Printf("⚠️  Failed to record the commission of deal %s: %v", deal.ID, err)
+		}
+	}
+
+	s.refreshBrokerStats(ctx, deal.TenantID, deal.BrokerIDs)
+	return nil
+}
+
+// CancelDeal undoes a closed deal (distrato or registered by mistake). Its
+// pending commission is cancelled and the broker statistics recomputed; the
+// property stays off the market until it is listed again.
+func (s *DealService) CancelDeal(ctx context.Context, tenantID, id, reason, actorID string) (*models.Deal, error) {
+	deal, err := s.closedDeal(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	deal.Status = models.DealStatusCancelled
+	deal.CancelledAt = &now
+	deal.CancelReason = strings.TrimSpace(reason)
+	if err := s.dealRepo.Update(ctx, tenantID, id, map[string]interface{}{
+		"status":        deal.Status,
+		"cancelled_at":  deal.CancelledAt,
+		"cancel_reason": deal.CancelReason,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to update deal: %w", err)
+	}
+	_ = s.logActivity(ctx, tenantID, "deal_cancelled", actorID, dealLogMetadata(deal))
+
+	if s.commissionService != nil {
+		if _, err := s.commissionService.CancelDeal(ctx, tenantID, deal.ID, deal.CancelReason, actorID); err != nil {
+			log.Printf("⚠️  Failed to cancel the commission of deal %s: %v", deal.ID, err)
+		}
+	}
+
+	s.refreshBrokerStats(ctx, tenantID, deal.BrokerIDs)
+	return deal, nil
+}
+
+// ReviewDeal records the rating the buyer or tenant gave the brokers of a
+// closed deal, replacing any previous one
+func (s *DealService) ReviewDeal(ctx context.Context, tenantID, id string, review models.DealReview, actorID string) (*models.Deal, error) {
+	if err := review.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", repositories.ErrInvalidInput, err)
+	}
+
+	deal, err := s.closedDeal(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	review.Comment = strings.TrimSpace(review.Comment)
+	review.ReviewedAt = time.Now()
+	deal.Review = &review
+	if err := s.dealRepo.Update(ctx, tenantID, id, map[string]interface{}{
+		"review": deal.Review,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to update deal: %w", err)
+	}
+
+	metadata := dealLogMetadata(deal)
+	metadata["rating"] = review.Rating
+	_ = s.logActivity(ctx, tenantID, "deal_reviewed", actorID, metadata)
+
+	s.refreshBrokerStats(ctx, tenantID, deal.BrokerIDs)
+	return deal, nil
+}
+
+// GetDeal retrieves a deal by ID
+func (s *DealService) GetDeal(ctx context.Context, tenantID, id string) (*models.Deal, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+
+	deal, err := s.dealRepo.Get(ctx, tenantID, id)
+	if err != nil {
+		return nil, fmt.Errorf("deal not found: %w", err)
+	}
+	return deal, nil
+}
+
+// ListDeals lists deals with filters, most recently closed first
+func (s *DealService) ListDeals(ctx context.Context, tenantID string, filters *repositories.DealFilters, opts repositories.PaginationOptions) ([]*models.Deal, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+
+	deals, err := s.dealRepo.List(ctx, tenantID, filters, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deals: %w", err)
+	}
+	return deals, nil
+}
+
+// BrokerStatsResponse summarizes a run of the broker statistics job
+type BrokerStatsResponse struct {
+	Brokers    int `json:"brokers"`    // Brokers whose statistics changed
+	Properties int `json:"properties"` // Properties taken off the market
+	Failed     int `json:"failed"`
+}
+
+// RecalculateBrokerStats recomputes the statistics of every broker of a
+// tenant from its closed deals, and takes off the market the properties of
+// deals still listed (unless they were put back on the market after the
+// deal). It is run daily by the background scheduler.
+func (s *DealService) RecalculateBrokerStats(ctx context.Context, tenantID string) (*BrokerStatsResponse, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+
+	deals, err := s.closedDeals(ctx, tenantID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	response := &BrokerStatsResponse{}
+	stats := aggregateBrokerStats(deals)
+	err = s.forEachBroker(ctx, tenantID, func(broker *models.Broker) {
+		changed, err := s.applyBrokerStats(ctx, broker, stats[broker.ID])
+		if err != nil {
+			log.Printf("⚠️  Failed to update the statistics of broker %s: %v", broker.ID, err)
+			response.Failed++
+			return
+		}
+		if changed {
+			response.Brokers++
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Deals are listed most recent first: the latest deal of a property decides
+	seen := make(map[string]bool)
+	for _, deal := range deals {
+		if seen[deal.PropertyID] {
+			continue
+		}
+		seen[deal.PropertyID] = true
+
+		property, err := s.propertyRepo.Get(ctx, tenantID, deal.PropertyID)
+		if err != nil {
+			log.Printf("⚠️  Failed to get property %s of deal %s: %v", deal.PropertyID, deal.ID, err)
+			response.Failed++
+			continue
+		}
+		if property.StatusConfirmedAt != nil && property.StatusConfirmedAt.After(deal.ClosedAt) {
+			continue // Status set after the deal (e.g. back on the market)
+		}
+		changed, err := s.propertyService.MarkUnavailable(ctx, tenantID, deal.PropertyID, deal.UnavailableReason())
+		if err != nil {
+			log.Printf("⚠️  Failed to take property %s off the market: %v", deal.PropertyID, err)
+			response.Failed++
+			continue
+		}
+		if changed {
+			response.Properties++
+		}
+	}
+
+	return response, nil
+}
+
+// refreshBrokerStats recomputes the statistics of the given brokers after a
+// deal changed. Failures are logged: the daily job catches up.
+func (s *DealService) refreshBrokerStats(ctx context.Context, tenantID string, brokerIDs []string) {
+	for _, brokerID := range brokerIDs {
+		deals, err := s.closedDeals(ctx, tenantID, brokerID)
+		if err != nil {
+			log.Printf("⚠️  Failed to list the deals of broker %s: %v", brokerID, err)
+			continue
+		}
+		broker, err := s.brokerRepo.Get(ctx, tenantID, brokerID)
+		if err != nil {
+			log.Printf("⚠️  Failed to get broker %s: %v", brokerID, err)
+			continue
+		}
+		if _, err := s.applyBrokerStats(ctx, broker, aggregateBrokerStats(deals)[brokerID]); err != nil {
+			log.Printf("⚠️  Failed to update the statistics of broker %s: %v", brokerID, err)
+		}
+	}
+}
+
+// closedDeals reads every closed deal of a tenant (of a broker when brokerID
+// is set), most recently closed first, paging through the whole collection
+func (s *DealService) closedDeals(ctx context.Context, tenantID, brokerID string) ([]*models.Deal, error) {
+	filters := &repositories.DealFilters{
+		BrokerID: brokerID,
+		Statuses: []models.DealStatus{models.DealStatusClosed},
+	}
+	opts := repositories.PaginationOptions{
+		Limit:     dealStatsPageSize,
+		OrderBy:   "closed_at",
+		Direction: firestore.Desc,
+	}
+
+	deals := make([]*models.Deal, 0)
+	for {
+		page, err := s.dealRepo.List(ctx, tenantID, filters, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list deals: %w", err)
+		}
+		deals = append(deals, page...)
+		if len(page) < opts.Limit {
+			return deals, nil
+		}
+
+		last := page[len(page)-1]
+		opts.StartAfter = last.ClosedAt
+		opts.StartAfterID = last.ID
+	}
+}
+
+// forEachBroker calls fn for every broker of a tenant, paging through the
+// whole collection
+func (s *DealService) forEachBroker(ctx context.Context, tenantID string, fn func(*models.Broker)) error {
+	opts := repositories.PaginationOptions{
+		Limit:     dealStatsPageSize,
+		OrderBy:   "created_at",
+		Direction: firestore.Asc,
+	}
+
+	for {
+		brokers, err := s.brokerRepo.List(ctx, tenantID, opts)
+		if err != nil {
+			return fmt.Errorf("failed to list brokers: %w", err)
+		}
+		for _, broker := range brokers {
+			fn(broker)
+		}
+		if len(brokers) < opts.Limit {
+			return nil
+		}
+
+		last := brokers[len(brokers)-1]
+		opts.StartAfter = last.CreatedAt
+		opts.StartAfterID = last.ID
+	}
+}
+
+// brokerStats are the statistics of a broker computed from closed deals
+type brokerStats struct {
+	TotalSales   int
+	AveragePrice float64
+	LastSaleDate string // YYYY-MM-DD
+	Rating       float64
+	ReviewCount  int
+}
+
+// aggregateBrokerStats computes the statistics of every broker in the closed
+// deals. Sales count sale deals only; reviews count on every deal.
+func aggregateBrokerStats(deals []*models.Deal) map[string]*brokerStats {
+	stats := make(map[string]*brokerStats)
+	salesVolume := make(map[string]float64)
+	ratingSum := make(map[string]int)
+	lastSale := make(map[string]time.Time)
+
+	for _, deal := range deals {
+		if deal.Status != models.DealStatusClosed {
+			continue
+		}
+		for _, brokerID := range deal.BrokerIDs {
+			stat, ok := stats[brokerID]
+			if !ok {
+				stat = &brokerStats{}
+				stats[brokerID] = stat
+			}
+			if deal.TransactionType == models.TransactionTypeSale {
+				stat.TotalSales++
+				salesVolume[brokerID] += deal.Price
+				if deal.ClosedAt.After(lastSale[brokerID]) {
+					lastSale[brokerID] = deal.ClosedAt
+				}
+			}
+			if deal.Review != nil {
+				stat.ReviewCount++
+				ratingSum[brokerID] += deal.Review.Rating
+			}
+		}
+	}
+
+	for brokerID, stat := range stats {
+		if stat.TotalSales > 0 {
+			stat.AveragePrice = roundCents(salesVolume[brokerID] / float64(stat.TotalSales))
+			stat.LastSaleDate = lastSale[brokerID].Format("2006-01-02")
+		}
+		if stat.ReviewCount > 0 {
+			stat.Rating = math.Round(float64(ratingSum[brokerID])/float64(stat.ReviewCount)*10) / 10
+		}
+	}
+	return stats
+}
+
+// applyBrokerStats writes the statistics of a broker (nil = no deals) when
+// they changed, and reports whether they did
+func (s *DealService) applyBrokerStats(ctx context.Context, broker *models.Broker, stats *brokerStats) (bool, error) {
+	if stats == nil {
+		stats = &brokerStats{}
+	}
+	if broker.TotalSales == stats.TotalSales &&
+		broker.AveragePrice == stats.AveragePrice &&
+		broker.LastSaleDate == stats.LastSaleDate &&
+		broker.Rating == stats.Rating &&
+		broker.ReviewCount == stats.ReviewCount {
+		return false, nil
+	}
+
+	if err := s.brokerRepo.Update(ctx, broker.TenantID, broker.ID, map[string]interface{}{
+		"total_sales":    stats.TotalSales,
+		"average_price":  stats.AveragePrice,
+		"last_sale_date": stats.LastSaleDate,
+		"rating":         stats.Rating,
+		"review_count":   stats.ReviewCount,
+	}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// closedDeal returns a deal that is still closed
+func (s *DealService) closedDeal(ctx context.Context, tenantID, id string) (*models.Deal, error) {
+	deal, err := s.GetDeal(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if deal.Status != models.DealStatusClosed {
+		return nil, fmt.Errorf("%w: deal is %s", repositories.ErrInvalidInput, deal.Status)
+	}
+	return deal, nil
+}
+
+// defaultDealBrokers returns the property's broker roles, plus the lead's
+// broker as co-broker when it has no role on the property
+func (s *DealService) defaultDealBrokers(ctx context.Context, tenantID, propertyID string, lead *models.Lead) ([]models.DealBroker, error) {
+	roles, err := s.roleRepo.ListByProperty(ctx, tenantID, propertyID, repositories.PaginationOptions{Limit: 100})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get property roles: %w", err)
+	}
+
+	brokers := make([]models.DealBroker, 0, len(roles)+1)
+	for _, role := range roles {
+		brokers = append(brokers, models.DealBroker{
+			BrokerID:             role.BrokerID,
+			Role:                 role.Role,
+			CommissionPercentage: role.CommissionPercentage,
+		})
+	}
+	if lead != nil && lead.AssignedBrokerID != "" {
+		for _, broker := range brokers {
+			if broker.BrokerID == lead.AssignedBrokerID {
+				return brokers, nil
+			}
+		}
+		brokers = append(brokers, models.DealBroker{
+			BrokerID: lead.AssignedBrokerID,
+			Role:     models.BrokerPropertyRoleCoBroker,
+		})
+	}
+	return brokers, nil
+}
+
+// validateDealBrokers checks the brokers of a deal and fills BrokerIDs
+func (s *DealService) validateDealBrokers(ctx context.Context, deal *models.Deal) error {
+	if len(deal.Brokers) == 0 {
+		return fmt.Errorf("%w: at least one broker is required", repositories.ErrInvalidInput)
+	}
+
+	deal.BrokerIDs = make([]string, 0, len(deal.Brokers))
+	for _, broker := range deal.Brokers {
+		switch broker.Role {
+		case models.BrokerPropertyRoleOriginating, models.BrokerPropertyRoleListing, models.BrokerPropertyRoleCoBroker:
+		default:
+			return fmt.Errorf("%w: invalid broker role: %s", repositories.ErrInvalidInput, broker.Role)
+		}
+		if broker.CommissionPercentage < 0 || broker.CommissionPercentage > 100 {
+			return fmt.Errorf("%w: commission_percentage must be between 0 and 100", repositories.ErrInvalidInput)
+		}
+		if _, err := s.brokerRepo.Get(ctx, deal.TenantID, broker.BrokerID); err != nil {
+			return fmt.Errorf("broker %s not found: %w", broker.BrokerID, err)
+		}
+		for _, brokerID := range deal.BrokerIDs {
+			if brokerID == broker.BrokerID {
+				return fmt.Errorf("%w: broker %s listed twice", repositories.ErrInvalidInput, broker.BrokerID)
+			}
+		}
+		deal.BrokerIDs = append(deal.BrokerIDs, broker.BrokerID)
+	}
+	return nil
+}
+
+// dealLogMetadata returns the activity log metadata of a deal
+func dealLogMetadata(deal *models.Deal) map[string]interface{} {
+	return map[string]interface{}{
+		"deal_id":          deal.ID,
+		"property_id":      deal.PropertyID,
+		"lead_id":          deal.LeadID,
+		"transaction_type": deal.TransactionType,
+		"price":            deal.Price,
+		"status":           deal.Status,
+	}
+}
+
+// logActivity logs an activity; actions without an actor are the system's
+func (s *DealService) logActivity(ctx context.Context, tenantID, eventType, actorID string, metadata map[string]interface{}) error {
+	actorType := models.ActorTypeUser
+	if actorID == "" {
+		actorType = models.ActorTypeSystem
+	}
+
+	log := &models.ActivityLog{
+		TenantID:  tenantID,
+		EventType: eventType,
+		ActorType: actorType,
+		ActorID:   actorID,
+		Metadata:  metadata,
+		Timestamp: time.Now(),
+	}
+
+	return s.activityLogRepo.Create(ctx, log)
+}
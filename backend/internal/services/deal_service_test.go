@@ -0,0 +1,232 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories/memory"
+)
+
+// newDealTestService returns a deal service over the proposal test data, with
+// broker-1 listing property "moema" and the commission ledger enabled
+func newDealTestService(t *testing.T) (*DealService, *ProposalService, *CommissionService) {
+	t.Helper()
+	ctx := context.Background()
+
+	proposalService, _ := newProposalTestService(t)
+	leadService := proposalService.leadService
+	require.NoError(t, leadService.roleRepo.Create(ctx, &models.PropertyBrokerRole{TenantID: "tenant-1", PropertyID: "moema", BrokerID: "broker-1", Role: models.BrokerPropertyRoleListing}))
+
+	commissionService := NewCommissionService(
+		memory.NewCommissionRepository(),
+		proposalService.proposalRepo,
+		leadService.propertyRepo,
+		leadService.roleRepo,
+		leadService.brokerRepo,
+		leadService.tenantRepo,
+		leadService.activityLogRepo,
+	)
+	service := NewDealService(
+		memory.NewDealRepository(),
+		proposalService.proposalRepo,
+		leadService,
+		proposalService.propertyService,
+		leadService.propertyRepo,
+		leadService.roleRepo,
+		leadService.brokerRepo,
+		leadService.activityLogRepo,
+	)
+	service.SetCommissionService(commissionService)
+	return service, proposalService, commissionService
+}
+
+func getTestBroker(t *testing.T, service *DealService, id string) *models.Broker {
+	t.Helper()
+	broker, err := service.brokerRepo.Get(context.Background(), "tenant-1", id)
+	require.NoError(t, err)
+	return broker
+}
+
+func TestCloseDeal_FromAcceptedProposal(t *testing.T) {
+	ctx := context.Background()
+	service, proposalService, commissionService := newDealTestService(t)
+
+	proposal := submitTestProposal(t, proposalService, 800000)
+
+	// Only accepted proposals close a deal
+	err := service.CloseDeal(ctx, &models.Deal{TenantID: "tenant-1", ProposalID: proposal.ID}, "user-1")
+	assert.True(t, errors.Is(err, repositories.ErrInvalidInput))
+
+	_, err = proposalService.AcceptProposal(ctx, "tenant-1", proposal.ID, "broker-3")
+	require.NoError(t, err)
+
+	closedAt := time.Date(2024, 7, 15, 14, 0, 0, 0, time.UTC)
+	deal := &models.Deal{TenantID: "tenant-1", ProposalID: proposal.ID, ClosedAt: closedAt}
+	require.NoError(t, service.CloseDeal(ctx, deal, "user-1"))
+	assert.NotEmpty(t, deal.ID)
+	assert.Equal(t, models.DealStatusClosed, deal.Status)
+	assert.Equal(t, "moema", deal.PropertyID)
+	assert.Equal(t, proposal.LeadID, deal.LeadID)
+	assert.Equal(t, 800000.0, deal.Price)
+	assert.ElementsMatch(t, []string{"broker-3", "broker-1"}, deal.BrokerIDs)
+
+	// The property is sold and the lead converted
+	property, err := service.propertyRepo.Get(ctx, "tenant-1", "moema")
+	require.NoError(t, err)
+	assert.Equal(t, models.PropertyStatusUnavailable, property.Status)
+	assert.Equal(t, models.PropertyUnavailableReasonSold, property.UnavailableReason)
+	lead, err := service.leadService.GetLead(ctx, "tenant-1", deal.LeadID)
+	require.NoError(t, err)
+	assert.Equal(t, models.LeadStatusConverted, lead.Status)
+
+	// The commission is recorded under the deal, split among its brokers
+	entries, err := commissionService.ListEntries(ctx, "tenant-1", &repositories.CommissionEntryFilters{DealID: deal.ID}, repositories.PaginationOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"broker-3": 9600, "broker-1": 9600, "agency": 28800}, commissionAmounts(entries))
+
+	// Both brokers get the sale
+	for _, brokerID := range []string{"broker-3", "broker-1"} {
+		broker := getTestBroker(t, service, brokerID)
+		assert.Equal(t, 1, broker.TotalSales, brokerID)
+		assert.Equal(t, 800000.0, broker.AveragePrice, brokerID)
+		assert.Equal(t, "2024-07-15", broker.LastSaleDate, brokerID)
+	}
+
+	// A proposal closes one deal
+	err = service.CloseDeal(ctx, &models.Deal{TenantID: "tenant-1", ProposalID: proposal.ID}, "user-1")
+	assert.True(t, errors.Is(err, repositories.ErrInvalidInput))
+}
+
+func TestDealReviewsAndCancel_UpdateBrokerStats(t *testing.T) {
+	ctx := context.Background()
+	service, _, commissionService := newDealTestService(t)
+
+	brokers := []models.DealBroker{{BrokerID: "broker-2", Role: models.BrokerPropertyRoleCoBroker}}
+	sale := &models.Deal{TenantID: "tenant-1", PropertyID: "moema", Price: 1000000, Brokers: brokers, ClosedAt: time.Date(2024, 5, 2, 10, 0, 0, 0, time.UTC)}
+	require.NoError(t, service.CloseDeal(ctx, sale, "user-1"))
+	rental := &models.Deal{TenantID: "tenant-1", PropertyID: "pinheiros", TransactionType: models.TransactionTypeRent, Price: 5000, Brokers: brokers}
+	require.NoError(t, service.CloseDeal(ctx, rental, "user-1"))
+
+	property, err := service.propertyRepo.Get(ctx, "tenant-1", "pinheiros")
+	require.NoError(t, err)
+	assert.Equal(t, models.PropertyUnavailableReasonRented, property.UnavailableReason)
+
+	_, err = service.ReviewDeal(ctx, "tenant-1", sale.ID, models.DealReview{Rating: 6}, "user-1")
+	assert.True(t, errors.Is(err, repositories.ErrInvalidInput))
+	_, err = service.ReviewDeal(ctx, "tenant-1", sale.ID, models.DealReview{Rating: 5, Comment: " Excelente "}, "user-1")
+	require.NoError(t, err)
+	reviewed, err := service.ReviewDeal(ctx, "tenant-1", rental.ID, models.DealReview{Rating: 4}, "user-1")
+	require.NoError(t, err)
+	require.NotNil(t, reviewed.Review)
+
+	// Rentals count for the rating, not for the sales
+	broker := getTestBroker(t, service, "broker-2")
+	assert.Equal(t, 1, broker.TotalSales)
+	assert.Equal(t, 1000000.0, broker.AveragePrice)
+	assert.Equal(t, "2024-05-02", broker.LastSaleDate)
+	assert.Equal(t, 4.5, broker.Rating)
+	assert.Equal(t, 2, broker.ReviewCount)
+
+	// Cancelling takes the sale, its review and its pending commission back
+	cancelled, err := service.CancelDeal(ctx, "tenant-1", sale.ID, "distrato", "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, models.DealStatusCancelled, cancelled.Status)
+	broker = getTestBroker(t, service, "broker-2")
+	assert.Equal(t, 0, broker.TotalSales)
+	assert.Equal(t, 0.0, broker.AveragePrice)
+	assert.Empty(t, broker.LastSaleDate)
+	assert.Equal(t, 4.0, broker.Rating)
+	assert.Equal(t, 1, broker.ReviewCount)
+
+	entries, err := commissionService.ListEntries(ctx, "tenant-1", &repositories.CommissionEntryFilters{DealID: sale.ID}, repositories.PaginationOptions{})
+	require.NoError(t, err)
+	for _, entry := range entries {
+		assert.Equal(t, models.CommissionEntryStatusCancelled, entry.Status)
+	}
+
+	_, err = service.CancelDeal(ctx, "tenant-1", sale.ID, "", "user-1")
+	assert.True(t, errors.Is(err, repositories.ErrInvalidInput))
+}
+
+func TestRecalculateBrokerStats(t *testing.T) {
+	ctx := context.Background()
+	service, _, _ := newDealTestService(t)
+
+	closedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	brokers := []models.DealBroker{{BrokerID: "broker-2", Role: models.BrokerPropertyRoleListing}}
+	for _, deal := range []*models.Deal{
+		{TenantID: "tenant-1", PropertyID: "moema", Price: 600000, Brokers: brokers, ClosedAt: closedAt},
+		{TenantID: "tenant-1", PropertyID: "pinheiros", TransactionType: models.TransactionTypeRent, Price: 4000, Brokers: brokers, ClosedAt: closedAt},
+	} {
+		require.NoError(t, service.CloseDeal(ctx, deal, "user-1"))
+	}
+
+	// Drift: stale statistics, a property that missed the update and one put
+	// back on the market after its deal
+	require.NoError(t, service.brokerRepo.Update(ctx, "tenant-1", "broker-4", map[string]interface{}{"total_sales": 7}))
+	before := closedAt.AddDate(0, 0, -1)
+	require.NoError(t, service.propertyRepo.Update(ctx, "tenant-1", "pinheiros", map[string]interface{}{
+		"status":              models.PropertyStatusAvailable,
+		"unavailable_reason":  "",
+		"status_confirmed_at": before,
+	}))
+	require.NoError(t, service.propertyService.UpdateStatus(ctx, "tenant-1", "moema", "", models.PropertyStatusAvailable))
+
+	response, err := service.RecalculateBrokerStats(ctx, "tenant-1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, response.Brokers)
+	assert.Equal(t, 1, response.Properties)
+	assert.Zero(t, response.Failed)
+
+	assert.Equal(t, 0, getTestBroker(t, service, "broker-4").TotalSales)
+	assert.Equal(t, 1, getTestBroker(t, service, "broker-2").TotalSales)
+
+	pinheiros, err := service.propertyRepo.Get(ctx, "tenant-1", "pinheiros")
+	require.NoError(t, err)
+	assert.Equal(t, models.PropertyStatusUnavailable, pinheiros.Status)
+	assert.Equal(t, models.PropertyUnavailableReasonRented, pinheiros.UnavailableReason)
+	moema, err := service.propertyRepo.Get(ctx, "tenant-1", "moema")
+	require.NoError(t, err)
+	assert.Equal(t, models.PropertyStatusAvailable, moema.Status)
+	assert.Empty(t, moema.UnavailableReason)
+
+	// Nothing left to do
+	response, err = service.RecalculateBrokerStats(ctx, "tenant-1")
+	require.NoError(t, err)
+	assert.Equal(t, &BrokerStatsResponse{}, response)
+}
+
+func TestRecalculateBrokerStats_ReadsEveryDeal(t *testing.T) {
+	ctx := context.Background()
+	service, _, _ := newDealTestService(t)
+
+	// More closed deals than a page, closed at the same time
+	closedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	const deals = 2*dealStatsPageSize + 10
+	for i := 0; i < deals; i++ {
+		require.NoError(t, service.dealRepo.Create(ctx, &models.Deal{
+			TenantID:        "tenant-1",
+			PropertyID:      "moema",
+			TransactionType: models.TransactionTypeSale,
+			Status:          models.DealStatusClosed,
+			Price:           500000,
+			Brokers:         []models.DealBroker{{BrokerID: "broker-2", Role: models.BrokerPropertyRoleListing}},
+			BrokerIDs:       []string{"broker-2"},
+			ClosedAt:        closedAt,
+		}))
+	}
+
+	_, err := service.RecalculateBrokerStats(ctx, "tenant-1")
+	require.NoError(t, err)
+
+	broker := getTestBroker(t, service, "broker-2")
+	assert.Equal(t, deals, broker.TotalSales)
+	assert.Equal(t, 500000.0, broker.AveragePrice)
+}
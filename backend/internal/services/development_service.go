@@ -0,0 +1,841 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// Limits of development unit inventories
+const (
+	maxUnitsPerRequest  = 500  // Units created in one request
+	developmentUnitsCap = 5000 // Units read per development
+	unitExpiryBatch     = 1000 // Reservations released per expiry run
+	minRepricePercent   = -50.0
+	maxRepricePercent   = 100.0
+)
+
+// ErrUnitUnavailable is returned when a unit is reserved or sold to someone
+// else
+var ErrUnitUnavailable = errors.New("unit not available")
+
+// DevelopmentService handles the unit inventory (tabela e espelho de vendas)
+// of real estate developments and keeps their DevelopmentInfo counters
+type DevelopmentService struct {
+	unitRepo        repositories.DevelopmentUnitStore
+	propertyRepo    repositories.PropertyStore
+	activityLogRepo repositories.ActivityLogStore
+}
+
+// NewDevelopmentService creates a new development service
+func NewDevelopmentService(
+	unitRepo repositories.DevelopmentUnitStore,
+	propertyRepo repositories.PropertyStore,
+	activityLogRepo repositories.ActivityLogStore,
+) *DevelopmentService {
+	return &DevelopmentService{
+		unitRepo:        unitRepo,
+		propertyRepo:    propertyRepo,
+		activityLogRepo: activityLogRepo,
+	}
+}
+
+// ============================================================================
+// Units
+// ============================================================================
+
+// CreateUnitsResponse lists the units created by CreateUnits and the ones
+// skipped because the development already had them
+type CreateUnitsResponse struct {
+	Created  []*models.DevelopmentUnit `json:"created"`
+	Existing []string                  `json:"existing,omitempty"` // Labels of the units skipped
+}
+
+// CreateUnits adds units to the inventory of a development (usually the
+// whole price table at once). Every unit is validated before any is created.
+func (s *DevelopmentService) CreateUnits(ctx context.Context, tenantID, propertyID string, units []*models.DevelopmentUnit, actorID string) (*CreateUnitsResponse, error) {
+	if _, err := s.developmentProperty(ctx, tenantID, propertyID); err != nil {
+		return nil, err
+	}
+	if len(units) == 0 {
+		return nil, fmt.Errorf("%w: at least one unit is required", repositories.ErrInvalidInput)
+	}
+	if len(units) > maxUnitsPerRequest {
+		return nil, fmt.Errorf("%w: at most %d units per request", repositories.ErrInvalidInput, maxUnitsPerRequest)
+	}
+
+	seen := make(map[string]bool, len(units))
+	for _, unit := range units {
+		unit.Tower = strings.TrimSpace(unit.Tower)
+		unit.Number = strings.TrimSpace(unit.Number)
+		if err := unit.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: unit %s: %v", repositories.ErrInvalidInput, unit.Label(), err)
+		}
+		id := models.DevelopmentUnitID(propertyID, unit.Tower, unit.Number)
+		if seen[id] {
+			return nil, fmt.Errorf("%w: unit %s is listed twice", repositories.ErrInvalidInput, unit.Label())
+		}
+		seen[id] = true
+	}
+
+	response := &CreateUnitsResponse{Created: make([]*models.DevelopmentUnit, 0, len(units))}
+	for _, unit := range units {
+		unit.TenantID = tenantID
+		unit.PropertyID = propertyID
+		unit.Status = models.DevelopmentUnitStatusAvailable
+		unit.BrokerID, unit.LeadID, unit.BuyerName = "", "", ""
+		unit.ReservedAt, unit.ReservationExpiresAt, unit.SoldAt = nil, nil, nil
+		unit.SalePrice = 0
+
+		if err := s.unitRepo.Create(ctx, unit); err != nil {
+			if errors.Is(err, repositories.ErrAlreadyExists) {
+				response.Existing = append(response.Existing, unit.Label())
+				continue
+			}
+			s.recount(ctx, tenantID, propertyID)
+			return nil, fmt.Errorf("failed to create unit %s: %w", unit.Label(), err)
+		}
+		response.Created = append(response.Created, unit)
+	}
+
+	s.recount(ctx, tenantID, propertyID)
+
+	_ = s.logActivity(ctx, tenantID, "development_units_created", models.ActorTypeUser, actorID, map[string]interface{}{
+		"property_id": propertyID,
+		"created":     len(response.Created),
+		"existing":    len(response.Existing),
+	})
+
+	return response, nil
+}
+
+// UpdateUnitRequest changes the typology and price table of a unit. Nil
+// fields are left unchanged.
+type UpdateUnitRequest struct {
+	Floor        *int                    `json:"floor"`
+	Typology     *string                 `json:"typology"`
+	Bedrooms     *int                    `json:"bedrooms"`
+	Suites       *int                    `json:"suites"`
+	ParkingSpots *int                    `json:"parking_spots"`
+	PrivateArea  *float64                `json:"private_area"`
+	Price        *float64                `json:"price"`
+	PaymentPlan  *models.UnitPaymentPlan `json:"payment_plan"`
+	Notes        *string                 `json:"notes"`
+}
+
+// UpdateUnit updates the typology and price table of a unit. Tower and
+// number identify the unit and cannot change; the status changes through
+// ReserveUnit, ReleaseUnit and SellUnit.
+func (s *DevelopmentService) UpdateUnit(ctx context.Context, tenantID, id string, req UpdateUnitRequest, actorID string) (*models.DevelopmentUnit, error) {
+	unit, err := s.GetUnit(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make(map[string]interface{})
+	if req.Floor != nil {
+		unit.Floor = *req.Floor
+		updates["floor"] = unit.Floor
+	}
+	if req.Typology != nil {
+		unit.Typology = *req.Typology
+		updates["typology"] = unit.Typology
+	}
+	if req.Bedrooms != nil {
+		unit.Bedrooms = *req.Bedrooms
+		updates["bedrooms"] = unit.Bedrooms
+	}
+	if req.Suites != nil {
+		unit.Suites = *req.Suites
+		updates["suites"] = unit.Suites
+	}
+	if req.ParkingSpots != nil {
+		unit.ParkingSpots = *req.ParkingSpots
+		updates["parking_spots"] = unit.ParkingSpots
+	}
+	if req.PrivateArea != nil {
+		unit.PrivateArea = *req.PrivateArea
+		updates["private_area"] = unit.PrivateArea
+	}
+	if req.Price != nil {
+		unit.Price = *req.Price
+		updates["price"] = unit.Price
+	}
+	if req.PaymentPlan != nil {
+		unit.PaymentPlan = req.PaymentPlan
+		updates["payment_plan"] = unit.PaymentPlan
+	}
+	if req.Notes != nil {
+		unit.Notes = *req.Notes
+		updates["notes"] = unit.Notes
+	}
+	if len(updates) == 0 {
+		return unit, nil
+	}
+	if err := unit.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", repositories.ErrInvalidInput, err)
+	}
+
+	if err := s.unitRepo.Update(ctx, tenantID, id, updates); err != nil {
+		return nil, err
+	}
+
+	_ = s.logActivity(ctx, tenantID, "development_unit_updated", models.ActorTypeUser, actorID, unitLogMetadata(unit))
+
+	return unit, nil
+}
+
+// DeleteUnit removes a unit that is neither reserved nor sold
+func (s *DevelopmentService) DeleteUnit(ctx context.Context, tenantID, id, actorID string) error {
+	unit, err := s.GetUnit(ctx, tenantID, id)
+	if err != nil {
+		return err
+	}
+	if !unit.IsFree(time.Now()) {
+		return fmt.Errorf("%w: unit %s is %s", ErrUnitUnavailable, unit.Label(), unit.Status)
+	}
+
+	if err := s.unitRepo.Delete(ctx, tenantID, id); err != nil {
+		return fmt.Errorf("failed to delete unit: %w", err)
+	}
+
+	s.recount(ctx, tenantID, unit.PropertyID)
+
+	_ = s.logActivity(ctx, tenantID, "development_unit_deleted", models.ActorTypeUser, actorID, unitLogMetadata(unit))
+
+	return nil
+}
+
+// GetUnit retrieves a unit by ID
+func (s *DevelopmentService) GetUnit(ctx context.Context, tenantID, id string) (*models.DevelopmentUnit, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+
+	unit, err := s.unitRepo.Get(ctx, tenantID, id)
+	if err != nil {
+		return nil, fmt.Errorf("unit not found: %w", err)
+	}
+	return unit, nil
+}
+
+// ListUnits lists the units of a development with filters
+func (s *DevelopmentService) ListUnits(ctx context.Context, tenantID, propertyID string, filters *repositories.DevelopmentUnitFilters, opts repositories.PaginationOptions) ([]*models.DevelopmentUnit, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+
+	if filters == nil {
+		filters = &repositories.DevelopmentUnitFilters{}
+	}
+	filters.PropertyID = propertyID
+
+	units, err := s.unitRepo.List(ctx, tenantID, filters, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list units: %w", err)
+	}
+	return units, nil
+}
+
+// RepriceUnitsRequest applies a percentage to the price table (reajuste da
+// tabela). Tower and Typology restrict the units repriced.
+type RepriceUnitsRequest struct {
+	Percentage float64 `json:"percentage" binding:"required"`
+	Tower      string  `json:"tower"`
+	Typology   string  `json:"typology"`
+}
+
+// RepriceUnitsResponse summarizes a reprice of the price table
+type RepriceUnitsResponse struct {
+	Repriced int `json:"repriced"`
+	Skipped  int `json:"skipped"` // Reserved or sold meanwhile
+}
+
+// RepriceUnits applies a percentage to the price and payment plan of the
+// units still for sale. Reserved and sold units keep the price they were
+// negotiated at.
+func (s *DevelopmentService) RepriceUnits(ctx context.Context, tenantID, propertyID string, req RepriceUnitsRequest, actorID string) (*RepriceUnitsResponse, error) {
+	if _, err := s.developmentProperty(ctx, tenantID, propertyID); err != nil {
+		return nil, err
+	}
+	if req.Percentage == 0 || req.Percentage < minRepricePercent || req.Percentage > maxRepricePercent {
+		return nil, fmt.Errorf("%w: percentage must be between %.0f and %.0f and not zero", repositories.ErrInvalidInput, minRepricePercent, maxRepricePercent)
+	}
+
+	units, err := s.unitRepo.List(ctx, tenantID, &repositories.DevelopmentUnitFilters{
+		PropertyID: propertyID,
+		Tower:      req.Tower,
+		Typology:   req.Typology,
+	}, repositories.PaginationOptions{Limit: developmentUnitsCap})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list units: %w", err)
+	}
+
+	now := time.Now()
+	response := &RepriceUnitsResponse{}
+	for _, unit := range units {
+		if !unit.IsFree(now) {
+			continue
+		}
+		updates := make(map[string]interface{})
+		if unit.PaymentPlan != nil {
+			plan := *unit.PaymentPlan
+			unit.PaymentPlan = &plan
+			updates["payment_plan"] = unit.PaymentPlan
+		}
+		unit.Reprice(req.Percentage)
+		updates["price"] = unit.Price
+
+		updated, err := s.unitRepo.UpdateIf(ctx, tenantID, unit.ID, func(current *models.DevelopmentUnit) bool {
+			return current.IsFree(now)
+		}, updates)
+		if err != nil {
+			return nil, err
+		}
+		if !updated {
+			response.Skipped++
+			continue
+		}
+		response.Repriced++
+	}
+
+	_ = s.logActivity(ctx, tenantID, "development_units_repriced", models.ActorTypeUser, actorID, map[string]interface{}{
+		"property_id": propertyID,
+		"percentage":  req.Percentage,
+		"tower":       req.Tower,
+		"typology":    req.Typology,
+		"repriced":    response.Repriced,
+	})
+
+	return response, nil
+}
+
+// ============================================================================
+// Reservations and sales
+// ============================================================================
+
+// ReserveUnitRequest holds a unit for a buyer
+type ReserveUnitRequest struct {
+	BrokerID  string `json:"broker_id"`
+	LeadID    string `json:"lead_id"`
+	BuyerName string `json:"buyer_name"`
+	Hours     int    `json:"hours"` // Default models.DefaultUnitReservationHours
+	Notes     string `json:"notes"`
+}
+
+// ReserveUnit holds an available unit for a buyer for a number of hours.
+// Two brokers reserving the same unit at once cannot both succeed.
+func (s *DevelopmentService) ReserveUnit(ctx context.Context, tenantID, id string, req ReserveUnitRequest, actorID string) (*models.DevelopmentUnit, error) {
+	if req.Hours == 0 {
+		req.Hours = models.DefaultUnitReservationHours
+	}
+	if req.Hours < 0 || req.Hours > models.MaxUnitReservationHours {
+		return nil, fmt.Errorf("%w: hours must be between 1 and %d", repositories.ErrInvalidInput, models.MaxUnitReservationHours)
+	}
+	if req.BrokerID == "" {
+		req.BrokerID = actorID
+	}
+	if req.LeadID == "" && strings.TrimSpace(req.BuyerName) == "" {
+		return nil, fmt.Errorf("%w: lead_id or buyer_name is required", repositories.ErrInvalidInput)
+	}
+
+	unit, err := s.GetUnit(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(time.Duration(req.Hours) * time.Hour)
+	updated, err := s.unitRepo.UpdateIf(ctx, tenantID, id, func(current *models.DevelopmentUnit) bool {
+		return current.IsFree(now)
+	}, map[string]interface{}{
+		"status":                 models.DevelopmentUnitStatusReserved,
+		"broker_id":              req.BrokerID,
+		"lead_id":                req.LeadID,
+		"buyer_name":             strings.TrimSpace(req.BuyerName),
+		"reserved_at":            now,
+		"reservation_expires_at": expiresAt,
+		"notes":                  req.Notes,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, fmt.Errorf("%w: unit %s is already reserved or sold", ErrUnitUnavailable, unit.Label())
+	}
+
+	unit.Status = models.DevelopmentUnitStatusReserved
+	unit.BrokerID, unit.LeadID, unit.BuyerName = req.BrokerID, req.LeadID, strings.TrimSpace(req.BuyerName)
+	unit.ReservedAt, unit.ReservationExpiresAt = &now, &expiresAt
+	unit.Notes = req.Notes
+
+	s.recount(ctx, tenantID, unit.PropertyID)
+
+	_ = s.logActivity(ctx, tenantID, "development_unit_reserved", models.ActorTypeUser, actorID, unitLogMetadata(unit))
+
+	return unit, nil
+}
+
+// ReleaseUnit makes a reserved unit available again. Releasing a sold unit
+// undoes the sale (distrato) and requires a reason.
+func (s *DevelopmentService) ReleaseUnit(ctx context.Context, tenantID, id, reason, actorID string) (*models.DevelopmentUnit, error) {
+	unit, err := s.GetUnit(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	status := unit.Status
+	switch status {
+	case models.DevelopmentUnitStatusReserved:
+	case models.DevelopmentUnitStatusSold:
+		if strings.TrimSpace(reason) == "" {
+			return nil, fmt.Errorf("%w: reason is required to undo a sale", repositories.ErrInvalidInput)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unit %s is not reserved or sold", repositories.ErrInvalidInput, unit.Label())
+	}
+
+	updated, err := s.unitRepo.UpdateIf(ctx, tenantID, id, func(current *models.DevelopmentUnit) bool {
+		return current.Status == status
+	}, releasedUnitUpdates(reason))
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, fmt.Errorf("%w: unit %s changed meanwhile", ErrUnitUnavailable, unit.Label())
+	}
+
+	released := *unit
+	releaseUnit(unit, reason)
+	s.recount(ctx, tenantID, unit.PropertyID)
+
+	eventType := "development_unit_released"
+	if status == models.DevelopmentUnitStatusSold {
+		eventType = "development_unit_sale_undone"
+	}
+	metadata := unitLogMetadata(&released)
+	metadata["reason"] = reason
+	_ = s.logActivity(ctx, tenantID, eventType, models.ActorTypeUser, actorID, metadata)
+
+	return unit, nil
+}
+
+// SellUnitRequest records the sale of a unit
+type SellUnitRequest struct {
+	BrokerID  string  `json:"broker_id"`
+	LeadID    string  `json:"lead_id"`
+	BuyerName string  `json:"buyer_name"`
+	SalePrice float64 `json:"sale_price"` // Default: table price
+	Notes     string  `json:"notes"`
+}
+
+// SellUnit records the sale of an available unit, or of a reserved one
+// whose reservation is still running. Fields left empty are kept from the
+// reservation.
+func (s *DevelopmentService) SellUnit(ctx context.Context, tenantID, id string, req SellUnitRequest, actorID string) (*models.DevelopmentUnit, error) {
+	if req.SalePrice < 0 {
+		return nil, fmt.Errorf("%w: sale_price cannot be negative", repositories.ErrInvalidInput)
+	}
+
+	unit, err := s.GetUnit(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if unit.EffectiveStatus(now) == models.DevelopmentUnitStatusReserved {
+		if req.BrokerID == "" {
+			req.BrokerID = unit.BrokerID
+		}
+		if req.LeadID == "" {
+			req.LeadID = unit.LeadID
+		}
+		if req.BuyerName == "" {
+			req.BuyerName = unit.BuyerName
+		}
+	}
+	if req.BrokerID == "" {
+		req.BrokerID = actorID
+	}
+	if req.LeadID == "" && strings.TrimSpace(req.BuyerName) == "" {
+		return nil, fmt.Errorf("%w: lead_id or buyer_name is required", repositories.ErrInvalidInput)
+	}
+	if req.SalePrice == 0 {
+		req.SalePrice = unit.Price
+	}
+
+	reservedBy := unit.ReservedAt
+	updated, err := s.unitRepo.UpdateIf(ctx, tenantID, id, func(current *models.DevelopmentUnit) bool {
+		switch current.EffectiveStatus(now) {
+		case models.DevelopmentUnitStatusAvailable:
+			return true
+		case models.DevelopmentUnitStatusReserved:
+			// Only the reservation read above, not one taken meanwhile
+			return reservedBy != nil && current.ReservedAt != nil && current.ReservedAt.Equal(*reservedBy)
+		}
+		return false
+	}, map[string]interface{}{
+		"status":                 models.DevelopmentUnitStatusSold,
+		"broker_id":              req.BrokerID,
+		"lead_id":                req.LeadID,
+		"buyer_name":             strings.TrimSpace(req.BuyerName),
+		"sold_at":                now,
+		"sale_price":             req.SalePrice,
+		"reservation_expires_at": nil,
+		"notes":                  req.Notes,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, fmt.Errorf("%w: unit %s is reserved or sold", ErrUnitUnavailable, unit.Label())
+	}
+
+	unit.Status = models.DevelopmentUnitStatusSold
+	unit.BrokerID, unit.LeadID, unit.BuyerName = req.BrokerID, req.LeadID, strings.TrimSpace(req.BuyerName)
+	unit.SoldAt, unit.SalePrice = &now, req.SalePrice
+	unit.ReservationExpiresAt = nil
+	unit.Notes = req.Notes
+
+	s.recount(ctx, tenantID, unit.PropertyID)
+
+	_ = s.logActivity(ctx, tenantID, "development_unit_sold", models.ActorTypeUser, actorID, unitLogMetadata(unit))
+
+	return unit, nil
+}
+
+// ProcessExpiredReservationsResponse summarizes a run of the unit
+// reservation expiry job
+type ProcessExpiredReservationsResponse struct {
+	Released int `json:"released"`
+	Failed   int `json:"failed"`
+}
+
+// ProcessExpiredReservations makes the units whose reservation ran out
+// available again and recomputes the counters of their developments
+func (s *DevelopmentService) ProcessExpiredReservations(ctx context.Context, tenantID string) (*ProcessExpiredReservationsResponse, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+
+	now := time.Now()
+	units, err := s.unitRepo.List(ctx, tenantID, &repositories.DevelopmentUnitFilters{
+		Statuses:                 []models.DevelopmentUnitStatus{models.DevelopmentUnitStatusReserved},
+		ReservationExpiresBefore: &now,
+	}, repositories.PaginationOptions{Limit: unitExpiryBatch})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expired unit reservations: %w", err)
+	}
+
+	response := &ProcessExpiredReservationsResponse{}
+	developments := make(map[string]bool)
+	for _, unit := range units {
+		updated, err := s.unitRepo.UpdateIf(ctx, tenantID, unit.ID, func(current *models.DevelopmentUnit) bool {
+			return current.Status == models.DevelopmentUnitStatusReserved && current.IsFree(now)
+		}, releasedUnitUpdates(""))
+		if err != nil {
+			log.Printf("⚠️  Failed to release the expired reservation of unit %s: %v", unit.ID, err)
+			response.Failed++
+			continue
+		}
+		developments[unit.PropertyID] = true
+		if !updated {
+			continue // Sold or reserved again meanwhile
+		}
+		_ = s.logActivity(ctx, tenantID, "development_unit_reservation_expired", models.ActorTypeSystem, "", unitLogMetadata(unit))
+		response.Released++
+	}
+
+	for propertyID := range developments {
+		s.recount(ctx, tenantID, propertyID)
+	}
+
+	return response, nil
+}
+
+// ============================================================================
+// Counters and sales mirror
+// ============================================================================
+
+// Recount recomputes the unit counters of a development from its units
+func (s *DevelopmentService) Recount(ctx context.Context, tenantID, propertyID string) (*models.UnitCounts, error) {
+	property, err := s.developmentProperty(ctx, tenantID, propertyID)
+	if err != nil {
+		return nil, err
+	}
+
+	units, err := s.developmentUnits(ctx, tenantID, propertyID)
+	if err != nil {
+		return nil, err
+	}
+	counts := models.CountUnits(units, time.Now())
+
+	var updates map[string]interface{}
+	if property.DevelopmentInfo == nil {
+		updates = map[string]interface{}{
+			"development_info": &models.DevelopmentInfo{
+				TotalUnits:     counts.Total,
+				UnitsAvailable: counts.Available,
+				UnitsReserved:  counts.Reserved,
+				UnitsSold:      counts.Sold,
+			},
+		}
+	} else {
+		updates = map[string]interface{}{
+			"development_info.total_units":     counts.Total,
+			"development_info.units_available": counts.Available,
+			"development_info.units_reserved":  counts.Reserved,
+			"development_info.units_sold":      counts.Sold,
+		}
+	}
+	if err := s.propertyRepo.Update(ctx, tenantID, propertyID, updates); err != nil {
+		return nil, fmt.Errorf("failed to update development counters: %w", err)
+	}
+
+	return &counts, nil
+}
+
+// recount recomputes the counters of a development after a change to its
+// units. The change stands even if the counters fail; the next one fixes
+// them.
+func (s *DevelopmentService) recount(ctx context.Context, tenantID, propertyID string) {
+	if _, err := s.Recount(ctx, tenantID, propertyID); err != nil {
+		log.Printf("⚠️  Failed to recount the units of development %s: %v", propertyID, err)
+	}
+}
+
+// SalesMirror is the espelho de vendas of a development: its units laid out
+// by tower and floor, top floor first, with their status
+type SalesMirror struct {
+	PropertyID     string            `json:"property_id"`
+	ProjectName    string            `json:"project_name,omitempty"`
+	Counts         models.UnitCounts `json:"counts"`
+	TotalValue     float64           `json:"total_value"`     // VGV: table price of the unsold units plus sale price of the sold ones
+	SoldValue      float64           `json:"sold_value"`      // Sale price of the sold units
+	AvailableValue float64           `json:"available_value"` // Table price of the available units
+	Towers         []MirrorTower     `json:"towers"`
+}
+
+// MirrorTower is a tower (or block) of the sales mirror
+type MirrorTower struct {
+	Name   string        `json:"name"` // "" for a single building
+	Floors []MirrorFloor `json:"floors"`
+}
+
+// MirrorFloor is a floor of a tower of the sales mirror
+type MirrorFloor struct {
+	Floor int          `json:"floor"`
+	Units []MirrorUnit `json:"units"`
+}
+
+// MirrorUnit is a unit of the sales mirror. Buyers are not disclosed.
+type MirrorUnit struct {
+	ID                   string                       `json:"id"`
+	Number               string                       `json:"number"`
+	Typology             string                       `json:"typology,omitempty"`
+	PrivateArea          float64                      `json:"private_area,omitempty"`
+	Price                float64                      `json:"price"`
+	Status               models.DevelopmentUnitStatus `json:"status"`
+	ReservationExpiresAt *time.Time                   `json:"reservation_expires_at,omitempty"`
+	BrokerID             string                       `json:"broker_id,omitempty"`
+}
+
+// SalesMirror lays out the units of a development by tower and floor with
+// their status now (reservations past their expiry read as available)
+func (s *DevelopmentService) SalesMirror(ctx context.Context, tenantID, propertyID string) (*SalesMirror, error) {
+	property, err := s.developmentProperty(ctx, tenantID, propertyID)
+	if err != nil {
+		return nil, err
+	}
+
+	units, err := s.developmentUnits(ctx, tenantID, propertyID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	mirror := &SalesMirror{
+		PropertyID: propertyID,
+		Counts:     models.CountUnits(units, now),
+		Towers:     make([]MirrorTower, 0),
+	}
+	if property.DevelopmentInfo != nil {
+		mirror.ProjectName = property.DevelopmentInfo.ProjectName
+	}
+
+	sort.SliceStable(units, func(i, j int) bool {
+		a, b := units[i], units[j]
+		if a.Tower != b.Tower {
+			return unitNumberLess(a.Tower, b.Tower)
+		}
+		if a.Floor != b.Floor {
+			return a.Floor > b.Floor
+		}
+		return unitNumberLess(a.Number, b.Number)
+	})
+
+	for _, unit := range units {
+		status := unit.EffectiveStatus(now)
+		entry := MirrorUnit{
+			ID:          unit.ID,
+			Number:      unit.Number,
+			Typology:    unit.Typology,
+			PrivateArea: unit.PrivateArea,
+			Price:       unit.Price,
+			Status:      status,
+		}
+		switch status {
+		case models.DevelopmentUnitStatusAvailable:
+			mirror.AvailableValue += unit.Price
+			mirror.TotalValue += unit.Price
+		case models.DevelopmentUnitStatusReserved:
+			entry.ReservationExpiresAt = unit.ReservationExpiresAt
+			entry.BrokerID = unit.BrokerID
+			mirror.TotalValue += unit.Price
+		case models.DevelopmentUnitStatusSold:
+			entry.BrokerID = unit.BrokerID
+			price := unit.SalePrice
+			if price == 0 {
+				price = unit.Price
+			}
+			mirror.SoldValue += price
+			mirror.TotalValue += price
+		}
+
+		if n := len(mirror.Towers); n == 0 || mirror.Towers[n-1].Name != unit.Tower {
+			mirror.Towers = append(mirror.Towers, MirrorTower{Name: unit.Tower})
+		}
+		tower := &mirror.Towers[len(mirror.Towers)-1]
+		if n := len(tower.Floors); n == 0 || tower.Floors[n-1].Floor != unit.Floor {
+			tower.Floors = append(tower.Floors, MirrorFloor{Floor: unit.Floor})
+		}
+		floor := &tower.Floors[len(tower.Floors)-1]
+		floor.Units = append(floor.Units, entry)
+	}
+
+	mirror.TotalValue = roundCents(mirror.TotalValue)
+	mirror.SoldValue = roundCents(mirror.SoldValue)
+	mirror.AvailableValue = roundCents(mirror.AvailableValue)
+
+	return mirror, nil
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+// developmentProperty returns a property that is a real estate development
+func (s *DevelopmentService) developmentProperty(ctx context.Context, tenantID, propertyID string) (*models.Property, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+
+	property, err := s.propertyRepo.Get(ctx, tenantID, propertyID)
+	if err != nil {
+		return nil, fmt.Errorf("property not found: %w", err)
+	}
+	if property.DevelopmentInfo == nil && property.PropertyType != models.PropertyTypeNewDevelopment {
+		return nil, fmt.Errorf("%w: property is not a real estate development", repositories.ErrInvalidInput)
+	}
+	return property, nil
+}
+
+// developmentUnits returns every unit of a development
+func (s *DevelopmentService) developmentUnits(ctx context.Context, tenantID, propertyID string) ([]*models.DevelopmentUnit, error) {
+	units, err := s.unitRepo.List(ctx, tenantID, &repositories.DevelopmentUnitFilters{
+		PropertyID: propertyID,
+	}, repositories.PaginationOptions{Limit: developmentUnitsCap})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list units: %w", err)
+	}
+	return units, nil
+}
+
+// releasedUnitUpdates returns the updates that make a unit available again
+func releasedUnitUpdates(reason string) map[string]interface{} {
+	return map[string]interface{}{
+		"status":                 models.DevelopmentUnitStatusAvailable,
+		"broker_id":              "",
+		"lead_id":                "",
+		"buyer_name":             "",
+		"reserved_at":            nil,
+		"reservation_expires_at": nil,
+		"sold_at":                nil,
+		"sale_price":             0.0,
+		"notes":                  reason,
+	}
+}
+
+// releaseUnit applies releasedUnitUpdates to a unit in memory
+func releaseUnit(unit *models.DevelopmentUnit, reason string) {
+	unit.Status = models.DevelopmentUnitStatusAvailable
+	unit.BrokerID, unit.LeadID, unit.BuyerName = "", "", ""
+	unit.ReservedAt, unit.ReservationExpiresAt, unit.SoldAt = nil, nil, nil
+	unit.SalePrice = 0
+	unit.Notes = reason
+}
+
+// unitNumberLess orders towers and unit numbers naturally: "2" before "10",
+// "Lote 9" before "Lote 10"
+func unitNumberLess(a, b string) bool {
+	prefixA, numberA := splitUnitNumber(a)
+	prefixB, numberB := splitUnitNumber(b)
+	if prefixA == prefixB && numberA >= 0 && numberB >= 0 && numberA != numberB {
+		return numberA < numberB
+	}
+	return a < b
+}
+
+// splitUnitNumber splits the trailing number off a tower or unit number; -1
+// when it does not end in digits
+func splitUnitNumber(value string) (string, int) {
+	prefix := strings.TrimRight(value, "0123456789")
+	number, err := strconv.Atoi(value[len(prefix):])
+	if err != nil {
+		return value, -1
+	}
+	return prefix, number
+}
+
+// unitLogMetadata returns the activity log metadata of a unit
+func unitLogMetadata(unit *models.DevelopmentUnit) map[string]interface{} {
+	metadata := map[string]interface{}{
+		"unit_id":     unit.ID,
+		"property_id": unit.PropertyID,
+		"unit":        unit.Label(),
+		"status":      unit.Status,
+		"price":       unit.Price,
+	}
+	if unit.BrokerID != "" {
+		metadata["broker_id"] = unit.BrokerID
+	}
+	if unit.LeadID != "" {
+		metadata["lead_id"] = unit.LeadID
+	}
+	if unit.SalePrice > 0 {
+		metadata["sale_price"] = unit.SalePrice
+	}
+	return metadata
+}
+
+// logActivity logs an activity to the activity log
+func (s *DevelopmentService) logActivity(ctx context.Context, tenantID, eventType string, actorType models.ActorType, actorID string, metadata map[string]interface{}) error {
+	log := &models.ActivityLog{
+		TenantID:  tenantID,
+		EventType: eventType,
+		ActorType: actorType,
+		ActorID:   actorID,
+		Metadata:  metadata,
+		Timestamp: time.Now(),
+	}
+
+	return s.activityLogRepo.Create(ctx, log)
+}
@@ -0,0 +1,234 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories/memory"
+)
+
+// newDevelopmentTestService returns a development service over the routing
+// test data: property "moema" is a development (lançamento)
+func newDevelopmentTestService(t *testing.T) *DevelopmentService {
+	t.Helper()
+
+	leadService, _ := newActivityTestService(t)
+	require.NoError(t, leadService.propertyRepo.Update(context.Background(), "tenant-1", "moema", map[string]interface{}{
+		"property_type":    models.PropertyTypeNewDevelopment,
+		"development_info": &models.DevelopmentInfo{ProjectName: "Residencial Vista Verde"},
+	}))
+
+	return NewDevelopmentService(
+		memory.NewDevelopmentUnitRepository(),
+		leadService.propertyRepo,
+		leadService.activityLogRepo,
+	)
+}
+
+// createDevelopmentTestUnits creates two towers of two floors with two
+// units each
+func createDevelopmentTestUnits(t *testing.T, service *DevelopmentService) {
+	t.Helper()
+
+	var units []*models.DevelopmentUnit
+	for _, tower := range []string{"B", "A"} {
+		for _, floor := range []int{1, 2} {
+			for _, end := range []string{"02", "01"} {
+				units = append(units, &models.DevelopmentUnit{
+					Tower:    tower,
+					Floor:    floor,
+					Number:   fmt.Sprintf("%d%s", floor, end),
+					Typology: "2 dorms",
+					Price:    500000 + float64(floor)*10000,
+				})
+			}
+		}
+	}
+	response, err := service.CreateUnits(context.Background(), "tenant-1", "moema", units, "admin-1")
+	require.NoError(t, err)
+	require.Len(t, response.Created, 8)
+}
+
+func developmentTestCounters(t *testing.T, service *DevelopmentService) *models.DevelopmentInfo {
+	t.Helper()
+
+	property, err := service.propertyRepo.Get(context.Background(), "tenant-1", "moema")
+	require.NoError(t, err)
+	require.NotNil(t, property.DevelopmentInfo)
+	return property.DevelopmentInfo
+}
+
+func TestDevelopmentUnits_CreateRecountsDevelopment(t *testing.T) {
+	ctx := context.Background()
+	service := newDevelopmentTestService(t)
+	createDevelopmentTestUnits(t, service)
+
+	info := developmentTestCounters(t, service)
+	assert.Equal(t, 8, info.TotalUnits)
+	assert.Equal(t, 8, info.UnitsAvailable)
+	assert.Equal(t, "Residencial Vista Verde", info.ProjectName, "the counters update only their fields")
+
+	// Units already in the inventory are skipped; the batch is validated first
+	response, err := service.CreateUnits(ctx, "tenant-1", "moema", []*models.DevelopmentUnit{
+		{Tower: "A", Floor: 1, Number: "101", Price: 510000},
+		{Tower: "A", Floor: 3, Number: "301", Price: 530000},
+	}, "admin-1")
+	require.NoError(t, err)
+	assert.Len(t, response.Created, 1)
+	assert.Equal(t, []string{"A 101"}, response.Existing)
+
+	_, err = service.CreateUnits(ctx, "tenant-1", "moema", []*models.DevelopmentUnit{
+		{Tower: "A", Floor: 4, Number: "401", Price: 540000},
+		{Tower: "a", Floor: 4, Number: " 401", Price: 540000},
+	}, "admin-1")
+	assert.True(t, errors.Is(err, repositories.ErrInvalidInput), "the same unit twice")
+
+	// Only developments have units
+	_, err = service.CreateUnits(ctx, "tenant-1", "pinheiros", []*models.DevelopmentUnit{
+		{Floor: 1, Number: "11", Price: 400000},
+	}, "admin-1")
+	assert.True(t, errors.Is(err, repositories.ErrInvalidInput))
+
+	assert.Equal(t, 9, developmentTestCounters(t, service).TotalUnits)
+}
+
+func TestDevelopmentUnits_ReserveConflictAndSell(t *testing.T) {
+	ctx := context.Background()
+	service := newDevelopmentTestService(t)
+	createDevelopmentTestUnits(t, service)
+	id := models.DevelopmentUnitID("moema", "A", "101")
+
+	unit, err := service.ReserveUnit(ctx, "tenant-1", id, ReserveUnitRequest{BrokerID: "broker-3", BuyerName: "Ana Lima"}, "broker-3")
+	require.NoError(t, err)
+	assert.Equal(t, models.DevelopmentUnitStatusReserved, unit.Status)
+	require.NotNil(t, unit.ReservationExpiresAt)
+	assert.WithinDuration(t, time.Now().Add(48*time.Hour), *unit.ReservationExpiresAt, time.Minute)
+
+	// Another broker cannot reserve nor sell it
+	_, err = service.ReserveUnit(ctx, "tenant-1", id, ReserveUnitRequest{BrokerID: "broker-1", BuyerName: "João"}, "broker-1")
+	assert.True(t, errors.Is(err, ErrUnitUnavailable))
+	assert.Equal(t, 1, developmentTestCounters(t, service).UnitsReserved)
+
+	// The sale keeps the buyer of the reservation and defaults to the table price
+	sold, err := service.SellUnit(ctx, "tenant-1", id, SellUnitRequest{}, "admin-1")
+	require.NoError(t, err)
+	assert.Equal(t, models.DevelopmentUnitStatusSold, sold.Status)
+	assert.Equal(t, "Ana Lima", sold.BuyerName)
+	assert.Equal(t, "broker-3", sold.BrokerID)
+	assert.Equal(t, 510000.0, sold.SalePrice)
+	assert.Nil(t, sold.ReservationExpiresAt)
+
+	info := developmentTestCounters(t, service)
+	assert.Equal(t, 0, info.UnitsReserved)
+	assert.Equal(t, 1, info.UnitsSold)
+	assert.Equal(t, 7, info.UnitsAvailable)
+
+	// Sold units cannot be deleted; undoing the sale needs a reason
+	assert.True(t, errors.Is(service.DeleteUnit(ctx, "tenant-1", id, "admin-1"), ErrUnitUnavailable))
+	_, err = service.ReleaseUnit(ctx, "tenant-1", id, "", "admin-1")
+	assert.True(t, errors.Is(err, repositories.ErrInvalidInput))
+
+	released, err := service.ReleaseUnit(ctx, "tenant-1", id, "Distrato", "admin-1")
+	require.NoError(t, err)
+	assert.Equal(t, models.DevelopmentUnitStatusAvailable, released.Status)
+	assert.Empty(t, released.BuyerName)
+	assert.Equal(t, 0, developmentTestCounters(t, service).UnitsSold)
+}
+
+func TestDevelopmentUnits_ProcessExpiredReservations(t *testing.T) {
+	ctx := context.Background()
+	service := newDevelopmentTestService(t)
+	createDevelopmentTestUnits(t, service)
+	expired := models.DevelopmentUnitID("moema", "A", "101")
+	running := models.DevelopmentUnitID("moema", "A", "102")
+
+	for _, id := range []string{expired, running} {
+		_, err := service.ReserveUnit(ctx, "tenant-1", id, ReserveUnitRequest{BuyerName: "Ana Lima"}, "broker-3")
+		require.NoError(t, err)
+	}
+	past := time.Now().Add(-time.Minute)
+	require.NoError(t, service.unitRepo.Update(ctx, "tenant-1", expired, map[string]interface{}{
+		"reservation_expires_at": past,
+	}))
+
+	// A reservation past its expiry no longer holds the unit
+	_, err := service.ReserveUnit(ctx, "tenant-1", expired, ReserveUnitRequest{BuyerName: "João"}, "broker-1")
+	require.NoError(t, err)
+	require.NoError(t, service.unitRepo.Update(ctx, "tenant-1", expired, map[string]interface{}{
+		"reservation_expires_at": past,
+	}))
+
+	response, err := service.ProcessExpiredReservations(ctx, "tenant-1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, response.Released)
+	assert.Equal(t, 0, response.Failed)
+
+	unit, err := service.GetUnit(ctx, "tenant-1", expired)
+	require.NoError(t, err)
+	assert.Equal(t, models.DevelopmentUnitStatusAvailable, unit.Status)
+	assert.Nil(t, unit.ReservationExpiresAt)
+
+	info := developmentTestCounters(t, service)
+	assert.Equal(t, 1, info.UnitsReserved)
+	assert.Equal(t, 7, info.UnitsAvailable)
+}
+
+func TestDevelopmentUnits_RepriceSkipsNegotiatedUnits(t *testing.T) {
+	ctx := context.Background()
+	service := newDevelopmentTestService(t)
+	createDevelopmentTestUnits(t, service)
+	reserved := models.DevelopmentUnitID("moema", "A", "101")
+	_, err := service.ReserveUnit(ctx, "tenant-1", reserved, ReserveUnitRequest{BuyerName: "Ana Lima"}, "broker-3")
+	require.NoError(t, err)
+
+	response, err := service.RepriceUnits(ctx, "tenant-1", "moema", RepriceUnitsRequest{Percentage: 10, Tower: "A"}, "admin-1")
+	require.NoError(t, err)
+	assert.Equal(t, 3, response.Repriced)
+
+	unit, err := service.GetUnit(ctx, "tenant-1", models.DevelopmentUnitID("moema", "A", "102"))
+	require.NoError(t, err)
+	assert.Equal(t, 561000.0, unit.Price)
+
+	unit, err = service.GetUnit(ctx, "tenant-1", reserved)
+	require.NoError(t, err)
+	assert.Equal(t, 510000.0, unit.Price, "reserved at the old price")
+
+	_, err = service.RepriceUnits(ctx, "tenant-1", "moema", RepriceUnitsRequest{Percentage: 150}, "admin-1")
+	assert.True(t, errors.Is(err, repositories.ErrInvalidInput))
+}
+
+func TestDevelopmentUnits_SalesMirror(t *testing.T) {
+	ctx := context.Background()
+	service := newDevelopmentTestService(t)
+	createDevelopmentTestUnits(t, service)
+	_, err := service.SellUnit(ctx, "tenant-1", models.DevelopmentUnitID("moema", "B", "201"), SellUnitRequest{BuyerName: "Ana Lima", SalePrice: 500000}, "broker-3")
+	require.NoError(t, err)
+
+	mirror, err := service.SalesMirror(ctx, "tenant-1", "moema")
+	require.NoError(t, err)
+	assert.Equal(t, "Residencial Vista Verde", mirror.ProjectName)
+	assert.Equal(t, models.UnitCounts{Total: 8, Available: 7, Sold: 1}, mirror.Counts)
+
+	// Towers in order, top floor first, units in order
+	require.Len(t, mirror.Towers, 2)
+	assert.Equal(t, "A", mirror.Towers[0].Name)
+	tower := mirror.Towers[1]
+	require.Len(t, tower.Floors, 2)
+	assert.Equal(t, 2, tower.Floors[0].Floor)
+	require.Len(t, tower.Floors[0].Units, 2)
+	assert.Equal(t, "201", tower.Floors[0].Units[0].Number)
+	assert.Equal(t, models.DevelopmentUnitStatusSold, tower.Floors[0].Units[0].Status)
+	assert.Equal(t, "202", tower.Floors[0].Units[1].Number)
+
+	// VGV: table price of the unsold units plus the sale price
+	assert.Equal(t, 4*510000.0+4*520000.0-520000+500000, mirror.TotalValue)
+	assert.Equal(t, 500000.0, mirror.SoldValue)
+}
@@ -8,8 +8,11 @@ import (
 	"time"
 
 	"cloud.google.com/go/firestore"
+	"github.com/altatech/ecosistema-imob/backend/internal/adapters"
 	"github.com/altatech/ecosistema-imob/backend/internal/adapters/union"
+	"github.com/altatech/ecosistema-imob/backend/internal/adapters/vrsync"
 	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
 	"github.com/google/uuid"
 )
 
@@ -18,6 +21,9 @@ type ImportService struct {
 	db                   *firestore.Client
 	deduplicationService *DeduplicationService
 	photoProcessor       *PhotoProcessor // Optional - nil if GCS not configured
+	propertyIndexer      PropertyIndexer // Optional - keeps the full-text index in sync
+	importers            *adapters.Registry
+	historyRepo          repositories.PropertyHistoryStore // Optional - price/status history
 }
 
 // NewImportService creates a new import service
@@ -26,6 +32,7 @@ func NewImportService(db *firestore.Client) *ImportService {
 		db:                   db,
 		deduplicationService: NewDeduplicationService(db),
 		photoProcessor:       nil, // Will be set via SetPhotoProcessor if needed
+		importers:            defaultImporters(),
 	}
 }
 
@@ -35,14 +42,55 @@ func NewImportServiceWithPhotos(db *firestore.Client, photoProcessor *PhotoProce
 		db:                   db,
 		deduplicationService: NewDeduplicationService(db),
 		photoProcessor:       photoProcessor,
+		importers:            defaultImporters(),
 	}
 }
 
+// defaultImporters returns the registry of the supported CRM export formats
+func defaultImporters() *adapters.Registry {
+	return adapters.NewRegistry(union.NewImporter(), vrsync.NewImporter())
+}
+
+// SetImporters replaces the importer registry (optional)
+func (s *ImportService) SetImporters(importers *adapters.Registry) {
+	s.importers = importers
+}
+
+// Importer returns the importer for an import source
+func (s *ImportService) Importer(source string) (adapters.Importer, error) {
+	return s.importers.Get(source)
+}
+
+// ImportSources returns the supported import sources
+func (s *ImportService) ImportSources() []string {
+	return s.importers.Sources()
+}
+
 // SetPhotoProcessor sets the photo processor (optional)
 func (s *ImportService) SetPhotoProcessor(photoProcessor *PhotoProcessor) {
 	s.photoProcessor = photoProcessor
 }
 
+// SetPropertyIndexer sets the full-text indexer notified for imported properties (optional)
+func (s *ImportService) SetPropertyIndexer(indexer PropertyIndexer) {
+	s.propertyIndexer = indexer
+}
+
+// SetHistoryRepository enables the price/status history of imported properties (optional)
+func (s *ImportService) SetHistoryRepository(historyRepo repositories.PropertyHistoryStore) {
+	s.historyRepo = historyRepo
+}
+
+// reindexProperty refreshes the property's search entry (best effort)
+func (s *ImportService) reindexProperty(ctx context.Context, tenantID, propertyID string) {
+	if s.propertyIndexer == nil {
+		return
+	}
+	if err := s.propertyIndexer.ReindexProperty(ctx, tenantID, propertyID); err != nil {
+		log.Printf("⚠️  Failed to reindex property %s: %v", propertyID, err)
+	}
+}
+
 // GetDB returns the Firestore client
 func (s *ImportService) GetDB() *firestore.Client {
 	return s.db
@@ -53,12 +101,12 @@ type ImportBatchRequest struct {
 	TenantID   string
 	XMLPath    string
 	XLSPath    string // optional
-	Source     string // "union"
+	Source     string // "union", "vrsync"
 	CreatedBy  string // broker_id or "system"
 }
 
 // ImportProperty imports a single property with all related entities
-func (s *ImportService) ImportProperty(ctx context.Context, batch *models.ImportBatch, payload union.PropertyPayload) error {
+func (s *ImportService) ImportProperty(ctx context.Context, batch *models.ImportBatch, payload adapters.PropertyPayload) error {
 	// 1. Check for duplicates
 	dedupResult, err := s.deduplicationService.CheckDuplicate(ctx, &payload.Property)
 	if err != nil {
@@ -96,6 +144,19 @@ func (s *ImportService) ImportProperty(ctx context.Context, batch *models.Import
 			}
 		}
 
+		// Backfill geolocation for properties imported before coordinates were stored
+		if !dedupResult.ExistingProperty.HasCoordinates() && payload.Property.HasCoordinates() {
+			_, err := s.db.Collection("properties").Doc(existingPropertyID).Update(ctx, []firestore.Update{
+				{Path: "latitude", Value: *payload.Property.Latitude},
+				{Path: "longitude", Value: *payload.Property.Longitude},
+				{Path: "geohash", Value: payload.Property.Geohash},
+				{Path: "updated_at", Value: time.Now()},
+			})
+			if err != nil {
+				log.Printf("⚠️  Failed to backfill coordinates for property %s: %v", payload.Property.Reference, err)
+			}
+		}
+
 		// Check if canonical listing exists, create if not
 		log.Printf("🔍 Checking canonical listing for property %s (ref: %s)", existingPropertyID, payload.Property.Reference)
 		listingID, err := s.findCanonicalListing(ctx, existingPropertyID)
@@ -125,6 +186,8 @@ func (s *ImportService) ImportProperty(ctx context.Context, batch *models.Import
 				})
 				if err != nil {
 					log.Printf("⚠️  Failed to update property with canonical_listing_id: %v", err)
+				} else {
+					s.reindexProperty(ctx, batch.TenantID, existingPropertyID)
 				}
 			}
 		}
@@ -135,7 +198,7 @@ func (s *ImportService) ImportProperty(ctx context.Context, batch *models.Import
 
 			if s.photoProcessor != nil {
 				// Process photos asynchronously
-				go s.processPhotosAsync(ctx, batch, listingID, payload)
+				go s.processPhotosAsync(ctx, batch, existingPropertyID, listingID, payload)
 			} else {
 				log.Printf("⚠️  Photo processor not configured - skipping photo update for existing property %s", payload.Property.Reference)
 			}
@@ -181,6 +244,12 @@ func (s *ImportService) ImportProperty(ctx context.Context, batch *models.Import
 
 	batch.TotalPropertiesCreated++
 
+	change := propertyChange{actorType: models.ActorTypeSystem, source: models.PropertyChangeSourceImport}
+	if batch.CreatedBy != "" && batch.CreatedBy != "system" {
+		change.actorType, change.actorID = models.ActorTypeUser, batch.CreatedBy
+	}
+	recordPropertyHistory(ctx, s.historyRepo, initialPropertyHistory(&payload.Property, change))
+
 	s.logActivity(ctx, batch.TenantID, "property_created", map[string]interface{}{
 		"property_id": payload.Property.ID,
 		"reference":   payload.Property.Reference,
@@ -212,6 +281,8 @@ func (s *ImportService) ImportProperty(ctx context.Context, batch *models.Import
 		})
 	}
 
+	s.reindexProperty(ctx, batch.TenantID, payload.Property.ID)
+
 	// 6. Create PropertyBrokerRole (originating_broker)
 	if batch.CreatedBy != "" && batch.CreatedBy != "system" {
 		if err := s.createPropertyBrokerRole(ctx, batch.TenantID, payload.Property.ID, batch.CreatedBy); err != nil {
@@ -223,7 +294,7 @@ func (s *ImportService) ImportProperty(ctx context.Context, batch *models.Import
 	if len(payload.Photos) > 0 {
 		if s.photoProcessor != nil {
 			// Process photos asynchronously (don't block import)
-			go s.processPhotosAsync(ctx, batch, listingID, payload)
+			go s.processPhotosAsync(ctx, batch, payload.Property.ID, listingID, payload)
 		} else {
 			// No photo processor - photos stay as original URLs
 			batch.TotalPhotosProcessed += len(payload.Photos)
@@ -236,7 +307,8 @@ func (s *ImportService) ImportProperty(ctx context.Context, batch *models.Import
 
 // processPhotosAsync processes photos in background and updates listing
 // Uses a worker pool to limit concurrent photo processing
-func (s *ImportService) processPhotosAsync(ctx context.Context, batch *models.ImportBatch, listingID string, payload union.PropertyPayload) {
+// propertyID is the stored property (the existing one when the payload matched a duplicate)
+func (s *ImportService) processPhotosAsync(ctx context.Context, batch *models.ImportBatch, propertyID, listingID string, payload adapters.PropertyPayload) {
 	const maxConcurrentPhotos = 5 // Limit concurrent photo downloads/processing
 
 	semaphore := make(chan struct{}, maxConcurrentPhotos)
@@ -289,6 +361,7 @@ func (s *ImportService) processPhotosAsync(ctx context.Context, batch *models.Im
 			log.Printf("❌ Failed to update listing photos for %s: %v", listingID, err)
 		} else {
 			log.Printf("✅ Updated listing %s with %d processed photos", listingID, len(processedPhotos))
+			s.reindexProperty(ctx, batch.TenantID, propertyID)
 		}
 	}
 }
@@ -346,7 +419,7 @@ func (s *ImportService) findCanonicalListing(ctx context.Context, propertyID str
 }
 
 // createOwner creates or finds existing owner
-func (s *ImportService) createOwner(ctx context.Context, tenantID string, ownerPayload union.OwnerPayload, reference string) (string, bool, error) {
+func (s *ImportService) createOwner(ctx context.Context, tenantID string, ownerPayload adapters.OwnerPayload, reference string) (string, bool, error) {
 	now := time.Now()
 	ownerID := uuid.New().String()
 
@@ -381,12 +454,12 @@ func (s *ImportService) createOwner(ctx context.Context, tenantID string, ownerP
 }
 
 // UpdateOwnerFromXLS updates existing owner with enriched data from XLS (exported for handlers)
-func (s *ImportService) UpdateOwnerFromXLS(ctx context.Context, tenantID, ownerID string, ownerPayload union.OwnerPayload, reference string) error {
+func (s *ImportService) UpdateOwnerFromXLS(ctx context.Context, tenantID, ownerID string, ownerPayload adapters.OwnerPayload, reference string) error {
 	return s.updateOwnerFromXLS(ctx, tenantID, ownerID, ownerPayload, reference)
 }
 
 // updateOwnerFromXLS updates existing owner with enriched data from XLS
-func (s *ImportService) updateOwnerFromXLS(ctx context.Context, tenantID, ownerID string, ownerPayload union.OwnerPayload, reference string) error {
+func (s *ImportService) updateOwnerFromXLS(ctx context.Context, tenantID, ownerID string, ownerPayload adapters.OwnerPayload, reference string) error {
 	if !ownerPayload.EnrichedFromXLS {
 		// No enriched data from XLS, skip update
 		return nil
@@ -0,0 +1,655 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+const (
+	maxLeadNoteLength      = 10000 // Characters of a note body
+	leadTaskReminderBatch  = 200   // Task reminders sent per run
+	defaultLeadTimelineLen = 100
+	maxLeadTimelineLen     = 500
+)
+
+// SetLeadNoteRepository enables the notes of leads
+func (s *LeadService) SetLeadNoteRepository(noteRepo repositories.LeadNoteStore) {
+	s.noteRepo = noteRepo
+}
+
+// SetLeadTaskRepository enables the follow-up tasks of leads
+func (s *LeadService) SetLeadTaskRepository(taskRepo repositories.LeadTaskStore) {
+	s.taskRepo = taskRepo
+}
+
+// SetLeadInteractionRepository enables the interactions logged on leads
+func (s *LeadService) SetLeadInteractionRepository(interactionRepo repositories.LeadInteractionStore) {
+	s.interactionRepo = interactionRepo
+}
+
+// activeLead returns a lead that can receive notes, tasks and interactions
+func (s *LeadService) activeLead(ctx context.Context, tenantID, leadID string) (*models.Lead, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+	if leadID == "" {
+		return nil, fmt.Errorf("lead ID is required")
+	}
+
+	lead, err := s.leadRepo.Get(ctx, tenantID, leadID)
+	if err != nil {
+		return nil, fmt.Errorf("lead not found: %w", err)
+	}
+	if lead.IsAnonymized {
+		return nil, fmt.Errorf("%w: lead data has been anonymized", repositories.ErrInvalidInput)
+	}
+	return lead, nil
+}
+
+// ============================================================================
+// Notes
+// ============================================================================
+
+// AddNote adds a note written by authorID to a lead
+func (s *LeadService) AddNote(ctx context.Context, tenantID, leadID, authorID, body string) (*models.LeadNote, error) {
+	if s.noteRepo == nil {
+		return nil, fmt.Errorf("lead notes not configured")
+	}
+
+	body, err := validateNoteBody(body)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.activeLead(ctx, tenantID, leadID); err != nil {
+		return nil, err
+	}
+
+	note := &models.LeadNote{
+		TenantID: tenantID,
+		LeadID:   leadID,
+		AuthorID: authorID,
+		Body:     body,
+	}
+	if err := s.noteRepo.Create(ctx, note); err != nil {
+		return nil, fmt.Errorf("failed to create lead note: %w", err)
+	}
+
+	_ = s.logActivity(ctx, tenantID, "lead_note_added", models.ActorTypeUser, authorID, map[string]interface{}{
+		"lead_id": leadID,
+		"note_id": note.ID,
+	})
+
+	return note, nil
+}
+
+// UpdateNote replaces the body of a lead note
+func (s *LeadService) UpdateNote(ctx context.Context, tenantID, leadID, noteID, body string) (*models.LeadNote, error) {
+	note, err := s.leadNote(ctx, tenantID, leadID, noteID)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err = validateNoteBody(body)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.noteRepo.Update(ctx, tenantID, noteID, map[string]interface{}{"body": body}); err != nil {
+		return nil, fmt.Errorf("failed to update lead note: %w", err)
+	}
+
+	note.Body = body
+	note.UpdatedAt = time.Now()
+	return note, nil
+}
+
+// DeleteNote deletes a lead note
+func (s *LeadService) DeleteNote(ctx context.Context, tenantID, leadID, noteID, actorID string) error {
+	if _, err := s.leadNote(ctx, tenantID, leadID, noteID); err != nil {
+		return err
+	}
+
+	if err := s.noteRepo.Delete(ctx, tenantID, noteID); err != nil {
+		return fmt.Errorf("failed to delete lead note: %w", err)
+	}
+
+	_ = s.logActivity(ctx, tenantID, "lead_note_deleted", models.ActorTypeUser, actorID, map[string]interface{}{
+		"lead_id": leadID,
+		"note_id": noteID,
+	})
+
+	return nil
+}
+
+// ListNotes lists the notes of a lead, most recent first
+func (s *LeadService) ListNotes(ctx context.Context, tenantID, leadID string, opts repositories.PaginationOptions) ([]*models.LeadNote, error) {
+	if s.noteRepo == nil {
+		return nil, fmt.Errorf("lead notes not configured")
+	}
+	if _, err := s.activeLead(ctx, tenantID, leadID); err != nil {
+		return nil, err
+	}
+
+	notes, err := s.noteRepo.ListByLead(ctx, tenantID, leadID, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list lead notes: %w", err)
+	}
+	return notes, nil
+}
+
+// leadNote returns a note of a lead
+func (s *LeadService) leadNote(ctx context.Context, tenantID, leadID, noteID string) (*models.LeadNote, error) {
+	if s.noteRepo == nil {
+		return nil, fmt.Errorf("lead notes not configured")
+	}
+	if _, err := s.activeLead(ctx, tenantID, leadID); err != nil {
+		return nil, err
+	}
+
+	note, err := s.noteRepo.Get(ctx, tenantID, noteID)
+	if err != nil {
+		return nil, fmt.Errorf("note not found: %w", err)
+	}
+	if note.LeadID != leadID {
+		return nil, fmt.Errorf("note not found: %w", repositories.ErrNotFound)
+	}
+	return note, nil
+}
+
+func validateNoteBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", fmt.Errorf("%w: note body is required", repositories.ErrInvalidInput)
+	}
+	if len([]rune(body)) > maxLeadNoteLength {
+		return "", fmt.Errorf("%w: note body cannot exceed %d characters", repositories.ErrInvalidInput, maxLeadNoteLength)
+	}
+	return body, nil
+}
+
+// deleteLeadNotes removes the notes of an anonymized lead, which may hold
+// personal data in free text
+func (s *LeadService) deleteLeadNotes(ctx context.Context, tenantID, leadID string) {
+	if s.noteRepo == nil {
+		return
+	}
+
+	notes, err := s.noteRepo.ListByLead(ctx, tenantID, leadID, repositories.PaginationOptions{Limit: maxLeadTimelineLen})
+	if err != nil {
+		log.Printf("⚠️  Failed to list notes of anonymized lead %s: %v", leadID, err)
+		return
+	}
+	for _, note := range notes {
+		if err := s.noteRepo.Delete(ctx, tenantID, note.ID); err != nil {
+			log.Printf("⚠️  Failed to delete note %s of anonymized lead %s: %v", note.ID, leadID, err)
+		}
+	}
+}
+
+// ============================================================================
+// Tasks
+// ============================================================================
+
+// CreateTask creates a follow-up task on a lead, assigned by default to the
+// lead's broker. A task with RemindAt sends a reminder to its broker.
+func (s *LeadService) CreateTask(ctx context.Context, task *models.LeadTask) error {
+	if s.taskRepo == nil {
+		return fmt.Errorf("lead tasks not configured")
+	}
+
+	lead, err := s.activeLead(ctx, task.TenantID, task.LeadID)
+	if err != nil {
+		return err
+	}
+
+	task.Title = strings.TrimSpace(task.Title)
+	if task.Title == "" {
+		return fmt.Errorf("%w: title is required", repositories.ErrInvalidInput)
+	}
+	if task.DueAt.IsZero() {
+		return fmt.Errorf("%w: due_at is required", repositories.ErrInvalidInput)
+	}
+	if task.RemindAt != nil && task.RemindAt.After(task.DueAt) {
+		return fmt.Errorf("%w: remind_at cannot be after due_at", repositories.ErrInvalidInput)
+	}
+	if task.AssignedTo == "" {
+		task.AssignedTo = lead.AssignedBrokerID
+	}
+
+	task.Status = models.LeadTaskStatusOpen
+	task.CompletedAt = nil
+	task.ReminderStatus, task.ReminderSentAt, task.ReminderError = "", nil, ""
+	if task.RemindAt != nil {
+		task.ReminderStatus = models.LeadTaskReminderPending
+	}
+
+	if err := s.taskRepo.Create(ctx, task); err != nil {
+		return fmt.Errorf("failed to create lead task: %w", err)
+	}
+
+	_ = s.logActivity(ctx, task.TenantID, "lead_task_created", models.ActorTypeUser, task.CreatedBy, map[string]interface{}{
+		"lead_id":     task.LeadID,
+		"task_id":     task.ID,
+		"assigned_to": task.AssignedTo,
+		"due_at":      task.DueAt,
+	})
+
+	return nil
+}
+
+// LeadTaskUpdate holds the fields of a task to change (nil = unchanged)
+type LeadTaskUpdate struct {
+	Title       *string    `json:"title"`
+	Description *string    `json:"description"`
+	AssignedTo  *string    `json:"assigned_to"`
+	DueAt       *time.Time `json:"due_at"`
+	RemindAt    *time.Time `json:"remind_at"` // Schedules a new reminder
+}
+
+// UpdateTask changes an open task
+func (s *LeadService) UpdateTask(ctx context.Context, tenantID, leadID, taskID string, update LeadTaskUpdate) (*models.LeadTask, error) {
+	task, err := s.leadTask(ctx, tenantID, leadID, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task.Status != models.LeadTaskStatusOpen {
+		return nil, fmt.Errorf("%w: task is %s", repositories.ErrInvalidInput, task.Status)
+	}
+
+	updates := map[string]interface{}{}
+	if update.Title != nil {
+		title := strings.TrimSpace(*update.Title)
+		if title == "" {
+			return nil, fmt.Errorf("%w: title is required", repositories.ErrInvalidInput)
+		}
+		task.Title = title
+		updates["title"] = title
+	}
+	if update.Description != nil {
+		task.Description = *update.Description
+		updates["description"] = task.Description
+	}
+	if update.AssignedTo != nil {
+		task.AssignedTo = *update.AssignedTo
+		updates["assigned_to"] = task.AssignedTo
+	}
+	if update.DueAt != nil {
+		task.DueAt = *update.DueAt
+		updates["due_at"] = task.DueAt
+	}
+	if update.RemindAt != nil {
+		task.RemindAt = update.RemindAt
+		task.ReminderStatus = models.LeadTaskReminderPending
+		task.ReminderSentAt, task.ReminderError = nil, ""
+		updates["remind_at"] = task.RemindAt
+		updates["reminder_status"] = task.ReminderStatus
+		updates["reminder_sent_at"] = task.ReminderSentAt
+		updates["reminder_error"] = task.ReminderError
+	}
+	if task.RemindAt != nil && task.RemindAt.After(task.DueAt) {
+		return nil, fmt.Errorf("%w: remind_at cannot be after due_at", repositories.ErrInvalidInput)
+	}
+	if len(updates) == 0 {
+		return task, nil
+	}
+
+	if err := s.taskRepo.Update(ctx, tenantID, taskID, updates); err != nil {
+		return nil, fmt.Errorf("failed to update lead task: %w", err)
+	}
+	return task, nil
+}
+
+// CloseTask marks an open task done or cancelled, dropping its pending reminder
+func (s *LeadService) CloseTask(ctx context.Context, tenantID, leadID, taskID string, status models.LeadTaskStatus, actorID string) (*models.LeadTask, error) {
+	if status != models.LeadTaskStatusDone && status != models.LeadTaskStatusCancelled {
+		return nil, fmt.Errorf("%w: status must be 'done' or 'cancelled'", repositories.ErrInvalidInput)
+	}
+
+	task, err := s.leadTask(ctx, tenantID, leadID, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task.Status != models.LeadTaskStatusOpen {
+		return nil, fmt.Errorf("%w: task is already %s", repositories.ErrInvalidInput, task.Status)
+	}
+
+	now := time.Now()
+	task.Status = status
+	task.CompletedAt = &now
+	updates := map[string]interface{}{
+		"status":       task.Status,
+		"completed_at": task.CompletedAt,
+	}
+	if task.ReminderStatus == models.LeadTaskReminderPending {
+		task.ReminderStatus = ""
+		updates["reminder_status"] = task.ReminderStatus
+	}
+
+	if err := s.taskRepo.Update(ctx, tenantID, taskID, updates); err != nil {
+		return nil, fmt.Errorf("failed to update lead task: %w", err)
+	}
+
+	_ = s.logActivity(ctx, tenantID, "lead_task_"+string(status), models.ActorTypeUser, actorID, map[string]interface{}{
+		"lead_id": leadID,
+		"task_id": taskID,
+	})
+
+	return task, nil
+}
+
+// ListTasks lists lead tasks with filters, soonest due first
+func (s *LeadService) ListTasks(ctx context.Context, tenantID string, filters *repositories.LeadTaskFilters, opts repositories.PaginationOptions) ([]*models.LeadTask, error) {
+	if s.taskRepo == nil {
+		return nil, fmt.Errorf("lead tasks not configured")
+	}
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+
+	tasks, err := s.taskRepo.List(ctx, tenantID, filters, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list lead tasks: %w", err)
+	}
+	return tasks, nil
+}
+
+// leadTask returns a task of a lead
+func (s *LeadService) leadTask(ctx context.Context, tenantID, leadID, taskID string) (*models.LeadTask, error) {
+	if s.taskRepo == nil {
+		return nil, fmt.Errorf("lead tasks not configured")
+	}
+	if _, err := s.activeLead(ctx, tenantID, leadID); err != nil {
+		return nil, err
+	}
+
+	task, err := s.taskRepo.Get(ctx, tenantID, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("task not found: %w", err)
+	}
+	if task.LeadID != leadID {
+		return nil, fmt.Errorf("task not found: %w", repositories.ErrNotFound)
+	}
+	return task, nil
+}
+
+// LeadTaskMessageData is the data available to the lead_task_reminder template
+type LeadTaskMessageData struct {
+	BrokerName  string
+	TenantName  string
+	LeadName    string
+	TaskTitle   string
+	Description string
+	DueAt       string // dd/mm/yyyy hh:mm
+}
+
+// ProcessLeadTaskRemindersResponse summarizes a run of the task reminders
+type ProcessLeadTaskRemindersResponse struct {
+	Due    int `json:"due"`
+	Sent   int `json:"sent"`
+	Failed int `json:"failed"` // Recorded on the task
+}
+
+// ProcessTaskReminders sends the due reminders of open lead tasks to their brokers
+func (s *LeadService) ProcessTaskReminders(ctx context.Context, tenantID string) (*ProcessLeadTaskRemindersResponse, error) {
+	if s.taskRepo == nil {
+		return nil, fmt.Errorf("lead tasks not configured")
+	}
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+
+	tasks, err := s.taskRepo.ListDueReminders(ctx, tenantID, time.Now(), leadTaskReminderBatch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due task reminders: %w", err)
+	}
+
+	tenant, err := s.tenantRepo.Get(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("tenant not found: %w", err)
+	}
+
+	response := &ProcessLeadTaskRemindersResponse{Due: len(tasks)}
+	for _, task := range tasks {
+		updates := map[string]interface{}{}
+		if err := s.sendTaskReminder(ctx, tenant, task); err != nil {
+			log.Printf("⚠️  Failed to send reminder of lead task %s: %v", task.ID, err)
+			response.Failed++
+			updates["reminder_status"] = models.LeadTaskReminderFailed
+			updates["reminder_error"] = err.Error()
+		} else {
+			response.Sent++
+			updates["reminder_status"] = models.LeadTaskReminderSent
+			updates["reminder_sent_at"] = time.Now()
+			updates["reminder_error"] = ""
+		}
+
+		if err := s.taskRepo.Update(ctx, tenantID, task.ID, updates); err != nil {
+			log.Printf("❌ Failed to update reminder of lead task %s: %v", task.ID, err)
+		}
+	}
+
+	return response, nil
+}
+
+// sendTaskReminder sends the reminder of a task to its broker
+func (s *LeadService) sendTaskReminder(ctx context.Context, tenant *models.Tenant, task *models.LeadTask) error {
+	if s.notifier == nil {
+		return fmt.Errorf("no notifier configured")
+	}
+	if task.AssignedTo == "" {
+		return fmt.Errorf("task has no broker")
+	}
+	if s.brokerRepo == nil {
+		return fmt.Errorf("broker repository not configured")
+	}
+
+	broker, err := s.brokerRepo.Get(ctx, task.TenantID, task.AssignedTo)
+	if err != nil {
+		return fmt.Errorf("broker not found: %w", err)
+	}
+
+	data := LeadTaskMessageData{
+		BrokerName:  firstName(broker.Name),
+		TenantName:  tenant.Name,
+		TaskTitle:   task.Title,
+		Description: task.Description,
+		DueAt:       task.DueAt.Format("02/01/2006 15:04"),
+	}
+	if lead, err := s.leadRepo.Get(ctx, task.TenantID, task.LeadID); err == nil {
+		data.LeadName = lead.Name
+	}
+
+	channel, _, _, err := sendNotification(ctx, s.notifier, tenant, models.NotificationTemplateLeadTaskReminder,
+		recipient{Name: broker.Name, Phone: broker.Phone, Email: broker.Email}, data,
+		[]string{data.BrokerName, data.TaskTitle, data.LeadName})
+	if err != nil {
+		return err
+	}
+	if channel == "" {
+		return fmt.Errorf("no channel available to reach the broker")
+	}
+	return nil
+}
+
+// ============================================================================
+// Interactions
+// ============================================================================
+
+// LogInteraction logs a call, visit or message with a lead. An outbound
+// interaction is a response to the lead and settles its first-response SLA.
+func (s *LeadService) LogInteraction(ctx context.Context, interaction *models.LeadInteraction) error {
+	if s.interactionRepo == nil {
+		return fmt.Errorf("lead interactions not configured")
+	}
+
+	lead, err := s.activeLead(ctx, interaction.TenantID, interaction.LeadID)
+	if err != nil {
+		return err
+	}
+
+	if interaction.Direction == "" {
+		interaction.Direction = models.LeadInteractionOutbound
+	}
+	if err := interaction.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", repositories.ErrInvalidInput, err)
+	}
+
+	now := time.Now()
+	if interaction.OccurredAt.IsZero() {
+		interaction.OccurredAt = now
+	}
+	if interaction.OccurredAt.After(now.Add(5 * time.Minute)) {
+		return fmt.Errorf("%w: occurred_at cannot be in the future", repositories.ErrInvalidInput)
+	}
+	if interaction.OccurredAt.Before(lead.CreatedAt) {
+		interaction.OccurredAt = lead.CreatedAt
+	}
+
+	if err := s.interactionRepo.Create(ctx, interaction); err != nil {
+		return fmt.Errorf("failed to create lead interaction: %w", err)
+	}
+
+	if interaction.Direction == models.LeadInteractionOutbound {
+		if updates := recordFirstResponse(lead, interaction.OccurredAt); updates != nil {
+			if err := s.leadRepo.Update(ctx, lead.TenantID, lead.ID, updates); err != nil {
+				log.Printf("⚠️  Failed to record first response of lead %s: %v", lead.ID, err)
+			}
+		}
+	}
+
+	_ = s.logActivity(ctx, interaction.TenantID, "lead_interaction_logged", models.ActorTypeUser, interaction.BrokerID, map[string]interface{}{
+		"lead_id":        interaction.LeadID,
+		"interaction_id": interaction.ID,
+		"type":           interaction.Type,
+		"direction":      interaction.Direction,
+	})
+
+	return nil
+}
+
+// ListInteractions lists the interactions of a lead, most recent first
+func (s *LeadService) ListInteractions(ctx context.Context, tenantID, leadID string, opts repositories.PaginationOptions) ([]*models.LeadInteraction, error) {
+	if s.interactionRepo == nil {
+		return nil, fmt.Errorf("lead interactions not configured")
+	}
+	if _, err := s.activeLead(ctx, tenantID, leadID); err != nil {
+		return nil, err
+	}
+
+	interactions, err := s.interactionRepo.ListByLead(ctx, tenantID, leadID, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list lead interactions: %w", err)
+	}
+	return interactions, nil
+}
+
+// ============================================================================
+// Timeline
+// ============================================================================
+
+// LeadTimelineKind tells the source of a timeline entry
+type LeadTimelineKind string
+
+const (
+	LeadTimelineNote        LeadTimelineKind = "note"
+	LeadTimelineTask        LeadTimelineKind = "task"
+	LeadTimelineInteraction LeadTimelineKind = "interaction"
+	LeadTimelineEvent       LeadTimelineKind = "event" // Activity log
+)
+
+// LeadTimelineEntry is an item of the lead timeline
+type LeadTimelineEntry struct {
+	Kind    LeadTimelineKind `json:"kind"`
+	Type    string           `json:"type"` // Interaction type, task change (created, done, cancelled) or event type
+	At      time.Time        `json:"at"`
+	ActorID string           `json:"actor_id,omitempty"`
+	Data    interface{}      `json:"data"` // The note, task, interaction or activity log
+}
+
+// timelineFollowUpEvents are the activity log events already shown through
+// the note, task and interaction entries
+var timelineFollowUpEvents = map[string]bool{
+	"lead_note_added":         true,
+	"lead_task_created":       true,
+	"lead_task_done":          true,
+	"lead_task_cancelled":     true,
+	"lead_interaction_logged": true,
+}
+
+// GetLeadTimeline returns the notes, tasks, interactions and activity log
+// events of a lead, most recent first
+func (s *LeadService) GetLeadTimeline(ctx context.Context, tenantID, leadID string, limit int) ([]*LeadTimelineEntry, error) {
+	lead, err := s.activeLead(ctx, tenantID, leadID)
+	if err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = defaultLeadTimelineLen
+	}
+	if limit > maxLeadTimelineLen {
+		limit = maxLeadTimelineLen
+	}
+
+	var entries []*LeadTimelineEntry
+
+	if s.noteRepo != nil {
+		notes, err := s.noteRepo.ListByLead(ctx, tenantID, leadID, repositories.PaginationOptions{Limit: limit, OrderBy: "created_at", Direction: firestore.Desc})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list lead notes: %w", err)
+		}
+		for _, note := range notes {
+			entries = append(entries, &LeadTimelineEntry{Kind: LeadTimelineNote, Type: "note", At: note.CreatedAt, ActorID: note.AuthorID, Data: note})
+		}
+	}
+
+	if s.taskRepo != nil {
+		tasks, err := s.taskRepo.List(ctx, tenantID, &repositories.LeadTaskFilters{LeadID: leadID}, repositories.PaginationOptions{Limit: limit, OrderBy: "created_at", Direction: firestore.Desc})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list lead tasks: %w", err)
+		}
+		for _, task := range tasks {
+			entries = append(entries, &LeadTimelineEntry{Kind: LeadTimelineTask, Type: "created", At: task.CreatedAt, ActorID: task.CreatedBy, Data: task})
+			if task.CompletedAt != nil {
+				entries = append(entries, &LeadTimelineEntry{Kind: LeadTimelineTask, Type: string(task.Status), At: *task.CompletedAt, ActorID: task.AssignedTo, Data: task})
+			}
+		}
+	}
+
+	if s.interactionRepo != nil {
+		interactions, err := s.interactionRepo.ListByLead(ctx, tenantID, leadID, repositories.PaginationOptions{Limit: limit})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list lead interactions: %w", err)
+		}
+		for _, interaction := range interactions {
+			entries = append(entries, &LeadTimelineEntry{Kind: LeadTimelineInteraction, Type: string(interaction.Type), At: interaction.OccurredAt, ActorID: interaction.BrokerID, Data: interaction})
+		}
+	}
+
+	logs, err := s.activityLogRepo.ListLeadLogs(ctx, tenantID, lead.ID, repositories.PaginationOptions{Limit: limit, OrderBy: "timestamp", Direction: firestore.Desc})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list lead activity: %w", err)
+	}
+	for _, activity := range logs {
+		if timelineFollowUpEvents[activity.EventType] {
+			continue
+		}
+		entries = append(entries, &LeadTimelineEntry{Kind: LeadTimelineEvent, Type: activity.EventType, At: activity.Timestamp, ActorID: activity.ActorID, Data: activity})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].At.After(entries[j].At) })
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
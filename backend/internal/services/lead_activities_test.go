@@ -0,0 +1,208 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/notify"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories/memory"
+)
+
+// newActivityTestService returns the routing test service (leads of moema go
+// to broker-3, reachable on WhatsApp) with notes, tasks and interactions and
+// the fake WhatsApp notifier
+func newActivityTestService(t *testing.T) (*LeadService, *notify.FakeNotifier) {
+	t.Helper()
+
+	service := newRoutingTestService(t, nil)
+	require.NoError(t, service.brokerRepo.Update(context.Background(), "tenant-1", "broker-3", map[string]interface{}{
+		"name":  "Paulo Souza",
+		"phone": "(11) 97777-1234",
+	}))
+	service.SetLeadNoteRepository(memory.NewLeadNoteRepository())
+	service.SetLeadTaskRepository(memory.NewLeadTaskRepository())
+	service.SetLeadInteractionRepository(memory.NewLeadInteractionRepository())
+
+	whatsapp := notify.NewFakeNotifier(models.NotificationChannelWhatsApp)
+	service.SetNotifier(notify.NewDispatcher(notify.RetryPolicy{MaxAttempts: 1}, whatsapp))
+	return service, whatsapp
+}
+
+func TestLeadNotes_AddUpdateDelete(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newActivityTestService(t)
+	lead := createRoutingTestLead(t, service, "moema")
+
+	_, err := service.AddNote(ctx, "tenant-1", lead.ID, "broker-3", "   ")
+	assert.True(t, errors.Is(err, repositories.ErrInvalidInput))
+
+	note, err := service.AddNote(ctx, "tenant-1", lead.ID, "broker-3", " Prefere contato à tarde ")
+	require.NoError(t, err)
+	assert.Equal(t, "Prefere contato à tarde", note.Body)
+
+	updated, err := service.UpdateNote(ctx, "tenant-1", lead.ID, note.ID, "Prefere contato pela manhã")
+	require.NoError(t, err)
+	assert.Equal(t, "Prefere contato pela manhã", updated.Body)
+
+	// Notes belong to their lead
+	other := createRoutingTestLead(t, service, "pinheiros")
+	_, err = service.UpdateNote(ctx, "tenant-1", other.ID, note.ID, "Outro lead")
+	assert.True(t, errors.Is(err, repositories.ErrNotFound))
+
+	notes, err := service.ListNotes(ctx, "tenant-1", lead.ID, repositories.PaginationOptions{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, notes, 1)
+	assert.Equal(t, "Prefere contato pela manhã", notes[0].Body)
+
+	require.NoError(t, service.DeleteNote(ctx, "tenant-1", lead.ID, note.ID, "broker-3"))
+	notes, err = service.ListNotes(ctx, "tenant-1", lead.ID, repositories.PaginationOptions{Limit: 10})
+	require.NoError(t, err)
+	assert.Empty(t, notes)
+}
+
+func TestAnonymizeLead_DeletesNotes(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newActivityTestService(t)
+	lead := createRoutingTestLead(t, service, "moema")
+
+	_, err := service.AddNote(ctx, "tenant-1", lead.ID, "broker-3", "Ana mora com os pais")
+	require.NoError(t, err)
+	require.NoError(t, service.AnonymizeLead(ctx, "tenant-1", lead.ID, "user_request"))
+
+	notes, err := service.noteRepo.ListByLead(ctx, "tenant-1", lead.ID, repositories.PaginationOptions{Limit: 10})
+	require.NoError(t, err)
+	assert.Empty(t, notes)
+
+	// Anonymized leads take no new activity
+	_, err = service.AddNote(ctx, "tenant-1", lead.ID, "broker-3", "Nova nota")
+	assert.True(t, errors.Is(err, repositories.ErrInvalidInput))
+}
+
+func TestProcessTaskReminders_RemindsAssignedBroker(t *testing.T) {
+	ctx := context.Background()
+	service, whatsapp := newActivityTestService(t)
+	lead := createRoutingTestLead(t, service, "moema")
+
+	remindAt := time.Now().Add(-time.Minute)
+	task := &models.LeadTask{
+		TenantID: "tenant-1",
+		LeadID:   lead.ID,
+		Title:    "Enviar proposta",
+		DueAt:    time.Now().Add(time.Hour),
+		RemindAt: &remindAt,
+	}
+	require.NoError(t, service.CreateTask(ctx, task))
+	assert.Equal(t, "broker-3", task.AssignedTo) // Broker of the lead
+	assert.Equal(t, models.LeadTaskReminderPending, task.ReminderStatus)
+
+	// A closed task is not reminded
+	cancelled := &models.LeadTask{TenantID: "tenant-1", LeadID: lead.ID, Title: "Ligar", DueAt: time.Now().Add(time.Hour), RemindAt: &remindAt}
+	require.NoError(t, service.CreateTask(ctx, cancelled))
+	_, err := service.CloseTask(ctx, "tenant-1", lead.ID, cancelled.ID, models.LeadTaskStatusCancelled, "broker-3")
+	require.NoError(t, err)
+
+	response, err := service.ProcessTaskReminders(ctx, "tenant-1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, response.Due)
+	assert.Equal(t, 1, response.Sent)
+
+	sent := whatsapp.Sent()
+	require.Len(t, sent, 1)
+	assert.Equal(t, "(11) 97777-1234", sent[0].To)
+	assert.Contains(t, sent[0].Body, "Enviar proposta")
+	assert.Contains(t, sent[0].Body, "Ana Costa")
+
+	reminded, err := service.taskRepo.Get(ctx, "tenant-1", task.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.LeadTaskReminderSent, reminded.ReminderStatus)
+	require.NotNil(t, reminded.ReminderSentAt)
+
+	// Reminders are sent once
+	response, err = service.ProcessTaskReminders(ctx, "tenant-1")
+	require.NoError(t, err)
+	assert.Zero(t, response.Due)
+}
+
+func TestCreateTask_Validates(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newActivityTestService(t)
+	lead := createRoutingTestLead(t, service, "moema")
+
+	dueAt := time.Now().Add(time.Hour)
+	late := dueAt.Add(time.Minute)
+	for _, task := range []*models.LeadTask{
+		{TenantID: "tenant-1", LeadID: lead.ID, DueAt: dueAt},
+		{TenantID: "tenant-1", LeadID: lead.ID, Title: "Ligar"},
+		{TenantID: "tenant-1", LeadID: lead.ID, Title: "Ligar", DueAt: dueAt, RemindAt: &late},
+	} {
+		assert.True(t, errors.Is(service.CreateTask(ctx, task), repositories.ErrInvalidInput))
+	}
+}
+
+func TestLogInteraction_SettlesFirstResponse(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newActivityTestService(t)
+	lead := createRoutingTestLead(t, service, "moema")
+
+	// Messages from the lead are not a response
+	require.NoError(t, service.LogInteraction(ctx, &models.LeadInteraction{TenantID: "tenant-1", LeadID: lead.ID, Type: models.LeadInteractionWhatsApp, Direction: models.LeadInteractionInbound}))
+	pending, err := service.GetLead(ctx, "tenant-1", lead.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.LeadSLAStatusPending, pending.SLAStatus)
+
+	call := &models.LeadInteraction{TenantID: "tenant-1", LeadID: lead.ID, Type: models.LeadInteractionCall, BrokerID: "broker-3", DurationMinutes: 4}
+	require.NoError(t, service.LogInteraction(ctx, call))
+	assert.Equal(t, models.LeadInteractionOutbound, call.Direction)
+
+	answered, err := service.GetLead(ctx, "tenant-1", lead.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.LeadSLAStatusMet, answered.SLAStatus)
+	require.NotNil(t, answered.FirstResponseAt)
+
+	future := &models.LeadInteraction{TenantID: "tenant-1", LeadID: lead.ID, Type: models.LeadInteractionVisit, OccurredAt: time.Now().Add(time.Hour)}
+	assert.True(t, errors.Is(service.LogInteraction(ctx, future), repositories.ErrInvalidInput))
+}
+
+func TestGetLeadTimeline_MergesActivityMostRecentFirst(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newActivityTestService(t)
+	lead := createRoutingTestLead(t, service, "moema")
+
+	_, err := service.AddNote(ctx, "tenant-1", lead.ID, "broker-3", "Procura 2 dormitórios")
+	require.NoError(t, err)
+	task := &models.LeadTask{TenantID: "tenant-1", LeadID: lead.ID, Title: "Agendar visita", DueAt: time.Now().Add(24 * time.Hour)}
+	require.NoError(t, service.CreateTask(ctx, task))
+	require.NoError(t, service.LogInteraction(ctx, &models.LeadInteraction{TenantID: "tenant-1", LeadID: lead.ID, Type: models.LeadInteractionCall, BrokerID: "broker-3"}))
+	_, err = service.CloseTask(ctx, "tenant-1", lead.ID, task.ID, models.LeadTaskStatusDone, "broker-3")
+	require.NoError(t, err)
+
+	timeline, err := service.GetLeadTimeline(ctx, "tenant-1", lead.ID, 0)
+	require.NoError(t, err)
+
+	kinds := map[LeadTimelineKind]int{}
+	for i, entry := range timeline {
+		kinds[entry.Kind]++
+		if i > 0 {
+			assert.False(t, entry.At.After(timeline[i-1].At), "timeline is not sorted most recent first")
+		}
+		if entry.Kind == LeadTimelineEvent {
+			assert.False(t, timelineFollowUpEvents[entry.Type], "follow-up event %s is duplicated", entry.Type)
+		}
+	}
+	assert.Equal(t, 1, kinds[LeadTimelineNote])
+	assert.Equal(t, 2, kinds[LeadTimelineTask]) // Created and done
+	assert.Equal(t, 1, kinds[LeadTimelineInteraction])
+	assert.Equal(t, LeadTimelineTask, timeline[0].Kind)
+	assert.Equal(t, "done", timeline[0].Type)
+
+	limited, err := service.GetLeadTimeline(ctx, "tenant-1", lead.ID, 2)
+	require.NoError(t, err)
+	assert.Len(t, limited, 2)
+}
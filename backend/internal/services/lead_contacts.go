@@ -0,0 +1,358 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"cloud.google.com/go/firestore"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/utils"
+)
+
+// contactInquiryLimit caps the leads read to rebuild a contact's history
+const contactInquiryLimit = 500
+
+// SetContactRepository enables contact identity: leads from the same email
+// or phone attach to one contact. Without it every lead stands alone.
+func (s *LeadService) SetContactRepository(contactRepo repositories.ContactStore) {
+	s.contactRepo = contactRepo
+}
+
+// ContactDetails is a contact with its inquiry history. Each inquiry (lead)
+// keeps its own LGPD consent record.
+type ContactDetails struct {
+	*models.Contact
+	Inquiries []*models.Lead `json:"inquiries"`
+}
+
+// leadIdentity returns the normalized email and E.164 phone of a lead
+func leadIdentity(lead *models.Lead) (email, phone string) {
+	if lead.IsAnonymized {
+		return "", ""
+	}
+	if lead.Email != "" {
+		email = utils.NormalizeEmail(lead.Email)
+	}
+	// WhatsApp leads may carry a placeholder until the contact is known
+	if lead.Phone != "" && lead.Phone != "WhatsApp" {
+		phone = utils.NormalizePhoneE164(lead.Phone, "55")
+	}
+	return email, phone
+}
+
+// attachContact sets lead.ContactID to the contact with the lead's email or
+// phone (email first), creating the contact when there is none. Leads
+// without contact details stay unattached. Failures are logged: a lead is
+// never lost because of its contact.
+func (s *LeadService) attachContact(ctx context.Context, lead *models.Lead) {
+	if s.contactRepo == nil {
+		return
+	}
+
+	email, phone := leadIdentity(lead)
+	if email == "" && phone == "" {
+		return
+	}
+
+	contact, err := s.findContact(ctx, lead.TenantID, email, phone)
+	if errors.Is(err, repositories.ErrNotFound) {
+		contact = &models.Contact{TenantID: lead.TenantID, Name: lead.Name}
+		err = s.contactRepo.Create(ctx, contact)
+	}
+	if err != nil {
+		log.Printf("⚠️  Failed to attach contact to lead of property %s: %v", lead.PropertyID, err)
+		return
+	}
+
+	lead.ContactID = contact.ID
+}
+
+// syncLeadContact updates the contact of a lead whose email or phone changed,
+// attaching leads that had no contact details before
+func (s *LeadService) syncLeadContact(ctx context.Context, tenantID, id string) {
+	if s.contactRepo == nil {
+		return
+	}
+
+	lead, err := s.leadRepo.Get(ctx, tenantID, id)
+	if err != nil {
+		log.Printf("⚠️  Failed to sync contact of lead %s: %v", id, err)
+		return
+	}
+
+	if lead.ContactID == "" {
+		s.attachContact(ctx, lead)
+		if lead.ContactID == "" {
+			return
+		}
+		if err := s.leadRepo.Update(ctx, tenantID, id, map[string]interface{}{"contact_id": lead.ContactID}); err != nil {
+			log.Printf("⚠️  Failed to attach contact to lead %s: %v", id, err)
+			return
+		}
+	}
+
+	s.refreshContact(ctx, tenantID, lead.ContactID)
+}
+
+// findContact returns the unmerged contact with the email or, failing that,
+// the phone
+func (s *LeadService) findContact(ctx context.Context, tenantID, email, phone string) (*models.Contact, error) {
+	if email != "" {
+		contact, err := s.contactRepo.FindByEmail(ctx, tenantID, email)
+		if !errors.Is(err, repositories.ErrNotFound) {
+			return contact, err
+		}
+	}
+	if phone != "" {
+		return s.contactRepo.FindByPhone(ctx, tenantID, phone)
+	}
+	return nil, repositories.ErrNotFound
+}
+
+// refreshContact rebuilds the identities and inquiry history of a contact
+// from its leads, logging failures
+func (s *LeadService) refreshContact(ctx context.Context, tenantID, contactID string) {
+	if s.contactRepo == nil || contactID == "" {
+		return
+	}
+
+	contact, err := s.contactRepo.Get(ctx, tenantID, contactID)
+	if err == nil {
+		err = s.rebuildContact(ctx, contact)
+	}
+	if err != nil {
+		log.Printf("⚠️  Failed to refresh contact %s: %v", contactID, err)
+	}
+}
+
+// rebuildContact recomputes the identities and inquiry history of contact
+// from its leads and saves it
+func (s *LeadService) rebuildContact(ctx context.Context, contact *models.Contact) error {
+	leads, err := s.contactLeads(ctx, contact.TenantID, contact.ID)
+	if err != nil {
+		return err
+	}
+
+	contact.Emails = []string{}
+	contact.Phones = []string{}
+	contact.LeadIDs = make([]string, 0, len(leads))
+	contact.PropertyIDs = []string{}
+	contact.InquiryCount = len(leads)
+	contact.FirstInquiryAt = nil
+	contact.LastInquiryAt = nil
+
+	for _, lead := range leads {
+		contact.LeadIDs = append(contact.LeadIDs, lead.ID)
+		if !slices.Contains(contact.PropertyIDs, lead.PropertyID) {
+			contact.PropertyIDs = append(contact.PropertyIDs, lead.PropertyID)
+		}
+
+		email, phone := leadIdentity(lead)
+		if email != "" && !slices.Contains(contact.Emails, email) {
+			contact.Emails = append(contact.Emails, email)
+		}
+		if phone != "" && !slices.Contains(contact.Phones, phone) {
+			contact.Phones = append(contact.Phones, phone)
+		}
+		if lead.Name != "" && !lead.IsAnonymized {
+			contact.Name = lead.Name
+		}
+
+		createdAt := lead.CreatedAt
+		if contact.FirstInquiryAt == nil {
+			contact.FirstInquiryAt = &createdAt
+		}
+		contact.LastInquiryAt = &createdAt
+	}
+
+	return s.contactRepo.Update(ctx, contact)
+}
+
+// contactLeads returns the leads of a contact, oldest first
+func (s *LeadService) contactLeads(ctx context.Context, tenantID, contactID string) ([]*models.Lead, error) {
+	leads, err := s.leadRepo.List(ctx, tenantID, &repositories.LeadFilters{ContactID: contactID}, repositories.PaginationOptions{
+		Limit:     contactInquiryLimit,
+		OrderBy:   "created_at",
+		Direction: firestore.Asc,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list leads of contact: %w", err)
+	}
+	return leads, nil
+}
+
+// GetContact retrieves a contact with its inquiry history
+func (s *LeadService) GetContact(ctx context.Context, tenantID, id string) (*ContactDetails, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+	if id == "" {
+		return nil, fmt.Errorf("contact ID is required")
+	}
+	if s.contactRepo == nil {
+		return nil, fmt.Errorf("contacts are not enabled")
+	}
+
+	contact, err := s.contactRepo.Get(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	leads, err := s.contactLeads(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ContactDetails{Contact: contact, Inquiries: leads}, nil
+}
+
+// ListContacts lists the contacts of a tenant
+func (s *LeadService) ListContacts(ctx context.Context, tenantID string, opts repositories.PaginationOptions) ([]*models.Contact, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+	if s.contactRepo == nil {
+		return nil, fmt.Errorf("contacts are not enabled")
+	}
+
+	contacts, err := s.contactRepo.List(ctx, tenantID, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list contacts: %w", err)
+	}
+
+	return contacts, nil
+}
+
+// MergeContacts moves the leads of the source contact to the target, for
+// the same person reaching out with different email and phone. Leads are
+// only re-pointed: each keeps its own LGPD consent record, and the merge is
+// recorded on the target so UnmergeContacts can undo it.
+func (s *LeadService) MergeContacts(ctx context.Context, tenantID, targetID, sourceID, actorID string) (*models.Contact, error) {
+	target, source, err := s.getContactPair(ctx, tenantID, targetID, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	if target.IsMerged() {
+		return nil, fmt.Errorf("%w: contact %s was merged into %s", repositories.ErrInvalidInput, target.ID, target.MergedIntoID)
+	}
+	if source.IsMerged() {
+		return nil, fmt.Errorf("%w: contact %s was already merged into %s", repositories.ErrInvalidInput, source.ID, source.MergedIntoID)
+	}
+
+	leads, err := s.contactLeads(ctx, tenantID, source.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	merge := models.ContactMerge{
+		SourceContactID: source.ID,
+		LeadIDs:         make([]string, 0, len(leads)),
+		MergedBy:        actorID,
+		MergedAt:        time.Now(),
+	}
+	for _, lead := range leads {
+		if err := s.leadRepo.Update(ctx, tenantID, lead.ID, map[string]interface{}{"contact_id": target.ID}); err != nil {
+			return nil, fmt.Errorf("failed to move lead %s: %w", lead.ID, err)
+		}
+		merge.LeadIDs = append(merge.LeadIDs, lead.ID)
+	}
+
+	source.MergedIntoID = target.ID
+	if err := s.rebuildContact(ctx, source); err != nil {
+		return nil, fmt.Errorf("failed to update merged contact: %w", err)
+	}
+
+	target.Merges = append(target.Merges, merge)
+	if err := s.rebuildContact(ctx, target); err != nil {
+		return nil, fmt.Errorf("failed to update contact: %w", err)
+	}
+
+	_ = s.logActivity(ctx, tenantID, "contact_merged", models.ActorTypeUser, actorID, map[string]interface{}{
+		"contact_id":        target.ID,
+		"source_contact_id": source.ID,
+		"lead_ids":          merge.LeadIDs,
+	})
+
+	return target, nil
+}
+
+// UnmergeContacts undoes the merge of the source contact into the target,
+// moving the leads that came from the source back to it
+func (s *LeadService) UnmergeContacts(ctx context.Context, tenantID, targetID, sourceID, actorID string) (*models.Contact, error) {
+	target, source, err := s.getContactPair(ctx, tenantID, targetID, sourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	index := slices.IndexFunc(target.Merges, func(m models.ContactMerge) bool {
+		return m.SourceContactID == source.ID
+	})
+	if index < 0 || source.MergedIntoID != target.ID {
+		return nil, fmt.Errorf("%w: contact %s is not merged into %s", repositories.ErrInvalidInput, source.ID, target.ID)
+	}
+	merge := target.Merges[index]
+
+	for _, leadID := range merge.LeadIDs {
+		lead, err := s.leadRepo.Get(ctx, tenantID, leadID)
+		if errors.Is(err, repositories.ErrNotFound) || (err == nil && lead.ContactID != target.ID) {
+			continue // Deleted or moved since the merge
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get lead %s: %w", leadID, err)
+		}
+		if err := s.leadRepo.Update(ctx, tenantID, leadID, map[string]interface{}{"contact_id": source.ID}); err != nil {
+			return nil, fmt.Errorf("failed to move lead %s: %w", leadID, err)
+		}
+	}
+
+	source.MergedIntoID = ""
+	if err := s.rebuildContact(ctx, source); err != nil {
+		return nil, fmt.Errorf("failed to update unmerged contact: %w", err)
+	}
+
+	target.Merges = slices.Delete(slices.Clone(target.Merges), index, index+1)
+	if err := s.rebuildContact(ctx, target); err != nil {
+		return nil, fmt.Errorf("failed to update contact: %w", err)
+	}
+
+	_ = s.logActivity(ctx, tenantID, "contact_unmerged", models.ActorTypeUser, actorID, map[string]interface{}{
+		"contact_id":        target.ID,
+		"source_contact_id": source.ID,
+		"lead_ids":          merge.LeadIDs,
+	})
+
+	return source, nil
+}
+
+// getContactPair loads the target and source contacts of a merge
+func (s *LeadService) getContactPair(ctx context.Context, tenantID, targetID, sourceID string) (*models.Contact, *models.Contact, error) {
+	if tenantID == "" {
+		return nil, nil, fmt.Errorf("tenant_id is required")
+	}
+	if s.contactRepo == nil {
+		return nil, nil, fmt.Errorf("contacts are not enabled")
+	}
+	if targetID == "" || sourceID == "" {
+		return nil, nil, fmt.Errorf("%w: contact IDs are required", repositories.ErrInvalidInput)
+	}
+	if targetID == sourceID {
+		return nil, nil, fmt.Errorf("%w: cannot merge a contact into itself", repositories.ErrInvalidInput)
+	}
+
+	target, err := s.contactRepo.Get(ctx, tenantID, targetID)
+	if err != nil {
+		return nil, nil, err
+	}
+	source, err := s.contactRepo.Get(ctx, tenantID, sourceID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return target, source, nil
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories/memory"
+)
+
+// newContactTestService returns a lead service with contacts enabled and two
+// properties (property-1 and property-2) of tenant-1
+func newContactTestService(t *testing.T) *LeadService {
+	t.Helper()
+	ctx := context.Background()
+
+	tenantRepo := memory.NewTenantRepository()
+	require.NoError(t, tenantRepo.Create(ctx, &models.Tenant{ID: "tenant-1", Name: "Imobiliária Teste", IsActive: true}))
+
+	propertyRepo := memory.NewPropertyRepository()
+	for _, id := range []string{"property-1", "property-2"} {
+		require.NoError(t, propertyRepo.Create(ctx, &models.Property{ID: id, TenantID: "tenant-1"}))
+	}
+
+	service := NewLeadService(memory.NewLeadRepository(), propertyRepo, memory.NewPropertyBrokerRoleRepository(), tenantRepo, memory.NewActivityLogRepository())
+	service.SetContactRepository(memory.NewContactRepository())
+	return service
+}
+
+func createContactTestLead(t *testing.T, service *LeadService, propertyID, email, phone string) *models.Lead {
+	t.Helper()
+	lead := &models.Lead{
+		TenantID:     "tenant-1",
+		PropertyID:   propertyID,
+		Name:         "Ana Costa",
+		Email:        email,
+		Phone:        phone,
+		Channel:      models.LeadChannelForm,
+		ConsentGiven: true,
+		ConsentIP:    "203.0.113." + propertyID[len(propertyID)-1:],
+	}
+	require.NoError(t, service.CreateLead(context.Background(), lead))
+	return lead
+}
+
+func TestCreateLead_AttachesRepeatInquiriesToContact(t *testing.T) {
+	ctx := context.Background()
+	service := newContactTestService(t)
+
+	first := createContactTestLead(t, service, "property-1", "Ana@Example.com", "")
+	second := createContactTestLead(t, service, "property-2", "ana@example.com", "(11) 98765-4321")
+	// Same phone in another format, no email
+	third := createContactTestLead(t, service, "property-1", "", "11987654321")
+	other := createContactTestLead(t, service, "property-1", "bruno@example.com", "")
+
+	require.NotEmpty(t, first.ContactID)
+	assert.Equal(t, first.ContactID, second.ContactID)
+	assert.Equal(t, first.ContactID, third.ContactID)
+	assert.NotEqual(t, first.ContactID, other.ContactID)
+
+	contact, err := service.GetContact(ctx, "tenant-1", first.ContactID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ana@example.com"}, contact.Emails)
+	assert.Equal(t, []string{"+5511987654321"}, contact.Phones)
+	assert.Equal(t, 3, contact.InquiryCount)
+	assert.ElementsMatch(t, []string{"property-1", "property-2"}, contact.PropertyIDs)
+	assert.Len(t, contact.Inquiries, 3)
+	assert.NotNil(t, contact.LastInquiryAt)
+}
+
+func TestCreateLead_WithoutContactDetails(t *testing.T) {
+	service := newContactTestService(t)
+
+	lead := &models.Lead{
+		TenantID:     "tenant-1",
+		PropertyID:   "property-1",
+		Phone:        "WhatsApp",
+		Channel:      models.LeadChannelWhatsApp,
+		ConsentGiven: true,
+	}
+	require.NoError(t, service.CreateLead(context.Background(), lead))
+	assert.Empty(t, lead.ContactID)
+}
+
+func TestMergeContacts_PreservesConsentAndCanBeUndone(t *testing.T) {
+	ctx := context.Background()
+	service := newContactTestService(t)
+
+	byEmail := createContactTestLead(t, service, "property-1", "ana@example.com", "")
+	byPhone := createContactTestLead(t, service, "property-2", "", "(11) 98765-4321")
+	require.NotEqual(t, byEmail.ContactID, byPhone.ContactID)
+
+	merged, err := service.MergeContacts(ctx, "tenant-1", byEmail.ContactID, byPhone.ContactID, "admin-1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, merged.InquiryCount)
+	assert.Equal(t, []string{"+5511987654321"}, merged.Phones)
+	require.Len(t, merged.Merges, 1)
+	assert.Equal(t, []string{byPhone.ID}, merged.Merges[0].LeadIDs)
+
+	// Consent records stay on the moved lead
+	moved, err := service.GetLead(ctx, "tenant-1", byPhone.ID)
+	require.NoError(t, err)
+	assert.Equal(t, byEmail.ContactID, moved.ContactID)
+	assert.True(t, moved.ConsentGiven)
+	assert.Equal(t, byPhone.ConsentIP, moved.ConsentIP)
+	assert.Equal(t, byPhone.ConsentDate.Unix(), moved.ConsentDate.Unix())
+
+	// New inquiries from either identity reach the merged contact
+	again := createContactTestLead(t, service, "property-1", "", "11 98765-4321")
+	assert.Equal(t, byEmail.ContactID, again.ContactID)
+
+	_, err = service.MergeContacts(ctx, "tenant-1", byEmail.ContactID, byPhone.ContactID, "admin-1")
+	assert.ErrorIs(t, err, repositories.ErrInvalidInput)
+
+	source, err := service.UnmergeContacts(ctx, "tenant-1", byEmail.ContactID, byPhone.ContactID, "admin-1")
+	require.NoError(t, err)
+	assert.False(t, source.IsMerged())
+	assert.Equal(t, 1, source.InquiryCount)
+
+	target, err := service.GetContact(ctx, "tenant-1", byEmail.ContactID)
+	require.NoError(t, err)
+	assert.Empty(t, target.Merges)
+	assert.Equal(t, 2, target.InquiryCount) // The inquiry made after the merge stays
+}
+
+func TestAnonymizeLead_RemovesIdentityFromContact(t *testing.T) {
+	ctx := context.Background()
+	service := newContactTestService(t)
+
+	lead := createContactTestLead(t, service, "property-1", "ana@example.com", "(11) 98765-4321")
+	require.NoError(t, service.AnonymizeLead(ctx, "tenant-1", lead.ID, "user_request"))
+
+	contact, err := service.GetContact(ctx, "tenant-1", lead.ContactID)
+	require.NoError(t, err)
+	assert.Empty(t, contact.Emails)
+	assert.Empty(t, contact.Phones)
+	assert.Equal(t, 1, contact.InquiryCount)
+
+	// The anonymized identity no longer matches
+	next := createContactTestLead(t, service, "property-1", "ana@example.com", "")
+	assert.NotEqual(t, lead.ContactID, next.ContactID)
+}
@@ -0,0 +1,300 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/search"
+)
+
+// routingPoolLimit caps the active brokers read to build a routing pool
+const routingPoolLimit = 500
+
+// ErrNoBrokerAvailable is returned when lead routing finds no broker
+var ErrNoBrokerAvailable = errors.New("no broker available")
+
+// SetBrokerRepository enables the broker pool strategies (round_robin,
+// least_open_leads, territory). Without it they find no broker and routing
+// falls back to the primary broker of the property.
+func (s *LeadService) SetBrokerRepository(brokerRepo repositories.BrokerStore) {
+	s.brokerRepo = brokerRepo
+}
+
+// SetRoutingCursorStore enables the round_robin strategy, which keeps its
+// turn in the store so every server instance shares it
+func (s *LeadService) SetRoutingCursorStore(cursorStore repositories.LeadRoutingCursorStore) {
+	s.cursorStore = cursorStore
+}
+
+// LeadRoute is the broker chosen for a lead and why
+type LeadRoute struct {
+	BrokerID string
+	Strategy models.LeadRoutingStrategy
+	Reason   string
+}
+
+// routeLead chooses the broker of a lead with the tenant's routing strategy,
+// trying the fallback strategy when it finds none. Excluded brokers are never
+// chosen. When no strategy finds a broker it returns the error along with an
+// unassigned route explaining why.
+func (s *LeadService) routeLead(ctx context.Context, tenant *models.Tenant, property *models.Property, exclude ...string) (*LeadRoute, error) {
+	settings := tenant.EffectiveLeadRouting()
+
+	route, err := s.applyRoutingStrategy(ctx, settings, settings.Strategy, property, exclude)
+	if err == nil {
+		return route, nil
+	}
+	if !errors.Is(err, ErrNoBrokerAvailable) {
+		log.Printf("⚠️  Lead routing (%s) failed for property %s: %v", settings.Strategy, property.ID, err)
+	}
+
+	fallback := settings.FallbackStrategy()
+	if fallback != settings.Strategy {
+		fallbackRoute, fallbackErr := s.applyRoutingStrategy(ctx, settings, fallback, property, exclude)
+		if fallbackErr == nil {
+			fallbackRoute.Reason = fmt.Sprintf("fallback after %s (%v): %s", settings.Strategy, err, fallbackRoute.Reason)
+			return fallbackRoute, nil
+		}
+		if !errors.Is(fallbackErr, ErrNoBrokerAvailable) {
+			log.Printf("⚠️  Lead routing fallback (%s) failed for property %s: %v", fallback, property.ID, fallbackErr)
+		}
+		err = fmt.Errorf("%w; fallback %s: %w", err, fallback, fallbackErr)
+	}
+
+	return &LeadRoute{
+		Strategy: settings.Strategy,
+		Reason:   fmt.Sprintf("unassigned: %v", err),
+	}, err
+}
+
+// applyRoutingStrategy runs a single routing strategy
+func (s *LeadService) applyRoutingStrategy(ctx context.Context, settings models.LeadRoutingSettings, strategy models.LeadRoutingStrategy, property *models.Property, exclude []string) (*LeadRoute, error) {
+	switch strategy {
+	case models.LeadRoutingPrimaryBroker:
+		return s.routeToPrimaryBroker(ctx, property, exclude)
+	case models.LeadRoutingRoundRobin:
+		return s.routeRoundRobin(ctx, property.TenantID, settings.BrokerIDs, exclude)
+	case models.LeadRoutingLeastOpenLeads:
+		return s.routeLeastOpenLeads(ctx, property.TenantID, settings.BrokerIDs, exclude)
+	case models.LeadRoutingTerritory:
+		return s.routeByTerritory(ctx, settings.Territories, property, exclude)
+	default:
+		return nil, fmt.Errorf("unknown routing strategy %q", strategy)
+	}
+}
+
+// routeToPrimaryBroker picks the primary broker of the property, then its
+// originating broker, then its captador
+func (s *LeadService) routeToPrimaryBroker(ctx context.Context, property *models.Property, exclude []string) (*LeadRoute, error) {
+	route := &LeadRoute{Strategy: models.LeadRoutingPrimaryBroker}
+
+	primaryRole, err := s.roleRepo.GetPrimaryBroker(ctx, property.TenantID, property.ID)
+	if err == nil && !slices.Contains(exclude, primaryRole.BrokerID) {
+		route.BrokerID = primaryRole.BrokerID
+		route.Reason = "primary broker of the property"
+		return route, nil
+	}
+	if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+		return nil, fmt.Errorf("failed to get primary broker: %w", err)
+	}
+
+	originatingRole, err := s.roleRepo.GetOriginatingBroker(ctx, property.TenantID, property.ID)
+	if err == nil && !slices.Contains(exclude, originatingRole.BrokerID) {
+		route.BrokerID = originatingRole.BrokerID
+		route.Reason = "originating broker of the property"
+		return route, nil
+	}
+	if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+		return nil, fmt.Errorf("failed to get originating broker: %w", err)
+	}
+
+	if property.CaptadorID != "" && !slices.Contains(exclude, property.CaptadorID) {
+		route.BrokerID = property.CaptadorID
+		route.Reason = "captador of the property"
+		return route, nil
+	}
+
+	return nil, fmt.Errorf("%w: property has no other primary or originating broker", ErrNoBrokerAvailable)
+}
+
+// routeRoundRobin picks the next broker of the pool, in turn
+func (s *LeadService) routeRoundRobin(ctx context.Context, tenantID string, brokerIDs, exclude []string) (*LeadRoute, error) {
+	if s.cursorStore == nil {
+		return nil, fmt.Errorf("round-robin cursor not configured")
+	}
+
+	pool, err := s.routingPool(ctx, tenantID, brokerIDs, exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	turn, err := s.cursorStore.NextTurn(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	index := int(turn % int64(len(pool)))
+
+	return &LeadRoute{
+		BrokerID: pool[index].ID,
+		Strategy: models.LeadRoutingRoundRobin,
+		Reason:   fmt.Sprintf("round-robin turn %d of %d active brokers", index+1, len(pool)),
+	}, nil
+}
+
+// routeLeastOpenLeads picks the broker of the pool with fewest open leads
+func (s *LeadService) routeLeastOpenLeads(ctx context.Context, tenantID string, brokerIDs, exclude []string) (*LeadRoute, error) {
+	pool, err := s.routingPool(ctx, tenantID, brokerIDs, exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	broker, openLeads, err := s.leastLoadedBroker(ctx, tenantID, pool)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LeadRoute{
+		BrokerID: broker.ID,
+		Strategy: models.LeadRoutingLeastOpenLeads,
+		Reason:   fmt.Sprintf("fewest open leads (%d) among %d active brokers", openLeads, len(pool)),
+	}, nil
+}
+
+// routeByTerritory picks, among the brokers of the territory rule matching
+// the property's location, the one with fewest open leads
+func (s *LeadService) routeByTerritory(ctx context.Context, rules []models.TerritoryRule, property *models.Property, exclude []string) (*LeadRoute, error) {
+	rule := matchTerritory(rules, property)
+	if rule == nil {
+		return nil, fmt.Errorf("%w: no territory rule for %s, %s", ErrNoBrokerAvailable, property.Neighborhood, property.City)
+	}
+
+	pool, err := s.routingPool(ctx, property.TenantID, rule.BrokerIDs, exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	broker, openLeads, err := s.leastLoadedBroker(ctx, property.TenantID, pool)
+	if err != nil {
+		return nil, err
+	}
+
+	territory := rule.City
+	if rule.Neighborhood != "" {
+		territory = rule.Neighborhood + ", " + rule.City
+	}
+
+	return &LeadRoute{
+		BrokerID: broker.ID,
+		Strategy: models.LeadRoutingTerritory,
+		Reason:   fmt.Sprintf("territory %s: fewest open leads (%d) among %d brokers", territory, openLeads, len(pool)),
+	}, nil
+}
+
+// matchTerritory returns the rule covering the property's neighborhood or,
+// when none does, its whole city. Names are compared without case or accents.
+func matchTerritory(rules []models.TerritoryRule, property *models.Property) *models.TerritoryRule {
+	city := normalizeLocation(property.City)
+	neighborhood := normalizeLocation(property.Neighborhood)
+	if city == "" {
+		return nil
+	}
+
+	var cityRule *models.TerritoryRule
+	for i := range rules {
+		rule := &rules[i]
+		if normalizeLocation(rule.City) != city {
+			continue
+		}
+		if rule.Neighborhood == "" {
+			if cityRule == nil {
+				cityRule = rule
+			}
+			continue
+		}
+		if neighborhood != "" && normalizeLocation(rule.Neighborhood) == neighborhood {
+			return rule
+		}
+	}
+	return cityRule
+}
+
+func normalizeLocation(name string) string {
+	return strings.TrimSpace(search.Normalize(name))
+}
+
+// routingPool returns the active brokers among brokerIDs (every active broker
+// of the tenant when empty) that are not excluded, ordered by ID
+func (s *LeadService) routingPool(ctx context.Context, tenantID string, brokerIDs, exclude []string) ([]*models.Broker, error) {
+	if s.brokerRepo == nil {
+		return nil, fmt.Errorf("broker repository not configured")
+	}
+
+	var pool []*models.Broker
+	if len(brokerIDs) == 0 {
+		brokers, err := s.brokerRepo.ListActive(ctx, tenantID, repositories.PaginationOptions{Limit: routingPoolLimit})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list active brokers: %w", err)
+		}
+		pool = brokers
+	} else {
+		for _, id := range brokerIDs {
+			broker, err := s.brokerRepo.Get(ctx, tenantID, id)
+			if errors.Is(err, repositories.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to get broker %s: %w", id, err)
+			}
+			if broker.IsActive {
+				pool = append(pool, broker)
+			}
+		}
+	}
+
+	pool = slices.DeleteFunc(pool, func(b *models.Broker) bool {
+		return slices.Contains(exclude, b.ID)
+	})
+	if len(pool) == 0 {
+		return nil, fmt.Errorf("%w: no active broker in the pool", ErrNoBrokerAvailable)
+	}
+
+	sort.Slice(pool, func(i, j int) bool { return pool[i].ID < pool[j].ID })
+	return pool, nil
+}
+
+// leastLoadedBroker returns the broker of the pool with fewest open leads;
+// ties go to the first broker of the pool
+func (s *LeadService) leastLoadedBroker(ctx context.Context, tenantID string, pool []*models.Broker) (*models.Broker, int, error) {
+	var chosen *models.Broker
+	fewest := 0
+	for _, broker := range pool {
+		openLeads, err := s.leadRepo.CountOpenByBroker(ctx, tenantID, broker.ID)
+		if err != nil {
+			return nil, 0, err
+		}
+		if chosen == nil || openLeads < fewest {
+			chosen, fewest = broker, openLeads
+		}
+	}
+	return chosen, fewest, nil
+}
+
+// applyLeadRoute records a route on a lead
+func applyLeadRoute(lead *models.Lead, route *LeadRoute) {
+	lead.AssignedBrokerID = route.BrokerID
+	lead.RoutingStrategy = route.Strategy
+	lead.RoutingReason = route.Reason
+	lead.AssignedAt = nil
+	if route.BrokerID != "" {
+		now := time.Now()
+		lead.AssignedAt = &now
+	}
+}
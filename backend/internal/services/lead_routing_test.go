@@ -0,0 +1,167 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories/memory"
+)
+
+// newRoutingTestService returns a lead service of tenant-1, which routes leads
+// with routing, with active brokers broker-1..3, inactive broker-4 and the
+// properties moema (Moema, São Paulo; primary broker-3), pinheiros (Pinheiros,
+// Sao Paulo) and centro (Centro, Campinas)
+func newRoutingTestService(t *testing.T, routing *models.LeadRoutingSettings) *LeadService {
+	t.Helper()
+	ctx := context.Background()
+
+	tenantRepo := memory.NewTenantRepository()
+	require.NoError(t, tenantRepo.Create(ctx, &models.Tenant{ID: "tenant-1", Name: "Imobiliária Teste", IsActive: true, LeadRouting: routing}))
+
+	brokerRepo := memory.NewBrokerRepository()
+	for _, id := range []string{"broker-1", "broker-2", "broker-3", "broker-4"} {
+		require.NoError(t, brokerRepo.Create(ctx, &models.Broker{ID: id, TenantID: "tenant-1", Name: id, IsActive: id != "broker-4"}))
+	}
+
+	propertyRepo := memory.NewPropertyRepository()
+	for _, p := range []*models.Property{
+		{ID: "moema", TenantID: "tenant-1", Neighborhood: "Moema", City: "São Paulo"},
+		{ID: "pinheiros", TenantID: "tenant-1", Neighborhood: "Pinheiros", City: "Sao Paulo"},
+		{ID: "centro", TenantID: "tenant-1", Neighborhood: "Centro", City: "Campinas"},
+	} {
+		require.NoError(t, propertyRepo.Create(ctx, p))
+	}
+
+	roleRepo := memory.NewPropertyBrokerRoleRepository()
+	require.NoError(t, roleRepo.Create(ctx, &models.PropertyBrokerRole{
+		TenantID:   "tenant-1",
+		PropertyID: "moema",
+		BrokerID:   "broker-3",
+		Role:       models.BrokerPropertyRoleOriginating,
+		IsPrimary:  true,
+	}))
+
+	service := NewLeadService(memory.NewLeadRepository(), propertyRepo, roleRepo, tenantRepo, memory.NewActivityLogRepository())
+	service.SetBrokerRepository(brokerRepo)
+	service.SetRoutingCursorStore(memory.NewLeadRoutingCursorRepository())
+	return service
+}
+
+func createRoutingTestLead(t *testing.T, service *LeadService, propertyID string) *models.Lead {
+	t.Helper()
+	lead := &models.Lead{
+		TenantID:     "tenant-1",
+		PropertyID:   propertyID,
+		Name:         "Ana Costa",
+		Email:        "ana@example.com",
+		Channel:      models.LeadChannelForm,
+		ConsentGiven: true,
+	}
+	require.NoError(t, service.CreateLead(context.Background(), lead))
+	return lead
+}
+
+func TestCreateLead_RoutesToPrimaryBrokerByDefault(t *testing.T) {
+	service := newRoutingTestService(t, nil)
+
+	lead := createRoutingTestLead(t, service, "moema")
+	assert.Equal(t, "broker-3", lead.AssignedBrokerID)
+	assert.Equal(t, models.LeadRoutingPrimaryBroker, lead.RoutingStrategy)
+	assert.Equal(t, "primary broker of the property", lead.RoutingReason)
+	assert.NotNil(t, lead.AssignedAt)
+
+	// No broker on the property: the lead is still created, unassigned
+	unassigned := createRoutingTestLead(t, service, "centro")
+	assert.Empty(t, unassigned.AssignedBrokerID)
+	assert.Nil(t, unassigned.AssignedAt)
+	assert.Contains(t, unassigned.RoutingReason, "unassigned")
+
+	stored, err := service.GetLead(context.Background(), "tenant-1", lead.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "broker-3", stored.AssignedBrokerID)
+}
+
+func TestCreateLead_RoundRobinSkipsInactiveBrokers(t *testing.T) {
+	service := newRoutingTestService(t, &models.LeadRoutingSettings{Strategy: models.LeadRoutingRoundRobin})
+
+	var assigned []string
+	for i := 0; i < 4; i++ {
+		assigned = append(assigned, createRoutingTestLead(t, service, "centro").AssignedBrokerID)
+	}
+	assert.Equal(t, []string{"broker-1", "broker-2", "broker-3", "broker-1"}, assigned)
+}
+
+func TestCreateLead_LeastOpenLeadsIgnoresClosedLeads(t *testing.T) {
+	ctx := context.Background()
+	service := newRoutingTestService(t, &models.LeadRoutingSettings{
+		Strategy:  models.LeadRoutingLeastOpenLeads,
+		BrokerIDs: []string{"broker-1", "broker-2"},
+	})
+
+	first := createRoutingTestLead(t, service, "centro")
+	assert.Equal(t, "broker-1", first.AssignedBrokerID)
+	assert.Equal(t, "fewest open leads (0) among 2 active brokers", first.RoutingReason)
+
+	second := createRoutingTestLead(t, service, "centro")
+	assert.Equal(t, "broker-2", second.AssignedBrokerID)
+
+	// A lost lead no longer counts against broker-1
+	require.NoError(t, service.UpdateStatus(ctx, "tenant-1", first.ID, models.LeadStatusLost))
+	third := createRoutingTestLead(t, service, "centro")
+	assert.Equal(t, "broker-1", third.AssignedBrokerID)
+}
+
+func TestCreateLead_TerritoryRules(t *testing.T) {
+	service := newRoutingTestService(t, &models.LeadRoutingSettings{
+		Strategy: models.LeadRoutingTerritory,
+		Fallback: models.LeadRoutingRoundRobin,
+		Territories: []models.TerritoryRule{
+			{City: "são paulo", BrokerIDs: []string{"broker-2"}},
+			{City: "São Paulo", Neighborhood: "MOEMA", BrokerIDs: []string{"broker-1", "broker-4"}},
+		},
+	})
+
+	// Neighborhood rule first, inactive brokers skipped
+	moema := createRoutingTestLead(t, service, "moema")
+	assert.Equal(t, "broker-1", moema.AssignedBrokerID)
+	assert.Equal(t, models.LeadRoutingTerritory, moema.RoutingStrategy)
+	assert.Contains(t, moema.RoutingReason, "territory MOEMA, São Paulo")
+
+	// City rule, matched without accents
+	pinheiros := createRoutingTestLead(t, service, "pinheiros")
+	assert.Equal(t, "broker-2", pinheiros.AssignedBrokerID)
+
+	// No rule for Campinas: fallback to round-robin
+	centro := createRoutingTestLead(t, service, "centro")
+	assert.Equal(t, "broker-1", centro.AssignedBrokerID)
+	assert.Equal(t, models.LeadRoutingRoundRobin, centro.RoutingStrategy)
+	assert.Contains(t, centro.RoutingReason, "fallback after territory")
+}
+
+func TestAssignToBroker_RecordsManualAssignment(t *testing.T) {
+	ctx := context.Background()
+	service := newRoutingTestService(t, nil)
+
+	lead := createRoutingTestLead(t, service, "moema")
+	require.NoError(t, service.AssignToBroker(ctx, "tenant-1", lead.ID, "broker-2"))
+
+	stored, err := service.GetLead(ctx, "tenant-1", lead.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "broker-2", stored.AssignedBrokerID)
+	assert.Equal(t, models.LeadRoutingManual, stored.RoutingStrategy)
+
+	assert.Error(t, service.AssignToBroker(ctx, "tenant-1", lead.ID, "broker-4"))
+
+	// Routing again applies the tenant strategy
+	brokerID, err := service.RouteToAvailableBroker(ctx, "tenant-1", lead.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "broker-3", brokerID)
+
+	unassigned := createRoutingTestLead(t, service, "centro")
+	_, err = service.RouteToAvailableBroker(ctx, "tenant-1", unassigned.ID)
+	assert.ErrorIs(t, err, ErrNoBrokerAvailable)
+}
@@ -6,26 +6,35 @@ import (
 	"time"
 
 	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/notify"
 	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
 	"github.com/altatech/ecosistema-imob/backend/internal/utils"
 )
 
 // LeadService handles business logic for lead management with LGPD compliance and routing
 type LeadService struct {
-	leadRepo        *repositories.LeadRepository
-	propertyRepo    *repositories.PropertyRepository
-	roleRepo        *repositories.PropertyBrokerRoleRepository
-	tenantRepo      *repositories.TenantRepository
-	activityLogRepo *repositories.ActivityLogRepository
+	leadRepo        repositories.LeadStore
+	propertyRepo    repositories.PropertyStore
+	roleRepo        repositories.PropertyBrokerRoleStore
+	tenantRepo      repositories.TenantStore
+	activityLogRepo repositories.ActivityLogStore
+	contactRepo     repositories.ContactStore           // optional, see SetContactRepository
+	brokerRepo      repositories.BrokerStore            // optional, see SetBrokerRepository
+	cursorStore     repositories.LeadRoutingCursorStore // optional, see SetRoutingCursorStore
+	notifier        *notify.Dispatcher                  // optional, see SetNotifier
+	userRepo        repositories.UserStore              // optional, see SetUserRepository
+	noteRepo        repositories.LeadNoteStore          // optional, see SetLeadNoteRepository
+	taskRepo        repositories.LeadTaskStore          // optional, see SetLeadTaskRepository
+	interactionRepo repositories.LeadInteractionStore   // optional, see SetLeadInteractionRepository
 }
 
 // NewLeadService creates a new lead service
 func NewLeadService(
-	leadRepo *repositories.LeadRepository,
-	propertyRepo *repositories.PropertyRepository,
-	roleRepo *repositories.PropertyBrokerRoleRepository,
-	tenantRepo *repositories.TenantRepository,
-	activityLogRepo *repositories.ActivityLogRepository,
+	leadRepo repositories.LeadStore,
+	propertyRepo repositories.PropertyStore,
+	roleRepo repositories.PropertyBrokerRoleStore,
+	tenantRepo repositories.TenantStore,
+	activityLogRepo repositories.ActivityLogStore,
 ) *LeadService {
 	return &LeadService{
 		leadRepo:        leadRepo,
@@ -57,12 +66,14 @@ func (s *LeadService) CreateLead(ctx context.Context, lead *models.Lead) error {
 	}
 
 	// Validate tenant exists
-	if _, err := s.tenantRepo.Get(ctx, lead.TenantID); err != nil {
+	tenant, err := s.tenantRepo.Get(ctx, lead.TenantID)
+	if err != nil {
 		return fmt.Errorf("tenant not found: %w", err)
 	}
 
 	// Validate property exists
-	if _, err := s.propertyRepo.Get(ctx, lead.TenantID, lead.PropertyID); err != nil {
+	property, err := s.propertyRepo.Get(ctx, lead.TenantID, lead.PropertyID)
+	if err != nil {
 		return fmt.Errorf("property not found: %w", err)
 	}
 
@@ -106,10 +117,34 @@ func (s *LeadService) CreateLead(ctx context.Context, lead *models.Lead) error {
 	lead.ConsentRevoked = false
 	lead.IsAnonymized = false
 
+	// Repeat inquiries from the same email or phone share a contact
+	lead.ContactID = ""
+	s.attachContact(ctx, lead)
+
+	// Route the lead to a broker with the tenant's strategy, unless one was given
+	if lead.AssignedBrokerID != "" {
+		applyLeadRoute(lead, &LeadRoute{
+			BrokerID: lead.AssignedBrokerID,
+			Strategy: models.LeadRoutingManual,
+			Reason:   "assigned on creation",
+		})
+	} else {
+		// An unassigned lead keeps the reason; routing never blocks lead creation
+		route, _ := s.routeLead(ctx, tenant, property)
+		applyLeadRoute(lead, route)
+	}
+
+	// New leads must be answered within the tenant's first-response target
+	lead.ResponseDueAt, lead.FirstResponseAt, lead.SLAStatus, lead.SLAEscalations = nil, nil, "", nil
+	if lead.Status == models.LeadStatusNew {
+		startLeadSLA(lead, tenant.EffectiveLeadSLA(), time.Now())
+	}
+
 	// Create lead in repository
 	if err := s.leadRepo.Create(ctx, lead); err != nil {
 		return fmt.Errorf("failed to create lead: %w", err)
 	}
+	s.refreshContact(ctx, lead.TenantID, lead.ContactID)
 
 	// Log activity based on channel
 	eventType := fmt.Sprintf("lead_created_%s", lead.Channel)
@@ -119,6 +154,9 @@ func (s *LeadService) CreateLead(ctx context.Context, lead *models.Lead) error {
 		"channel":       lead.Channel,
 		"consent_given": lead.ConsentGiven,
 		"consent_ip":    lead.ConsentIP,
+		"broker_id":     lead.AssignedBrokerID,
+		"routing":       lead.RoutingStrategy,
+		"reason":        lead.RoutingReason,
 	})
 
 	return nil
@@ -201,11 +239,37 @@ func (s *LeadService) UpdateLead(ctx context.Context, tenantID, id string, updat
 	delete(updates, "tenant_id")
 	delete(updates, "property_id")
 
+	// Contacts change through merge/unmerge only
+	delete(updates, "contact_id")
+
+	// Brokers change through AssignToBroker/RouteToAvailableBroker only
+	delete(updates, "assigned_broker_id")
+	delete(updates, "assigned_at")
+	delete(updates, "routing_strategy")
+	delete(updates, "routing_reason")
+
+	// The first-response SLA is tracked from status changes only
+	delete(updates, "response_due_at")
+	delete(updates, "first_response_at")
+	delete(updates, "sla_status")
+	delete(updates, "sla_escalations")
+	if leadStatusUpdate(updates) != "" && leadStatusUpdate(updates) != models.LeadStatusNew {
+		for field, value := range recordFirstResponse(existing, time.Now()) {
+			updates[field] = value
+		}
+	}
+
 	// Update lead in repository
 	if err := s.leadRepo.Update(ctx, tenantID, id, updates); err != nil {
 		return fmt.Errorf("failed to update lead: %w", err)
 	}
 
+	_, emailChanged := updates["email"]
+	_, phoneChanged := updates["phone"]
+	if emailChanged || phoneChanged {
+		s.syncLeadContact(ctx, tenantID, id)
+	}
+
 	// Log activity
 	_ = s.logActivity(ctx, tenantID, "lead_updated", models.ActorTypeSystem, "", map[string]interface{}{
 		"lead_id":     id,
@@ -235,6 +299,7 @@ func (s *LeadService) DeleteLead(ctx context.Context, tenantID, id string) error
 	if err := s.leadRepo.Delete(ctx, tenantID, id); err != nil {
 		return fmt.Errorf("failed to delete lead: %w", err)
 	}
+	s.refreshContact(ctx, tenantID, lead.ContactID)
 
 	// Log activity
 	_ = s.logActivity(ctx, tenantID, "lead_deleted", models.ActorTypeSystem, "", map[string]interface{}{
@@ -299,6 +364,13 @@ func (s *LeadService) UpdateStatus(ctx context.Context, tenantID, id string, sta
 		"status": status,
 	}
 
+	// Leaving "new" is the first response to the lead
+	if status != models.LeadStatusNew {
+		for field, value := range recordFirstResponse(lead, time.Now()) {
+			updates[field] = value
+		}
+	}
+
 	if err := s.leadRepo.Update(ctx, tenantID, id, updates); err != nil {
 		return fmt.Errorf("failed to update lead status: %w", err)
 	}
@@ -332,22 +404,38 @@ func (s *LeadService) AssignToBroker(ctx context.Context, tenantID, leadID, brok
 		return fmt.Errorf("lead not found: %w", err)
 	}
 
-	// Note: In MVP, we don't store assigned_broker_id in the Lead model
-	// This is for future implementation in MVP+1
-	// For now, we just log the assignment
+	if s.brokerRepo != nil {
+		broker, err := s.brokerRepo.Get(ctx, tenantID, brokerID)
+		if err != nil {
+			return fmt.Errorf("broker not found: %w", err)
+		}
+		if !broker.IsActive {
+			return fmt.Errorf("%w: broker %s is not active", repositories.ErrInvalidInput, brokerID)
+		}
+	}
+
+	route := &LeadRoute{
+		BrokerID: brokerID,
+		Strategy: models.LeadRoutingManual,
+		Reason:   "assigned manually",
+	}
+	if err := s.saveLeadRoute(ctx, lead, route); err != nil {
+		return err
+	}
 
 	// Log activity
 	_ = s.logActivity(ctx, tenantID, "lead_assigned_to_broker", models.ActorTypeSystem, "", map[string]interface{}{
 		"lead_id":     leadID,
 		"property_id": lead.PropertyID,
 		"broker_id":   brokerID,
+		"routing":     route.Strategy,
 	})
 
 	return nil
 }
 
-// RouteToAvailableBroker routes a lead to an available broker (automatic routing)
-// Uses round-robin or primary broker logic
+// RouteToAvailableBroker routes a lead again with the tenant's routing
+// strategy (see models.LeadRoutingSettings) and returns the chosen broker
 func (s *LeadService) RouteToAvailableBroker(ctx context.Context, tenantID, leadID string) (string, error) {
 	if tenantID == "" {
 		return "", fmt.Errorf("tenant_id is required")
@@ -362,21 +450,59 @@ func (s *LeadService) RouteToAvailableBroker(ctx context.Context, tenantID, lead
 		return "", fmt.Errorf("lead not found: %w", err)
 	}
 
-	// Get primary broker for the property
-	primaryRole, err := s.roleRepo.GetPrimaryBroker(ctx, tenantID, lead.PropertyID)
+	tenant, err := s.tenantRepo.Get(ctx, tenantID)
+	if err != nil {
+		return "", fmt.Errorf("tenant not found: %w", err)
+	}
+
+	property, err := s.propertyRepo.Get(ctx, tenantID, lead.PropertyID)
+	if err != nil {
+		return "", fmt.Errorf("property not found: %w", err)
+	}
+
+	route, err := s.routeLead(ctx, tenant, property)
 	if err != nil {
-		// If no primary broker, try to get originating broker
-		if err == repositories.ErrNotFound {
-			originatingRole, err := s.roleRepo.GetOriginatingBroker(ctx, tenantID, lead.PropertyID)
-			if err != nil {
-				return "", fmt.Errorf("no broker available for routing: %w", err)
-			}
-			return originatingRole.BrokerID, nil
+		return "", fmt.Errorf("failed to route lead: %w", err)
+	}
+	if err := s.saveLeadRoute(ctx, lead, route); err != nil {
+		return "", err
+	}
+
+	// Log activity
+	_ = s.logActivity(ctx, tenantID, "lead_assigned_to_broker", models.ActorTypeSystem, "", map[string]interface{}{
+		"lead_id":     leadID,
+		"property_id": lead.PropertyID,
+		"broker_id":   route.BrokerID,
+		"routing":     route.Strategy,
+		"reason":      route.Reason,
+	})
+
+	return route.BrokerID, nil
+}
+
+// saveLeadRoute records a route on a stored lead. A lead still waiting for
+// its first response gets a new target for the new broker.
+func (s *LeadService) saveLeadRoute(ctx context.Context, lead *models.Lead, route *LeadRoute) error {
+	applyLeadRoute(lead, route)
+
+	updates := map[string]interface{}{
+		"assigned_broker_id": lead.AssignedBrokerID,
+		"assigned_at":        lead.AssignedAt,
+		"routing_strategy":   lead.RoutingStrategy,
+		"routing_reason":     lead.RoutingReason,
+	}
+	if lead.FirstResponseAt == nil && lead.SLAStatus != "" && lead.AssignedBrokerID != "" {
+		if tenant, err := s.tenantRepo.Get(ctx, lead.TenantID); err == nil {
+			startLeadSLA(lead, tenant.EffectiveLeadSLA(), time.Now())
+			updates["response_due_at"] = lead.ResponseDueAt
+			updates["sla_status"] = lead.SLAStatus
 		}
-		return "", fmt.Errorf("failed to get primary broker: %w", err)
 	}
 
-	return primaryRole.BrokerID, nil
+	if err := s.leadRepo.Update(ctx, lead.TenantID, lead.ID, updates); err != nil {
+		return fmt.Errorf("failed to assign lead: %w", err)
+	}
+	return nil
 }
 
 // RevokeConsent revokes lead consent (LGPD)
@@ -442,6 +568,10 @@ func (s *LeadService) AnonymizeLead(ctx context.Context, tenantID, id, reason st
 		return fmt.Errorf("failed to anonymize lead: %w", err)
 	}
 
+	// The contact must not keep the anonymized email and phone
+	s.refreshContact(ctx, tenantID, lead.ContactID)
+	s.deleteLeadNotes(ctx, tenantID, id)
+
 	// Log activity
 	_ = s.logActivity(ctx, tenantID, "lead_anonymized", models.ActorTypeSystem, "", map[string]interface{}{
 		"lead_id":     id,
@@ -452,6 +582,17 @@ func (s *LeadService) AnonymizeLead(ctx context.Context, tenantID, id, reason st
 	return nil
 }
 
+// leadStatusUpdate returns the status set by an update map, if any
+func leadStatusUpdate(updates map[string]interface{}) models.LeadStatus {
+	switch status := updates["status"].(type) {
+	case models.LeadStatus:
+		return status
+	case string:
+		return models.LeadStatus(status)
+	}
+	return ""
+}
+
 // validateChannel validates lead channel
 func (s *LeadService) validateChannel(channel models.LeadChannel) error {
 	validChannels := map[models.LeadChannel]bool{
@@ -471,10 +612,12 @@ func (s *LeadService) validateChannel(channel models.LeadChannel) error {
 // validateStatus validates lead status
 func (s *LeadService) validateStatus(status models.LeadStatus) error {
 	validStatuses := map[models.LeadStatus]bool{
-		models.LeadStatusNew:       true,
-		models.LeadStatusContacted: true,
-		models.LeadStatusQualified: true,
-		models.LeadStatusLost:      true,
+		models.LeadStatusNew:         true,
+		models.LeadStatusContacted:   true,
+		models.LeadStatusQualified:   true,
+		models.LeadStatusNegotiating: true,
+		models.LeadStatusConverted:   true,
+		models.LeadStatusLost:        true,
 	}
 
 	if !validStatuses[status] {
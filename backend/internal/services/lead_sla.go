@@ -0,0 +1,427 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"time"
+
+	"cloud.google.com/go/firestore"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/notify"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+const (
+	leadSLABatchSize = 200  // Overdue leads escalated per run
+	slaReportLimit   = 5000 // Leads read to build an SLA report
+)
+
+// SetNotifier enables the notify_manager action of lead SLA breaches
+func (s *LeadService) SetNotifier(notifier *notify.Dispatcher) {
+	s.notifier = notifier
+}
+
+// SetUserRepository sets the user repository used to find the managers
+// notified of lead SLA breaches
+func (s *LeadService) SetUserRepository(userRepo repositories.UserStore) {
+	s.userRepo = userRepo
+}
+
+// LeadSLAMessageData is the data available to the lead_sla_breach template
+type LeadSLAMessageData struct {
+	ManagerName       string
+	TenantName        string
+	LeadName          string
+	Channel           string
+	PropertyReference string
+	BrokerName        string // Broker that missed the target
+	NewBrokerName     string // Broker the lead was reassigned to
+	TargetMinutes     int
+}
+
+// startLeadSLA starts the first-response target of a lead at the given time
+func startLeadSLA(lead *models.Lead, settings models.LeadSLASettings, at time.Time) {
+	due := at.Add(settings.ResponseTarget(lead.Channel))
+	lead.ResponseDueAt = &due
+	lead.SLAStatus = models.LeadSLAStatusPending
+}
+
+// recordFirstResponse records the first response to a lead, settling its
+// target, and returns the fields to store (nil when it was answered before)
+func recordFirstResponse(lead *models.Lead, at time.Time) map[string]interface{} {
+	if lead.FirstResponseAt != nil {
+		return nil
+	}
+
+	lead.FirstResponseAt = &at
+	updates := map[string]interface{}{"first_response_at": at}
+	if lead.SLAStatus != "" && lead.ResponseDueAt != nil {
+		lead.SLAStatus = models.LeadSLAStatusMet
+		if at.After(*lead.ResponseDueAt) || len(lead.SLAEscalations) > 0 {
+			lead.SLAStatus = models.LeadSLAStatusMissed
+		}
+		updates["sla_status"] = lead.SLAStatus
+	}
+	return updates
+}
+
+// ProcessLeadSLAResponse summarizes a run of the lead SLA escalation
+type ProcessLeadSLAResponse struct {
+	Overdue    int `json:"overdue"`    // Leads past their first-response target
+	Reassigned int `json:"reassigned"` // Leads routed to another broker
+	Notified   int `json:"notified"`   // Breaches that reached at least one manager
+	Failed     int `json:"failed"`     // Escalations with an error (recorded on the lead)
+}
+
+// ProcessSLABreaches escalates the leads not contacted within their
+// first-response target with the tenant's SLA actions: reassign them to
+// another broker (with a new target) and/or notify the managers
+func (s *LeadService) ProcessSLABreaches(ctx context.Context, tenantID string) (*ProcessLeadSLAResponse, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+
+	now := time.Now()
+	overdue, err := s.leadRepo.ListOverdueResponses(ctx, tenantID, now, leadSLABatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list overdue leads: %w", err)
+	}
+
+	tenant, err := s.tenantRepo.Get(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("tenant not found: %w", err)
+	}
+	settings := tenant.EffectiveLeadSLA()
+	response := &ProcessLeadSLAResponse{Overdue: len(overdue)}
+
+	for _, lead := range overdue {
+		escalation := s.escalateLead(ctx, tenant, settings, lead, now)
+		if escalation.NewBrokerID != "" {
+			response.Reassigned++
+		}
+		if len(escalation.NotifiedUsers) > 0 {
+			response.Notified++
+		}
+		if escalation.Error != "" {
+			response.Failed++
+		}
+
+		if err := s.leadRepo.Update(ctx, tenantID, lead.ID, map[string]interface{}{
+			"assigned_broker_id": lead.AssignedBrokerID,
+			"assigned_at":        lead.AssignedAt,
+			"routing_strategy":   lead.RoutingStrategy,
+			"routing_reason":     lead.RoutingReason,
+			"response_due_at":    lead.ResponseDueAt,
+			"sla_status":         lead.SLAStatus,
+			"sla_escalations":    lead.SLAEscalations,
+		}); err != nil {
+			log.Printf("❌ Failed to update lead %s after SLA breach: %v", lead.ID, err)
+			continue
+		}
+
+		_ = s.logActivity(ctx, tenantID, "lead_sla_breached", models.ActorTypeSystem, "", map[string]interface{}{
+			"lead_id":        lead.ID,
+			"property_id":    lead.PropertyID,
+			"broker_id":      escalation.BrokerID,
+			"new_broker_id":  escalation.NewBrokerID,
+			"notified_users": escalation.NotifiedUsers,
+		})
+	}
+
+	log.Printf("⏱️  Lead SLA for tenant %s: %d overdue, %d reassigned, %d notified, %d failed",
+		tenantID, response.Overdue, response.Reassigned, response.Notified, response.Failed)
+	return response, nil
+}
+
+// escalateLead runs the SLA actions on an overdue lead and records the
+// escalation on it. A reassigned lead gets a new target; otherwise it stays
+// breached until answered.
+func (s *LeadService) escalateLead(ctx context.Context, tenant *models.Tenant, settings models.LeadSLASettings, lead *models.Lead, now time.Time) models.LeadSLAEscalation {
+	escalation := models.LeadSLAEscalation{
+		BrokerID:    lead.AssignedBrokerID,
+		DueAt:       *lead.ResponseDueAt,
+		EscalatedAt: now,
+		Actions:     settings.Actions,
+	}
+	var errs []error
+
+	property, err := s.propertyRepo.Get(ctx, lead.TenantID, lead.PropertyID)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("property not found: %w", err))
+	}
+
+	lead.SLAStatus = models.LeadSLAStatusBreached
+	if property != nil && settings.HasAction(models.LeadSLAActionReassign) && reassignments(lead) < settings.MaxReassignments {
+		// Brokers that already missed the target of this lead are skipped
+		exclude := []string{lead.AssignedBrokerID}
+		for _, previous := range lead.SLAEscalations {
+			exclude = append(exclude, previous.BrokerID)
+		}
+
+		route, err := s.routeLead(ctx, tenant, property, exclude...)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("reassign: %w", err))
+		} else {
+			route.Reason = "SLA reassignment: " + route.Reason
+			applyLeadRoute(lead, route)
+			startLeadSLA(lead, settings, now)
+			escalation.NewBrokerID = route.BrokerID
+		}
+	}
+
+	if settings.HasAction(models.LeadSLAActionNotifyManager) {
+		notified, err := s.notifyManagers(ctx, tenant, settings, lead, property, escalation)
+		escalation.NotifiedUsers = notified
+		if err != nil {
+			errs = append(errs, fmt.Errorf("notify_manager: %w", err))
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		log.Printf("⚠️  SLA escalation of lead %s: %v", lead.ID, err)
+		escalation.Error = err.Error()
+	}
+
+	lead.SLAEscalations = append(slices.Clone(lead.SLAEscalations), escalation)
+	return escalation
+}
+
+// reassignments counts the SLA reassignments of a lead
+func reassignments(lead *models.Lead) int {
+	count := 0
+	for _, escalation := range lead.SLAEscalations {
+		if escalation.NewBrokerID != "" {
+			count++
+		}
+	}
+	return count
+}
+
+// notifyManagers sends the lead_sla_breach message to the tenant's managers,
+// returning the IDs of the users reached
+func (s *LeadService) notifyManagers(ctx context.Context, tenant *models.Tenant, settings models.LeadSLASettings, lead *models.Lead, property *models.Property, escalation models.LeadSLAEscalation) ([]string, error) {
+	if s.notifier == nil {
+		return nil, fmt.Errorf("no notifier configured")
+	}
+
+	managers, err := s.slaManagers(ctx, lead.TenantID, settings)
+	if err != nil {
+		return nil, err
+	}
+	if len(managers) == 0 {
+		return nil, fmt.Errorf("no active manager to notify")
+	}
+
+	data := LeadSLAMessageData{
+		TenantName:        tenant.Name,
+		LeadName:          lead.Name,
+		Channel:           string(lead.Channel),
+		PropertyReference: lead.PropertyID,
+		BrokerName:        s.brokerName(ctx, lead.TenantID, escalation.BrokerID),
+		NewBrokerName:     s.brokerName(ctx, lead.TenantID, escalation.NewBrokerID),
+		TargetMinutes:     int(settings.ResponseTarget(lead.Channel).Minutes()),
+	}
+	if property != nil && property.Reference != "" {
+		data.PropertyReference = property.Reference
+	}
+
+	var notified []string
+	var lastErr error
+	for _, manager := range managers {
+		data.ManagerName = firstName(manager.Name)
+		channel, _, _, err := sendNotification(ctx, s.notifier, tenant, models.NotificationTemplateLeadSLABreach,
+			recipient{Name: manager.Name, Phone: manager.Phone, Email: manager.Email}, data,
+			[]string{data.ManagerName, data.LeadName, data.PropertyReference})
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if channel != "" {
+			notified = append(notified, manager.ID)
+		}
+	}
+
+	if len(notified) == 0 {
+		if lastErr == nil {
+			lastErr = fmt.Errorf("no channel available to reach the managers")
+		}
+		return nil, lastErr
+	}
+	return notified, nil
+}
+
+// slaManagers returns the users notified of SLA breaches: the configured ones,
+// or every active admin and manager of the tenant
+func (s *LeadService) slaManagers(ctx context.Context, tenantID string, settings models.LeadSLASettings) ([]*models.User, error) {
+	if s.userRepo == nil {
+		return nil, fmt.Errorf("user repository not configured")
+	}
+
+	if len(settings.ManagerUserIDs) > 0 {
+		var managers []*models.User
+		for _, id := range settings.ManagerUserIDs {
+			user, err := s.userRepo.Get(ctx, tenantID, id)
+			if errors.Is(err, repositories.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to get user %s: %w", id, err)
+			}
+			if user.IsActive {
+				managers = append(managers, user)
+			}
+		}
+		return managers, nil
+	}
+
+	users, err := s.userRepo.ListActive(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list active users: %w", err)
+	}
+	return slices.DeleteFunc(users, func(u *models.User) bool {
+		return u.Role != "admin" && u.Role != "manager"
+	}), nil
+}
+
+// brokerName returns the name of a broker, or its ID when it cannot be loaded
+func (s *LeadService) brokerName(ctx context.Context, tenantID, brokerID string) string {
+	if brokerID == "" || s.brokerRepo == nil {
+		return brokerID
+	}
+	broker, err := s.brokerRepo.Get(ctx, tenantID, brokerID)
+	if err != nil {
+		return brokerID
+	}
+	return broker.Name
+}
+
+// BrokerSLAStats is the first-response performance of a broker
+type BrokerSLAStats struct {
+	BrokerID           string  `json:"broker_id"` // Empty for unassigned leads
+	Assigned           int     `json:"assigned"`  // Leads currently assigned to the broker
+	Responded          int     `json:"responded"`
+	OnTime             int     `json:"on_time"`              // Answered within the target
+	Late               int     `json:"late"`                 // Answered after the target
+	Breached           int     `json:"breached"`             // Targets missed (escalated), including leads reassigned away
+	Pending            int     `json:"pending"`              // Waiting for the first response, within the target
+	ComplianceRate     float64 `json:"compliance_rate"`      // on_time / (on_time + late + breached), 0-1
+	AvgResponseMinutes float64 `json:"avg_response_minutes"` // From assignment to first response
+}
+
+// LeadSLAReport is the SLA compliance of the leads created in a period
+type LeadSLAReport struct {
+	From    time.Time         `json:"from"`
+	To      time.Time         `json:"to"`
+	Leads   int               `json:"leads"`
+	Brokers []*BrokerSLAStats `json:"brokers"`
+	Total   BrokerSLAStats    `json:"total"`
+}
+
+// GetSLAReport returns the first-response compliance per broker of the leads
+// created in [from, to)
+func (s *LeadService) GetSLAReport(ctx context.Context, tenantID string, from, to time.Time) (*LeadSLAReport, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+	if !from.Before(to) {
+		return nil, fmt.Errorf("%w: from must be before to", repositories.ErrInvalidInput)
+	}
+
+	leads, err := s.leadRepo.List(ctx, tenantID, &repositories.LeadFilters{CreatedFrom: &from, CreatedTo: &to}, repositories.PaginationOptions{
+		Limit:     slaReportLimit,
+		OrderBy:   "created_at",
+		Direction: firestore.Asc,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list leads: %w", err)
+	}
+
+	report := &LeadSLAReport{From: from, To: to, Total: BrokerSLAStats{}}
+	byBroker := make(map[string]*BrokerSLAStats)
+	stats := func(brokerID string) *BrokerSLAStats {
+		if byBroker[brokerID] == nil {
+			byBroker[brokerID] = &BrokerSLAStats{BrokerID: brokerID}
+		}
+		return byBroker[brokerID]
+	}
+	responseMinutes := make(map[string]float64)
+
+	for _, lead := range leads {
+		if lead.SLAStatus == "" {
+			continue // Created before SLA tracking or not new
+		}
+		report.Leads++
+
+		// Every escalation is a breach of the broker that missed the target
+		for _, escalation := range lead.SLAEscalations {
+			stats(escalation.BrokerID).Breached++
+		}
+
+		current := stats(lead.AssignedBrokerID)
+		current.Assigned++
+		switch lead.SLAStatus {
+		case models.LeadSLAStatusPending:
+			current.Pending++
+		case models.LeadSLAStatusMet:
+			current.OnTime++
+		case models.LeadSLAStatusMissed:
+			// Late answers after an escalation were already counted as a breach
+			if !answeredAfterEscalation(lead) {
+				current.Late++
+			}
+		}
+
+		if lead.FirstResponseAt != nil {
+			current.Responded++
+			start := lead.CreatedAt
+			if lead.AssignedAt != nil && lead.AssignedAt.After(start) {
+				start = *lead.AssignedAt
+			}
+			responseMinutes[lead.AssignedBrokerID] += lead.FirstResponseAt.Sub(start).Minutes()
+		}
+	}
+
+	for brokerID, broker := range byBroker {
+		finishStats(broker, responseMinutes[brokerID])
+		report.Brokers = append(report.Brokers, broker)
+
+		report.Total.Assigned += broker.Assigned
+		report.Total.Responded += broker.Responded
+		report.Total.OnTime += broker.OnTime
+		report.Total.Late += broker.Late
+		report.Total.Breached += broker.Breached
+		report.Total.Pending += broker.Pending
+	}
+	total := 0.0
+	for _, minutes := range responseMinutes {
+		total += minutes
+	}
+	finishStats(&report.Total, total)
+
+	sort.Slice(report.Brokers, func(i, j int) bool { return report.Brokers[i].BrokerID < report.Brokers[j].BrokerID })
+	return report, nil
+}
+
+// answeredAfterEscalation reports whether the broker answering a lead is the
+// one that missed its last target (the lead was not reassigned)
+func answeredAfterEscalation(lead *models.Lead) bool {
+	if len(lead.SLAEscalations) == 0 {
+		return false
+	}
+	return lead.SLAEscalations[len(lead.SLAEscalations)-1].NewBrokerID == ""
+}
+
+// finishStats computes the rates of a broker's stats
+func finishStats(stats *BrokerSLAStats, totalResponseMinutes float64) {
+	if measured := stats.OnTime + stats.Late + stats.Breached; measured > 0 {
+		stats.ComplianceRate = float64(stats.OnTime) / float64(measured)
+	}
+	if stats.Responded > 0 {
+		stats.AvgResponseMinutes = totalResponseMinutes / float64(stats.Responded)
+	}
+}
@@ -0,0 +1,201 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/notify"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories/memory"
+)
+
+// newSLATestService returns the routing test service (least_open_leads) with
+// the given SLA settings, an active manager reachable on WhatsApp and the
+// fake WhatsApp notifier
+func newSLATestService(t *testing.T, sla *models.LeadSLASettings) (*LeadService, *notify.FakeNotifier) {
+	t.Helper()
+	ctx := context.Background()
+
+	service := newRoutingTestService(t, &models.LeadRoutingSettings{Strategy: models.LeadRoutingLeastOpenLeads})
+	require.NoError(t, service.tenantRepo.Update(ctx, "tenant-1", map[string]interface{}{"lead_sla": sla}))
+
+	userRepo := memory.NewUserRepository()
+	require.NoError(t, userRepo.Create(ctx, &models.User{ID: "manager-1", TenantID: "tenant-1", Name: "Carla Lima", Phone: "(11) 91234-5678", Role: "manager", IsActive: true}))
+	require.NoError(t, userRepo.Create(ctx, &models.User{ID: "broker-user", TenantID: "tenant-1", Name: "Bruno", Phone: "(11) 99876-5432", Role: "broker", IsActive: true}))
+	service.SetUserRepository(userRepo)
+
+	whatsapp := notify.NewFakeNotifier(models.NotificationChannelWhatsApp)
+	service.SetNotifier(notify.NewDispatcher(notify.RetryPolicy{MaxAttempts: 1}, whatsapp))
+	return service, whatsapp
+}
+
+// expireResponseTarget moves the first-response target of a lead to the past
+func expireResponseTarget(t *testing.T, service *LeadService, leadID string) {
+	t.Helper()
+	past := time.Now().Add(-time.Minute)
+	require.NoError(t, service.leadRepo.Update(context.Background(), "tenant-1", leadID, map[string]interface{}{"response_due_at": &past}))
+}
+
+func TestCreateLead_StartsResponseSLA(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newSLATestService(t, &models.LeadSLASettings{
+		ResponseMinutes:        30,
+		ChannelResponseMinutes: map[models.LeadChannel]int{models.LeadChannelForm: 15},
+	})
+
+	before := time.Now()
+	lead := createRoutingTestLead(t, service, "moema")
+	assert.Equal(t, models.LeadSLAStatusPending, lead.SLAStatus)
+	require.NotNil(t, lead.ResponseDueAt)
+	assert.WithinDuration(t, before.Add(15*time.Minute), *lead.ResponseDueAt, 5*time.Second)
+
+	// Leaving "new" within the target meets it
+	require.NoError(t, service.UpdateStatus(ctx, "tenant-1", lead.ID, models.LeadStatusContacted))
+	answered, err := service.GetLead(ctx, "tenant-1", lead.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.LeadSLAStatusMet, answered.SLAStatus)
+	require.NotNil(t, answered.FirstResponseAt)
+
+	// Later changes keep the first response
+	firstResponse := *answered.FirstResponseAt
+	require.NoError(t, service.UpdateStatus(ctx, "tenant-1", lead.ID, models.LeadStatusQualified))
+	answered, err = service.GetLead(ctx, "tenant-1", lead.ID)
+	require.NoError(t, err)
+	assert.True(t, firstResponse.Equal(*answered.FirstResponseAt))
+
+	// Answering after the target misses it
+	late := createRoutingTestLead(t, service, "pinheiros")
+	expireResponseTarget(t, service, late.ID)
+	require.NoError(t, service.UpdateLead(ctx, "tenant-1", late.ID, map[string]interface{}{"status": "contacted", "sla_status": "met"}))
+	answered, err = service.GetLead(ctx, "tenant-1", late.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.LeadSLAStatusMissed, answered.SLAStatus)
+}
+
+func TestProcessSLABreaches_ReassignsThenNotifiesManagers(t *testing.T) {
+	ctx := context.Background()
+	service, whatsapp := newSLATestService(t, &models.LeadSLASettings{
+		Actions: []models.LeadSLAAction{models.LeadSLAActionReassign, models.LeadSLAActionNotifyManager},
+	})
+
+	lead := createRoutingTestLead(t, service, "moema")
+	require.Equal(t, "broker-1", lead.AssignedBrokerID)
+	onTime := createRoutingTestLead(t, service, "pinheiros")
+
+	// Nothing overdue yet
+	response, err := service.ProcessSLABreaches(ctx, "tenant-1")
+	require.NoError(t, err)
+	assert.Equal(t, 0, response.Overdue)
+
+	expireResponseTarget(t, service, lead.ID)
+	response, err = service.ProcessSLABreaches(ctx, "tenant-1")
+	require.NoError(t, err)
+	assert.Equal(t, &ProcessLeadSLAResponse{Overdue: 1, Reassigned: 1, Notified: 1}, response)
+
+	reassigned, err := service.GetLead(ctx, "tenant-1", lead.ID)
+	require.NoError(t, err)
+	assert.NotEqual(t, "broker-1", reassigned.AssignedBrokerID)
+	assert.Contains(t, reassigned.RoutingReason, "SLA reassignment: ")
+	assert.Equal(t, models.LeadSLAStatusPending, reassigned.SLAStatus)
+	assert.True(t, reassigned.ResponseDueAt.After(time.Now()))
+	require.Len(t, reassigned.SLAEscalations, 1)
+	assert.Equal(t, "broker-1", reassigned.SLAEscalations[0].BrokerID)
+	assert.Equal(t, reassigned.AssignedBrokerID, reassigned.SLAEscalations[0].NewBrokerID)
+	assert.Equal(t, []string{"manager-1"}, reassigned.SLAEscalations[0].NotifiedUsers)
+
+	sent := whatsapp.Sent()
+	require.Len(t, sent, 1)
+	assert.Equal(t, "(11) 91234-5678", sent[0].To)
+	assert.Contains(t, sent[0].Body, "Ana Costa")
+
+	// The reassignment limit is reached: the next breach only notifies
+	expireResponseTarget(t, service, lead.ID)
+	response, err = service.ProcessSLABreaches(ctx, "tenant-1")
+	require.NoError(t, err)
+	assert.Equal(t, &ProcessLeadSLAResponse{Overdue: 1, Notified: 1}, response)
+
+	breached, err := service.GetLead(ctx, "tenant-1", lead.ID)
+	require.NoError(t, err)
+	assert.Equal(t, reassigned.AssignedBrokerID, breached.AssignedBrokerID)
+	assert.Equal(t, models.LeadSLAStatusBreached, breached.SLAStatus)
+	assert.Len(t, breached.SLAEscalations, 2)
+
+	// Breached leads are not escalated again
+	response, err = service.ProcessSLABreaches(ctx, "tenant-1")
+	require.NoError(t, err)
+	assert.Equal(t, 0, response.Overdue)
+
+	untouched, err := service.GetLead(ctx, "tenant-1", onTime.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.LeadSLAStatusPending, untouched.SLAStatus)
+	assert.Empty(t, untouched.SLAEscalations)
+}
+
+func TestProcessSLABreaches_RecordsNotificationFailure(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newSLATestService(t, nil)
+	service.SetNotifier(nil)
+
+	lead := createRoutingTestLead(t, service, "moema")
+	expireResponseTarget(t, service, lead.ID)
+
+	response, err := service.ProcessSLABreaches(ctx, "tenant-1")
+	require.NoError(t, err)
+	assert.Equal(t, &ProcessLeadSLAResponse{Overdue: 1, Failed: 1}, response)
+
+	breached, err := service.GetLead(ctx, "tenant-1", lead.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.LeadSLAStatusBreached, breached.SLAStatus)
+	require.Len(t, breached.SLAEscalations, 1)
+	assert.Contains(t, breached.SLAEscalations[0].Error, "no notifier configured")
+}
+
+func TestGetSLAReport_ComplianceByBroker(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newSLATestService(t, &models.LeadSLASettings{
+		Actions: []models.LeadSLAAction{models.LeadSLAActionReassign},
+	})
+	from := time.Now().Add(-time.Hour)
+
+	// broker-1 answers on time, broker-2 late, broker-3 misses the target
+	onTime := createRoutingTestLead(t, service, "moema")
+	late := createRoutingTestLead(t, service, "moema")
+	breached := createRoutingTestLead(t, service, "moema")
+	require.Equal(t, []string{"broker-1", "broker-2", "broker-3"}, []string{onTime.AssignedBrokerID, late.AssignedBrokerID, breached.AssignedBrokerID})
+
+	require.NoError(t, service.UpdateStatus(ctx, "tenant-1", onTime.ID, models.LeadStatusContacted))
+	expireResponseTarget(t, service, late.ID)
+	require.NoError(t, service.UpdateStatus(ctx, "tenant-1", late.ID, models.LeadStatusContacted))
+	expireResponseTarget(t, service, breached.ID)
+	_, err := service.ProcessSLABreaches(ctx, "tenant-1")
+	require.NoError(t, err)
+
+	report, err := service.GetSLAReport(ctx, "tenant-1", from, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 3, report.Leads)
+
+	stats := make(map[string]*BrokerSLAStats)
+	for _, broker := range report.Brokers {
+		stats[broker.BrokerID] = broker
+	}
+	require.Contains(t, stats, "broker-3")
+	assert.Equal(t, 1, stats["broker-1"].OnTime)
+	assert.Equal(t, 1.0, stats["broker-1"].ComplianceRate)
+	assert.Equal(t, 1, stats["broker-2"].Late)
+	assert.Equal(t, 0.0, stats["broker-2"].ComplianceRate)
+	assert.Equal(t, 1, stats["broker-3"].Breached)
+	assert.Equal(t, 0, stats["broker-3"].Assigned) // Reassigned away
+
+	assert.Equal(t, 1, report.Total.OnTime)
+	assert.Equal(t, 1, report.Total.Late)
+	assert.Equal(t, 1, report.Total.Breached)
+	assert.Equal(t, 1, report.Total.Pending)
+	assert.InDelta(t, 1.0/3, report.Total.ComplianceRate, 0.001)
+
+	_, err = service.GetSLAReport(ctx, "tenant-1", time.Now(), from)
+	assert.Error(t, err)
+}
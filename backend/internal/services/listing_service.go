@@ -9,22 +9,28 @@ import (
 	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
 )
 
+// PropertyIndexer refreshes a property's full-text index entry (implemented by PropertyService)
+type PropertyIndexer interface {
+	ReindexProperty(ctx context.Context, tenantID, propertyID string) error
+}
+
 // ListingService handles business logic for listing management with canonical logic
 type ListingService struct {
-	listingRepo     *repositories.ListingRepository
-	propertyRepo    *repositories.PropertyRepository
-	brokerRepo      *repositories.BrokerRepository
-	tenantRepo      *repositories.TenantRepository
-	activityLogRepo *repositories.ActivityLogRepository
+	listingRepo     repositories.ListingStore
+	propertyRepo    repositories.PropertyStore
+	brokerRepo      repositories.BrokerStore
+	tenantRepo      repositories.TenantStore
+	activityLogRepo repositories.ActivityLogStore
+	propertyIndexer PropertyIndexer // optional: keeps canonical title/description searchable
 }
 
 // NewListingService creates a new listing service
 func NewListingService(
-	listingRepo *repositories.ListingRepository,
-	propertyRepo *repositories.PropertyRepository,
-	brokerRepo *repositories.BrokerRepository,
-	tenantRepo *repositories.TenantRepository,
-	activityLogRepo *repositories.ActivityLogRepository,
+	listingRepo repositories.ListingStore,
+	propertyRepo repositories.PropertyStore,
+	brokerRepo repositories.BrokerStore,
+	tenantRepo repositories.TenantStore,
+	activityLogRepo repositories.ActivityLogStore,
 ) *ListingService {
 	return &ListingService{
 		listingRepo:     listingRepo,
@@ -35,6 +41,19 @@ func NewListingService(
 	}
 }
 
+// SetPropertyIndexer sets the indexer notified when a canonical listing changes
+func (s *ListingService) SetPropertyIndexer(indexer PropertyIndexer) {
+	s.propertyIndexer = indexer
+}
+
+// reindexProperty refreshes the property's search entry (best effort)
+func (s *ListingService) reindexProperty(ctx context.Context, tenantID, propertyID string) {
+	if s.propertyIndexer == nil {
+		return
+	}
+	_ = s.propertyIndexer.ReindexProperty(ctx, tenantID, propertyID)
+}
+
 // CreateListing creates a new listing with validation and canonical logic
 func (s *ListingService) CreateListing(ctx context.Context, listing *models.Listing) error {
 	// Validate required fields
@@ -114,6 +133,8 @@ func (s *ListingService) CreateListing(ctx context.Context, listing *models.List
 			"listing_id":  listing.ID,
 			"broker_id":   listing.BrokerID,
 		})
+
+		s.reindexProperty(ctx, listing.TenantID, listing.PropertyID)
 	}
 
 	// Log activity
@@ -191,6 +212,11 @@ func (s *ListingService) UpdateListing(ctx context.Context, tenantID, id string,
 		return fmt.Errorf("failed to update listing: %w", err)
 	}
 
+	// Title/description of the canonical listing are part of the property search entry
+	if existing.IsCanonical {
+		s.reindexProperty(ctx, tenantID, existing.PropertyID)
+	}
+
 	// Log activity
 	_ = s.logActivity(ctx, tenantID, "listing_updated", models.ActorTypeSystem, "", map[string]interface{}{
 		"listing_id":  id,
@@ -254,6 +280,10 @@ func (s *ListingService) DeleteListing(ctx context.Context, tenantID, id string)
 		return fmt.Errorf("failed to delete listing: %w", err)
 	}
 
+	if existing.IsCanonical {
+		s.reindexProperty(ctx, tenantID, existing.PropertyID)
+	}
+
 	// Log activity
 	_ = s.logActivity(ctx, tenantID, "listing_deleted", models.ActorTypeSystem, "", map[string]interface{}{
 		"listing_id":   id,
@@ -312,6 +342,8 @@ func (s *ListingService) SetCanonical(ctx context.Context, tenantID, listingID s
 		return fmt.Errorf("failed to update property canonical listing: %w", err)
 	}
 
+	s.reindexProperty(ctx, tenantID, listing.PropertyID)
+
 	// Log activity
 	metadata := map[string]interface{}{
 		"property_id":     listing.PropertyID,
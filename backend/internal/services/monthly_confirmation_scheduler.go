@@ -7,22 +7,27 @@ import (
 	"time"
 
 	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/notify"
 	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
 )
 
 // MonthlyConfirmationScheduler handles automatic monthly confirmation reminders
 type MonthlyConfirmationScheduler struct {
-	scheduledConfirmationRepo *repositories.ScheduledConfirmationRepository
-	propertyRepo              *repositories.PropertyRepository
-	ownerRepo                 *repositories.OwnerRepository
+	scheduledConfirmationRepo repositories.ScheduledConfirmationStore
+	propertyRepo              repositories.PropertyStore
+	ownerRepo                 repositories.OwnerStore
 	ownerConfirmationService  *OwnerConfirmationService
+	tenantRepo                repositories.TenantStore // Optional - channel preference, templates and reminder steps
+	notifier                  *notify.Dispatcher       // Optional - manual delivery when nil
+	brokerRepo                repositories.BrokerStore // Optional - notify_captador reminder step
+	propertyService           *PropertyService         // Optional - flag_pending reminder step
 }
 
 // NewMonthlyConfirmationScheduler creates a new monthly confirmation scheduler
 func NewMonthlyConfirmationScheduler(
-	scheduledConfirmationRepo *repositories.ScheduledConfirmationRepository,
-	propertyRepo *repositories.PropertyRepository,
-	ownerRepo *repositories.OwnerRepository,
+	scheduledConfirmationRepo repositories.ScheduledConfirmationStore,
+	propertyRepo repositories.PropertyStore,
+	ownerRepo repositories.OwnerStore,
 	ownerConfirmationService *OwnerConfirmationService,
 ) *MonthlyConfirmationScheduler {
 	return &MonthlyConfirmationScheduler{
@@ -65,110 +70,116 @@ func (s *MonthlyConfirmationScheduler) ScheduleMonthlyConfirmations(ctx context.
 
 	log.Printf("🗓️  Scheduling monthly confirmations for tenant %s on %s", req.TenantID, req.ScheduledFor.Format("2006-01-02"))
 
-	// Get all properties for the tenant
-	properties, err := s.propertyRepo.List(ctx, req.TenantID, &repositories.PropertyFilters{}, repositories.PaginationOptions{})
-	if err != nil {
-		return nil, fmt.Errorf("failed to list properties: %w", err)
-	}
-
 	response := &ScheduleMonthlyConfirmationsResponse{
-		TotalProperties:  len(properties),
 		ScheduledForDate: req.ScheduledFor.Format("2006-01-02 15:04:05"),
 		SkippedReasons:   []string{},
 	}
 
-	// Check if confirmations already exist for this month
-	existingConfirmations, err := s.scheduledConfirmationRepo.GetByPropertyAndMonth(
+	policy := s.ownerConfirmationService.tenantPolicy(ctx, req.TenantID)
+
+	// Go through every property of the tenant, not only the first page
+	err := forEachProperty(ctx, s.propertyRepo, req.TenantID, func(property *models.Property) error {
+		response.TotalProperties++
+		s.scheduleProperty(ctx, req, policy, property, response)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("📊 Scheduling complete: %d scheduled, %d skipped out of %d total properties",
+		response.ScheduledCount, response.SkippedCount, response.TotalProperties)
+
+	return response, nil
+}
+
+// scheduleProperty schedules the monthly confirmation of one property,
+// recording the outcome in response
+func (s *MonthlyConfirmationScheduler) scheduleProperty(ctx context.Context, req ScheduleMonthlyConfirmationsRequest, policy models.TenantPolicy, property *models.Property, response *ScheduleMonthlyConfirmationsResponse) {
+	// Skip if property has no owner
+	if property.OwnerID == "" {
+		response.SkippedCount++
+		response.SkippedReasons = append(response.SkippedReasons, fmt.Sprintf("Property %s: no owner", property.Reference))
+		return
+	}
+
+	// Skip if already scheduled for this month, so reruns are idempotent
+	existing, err := s.scheduledConfirmationRepo.GetByPropertyAndMonth(
 		ctx,
 		req.TenantID,
-		"", // Will check per property
+		property.ID,
 		req.ScheduledFor.Year(),
 		req.ScheduledFor.Month(),
 	)
 	if err != nil {
-		log.Printf("⚠️  Warning: failed to check existing confirmations: %v", err)
+		log.Printf("⚠️  Warning: failed to check existing confirmations for property %s: %v", property.Reference, err)
 	}
-
-	// Create a map for quick lookup
-	existingByProperty := make(map[string]bool)
-	for _, ec := range existingConfirmations {
-		existingByProperty[ec.PropertyID] = true
+	if len(existing) > 0 {
+		response.SkippedCount++
+		response.SkippedReasons = append(response.SkippedReasons, fmt.Sprintf("Property %s: already scheduled", property.Reference))
+		return
 	}
 
-	for _, property := range properties {
-		// Skip if property has no owner
-		if property.OwnerID == "" {
-			response.SkippedCount++
-			response.SkippedReasons = append(response.SkippedReasons, fmt.Sprintf("Property %s: no owner", property.Reference))
-			continue
-		}
-
-		// Skip if already scheduled for this month
-		if existingByProperty[property.ID] {
-			response.SkippedCount++
-			response.SkippedReasons = append(response.SkippedReasons, fmt.Sprintf("Property %s: already scheduled", property.Reference))
-			continue
-		}
-
-		// Skip if property status is not available or pending_confirmation
-		if property.Status != models.PropertyStatusAvailable && property.Status != models.PropertyStatusPendingConfirmation {
-			response.SkippedCount++
-			response.SkippedReasons = append(response.SkippedReasons, fmt.Sprintf("Property %s: status is %s", property.Reference, property.Status))
-			continue
-		}
-
-		if req.DryRun {
-			response.ScheduledCount++
-			continue
-		}
+	// Skip if the status was confirmed recently (tenant reminder interval)
+	if property.StatusConfirmedAt != nil && req.ScheduledFor.Sub(*property.StatusConfirmedAt) < policy.ReminderInterval() {
+		response.SkippedCount++
+		response.SkippedReasons = append(response.SkippedReasons, fmt.Sprintf("Property %s: recently confirmed", property.Reference))
+		return
+	}
 
-		// Generate confirmation token and link
-		confirmationURL, tokenID, expiresAt, err := s.ownerConfirmationService.GenerateOwnerConfirmationLink(
-			ctx,
-			req.TenantID,
-			property.ID,
-			property.CaptadorID, // actorID is the broker who captured the property
-			&property.OwnerID,   // ownerID as pointer
-			"whatsapp",
-		)
-		if err != nil {
-			log.Printf("❌ Failed to generate confirmation link for property %s: %v", property.Reference, err)
-			response.SkippedCount++
-			response.SkippedReasons = append(response.SkippedReasons, fmt.Sprintf("Property %s: failed to generate link", property.Reference))
-			continue
-		}
+	// Skip if property status is not available or pending_confirmation
+	if property.Status != models.PropertyStatusAvailable && property.Status != models.PropertyStatusPendingConfirmation {
+		response.SkippedCount++
+		response.SkippedReasons = append(response.SkippedReasons, fmt.Sprintf("Property %s: status is %s", property.Reference, property.Status))
+		return
+	}
 
-		// Create scheduled confirmation record
-		scheduledConfirmation := &models.ScheduledConfirmation{
-			TenantID:        req.TenantID,
-			PropertyID:      property.ID,
-			OwnerID:         property.OwnerID,
-			BrokerID:        property.CaptadorID,
-			TokenID:         tokenID,
-			ConfirmationURL: confirmationURL,
-			ScheduledFor:    req.ScheduledFor,
-			Status:          models.ScheduledConfirmationStatusPending,
-			DeliveryMethod:  "manual", // Will be updated when WhatsApp API is integrated
-		}
+	if req.DryRun {
+		response.ScheduledCount++
+		return
+	}
 
-		if err := s.scheduledConfirmationRepo.Create(ctx, scheduledConfirmation); err != nil {
-			log.Printf("❌ Failed to create scheduled confirmation for property %s: %v", property.Reference, err)
-			response.SkippedCount++
-			response.SkippedReasons = append(response.SkippedReasons, fmt.Sprintf("Property %s: failed to save", property.Reference))
-			continue
-		}
+	// Generate confirmation token and link
+	confirmationURL, tokenID, expiresAt, err := s.ownerConfirmationService.GenerateOwnerConfirmationLink(
+		ctx,
+		req.TenantID,
+		property.ID,
+		property.CaptadorID, // actorID is the broker who captured the property
+		&property.OwnerID,   // ownerID as pointer
+		"whatsapp",
+	)
+	if err != nil {
+		log.Printf("❌ Failed to generate confirmation link for property %s: %v", property.Reference, err)
+		response.SkippedCount++
+		response.SkippedReasons = append(response.SkippedReasons, fmt.Sprintf("Property %s: failed to generate link", property.Reference))
+		return
+	}
 
-		response.ScheduledCount++
-		response.ScheduledConfirmIDs = append(response.ScheduledConfirmIDs, scheduledConfirmation.ID)
+	// Create scheduled confirmation record
+	scheduledConfirmation := &models.ScheduledConfirmation{
+		TenantID:        req.TenantID,
+		PropertyID:      property.ID,
+		OwnerID:         property.OwnerID,
+		BrokerID:        property.CaptadorID,
+		TokenID:         tokenID,
+		ConfirmationURL: confirmationURL,
+		ScheduledFor:    req.ScheduledFor,
+		Status:          models.ScheduledConfirmationStatusPending,
+		DeliveryMethod:  "manual", // Set to the channel used when it is sent
+	}
 
-		log.Printf("✅ Scheduled confirmation for property %s (owner: %s, expires: %s)",
-			property.Reference, property.OwnerID, expiresAt.Format("2006-01-02"))
+	if err := s.scheduledConfirmationRepo.Create(ctx, scheduledConfirmation); err != nil {
+		log.Printf("❌ Failed to create scheduled confirmation for property %s: %v", property.Reference, err)
+		response.SkippedCount++
+		response.SkippedReasons = append(response.SkippedReasons, fmt.Sprintf("Property %s: failed to save", property.Reference))
+		return
 	}
 
-	log.Printf("📊 Scheduling complete: %d scheduled, %d skipped out of %d total properties",
-		response.ScheduledCount, response.SkippedCount, response.TotalProperties)
+	response.ScheduledCount++
+	response.ScheduledConfirmIDs = append(response.ScheduledConfirmIDs, scheduledConfirmation.ID)
 
-	return response, nil
+	log.Printf("✅ Scheduled confirmation for property %s (owner: %s, expires: %s)",
+		property.Reference, property.OwnerID, expiresAt.Format("2006-01-02"))
 }
 
 // ProcessPendingConfirmations processes all pending confirmations for today
@@ -192,21 +203,18 @@ func (s *MonthlyConfirmationScheduler) ProcessPendingConfirmations(ctx context.C
 	successCount := 0
 	failCount := 0
 
-	for _, sc := range pendingConfirmations {
-		// TODO: When WhatsApp Business API is integrated, send message here
-		// For now, just mark as "sent" (manual delivery)
+	tenant := s.loadTenant(ctx, tenantID)
 
-		// Get owner info for logging
+	for _, sc := range pendingConfirmations {
 		owner, err := s.ownerRepo.Get(ctx, tenantID, sc.OwnerID)
 		if err != nil {
 			log.Printf("⚠️  Warning: could not get owner info for %s: %v", sc.OwnerID, err)
+			owner = nil
 		}
 
-		// Update status to sent
-		now := time.Now()
-		sc.Status = models.ScheduledConfirmationStatusSent
-		sc.SentAt = &now
-		sc.DeliveryStatus = "manual_delivery_required"
+		// Send through the tenant's channels, or leave it for manual delivery
+		s.deliver(ctx, tenant, sc, owner)
+		scheduleNextReminder(sc, tenant.EffectivePolicy())
 
 		if err := s.scheduledConfirmationRepo.Update(ctx, sc); err != nil {
 			log.Printf("❌ Failed to update scheduled confirmation %s: %v", sc.ID, err)
@@ -214,12 +222,18 @@ func (s *MonthlyConfirmationScheduler) ProcessPendingConfirmations(ctx context.C
 			continue
 		}
 
+		if sc.Status == models.ScheduledConfirmationStatusFailed {
+			log.Printf("❌ Failed to deliver confirmation for property %s: %s", sc.PropertyID, sc.DeliveryError)
+			failCount++
+			continue
+		}
+
 		successCount++
 		ownerName := "Unknown"
 		if owner != nil {
 			ownerName = owner.Name
 		}
-		log.Printf("✅ Marked confirmation as ready for property %s (owner: %s)", sc.PropertyID, ownerName)
+		log.Printf("✅ Confirmation for property %s ready (owner: %s, delivery: %s)", sc.PropertyID, ownerName, sc.DeliveryMethod)
 	}
 
 	log.Printf("📊 Processing complete: %d successful, %d failed", successCount, failCount)
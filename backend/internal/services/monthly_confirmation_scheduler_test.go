@@ -1,10 +1,14 @@
 package services
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories/memory"
 )
 
 func TestScheduleMonthlyConfirmationsRequest_DefaultScheduledDate(t *testing.T) {
@@ -189,3 +193,34 @@ func TestRequestValidation_TenantIDRequired(t *testing.T) {
 
 	assert.NotEmpty(t, validReq.TenantID, "Valid tenant ID should be present")
 }
+
+func TestScheduleMonthlyConfirmations_IsIdempotent(t *testing.T) {
+	ctx := context.Background()
+	propertyService, ownerConfirmationService := newHistoryTestServices(t)
+	for i := 0; i < 3; i++ {
+		createHistoryTestProperty(t, propertyService)
+	}
+
+	scheduler := NewMonthlyConfirmationScheduler(
+		memory.NewScheduledConfirmationRepository(),
+		propertyService.propertyRepo,
+		memory.NewOwnerRepository(),
+		ownerConfirmationService,
+	)
+	req := ScheduleMonthlyConfirmationsRequest{
+		TenantID:     "tenant-1",
+		ScheduledFor: time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC),
+	}
+
+	response, err := scheduler.ScheduleMonthlyConfirmations(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, 3, response.TotalProperties)
+	assert.Equal(t, 3, response.ScheduledCount)
+
+	// A second run in the same month skips every property
+	response, err = scheduler.ScheduleMonthlyConfirmations(ctx, req)
+	require.NoError(t, err)
+	assert.Zero(t, response.ScheduledCount)
+	assert.Equal(t, 3, response.SkippedCount)
+	assert.Contains(t, response.SkippedReasons[0], "already scheduled")
+}
@@ -14,22 +14,28 @@ import (
 
 // OwnerConfirmationService handles owner confirmation token logic
 type OwnerConfirmationService struct {
-	tokenRepo       *repositories.OwnerConfirmationTokenRepository
-	propertyRepo    *repositories.PropertyRepository
-	ownerRepo       *repositories.OwnerRepository
-	brokerRepo      *repositories.BrokerRepository
-	listingRepo     *repositories.ListingRepository
-	activityLogRepo *repositories.ActivityLogRepository
+	tokenRepo       repositories.OwnerConfirmationTokenStore
+	propertyRepo    repositories.PropertyStore
+	ownerRepo       repositories.OwnerStore
+	brokerRepo      repositories.BrokerStore
+	listingRepo     repositories.ListingStore
+	activityLogRepo repositories.ActivityLogStore
+	portalFeed      *PortalFeedService                // Optional - VRSync portal feeds
+	propertyIndexer PropertyIndexer                   // Optional - full-text search index
+	historyRepo     repositories.PropertyHistoryStore // Optional - price/status history
+	tenantRepo      repositories.TenantStore          // Optional - tenant policy (defaults when nil)
+
+	scheduledConfirmationRepo repositories.ScheduledConfirmationStore // Optional - stops reminders on response
 }
 
 // NewOwnerConfirmationService creates a new owner confirmation service
 func NewOwnerConfirmationService(
-	tokenRepo *repositories.OwnerConfirmationTokenRepository,
-	propertyRepo *repositories.PropertyRepository,
-	ownerRepo *repositories.OwnerRepository,
-	brokerRepo *repositories.BrokerRepository,
-	listingRepo *repositories.ListingRepository,
-	activityLogRepo *repositories.ActivityLogRepository,
+	tokenRepo repositories.OwnerConfirmationTokenStore,
+	propertyRepo repositories.PropertyStore,
+	ownerRepo repositories.OwnerStore,
+	brokerRepo repositories.BrokerStore,
+	listingRepo repositories.ListingStore,
+	activityLogRepo repositories.ActivityLogStore,
 ) *OwnerConfirmationService {
 	return &OwnerConfirmationService{
 		tokenRepo:       tokenRepo,
@@ -68,8 +74,8 @@ func (s *OwnerConfirmationService) GenerateOwnerConfirmationLink(
 	hash := sha256.Sum256([]byte(token))
 	tokenHash := fmt.Sprintf("%x", hash)
 
-	// Set expiration (7 days from now)
-	expiresAt := time.Now().Add(7 * 24 * time.Hour)
+	// Set expiration (tenant policy, 7 days by default)
+	expiresAt := time.Now().Add(s.tenantPolicy(ctx, tenantID).ConfirmationTokenTTL())
 
 	// Get owner snapshot if owner exists and is not incomplete
 	var ownerSnapshot *models.OwnerSnapshotMinimal
@@ -328,14 +334,63 @@ func (s *OwnerConfirmationService) SubmitOwnerConfirmation(
 		return fmt.Errorf("failed to mark token as used: %w", err)
 	}
 
+	// Track price/status changes (the token's owner, when known, is the actor)
+	change := propertyChange{
+		actorType: models.ActorTypeOwner,
+		source:    models.PropertyChangeSourceOwnerLink,
+	}
+	if confirmationToken.OwnerID != nil {
+		change.actorID = *confirmationToken.OwnerID
+	}
+	history := trackPropertyChanges(property, updates, change, now)
+
 	// Update property
 	if err := s.propertyRepo.Update(ctx, tenantID, property.ID, updates); err != nil {
 		return fmt.Errorf("failed to update property: %w", err)
 	}
+	recordPropertyHistory(ctx, s.historyRepo, history)
+	if s.propertyIndexer != nil {
+		// Also marks the portal feed entry stale
+		_ = s.propertyIndexer.ReindexProperty(ctx, tenantID, property.ID)
+	} else if s.portalFeed != nil {
+		s.portalFeed.InvalidateProperty(tenantID, property.ID)
+	}
+	markScheduledConfirmationResponded(ctx, s.scheduledConfirmationRepo, tenantID, confirmationToken.ID, action, now)
 
 	return nil
 }
 
+// SetPortalFeed sets the portal feed service notified of property changes (optional)
+func (s *OwnerConfirmationService) SetPortalFeed(feed *PortalFeedService) {
+	s.portalFeed = feed
+}
+
+// SetPropertyIndexer sets the indexer notified of property changes (optional)
+func (s *OwnerConfirmationService) SetPropertyIndexer(indexer PropertyIndexer) {
+	s.propertyIndexer = indexer
+}
+
+// SetHistoryRepository enables the price/status history of properties (optional)
+func (s *OwnerConfirmationService) SetHistoryRepository(historyRepo repositories.PropertyHistoryStore) {
+	s.historyRepo = historyRepo
+}
+
+// SetTenantRepository enables the per-tenant confirmation policy (optional)
+func (s *OwnerConfirmationService) SetTenantRepository(tenantRepo repositories.TenantStore) {
+	s.tenantRepo = tenantRepo
+}
+
+// SetScheduledConfirmationRepository records owner responses on the monthly
+// scheduled confirmations, stopping their reminders (optional)
+func (s *OwnerConfirmationService) SetScheduledConfirmationRepository(repo repositories.ScheduledConfirmationStore) {
+	s.scheduledConfirmationRepo = repo
+}
+
+// tenantPolicy returns the staleness and confirmation policy of a tenant
+func (s *OwnerConfirmationService) tenantPolicy(ctx context.Context, tenantID string) models.TenantPolicy {
+	return loadTenantPolicy(ctx, s.tenantRepo, tenantID)
+}
+
 // logActivity logs an activity (helper method)
 func (s *OwnerConfirmationService) logActivity(
 	ctx context.Context,
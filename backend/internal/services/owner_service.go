@@ -12,16 +12,16 @@ import (
 
 // OwnerService handles business logic for owner management with LGPD compliance
 type OwnerService struct {
-	ownerRepo       *repositories.OwnerRepository
-	tenantRepo      *repositories.TenantRepository
-	activityLogRepo *repositories.ActivityLogRepository
+	ownerRepo       repositories.OwnerStore
+	tenantRepo      repositories.TenantStore
+	activityLogRepo repositories.ActivityLogStore
 }
 
 // NewOwnerService creates a new owner service
 func NewOwnerService(
-	ownerRepo *repositories.OwnerRepository,
-	tenantRepo *repositories.TenantRepository,
-	activityLogRepo *repositories.ActivityLogRepository,
+	ownerRepo repositories.OwnerStore,
+	tenantRepo repositories.TenantStore,
+	activityLogRepo repositories.ActivityLogStore,
 ) *OwnerService {
 	return &OwnerService{
 		ownerRepo:       ownerRepo,
@@ -0,0 +1,278 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/firestore"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/adapters/vrsync"
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// portalFeedTTL is how long a tenant's cached feed is served before it is
+// rebuilt from the repositories. InvalidateProperty only reaches the cache of
+// the instance that made the change, so the TTL bounds how stale the feeds
+// served by the other replicas can get.
+const portalFeedTTL = 10 * time.Minute
+
+// PortalFeedService generates the per-tenant VRSync feeds read by ZAP Imóveis,
+// VivaReal and OLX. Rendered <Listing> entries are cached per tenant: the first
+// request builds the whole feed, afterwards property and listing changes mark
+// single entries stale (InvalidateProperty) and only those are re-rendered,
+// until the feed is older than portalFeedTTL and is built again.
+type PortalFeedService struct {
+	propertyRepo repositories.PropertyStore
+	listingRepo  repositories.ListingStore
+	tenantRepo   repositories.TenantStore
+
+	mu    sync.Mutex
+	feeds map[string]*tenantFeed // by tenant ID
+}
+
+// tenantFeed is the cached feed of a tenant
+type tenantFeed struct {
+	mu      sync.Mutex            // serializes refreshes; entries are guarded by it
+	builtAt time.Time             // zero until the first build
+	entries map[string]*feedEntry // by property ID
+
+	stale map[string]struct{} // property IDs to re-render, guarded by PortalFeedService.mu
+}
+
+// feedEntry is a rendered property of the feed
+type feedEntry struct {
+	propertyID string
+	createdAt  time.Time
+	portals    models.PortalFlags
+	xml        []byte
+}
+
+// NewPortalFeedService creates a new portal feed service
+func NewPortalFeedService(
+	propertyRepo repositories.PropertyStore,
+	listingRepo repositories.ListingStore,
+	tenantRepo repositories.TenantStore,
+) *PortalFeedService {
+	return &PortalFeedService{
+		propertyRepo: propertyRepo,
+		listingRepo:  listingRepo,
+		tenantRepo:   tenantRepo,
+		feeds:        make(map[string]*tenantFeed),
+	}
+}
+
+// InvalidateProperty marks a property's feed entry stale. It is re-rendered
+// (or dropped) on the next feed request of its tenant.
+func (s *PortalFeedService) InvalidateProperty(tenantID, propertyID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if feed, ok := s.feeds[tenantID]; ok {
+		feed.stale[propertyID] = struct{}{}
+	}
+}
+
+// RenderFeed returns the VRSync XML feed of a tenant for a portal
+func (s *PortalFeedService) RenderFeed(ctx context.Context, tenantID string, portal models.Portal) ([]byte, error) {
+	if !models.IsValidPortal(portal) {
+		return nil, fmt.Errorf("%w: unknown portal %q", repositories.ErrInvalidInput, portal)
+	}
+
+	tenant, err := s.tenantRepo.Get(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("tenant not found: %w", err)
+	}
+	if !tenant.IsActive {
+		return nil, fmt.Errorf("tenant not found: %w", repositories.ErrNotFound)
+	}
+
+	contact := &vrsync.ContactInfo{Name: tenant.Name, Email: tenant.Email, Telephone: tenant.Phone}
+
+	feed := s.tenantFeed(tenantID)
+	feed.mu.Lock()
+	defer feed.mu.Unlock()
+
+	if err := s.refresh(ctx, tenantID, feed, contact); err != nil {
+		return nil, err
+	}
+
+	entries := make([]*feedEntry, 0, len(feed.entries))
+	for _, entry := range feed.entries {
+		if entry.portals.Includes(portal) {
+			entries = append(entries, entry)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].createdAt.Equal(entries[j].createdAt) {
+			return entries[i].createdAt.Before(entries[j].createdAt)
+		}
+		return entries[i].propertyID < entries[j].propertyID
+	})
+
+	listings := make([][]byte, len(entries))
+	for i, entry := range entries {
+		listings[i] = entry.xml
+	}
+
+	var buf bytes.Buffer
+	header := vrsync.Header{
+		Provider:    tenant.Name,
+		Email:       tenant.Email,
+		ContactName: tenant.Name,
+		PublishDate: time.Now().UTC().Format(time.RFC3339),
+		Telephone:   tenant.Phone,
+	}
+	if err := vrsync.WriteFeed(&buf, header, listings); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// tenantFeed returns the cached feed of a tenant, creating an empty one
+func (s *PortalFeedService) tenantFeed(tenantID string) *tenantFeed {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	feed, ok := s.feeds[tenantID]
+	if !ok {
+		feed = &tenantFeed{
+			entries: make(map[string]*feedEntry),
+			stale:   make(map[string]struct{}),
+		}
+		s.feeds[tenantID] = feed
+	}
+	return feed
+}
+
+// refresh builds the feed on first use and once it expires, otherwise it
+// re-renders the stale entries. Must be called with feed.mu held.
+func (s *PortalFeedService) refresh(ctx context.Context, tenantID string, feed *tenantFeed, contact *vrsync.ContactInfo) error {
+	s.mu.Lock()
+	stale := feed.stale
+	feed.stale = make(map[string]struct{})
+	s.mu.Unlock()
+
+	if feed.builtAt.IsZero() || time.Since(feed.builtAt) > portalFeedTTL {
+		builtAt := time.Now()
+		if err := s.build(ctx, tenantID, feed, contact); err != nil {
+			s.restoreStale(feed, stale)
+			return err
+		}
+		feed.builtAt = builtAt
+		return nil
+	}
+
+	for propertyID := range stale {
+		property, err := s.propertyRepo.Get(ctx, tenantID, propertyID)
+		if err != nil {
+			if errors.Is(err, repositories.ErrNotFound) {
+				delete(feed.entries, propertyID)
+				continue
+			}
+			s.restoreStale(feed, stale)
+			return fmt.Errorf("failed to load property %s: %w", propertyID, err)
+		}
+		s.render(ctx, feed, property, contact)
+	}
+	return nil
+}
+
+// build renders every property of the tenant
+func (s *PortalFeedService) build(ctx context.Context, tenantID string, feed *tenantFeed, contact *vrsync.ContactInfo) error {
+	const pageSize = 200
+	feed.entries = make(map[string]*feedEntry)
+
+	for offset := 0; ; offset += pageSize {
+		properties, err := s.propertyRepo.List(ctx, tenantID, nil, repositories.PaginationOptions{
+			Limit:     pageSize,
+			Offset:    offset,
+			OrderBy:   "created_at",
+			Direction: firestore.Asc,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list properties: %w", err)
+		}
+
+		for _, property := range properties {
+			s.render(ctx, feed, property, contact)
+		}
+
+		if len(properties) < pageSize {
+			return nil
+		}
+	}
+}
+
+// render stores the property's feed entry, or drops it when the property is
+// not syndicated to any portal or has no canonical listing to advertise
+func (s *PortalFeedService) render(ctx context.Context, feed *tenantFeed, property *models.Property, contact *vrsync.ContactInfo) {
+	delete(feed.entries, property.ID)
+
+	portals := models.PortalFlags{
+		ZAP:      property.IsSyndicated(models.PortalZAP),
+		VivaReal: property.IsSyndicated(models.PortalVivaReal),
+		OLX:      property.IsSyndicated(models.PortalOLX),
+	}
+	if !portals.Any() || property.CanonicalListingID == "" {
+		return
+	}
+
+	listing, err := s.listingRepo.Get(ctx, property.TenantID, property.CanonicalListingID)
+	if err != nil {
+		log.Printf("⚠️  Portal feed: skipping property %s, canonical listing unavailable: %v", property.ID, err)
+		return
+	}
+
+	out, err := vrsync.MarshalListing(vrsync.ListingFromProperty(property, listing.Title, listing.Description, feedPhotoURLs(listing.Photos), contact))
+	if err != nil {
+		log.Printf("⚠️  Portal feed: skipping property %s: %v", property.ID, err)
+		return
+	}
+
+	feed.entries[property.ID] = &feedEntry{
+		propertyID: property.ID,
+		createdAt:  property.CreatedAt,
+		portals:    portals,
+		xml:        out,
+	}
+}
+
+// restoreStale puts back stale entries of a failed refresh
+func (s *PortalFeedService) restoreStale(feed *tenantFeed, stale map[string]struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for propertyID := range stale {
+		feed.stale[propertyID] = struct{}{}
+	}
+}
+
+// feedPhotoURLs returns the largest photo URLs, cover first, then by order
+func feedPhotoURLs(photos []models.Photo) []string {
+	sorted := make([]models.Photo, len(photos))
+	copy(sorted, photos)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].IsCover != sorted[j].IsCover {
+			return sorted[i].IsCover
+		}
+		return sorted[i].Order < sorted[j].Order
+	})
+
+	urls := make([]string, 0, len(sorted))
+	for _, photo := range sorted {
+		url := photo.LargeURL
+		if url == "" {
+			url = photo.URL
+		}
+		if url != "" {
+			urls = append(urls, url)
+		}
+	}
+	return urls
+}
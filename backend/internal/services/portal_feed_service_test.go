@@ -0,0 +1,198 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/adapters/vrsync"
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories/memory"
+	"github.com/altatech/ecosistema-imob/backend/internal/search"
+)
+
+// newPortalFeedTestServices wires property, listing and portal feed services over the in-memory backend
+func newPortalFeedTestServices(t *testing.T) (*PropertyService, *ListingService, *PortalFeedService) {
+	t.Helper()
+	ctx := context.Background()
+
+	tenantRepo := memory.NewTenantRepository()
+	ownerRepo := memory.NewOwnerRepository()
+	brokerRepo := memory.NewBrokerRepository()
+	propertyRepo := memory.NewPropertyRepository()
+	listingRepo := memory.NewListingRepository()
+	activityLogRepo := memory.NewActivityLogRepository()
+
+	require.NoError(t, tenantRepo.Create(ctx, &models.Tenant{ID: "tenant-1", Name: "Imobiliária Teste", Email: "contato@teste.com.br", IsActive: true}))
+	require.NoError(t, ownerRepo.Create(ctx, &models.Owner{ID: "owner-1", TenantID: "tenant-1", Name: "Maria"}))
+	require.NoError(t, brokerRepo.Create(ctx, &models.Broker{ID: "broker-1", TenantID: "tenant-1", Name: "João"}))
+
+	portalFeed := NewPortalFeedService(propertyRepo, listingRepo, tenantRepo)
+
+	propertyService := NewPropertyService(propertyRepo, listingRepo, ownerRepo, brokerRepo, tenantRepo, activityLogRepo)
+	propertyService.SetSearchIndex(search.NewIndex())
+	propertyService.SetPortalFeed(portalFeed)
+
+	listingService := NewListingService(listingRepo, propertyRepo, brokerRepo, tenantRepo, activityLogRepo)
+	listingService.SetPropertyIndexer(propertyService)
+
+	return propertyService, listingService, portalFeed
+}
+
+func createFeedTestProperty(t *testing.T, propertyService *PropertyService, listingService *ListingService, reference string, portals models.PortalFlags) *models.Property {
+	t.Helper()
+	ctx := context.Background()
+
+	property := &models.Property{
+		TenantID:     "tenant-1",
+		OwnerID:      "owner-1",
+		PropertyType: models.PropertyTypeApartment,
+		Reference:    reference,
+		Street:       "Rua dos Pinheiros",
+		Number:       "100",
+		Neighborhood: "Pinheiros",
+		City:         "São Paulo",
+		State:        "SP",
+		PriceAmount:  750000,
+		Status:       models.PropertyStatusAvailable,
+		Visibility:   models.PropertyVisibilityPublic,
+		Portals:      portals,
+	}
+	require.NoError(t, propertyService.CreateProperty(ctx, property))
+
+	require.NoError(t, listingService.CreateListing(ctx, &models.Listing{
+		TenantID:    "tenant-1",
+		PropertyID:  property.ID,
+		BrokerID:    "broker-1",
+		Title:       "Apartamento " + reference,
+		Description: "Apartamento reformado próximo ao metrô",
+		IsActive:    true,
+		Photos: []models.Photo{
+			{ID: "p2", URL: "https://cdn.example.com/sala.jpg", Order: 2},
+			{ID: "p1", URL: "https://cdn.example.com/fachada.jpg", Order: 1, IsCover: true},
+		},
+	}))
+
+	return property
+}
+
+func renderFeedListings(t *testing.T, portalFeed *PortalFeedService, portal models.Portal) []vrsync.Listing {
+	t.Helper()
+	out, err := portalFeed.RenderFeed(context.Background(), "tenant-1", portal)
+	require.NoError(t, err)
+
+	feed, err := vrsync.ParseFeed(strings.NewReader(string(out)))
+	require.NoError(t, err)
+	return feed.Listings
+}
+
+func TestPortalFeed_RenderFeed(t *testing.T) {
+	propertyService, listingService, portalFeed := newPortalFeedTestServices(t)
+
+	createFeedTestProperty(t, propertyService, listingService, "AP-1", models.PortalFlags{ZAP: true, VivaReal: true})
+	createFeedTestProperty(t, propertyService, listingService, "AP-2", models.PortalFlags{OLX: true})
+	createFeedTestProperty(t, propertyService, listingService, "AP-3", models.PortalFlags{})
+
+	listings := renderFeedListings(t, portalFeed, models.PortalZAP)
+	require.Len(t, listings, 1)
+	assert.Equal(t, "AP-1", listings[0].ListingID)
+	assert.Equal(t, "Apartamento AP-1", listings[0].Title)
+	assert.Equal(t, "For Sale", listings[0].TransactionType)
+	assert.Equal(t, 750000.0, listings[0].Details.ListPrice.Float())
+	require.Len(t, listings[0].Media, 2)
+	assert.Equal(t, "https://cdn.example.com/fachada.jpg", listings[0].Media[0].URL)
+	assert.True(t, listings[0].Media[0].Primary)
+
+	listings = renderFeedListings(t, portalFeed, models.PortalOLX)
+	require.Len(t, listings, 1)
+	assert.Equal(t, "AP-2", listings[0].ListingID)
+
+	_, err := portalFeed.RenderFeed(context.Background(), "tenant-1", models.Portal("imovelweb"))
+	assert.ErrorIs(t, err, repositories.ErrInvalidInput)
+
+	_, err = portalFeed.RenderFeed(context.Background(), "tenant-2", models.PortalZAP)
+	assert.ErrorIs(t, err, repositories.ErrNotFound)
+}
+
+func TestPortalFeed_IncrementalUpdates(t *testing.T) {
+	ctx := context.Background()
+	propertyService, listingService, portalFeed := newPortalFeedTestServices(t)
+
+	property := createFeedTestProperty(t, propertyService, listingService, "AP-1", models.PortalFlags{ZAP: true})
+	require.Len(t, renderFeedListings(t, portalFeed, models.PortalZAP), 1)
+
+	// Canonical listing changes are re-rendered
+	loaded, err := propertyService.GetProperty(ctx, "tenant-1", property.ID)
+	require.NoError(t, err)
+	require.NoError(t, listingService.UpdateListing(ctx, "tenant-1", loaded.CanonicalListingID, map[string]interface{}{
+		"title": "Cobertura duplex",
+	}))
+
+	listings := renderFeedListings(t, portalFeed, models.PortalZAP)
+	require.Len(t, listings, 1)
+	assert.Equal(t, "Cobertura duplex", listings[0].Title)
+
+	// Portal flags and price changes
+	require.NoError(t, propertyService.UpdatePortals(ctx, "tenant-1", property.ID, models.PortalFlags{ZAP: true, OLX: true}))
+	require.NoError(t, propertyService.UpdateProperty(ctx, "tenant-1", property.ID, "", map[string]interface{}{
+		"price_amount": 800000.0,
+	}))
+
+	listings = renderFeedListings(t, portalFeed, models.PortalOLX)
+	require.Len(t, listings, 1)
+	assert.Equal(t, 800000.0, listings[0].Details.ListPrice.Float())
+
+	// Properties that are no longer public leave every feed
+	require.NoError(t, propertyService.UpdateVisibility(ctx, "tenant-1", property.ID, models.PropertyVisibilityNetwork))
+	assert.Empty(t, renderFeedListings(t, portalFeed, models.PortalZAP))
+	assert.Empty(t, renderFeedListings(t, portalFeed, models.PortalOLX))
+
+	require.NoError(t, propertyService.UpdateVisibility(ctx, "tenant-1", property.ID, models.PropertyVisibilityMarketplace))
+	require.Len(t, renderFeedListings(t, portalFeed, models.PortalZAP), 1)
+
+	require.NoError(t, propertyService.DeleteProperty(ctx, "tenant-1", property.ID))
+	assert.Empty(t, renderFeedListings(t, portalFeed, models.PortalZAP))
+}
+
+func TestPortalFeed_ExpiresOtherInstancesChanges(t *testing.T) {
+	ctx := context.Background()
+	propertyService, listingService, portalFeed := newPortalFeedTestServices(t)
+
+	property := createFeedTestProperty(t, propertyService, listingService, "AP-1", models.PortalFlags{ZAP: true})
+	require.Len(t, renderFeedListings(t, portalFeed, models.PortalZAP), 1)
+
+	// Changes made by another instance do not invalidate this one's cache
+	require.NoError(t, propertyService.propertyRepo.Update(ctx, "tenant-1", property.ID, map[string]interface{}{
+		"portals": models.PortalFlags{},
+	}))
+	require.Len(t, renderFeedListings(t, portalFeed, models.PortalZAP), 1)
+
+	// ... until the cached feed expires
+	portalFeed.tenantFeed("tenant-1").builtAt = time.Now().Add(-portalFeedTTL - time.Minute)
+	assert.Empty(t, renderFeedListings(t, portalFeed, models.PortalZAP))
+}
+
+func TestPropertyService_UpdatePropertyValidatesPortals(t *testing.T) {
+	ctx := context.Background()
+	propertyService, listingService, _ := newPortalFeedTestServices(t)
+
+	property := createFeedTestProperty(t, propertyService, listingService, "AP-1", models.PortalFlags{})
+
+	err := propertyService.UpdateProperty(ctx, "tenant-1", property.ID, "", map[string]interface{}{
+		"portals": map[string]interface{}{"imovelweb": true},
+	})
+	assert.ErrorIs(t, err, repositories.ErrInvalidInput)
+
+	require.NoError(t, propertyService.UpdateProperty(ctx, "tenant-1", property.ID, "", map[string]interface{}{
+		"portals": map[string]interface{}{"vivareal": true},
+	}))
+
+	loaded, err := propertyService.GetProperty(ctx, "tenant-1", property.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.PortalFlags{VivaReal: true}, loaded.Portals)
+}
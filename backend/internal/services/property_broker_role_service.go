@@ -11,20 +11,20 @@ import (
 
 // PropertyBrokerRoleService handles business logic for co-brokerage management
 type PropertyBrokerRoleService struct {
-	roleRepo        *repositories.PropertyBrokerRoleRepository
-	propertyRepo    *repositories.PropertyRepository
-	brokerRepo      *repositories.BrokerRepository
-	tenantRepo      *repositories.TenantRepository
-	activityLogRepo *repositories.ActivityLogRepository
+	roleRepo        repositories.PropertyBrokerRoleStore
+	propertyRepo    repositories.PropertyStore
+	brokerRepo      repositories.BrokerStore
+	tenantRepo      repositories.TenantStore
+	activityLogRepo repositories.ActivityLogStore
 }
 
 // NewPropertyBrokerRoleService creates a new property broker role service
 func NewPropertyBrokerRoleService(
-	roleRepo *repositories.PropertyBrokerRoleRepository,
-	propertyRepo *repositories.PropertyRepository,
-	brokerRepo *repositories.BrokerRepository,
-	tenantRepo *repositories.TenantRepository,
-	activityLogRepo *repositories.ActivityLogRepository,
+	roleRepo repositories.PropertyBrokerRoleStore,
+	propertyRepo repositories.PropertyStore,
+	brokerRepo repositories.BrokerStore,
+	tenantRepo repositories.TenantStore,
+	activityLogRepo repositories.ActivityLogStore,
 ) *PropertyBrokerRoleService {
 	return &PropertyBrokerRoleService{
 		roleRepo:        roleRepo,
@@ -335,8 +335,9 @@ func (s *PropertyBrokerRoleService) GetPrimaryBroker(ctx context.Context, tenant
 	return role, nil
 }
 
-// CalculateCommissionSplit calculates commission split for co-brokerage (MVP+1 preparation)
-// In MVP, this just returns the commission percentages without actual calculation
+// CalculateCommissionSplit returns the commission percentages recorded on the
+// roles of a property. The split of a closed deal, with the tenant rules and
+// amounts, is CommissionService.CalculateSplit
 func (s *PropertyBrokerRoleService) CalculateCommissionSplit(ctx context.Context, tenantID, propertyID string) (map[string]float64, error) {
 	if tenantID == "" {
 		return nil, fmt.Errorf("tenant_id is required")
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// propertyChange identifies who changed a property and through which channel
+type propertyChange struct {
+	actorType models.ActorType
+	actorID   string
+	source    models.PropertyChangeSource
+}
+
+// brokerChange is a change made from the admin panel by actorID; changes
+// without an actor are made by the system on a broker's behalf (ex: a
+// proposal acceptance marking the property unavailable)
+func brokerChange(actorID string) propertyChange {
+	actorType := models.ActorTypeUser
+	if actorID == "" {
+		actorType = models.ActorTypeSystem
+	}
+	return propertyChange{actorType: actorType, actorID: actorID, source: models.PropertyChangeSourceBroker}
+}
+
+// trackPropertyChanges compares the price and status updates against the
+// stored property and returns the history entries describing them. A price
+// change also stores the previous price and the "preço reduzido" percentage
+// in updates, so it must run before the update is persisted.
+func trackPropertyChanges(existing *models.Property, updates map[string]interface{}, change propertyChange, now time.Time) []*models.PropertyHistoryEntry {
+	var entries []*models.PropertyHistoryEntry
+
+	if value, ok := updates["price_amount"]; ok {
+		if newPrice, ok := toFloat64(value); ok && newPrice != existing.PriceAmount {
+			oldPrice := existing.PriceAmount
+			entry := change.entry(existing, models.PropertyHistoryFieldPrice, now)
+			entry.OldPrice = &oldPrice
+			entry.NewPrice = &newPrice
+			entries = append(entries, entry)
+
+			updates["previous_price_amount"] = oldPrice
+			updates["price_changed_at"] = now
+			updates["price_reduced_percent"] = models.PriceReductionPercent(oldPrice, newPrice)
+		}
+	}
+
+	if value, ok := updates["status"]; ok {
+		if newStatus, ok := toPropertyStatus(value); ok && newStatus != existing.Status {
+			entry := change.entry(existing, models.PropertyHistoryFieldStatus, now)
+			entry.OldStatus = existing.Status
+			entry.NewStatus = newStatus
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries
+}
+
+// initialPropertyHistory returns the entries recording the price and status a
+// property was created with (entries without an old value)
+func initialPropertyHistory(property *models.Property, change propertyChange) []*models.PropertyHistoryEntry {
+	var entries []*models.PropertyHistoryEntry
+	now := property.CreatedAt
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	if property.PriceAmount > 0 {
+		price := property.PriceAmount
+		entry := change.entry(property, models.PropertyHistoryFieldPrice, now)
+		entry.NewPrice = &price
+		entries = append(entries, entry)
+	}
+
+	if property.Status != "" {
+		entry := change.entry(property, models.PropertyHistoryFieldStatus, now)
+		entry.NewStatus = property.Status
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+// entry creates a history entry of the change for a property field
+func (c propertyChange) entry(property *models.Property, field models.PropertyHistoryField, now time.Time) *models.PropertyHistoryEntry {
+	return &models.PropertyHistoryEntry{
+		TenantID:   property.TenantID,
+		PropertyID: property.ID,
+		Field:      field,
+		ActorType:  c.actorType,
+		ActorID:    c.actorID,
+		Source:     c.source,
+		ChangedAt:  now,
+	}
+}
+
+// recordPropertyHistory appends history entries (best effort: the property
+// update already succeeded, so failures are only logged). A nil repository
+// disables history.
+func recordPropertyHistory(ctx context.Context, historyRepo repositories.PropertyHistoryStore, entries []*models.PropertyHistoryEntry) {
+	if historyRepo == nil {
+		return
+	}
+	for _, entry := range entries {
+		if err := historyRepo.Create(ctx, entry); err != nil {
+			log.Printf("⚠️  Failed to record %s history for property %s: %v", entry.Field, entry.PropertyID, err)
+		}
+	}
+}
+
+// toPropertyStatus converts a decoded JSON/update value into a property status
+func toPropertyStatus(value interface{}) (models.PropertyStatus, bool) {
+	switch v := value.(type) {
+	case models.PropertyStatus:
+		return v, true
+	case string:
+		return models.PropertyStatus(v), true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories/memory"
+)
+
+// newHistoryTestServices wires property and owner confirmation services with history enabled
+func newHistoryTestServices(t *testing.T) (*PropertyService, *OwnerConfirmationService) {
+	t.Helper()
+	ctx := context.Background()
+
+	tenantRepo := memory.NewTenantRepository()
+	ownerRepo := memory.NewOwnerRepository()
+	brokerRepo := memory.NewBrokerRepository()
+	propertyRepo := memory.NewPropertyRepository()
+	listingRepo := memory.NewListingRepository()
+	activityLogRepo := memory.NewActivityLogRepository()
+	historyRepo := memory.NewPropertyHistoryRepository()
+
+	require.NoError(t, tenantRepo.Create(ctx, &models.Tenant{ID: "tenant-1", Name: "Imobiliária Teste", IsActive: true}))
+	require.NoError(t, ownerRepo.Create(ctx, &models.Owner{ID: "owner-1", TenantID: "tenant-1", Name: "Maria"}))
+
+	propertyService := NewPropertyService(propertyRepo, listingRepo, ownerRepo, brokerRepo, tenantRepo, activityLogRepo)
+	propertyService.SetHistoryRepository(historyRepo)
+
+	ownerConfirmationService := NewOwnerConfirmationService(memory.NewOwnerConfirmationTokenRepository(), propertyRepo, ownerRepo, brokerRepo, listingRepo, activityLogRepo)
+	ownerConfirmationService.SetHistoryRepository(historyRepo)
+
+	return propertyService, ownerConfirmationService
+}
+
+func createHistoryTestProperty(t *testing.T, propertyService *PropertyService) *models.Property {
+	t.Helper()
+
+	property := &models.Property{
+		TenantID:     "tenant-1",
+		OwnerID:      "owner-1",
+		PropertyType: models.PropertyTypeApartment,
+		Neighborhood: "Pinheiros",
+		City:         "São Paulo",
+		State:        "SP",
+		PriceAmount:  500000,
+	}
+	require.NoError(t, propertyService.CreateProperty(context.Background(), property))
+	return property
+}
+
+func TestPropertyHistory_RecordsPriceAndStatusChanges(t *testing.T) {
+	ctx := context.Background()
+	propertyService, _ := newHistoryTestServices(t)
+	property := createHistoryTestProperty(t, propertyService)
+
+	// Broker lowers the price from the admin panel (JSON-decoded values)
+	require.NoError(t, propertyService.UpdateProperty(ctx, "tenant-1", property.ID, "member-1", map[string]interface{}{
+		"price_amount": float64(450000),
+		"status":       "unavailable",
+	}))
+
+	updated, err := propertyService.GetProperty(ctx, "tenant-1", property.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.PropertyStatusUnavailable, updated.Status)
+	assert.Equal(t, float64(500000), updated.PreviousPriceAmount)
+	assert.Equal(t, 10.0, updated.PriceReducedPercent)
+	assert.NotNil(t, updated.PriceChangedAt)
+
+	// Unchanged values are not recorded
+	require.NoError(t, propertyService.UpdateStatus(ctx, "tenant-1", property.ID, "member-1", models.PropertyStatusUnavailable))
+
+	// Operator confirmation raises the price back
+	price := float64(480000)
+	_, err = propertyService.ConfirmPropertyStatusPrice(ctx, "tenant-1", property.ID, "user-1", nil, &price, "", "")
+	require.NoError(t, err)
+
+	updated, err = propertyService.GetProperty(ctx, "tenant-1", property.ID)
+	require.NoError(t, err)
+	assert.Equal(t, float64(450000), updated.PreviousPriceAmount)
+	assert.Zero(t, updated.PriceReducedPercent)
+
+	entries, err := propertyService.ListPropertyHistory(ctx, "tenant-1", property.ID, repositories.PaginationOptions{})
+	require.NoError(t, err)
+	require.Len(t, entries, 5) // initial price and status, then price, status and price
+
+	var prices []float64
+	for _, entry := range entries {
+		if entry.Field == models.PropertyHistoryFieldPrice {
+			prices = append(prices, *entry.NewPrice)
+		}
+	}
+	assert.Equal(t, []float64{480000, 450000, 500000}, prices) // newest first
+
+	latest := entries[0]
+	assert.Equal(t, models.PropertyHistoryFieldPrice, latest.Field)
+	assert.Equal(t, float64(450000), *latest.OldPrice)
+	assert.Equal(t, models.ActorTypeUser, latest.ActorType)
+	assert.Equal(t, "user-1", latest.ActorID)
+	assert.Equal(t, models.PropertyChangeSourceBroker, latest.Source)
+
+	// Admin panel edits are recorded as the member's
+	for _, entry := range entries[1:3] {
+		assert.Equal(t, models.ActorTypeUser, entry.ActorType)
+		assert.Equal(t, "member-1", entry.ActorID)
+	}
+}
+
+func TestPropertyHistory_OwnerConfirmation(t *testing.T) {
+	ctx := context.Background()
+	propertyService, ownerConfirmationService := newHistoryTestServices(t)
+	property := createHistoryTestProperty(t, propertyService)
+
+	url, _, _, err := ownerConfirmationService.GenerateOwnerConfirmationLink(ctx, "tenant-1", property.ID, "user-1", nil, "whatsapp")
+	require.NoError(t, err)
+	token := strings.TrimPrefix(url[strings.Index(url, "/confirmar/"):], "/confirmar/")
+	token = token[:strings.Index(token, "?")]
+
+	price := float64(400000)
+	require.NoError(t, ownerConfirmationService.SubmitOwnerConfirmation(ctx, "tenant-1", token, models.ConfirmationActionPrice, &price))
+
+	updated, err := propertyService.GetProperty(ctx, "tenant-1", property.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 20.0, updated.PriceReducedPercent)
+
+	entries, err := propertyService.ListPropertyHistory(ctx, "tenant-1", property.ID, repositories.PaginationOptions{Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, models.ActorTypeOwner, entries[0].ActorType)
+	assert.Equal(t, "owner-1", entries[0].ActorID)
+	assert.Equal(t, models.PropertyChangeSourceOwnerLink, entries[0].Source)
+	assert.Equal(t, float64(500000), *entries[0].OldPrice)
+	assert.Equal(t, float64(400000), *entries[0].NewPrice)
+}
+
+func TestPropertyHistory_IsTenantScoped(t *testing.T) {
+	ctx := context.Background()
+	propertyService, _ := newHistoryTestServices(t)
+	property := createHistoryTestProperty(t, propertyService)
+
+	_, err := propertyService.ListPropertyHistory(ctx, "tenant-2", property.ID, repositories.PaginationOptions{})
+	assert.ErrorIs(t, err, repositories.ErrNotFound)
+}
+
+func TestRecalculateTenantStaleness_RecordsSystemHistory(t *testing.T) {
+	ctx := context.Background()
+	propertyService, _ := newHistoryTestServices(t)
+	property := createHistoryTestProperty(t, propertyService)
+
+	// Never confirmed: becomes pending confirmation
+	changed, err := propertyService.RecalculateTenantStaleness(ctx, "tenant-1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, changed)
+
+	updated, err := propertyService.GetProperty(ctx, "tenant-1", property.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.PropertyStatusPendingConfirmation, updated.Status)
+
+	entries, err := propertyService.ListPropertyHistory(ctx, "tenant-1", property.ID, repositories.PaginationOptions{Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, models.ActorTypeSystem, entries[0].ActorType)
+	assert.Equal(t, models.PropertyChangeSourceSystem, entries[0].Source)
+	assert.Equal(t, models.PropertyStatusAvailable, entries[0].OldStatus)
+
+	// Rerunning changes nothing
+	changed, err = propertyService.RecalculateTenantStaleness(ctx, "tenant-1")
+	require.NoError(t, err)
+	assert.Zero(t, changed)
+}
@@ -0,0 +1,301 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories/memory"
+	"github.com/altatech/ecosistema-imob/backend/internal/search"
+)
+
+// newSearchTestServices wires property and listing services over the in-memory backend
+func newSearchTestServices(t *testing.T) (*PropertyService, *ListingService) {
+	t.Helper()
+	ctx := context.Background()
+
+	tenantRepo := memory.NewTenantRepository()
+	ownerRepo := memory.NewOwnerRepository()
+	brokerRepo := memory.NewBrokerRepository()
+	propertyRepo := memory.NewPropertyRepository()
+	listingRepo := memory.NewListingRepository()
+	activityLogRepo := memory.NewActivityLogRepository()
+
+	require.NoError(t, tenantRepo.Create(ctx, &models.Tenant{ID: "tenant-1", Name: "Imobiliária Teste", IsActive: true}))
+	require.NoError(t, ownerRepo.Create(ctx, &models.Owner{ID: "owner-1", TenantID: "tenant-1", Name: "Maria"}))
+	require.NoError(t, brokerRepo.Create(ctx, &models.Broker{ID: "broker-1", TenantID: "tenant-1", Name: "João"}))
+
+	propertyService := NewPropertyService(propertyRepo, listingRepo, ownerRepo, brokerRepo, tenantRepo, activityLogRepo)
+	propertyService.SetSearchIndex(search.NewIndex())
+
+	listingService := NewListingService(listingRepo, propertyRepo, brokerRepo, tenantRepo, activityLogRepo)
+	listingService.SetPropertyIndexer(propertyService)
+
+	return propertyService, listingService
+}
+
+func createSearchTestProperty(t *testing.T, propertyService *PropertyService, listingService *ListingService, reference, neighborhood, title string) *models.Property {
+	t.Helper()
+	ctx := context.Background()
+
+	property := &models.Property{
+		TenantID:     "tenant-1",
+		OwnerID:      "owner-1",
+		PropertyType: models.PropertyTypeApartment,
+		Reference:    reference,
+		Neighborhood: neighborhood,
+		City:         "São Paulo",
+		State:        "SP",
+	}
+	require.NoError(t, propertyService.CreateProperty(ctx, property))
+
+	require.NoError(t, listingService.CreateListing(ctx, &models.Listing{
+		TenantID:    "tenant-1",
+		PropertyID:  property.ID,
+		BrokerID:    "broker-1",
+		Title:       title,
+		Description: "Imóvel bem localizado, próximo ao metrô",
+		IsActive:    true,
+	}))
+
+	return property
+}
+
+func TestSearchPropertiesFullText(t *testing.T) {
+	ctx := context.Background()
+	propertyService, listingService := newSearchTestServices(t)
+
+	pinheiros := createSearchTestProperty(t, propertyService, listingService, "AP-00335", "Pinheiros", "Apartamento com varanda gourmet")
+	moema := createSearchTestProperty(t, propertyService, listingService, "CA-00120", "Moema", "Cobertura duplex com piscina")
+
+	results, total, err := propertyService.SearchPropertiesFullText(ctx, "tenant-1", "varanda gourmt", nil, repositories.PaginationOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, results, 1)
+	assert.Equal(t, pinheiros.ID, results[0].ID)
+
+	results, _, err = propertyService.SearchPropertiesFullText(ctx, "tenant-1", "AP00335", nil, repositories.PaginationOptions{})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, pinheiros.ID, results[0].ID)
+
+	// Both match the shared description; filters still apply
+	results, total, err = propertyService.SearchPropertiesFullText(ctx, "tenant-1", "metro", &repositories.PropertyFilters{Neighborhood: "Moema"}, repositories.PaginationOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, results, 1)
+	assert.Equal(t, moema.ID, results[0].ID)
+}
+
+func TestSearchPropertiesFullText_KeepsIndexInSync(t *testing.T) {
+	ctx := context.Background()
+	propertyService, listingService := newSearchTestServices(t)
+
+	property := createSearchTestProperty(t, propertyService, listingService, "AP-00335", "Pinheiros", "Apartamento com varanda")
+
+	// Canonical listing title changes are searchable
+	loaded, err := propertyService.GetProperty(ctx, "tenant-1", property.ID)
+	require.NoError(t, err)
+	require.NotEmpty(t, loaded.CanonicalListingID)
+
+	require.NoError(t, listingService.UpdateListing(ctx, "tenant-1", loaded.CanonicalListingID, map[string]interface{}{
+		"title": "Studio reformado",
+	}))
+
+	results, _, err := propertyService.SearchPropertiesFullText(ctx, "tenant-1", "studio", nil, repositories.PaginationOptions{})
+	require.NoError(t, err)
+	assert.Len(t, results, 1)
+
+	// Address changes are searchable
+	require.NoError(t, propertyService.UpdateProperty(ctx, "tenant-1", property.ID, "", map[string]interface{}{
+		"neighborhood": "Perdizes",
+	}))
+
+	results, _, err = propertyService.SearchPropertiesFullText(ctx, "tenant-1", "perdizes", nil, repositories.PaginationOptions{})
+	require.NoError(t, err)
+	assert.Len(t, results, 1)
+
+	// Deleted properties disappear from the index
+	require.NoError(t, propertyService.DeleteProperty(ctx, "tenant-1", property.ID))
+
+	results, total, err := propertyService.SearchPropertiesFullText(ctx, "tenant-1", "perdizes", nil, repositories.PaginationOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 0, total)
+	assert.Empty(t, results)
+}
+
+func TestRebuildSearchIndex_PicksUpOtherInstancesWrites(t *testing.T) {
+	ctx := context.Background()
+	propertyService, listingService := newSearchTestServices(t)
+
+	stale := createSearchTestProperty(t, propertyService, listingService, "AP-00335", "Pinheiros", "Apartamento com varanda")
+
+	// Writes made by another instance go straight to the store
+	other := &models.Property{TenantID: "tenant-1", OwnerID: "owner-1", PropertyType: models.PropertyTypeHouse, Neighborhood: "Perdizes", City: "São Paulo"}
+	require.NoError(t, propertyService.propertyRepo.Create(ctx, other))
+	require.NoError(t, propertyService.propertyRepo.Delete(ctx, "tenant-1", stale.ID))
+
+	indexed, err := propertyService.RebuildSearchIndex(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, indexed)
+	assert.Equal(t, 1, propertyService.searchIndex.Len())
+
+	results, _, err := propertyService.SearchPropertiesFullText(ctx, "tenant-1", "perdizes", nil, repositories.PaginationOptions{})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, other.ID, results[0].ID)
+}
+
+// countingListingStore counts the canonical listing reads of a rebuild
+type countingListingStore struct {
+	repositories.ListingStore
+	gets    int
+	batches int
+}
+
+func (s *countingListingStore) Get(ctx context.Context, tenantID, id string) (*models.Listing, error) {
+	s.gets++
+	return s.ListingStore.Get(ctx, tenantID, id)
+}
+
+func (s *countingListingStore) GetMany(ctx context.Context, tenantID string, ids []string) ([]*models.Listing, error) {
+	s.batches++
+	return s.ListingStore.GetMany(ctx, tenantID, ids)
+}
+
+func TestRebuildSearchIndex_PagesWithCursorsAndBatchesListings(t *testing.T) {
+	ctx := context.Background()
+	propertyService, listingService := newSearchTestServices(t)
+
+	titled := createSearchTestProperty(t, propertyService, listingService, "AP-00335", "Pinheiros", "Cobertura com terraço")
+
+	// More than a page, written by another instance
+	for i := 0; i < 250; i++ {
+		require.NoError(t, propertyService.propertyRepo.Create(ctx, &models.Property{TenantID: "tenant-1", OwnerID: "owner-1", PropertyType: models.PropertyTypeHouse, Neighborhood: "Perdizes", City: "São Paulo"}))
+	}
+
+	listings := &countingListingStore{ListingStore: propertyService.listingRepo}
+	propertyService.listingRepo = listings
+
+	indexed, err := propertyService.RebuildSearchIndex(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 251, indexed)
+	assert.Equal(t, 251, propertyService.searchIndex.Len())
+	assert.Equal(t, 0, listings.gets)
+	assert.Equal(t, 2, listings.batches)
+
+	results, _, err := propertyService.SearchPropertiesFullText(ctx, "tenant-1", "terraço", nil, repositories.PaginationOptions{})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, titled.ID, results[0].ID)
+}
+
+// countingPropertyStore counts the property reads of a search
+type countingPropertyStore struct {
+	repositories.PropertyStore
+	gets    int
+	batches int
+	loaded  int
+}
+
+func (s *countingPropertyStore) Get(ctx context.Context, tenantID, id string) (*models.Property, error) {
+	s.gets++
+	return s.PropertyStore.Get(ctx, tenantID, id)
+}
+
+func (s *countingPropertyStore) GetMany(ctx context.Context, ids []string) ([]*models.Property, error) {
+	s.batches++
+	s.loaded += len(ids)
+	return s.PropertyStore.GetMany(ctx, ids)
+}
+
+func TestSearchPropertiesFullText_LoadsOnlyThePage(t *testing.T) {
+	ctx := context.Background()
+	propertyService, listingService := newSearchTestServices(t)
+
+	for _, neighborhood := range []string{"Pinheiros", "Moema", "Pinheiros", "Pinheiros", "Moema"} {
+		createSearchTestProperty(t, propertyService, listingService, "", neighborhood, "Apartamento com varanda")
+	}
+
+	store := &countingPropertyStore{PropertyStore: propertyService.propertyRepo}
+	propertyService.propertyRepo = store
+
+	// Filters run on the indexed attributes; only the page is read, at once
+	results, total, err := propertyService.SearchPropertiesFullText(ctx, "tenant-1", "varanda", &repositories.PropertyFilters{Neighborhood: "Pinheiros"}, repositories.PaginationOptions{Limit: 2, Offset: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	require.Len(t, results, 2)
+	for _, property := range results {
+		assert.Equal(t, "Pinheiros", property.Neighborhood)
+	}
+	assert.Equal(t, 0, store.gets)
+	assert.Equal(t, 1, store.batches)
+	assert.Equal(t, 2, store.loaded)
+}
+
+func TestSearchPublicPropertiesFullText_SeesStatusAndVisibilityChanges(t *testing.T) {
+	ctx := context.Background()
+	propertyService, listingService := newSearchTestServices(t)
+
+	property := createSearchTestProperty(t, propertyService, listingService, "AP-00335", "Pinheiros", "Apartamento com varanda")
+
+	// New properties are private
+	results, total, err := propertyService.SearchPublicPropertiesFullText(ctx, "varanda", nil, repositories.PaginationOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 0, total)
+	assert.Empty(t, results)
+
+	require.NoError(t, propertyService.UpdateVisibility(ctx, "tenant-1", property.ID, models.PropertyVisibilityPublic))
+
+	results, total, err = propertyService.SearchPublicPropertiesFullText(ctx, "varanda", nil, repositories.PaginationOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, results, 1)
+	assert.Equal(t, property.ID, results[0].ID)
+
+	// A status change written by another instance is seen before any rebuild
+	require.NoError(t, propertyService.propertyRepo.Update(ctx, "tenant-1", property.ID, map[string]interface{}{
+		"status": models.PropertyStatusUnavailable,
+	}))
+
+	results, total, err = propertyService.SearchPublicPropertiesFullText(ctx, "varanda", nil, repositories.PaginationOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 0, total)
+	assert.Empty(t, results)
+
+	require.NoError(t, propertyService.UpdateStatus(ctx, "tenant-1", property.ID, "", models.PropertyStatusAvailable))
+
+	status := models.PropertyStatusAvailable
+	results, total, err = propertyService.SearchPropertiesFullText(ctx, "tenant-1", "varanda", &repositories.PropertyFilters{Status: &status}, repositories.PaginationOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Len(t, results, 1)
+}
+
+func TestSearchPublicPropertiesFullText_PagesPastPrivateHits(t *testing.T) {
+	ctx := context.Background()
+	propertyService, listingService := newSearchTestServices(t)
+
+	// Private properties rank above the public ones
+	for i := 0; i < 3; i++ {
+		createSearchTestProperty(t, propertyService, listingService, "", "Pinheiros", "Varanda varanda varanda")
+	}
+	var public []string
+	for i := 0; i < 3; i++ {
+		property := createSearchTestProperty(t, propertyService, listingService, "", "Moema", "Apartamento com varanda")
+		require.NoError(t, propertyService.UpdateVisibility(ctx, "tenant-1", property.ID, models.PropertyVisibilityPublic))
+		public = append(public, property.ID)
+	}
+
+	results, total, err := propertyService.SearchPublicPropertiesFullText(ctx, "varanda", nil, repositories.PaginationOptions{Limit: 2, Offset: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	require.Len(t, results, 2)
+	for _, property := range results {
+		assert.Contains(t, public, property.ID)
+	}
+}
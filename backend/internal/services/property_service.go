@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"crypto/sha256"
+	"errors"
 	"fmt"
 	"regexp"
 	"sort"
@@ -10,32 +11,38 @@ import (
 	"time"
 	"unicode"
 
+	"cloud.google.com/go/firestore"
 	"golang.org/x/text/transform"
 	"golang.org/x/text/unicode/norm"
 
 	"github.com/altatech/ecosistema-imob/backend/internal/models"
 	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/search"
+	"github.com/altatech/ecosistema-imob/backend/internal/utils"
 )
 
 // PropertyService handles business logic for property management
 type PropertyService struct {
-	propertyRepo             *repositories.PropertyRepository
-	listingRepo              *repositories.ListingRepository
-	ownerRepo                *repositories.OwnerRepository
-	brokerRepo               *repositories.BrokerRepository
-	tenantRepo               *repositories.TenantRepository
-	activityLogRepo          *repositories.ActivityLogRepository
-	ownerConfirmationService *OwnerConfirmationService // PROMPT 08: for generating owner confirmation links
+	propertyRepo             repositories.PropertyStore
+	listingRepo              repositories.ListingStore
+	ownerRepo                repositories.OwnerStore
+	brokerRepo               repositories.BrokerStore
+	tenantRepo               repositories.TenantStore
+	activityLogRepo          repositories.ActivityLogStore
+	ownerConfirmationService *OwnerConfirmationService         // PROMPT 08: for generating owner confirmation links
+	searchIndex              *search.Index                     // Full-text index (nil disables q= search)
+	portalFeed               *PortalFeedService                // Optional - VRSync portal feeds
+	historyRepo              repositories.PropertyHistoryStore // Optional - price/status history
 }
 
 // NewPropertyService creates a new property service
 func NewPropertyService(
-	propertyRepo *repositories.PropertyRepository,
-	listingRepo *repositories.ListingRepository,
-	ownerRepo *repositories.OwnerRepository,
-	brokerRepo *repositories.BrokerRepository,
-	tenantRepo *repositories.TenantRepository,
-	activityLogRepo *repositories.ActivityLogRepository,
+	propertyRepo repositories.PropertyStore,
+	listingRepo repositories.ListingStore,
+	ownerRepo repositories.OwnerStore,
+	brokerRepo repositories.BrokerStore,
+	tenantRepo repositories.TenantStore,
+	activityLogRepo repositories.ActivityLogStore,
 ) *PropertyService {
 	return &PropertyService{
 		propertyRepo:    propertyRepo,
@@ -95,6 +102,16 @@ func (s *PropertyService) CreateProperty(ctx context.Context, property *models.P
 		property.Visibility = models.PropertyVisibilityPrivate
 	}
 
+	// Validate coordinates and keep the geohash in sync
+	if property.HasCoordinates() {
+		if err := utils.ValidateCoordinates(*property.Latitude, *property.Longitude); err != nil {
+			return err
+		}
+		property.SetCoordinates(*property.Latitude, *property.Longitude)
+	} else {
+		property.Latitude, property.Longitude, property.Geohash = nil, nil, ""
+	}
+
 	// Generate slug if not provided
 	if property.Slug == "" {
 		property.Slug = s.GenerateSlug(property)
@@ -126,6 +143,15 @@ func (s *PropertyService) CreateProperty(ctx context.Context, property *models.P
 		return fmt.Errorf("failed to create property: %w", err)
 	}
 
+	// Keep full-text index and portal feeds in sync
+	s.indexProperty(ctx, property)
+	s.invalidatePortalFeed(property.TenantID, property.ID)
+
+	recordPropertyHistory(ctx, s.historyRepo, initialPropertyHistory(property, propertyChange{
+		actorType: models.ActorTypeSystem,
+		source:    models.PropertyChangeSourceBroker,
+	}))
+
 	// Log activity
 	_ = s.logActivity(ctx, property.TenantID, "property_created", models.ActorTypeSystem, "", map[string]interface{}{
 		"property_id":        property.ID,
@@ -260,8 +286,9 @@ func (s *PropertyService) populatePropertyBroker(ctx context.Context, tenantID s
 	}
 }
 
-// UpdateProperty updates a property with validation
-func (s *PropertyService) UpdateProperty(ctx context.Context, tenantID, id string, updates map[string]interface{}) error {
+// UpdateProperty updates a property with validation. actorID is the member
+// making the change, empty for system updates.
+func (s *PropertyService) UpdateProperty(ctx context.Context, tenantID, id, actorID string, updates map[string]interface{}) error {
 	if tenantID == "" {
 		return fmt.Errorf("tenant_id is required")
 	}
@@ -283,9 +310,12 @@ func (s *PropertyService) UpdateProperty(ctx context.Context, tenantID, id strin
 	}
 
 	// Validate status if being updated
-	if status, ok := updates["status"].(models.PropertyStatus); ok {
-		if err := s.validatePropertyStatus(status); err != nil {
-			return err
+	if value, ok := updates["status"]; ok {
+		if status, ok := toPropertyStatus(value); ok {
+			if err := s.validatePropertyStatus(status); err != nil {
+				return err
+			}
+			updates["status"] = status
 		}
 	}
 
@@ -296,11 +326,25 @@ func (s *PropertyService) UpdateProperty(ctx context.Context, tenantID, id strin
 		}
 	}
 
+	// Validate portal flags if being updated
+	if portals, ok := updates["portals"]; ok {
+		flags, err := parsePortalFlags(portals)
+		if err != nil {
+			return err
+		}
+		updates["portals"] = flags
+	}
+
 	// Normalize slug if being updated
 	if slug, ok := updates["slug"].(string); ok && slug != "" {
 		updates["slug"] = s.NormalizeSlug(slug)
 	}
 
+	// Recompute geohash if coordinates are updated
+	if err := s.applyCoordinateUpdates(existing, updates); err != nil {
+		return err
+	}
+
 	// Regenerate fingerprint if key fields are updated
 	shouldRegenerateFingerprint := false
 	keyFields := []string{"street", "number", "city", "property_type", "total_area"}
@@ -334,13 +378,21 @@ func (s *PropertyService) UpdateProperty(ctx context.Context, tenantID, id strin
 	// Prevent updating tenant_id
 	delete(updates, "tenant_id")
 
+	// Track price/status changes
+	change := brokerChange(actorID)
+	history := trackPropertyChanges(existing, updates, change, time.Now())
+
 	// Update property in repository
 	if err := s.propertyRepo.Update(ctx, tenantID, id, updates); err != nil {
 		return fmt.Errorf("failed to update property: %w", err)
 	}
+	recordPropertyHistory(ctx, s.historyRepo, history)
+
+	// Keep full-text index in sync
+	_ = s.ReindexProperty(ctx, tenantID, id)
 
 	// Log activity
-	_ = s.logActivity(ctx, tenantID, "property_updated", models.ActorTypeSystem, "", map[string]interface{}{
+	_ = s.logActivity(ctx, tenantID, "property_updated", change.actorType, actorID, map[string]interface{}{
 		"property_id": id,
 		"updates":     updates,
 	})
@@ -348,6 +400,73 @@ func (s *PropertyService) UpdateProperty(ctx context.Context, tenantID, id strin
 	return nil
 }
 
+// applyCoordinateUpdates validates latitude/longitude updates and sets the matching geohash.
+// Clearing either coordinate (nil) clears both coordinates and the geohash.
+func (s *PropertyService) applyCoordinateUpdates(existing *models.Property, updates map[string]interface{}) error {
+	latValue, latSet := updates["latitude"]
+	lngValue, lngSet := updates["longitude"]
+	if !latSet && !lngSet {
+		return nil
+	}
+
+	delete(updates, "geohash")
+
+	if (latSet && latValue == nil) || (lngSet && lngValue == nil) {
+		updates["latitude"] = nil
+		updates["longitude"] = nil
+		updates["geohash"] = ""
+		return nil
+	}
+
+	lat, lng := existing.Latitude, existing.Longitude
+	if latSet {
+		value, ok := toFloat64(latValue)
+		if !ok {
+			return fmt.Errorf("latitude must be a number")
+		}
+		lat = &value
+	}
+	if lngSet {
+		value, ok := toFloat64(lngValue)
+		if !ok {
+			return fmt.Errorf("longitude must be a number")
+		}
+		lng = &value
+	}
+	if lat == nil || lng == nil {
+		return fmt.Errorf("latitude and longitude must be set together")
+	}
+	if err := utils.ValidateCoordinates(*lat, *lng); err != nil {
+		return err
+	}
+
+	updates["latitude"] = *lat
+	updates["longitude"] = *lng
+	updates["geohash"] = utils.EncodeGeohash(*lat, *lng, utils.DefaultGeohashPrecision)
+	return nil
+}
+
+// toFloat64 converts a decoded JSON/update value into a float64
+func toFloat64(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case *float64:
+		if v == nil {
+			return 0, false
+		}
+		return *v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
 // DeleteProperty deletes a property
 func (s *PropertyService) DeleteProperty(ctx context.Context, tenantID, id string) error {
 	if tenantID == "" {
@@ -367,6 +486,12 @@ func (s *PropertyService) DeleteProperty(ctx context.Context, tenantID, id strin
 		return fmt.Errorf("failed to delete property: %w", err)
 	}
 
+	// Keep full-text index and portal feeds in sync
+	s.invalidatePortalFeed(tenantID, id)
+	if s.searchIndex != nil {
+		s.searchIndex.Remove(tenantID, id)
+	}
+
 	// Log activity
 	_ = s.logActivity(ctx, tenantID, "property_deleted", models.ActorTypeSystem, "", map[string]interface{}{
 		"property_id": id,
@@ -441,6 +566,405 @@ func (s *PropertyService) ListAllPublicProperties(ctx context.Context, filters *
 	return properties, nil
 }
 
+// ListPublicPropertiesInBounds lists PUBLIC properties inside a map bounding box (across all tenants)
+// This is used by the public portal map view
+func (s *PropertyService) ListPublicPropertiesInBounds(ctx context.Context, bounds utils.GeoBounds, filters *repositories.PropertyFilters, limit int) ([]*models.Property, error) {
+	properties, err := s.propertyRepo.ListPublicInBounds(ctx, bounds, filters, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list public properties in bounds: %w", err)
+	}
+
+	s.populatePublicProperties(ctx, properties)
+
+	return properties, nil
+}
+
+// ListPublicPropertiesNearby lists PUBLIC properties within radiusKm of a point (across all tenants),
+// nearest first
+func (s *PropertyService) ListPublicPropertiesNearby(ctx context.Context, lat, lng, radiusKm float64, filters *repositories.PropertyFilters, limit int) ([]*models.Property, error) {
+	properties, err := s.propertyRepo.ListPublicNearby(ctx, lat, lng, radiusKm, filters, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list public properties nearby: %w", err)
+	}
+
+	s.populatePublicProperties(ctx, properties)
+
+	return properties, nil
+}
+
+// populatePublicProperties enriches public properties with photos and broker data
+func (s *PropertyService) populatePublicProperties(ctx context.Context, properties []*models.Property) {
+	for _, property := range properties {
+		s.populatePropertyPhotos(ctx, property.TenantID, property)
+		s.populatePropertyBroker(ctx, property.TenantID, property)
+	}
+}
+
+// maxSearchReads caps how many properties a single full-text search loads
+// to check status, visibility and price against the stored document
+const maxSearchReads = 2000
+
+// searchReadBatch is how many hits a full-text search loads per read
+const searchReadBatch = 100
+
+// SetPortalFeed sets the portal feed service notified of property changes (optional)
+func (s *PropertyService) SetPortalFeed(feed *PortalFeedService) {
+	s.portalFeed = feed
+}
+
+// invalidatePortalFeed marks the property's portal feed entry stale
+func (s *PropertyService) invalidatePortalFeed(tenantID, propertyID string) {
+	if s.portalFeed != nil {
+		s.portalFeed.InvalidateProperty(tenantID, propertyID)
+	}
+}
+
+// SetHistoryRepository enables the price/status history of properties (optional)
+func (s *PropertyService) SetHistoryRepository(historyRepo repositories.PropertyHistoryStore) {
+	s.historyRepo = historyRepo
+}
+
+// ListPropertyHistory retrieves the price/status history of a property, newest first
+func (s *PropertyService) ListPropertyHistory(ctx context.Context, tenantID, propertyID string, opts repositories.PaginationOptions) ([]*models.PropertyHistoryEntry, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+	if propertyID == "" {
+		return nil, fmt.Errorf("property ID is required")
+	}
+
+	// Validate property exists within the tenant
+	if _, err := s.propertyRepo.Get(ctx, tenantID, propertyID); err != nil {
+		return nil, err
+	}
+
+	if s.historyRepo == nil {
+		return []*models.PropertyHistoryEntry{}, nil
+	}
+
+	return s.historyRepo.ListByProperty(ctx, tenantID, propertyID, opts)
+}
+
+// SetSearchIndex sets the full-text index used by q= searches
+func (s *PropertyService) SetSearchIndex(index *search.Index) {
+	s.searchIndex = index
+}
+
+// SearchPropertiesFullText searches a tenant's properties by free text
+// (canonical listing title/description, address and reference), best match first.
+// Returns the requested page and the total number of matches.
+func (s *PropertyService) SearchPropertiesFullText(ctx context.Context, tenantID, query string, filters *repositories.PropertyFilters, opts repositories.PaginationOptions) ([]*models.Property, int, error) {
+	if tenantID == "" {
+		return nil, 0, fmt.Errorf("tenant_id is required")
+	}
+
+	page, total, err := s.searchPage(ctx, tenantID, query, filters, false, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	s.populatePublicProperties(ctx, page)
+
+	return page, total, nil
+}
+
+// SearchPublicPropertiesFullText searches PUBLIC properties across all tenants by free text
+func (s *PropertyService) SearchPublicPropertiesFullText(ctx context.Context, query string, filters *repositories.PropertyFilters, opts repositories.PaginationOptions) ([]*models.Property, int, error) {
+	page, total, err := s.searchPage(ctx, "", query, filters, true, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	s.populatePublicProperties(ctx, page)
+
+	return page, total, nil
+}
+
+// searchPage runs query against the index, preserving relevance order, and
+// returns the requested page and the number of matches.
+//
+// The indexed copy of a property can lag behind the store (another instance
+// changed it), so only the filters on attributes that rarely change run on it.
+// Status, visibility (publicOnly) and price are checked on the loaded
+// documents: hits are then loaded in batches, in relevance order, until every
+// hit is checked or maxSearchReads is reached; past that budget the unchecked
+// hits are counted as matches. Without such conditions only the page is loaded.
+func (s *PropertyService) searchPage(ctx context.Context, tenantID, query string, filters *repositories.PropertyFilters, publicOnly bool, opts repositories.PaginationOptions) ([]*models.Property, int, error) {
+	if s.searchIndex == nil {
+		return nil, 0, fmt.Errorf("full-text search is not enabled")
+	}
+
+	indexed, verify := splitSearchFilters(filters)
+	verify = verify || publicOnly
+	keep := func(p *models.Property) bool {
+		if publicOnly && (p.Visibility != models.PropertyVisibilityPublic || p.Status != models.PropertyStatusAvailable) {
+			return false
+		}
+		return filters.Matches(p)
+	}
+
+	hits := s.searchIndex.Search(tenantID, query, 0)
+	candidates := make([]search.Hit, 0, len(hits))
+	for _, hit := range hits {
+		if attributes, ok := hit.Payload.(*models.Property); ok && !indexed.Matches(attributes) {
+			continue
+		}
+		candidates = append(candidates, hit)
+	}
+
+	if !verify {
+		page, err := s.loadSearchHits(ctx, paginateSearchResults(candidates, opts))
+		if err != nil {
+			return nil, 0, err
+		}
+		properties := make([]*models.Property, 0, len(page))
+		for _, property := range page {
+			if property != nil && keep(property) {
+				properties = append(properties, property)
+			}
+		}
+		return properties, len(candidates), nil
+	}
+
+	if opts.Limit <= 0 {
+		opts.Limit = repositories.DefaultPaginationOptions().Limit
+	}
+	end := opts.Offset + opts.Limit
+
+	properties := make([]*models.Property, 0, opts.Limit)
+	total := 0
+	for start := 0; start < len(candidates); start += searchReadBatch {
+		if start >= maxSearchReads {
+			total += len(candidates) - start
+			break
+		}
+		batch, err := s.loadSearchHits(ctx, candidates[start:min(start+searchReadBatch, len(candidates))])
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, property := range batch {
+			if property == nil || !keep(property) {
+				continue
+			}
+			if total >= opts.Offset && total < end {
+				properties = append(properties, property)
+			}
+			total++
+		}
+	}
+
+	return properties, total, nil
+}
+
+// splitSearchFilters returns the filters that can run on indexed attributes and
+// whether any filter (status, visibility, price) must be checked on the loaded document
+func splitSearchFilters(filters *repositories.PropertyFilters) (*repositories.PropertyFilters, bool) {
+	if filters == nil {
+		return nil, false
+	}
+	indexed := *filters
+	indexed.Status = nil
+	indexed.Visibility = nil
+	indexed.MinPrice = nil
+	indexed.MaxPrice = nil
+	verify := filters.Status != nil || filters.Visibility != nil || filters.MinPrice != nil || filters.MaxPrice != nil
+	return &indexed, verify
+}
+
+// loadSearchHits loads the properties of hits in a single read, in order.
+// Entries of properties deleted outside the service are removed from the
+// index and come back nil.
+func (s *PropertyService) loadSearchHits(ctx context.Context, hits []search.Hit) ([]*models.Property, error) {
+	ids := make([]string, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.ID
+	}
+
+	loaded, err := s.propertyRepo.GetMany(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load search results: %w", err)
+	}
+
+	for i, property := range loaded {
+		if property == nil || property.TenantID != hits[i].TenantID {
+			s.searchIndex.Remove(hits[i].TenantID, hits[i].ID)
+			loaded[i] = nil
+		}
+	}
+	return loaded, nil
+}
+
+// paginateSearchResults applies offset and limit to relevance-ordered results
+func paginateSearchResults[T any](results []T, opts repositories.PaginationOptions) []T {
+	if opts.Limit <= 0 {
+		opts.Limit = repositories.DefaultPaginationOptions().Limit
+	}
+	if opts.Offset >= len(results) {
+		return []T{}
+	}
+
+	end := opts.Offset + opts.Limit
+	if end > len(results) {
+		end = len(results)
+	}
+	return results[opts.Offset:end]
+}
+
+// ReindexProperty reloads a property (and its canonical listing) into the full-text index
+// and marks its portal feed entry stale. A property that no longer exists is removed from the index.
+func (s *PropertyService) ReindexProperty(ctx context.Context, tenantID, propertyID string) error {
+	s.invalidatePortalFeed(tenantID, propertyID)
+
+	if s.searchIndex == nil {
+		return nil
+	}
+
+	property, err := s.propertyRepo.Get(ctx, tenantID, propertyID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			s.searchIndex.Remove(tenantID, propertyID)
+			return nil
+		}
+		return fmt.Errorf("failed to reindex property: %w", err)
+	}
+
+	s.indexProperty(ctx, property)
+	return nil
+}
+
+// RebuildSearchIndex indexes every property of every tenant and drops the
+// documents of properties no longer found. The index only sees the writes of
+// its own instance, so each replica runs it periodically to pick up the others.
+// Tenants and properties are paged with keyset cursors and the canonical
+// listings of each page are read at once. Returns the number of indexed properties.
+func (s *PropertyService) RebuildSearchIndex(ctx context.Context) (int, error) {
+	if s.searchIndex == nil {
+		return 0, nil
+	}
+
+	const pageSize = 200
+	indexed := 0
+	generation := s.searchIndex.BeginGeneration()
+
+	tenantOpts := repositories.PaginationOptions{Limit: pageSize, OrderBy: "created_at", Direction: firestore.Asc}
+	for {
+		tenants, err := s.tenantRepo.List(ctx, tenantOpts)
+		if err != nil {
+			return indexed, fmt.Errorf("failed to list tenants: %w", err)
+		}
+
+		for _, tenant := range tenants {
+			opts := repositories.PaginationOptions{Limit: pageSize, OrderBy: "created_at", Direction: firestore.Asc}
+			for {
+				properties, err := s.propertyRepo.List(ctx, tenant.ID, nil, opts)
+				if err != nil {
+					return indexed, fmt.Errorf("failed to list properties for tenant %s: %w", tenant.ID, err)
+				}
+
+				listings, err := s.canonicalListings(ctx, tenant.ID, properties)
+				if err != nil {
+					return indexed, err
+				}
+				for _, property := range properties {
+					s.indexPropertyWithListing(property, listings[property.CanonicalListingID])
+					indexed++
+				}
+
+				if len(properties) < pageSize {
+					break
+				}
+				last := properties[len(properties)-1]
+				opts.StartAfter, opts.StartAfterID = last.CreatedAt, last.ID
+			}
+		}
+
+		if len(tenants) < pageSize {
+			s.searchIndex.Sweep(generation)
+			return indexed, nil
+		}
+		last := tenants[len(tenants)-1]
+		tenantOpts.StartAfter, tenantOpts.StartAfterID = last.CreatedAt, last.ID
+	}
+}
+
+// canonicalListings reads the canonical listings of properties in a single
+// read, by listing ID
+func (s *PropertyService) canonicalListings(ctx context.Context, tenantID string, properties []*models.Property) (map[string]*models.Listing, error) {
+	ids := make([]string, 0, len(properties))
+	for _, property := range properties {
+		if property.CanonicalListingID != "" {
+			ids = append(ids, property.CanonicalListingID)
+		}
+	}
+
+	loaded, err := s.listingRepo.GetMany(ctx, tenantID, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load canonical listings for tenant %s: %w", tenantID, err)
+	}
+
+	listings := make(map[string]*models.Listing, len(loaded))
+	for _, listing := range loaded {
+		if listing != nil {
+			listings[listing.ID] = listing
+		}
+	}
+	return listings, nil
+}
+
+// indexProperty writes a property document to the full-text index
+func (s *PropertyService) indexProperty(ctx context.Context, property *models.Property) {
+	if s.searchIndex == nil {
+		return
+	}
+
+	var listing *models.Listing
+	if property.CanonicalListingID != "" {
+		listing, _ = s.listingRepo.Get(ctx, property.TenantID, property.CanonicalListingID)
+	}
+	s.indexPropertyWithListing(property, listing)
+}
+
+// indexPropertyWithListing writes a property document, with the title and
+// description of its canonical listing (when not nil), to the full-text index
+func (s *PropertyService) indexPropertyWithListing(property *models.Property, listing *models.Listing) {
+	fields := []search.Field{
+		{Text: property.Reference + " " + search.Compact(property.Reference), Weight: search.WeightReference},
+		{Text: property.ExternalID, Weight: search.WeightReference},
+		{Text: strings.Join([]string{property.Street, property.Neighborhood, property.City, property.State, property.ZipCode}, " "), Weight: search.WeightAddress},
+	}
+
+	if listing != nil {
+		fields = append(fields,
+			search.Field{Text: listing.Title, Weight: search.WeightTitle},
+			search.Field{Text: listing.Description, Weight: search.WeightDescription},
+		)
+	}
+
+	s.searchIndex.Upsert(search.Document{
+		ID:       property.ID,
+		TenantID: property.TenantID,
+		Fields:   fields,
+		Payload:  searchAttributes(property),
+	})
+}
+
+// searchAttributes copies the fields full-text searches filter on before any
+// property is loaded. Status, visibility and price are left out: they change
+// often and are only checked on the loaded document (see searchPage).
+func searchAttributes(property *models.Property) *models.Property {
+	return &models.Property{
+		ID:              property.ID,
+		TenantID:        property.TenantID,
+		PropertyType:    property.PropertyType,
+		TransactionType: property.TransactionType,
+		OwnerID:         property.OwnerID,
+		City:            property.City,
+		Neighborhood:    property.Neighborhood,
+		Bedrooms:        property.Bedrooms,
+		Bathrooms:       property.Bathrooms,
+	}
+}
+
 // GetPublicProperty retrieves a PUBLIC property by ID (across all tenants)
 // This is used by the public portal agregador
 func (s *PropertyService) GetPublicProperty(ctx context.Context, id string) (*models.Property, error) {
@@ -521,8 +1045,9 @@ func (s *PropertyService) SearchProperties(ctx context.Context, tenantID, city,
 	return properties, nil
 }
 
-// UpdateStatus updates the status of a property
-func (s *PropertyService) UpdateStatus(ctx context.Context, tenantID, id string, status models.PropertyStatus) error {
+// UpdateStatus updates the status of a property. actorID is the member
+// making the change, empty for system updates.
+func (s *PropertyService) UpdateStatus(ctx context.Context, tenantID, id, actorID string, status models.PropertyStatus) error {
 	if tenantID == "" {
 		return fmt.Errorf("tenant_id is required")
 	}
@@ -534,18 +1059,31 @@ func (s *PropertyService) UpdateStatus(ctx context.Context, tenantID, id string,
 		return err
 	}
 
+	existing, err := s.propertyRepo.Get(ctx, tenantID, id)
+	if err != nil {
+		return fmt.Errorf("failed to update property status: %w", err)
+	}
+
 	now := time.Now()
 	updates := map[string]interface{}{
 		"status":               status,
 		"status_confirmed_at":  now,
 	}
+	if status != models.PropertyStatusUnavailable && existing.UnavailableReason != "" {
+		// Back on the market: a sale or rental that fell through
+		updates["unavailable_reason"] = ""
+	}
+	change := brokerChange(actorID)
+	history := trackPropertyChanges(existing, updates, change, now)
 
 	if err := s.propertyRepo.Update(ctx, tenantID, id, updates); err != nil {
 		return fmt.Errorf("failed to update property status: %w", err)
 	}
+	recordPropertyHistory(ctx, s.historyRepo, history)
+	_ = s.ReindexProperty(ctx, tenantID, id)
 
 	// Log activity
-	_ = s.logActivity(ctx, tenantID, "property_status_changed", models.ActorTypeSystem, "", map[string]interface{}{
+	_ = s.logActivity(ctx, tenantID, "property_status_changed", change.actorType, actorID, map[string]interface{}{
 		"property_id": id,
 		"status":      status,
 	})
@@ -573,6 +1111,7 @@ func (s *PropertyService) UpdateVisibility(ctx context.Context, tenantID, id str
 	if err := s.propertyRepo.Update(ctx, tenantID, id, updates); err != nil {
 		return fmt.Errorf("failed to update property visibility: %w", err)
 	}
+	_ = s.ReindexProperty(ctx, tenantID, id)
 
 	// Log activity
 	_ = s.logActivity(ctx, tenantID, "property_visibility_changed", models.ActorTypeSystem, "", map[string]interface{}{
@@ -583,6 +1122,65 @@ func (s *PropertyService) UpdateVisibility(ctx context.Context, tenantID, id str
 	return nil
 }
 
+// UpdatePortals sets the portals a property is syndicated to (VRSync feeds)
+func (s *PropertyService) UpdatePortals(ctx context.Context, tenantID, id string, portals models.PortalFlags) error {
+	if tenantID == "" {
+		return fmt.Errorf("tenant_id is required")
+	}
+	if id == "" {
+		return fmt.Errorf("property ID is required")
+	}
+
+	updates := map[string]interface{}{
+		"portals":    portals,
+		"updated_at": time.Now(),
+	}
+
+	if err := s.propertyRepo.Update(ctx, tenantID, id, updates); err != nil {
+		return fmt.Errorf("failed to update property portals: %w", err)
+	}
+	_ = s.ReindexProperty(ctx, tenantID, id)
+
+	// Log activity
+	_ = s.logActivity(ctx, tenantID, "property_portals_changed", models.ActorTypeSystem, "", map[string]interface{}{
+		"property_id": id,
+		"portals":     portals,
+	})
+
+	return nil
+}
+
+// parsePortalFlags converts a "portals" update ({"zap": true, ...}) to PortalFlags
+func parsePortalFlags(value interface{}) (models.PortalFlags, error) {
+	if flags, ok := value.(models.PortalFlags); ok {
+		return flags, nil
+	}
+
+	raw, ok := value.(map[string]interface{})
+	if !ok {
+		return models.PortalFlags{}, fmt.Errorf("%w: portals must be an object of portal flags", repositories.ErrInvalidInput)
+	}
+
+	var flags models.PortalFlags
+	for key, v := range raw {
+		enabled, ok := v.(bool)
+		if !ok {
+			return models.PortalFlags{}, fmt.Errorf("%w: portal %s must be true or false", repositories.ErrInvalidInput, key)
+		}
+		switch models.Portal(key) {
+		case models.PortalZAP:
+			flags.ZAP = enabled
+		case models.PortalVivaReal:
+			flags.VivaReal = enabled
+		case models.PortalOLX:
+			flags.OLX = enabled
+		default:
+			return models.PortalFlags{}, fmt.Errorf("%w: unknown portal %s (must be one of %v)", repositories.ErrInvalidInput, key, models.AllPortals())
+		}
+	}
+	return flags, nil
+}
+
 // GenerateFingerprint generates a fingerprint for deduplication
 // Fingerprint format: hash(street+number+city+property_type+area)
 func (s *PropertyService) GenerateFingerprint(property *models.Property) string {
@@ -903,7 +1501,8 @@ func (s *PropertyService) ConfirmPropertyStatusPrice(
 	}
 
 	// Recalculate visibility based on new status/confirmation
-	newVisibility := s.calculateVisibility(property, confirmStatus, &now)
+	policy := loadTenantPolicy(ctx, s.tenantRepo, tenantID)
+	newVisibility := s.calculateVisibility(property, confirmStatus, &now, policy)
 	if newVisibility != property.Visibility {
 		updates["visibility"] = newVisibility
 		metadata["visibility_changed"] = newVisibility
@@ -916,10 +1515,19 @@ func (s *PropertyService) ConfirmPropertyStatusPrice(
 		})
 	}
 
+	// Track price/status changes
+	history := trackPropertyChanges(property, updates, propertyChange{
+		actorType: models.ActorTypeUser,
+		actorID:   actorID,
+		source:    models.PropertyChangeSourceBroker,
+	}, now)
+
 	// Update property
 	if err := s.propertyRepo.Update(ctx, tenantID, propertyID, updates); err != nil {
 		return nil, fmt.Errorf("failed to update property: %w", err)
 	}
+	recordPropertyHistory(ctx, s.historyRepo, history)
+	_ = s.ReindexProperty(ctx, tenantID, propertyID)
 
 	// Return updated property
 	return s.propertyRepo.Get(ctx, tenantID, propertyID)
@@ -961,6 +1569,7 @@ func (s *PropertyService) calculateVisibility(
 	property *models.Property,
 	newStatus *models.PropertyStatus,
 	confirmedAt *time.Time,
+	policy models.TenantPolicy,
 ) models.PropertyVisibility {
 	status := property.Status
 	if newStatus != nil {
@@ -972,11 +1581,10 @@ func (s *PropertyService) calculateVisibility(
 		return models.PropertyVisibilityPrivate
 	}
 
-	// Check if status is stale (older than hide_after_days)
-	hideAfterDays := 30 // TODO: make this configurable
+	// Check if status is stale (older than the tenant's hide_after_days)
 	if property.StatusConfirmedAt != nil {
 		daysSinceConfirmation := int(time.Since(*property.StatusConfirmedAt).Hours() / 24)
-		if daysSinceConfirmation > hideAfterDays {
+		if daysSinceConfirmation > policy.HideAfterDays && policy.AutoHides(property.Visibility) {
 			return models.PropertyVisibilityPrivate
 		}
 	} else if confirmedAt == nil {
@@ -1004,12 +1612,109 @@ func (s *PropertyService) RecalculateStalenessAndVisibility(ctx context.Context,
 		return err
 	}
 
+	_, err = s.recalculateStaleness(ctx, property, loadTenantPolicy(ctx, s.tenantRepo, tenantID))
+	return err
+}
+
+// MarkPendingConfirmation flags an available property as pending_confirmation
+// (e.g. when its owner does not answer the confirmation reminders). It reports
+// whether the property changed.
+func (s *PropertyService) MarkPendingConfirmation(ctx context.Context, tenantID, propertyID, reason string) (bool, error) {
+	property, err := s.propertyRepo.Get(ctx, tenantID, propertyID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get property: %w", err)
+	}
+	if property.Status != models.PropertyStatusAvailable {
+		return false, nil
+	}
+
 	now := time.Now()
-	updates := make(map[string]interface{})
+	updates := map[string]interface{}{
+		"status":         models.PropertyStatusPendingConfirmation,
+		"pending_reason": reason,
+	}
+	history := trackPropertyChanges(property, updates, propertyChange{
+		actorType: models.ActorTypeSystem,
+		source:    models.PropertyChangeSourceSystem,
+	}, now)
+
+	if err := s.propertyRepo.Update(ctx, tenantID, propertyID, updates); err != nil {
+		return false, fmt.Errorf("failed to update property: %w", err)
+	}
+	recordPropertyHistory(ctx, s.historyRepo, history)
+	_ = s.ReindexProperty(ctx, tenantID, propertyID)
+
+	_ = s.logActivity(ctx, tenantID, "property_pending_confirmation", models.ActorTypeSystem, "", map[string]interface{}{
+		"property_id": propertyID,
+		"reason":      reason,
+	})
+
+	return true, nil
+}
+
+// MarkUnavailable takes a property off the market after a deal, recording the
+// reason (sold, rented). It reports whether the property changed.
+func (s *PropertyService) MarkUnavailable(ctx context.Context, tenantID, propertyID, reason string) (bool, error) {
+	property, err := s.propertyRepo.Get(ctx, tenantID, propertyID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get property: %w", err)
+	}
+	if property.Status == models.PropertyStatusUnavailable && property.UnavailableReason == reason {
+		return false, nil
+	}
 
-	// Configuration (TODO: move to tenant config)
-	statusTTLDays := 15 // status becomes "pending" after this many days
-	hideAfterDays := 30 // property hidden after this many days
+	now := time.Now()
+	updates := map[string]interface{}{
+		"status":              models.PropertyStatusUnavailable,
+		"unavailable_reason":  reason,
+		"status_confirmed_at": now,
+	}
+	history := trackPropertyChanges(property, updates, propertyChange{
+		actorType: models.ActorTypeSystem,
+		source:    models.PropertyChangeSourceSystem,
+	}, now)
+
+	if err := s.propertyRepo.Update(ctx, tenantID, propertyID, updates); err != nil {
+		return false, fmt.Errorf("failed to update property: %w", err)
+	}
+	recordPropertyHistory(ctx, s.historyRepo, history)
+	_ = s.ReindexProperty(ctx, tenantID, propertyID)
+
+	_ = s.logActivity(ctx, tenantID, "property_status_changed", models.ActorTypeSystem, "", map[string]interface{}{
+		"property_id": propertyID,
+		"status":      models.PropertyStatusUnavailable,
+		"reason":      reason,
+	})
+
+	return true, nil
+}
+
+// RecalculateTenantStaleness recalculates staleness and visibility for every
+// property of a tenant and returns how many properties changed. It is run
+// daily by the background scheduler.
+func (s *PropertyService) RecalculateTenantStaleness(ctx context.Context, tenantID string) (int, error) {
+	policy := loadTenantPolicy(ctx, s.tenantRepo, tenantID)
+
+	changed := 0
+	err := forEachProperty(ctx, s.propertyRepo, tenantID, func(property *models.Property) error {
+		updated, err := s.recalculateStaleness(ctx, property, policy)
+		if err != nil {
+			return fmt.Errorf("property %s: %w", property.ID, err)
+		}
+		if updated {
+			changed++
+		}
+		return nil
+	})
+	return changed, err
+}
+
+// recalculateStaleness applies the tenant's staleness policy to a property and
+// reports whether it was updated
+func (s *PropertyService) recalculateStaleness(ctx context.Context, property *models.Property, policy models.TenantPolicy) (bool, error) {
+	tenantID, propertyID := property.TenantID, property.ID
+	now := time.Now()
+	updates := make(map[string]interface{})
 
 	// Check status staleness
 	if property.StatusConfirmedAt == nil {
@@ -1021,10 +1726,9 @@ func (s *PropertyService) RecalculateStalenessAndVisibility(ctx context.Context,
 	} else {
 		daysSinceStatus := int(now.Sub(*property.StatusConfirmedAt).Hours() / 24)
 
-		if daysSinceStatus > hideAfterDays {
+		if daysSinceStatus > policy.HideAfterDays {
 			// Hide from public
-			if property.Visibility == models.PropertyVisibilityPublic ||
-				property.Visibility == models.PropertyVisibilityMarketplace {
+			if policy.AutoHides(property.Visibility) {
 				updates["visibility"] = models.PropertyVisibilityPrivate
 				updates["pending_reason"] = "stale_status"
 
@@ -1034,7 +1738,7 @@ func (s *PropertyService) RecalculateStalenessAndVisibility(ctx context.Context,
 					"reason":                  "stale_status",
 				})
 			}
-		} else if daysSinceStatus > statusTTLDays {
+		} else if daysSinceStatus > policy.StatusTTLDays {
 			// Mark as pending confirmation
 			if property.Status != models.PropertyStatusPendingConfirmation {
 				updates["status"] = models.PropertyStatusPendingConfirmation
@@ -1043,12 +1747,54 @@ func (s *PropertyService) RecalculateStalenessAndVisibility(ctx context.Context,
 		}
 	}
 
-	// Apply updates if any
-	if len(updates) > 0 {
-		return s.propertyRepo.Update(ctx, tenantID, propertyID, updates)
+	if len(updates) == 0 {
+		return false, nil
 	}
 
-	return nil
+	history := trackPropertyChanges(property, updates, propertyChange{
+		actorType: models.ActorTypeSystem,
+		source:    models.PropertyChangeSourceSystem,
+	}, now)
+
+	if err := s.propertyRepo.Update(ctx, tenantID, propertyID, updates); err != nil {
+		return false, err
+	}
+	recordPropertyHistory(ctx, s.historyRepo, history)
+	_ = s.ReindexProperty(ctx, tenantID, propertyID)
+
+	return true, nil
+}
+
+// propertyPageSize is the page size used by forEachProperty
+const propertyPageSize = 200
+
+// forEachProperty calls fn for every property of a tenant, paging through the
+// whole collection; it stops at the first error returned by fn
+func forEachProperty(ctx context.Context, propertyRepo repositories.PropertyStore, tenantID string, fn func(*models.Property) error) error {
+	opts := repositories.PaginationOptions{
+		Limit:     propertyPageSize,
+		OrderBy:   "created_at",
+		Direction: firestore.Desc,
+	}
+
+	for {
+		properties, err := propertyRepo.List(ctx, tenantID, nil, opts)
+		if err != nil {
+			return fmt.Errorf("failed to list properties: %w", err)
+		}
+		for _, property := range properties {
+			if err := fn(property); err != nil {
+				return err
+			}
+		}
+		if len(properties) < opts.Limit {
+			return nil
+		}
+
+		last := properties[len(properties)-1]
+		opts.StartAfter = last.CreatedAt
+		opts.StartAfterID = last.ID
+	}
 }
 
 // PropertyStats represents property statistics by type and status
@@ -0,0 +1,515 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/notify"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// Proposal defaults and limits
+const (
+	defaultProposalValidity = 7 * 24 * time.Hour // Answer deadline when none is given
+	proposalExpiryBatch     = 500
+)
+
+// ProposalService handles proposals (offers and counter-offers) on properties
+type ProposalService struct {
+	proposalRepo    repositories.ProposalStore
+	leadService     *LeadService
+	propertyService *PropertyService
+	propertyRepo    repositories.PropertyStore
+	ownerRepo       repositories.OwnerStore
+	brokerRepo      repositories.BrokerStore
+	tenantRepo      repositories.TenantStore
+	activityLogRepo repositories.ActivityLogStore
+	notifier        *notify.Dispatcher // optional, see SetNotifier
+}
+
+// NewProposalService creates a new proposal service
+func NewProposalService(
+	proposalRepo repositories.ProposalStore,
+	leadService *LeadService,
+	propertyService *PropertyService,
+	propertyRepo repositories.PropertyStore,
+	ownerRepo repositories.OwnerStore,
+	brokerRepo repositories.BrokerStore,
+	tenantRepo repositories.TenantStore,
+	activityLogRepo repositories.ActivityLogStore,
+) *ProposalService {
+	return &ProposalService{
+		proposalRepo:    proposalRepo,
+		leadService:     leadService,
+		propertyService: propertyService,
+		propertyRepo:    propertyRepo,
+		ownerRepo:       ownerRepo,
+		brokerRepo:      brokerRepo,
+		tenantRepo:      tenantRepo,
+		activityLogRepo: activityLogRepo,
+	}
+}
+
+// SetNotifier enables the messages to owners about new and accepted proposals
+func (s *ProposalService) SetNotifier(notifier *notify.Dispatcher) {
+	s.notifier = notifier
+}
+
+// SubmitProposal records the offer of a lead. The property defaults to the
+// lead's and the broker to the lead's broker; the answer deadline defaults to
+// 7 days. The lead moves to negotiating and the owner is told.
+func (s *ProposalService) SubmitProposal(ctx context.Context, proposal *models.Proposal, note, actorID string) error {
+	lead, err := s.leadService.activeLead(ctx, proposal.TenantID, proposal.LeadID)
+	if err != nil {
+		return err
+	}
+
+	if proposal.PropertyID == "" {
+		proposal.PropertyID = lead.PropertyID
+	}
+	if proposal.BrokerID == "" {
+		proposal.BrokerID = lead.AssignedBrokerID
+	}
+	if proposal.TransactionType == "" {
+		proposal.TransactionType = models.TransactionTypeSale
+	}
+
+	property, err := s.propertyRepo.Get(ctx, proposal.TenantID, proposal.PropertyID)
+	if err != nil {
+		return fmt.Errorf("property not found: %w", err)
+	}
+	if property.Status == models.PropertyStatusUnavailable {
+		return fmt.Errorf("%w: property is not available", repositories.ErrInvalidInput)
+	}
+	if err := checkProposalTransaction(property, proposal.TransactionType); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if err := validateProposalTerms(&proposal.Terms, now); err != nil {
+		return err
+	}
+
+	proposal.Status = models.ProposalStatusSubmitted
+	proposal.ListPrice = proposalListPrice(property, proposal.TransactionType)
+	proposal.Rounds = []models.ProposalRound{{
+		By:        models.ProposalPartyBuyer,
+		Terms:     proposal.Terms,
+		Note:      strings.TrimSpace(note),
+		CreatedBy: actorID,
+		CreatedAt: now,
+	}}
+	proposal.DecidedAt, proposal.DecidedBy, proposal.DecisionReason = nil, "", ""
+
+	if err := s.proposalRepo.Create(ctx, proposal); err != nil {
+		return fmt.Errorf("failed to create proposal: %w", err)
+	}
+
+	// An offer means the lead is negotiating
+	switch lead.Status {
+	case models.LeadStatusNew, models.LeadStatusContacted, models.LeadStatusQualified:
+		if err := s.leadService.UpdateStatus(ctx, lead.TenantID, lead.ID, models.LeadStatusNegotiating); err != nil {
+			log.Printf("⚠️  Failed to move lead %s to negotiating: %v", lead.ID, err)
+		}
+	}
+
+	_ = s.logActivity(ctx, proposal.TenantID, "proposal_submitted", actorID, proposalLogMetadata(proposal))
+
+	s.notifyOwner(ctx, proposal, property, models.NotificationTemplateProposalReceived)
+
+	return nil
+}
+
+// CounterProposal records a counter-offer of the side the proposal is waiting
+// for: the owner answers submitted proposals and the buyer countered ones. A
+// buyer counter-offer is sent to the owner.
+func (s *ProposalService) CounterProposal(ctx context.Context, tenantID, id string, by models.ProposalParty, terms models.ProposalTerms, note, actorID string) (*models.Proposal, error) {
+	if !models.IsValidProposalParty(by) {
+		return nil, fmt.Errorf("%w: by must be 'buyer' or 'owner'", repositories.ErrInvalidInput)
+	}
+
+	proposal, err := s.openProposal(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if awaiting := proposal.AwaitingParty(); by != awaiting {
+		return nil, fmt.Errorf("%w: proposal is waiting for the %s", repositories.ErrInvalidInput, awaiting)
+	}
+
+	now := time.Now()
+	if err := validateProposalTerms(&terms, now); err != nil {
+		return nil, err
+	}
+
+	proposal.Terms = terms
+	proposal.Rounds = append(proposal.Rounds, models.ProposalRound{
+		By:        by,
+		Terms:     terms,
+		Note:      strings.TrimSpace(note),
+		CreatedBy: actorID,
+		CreatedAt: now,
+	})
+	proposal.Status = models.ProposalStatusCountered
+	if by == models.ProposalPartyBuyer {
+		proposal.Status = models.ProposalStatusSubmitted
+	}
+
+	if err := s.proposalRepo.Update(ctx, tenantID, id, map[string]interface{}{
+		"status": proposal.Status,
+		"terms":  proposal.Terms,
+		"rounds": proposal.Rounds,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to update proposal: %w", err)
+	}
+
+	metadata := proposalLogMetadata(proposal)
+	metadata["by"] = by
+	_ = s.logActivity(ctx, tenantID, "proposal_countered", actorID, metadata)
+
+	if by == models.ProposalPartyBuyer {
+		if property, err := s.propertyRepo.Get(ctx, tenantID, proposal.PropertyID); err == nil {
+			s.notifyOwner(ctx, proposal, property, models.NotificationTemplateProposalReceived)
+		}
+	}
+
+	return proposal, nil
+}
+
+// AcceptProposal accepts the current terms of an open proposal. The property
+// leaves the market (unavailable), the other open proposals on it are
+// rejected, and the owner and the lead are told.
+func (s *ProposalService) AcceptProposal(ctx context.Context, tenantID, id, actorID string) (*models.Proposal, error) {
+	proposal, err := s.openProposal(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.decide(ctx, proposal, models.ProposalStatusAccepted, actorID, ""); err != nil {
+		return nil, err
+	}
+	_ = s.logActivity(ctx, tenantID, "proposal_accepted", actorID, proposalLogMetadata(proposal))
+
+	if err := s.propertyService.UpdateStatus(ctx, tenantID, proposal.PropertyID, actorID, models.PropertyStatusUnavailable); err != nil {
+		log.Printf("⚠️  Failed to take property %s off the market after proposal %s: %v", proposal.PropertyID, proposal.ID, err)
+	}
+
+	others, err := s.proposalRepo.List(ctx, tenantID, &repositories.ProposalFilters{
+		PropertyID: proposal.PropertyID,
+		Statuses:   []models.ProposalStatus{models.ProposalStatusSubmitted, models.ProposalStatusCountered},
+	}, repositories.PaginationOptions{Limit: proposalExpiryBatch})
+	if err != nil {
+		log.Printf("⚠️  Failed to list the other proposals on property %s: %v", proposal.PropertyID, err)
+	}
+	for _, other := range others {
+		if other.ID == proposal.ID {
+			continue
+		}
+		if err := s.decide(ctx, other, models.ProposalStatusRejected, actorID, "another proposal was accepted"); err != nil {
+			log.Printf("⚠️  Failed to reject proposal %s: %v", other.ID, err)
+			continue
+		}
+		_ = s.logActivity(ctx, tenantID, "proposal_rejected", actorID, proposalLogMetadata(other))
+	}
+
+	if property, err := s.propertyRepo.Get(ctx, tenantID, proposal.PropertyID); err == nil {
+		s.notifyOwner(ctx, proposal, property, models.NotificationTemplateProposalAccepted)
+		if lead, err := s.leadService.activeLead(ctx, tenantID, proposal.LeadID); err == nil {
+			s.notify(ctx, proposal, property, models.NotificationTemplateProposalAccepted, recipient{Name: lead.Name, Phone: lead.Phone, Email: lead.Email})
+		}
+	}
+
+	return proposal, nil
+}
+
+// RejectProposal rejects an open proposal
+func (s *ProposalService) RejectProposal(ctx context.Context, tenantID, id, reason, actorID string) (*models.Proposal, error) {
+	proposal, err := s.openProposal(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.decide(ctx, proposal, models.ProposalStatusRejected, actorID, strings.TrimSpace(reason)); err != nil {
+		return nil, err
+	}
+	_ = s.logActivity(ctx, tenantID, "proposal_rejected", actorID, proposalLogMetadata(proposal))
+
+	return proposal, nil
+}
+
+// ProcessExpiredProposalsResponse summarizes a run of the proposal expiry job
+type ProcessExpiredProposalsResponse struct {
+	Expired int `json:"expired"`
+	Failed  int `json:"failed"`
+}
+
+// ProcessExpiredProposals expires the open proposals past their answer deadline
+func (s *ProposalService) ProcessExpiredProposals(ctx context.Context, tenantID string) (*ProcessExpiredProposalsResponse, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+
+	now := time.Now()
+	proposals, err := s.proposalRepo.List(ctx, tenantID, &repositories.ProposalFilters{
+		Statuses:    []models.ProposalStatus{models.ProposalStatusSubmitted, models.ProposalStatusCountered},
+		ValidBefore: &now,
+	}, repositories.PaginationOptions{Limit: proposalExpiryBatch})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expired proposals: %w", err)
+	}
+
+	response := &ProcessExpiredProposalsResponse{}
+	for _, proposal := range proposals {
+		if err := s.expire(ctx, proposal); err != nil {
+			log.Printf("⚠️  Failed to expire proposal %s: %v", proposal.ID, err)
+			response.Failed++
+			continue
+		}
+		response.Expired++
+	}
+
+	return response, nil
+}
+
+// GetProposal retrieves a proposal by ID
+func (s *ProposalService) GetProposal(ctx context.Context, tenantID, id string) (*models.Proposal, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+
+	proposal, err := s.proposalRepo.Get(ctx, tenantID, id)
+	if err != nil {
+		return nil, fmt.Errorf("proposal not found: %w", err)
+	}
+	return proposal, nil
+}
+
+// ListProposals lists proposals with filters, most recent first
+func (s *ProposalService) ListProposals(ctx context.Context, tenantID string, filters *repositories.ProposalFilters, opts repositories.PaginationOptions) ([]*models.Proposal, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+
+	proposals, err := s.proposalRepo.List(ctx, tenantID, filters, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list proposals: %w", err)
+	}
+	return proposals, nil
+}
+
+// openProposal returns a proposal still being negotiated. One past its
+// deadline is expired on the way.
+func (s *ProposalService) openProposal(ctx context.Context, tenantID, id string) (*models.Proposal, error) {
+	proposal, err := s.GetProposal(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if proposal.IsExpired(time.Now()) {
+		if err := s.expire(ctx, proposal); err != nil {
+			return nil, err
+		}
+	}
+	if !proposal.IsOpen() {
+		return nil, fmt.Errorf("%w: proposal is %s", repositories.ErrInvalidInput, proposal.Status)
+	}
+	return proposal, nil
+}
+
+// expire marks an open proposal past its deadline as expired
+func (s *ProposalService) expire(ctx context.Context, proposal *models.Proposal) error {
+	if err := s.decide(ctx, proposal, models.ProposalStatusExpired, "", ""); err != nil {
+		return err
+	}
+	_ = s.logActivity(ctx, proposal.TenantID, "proposal_expired", "", proposalLogMetadata(proposal))
+	return nil
+}
+
+// decide records the outcome of a proposal
+func (s *ProposalService) decide(ctx context.Context, proposal *models.Proposal, status models.ProposalStatus, actorID, reason string) error {
+	now := time.Now()
+	proposal.Status = status
+	proposal.DecidedAt = &now
+	proposal.DecidedBy = actorID
+	proposal.DecisionReason = reason
+
+	if err := s.proposalRepo.Update(ctx, proposal.TenantID, proposal.ID, map[string]interface{}{
+		"status":          proposal.Status,
+		"decided_at":      proposal.DecidedAt,
+		"decided_by":      proposal.DecidedBy,
+		"decision_reason": proposal.DecisionReason,
+	}); err != nil {
+		return fmt.Errorf("failed to update proposal: %w", err)
+	}
+	return nil
+}
+
+// validateProposalTerms defaults the answer deadline and validates the terms
+func validateProposalTerms(terms *models.ProposalTerms, now time.Time) error {
+	if terms.ValidUntil.IsZero() {
+		terms.ValidUntil = now.Add(defaultProposalValidity)
+	}
+	terms.PaymentConditions = strings.TrimSpace(terms.PaymentConditions)
+	if err := terms.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", repositories.ErrInvalidInput, err)
+	}
+	if !terms.ValidUntil.After(now) {
+		return fmt.Errorf("%w: valid_until must be in the future", repositories.ErrInvalidInput)
+	}
+	return nil
+}
+
+// checkProposalTransaction checks that the property is offered for the
+// transaction of the proposal (properties without a type are for sale)
+func checkProposalTransaction(property *models.Property, transaction models.TransactionType) error {
+	if transaction != models.TransactionTypeSale && transaction != models.TransactionTypeRent {
+		return fmt.Errorf("%w: transaction_type must be 'sale' or 'rent'", repositories.ErrInvalidInput)
+	}
+
+	offered := models.TransactionTypeSale
+	if property.TransactionType != nil {
+		offered = *property.TransactionType
+	}
+	if offered != models.TransactionTypeBoth && offered != transaction {
+		return fmt.Errorf("%w: property is not offered for %s", repositories.ErrInvalidInput, transaction)
+	}
+	return nil
+}
+
+// proposalListPrice returns the asking price of the property for the transaction
+func proposalListPrice(property *models.Property, transaction models.TransactionType) float64 {
+	if transaction == models.TransactionTypeRent && property.RentalInfo != nil && property.RentalInfo.MonthlyRent > 0 {
+		return property.RentalInfo.MonthlyRent
+	}
+	return property.PriceAmount
+}
+
+// ProposalMessageData is the data available to the proposal templates
+type ProposalMessageData struct {
+	RecipientName     string
+	TenantName        string
+	BrokerName        string
+	PropertyReference string
+	PropertyAddress   string
+	Amount            string // R$ 1.234,56
+	PaymentConditions string // Summary of the payment terms
+	ValidUntil        string // dd/mm/yyyy
+	Counter           bool   // Counter-offer of the buyer
+}
+
+// notifyOwner sends a proposal message to the owner of the property, when known
+func (s *ProposalService) notifyOwner(ctx context.Context, proposal *models.Proposal, property *models.Property, name string) {
+	if s.notifier == nil || property.OwnerID == "" {
+		return
+	}
+
+	owner, err := s.ownerRepo.Get(ctx, proposal.TenantID, property.OwnerID)
+	if err != nil {
+		log.Printf("⚠️  Failed to load owner %s of proposal %s: %v", property.OwnerID, proposal.ID, err)
+		return
+	}
+	if owner.IsAnonymized {
+		return
+	}
+
+	s.notify(ctx, proposal, property, name, recipient{Name: owner.Name, Phone: owner.Phone, Email: owner.Email})
+}
+
+// notify sends a proposal message; failures are logged, never returned
+func (s *ProposalService) notify(ctx context.Context, proposal *models.Proposal, property *models.Property, name string, to recipient) {
+	if s.notifier == nil {
+		return
+	}
+
+	tenant, err := s.tenantRepo.Get(ctx, proposal.TenantID)
+	if err != nil {
+		log.Printf("⚠️  Failed to load tenant %s for proposal %s: %v", proposal.TenantID, proposal.ID, err)
+		return
+	}
+
+	data := ProposalMessageData{
+		RecipientName:     firstName(to.Name),
+		TenantName:        tenant.Name,
+		PropertyReference: propertyReference(property),
+		PropertyAddress:   propertyAddress(property),
+		Amount:            formatBRL(proposal.Terms.Amount),
+		PaymentConditions: paymentSummary(proposal.Terms),
+		ValidUntil:        proposal.Terms.ValidUntil.In(models.DefaultBrokerAvailability().Location()).Format("02/01/2006"),
+		Counter:           len(proposal.Rounds) > 1,
+	}
+	if proposal.TransactionType == models.TransactionTypeRent {
+		data.Amount += "/mês"
+	}
+	if proposal.BrokerID != "" {
+		if broker, err := s.brokerRepo.Get(ctx, proposal.TenantID, proposal.BrokerID); err == nil {
+			data.BrokerName = broker.Name
+		}
+	}
+
+	_, _, _, err = sendNotification(ctx, s.notifier, tenant, name, to, data,
+		[]string{data.RecipientName, data.PropertyReference, data.Amount})
+	if err != nil {
+		log.Printf("⚠️  Failed to send %s of proposal %s: %v", name, proposal.ID, err)
+	}
+}
+
+// paymentSummary describes the payment terms of a proposal in Portuguese
+func paymentSummary(terms models.ProposalTerms) string {
+	var parts []string
+	if terms.DownPayment > 0 {
+		parts = append(parts, "entrada de "+formatBRL(terms.DownPayment))
+	}
+	if terms.Financing {
+		financing := "financiamento"
+		if terms.FinancingAmount > 0 {
+			financing += " de " + formatBRL(terms.FinancingAmount)
+		}
+		if terms.FinancingBank != "" {
+			financing += " (" + terms.FinancingBank + ")"
+		}
+		parts = append(parts, financing)
+	}
+	if terms.FGTSAmount > 0 {
+		parts = append(parts, "FGTS de "+formatBRL(terms.FGTSAmount))
+	}
+	if terms.Installments > 0 {
+		parts = append(parts, fmt.Sprintf("%d parcelas", terms.Installments))
+	}
+	if terms.PaymentConditions != "" {
+		parts = append(parts, terms.PaymentConditions)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// proposalLogMetadata returns the activity log metadata of a proposal
+func proposalLogMetadata(proposal *models.Proposal) map[string]interface{} {
+	return map[string]interface{}{
+		"proposal_id": proposal.ID,
+		"lead_id":     proposal.LeadID,
+		"property_id": proposal.PropertyID,
+		"broker_id":   proposal.BrokerID,
+		"amount":      proposal.Terms.Amount,
+		"status":      proposal.Status,
+	}
+}
+
+// logActivity logs an activity; actions without an actor are the system's
+func (s *ProposalService) logActivity(ctx context.Context, tenantID, eventType, actorID string, metadata map[string]interface{}) error {
+	actorType := models.ActorTypeUser
+	if actorID == "" {
+		actorType = models.ActorTypeSystem
+	}
+
+	log := &models.ActivityLog{
+		TenantID:  tenantID,
+		EventType: eventType,
+		ActorType: actorType,
+		ActorID:   actorID,
+		Metadata:  metadata,
+		Timestamp: time.Now(),
+	}
+
+	return s.activityLogRepo.Create(ctx, log)
+}
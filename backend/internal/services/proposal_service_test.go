@@ -0,0 +1,197 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/notify"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories/memory"
+)
+
+// newProposalTestService returns a proposal service over the routing test
+// data: property "moema" (available, R$ 850.000) belongs to owner-1 ("Maria
+// Lima") and is shown by broker-3
+func newProposalTestService(t *testing.T) (*ProposalService, *notify.FakeNotifier) {
+	t.Helper()
+	ctx := context.Background()
+
+	leadService, whatsapp := newActivityTestService(t)
+	ownerRepo := memory.NewOwnerRepository()
+	require.NoError(t, ownerRepo.Create(ctx, &models.Owner{ID: "owner-1", TenantID: "tenant-1", Name: "Maria Lima", Phone: "(11) 96666-5555"}))
+	require.NoError(t, leadService.propertyRepo.Update(ctx, "tenant-1", "moema", map[string]interface{}{
+		"status":       models.PropertyStatusAvailable,
+		"reference":    "AP00335",
+		"owner_id":     "owner-1",
+		"price_amount": 850000.0,
+	}))
+
+	propertyService := NewPropertyService(leadService.propertyRepo, memory.NewListingRepository(), ownerRepo, leadService.brokerRepo, leadService.tenantRepo, leadService.activityLogRepo)
+	service := NewProposalService(
+		memory.NewProposalRepository(),
+		leadService,
+		propertyService,
+		leadService.propertyRepo,
+		ownerRepo,
+		leadService.brokerRepo,
+		leadService.tenantRepo,
+		leadService.activityLogRepo,
+	)
+	service.SetNotifier(notify.NewDispatcher(notify.RetryPolicy{MaxAttempts: 1}, whatsapp))
+	return service, whatsapp
+}
+
+func submitTestProposal(t *testing.T, service *ProposalService, amount float64) *models.Proposal {
+	t.Helper()
+	lead := createRoutingTestLead(t, service.leadService, "moema")
+	proposal := &models.Proposal{
+		TenantID: "tenant-1",
+		LeadID:   lead.ID,
+		Terms: models.ProposalTerms{
+			Amount:          amount,
+			DownPayment:     100000,
+			Financing:       true,
+			FinancingAmount: 600000,
+			FinancingBank:   "Caixa",
+		},
+	}
+	require.NoError(t, service.SubmitProposal(context.Background(), proposal, "", "broker-3"))
+	return proposal
+}
+
+func TestSubmitProposal_MovesLeadToNegotiatingAndNotifiesOwner(t *testing.T) {
+	ctx := context.Background()
+	service, whatsapp := newProposalTestService(t)
+
+	proposal := submitTestProposal(t, service, 800000)
+	assert.NotEmpty(t, proposal.ID)
+	assert.Equal(t, "moema", proposal.PropertyID)
+	assert.Equal(t, "broker-3", proposal.BrokerID)
+	assert.Equal(t, models.TransactionTypeSale, proposal.TransactionType)
+	assert.Equal(t, models.ProposalStatusSubmitted, proposal.Status)
+	assert.Equal(t, 850000.0, proposal.ListPrice)
+	assert.WithinDuration(t, time.Now().Add(defaultProposalValidity), proposal.Terms.ValidUntil, time.Minute)
+	require.Len(t, proposal.Rounds, 1)
+	assert.Equal(t, models.ProposalPartyBuyer, proposal.Rounds[0].By)
+
+	lead, err := service.leadService.GetLead(ctx, "tenant-1", proposal.LeadID)
+	require.NoError(t, err)
+	assert.Equal(t, models.LeadStatusNegotiating, lead.Status)
+
+	sent := whatsapp.Sent()
+	require.Len(t, sent, 1)
+	assert.Equal(t, "(11) 96666-5555", sent[0].To)
+	assert.Contains(t, sent[0].Body, "Maria")
+	assert.Contains(t, sent[0].Body, "R$ 800.000,00")
+	assert.Contains(t, sent[0].Body, "AP00335")
+
+	// Terms are validated and the property must be offered for the transaction
+	invalid := &models.Proposal{TenantID: "tenant-1", LeadID: proposal.LeadID, Terms: models.ProposalTerms{Amount: 100000, DownPayment: 150000}}
+	assert.True(t, errors.Is(service.SubmitProposal(ctx, invalid, "", "broker-3"), repositories.ErrInvalidInput))
+	rent := &models.Proposal{TenantID: "tenant-1", LeadID: proposal.LeadID, TransactionType: models.TransactionTypeRent, Terms: models.ProposalTerms{Amount: 4000}}
+	assert.True(t, errors.Is(service.SubmitProposal(ctx, rent, "", "broker-3"), repositories.ErrInvalidInput))
+}
+
+func TestCounterProposal_AlternatesParties(t *testing.T) {
+	ctx := context.Background()
+	service, whatsapp := newProposalTestService(t)
+	proposal := submitTestProposal(t, service, 780000)
+
+	// Submitted proposals wait for the owner
+	_, err := service.CounterProposal(ctx, "tenant-1", proposal.ID, models.ProposalPartyBuyer, models.ProposalTerms{Amount: 790000}, "", "broker-3")
+	assert.True(t, errors.Is(err, repositories.ErrInvalidInput))
+
+	countered, err := service.CounterProposal(ctx, "tenant-1", proposal.ID, models.ProposalPartyOwner, models.ProposalTerms{Amount: 830000}, "Aceito 830 mil à vista", "broker-3")
+	require.NoError(t, err)
+	assert.Equal(t, models.ProposalStatusCountered, countered.Status)
+	assert.Equal(t, models.ProposalPartyBuyer, countered.AwaitingParty())
+	assert.Equal(t, 830000.0, countered.Terms.Amount)
+
+	countered, err = service.CounterProposal(ctx, "tenant-1", proposal.ID, models.ProposalPartyBuyer, models.ProposalTerms{Amount: 815000, DownPayment: 315000}, "", "broker-3")
+	require.NoError(t, err)
+	assert.Equal(t, models.ProposalStatusSubmitted, countered.Status)
+	require.Len(t, countered.Rounds, 3)
+
+	// The buyer counter-offer reaches the owner
+	sent := whatsapp.Sent()
+	require.Len(t, sent, 2)
+	assert.Equal(t, "(11) 96666-5555", sent[1].To)
+	assert.Contains(t, sent[1].Body, "R$ 815.000,00")
+
+	stored, err := service.GetProposal(ctx, "tenant-1", proposal.ID)
+	require.NoError(t, err)
+	assert.Equal(t, countered.Terms.Amount, stored.Terms.Amount)
+	assert.Len(t, stored.Rounds, 3)
+}
+
+func TestAcceptProposal_TakesPropertyOffMarketAndRejectsOthers(t *testing.T) {
+	ctx := context.Background()
+	service, whatsapp := newProposalTestService(t)
+	accepted := submitTestProposal(t, service, 820000)
+	other := submitTestProposal(t, service, 800000)
+
+	proposal, err := service.AcceptProposal(ctx, "tenant-1", accepted.ID, "broker-3")
+	require.NoError(t, err)
+	assert.Equal(t, models.ProposalStatusAccepted, proposal.Status)
+	assert.NotNil(t, proposal.DecidedAt)
+	assert.Equal(t, "broker-3", proposal.DecidedBy)
+
+	property, err := service.propertyRepo.Get(ctx, "tenant-1", "moema")
+	require.NoError(t, err)
+	assert.Equal(t, models.PropertyStatusUnavailable, property.Status)
+
+	rejected, err := service.GetProposal(ctx, "tenant-1", other.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ProposalStatusRejected, rejected.Status)
+	assert.Equal(t, "another proposal was accepted", rejected.DecisionReason)
+
+	// Decided proposals cannot change
+	_, err = service.RejectProposal(ctx, "tenant-1", accepted.ID, "", "broker-3")
+	assert.True(t, errors.Is(err, repositories.ErrInvalidInput))
+
+	// Owner told of both offers, then of the acceptance (the lead has no phone)
+	sent := whatsapp.Sent()
+	require.Len(t, sent, 3)
+	assert.Equal(t, "(11) 96666-5555", sent[2].To)
+	assert.Contains(t, sent[2].Body, "R$ 820.000,00")
+}
+
+func TestProcessExpiredProposals(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newProposalTestService(t)
+	expired := submitTestProposal(t, service, 800000)
+	open := submitTestProposal(t, service, 810000)
+
+	require.NoError(t, service.proposalRepo.Update(ctx, "tenant-1", expired.ID, map[string]interface{}{
+		"terms.valid_until": time.Now().Add(-time.Hour),
+	}))
+
+	response, err := service.ProcessExpiredProposals(ctx, "tenant-1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, response.Expired)
+
+	stored, err := service.GetProposal(ctx, "tenant-1", expired.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ProposalStatusExpired, stored.Status)
+	assert.Empty(t, stored.DecidedBy)
+
+	stored, err = service.GetProposal(ctx, "tenant-1", open.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ProposalStatusSubmitted, stored.Status)
+
+	_, err = service.AcceptProposal(ctx, "tenant-1", expired.ID, "broker-3")
+	assert.True(t, errors.Is(err, repositories.ErrInvalidInput))
+}
+
+func TestFormatBRL(t *testing.T) {
+	assert.Equal(t, "R$ 0,00", formatBRL(0))
+	assert.Equal(t, "R$ 999,90", formatBRL(999.9))
+	assert.Equal(t, "R$ 1.234.567,89", formatBRL(1234567.891))
+	assert.Equal(t, "-R$ 1.500,00", formatBRL(-1500))
+}
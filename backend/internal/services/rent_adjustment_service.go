@@ -0,0 +1,625 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// Bounds of the upcoming adjustments report, in months
+const (
+	defaultUpcomingAdjustmentMonths = 3
+	maxUpcomingAdjustmentMonths     = 12
+)
+
+// RentAdjustmentService keeps the monthly index series and proposes the
+// annual rent adjustment (reajuste) of the active rental contracts
+type RentAdjustmentService struct {
+	indexRepo       repositories.IndexRateStore
+	adjustmentRepo  repositories.RentAdjustmentStore
+	contractRepo    repositories.RentalContractStore
+	activityLogRepo repositories.ActivityLogStore
+}
+
+// NewRentAdjustmentService creates a new rent adjustment service
+func NewRentAdjustmentService(
+	indexRepo repositories.IndexRateStore,
+	adjustmentRepo repositories.RentAdjustmentStore,
+	contractRepo repositories.RentalContractStore,
+	activityLogRepo repositories.ActivityLogStore,
+) *RentAdjustmentService {
+	return &RentAdjustmentService{
+		indexRepo:       indexRepo,
+		adjustmentRepo:  adjustmentRepo,
+		contractRepo:    contractRepo,
+		activityLogRepo: activityLogRepo,
+	}
+}
+
+// ImportIndexResponse summarizes an index series import
+type ImportIndexResponse struct {
+	Imported int      `json:"imported"`
+	Errors   []string `json:"errors,omitempty"` // Rejected lines
+}
+
+// ImportIndexCSV imports monthly index variations from a CSV with the columns
+// period;rate (of defaultIndex) or index;period;rate. Separators may be ';' or
+// ',', periods YYYY-MM or MM/YYYY and rates in % with a decimal point or comma
+// (0,52 = 0,52%). A header line is skipped; imported months replace the
+// stored ones.
+func (s *RentAdjustmentService) ImportIndexCSV(ctx context.Context, defaultIndex models.IndexationType, data []byte) (*ImportIndexResponse, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	if defaultIndex != "" {
+		defaultIndex = parseIndexationType(string(defaultIndex))
+		if !models.IsValidIndexationType(defaultIndex) {
+			return nil, fmt.Errorf("%w: invalid index %s", repositories.ErrInvalidInput, defaultIndex)
+		}
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = ','
+	if firstLine, _, _ := bytes.Cut(data, []byte("\n")); bytes.Contains(firstLine, []byte(";")) {
+		reader.Comma = ';'
+	}
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	response := &ImportIndexResponse{}
+	rates := make(map[string]*models.IndexRate)
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid CSV: %v", repositories.ErrInvalidInput, err)
+		}
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+
+		rate, err := parseIndexRecord(record, defaultIndex)
+		if err != nil {
+			if line == 1 {
+				continue // header
+			}
+			response.Errors = append(response.Errors, fmt.Sprintf("line %d: %v", line, err))
+			continue
+		}
+		rates[models.IndexRateID(rate.Index, rate.Period)] = rate
+	}
+
+	if len(rates) == 0 {
+		return response, fmt.Errorf("%w: no index months found", repositories.ErrInvalidInput)
+	}
+
+	imported := make([]*models.IndexRate, 0, len(rates))
+	for _, rate := range rates {
+		imported = append(imported, rate)
+	}
+	if err := s.indexRepo.Upsert(ctx, imported); err != nil {
+		return nil, fmt.Errorf("failed to import index rates: %w", err)
+	}
+	response.Imported = len(imported)
+
+	return response, nil
+}
+
+// ListIndex lists the stored months of an index between two periods
+// (YYYY-MM, inclusive; empty is unbounded), oldest first
+func (s *RentAdjustmentService) ListIndex(ctx context.Context, index models.IndexationType, from, to string) ([]*models.IndexRate, error) {
+	index = parseIndexationType(string(index))
+	if !models.IsValidIndexationType(index) {
+		return nil, fmt.Errorf("%w: invalid index %s", repositories.ErrInvalidInput, index)
+	}
+
+	rates, err := s.indexRepo.List(ctx, index, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list index rates: %w", err)
+	}
+	return rates, nil
+}
+
+// IndexCalculation is the accumulated variation of an index for an
+// adjustment effective in a month
+type IndexCalculation struct {
+	Index           models.IndexationType `json:"index"`
+	EffectivePeriod string                `json:"effective_period"`
+	ReferenceFrom   string                `json:"reference_from"`
+	ReferenceTo     string                `json:"reference_to"`
+	Months          int                   `json:"months"`
+	Variation       float64               `json:"variation"` // %
+}
+
+// Calculate accumulates the 12 months of an index before the effective
+// month. It fails while any of them is not published (stored) yet.
+func (s *RentAdjustmentService) Calculate(ctx context.Context, index models.IndexationType, effective time.Time) (*IndexCalculation, error) {
+	index = parseIndexationType(string(index))
+	if !models.IsValidIndexationType(index) {
+		return nil, fmt.Errorf("%w: invalid index %s", repositories.ErrInvalidInput, index)
+	}
+
+	from, to := models.AdjustmentReference(effective)
+	rates, err := s.indexRepo.List(ctx, index, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list index rates: %w", err)
+	}
+	if len(rates) < 12 {
+		return nil, fmt.Errorf("%w: %s has %d of the 12 months from %s to %s", repositories.ErrInvalidInput, index, len(rates), from, to)
+	}
+
+	return &IndexCalculation{
+		Index:           index,
+		EffectivePeriod: effective.Format(models.PeriodLayout),
+		ReferenceFrom:   from,
+		ReferenceTo:     to,
+		Months:          len(rates),
+		Variation:       models.AccumulatedVariation(indexRateValues(rates)),
+	}, nil
+}
+
+// ProposeAdjustmentsResponse summarizes a run of the rent adjustment job
+type ProposeAdjustmentsResponse struct {
+	Proposed int `json:"proposed"`
+	Pending  int `json:"pending"` // Index months not published yet; retried on the next run
+	Failed   int `json:"failed"`
+}
+
+// ProposeAdjustments proposes the adjusted rent of the active contracts with
+// an adjustment due in the current or the next month. A contract gets one
+// proposal per adjustment month.
+func (s *RentAdjustmentService) ProposeAdjustments(ctx context.Context, tenantID string, now time.Time) (*ProposeAdjustmentsResponse, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+
+	contracts, err := s.activeContracts(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ProposeAdjustmentsResponse{}
+	calculations := make(map[string]*IndexCalculation)
+	for _, contract := range contracts {
+		for _, effective := range []time.Time{monthStart(now), monthStart(now).AddDate(0, 1, 0)} {
+			if !adjustmentDue(contract, effective) {
+				continue
+			}
+
+			existing, err := s.contractAdjustment(ctx, tenantID, contract.ID, effective.Format(models.PeriodLayout))
+			if err != nil {
+				log.Printf("⚠️  Failed to check rent adjustment of contract %s: %v", contract.ID, err)
+				response.Failed++
+				continue
+			}
+			if existing != nil {
+				continue
+			}
+
+			key := models.IndexRateID(contract.IndexationType, effective.Format(models.PeriodLayout))
+			calculation, ok := calculations[key]
+			if !ok {
+				calculation, err = s.Calculate(ctx, contract.IndexationType, effective)
+				if err != nil {
+					calculation = nil
+				}
+				calculations[key] = calculation
+			}
+			if calculation == nil {
+				response.Pending++
+				continue
+			}
+
+			adjustment := &models.RentAdjustment{
+				TenantID:        tenantID,
+				ContractID:      contract.ID,
+				PropertyID:      contract.PropertyID,
+				Index:           calculation.Index,
+				EffectivePeriod: calculation.EffectivePeriod,
+				ReferenceFrom:   calculation.ReferenceFrom,
+				ReferenceTo:     calculation.ReferenceTo,
+				Variation:       calculation.Variation,
+				CurrentRent:     contract.MonthlyRent,
+				ProposedRent:    models.AdjustedRent(contract.MonthlyRent, calculation.Variation),
+				Status:          models.RentAdjustmentStatusProposed,
+			}
+			if err := s.adjustmentRepo.Create(ctx, adjustment); err != nil {
+				log.Printf("⚠️  Failed to propose rent adjustment of contract %s: %v", contract.ID, err)
+				response.Failed++
+				continue
+			}
+			_ = s.logActivity(ctx, tenantID, "rent_adjustment_proposed", "", adjustmentLogMetadata(adjustment))
+			response.Proposed++
+		}
+	}
+
+	return response, nil
+}
+
+// ApplyAdjustment sets the proposed rent on the contract
+func (s *RentAdjustmentService) ApplyAdjustment(ctx context.Context, tenantID, id, actorID string) (*models.RentAdjustment, error) {
+	adjustment, err := s.proposedAdjustment(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	contract, err := s.contractRepo.Get(ctx, tenantID, adjustment.ContractID)
+	if err != nil {
+		return nil, fmt.Errorf("rental contract not found: %w", err)
+	}
+	if !contract.IsActive() {
+		return nil, fmt.Errorf("%w: contract is %s", repositories.ErrInvalidInput, contract.Status)
+	}
+	if contract.MonthlyRent != adjustment.CurrentRent {
+		return nil, fmt.Errorf("%w: contract rent changed since the proposal; dismiss it and calculate again", repositories.ErrInvalidInput)
+	}
+
+	if err := s.contractRepo.Update(ctx, tenantID, contract.ID, map[string]interface{}{
+		"monthly_rent": adjustment.ProposedRent,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to update rental contract: %w", err)
+	}
+
+	if err := s.decide(ctx, adjustment, models.RentAdjustmentStatusApplied, "", actorID); err != nil {
+		return nil, err
+	}
+	_ = s.logActivity(ctx, tenantID, "rent_adjustment_applied", actorID, adjustmentLogMetadata(adjustment))
+
+	return adjustment, nil
+}
+
+// DismissAdjustment discards a proposal, e.g. when the rent was negotiated
+// with the tenant
+func (s *RentAdjustmentService) DismissAdjustment(ctx context.Context, tenantID, id, reason, actorID string) (*models.RentAdjustment, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, fmt.Errorf("%w: reason is required", repositories.ErrInvalidInput)
+	}
+
+	adjustment, err := s.proposedAdjustment(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.decide(ctx, adjustment, models.RentAdjustmentStatusDismissed, reason, actorID); err != nil {
+		return nil, err
+	}
+	_ = s.logActivity(ctx, tenantID, "rent_adjustment_dismissed", actorID, adjustmentLogMetadata(adjustment))
+
+	return adjustment, nil
+}
+
+// GetAdjustment retrieves a rent adjustment by ID
+func (s *RentAdjustmentService) GetAdjustment(ctx context.Context, tenantID, id string) (*models.RentAdjustment, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+
+	adjustment, err := s.adjustmentRepo.Get(ctx, tenantID, id)
+	if err != nil {
+		return nil, fmt.Errorf("rent adjustment not found: %w", err)
+	}
+	return adjustment, nil
+}
+
+// ListAdjustments lists rent adjustments with filters, latest effective month first
+func (s *RentAdjustmentService) ListAdjustments(ctx context.Context, tenantID string, filters *repositories.RentAdjustmentFilters, opts repositories.PaginationOptions) ([]*models.RentAdjustment, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+
+	adjustments, err := s.adjustmentRepo.List(ctx, tenantID, filters, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rent adjustments: %w", err)
+	}
+	return adjustments, nil
+}
+
+// UpcomingAdjustment is an adjustment due in the coming months
+type UpcomingAdjustment struct {
+	ContractID      string                `json:"contract_id"`
+	PropertyID      string                `json:"property_id"`
+	Tenant          models.RentalTenant   `json:"tenant"`
+	Index           models.IndexationType `json:"index"`
+	EffectivePeriod string                `json:"effective_period"`
+	ReferenceFrom   string                `json:"reference_from"`
+	ReferenceTo     string                `json:"reference_to"`
+	Variation       float64               `json:"variation"`
+	CurrentRent     float64               `json:"current_rent"`
+	ProposedRent    float64               `json:"proposed_rent"`
+
+	// Estimated is set while the reference months are not all published: the
+	// variation is then the one of the latest 12 published months
+	Estimated bool `json:"estimated"`
+
+	// Proposal already made for the month, if any
+	AdjustmentID string                      `json:"adjustment_id,omitempty"`
+	Status       models.RentAdjustmentStatus `json:"status,omitempty"`
+}
+
+// UpcomingAdjustments reports the adjustments due from the current month over
+// the given number of months (default 3, up to 12), by month and tenant
+func (s *RentAdjustmentService) UpcomingAdjustments(ctx context.Context, tenantID string, months int, now time.Time) ([]*UpcomingAdjustment, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+	if months <= 0 {
+		months = defaultUpcomingAdjustmentMonths
+	}
+	if months > maxUpcomingAdjustmentMonths {
+		months = maxUpcomingAdjustmentMonths
+	}
+
+	contracts, err := s.activeContracts(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	upcoming := make([]*UpcomingAdjustment, 0)
+	calculations := make(map[string]*IndexCalculation)
+	estimates := make(map[models.IndexationType]float64)
+	for _, contract := range contracts {
+		for offset := 0; offset < months; offset++ {
+			effective := monthStart(now).AddDate(0, offset, 0)
+			if !adjustmentDue(contract, effective) {
+				continue
+			}
+
+			period := effective.Format(models.PeriodLayout)
+			from, to := models.AdjustmentReference(effective)
+			item := &UpcomingAdjustment{
+				ContractID:      contract.ID,
+				PropertyID:      contract.PropertyID,
+				Tenant:          contract.Tenant,
+				Index:           contract.IndexationType,
+				EffectivePeriod: period,
+				ReferenceFrom:   from,
+				ReferenceTo:     to,
+				CurrentRent:     contract.MonthlyRent,
+			}
+
+			existing, err := s.contractAdjustment(ctx, tenantID, contract.ID, period)
+			if err != nil {
+				return nil, err
+			}
+			if existing != nil {
+				item.AdjustmentID = existing.ID
+				item.Status = existing.Status
+				item.Variation = existing.Variation
+				item.CurrentRent = existing.CurrentRent
+				item.ProposedRent = existing.ProposedRent
+				upcoming = append(upcoming, item)
+				continue
+			}
+
+			key := models.IndexRateID(contract.IndexationType, period)
+			calculation, ok := calculations[key]
+			if !ok {
+				calculation, _ = s.Calculate(ctx, contract.IndexationType, effective)
+				calculations[key] = calculation
+			}
+			if calculation != nil {
+				item.Variation = calculation.Variation
+			} else {
+				estimate, ok := estimates[contract.IndexationType]
+				if !ok {
+					estimate, err = s.latestVariation(ctx, contract.IndexationType)
+					if err != nil {
+						return nil, err
+					}
+					estimates[contract.IndexationType] = estimate
+				}
+				item.Variation = estimate
+				item.Estimated = true
+			}
+			item.ProposedRent = models.AdjustedRent(contract.MonthlyRent, item.Variation)
+			upcoming = append(upcoming, item)
+		}
+	}
+
+	sort.SliceStable(upcoming, func(i, j int) bool {
+		if upcoming[i].EffectivePeriod != upcoming[j].EffectivePeriod {
+			return upcoming[i].EffectivePeriod < upcoming[j].EffectivePeriod
+		}
+		return upcoming[i].Tenant.Name < upcoming[j].Tenant.Name
+	})
+
+	return upcoming, nil
+}
+
+// activeContracts returns the contracts in force
+func (s *RentAdjustmentService) activeContracts(ctx context.Context, tenantID string) ([]*models.RentalContract, error) {
+	contracts, err := s.contractRepo.List(ctx, tenantID, &repositories.RentalContractFilters{
+		Statuses: []models.RentalContractStatus{models.RentalContractStatusActive},
+	}, repositories.PaginationOptions{Limit: rentalContractBatch})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rental contracts: %w", err)
+	}
+	return contracts, nil
+}
+
+// contractAdjustment returns the adjustment of a contract for a month, if any
+func (s *RentAdjustmentService) contractAdjustment(ctx context.Context, tenantID, contractID, period string) (*models.RentAdjustment, error) {
+	adjustments, err := s.adjustmentRepo.List(ctx, tenantID, &repositories.RentAdjustmentFilters{
+		ContractID:      contractID,
+		EffectivePeriod: period,
+	}, repositories.PaginationOptions{Limit: 1})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rent adjustments: %w", err)
+	}
+	if len(adjustments) == 0 {
+		return nil, nil
+	}
+	return adjustments[0], nil
+}
+
+// proposedAdjustment returns an adjustment still awaiting a decision
+func (s *RentAdjustmentService) proposedAdjustment(ctx context.Context, tenantID, id string) (*models.RentAdjustment, error) {
+	adjustment, err := s.GetAdjustment(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if adjustment.Status != models.RentAdjustmentStatusProposed {
+		return nil, fmt.Errorf("%w: adjustment is %s", repositories.ErrInvalidInput, adjustment.Status)
+	}
+	return adjustment, nil
+}
+
+// decide records the decision on a proposal
+func (s *RentAdjustmentService) decide(ctx context.Context, adjustment *models.RentAdjustment, status models.RentAdjustmentStatus, reason, actorID string) error {
+	now := time.Now()
+	adjustment.Status = status
+	adjustment.DecidedAt = &now
+	adjustment.DecidedBy = actorID
+	adjustment.DismissReason = reason
+
+	updates := map[string]interface{}{
+		"status":     adjustment.Status,
+		"decided_at": now,
+		"decided_by": actorID,
+	}
+	if reason != "" {
+		updates["dismiss_reason"] = reason
+	}
+	if err := s.adjustmentRepo.Update(ctx, adjustment.TenantID, adjustment.ID, updates); err != nil {
+		return fmt.Errorf("failed to update rent adjustment: %w", err)
+	}
+	return nil
+}
+
+// latestVariation accumulates the latest 12 published months of an index (0
+// while fewer are stored)
+func (s *RentAdjustmentService) latestVariation(ctx context.Context, index models.IndexationType) (float64, error) {
+	rates, err := s.indexRepo.List(ctx, index, "", "")
+	if err != nil {
+		return 0, fmt.Errorf("failed to list index rates: %w", err)
+	}
+	if len(rates) < 12 {
+		return 0, nil
+	}
+	return models.AccumulatedVariation(indexRateValues(rates[len(rates)-12:])), nil
+}
+
+// adjustmentDue reports whether a contract's annual adjustment falls in a
+// month: its adjustment month, at least a year after the start and before
+// the end of the contract
+func adjustmentDue(contract *models.RentalContract, month time.Time) bool {
+	if int(month.Month()) != contract.AdjustmentMonth {
+		return false
+	}
+	if month.Before(monthStart(contract.StartDate).AddDate(1, 0, 0)) {
+		return false
+	}
+	return !month.After(contract.EndDate)
+}
+
+// monthStart returns the first day of the month of t (UTC)
+func monthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// indexRateValues returns the monthly variations of index months
+func indexRateValues(rates []*models.IndexRate) []float64 {
+	values := make([]float64, len(rates))
+	for i, rate := range rates {
+		values[i] = rate.Rate
+	}
+	return values
+}
+
+// parseIndexRecord parses a CSV line of an index series
+func parseIndexRecord(record []string, defaultIndex models.IndexationType) (*models.IndexRate, error) {
+	for i := range record {
+		record[i] = strings.TrimSpace(record[i])
+	}
+
+	rate := &models.IndexRate{Index: defaultIndex}
+	var period, value string
+	switch {
+	case len(record) == 2:
+		period, value = record[0], record[1]
+	case len(record) >= 3:
+		rate.Index = parseIndexationType(record[0])
+		period, value = record[1], record[2]
+	default:
+		return nil, fmt.Errorf("expected period;rate or index;period;rate")
+	}
+	if !models.IsValidIndexationType(rate.Index) {
+		return nil, fmt.Errorf("invalid index %q", rate.Index)
+	}
+
+	parsed, err := parseIndexPeriod(period)
+	if err != nil {
+		return nil, err
+	}
+	rate.Period = parsed
+
+	value = strings.TrimSpace(strings.TrimSuffix(value, "%"))
+	if strings.Contains(value, ",") {
+		value = strings.ReplaceAll(strings.ReplaceAll(value, ".", ""), ",", ".")
+	}
+	rate.Rate, err = strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rate %q", value)
+	}
+
+	return rate, nil
+}
+
+// parseIndexPeriod normalizes a YYYY-MM or MM/YYYY month to YYYY-MM
+func parseIndexPeriod(period string) (string, error) {
+	for _, layout := range []string{models.PeriodLayout, "01/2006"} {
+		if t, err := time.Parse(layout, period); err == nil {
+			return t.Format(models.PeriodLayout), nil
+		}
+	}
+	return "", fmt.Errorf("invalid period %q", period)
+}
+
+// parseIndexationType normalizes index names such as "IGP-M" to their type
+func parseIndexationType(index string) models.IndexationType {
+	return models.IndexationType(strings.ToLower(strings.ReplaceAll(strings.TrimSpace(index), "-", "")))
+}
+
+// adjustmentLogMetadata returns the activity log metadata of a rent adjustment
+func adjustmentLogMetadata(adjustment *models.RentAdjustment) map[string]interface{} {
+	return map[string]interface{}{
+		"adjustment_id":    adjustment.ID,
+		"contract_id":      adjustment.ContractID,
+		"property_id":      adjustment.PropertyID,
+		"effective_period": adjustment.EffectivePeriod,
+		"variation":        adjustment.Variation,
+		"current_rent":     adjustment.CurrentRent,
+		"proposed_rent":    adjustment.ProposedRent,
+		"status":           adjustment.Status,
+	}
+}
+
+// logActivity logs an activity; actions without an actor are the system's
+func (s *RentAdjustmentService) logActivity(ctx context.Context, tenantID, eventType, actorID string, metadata map[string]interface{}) error {
+	actorType := models.ActorTypeUser
+	if actorID == "" {
+		actorType = models.ActorTypeSystem
+	}
+
+	log := &models.ActivityLog{
+		TenantID:  tenantID,
+		EventType: eventType,
+		ActorType: actorType,
+		ActorID:   actorID,
+		Metadata:  metadata,
+		Timestamp: time.Now(),
+	}
+
+	return s.activityLogRepo.Create(ctx, log)
+}
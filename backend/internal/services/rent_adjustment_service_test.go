@@ -0,0 +1,190 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories/memory"
+)
+
+// newRentAdjustmentTestService returns a rent adjustment service with an
+// active IPCA contract on "pinheiros": R$ 3.000 from March 2024 to February
+// 2027, adjusted every March
+func newRentAdjustmentTestService(t *testing.T) (*RentAdjustmentService, *models.RentalContract) {
+	t.Helper()
+
+	contractService := newRentalContractTestService(t)
+	contract := newTestContract(testDate(2024, 3, 1), testDate(2027, 2, 28))
+	require.NoError(t, contractService.CreateContract(context.Background(), contract, "user-1"))
+	require.Equal(t, 3, contract.AdjustmentMonth)
+
+	service := NewRentAdjustmentService(
+		memory.NewIndexRateRepository(),
+		memory.NewRentAdjustmentRepository(),
+		contractService.contractRepo,
+		contractService.activityLogRepo,
+	)
+	return service, contract
+}
+
+// indexCSV returns an IPCA series of 0,50% a month over the given periods
+func indexCSV(periods ...string) []byte {
+	var b strings.Builder
+	b.WriteString("periodo;variacao\n")
+	for _, period := range periods {
+		fmt.Fprintf(&b, "%s;0,50\n", period)
+	}
+	return []byte(b.String())
+}
+
+func monthsOf(year int, first, last int) []string {
+	periods := make([]string, 0)
+	for month := first; month <= last; month++ {
+		periods = append(periods, fmt.Sprintf("%d-%02d", year, month))
+	}
+	return periods
+}
+
+func TestImportIndexCSV(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newRentAdjustmentTestService(t)
+
+	csv := "indice,periodo,taxa\n" +
+		"IGP-M,01/2025,\"0,27\"\n" +
+		"igpm,2025-02,1.06\n" +
+		"igpm,2025-13,0.5\n" +
+		"selic,2025-02,1.0\n"
+	response, err := service.ImportIndexCSV(ctx, "", []byte(csv))
+	require.NoError(t, err)
+	assert.Equal(t, 2, response.Imported)
+	assert.Len(t, response.Errors, 2)
+
+	rates, err := service.ListIndex(ctx, "IGP-M", "", "")
+	require.NoError(t, err)
+	require.Len(t, rates, 2)
+	assert.Equal(t, "2025-01", rates[0].Period)
+	assert.Equal(t, 0.27, rates[0].Rate)
+	assert.Equal(t, 1.06, rates[1].Rate)
+
+	// Re-importing a month replaces it
+	response, err = service.ImportIndexCSV(ctx, models.IndexationTypeIGPM, []byte("01/2025;0,30\n"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, response.Imported)
+	rates, err = service.ListIndex(ctx, models.IndexationTypeIGPM, "2025-01", "2025-01")
+	require.NoError(t, err)
+	require.Len(t, rates, 1)
+	assert.Equal(t, 0.30, rates[0].Rate)
+
+	// period;rate files need the index
+	_, err = service.ImportIndexCSV(ctx, "", []byte("2025-01;0,30\n2025-02;0,40\n"))
+	assert.True(t, errors.Is(err, repositories.ErrInvalidInput))
+}
+
+func TestProposeAdjustments_ApplyAndDismiss(t *testing.T) {
+	ctx := context.Background()
+	service, contract := newRentAdjustmentTestService(t)
+	now := testDate(2025, 2, 15)
+
+	// February 2025 is not published yet: the adjustment waits for it
+	_, err := service.ImportIndexCSV(ctx, models.IndexationTypeIPCA, indexCSV(append(monthsOf(2024, 3, 12), "2025-01")...))
+	require.NoError(t, err)
+	response, err := service.ProposeAdjustments(ctx, "tenant-1", now)
+	require.NoError(t, err)
+	assert.Equal(t, 0, response.Proposed)
+	assert.Equal(t, 1, response.Pending)
+
+	_, err = service.ImportIndexCSV(ctx, models.IndexationTypeIPCA, indexCSV("2025-02"))
+	require.NoError(t, err)
+	response, err = service.ProposeAdjustments(ctx, "tenant-1", now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, response.Proposed)
+
+	// One proposal per adjustment month
+	response, err = service.ProposeAdjustments(ctx, "tenant-1", now)
+	require.NoError(t, err)
+	assert.Equal(t, 0, response.Proposed)
+
+	adjustments, err := service.ListAdjustments(ctx, "tenant-1", &repositories.RentAdjustmentFilters{ContractID: contract.ID}, repositories.DefaultPaginationOptions())
+	require.NoError(t, err)
+	require.Len(t, adjustments, 1)
+	adjustment := adjustments[0]
+	assert.Equal(t, "2025-03", adjustment.EffectivePeriod)
+	assert.Equal(t, "2024-03", adjustment.ReferenceFrom)
+	assert.Equal(t, "2025-02", adjustment.ReferenceTo)
+	assert.Equal(t, 6.1678, adjustment.Variation)
+	assert.Equal(t, 3185.03, adjustment.ProposedRent)
+
+	_, err = service.DismissAdjustment(ctx, "tenant-1", adjustment.ID, " ", "user-1")
+	assert.True(t, errors.Is(err, repositories.ErrInvalidInput))
+
+	applied, err := service.ApplyAdjustment(ctx, "tenant-1", adjustment.ID, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, models.RentAdjustmentStatusApplied, applied.Status)
+	assert.NotNil(t, applied.DecidedAt)
+
+	stored, err := service.contractRepo.Get(ctx, "tenant-1", contract.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 3185.03, stored.MonthlyRent)
+
+	_, err = service.ApplyAdjustment(ctx, "tenant-1", adjustment.ID, "user-1")
+	assert.True(t, errors.Is(err, repositories.ErrInvalidInput))
+}
+
+func TestProposeAdjustments_FirstYear(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newRentAdjustmentTestService(t)
+
+	// March 2024 is the start month: no adjustment before March 2025
+	_, err := service.ImportIndexCSV(ctx, models.IndexationTypeIPCA, indexCSV(append(monthsOf(2023, 3, 12), monthsOf(2024, 1, 2)...)...))
+	require.NoError(t, err)
+	response, err := service.ProposeAdjustments(ctx, "tenant-1", testDate(2024, 2, 20))
+	require.NoError(t, err)
+	assert.Equal(t, 0, response.Proposed)
+	assert.Equal(t, 0, response.Pending)
+}
+
+func TestUpcomingAdjustments(t *testing.T) {
+	ctx := context.Background()
+	service, contract := newRentAdjustmentTestService(t)
+	now := testDate(2025, 1, 10)
+
+	// Only 2024 is published: the March adjustment is estimated
+	_, err := service.ImportIndexCSV(ctx, models.IndexationTypeIPCA, indexCSV(monthsOf(2024, 1, 12)...))
+	require.NoError(t, err)
+
+	upcoming, err := service.UpcomingAdjustments(ctx, "tenant-1", 2, now)
+	require.NoError(t, err)
+	assert.Empty(t, upcoming)
+
+	upcoming, err = service.UpcomingAdjustments(ctx, "tenant-1", 0, now)
+	require.NoError(t, err)
+	require.Len(t, upcoming, 1)
+	assert.Equal(t, contract.ID, upcoming[0].ContractID)
+	assert.Equal(t, "João Pereira", upcoming[0].Tenant.Name)
+	assert.Equal(t, "2025-03", upcoming[0].EffectivePeriod)
+	assert.True(t, upcoming[0].Estimated)
+	assert.Equal(t, 6.1678, upcoming[0].Variation)
+	assert.Equal(t, 3185.03, upcoming[0].ProposedRent)
+	assert.Empty(t, upcoming[0].AdjustmentID)
+
+	// Once proposed, the report shows the proposal
+	_, err = service.ImportIndexCSV(ctx, models.IndexationTypeIPCA, indexCSV("2025-01", "2025-02"))
+	require.NoError(t, err)
+	_, err = service.ProposeAdjustments(ctx, "tenant-1", testDate(2025, 3, 5))
+	require.NoError(t, err)
+
+	upcoming, err = service.UpcomingAdjustments(ctx, "tenant-1", 3, now)
+	require.NoError(t, err)
+	require.Len(t, upcoming, 1)
+	assert.False(t, upcoming[0].Estimated)
+	assert.NotEmpty(t, upcoming[0].AdjustmentID)
+	assert.Equal(t, models.RentAdjustmentStatusProposed, upcoming[0].Status)
+}
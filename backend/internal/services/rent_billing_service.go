@@ -0,0 +1,649 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/payments"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// rentInvoiceReportLimit bounds the invoices read by a report
+const rentInvoiceReportLimit = 5000
+
+// RentBillingService issues the monthly invoices of rental contracts, records
+// their payments and reports delinquency and owner payouts (repasse)
+type RentBillingService struct {
+	invoiceRepo     repositories.RentInvoiceStore
+	contractRepo    repositories.RentalContractStore
+	ownerRepo       repositories.OwnerStore
+	propertyRepo    repositories.PropertyStore
+	activityLogRepo repositories.ActivityLogStore
+}
+
+// NewRentBillingService creates a new rent billing service
+func NewRentBillingService(
+	invoiceRepo repositories.RentInvoiceStore,
+	contractRepo repositories.RentalContractStore,
+	ownerRepo repositories.OwnerStore,
+	propertyRepo repositories.PropertyStore,
+	activityLogRepo repositories.ActivityLogStore,
+) *RentBillingService {
+	return &RentBillingService{
+		invoiceRepo:     invoiceRepo,
+		contractRepo:    contractRepo,
+		ownerRepo:       ownerRepo,
+		propertyRepo:    propertyRepo,
+		activityLogRepo: activityLogRepo,
+	}
+}
+
+// GenerateInvoicesResponse summarizes an invoice generation run
+type GenerateInvoicesResponse struct {
+	Period  string `json:"period"`
+	Created int    `json:"created"`
+	Skipped int    `json:"skipped"` // Already invoiced
+	Failed  int    `json:"failed"`
+}
+
+// GenerateInvoices issues the invoice of a month (YYYY-MM) for every active
+// contract in force during it: rent, condo fee and IPTU, pro rata in the
+// first and last months. Contracts already invoiced for the month are
+// skipped, so runs can be repeated.
+func (s *RentBillingService) GenerateInvoices(ctx context.Context, tenantID, period string) (*GenerateInvoicesResponse, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+	month, err := time.Parse(models.PeriodLayout, period)
+	if err != nil {
+		return nil, fmt.Errorf("%w: period must be YYYY-MM", repositories.ErrInvalidInput)
+	}
+
+	contracts, err := s.contractRepo.List(ctx, tenantID, &repositories.RentalContractFilters{
+		Statuses: []models.RentalContractStatus{models.RentalContractStatusActive},
+	}, repositories.PaginationOptions{Limit: rentalContractBatch})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rental contracts: %w", err)
+	}
+
+	response := &GenerateInvoicesResponse{Period: period}
+	for _, contract := range contracts {
+		invoice, ok := newRentInvoice(contract, month)
+		if !ok {
+			continue
+		}
+
+		if err := s.invoiceRepo.Create(ctx, invoice); err != nil {
+			if errors.Is(err, repositories.ErrAlreadyExists) {
+				response.Skipped++
+				continue
+			}
+			log.Printf("⚠️  Failed to invoice contract %s for %s: %v", contract.ID, period, err)
+			response.Failed++
+			continue
+		}
+		response.Created++
+	}
+
+	return response, nil
+}
+
+// RegisterPaymentRequest describes a payment received by the agency
+type RegisterPaymentRequest struct {
+	Amount float64
+	PaidAt time.Time // default: now
+	Note   string
+}
+
+// RegisterPayment records a payment of an open invoice, split into
+// principal, late fee and interest as of its payment date
+func (s *RentBillingService) RegisterPayment(ctx context.Context, tenantID, id string, req RegisterPaymentRequest, actorID string) (*models.RentInvoice, error) {
+	if req.Amount <= 0 {
+		return nil, fmt.Errorf("%w: amount must be positive", repositories.ErrInvalidInput)
+	}
+	if req.PaidAt.IsZero() {
+		req.PaidAt = time.Now()
+	}
+	if req.PaidAt.After(time.Now()) {
+		return nil, fmt.Errorf("%w: paid_at cannot be in the future", repositories.ErrInvalidInput)
+	}
+
+	for attempt := 1; ; attempt++ {
+		invoice, err := s.GetInvoice(ctx, tenantID, id)
+		if err != nil {
+			return nil, err
+		}
+		if invoice.Status != models.RentInvoiceStatusOpen {
+			return nil, fmt.Errorf("%w: invoice is %s", repositories.ErrInvalidInput, invoice.Status)
+		}
+
+		payment, saved, err := s.savePayment(ctx, invoice, models.RentPayment{
+			Amount:     req.Amount,
+			PaidAt:     req.PaidAt,
+			Method:     models.RentPaymentMethodManual,
+			Note:       strings.TrimSpace(req.Note),
+			RecordedBy: actorID,
+			RecordedAt: time.Now(),
+		}, attempt)
+		if err != nil {
+			return nil, err
+		}
+		if saved {
+			_ = s.logActivity(ctx, tenantID, "rent_payment_registered", actorID, paymentLogMetadata(invoice, payment))
+			return invoice, nil
+		}
+	}
+}
+
+// ApplyProviderPayment records a payment confirmed by a provider webhook on
+// the invoice charged with its reference. Repeated notifications of a
+// payment are ignored; payments of paid invoices are kept as overpayments.
+func (s *RentBillingService) ApplyProviderPayment(ctx context.Context, event payments.Event) (*models.RentInvoice, error) {
+	for attempt := 1; ; attempt++ {
+		invoice, err := s.invoiceRepo.GetByReference(ctx, event.Reference)
+		if err != nil {
+			return nil, err
+		}
+		if invoice.HasProviderPayment(event.Provider, event.PaymentID) {
+			return invoice, nil
+		}
+		if invoice.Status == models.RentInvoiceStatusCancelled {
+			return nil, fmt.Errorf("%w: invoice %s is cancelled", repositories.ErrInvalidInput, invoice.ID)
+		}
+
+		payment, saved, err := s.savePayment(ctx, invoice, models.RentPayment{
+			Amount:            event.Amount,
+			PaidAt:            event.PaidAt,
+			Method:            models.RentPaymentMethodProvider,
+			Provider:          event.Provider,
+			ProviderPaymentID: event.PaymentID,
+			RecordedAt:        time.Now(),
+		}, attempt)
+		if err != nil {
+			return nil, err
+		}
+		if saved {
+			_ = s.logActivity(ctx, invoice.TenantID, "rent_payment_registered", "", paymentLogMetadata(invoice, payment))
+			return invoice, nil
+		}
+	}
+}
+
+// CancelInvoice cancels an open invoice without payments, e.g. issued for a
+// contract terminated before the month
+func (s *RentBillingService) CancelInvoice(ctx context.Context, tenantID, id, reason, actorID string) (*models.RentInvoice, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, fmt.Errorf("%w: reason is required", repositories.ErrInvalidInput)
+	}
+
+	invoice, err := s.GetInvoice(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if invoice.Status != models.RentInvoiceStatusOpen {
+		return nil, fmt.Errorf("%w: invoice is %s", repositories.ErrInvalidInput, invoice.Status)
+	}
+	if len(invoice.Payments) > 0 {
+		return nil, fmt.Errorf("%w: invoice has payments", repositories.ErrInvalidInput)
+	}
+
+	now := time.Now()
+	invoice.Status = models.RentInvoiceStatusCancelled
+	invoice.CancelledAt = &now
+	invoice.CancelReason = reason
+	if err := s.invoiceRepo.Update(ctx, tenantID, id, map[string]interface{}{
+		"status":        invoice.Status,
+		"cancelled_at":  now,
+		"cancel_reason": reason,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to update rent invoice: %w", err)
+	}
+	_ = s.logActivity(ctx, tenantID, "rent_invoice_cancelled", actorID, map[string]interface{}{
+		"invoice_id":  invoice.ID,
+		"contract_id": invoice.ContractID,
+		"period":      invoice.Period,
+		"reason":      reason,
+	})
+
+	return invoice, nil
+}
+
+// GetInvoice retrieves a rent invoice by ID
+func (s *RentBillingService) GetInvoice(ctx context.Context, tenantID, id string) (*models.RentInvoice, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+
+	invoice, err := s.invoiceRepo.Get(ctx, tenantID, id)
+	if err != nil {
+		return nil, fmt.Errorf("rent invoice not found: %w", err)
+	}
+	return invoice, nil
+}
+
+// ListInvoices lists rent invoices with filters, latest due date first
+func (s *RentBillingService) ListInvoices(ctx context.Context, tenantID string, filters *repositories.RentInvoiceFilters, opts repositories.PaginationOptions) ([]*models.RentInvoice, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+
+	invoices, err := s.invoiceRepo.List(ctx, tenantID, filters, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rent invoices: %w", err)
+	}
+	return invoices, nil
+}
+
+// DelinquencyReport is the overdue rent of an agency at a date
+type DelinquencyReport struct {
+	Date        time.Time             `json:"date"`
+	Contracts   []*DelinquentContract `json:"contracts"` // Longest overdue first
+	Aging       DelinquencyAging      `json:"aging"`     // Outstanding amount by days overdue
+	Outstanding float64               `json:"outstanding"`
+	Charges     float64               `json:"charges"` // Late fees and interest
+	Total       float64               `json:"total"`
+}
+
+// DelinquentContract is the overdue rent of a contract
+type DelinquentContract struct {
+	ContractID    string              `json:"contract_id"`
+	PropertyID    string              `json:"property_id"`
+	OwnerID       string              `json:"owner_id,omitempty"`
+	Tenant        models.RentalTenant `json:"tenant"`
+	InvoiceIDs    []string            `json:"invoice_ids"`
+	OldestDueDate time.Time           `json:"oldest_due_date"`
+	DaysOverdue   int                 `json:"days_overdue"`
+	Outstanding   float64             `json:"outstanding"`
+	Charges       float64             `json:"charges"`
+	Total         float64             `json:"total"`
+}
+
+// DelinquencyAging buckets the outstanding amount by days overdue
+type DelinquencyAging struct {
+	Days1To30  float64 `json:"days_1_30"`
+	Days31To60 float64 `json:"days_31_60"`
+	Days61To90 float64 `json:"days_61_90"`
+	Over90     float64 `json:"over_90"`
+}
+
+// DelinquencyReport reports the open invoices past their due date at a date,
+// by contract, with the late fees and interest owed then
+func (s *RentBillingService) DelinquencyReport(ctx context.Context, tenantID string, at time.Time) (*DelinquencyReport, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+
+	today := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, at.Location())
+	invoices, err := s.invoiceRepo.List(ctx, tenantID, &repositories.RentInvoiceFilters{
+		Statuses:  []models.RentInvoiceStatus{models.RentInvoiceStatusOpen},
+		DueBefore: &today,
+	}, repositories.PaginationOptions{Limit: rentInvoiceReportLimit})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list overdue invoices: %w", err)
+	}
+
+	report := &DelinquencyReport{Date: today, Contracts: make([]*DelinquentContract, 0)}
+	contracts := make(map[string]*DelinquentContract)
+	for _, invoice := range invoices {
+		days := invoice.DaysOverdue(at)
+		if days == 0 {
+			continue
+		}
+		outstanding := invoice.Outstanding()
+		lateFee, interest := invoice.Charges(at)
+		charges := roundCents(lateFee + interest)
+
+		contract, ok := contracts[invoice.ContractID]
+		if !ok {
+			contract = &DelinquentContract{
+				ContractID:    invoice.ContractID,
+				PropertyID:    invoice.PropertyID,
+				OwnerID:       invoice.OwnerID,
+				Tenant:        invoice.Tenant,
+				OldestDueDate: invoice.DueDate,
+			}
+			contracts[invoice.ContractID] = contract
+			report.Contracts = append(report.Contracts, contract)
+		}
+		contract.InvoiceIDs = append(contract.InvoiceIDs, invoice.ID)
+		if invoice.DueDate.Before(contract.OldestDueDate) {
+			contract.OldestDueDate = invoice.DueDate
+		}
+		contract.DaysOverdue = max(contract.DaysOverdue, days)
+		contract.Outstanding = roundCents(contract.Outstanding + outstanding)
+		contract.Charges = roundCents(contract.Charges + charges)
+		contract.Total = roundCents(contract.Outstanding + contract.Charges)
+
+		switch {
+		case days <= 30:
+			report.Aging.Days1To30 = roundCents(report.Aging.Days1To30 + outstanding)
+		case days <= 60:
+			report.Aging.Days31To60 = roundCents(report.Aging.Days31To60 + outstanding)
+		case days <= 90:
+			report.Aging.Days61To90 = roundCents(report.Aging.Days61To90 + outstanding)
+		default:
+			report.Aging.Over90 = roundCents(report.Aging.Over90 + outstanding)
+		}
+		report.Outstanding = roundCents(report.Outstanding + outstanding)
+		report.Charges = roundCents(report.Charges + charges)
+	}
+	report.Total = roundCents(report.Outstanding + report.Charges)
+
+	sort.SliceStable(report.Contracts, func(i, j int) bool {
+		return report.Contracts[i].DaysOverdue > report.Contracts[j].DaysOverdue
+	})
+
+	return report, nil
+}
+
+// OwnerStatement is the payout (repasse) of a month to the owners: the rent
+// received net of the administration fee
+type OwnerStatement struct {
+	Period   string         `json:"period"` // YYYY-MM of the payments
+	OwnerID  string         `json:"owner_id,omitempty"`
+	Owners   []*OwnerPayout `json:"owners"`
+	Received float64        `json:"received"`
+	AdminFee float64        `json:"admin_fee"`
+	Net      float64        `json:"net"`
+}
+
+// OwnerPayout is the payout of one owner
+type OwnerPayout struct {
+	OwnerID   string                `json:"owner_id"`
+	OwnerName string                `json:"owner_name,omitempty"`
+	Lines     []*OwnerStatementLine `json:"lines"`
+	Received  float64               `json:"received"`
+	AdminFee  float64               `json:"admin_fee"`
+	Net       float64               `json:"net"`
+}
+
+// OwnerStatementLine is a payment received for an owner
+type OwnerStatementLine struct {
+	InvoiceID         string    `json:"invoice_id"`
+	ContractID        string    `json:"contract_id"`
+	PropertyID        string    `json:"property_id"`
+	PropertyReference string    `json:"property_reference,omitempty"`
+	TenantName        string    `json:"tenant_name"`
+	RentPeriod        string    `json:"rent_period"` // Month charged
+	PaidAt            time.Time `json:"paid_at"`
+	Received          float64   `json:"received"`
+	Charges           float64   `json:"charges"`      // Late fee and interest included in Received
+	PassThrough       float64   `json:"pass_through"` // Condo fee and IPTU, free of the administration fee
+	AdminFee          float64   `json:"admin_fee"`
+	Net               float64   `json:"net"`
+}
+
+// OwnerStatement returns the payouts of the payments received in a month
+// (YYYY-MM), of one owner or of all of them. The administration fee applies
+// to the rent and the late charges received; the condo fee and IPTU pass
+// through in full.
+func (s *RentBillingService) OwnerStatement(ctx context.Context, tenantID, period, ownerID string) (*OwnerStatement, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+	if _, err := time.Parse(models.PeriodLayout, period); err != nil {
+		return nil, fmt.Errorf("%w: period must be YYYY-MM", repositories.ErrInvalidInput)
+	}
+
+	invoices, err := s.invoiceRepo.List(ctx, tenantID, &repositories.RentInvoiceFilters{
+		OwnerID:       ownerID,
+		PaymentPeriod: period,
+	}, repositories.PaginationOptions{Limit: rentInvoiceReportLimit, OrderBy: "due_date", Direction: firestore.Asc})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rent invoices: %w", err)
+	}
+
+	statement := &OwnerStatement{Period: period, OwnerID: ownerID, Owners: make([]*OwnerPayout, 0)}
+	owners := make(map[string]*OwnerPayout)
+	references := make(map[string]string)
+	for _, invoice := range invoices {
+		payout, ok := owners[invoice.OwnerID]
+		if !ok {
+			payout = &OwnerPayout{OwnerID: invoice.OwnerID}
+			if invoice.OwnerID != "" {
+				if owner, err := s.ownerRepo.Get(ctx, tenantID, invoice.OwnerID); err == nil {
+					payout.OwnerName = owner.Name
+				}
+			}
+			owners[invoice.OwnerID] = payout
+			statement.Owners = append(statement.Owners, payout)
+		}
+		if _, ok := references[invoice.PropertyID]; !ok {
+			if property, err := s.propertyRepo.Get(ctx, tenantID, invoice.PropertyID); err == nil {
+				references[invoice.PropertyID] = propertyReference(property)
+			}
+		}
+
+		passThroughShare := 0.0
+		if invoice.Amount > 0 {
+			passThroughShare = invoiceItemsTotal(invoice, models.InvoiceItemKindCondoFee, models.InvoiceItemKindIPTU) / invoice.Amount
+		}
+		for _, payment := range invoice.Payments {
+			if payment.PaidAt.Format(models.PeriodLayout) != period {
+				continue
+			}
+
+			line := &OwnerStatementLine{
+				InvoiceID:         invoice.ID,
+				ContractID:        invoice.ContractID,
+				PropertyID:        invoice.PropertyID,
+				PropertyReference: references[invoice.PropertyID],
+				TenantName:        invoice.Tenant.Name,
+				RentPeriod:        invoice.Period,
+				PaidAt:            payment.PaidAt,
+				Received:          payment.Amount,
+				Charges:           roundCents(payment.LateFee + payment.Interest),
+				PassThrough:       roundCents(payment.Principal * passThroughShare),
+			}
+			line.AdminFee = roundCents((line.Received - line.PassThrough) * invoice.AdminFeePercentage / 100)
+			line.Net = roundCents(line.Received - line.AdminFee)
+			payout.Lines = append(payout.Lines, line)
+
+			payout.Received = roundCents(payout.Received + line.Received)
+			payout.AdminFee = roundCents(payout.AdminFee + line.AdminFee)
+			payout.Net = roundCents(payout.Net + line.Net)
+		}
+	}
+
+	for _, payout := range statement.Owners {
+		statement.Received = roundCents(statement.Received + payout.Received)
+		statement.AdminFee = roundCents(statement.AdminFee + payout.AdminFee)
+		statement.Net = roundCents(statement.Net + payout.Net)
+	}
+
+	return statement, nil
+}
+
+// OwnerStatementCSV exports an owner statement as CSV for spreadsheets (";"
+// separated, decimal comma)
+func (s *RentBillingService) OwnerStatementCSV(statement *OwnerStatement) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Comma = ';'
+
+	_ = w.Write([]string{"proprietario", "imovel", "inquilino", "competencia", "pago_em", "recebido", "encargos", "repasse_direto", "taxa_administracao", "liquido"})
+	for _, payout := range statement.Owners {
+		owner := payout.OwnerName
+		if owner == "" {
+			owner = payout.OwnerID
+		}
+		for _, line := range payout.Lines {
+			reference := line.PropertyReference
+			if reference == "" {
+				reference = line.PropertyID
+			}
+
+			_ = w.Write([]string{
+				owner,
+				reference,
+				line.TenantName,
+				line.RentPeriod,
+				line.PaidAt.Format("02/01/2006"),
+				decimalBR(line.Received),
+				decimalBR(line.Charges),
+				decimalBR(line.PassThrough),
+				decimalBR(line.AdminFee),
+				decimalBR(line.Net),
+			})
+		}
+	}
+	_ = w.Write([]string{"total", "", "", "", "", decimalBR(statement.Received), "", "", decimalBR(statement.AdminFee), decimalBR(statement.Net)})
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("failed to write statement: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// paymentAttempts is how many times a payment is applied to a fresh read of
+// its invoice when other payments keep reaching the invoice meanwhile
+const paymentAttempts = 3
+
+// savePayment applies a payment to an invoice and stores its payments and
+// totals. The write only happens, in one transaction with a fresh read, if the
+// invoice still has the payments and status it was read with and does not
+// hold the provider payment yet. It returns false when the invoice changed
+// and the caller must read it again; on the last attempt that is an error.
+func (s *RentBillingService) savePayment(ctx context.Context, invoice *models.RentInvoice, payment models.RentPayment, attempt int) (models.RentPayment, bool, error) {
+	payments, status := len(invoice.Payments), invoice.Status
+
+	payment = invoice.ApplyPayment(payment)
+	updates := map[string]interface{}{
+		"payments":        invoice.Payments,
+		"payment_periods": invoice.PaymentPeriods,
+		"principal_paid":  invoice.PrincipalPaid,
+		"charges_paid":    invoice.ChargesPaid,
+		"status":          invoice.Status,
+	}
+	if invoice.PaidAt != nil {
+		updates["paid_at"] = *invoice.PaidAt
+	}
+
+	saved, err := s.invoiceRepo.UpdateIf(ctx, invoice.TenantID, invoice.ID, func(current *models.RentInvoice) bool {
+		if payment.ProviderPaymentID != "" && current.HasProviderPayment(payment.Provider, payment.ProviderPaymentID) {
+			return false
+		}
+		return current.Status == status && len(current.Payments) == payments
+	}, updates)
+	if err != nil {
+		return payment, false, fmt.Errorf("failed to update rent invoice: %w", err)
+	}
+	if !saved && attempt >= paymentAttempts {
+		return payment, false, fmt.Errorf("%w: invoice %s changed while the payment was recorded, try again", repositories.ErrInvalidInput, invoice.ID)
+	}
+	return payment, saved, nil
+}
+
+// newRentInvoice builds the invoice of a contract for a month, pro rata to
+// the days of the month in force; false when the contract is not in force
+// during the month
+func newRentInvoice(contract *models.RentalContract, month time.Time) (*models.RentInvoice, bool) {
+	first := monthStart(month)
+	last := first.AddDate(0, 1, -1)
+	from, to := first, last
+	if contract.StartDate.After(from) {
+		from = contract.StartDate
+	}
+	if contract.EndDate.Before(to) {
+		to = contract.EndDate
+	}
+	days := models.CalendarDays(from, to) + 1
+	if days <= 0 {
+		return nil, false
+	}
+	monthDays := last.Day()
+	share := float64(days) / float64(monthDays)
+
+	period := first.Format(models.PeriodLayout)
+	label := first.Format("01/2006")
+	if days < monthDays {
+		label = fmt.Sprintf("%s (pro rata %d/%d dias)", label, days, monthDays)
+	}
+
+	invoice := &models.RentInvoice{
+		TenantID:            contract.TenantID,
+		ContractID:          contract.ID,
+		PropertyID:          contract.PropertyID,
+		OwnerID:             contract.OwnerID,
+		Period:              period,
+		Tenant:              contract.Tenant,
+		DueDate:             time.Date(first.Year(), first.Month()+1, contract.EffectiveDueDay(), 0, 0, 0, 0, time.UTC),
+		LateFeePercentage:   contract.LateFeePercentage,
+		MonthlyInterestRate: contract.MonthlyInterestRate,
+		AdminFeePercentage:  contract.AdminFeePercentage,
+		Status:              models.RentInvoiceStatusOpen,
+	}
+	for _, item := range []models.InvoiceItem{
+		{Kind: models.InvoiceItemKindRent, Description: "Aluguel " + label, Amount: contract.MonthlyRent},
+		{Kind: models.InvoiceItemKindCondoFee, Description: "Condomínio " + label, Amount: contract.CondoFee},
+		{Kind: models.InvoiceItemKindIPTU, Description: "IPTU " + label, Amount: contract.IPTUMonthly},
+	} {
+		if item.Amount <= 0 {
+			continue
+		}
+		item.Amount = roundCents(item.Amount * share)
+		invoice.Items = append(invoice.Items, item)
+		invoice.Amount = roundCents(invoice.Amount + item.Amount)
+	}
+
+	return invoice, true
+}
+
+// invoiceItemsTotal sums the items of the given kinds
+func invoiceItemsTotal(invoice *models.RentInvoice, kinds ...models.InvoiceItemKind) float64 {
+	total := 0.0
+	for _, item := range invoice.Items {
+		for _, kind := range kinds {
+			if item.Kind == kind {
+				total += item.Amount
+			}
+		}
+	}
+	return roundCents(total)
+}
+
+// paymentLogMetadata returns the activity log metadata of a rent payment
+func paymentLogMetadata(invoice *models.RentInvoice, payment models.RentPayment) map[string]interface{} {
+	return map[string]interface{}{
+		"invoice_id":  invoice.ID,
+		"contract_id": invoice.ContractID,
+		"property_id": invoice.PropertyID,
+		"period":      invoice.Period,
+		"amount":      payment.Amount,
+		"late_fee":    payment.LateFee,
+		"interest":    payment.Interest,
+		"method":      payment.Method,
+		"status":      invoice.Status,
+	}
+}
+
+// logActivity logs an activity; actions without an actor are the system's
+func (s *RentBillingService) logActivity(ctx context.Context, tenantID, eventType, actorID string, metadata map[string]interface{}) error {
+	actorType := models.ActorTypeUser
+	if actorID == "" {
+		actorType = models.ActorTypeSystem
+	}
+
+	log := &models.ActivityLog{
+		TenantID:  tenantID,
+		EventType: eventType,
+		ActorType: actorType,
+		ActorID:   actorID,
+		Metadata:  metadata,
+		Timestamp: time.Now(),
+	}
+
+	return s.activityLogRepo.Create(ctx, log)
+}
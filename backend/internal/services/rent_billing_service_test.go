@@ -0,0 +1,299 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/payments"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories/memory"
+)
+
+// newRentBillingTestService returns a rent billing service with an active
+// contract on "pinheiros" owned by "owner-1": R$ 3.000 + R$ 800 condo fee +
+// R$ 150 IPTU from 15 March 2024, due on the 15th
+func newRentBillingTestService(t *testing.T) (*RentBillingService, *models.RentalContract) {
+	t.Helper()
+	ctx := context.Background()
+
+	contractService := newRentalContractTestService(t)
+	contract := newTestContract(testDate(2024, 3, 15), testDate(2027, 3, 14))
+	require.NoError(t, contractService.CreateContract(ctx, contract, "user-1"))
+	require.Equal(t, 15, contract.DueDay)
+
+	ownerRepo := memory.NewOwnerRepository()
+	require.NoError(t, ownerRepo.Create(ctx, &models.Owner{ID: "owner-1", TenantID: "tenant-1", Name: "Maria Souza"}))
+
+	service := NewRentBillingService(
+		memory.NewRentInvoiceRepository(),
+		contractService.contractRepo,
+		ownerRepo,
+		contractService.propertyRepo,
+		contractService.activityLogRepo,
+	)
+	return service, contract
+}
+
+func TestGenerateInvoices_ProRataAndIdempotent(t *testing.T) {
+	ctx := context.Background()
+	service, contract := newRentBillingTestService(t)
+
+	// Not in force in February
+	response, err := service.GenerateInvoices(ctx, "tenant-1", "2024-02")
+	require.NoError(t, err)
+	assert.Equal(t, 0, response.Created)
+
+	// First month pro rata: 17 of 31 days
+	response, err = service.GenerateInvoices(ctx, "tenant-1", "2024-03")
+	require.NoError(t, err)
+	assert.Equal(t, 1, response.Created)
+
+	invoice, err := service.GetInvoice(ctx, "tenant-1", models.RentInvoiceID(contract.ID, "2024-03"))
+	require.NoError(t, err)
+	require.Len(t, invoice.Items, 3)
+	assert.Equal(t, 1645.16, invoice.Items[0].Amount)
+	assert.Contains(t, invoice.Items[0].Description, "pro rata 17/31")
+	assert.Equal(t, 2166.13, invoice.Amount)
+	assert.Equal(t, testDate(2024, 4, 15), invoice.DueDate)
+	assert.Equal(t, invoice.ID, invoice.Reference)
+	assert.Equal(t, "owner-1", invoice.OwnerID)
+	assert.Equal(t, models.RentInvoiceStatusOpen, invoice.Status)
+
+	// Repeated runs skip the contracts already invoiced
+	response, err = service.GenerateInvoices(ctx, "tenant-1", "2024-03")
+	require.NoError(t, err)
+	assert.Equal(t, 0, response.Created)
+	assert.Equal(t, 1, response.Skipped)
+
+	_, err = service.GenerateInvoices(ctx, "tenant-1", "03/2024")
+	assert.True(t, errors.Is(err, repositories.ErrInvalidInput))
+}
+
+func TestRegisterPayment_LateChargesAndCancel(t *testing.T) {
+	ctx := context.Background()
+	service, contract := newRentBillingTestService(t)
+
+	_, err := service.GenerateInvoices(ctx, "tenant-1", "2024-04")
+	require.NoError(t, err)
+	id := models.RentInvoiceID(contract.ID, "2024-04")
+
+	_, err = service.RegisterPayment(ctx, "tenant-1", id, RegisterPaymentRequest{Amount: 0}, "user-1")
+	assert.True(t, errors.Is(err, repositories.ErrInvalidInput))
+
+	// Paid 10 days late: 10% late fee and 1% a month interest pro rata
+	invoice, err := service.RegisterPayment(ctx, "tenant-1", id, RegisterPaymentRequest{
+		Amount: 4358.17,
+		PaidAt: testDate(2024, 5, 25),
+		Note:   "PIX",
+	}, "user-1")
+	require.NoError(t, err)
+	require.Len(t, invoice.Payments, 1)
+	assert.Equal(t, 3950.0, invoice.Payments[0].Principal)
+	assert.Equal(t, 395.0, invoice.Payments[0].LateFee)
+	assert.Equal(t, 13.17, invoice.Payments[0].Interest)
+	assert.Equal(t, models.RentPaymentMethodManual, invoice.Payments[0].Method)
+	assert.Equal(t, models.RentInvoiceStatusPaid, invoice.Status)
+
+	stored, err := service.GetInvoice(ctx, "tenant-1", id)
+	require.NoError(t, err)
+	assert.Equal(t, 408.17, stored.ChargesPaid)
+
+	// Paid invoices take no more payments and cannot be cancelled
+	_, err = service.RegisterPayment(ctx, "tenant-1", id, RegisterPaymentRequest{Amount: 100}, "user-1")
+	assert.True(t, errors.Is(err, repositories.ErrInvalidInput))
+	_, err = service.CancelInvoice(ctx, "tenant-1", id, "Contrato encerrado", "user-1")
+	assert.True(t, errors.Is(err, repositories.ErrInvalidInput))
+
+	_, err = service.GenerateInvoices(ctx, "tenant-1", "2024-05")
+	require.NoError(t, err)
+	mayID := models.RentInvoiceID(contract.ID, "2024-05")
+	_, err = service.CancelInvoice(ctx, "tenant-1", mayID, " ", "user-1")
+	assert.True(t, errors.Is(err, repositories.ErrInvalidInput))
+
+	cancelled, err := service.CancelInvoice(ctx, "tenant-1", mayID, "Emitida em duplicidade", "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, models.RentInvoiceStatusCancelled, cancelled.Status)
+	assert.NotNil(t, cancelled.CancelledAt)
+}
+
+func TestApplyProviderPayment_Idempotent(t *testing.T) {
+	ctx := context.Background()
+	service, contract := newRentBillingTestService(t)
+
+	_, err := service.GenerateInvoices(ctx, "tenant-1", "2024-04")
+	require.NoError(t, err)
+	invoice, err := service.GetInvoice(ctx, "tenant-1", models.RentInvoiceID(contract.ID, "2024-04"))
+	require.NoError(t, err)
+
+	event := payments.Event{
+		Provider:  "gateway",
+		Reference: invoice.Reference,
+		PaymentID: "pay-1",
+		Amount:    3950,
+		PaidAt:    testDate(2024, 5, 10),
+	}
+	for range 2 {
+		invoice, err = service.ApplyProviderPayment(ctx, event)
+		require.NoError(t, err)
+	}
+	assert.Len(t, invoice.Payments, 1)
+	assert.Equal(t, models.RentPaymentMethodProvider, invoice.Payments[0].Method)
+	assert.Equal(t, models.RentInvoiceStatusPaid, invoice.Status)
+
+	_, err = service.ApplyProviderPayment(ctx, payments.Event{Provider: "gateway", Reference: "unknown", PaymentID: "pay-2", Amount: 10})
+	assert.True(t, errors.Is(err, repositories.ErrNotFound))
+}
+
+// staleInvoiceStore returns an invoice as it was before other payments
+// reached it, once, like a payment recorded between the read and the write
+type staleInvoiceStore struct {
+	repositories.RentInvoiceStore
+	stale *models.RentInvoice
+}
+
+func (s *staleInvoiceStore) read(ctx context.Context, tenantID, id string) (*models.RentInvoice, error) {
+	if stale := s.stale; stale != nil {
+		s.stale = nil
+		return stale, nil
+	}
+	return s.RentInvoiceStore.Get(ctx, tenantID, id)
+}
+
+func (s *staleInvoiceStore) Get(ctx context.Context, tenantID, id string) (*models.RentInvoice, error) {
+	return s.read(ctx, tenantID, id)
+}
+
+func (s *staleInvoiceStore) GetByReference(ctx context.Context, reference string) (*models.RentInvoice, error) {
+	invoice, err := s.RentInvoiceStore.GetByReference(ctx, reference)
+	if err != nil {
+		return nil, err
+	}
+	return s.read(ctx, invoice.TenantID, invoice.ID)
+}
+
+func TestPayments_DoNotOverwriteConcurrentPayments(t *testing.T) {
+	ctx := context.Background()
+	service, contract := newRentBillingTestService(t)
+
+	_, err := service.GenerateInvoices(ctx, "tenant-1", "2024-04")
+	require.NoError(t, err)
+	id := models.RentInvoiceID(contract.ID, "2024-04")
+	unpaid, err := service.GetInvoice(ctx, "tenant-1", id)
+	require.NoError(t, err)
+
+	store := &staleInvoiceStore{RentInvoiceStore: service.invoiceRepo}
+	service.invoiceRepo = store
+
+	event := payments.Event{
+		Provider:  "gateway",
+		Reference: unpaid.Reference,
+		PaymentID: "pay-1",
+		Amount:    1000,
+		PaidAt:    testDate(2024, 5, 10),
+	}
+	_, err = service.ApplyProviderPayment(ctx, event)
+	require.NoError(t, err)
+
+	// A manual payment read before the webhook is applied on top of it
+	copied := *unpaid
+	store.stale = &copied
+	invoice, err := service.RegisterPayment(ctx, "tenant-1", id, RegisterPaymentRequest{Amount: 500, PaidAt: testDate(2024, 5, 11)}, "user-1")
+	require.NoError(t, err)
+	require.Len(t, invoice.Payments, 2)
+	assert.Equal(t, 1500.0, invoice.PrincipalPaid)
+
+	// A retry that read the invoice before its first delivery is ignored
+	copied = *unpaid
+	store.stale = &copied
+	invoice, err = service.ApplyProviderPayment(ctx, event)
+	require.NoError(t, err)
+	assert.Len(t, invoice.Payments, 2)
+
+	stored, err := service.GetInvoice(ctx, "tenant-1", id)
+	require.NoError(t, err)
+	assert.Len(t, stored.Payments, 2)
+	assert.Equal(t, 1500.0, stored.PrincipalPaid)
+}
+
+func TestDelinquencyReport(t *testing.T) {
+	ctx := context.Background()
+	service, contract := newRentBillingTestService(t)
+
+	for _, period := range []string{"2024-03", "2024-04", "2024-05"} {
+		_, err := service.GenerateInvoices(ctx, "tenant-1", period)
+		require.NoError(t, err)
+	}
+
+	// March (due 15/04) and April (due 15/05) are overdue; May is not due yet
+	report, err := service.DelinquencyReport(ctx, "tenant-1", testDate(2024, 5, 20))
+	require.NoError(t, err)
+	require.Len(t, report.Contracts, 1)
+
+	delinquent := report.Contracts[0]
+	assert.Equal(t, contract.ID, delinquent.ContractID)
+	assert.Equal(t, "João Pereira", delinquent.Tenant.Name)
+	assert.Len(t, delinquent.InvoiceIDs, 2)
+	assert.Equal(t, testDate(2024, 4, 15), delinquent.OldestDueDate)
+	assert.Equal(t, 35, delinquent.DaysOverdue)
+	assert.Equal(t, 6116.13, delinquent.Outstanding)
+	assert.Equal(t, 643.46, delinquent.Charges)
+
+	assert.Equal(t, 3950.0, report.Aging.Days1To30)
+	assert.Equal(t, 2166.13, report.Aging.Days31To60)
+	assert.Equal(t, 6759.59, report.Total)
+
+	// Paid invoices leave the report
+	_, err = service.RegisterPayment(ctx, "tenant-1", models.RentInvoiceID(contract.ID, "2024-03"), RegisterPaymentRequest{
+		Amount: 2408.01,
+		PaidAt: testDate(2024, 5, 20),
+	}, "user-1")
+	require.NoError(t, err)
+
+	report, err = service.DelinquencyReport(ctx, "tenant-1", testDate(2024, 5, 20))
+	require.NoError(t, err)
+	require.Len(t, report.Contracts, 1)
+	assert.Equal(t, 5, report.Contracts[0].DaysOverdue)
+	assert.Equal(t, 3950.0, report.Outstanding)
+}
+
+func TestOwnerStatement_NetOfAdminFee(t *testing.T) {
+	ctx := context.Background()
+	service, contract := newRentBillingTestService(t)
+
+	_, err := service.GenerateInvoices(ctx, "tenant-1", "2024-04")
+	require.NoError(t, err)
+	_, err = service.RegisterPayment(ctx, "tenant-1", models.RentInvoiceID(contract.ID, "2024-04"), RegisterPaymentRequest{
+		Amount: 3950,
+		PaidAt: testDate(2024, 5, 10),
+	}, "user-1")
+	require.NoError(t, err)
+
+	// The condo fee and IPTU pass through free of the 10% administration fee
+	statement, err := service.OwnerStatement(ctx, "tenant-1", "2024-05", "owner-1")
+	require.NoError(t, err)
+	require.Len(t, statement.Owners, 1)
+	payout := statement.Owners[0]
+	assert.Equal(t, "Maria Souza", payout.OwnerName)
+	require.Len(t, payout.Lines, 1)
+	assert.Equal(t, "2024-04", payout.Lines[0].RentPeriod)
+	assert.Equal(t, 950.0, payout.Lines[0].PassThrough)
+	assert.Equal(t, 300.0, payout.AdminFee)
+	assert.Equal(t, 3650.0, payout.Net)
+	assert.Equal(t, 3650.0, statement.Net)
+
+	data, err := service.OwnerStatementCSV(statement)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "Maria Souza;")
+	assert.Contains(t, string(data), ";3950,00;0,00;950,00;300,00;3650,00")
+
+	// Nothing was received in April
+	statement, err = service.OwnerStatement(ctx, "tenant-1", "2024-04", "")
+	require.NoError(t, err)
+	assert.Empty(t, statement.Owners)
+	assert.Equal(t, 0.0, statement.Net)
+}
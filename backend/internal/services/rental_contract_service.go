@@ -0,0 +1,466 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// rentalContractBatch bounds the contracts read by a job run or a vacancy average
+const rentalContractBatch = 500
+
+// RentalContractService handles rental contracts and keeps the contract
+// fields of their properties (current contract, history, vacancy) up to date
+type RentalContractService struct {
+	contractRepo    repositories.RentalContractStore
+	dealRepo        repositories.DealStore
+	leadService     *LeadService
+	propertyService *PropertyService
+	propertyRepo    repositories.PropertyStore
+	activityLogRepo repositories.ActivityLogStore
+}
+
+// NewRentalContractService creates a new rental contract service
+func NewRentalContractService(
+	contractRepo repositories.RentalContractStore,
+	dealRepo repositories.DealStore,
+	leadService *LeadService,
+	propertyService *PropertyService,
+	propertyRepo repositories.PropertyStore,
+	activityLogRepo repositories.ActivityLogStore,
+) *RentalContractService {
+	return &RentalContractService{
+		contractRepo:    contractRepo,
+		dealRepo:        dealRepo,
+		leadService:     leadService,
+		propertyService: propertyService,
+		propertyRepo:    propertyRepo,
+		activityLogRepo: activityLogRepo,
+	}
+}
+
+// CreateContract registers the lease of a property. A rental deal defaults
+// the property, the tenant's lead, the start date and the rent; the lead
+// defaults the tenant's contact data and the property's RentalInfo the other
+// monthly values, the indexation and the deposit. The property becomes its
+// current contract and leaves the market as rented.
+func (s *RentalContractService) CreateContract(ctx context.Context, contract *models.RentalContract, actorID string) error {
+	if contract.TenantID == "" {
+		return fmt.Errorf("tenant_id is required")
+	}
+
+	if contract.DealID != "" {
+		deal, err := s.dealRepo.Get(ctx, contract.TenantID, contract.DealID)
+		if err != nil {
+			return fmt.Errorf("deal not found: %w", err)
+		}
+		if deal.Status != models.DealStatusClosed || deal.TransactionType != models.TransactionTypeRent {
+			return fmt.Errorf("%w: deal %s is not a closed rental", repositories.ErrInvalidInput, deal.ID)
+		}
+		if contract.PropertyID == "" {
+			contract.PropertyID = deal.PropertyID
+		}
+		if contract.Tenant.LeadID == "" {
+			contract.Tenant.LeadID = deal.LeadID
+		}
+		if contract.StartDate.IsZero() {
+			contract.StartDate = deal.ClosedAt
+		}
+		if contract.MonthlyRent == 0 {
+			contract.MonthlyRent = deal.Price
+		}
+	}
+	if contract.PropertyID == "" {
+		return fmt.Errorf("%w: property_id or deal_id is required", repositories.ErrInvalidInput)
+	}
+
+	if contract.Tenant.LeadID != "" && contract.Tenant.Name == "" {
+		lead, err := s.leadService.activeLead(ctx, contract.TenantID, contract.Tenant.LeadID)
+		if err != nil {
+			return err
+		}
+		contract.Tenant.Name = lead.Name
+		if contract.Tenant.Email == "" {
+			contract.Tenant.Email = lead.Email
+		}
+		if contract.Tenant.Phone == "" {
+			contract.Tenant.Phone = lead.Phone
+		}
+	}
+
+	property, err := s.propertyRepo.Get(ctx, contract.TenantID, contract.PropertyID)
+	if err != nil {
+		return fmt.Errorf("property not found: %w", err)
+	}
+	if err := checkProposalTransaction(property, models.TransactionTypeRent); err != nil {
+		return err
+	}
+	if property.CurrentContractID != nil && *property.CurrentContractID != "" {
+		return fmt.Errorf("%w: property already has contract %s", repositories.ErrInvalidInput, *property.CurrentContractID)
+	}
+	applyRentalInfoDefaults(contract, property.RentalInfo)
+
+	contract.Tenant.Name = strings.TrimSpace(contract.Tenant.Name)
+	if err := contract.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", repositories.ErrInvalidInput, err)
+	}
+	if rental := property.RentalInfo; rental != nil && len(rental.AcceptedGuarantees) > 0 && !slices.Contains(rental.AcceptedGuarantees, string(contract.GuaranteeType)) {
+		return fmt.Errorf("%w: property does not accept guarantee %s", repositories.ErrInvalidInput, contract.GuaranteeType)
+	}
+
+	contract.ID = ""
+	contract.OwnerID = property.OwnerID
+	contract.Status = models.RentalContractStatusActive
+	contract.Renewals = nil
+	contract.EndedAt = nil
+	contract.TerminationReason = ""
+	contract.VacancyDaysBefore = nil
+	if property.LastRentalEndDate != nil {
+		days := models.CalendarDays(*property.LastRentalEndDate, contract.StartDate)
+		if days < 0 {
+			days = 0
+		}
+		contract.VacancyDaysBefore = &days
+	}
+	contract.Notes = strings.TrimSpace(contract.Notes)
+	contract.CreatedBy = actorID
+
+	if err := s.contractRepo.Create(ctx, contract); err != nil {
+		return fmt.Errorf("failed to create rental contract: %w", err)
+	}
+	_ = s.logActivity(ctx, contract.TenantID, "rental_contract_created", actorID, contractLogMetadata(contract))
+
+	updates := map[string]interface{}{
+		"current_contract_id": contract.ID,
+	}
+	if average, ok := s.averageVacancyDays(ctx, contract.TenantID, contract.PropertyID); ok {
+		updates["average_vacancy_days"] = average
+	}
+	if err := s.propertyRepo.Update(ctx, contract.TenantID, contract.PropertyID, updates); err != nil {
+		log.Printf("⚠️  Failed to set contract %s on property %s: %v", contract.ID, contract.PropertyID, err)
+	}
+	if _, err := s.propertyService.MarkUnavailable(ctx, contract.TenantID, contract.PropertyID, models.PropertyUnavailableReasonRented); err != nil {
+		log.Printf("⚠️  Failed to take property %s off the market after contract %s: %v", contract.PropertyID, contract.ID, err)
+	}
+
+	return nil
+}
+
+// RenewContractRequest describes the extension of a rental contract
+type RenewContractRequest struct {
+	EndDate     time.Time // New end date, after the current one
+	MonthlyRent float64   // 0 keeps the current rent
+	Note        string
+}
+
+// RenewContract extends an active contract, optionally with a new rent
+func (s *RentalContractService) RenewContract(ctx context.Context, tenantID, id string, req RenewContractRequest, actorID string) (*models.RentalContract, error) {
+	contract, err := s.activeContract(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if contract.TerminationReason != "" {
+		return nil, fmt.Errorf("%w: contract termination is scheduled", repositories.ErrInvalidInput)
+	}
+	if !req.EndDate.After(contract.EndDate) {
+		return nil, fmt.Errorf("%w: end_date must be after the current end date", repositories.ErrInvalidInput)
+	}
+	if req.MonthlyRent < 0 {
+		return nil, fmt.Errorf("%w: monthly_rent cannot be negative", repositories.ErrInvalidInput)
+	}
+	if req.MonthlyRent == 0 {
+		req.MonthlyRent = contract.MonthlyRent
+	}
+
+	renewal := models.ContractRenewal{
+		PreviousEndDate: contract.EndDate,
+		NewEndDate:      req.EndDate,
+		PreviousRent:    contract.MonthlyRent,
+		MonthlyRent:     req.MonthlyRent,
+		Note:            strings.TrimSpace(req.Note),
+		RenewedBy:       actorID,
+		RenewedAt:       time.Now(),
+	}
+	contract.Renewals = append(contract.Renewals, renewal)
+	contract.EndDate = req.EndDate
+	contract.MonthlyRent = req.MonthlyRent
+
+	if err := s.contractRepo.Update(ctx, tenantID, id, map[string]interface{}{
+		"renewals":     contract.Renewals,
+		"end_date":     contract.EndDate,
+		"monthly_rent": contract.MonthlyRent,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to update rental contract: %w", err)
+	}
+
+	metadata := contractLogMetadata(contract)
+	metadata["previous_end_date"] = renewal.PreviousEndDate
+	metadata["previous_rent"] = renewal.PreviousRent
+	_ = s.logActivity(ctx, tenantID, "rental_contract_renewed", actorID, metadata)
+
+	return contract, nil
+}
+
+// TerminateContract ends an active contract early (rescisão). The move-out
+// date defaults to today; with a future date (aviso prévio) the contract
+// stays active until then and the daily job ends it.
+func (s *RentalContractService) TerminateContract(ctx context.Context, tenantID, id string, moveOut time.Time, reason, actorID string) (*models.RentalContract, error) {
+	contract, err := s.activeContract(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, fmt.Errorf("%w: reason is required", repositories.ErrInvalidInput)
+	}
+	now := time.Now()
+	if moveOut.IsZero() {
+		moveOut = now
+	}
+	if moveOut.Before(contract.StartDate) || moveOut.After(contract.EndDate) {
+		return nil, fmt.Errorf("%w: move-out date must be within the contract term", repositories.ErrInvalidInput)
+	}
+
+	contract.TerminationReason = reason
+	if moveOut.After(now) {
+		contract.EndDate = moveOut
+		if err := s.contractRepo.Update(ctx, tenantID, id, map[string]interface{}{
+			"end_date":           contract.EndDate,
+			"termination_reason": contract.TerminationReason,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to update rental contract: %w", err)
+		}
+		_ = s.logActivity(ctx, tenantID, "rental_contract_termination_scheduled", actorID, contractLogMetadata(contract))
+		return contract, nil
+	}
+
+	if err := s.end(ctx, contract, moveOut, actorID); err != nil {
+		return nil, err
+	}
+	return contract, nil
+}
+
+// ProcessEndedContractsResponse summarizes a run of the contract end job
+type ProcessEndedContractsResponse struct {
+	Ended  int `json:"ended"`
+	Failed int `json:"failed"`
+}
+
+// ProcessEndedContracts ends the active contracts past their end date (not
+// renewed, or with a scheduled termination)
+func (s *RentalContractService) ProcessEndedContracts(ctx context.Context, tenantID string) (*ProcessEndedContractsResponse, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+
+	now := time.Now()
+	contracts, err := s.contractRepo.List(ctx, tenantID, &repositories.RentalContractFilters{
+		Statuses:   []models.RentalContractStatus{models.RentalContractStatusActive},
+		EndsBefore: &now,
+	}, repositories.PaginationOptions{Limit: rentalContractBatch})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ended contracts: %w", err)
+	}
+
+	response := &ProcessEndedContractsResponse{}
+	for _, contract := range contracts {
+		if err := s.end(ctx, contract, contract.EndDate, ""); err != nil {
+			log.Printf("⚠️  Failed to end rental contract %s: %v", contract.ID, err)
+			response.Failed++
+			continue
+		}
+		response.Ended++
+	}
+
+	return response, nil
+}
+
+// GetContract retrieves a rental contract by ID
+func (s *RentalContractService) GetContract(ctx context.Context, tenantID, id string) (*models.RentalContract, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+
+	contract, err := s.contractRepo.Get(ctx, tenantID, id)
+	if err != nil {
+		return nil, fmt.Errorf("rental contract not found: %w", err)
+	}
+	return contract, nil
+}
+
+// ListContracts lists rental contracts with filters, latest start first
+func (s *RentalContractService) ListContracts(ctx context.Context, tenantID string, filters *repositories.RentalContractFilters, opts repositories.PaginationOptions) ([]*models.RentalContract, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+
+	contracts, err := s.contractRepo.List(ctx, tenantID, filters, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rental contracts: %w", err)
+	}
+	return contracts, nil
+}
+
+// activeContract returns a contract that is still in force
+func (s *RentalContractService) activeContract(ctx context.Context, tenantID, id string) (*models.RentalContract, error) {
+	contract, err := s.GetContract(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if !contract.IsActive() {
+		return nil, fmt.Errorf("%w: contract is %s", repositories.ErrInvalidInput, contract.Status)
+	}
+	return contract, nil
+}
+
+// end closes a contract on its move-out date: it leaves the property's
+// current contract for its history, and the vacant property goes back to
+// the market
+func (s *RentalContractService) end(ctx context.Context, contract *models.RentalContract, moveOut time.Time, actorID string) error {
+	contract.Status = models.RentalContractStatusEnded
+	if contract.TerminationReason != "" {
+		contract.Status = models.RentalContractStatusTerminated
+	}
+	contract.EndedAt = &moveOut
+
+	if err := s.contractRepo.Update(ctx, contract.TenantID, contract.ID, map[string]interface{}{
+		"status":             contract.Status,
+		"ended_at":           contract.EndedAt,
+		"termination_reason": contract.TerminationReason,
+	}); err != nil {
+		return fmt.Errorf("failed to update rental contract: %w", err)
+	}
+	_ = s.logActivity(ctx, contract.TenantID, "rental_contract_"+string(contract.Status), actorID, contractLogMetadata(contract))
+
+	property, err := s.propertyRepo.Get(ctx, contract.TenantID, contract.PropertyID)
+	if err != nil {
+		log.Printf("⚠️  Failed to get property %s of contract %s: %v", contract.PropertyID, contract.ID, err)
+		return nil
+	}
+	updates := map[string]interface{}{
+		"last_rental_end_date": moveOut,
+	}
+	if property.CurrentContractID != nil && *property.CurrentContractID == contract.ID {
+		updates["current_contract_id"] = nil
+	}
+	if !slices.Contains(property.ContractHistory, contract.ID) {
+		updates["contract_history"] = append(property.ContractHistory, contract.ID)
+	}
+	if err := s.propertyRepo.Update(ctx, contract.TenantID, contract.PropertyID, updates); err != nil {
+		log.Printf("⚠️  Failed to record the end of contract %s on property %s: %v", contract.ID, contract.PropertyID, err)
+	}
+
+	if property.Status == models.PropertyStatusUnavailable && property.UnavailableReason == models.PropertyUnavailableReasonRented {
+		if err := s.propertyService.UpdateStatus(ctx, contract.TenantID, contract.PropertyID, actorID, models.PropertyStatusAvailable); err != nil {
+			log.Printf("⚠️  Failed to put property %s back on the market: %v", contract.PropertyID, err)
+		}
+	}
+	return nil
+}
+
+// averageVacancyDays returns the average days the property stood empty
+// between its contracts, false before its second contract
+func (s *RentalContractService) averageVacancyDays(ctx context.Context, tenantID, propertyID string) (int, bool) {
+	contracts, err := s.contractRepo.List(ctx, tenantID, &repositories.RentalContractFilters{
+		PropertyID: propertyID,
+	}, repositories.PaginationOptions{Limit: rentalContractBatch})
+	if err != nil {
+		log.Printf("⚠️  Failed to list the contracts of property %s: %v", propertyID, err)
+		return 0, false
+	}
+
+	total, count := 0, 0
+	for _, contract := range contracts {
+		if contract.VacancyDaysBefore != nil {
+			total += *contract.VacancyDaysBefore
+			count++
+		}
+	}
+	if count == 0 {
+		return 0, false
+	}
+	return (total + count/2) / count, true
+}
+
+// applyRentalInfoDefaults fills the values the contract leaves empty from
+// the property's rental info, and its billing terms from the defaults
+func applyRentalInfoDefaults(contract *models.RentalContract, rental *models.RentalInfo) {
+	if rental != nil {
+		if contract.MonthlyRent == 0 {
+			contract.MonthlyRent = rental.MonthlyRent
+		}
+		if contract.CondoFee == 0 {
+			contract.CondoFee = rental.CondoFee
+		}
+		if contract.IPTUMonthly == 0 {
+			contract.IPTUMonthly = rental.IPTUMonthly
+		}
+		if contract.IndexationType == "" {
+			contract.IndexationType = rental.IndexationType
+		}
+		if contract.AdjustmentMonth == 0 {
+			contract.AdjustmentMonth = rental.AdjustmentMonth
+		}
+		if contract.DepositAmount == 0 && contract.GuaranteeType == models.GuaranteeTypeCaucao {
+			contract.DepositAmount = float64(rental.DepositMonths) * contract.MonthlyRent
+		}
+	}
+
+	if contract.IndexationType == "" {
+		contract.IndexationType = models.IndexationTypeIGPM
+	}
+	if contract.AdjustmentMonth == 0 {
+		contract.AdjustmentMonth = int(contract.StartDate.Month())
+	}
+
+	// Billing terms
+	if contract.DueDay == 0 {
+		contract.DueDay = contract.EffectiveDueDay()
+	}
+	if contract.LateFeePercentage == 0 {
+		contract.LateFeePercentage = models.DefaultLateFeePercentage
+	}
+	if contract.MonthlyInterestRate == 0 {
+		contract.MonthlyInterestRate = models.DefaultMonthlyInterestRate
+	}
+	if contract.AdminFeePercentage == 0 {
+		contract.AdminFeePercentage = models.DefaultAdminFeePercentage
+	}
+}
+
+// contractLogMetadata returns the activity log metadata of a rental contract
+func contractLogMetadata(contract *models.RentalContract) map[string]interface{} {
+	return map[string]interface{}{
+		"contract_id":  contract.ID,
+		"property_id":  contract.PropertyID,
+		"status":       contract.Status,
+		"end_date":     contract.EndDate,
+		"monthly_rent": contract.MonthlyRent,
+	}
+}
+
+// logActivity logs an activity; actions without an actor are the system's
+func (s *RentalContractService) logActivity(ctx context.Context, tenantID, eventType, actorID string, metadata map[string]interface{}) error {
+	actorType := models.ActorTypeUser
+	if actorID == "" {
+		actorType = models.ActorTypeSystem
+	}
+
+	log := &models.ActivityLog{
+		TenantID:  tenantID,
+		EventType: eventType,
+		ActorType: actorType,
+		ActorID:   actorID,
+		Metadata:  metadata,
+		Timestamp: time.Now(),
+	}
+
+	return s.activityLogRepo.Create(ctx, log)
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories/memory"
+)
+
+// newRentalContractTestService returns a rental contract service over the
+// routing test data: property "pinheiros" (owner-1) is for rent at R$ 3.000
+// plus R$ 800 condo and R$ 150 IPTU, with 3 months of deposit, IPCA and
+// caução or seguro fiança
+func newRentalContractTestService(t *testing.T) *RentalContractService {
+	t.Helper()
+	ctx := context.Background()
+
+	leadService, _ := newActivityTestService(t)
+	require.NoError(t, leadService.propertyRepo.Update(ctx, "tenant-1", "pinheiros", map[string]interface{}{
+		"status":           models.PropertyStatusAvailable,
+		"owner_id":         "owner-1",
+		"transaction_type": models.TransactionTypeRent,
+		"rental_info": &models.RentalInfo{
+			MonthlyRent:        3000,
+			CondoFee:           800,
+			IPTUMonthly:        150,
+			DepositMonths:      3,
+			AcceptedGuarantees: []string{"caucao", "seguro_fianca"},
+			IndexationType:     models.IndexationTypeIPCA,
+		},
+	}))
+
+	propertyService := NewPropertyService(leadService.propertyRepo, memory.NewListingRepository(), memory.NewOwnerRepository(), leadService.brokerRepo, leadService.tenantRepo, leadService.activityLogRepo)
+	return NewRentalContractService(
+		memory.NewRentalContractRepository(),
+		memory.NewDealRepository(),
+		leadService,
+		propertyService,
+		leadService.propertyRepo,
+		leadService.activityLogRepo,
+	)
+}
+
+func newTestContract(start, end time.Time) *models.RentalContract {
+	return &models.RentalContract{
+		TenantID:      "tenant-1",
+		PropertyID:    "pinheiros",
+		Tenant:        models.RentalTenant{Name: "João Pereira", Document: "123.456.789-09"},
+		GuaranteeType: models.GuaranteeTypeCaucao,
+		StartDate:     start,
+		EndDate:       end,
+	}
+}
+
+func testDate(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestCreateContract_DefaultsAndPropertyFields(t *testing.T) {
+	ctx := context.Background()
+	service := newRentalContractTestService(t)
+
+	// The property only accepts caução and seguro fiança
+	contract := newTestContract(testDate(2024, 3, 1), testDate(2026, 2, 28))
+	contract.GuaranteeType = models.GuaranteeTypeFiador
+	err := service.CreateContract(ctx, contract, "user-1")
+	assert.True(t, errors.Is(err, repositories.ErrInvalidInput))
+
+	contract = newTestContract(testDate(2024, 3, 1), testDate(2026, 2, 28))
+	require.NoError(t, service.CreateContract(ctx, contract, "user-1"))
+	assert.NotEmpty(t, contract.ID)
+	assert.Equal(t, models.RentalContractStatusActive, contract.Status)
+	assert.Equal(t, "owner-1", contract.OwnerID)
+	assert.Equal(t, 3950.0, contract.TotalMonthly())
+	assert.Equal(t, 9000.0, contract.DepositAmount)
+	assert.Equal(t, models.IndexationTypeIPCA, contract.IndexationType)
+	assert.Equal(t, 3, contract.AdjustmentMonth)
+	assert.Equal(t, 1, contract.DueDay)
+	assert.Equal(t, models.DefaultLateFeePercentage, contract.LateFeePercentage)
+	assert.Equal(t, models.DefaultAdminFeePercentage, contract.AdminFeePercentage)
+	assert.Nil(t, contract.VacancyDaysBefore)
+
+	property, err := service.propertyRepo.Get(ctx, "tenant-1", "pinheiros")
+	require.NoError(t, err)
+	require.NotNil(t, property.CurrentContractID)
+	assert.Equal(t, contract.ID, *property.CurrentContractID)
+	assert.Equal(t, models.PropertyStatusUnavailable, property.Status)
+	assert.Equal(t, models.PropertyUnavailableReasonRented, property.UnavailableReason)
+
+	// One contract at a time, and only on properties for rent
+	err = service.CreateContract(ctx, newTestContract(testDate(2024, 4, 1), testDate(2025, 3, 31)), "user-1")
+	assert.True(t, errors.Is(err, repositories.ErrInvalidInput))
+	other := newTestContract(testDate(2024, 4, 1), testDate(2025, 3, 31))
+	other.PropertyID = "moema"
+	err = service.CreateContract(ctx, other, "user-1")
+	assert.True(t, errors.Is(err, repositories.ErrInvalidInput))
+}
+
+func TestContractRenewalTerminationAndVacancy(t *testing.T) {
+	ctx := context.Background()
+	service := newRentalContractTestService(t)
+
+	first := newTestContract(testDate(2023, 1, 10), testDate(2024, 1, 9))
+	require.NoError(t, service.CreateContract(ctx, first, "user-1"))
+
+	renewed, err := service.RenewContract(ctx, "tenant-1", first.ID, RenewContractRequest{EndDate: testDate(2025, 1, 9), MonthlyRent: 3300}, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, testDate(2025, 1, 9), renewed.EndDate)
+	assert.Equal(t, 3300.0, renewed.MonthlyRent)
+	require.Len(t, renewed.Renewals, 1)
+	assert.Equal(t, 3000.0, renewed.Renewals[0].PreviousRent)
+
+	_, err = service.TerminateContract(ctx, "tenant-1", first.ID, testDate(2024, 6, 30), "", "user-1")
+	assert.True(t, errors.Is(err, repositories.ErrInvalidInput))
+	terminated, err := service.TerminateContract(ctx, "tenant-1", first.ID, testDate(2024, 6, 30), "transferência de emprego", "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, models.RentalContractStatusTerminated, terminated.Status)
+
+	// The property is vacant and back on the market
+	property, err := service.propertyRepo.Get(ctx, "tenant-1", "pinheiros")
+	require.NoError(t, err)
+	assert.Nil(t, property.CurrentContractID)
+	assert.Equal(t, []string{first.ID}, property.ContractHistory)
+	require.NotNil(t, property.LastRentalEndDate)
+	assert.Equal(t, testDate(2024, 6, 30), property.LastRentalEndDate.UTC())
+	assert.Equal(t, models.PropertyStatusAvailable, property.Status)
+	assert.Empty(t, property.UnavailableReason)
+
+	// The next contract records the 20 empty days
+	second := newTestContract(testDate(2024, 7, 20), testDate(2025, 7, 19))
+	require.NoError(t, service.CreateContract(ctx, second, "user-1"))
+	require.NotNil(t, second.VacancyDaysBefore)
+	assert.Equal(t, 20, *second.VacancyDaysBefore)
+	property, err = service.propertyRepo.Get(ctx, "tenant-1", "pinheiros")
+	require.NoError(t, err)
+	require.NotNil(t, property.AverageVacancyDays)
+	assert.Equal(t, 20, *property.AverageVacancyDays)
+
+	_, err = service.RenewContract(ctx, "tenant-1", first.ID, RenewContractRequest{EndDate: testDate(2026, 1, 9)}, "user-1")
+	assert.True(t, errors.Is(err, repositories.ErrInvalidInput))
+}
+
+func TestProcessEndedContracts(t *testing.T) {
+	ctx := context.Background()
+	service := newRentalContractTestService(t)
+
+	now := time.Now()
+	contract := newTestContract(now.AddDate(-1, 0, -1), now.AddDate(0, 2, 0))
+	require.NoError(t, service.CreateContract(ctx, contract, "user-1"))
+
+	// A future move-out keeps the contract active until then
+	moveOut := now.AddDate(0, 0, 30)
+	scheduled, err := service.TerminateContract(ctx, "tenant-1", contract.ID, moveOut, "aviso prévio", "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, models.RentalContractStatusActive, scheduled.Status)
+	assert.Equal(t, moveOut, scheduled.EndDate)
+
+	response, err := service.ProcessEndedContracts(ctx, "tenant-1")
+	require.NoError(t, err)
+	assert.Equal(t, &ProcessEndedContractsResponse{}, response)
+
+	// Past its end date the job ends it
+	require.NoError(t, service.contractRepo.Update(ctx, "tenant-1", contract.ID, map[string]interface{}{"end_date": now.AddDate(0, 0, -1)}))
+	response, err = service.ProcessEndedContracts(ctx, "tenant-1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, response.Ended)
+
+	ended, err := service.GetContract(ctx, "tenant-1", contract.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.RentalContractStatusTerminated, ended.Status)
+	require.NotNil(t, ended.EndedAt)
+	property, err := service.propertyRepo.Get(ctx, "tenant-1", "pinheiros")
+	require.NoError(t, err)
+	assert.Nil(t, property.CurrentContractID)
+}
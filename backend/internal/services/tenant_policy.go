@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// GetTenantPolicy returns the staleness and confirmation policy of a tenant,
+// with defaults applied
+func (s *TenantService) GetTenantPolicy(ctx context.Context, tenantID string) (models.TenantPolicy, error) {
+	tenant, err := s.GetTenant(ctx, tenantID)
+	if err != nil {
+		return models.TenantPolicy{}, err
+	}
+	return tenant.EffectivePolicy(), nil
+}
+
+// UpdateTenantPolicy validates and stores the policy of a tenant; unset fields
+// take the default values
+func (s *TenantService) UpdateTenantPolicy(ctx context.Context, tenantID string, policy models.TenantPolicy) (models.TenantPolicy, error) {
+	if tenantID == "" {
+		return models.TenantPolicy{}, fmt.Errorf("tenant ID is required")
+	}
+
+	policy = policy.WithDefaults()
+	if err := policy.Validate(); err != nil {
+		return models.TenantPolicy{}, fmt.Errorf("%w: %v", repositories.ErrInvalidInput, err)
+	}
+
+	if err := s.tenantRepo.Update(ctx, tenantID, map[string]interface{}{"policy": policy}); err != nil {
+		return models.TenantPolicy{}, err
+	}
+
+	_ = s.logActivity(ctx, tenantID, "tenant_policy_updated", models.ActorTypeSystem, "", map[string]interface{}{
+		"tenant_id": tenantID,
+		"policy":    policy,
+	})
+
+	return policy, nil
+}
+
+// tenantSettings is a group of tenant settings stored typed on the tenant
+// (policy, notifications, lead_routing, lead_sla, commission)
+type tenantSettings interface {
+	Validate() error
+}
+
+// decodeSettings converts an UpdateTenant value (typed or decoded JSON) into
+// validated settings, applying the defaults first when T has any. field names
+// the value in the errors.
+func decodeSettings[T tenantSettings](field string, value interface{}) (T, error) {
+	settings, ok := value.(T)
+	if !ok {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return settings, fmt.Errorf("invalid %s: %w", field, err)
+		}
+		if err := json.Unmarshal(data, &settings); err != nil {
+			return settings, fmt.Errorf("invalid %s: %w", field, err)
+		}
+	}
+
+	if defaults, ok := any(settings).(interface{ WithDefaults() T }); ok {
+		settings = defaults.WithDefaults()
+	}
+	if err := settings.Validate(); err != nil {
+		return settings, fmt.Errorf("invalid %s: %w", field, err)
+	}
+	return settings, nil
+}
+
+// loadTenantPolicy returns the policy of a tenant, falling back to the
+// defaults when the tenant cannot be loaded (or tenantRepo is nil)
+func loadTenantPolicy(ctx context.Context, tenantRepo repositories.TenantStore, tenantID string) models.TenantPolicy {
+	if tenantRepo == nil {
+		return models.DefaultTenantPolicy()
+	}
+
+	tenant, err := tenantRepo.Get(ctx, tenantID)
+	if err != nil {
+		log.Printf("⚠️  Failed to load policy of tenant %s, using defaults: %v", tenantID, err)
+		return models.DefaultTenantPolicy()
+	}
+	return tenant.EffectivePolicy()
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories/memory"
+)
+
+func TestTenantPolicy_UpdateValidates(t *testing.T) {
+	ctx := context.Background()
+	tenantRepo := memory.NewTenantRepository()
+	require.NoError(t, tenantRepo.Create(ctx, &models.Tenant{ID: "tenant-1", Name: "Imobiliária Teste", IsActive: true}))
+	tenantService := NewTenantService(tenantRepo, memory.NewActivityLogRepository())
+
+	_, err := tenantService.UpdateTenantPolicy(ctx, "tenant-1", models.TenantPolicy{StatusTTLDays: 40, HideAfterDays: 20})
+	assert.ErrorIs(t, err, repositories.ErrInvalidInput)
+
+	policy, err := tenantService.UpdateTenantPolicy(ctx, "tenant-1", models.TenantPolicy{HideAfterDays: 45})
+	require.NoError(t, err)
+	assert.Equal(t, 45, policy.HideAfterDays)
+	assert.Equal(t, models.DefaultStatusTTLDays, policy.StatusTTLDays)
+
+	stored, err := tenantService.GetTenantPolicy(ctx, "tenant-1")
+	require.NoError(t, err)
+	assert.Equal(t, policy, stored)
+
+	// Generic tenant updates validate the policy too
+	err = tenantService.UpdateTenant(ctx, "tenant-1", map[string]interface{}{
+		"policy": map[string]interface{}{"confirmation_token_ttl_days": float64(90)},
+	})
+	assert.Error(t, err)
+}
+
+func TestDecodeSettings(t *testing.T) {
+	// Decoded JSON, with the defaults applied
+	sla, err := decodeSettings[models.LeadSLASettings]("lead_sla", map[string]interface{}{"response_minutes": float64(30)})
+	require.NoError(t, err)
+	assert.Equal(t, 30, sla.ResponseMinutes)
+	assert.Equal(t, models.DefaultLeadSLA().MaxReassignments, sla.MaxReassignments)
+
+	// Typed values are validated as they are
+	notifications, err := decodeSettings[models.NotificationSettings]("notifications", models.NotificationSettings{
+		Channels: []models.NotificationChannel{models.NotificationChannelEmail},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []models.NotificationChannel{models.NotificationChannelEmail}, notifications.Channels)
+
+	_, err = decodeSettings[models.NotificationSettings]("notifications", map[string]interface{}{"channels": []string{"fax"}})
+	assert.ErrorContains(t, err, "invalid notifications")
+
+	_, err = decodeSettings[models.TenantPolicy]("policy", "30 days")
+	assert.ErrorContains(t, err, "invalid policy")
+}
+
+func TestTenantPolicy_DrivesStalenessAndTokenTTL(t *testing.T) {
+	ctx := context.Background()
+	propertyService, ownerConfirmationService := newHistoryTestServices(t)
+	tenantRepo := propertyService.tenantRepo
+	ownerConfirmationService.SetTenantRepository(tenantRepo)
+
+	require.NoError(t, tenantRepo.Update(ctx, "tenant-1", map[string]interface{}{
+		"policy": models.TenantPolicy{
+			StatusTTLDays:            5,
+			HideAfterDays:            10,
+			ConfirmationTokenTTLDays: 2,
+			AutoHideVisibilities:     []models.PropertyVisibility{models.PropertyVisibilityNetwork},
+		}.WithDefaults(),
+	}))
+
+	// Confirmed 12 days ago: hidden under this policy (network is auto-hidden)
+	confirmedAt := time.Now().AddDate(0, 0, -12)
+	network := createHistoryTestProperty(t, propertyService)
+	require.NoError(t, propertyService.propertyRepo.Update(ctx, "tenant-1", network.ID, map[string]interface{}{
+		"status_confirmed_at": confirmedAt,
+		"visibility":          models.PropertyVisibilityNetwork,
+	}))
+	public := createHistoryTestProperty(t, propertyService)
+	require.NoError(t, propertyService.propertyRepo.Update(ctx, "tenant-1", public.ID, map[string]interface{}{
+		"status_confirmed_at": confirmedAt,
+		"visibility":          models.PropertyVisibilityPublic,
+	}))
+
+	_, err := propertyService.RecalculateTenantStaleness(ctx, "tenant-1")
+	require.NoError(t, err)
+
+	updated, err := propertyService.GetProperty(ctx, "tenant-1", network.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.PropertyVisibilityPrivate, updated.Visibility)
+
+	updated, err = propertyService.GetProperty(ctx, "tenant-1", public.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.PropertyVisibilityPublic, updated.Visibility)
+
+	_, _, expiresAt, err := ownerConfirmationService.GenerateOwnerConfirmationLink(ctx, "tenant-1", network.ID, "user-1", nil, "whatsapp")
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(48*time.Hour), expiresAt, time.Minute)
+}
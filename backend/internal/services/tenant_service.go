@@ -18,14 +18,14 @@ import (
 
 // TenantService handles business logic for tenant management
 type TenantService struct {
-	tenantRepo      *repositories.TenantRepository
-	activityLogRepo *repositories.ActivityLogRepository
+	tenantRepo      repositories.TenantStore
+	activityLogRepo repositories.ActivityLogStore
 }
 
 // NewTenantService creates a new tenant service
 func NewTenantService(
-	tenantRepo *repositories.TenantRepository,
-	activityLogRepo *repositories.ActivityLogRepository,
+	tenantRepo repositories.TenantStore,
+	activityLogRepo repositories.ActivityLogStore,
 ) *TenantService {
 	return &TenantService{
 		tenantRepo:      tenantRepo,
@@ -283,6 +283,51 @@ func (s *TenantService) UpdateTenant(ctx context.Context, id string, updates map
 		updates["phone"] = utils.NormalizePhoneBR(cleanPhone)
 	}
 
+	// Validate policy if being updated (stored typed, with defaults applied)
+	if value, ok := updates["policy"]; ok {
+		policy, err := decodeSettings[models.TenantPolicy]("policy", value)
+		if err != nil {
+			return err
+		}
+		updates["policy"] = policy
+	}
+
+	// Validate notification settings if being updated
+	if value, ok := updates["notifications"]; ok {
+		settings, err := decodeSettings[models.NotificationSettings]("notifications", value)
+		if err != nil {
+			return err
+		}
+		updates["notifications"] = settings
+	}
+
+	// Validate lead routing settings if being updated
+	if value, ok := updates["lead_routing"]; ok {
+		routing, err := decodeSettings[models.LeadRoutingSettings]("lead_routing", value)
+		if err != nil {
+			return err
+		}
+		updates["lead_routing"] = routing
+	}
+
+	// Validate lead SLA settings if being updated (stored with defaults applied)
+	if value, ok := updates["lead_sla"]; ok {
+		settings, err := decodeSettings[models.LeadSLASettings]("lead_sla", value)
+		if err != nil {
+			return err
+		}
+		updates["lead_sla"] = settings
+	}
+
+	// Validate commission settings if being updated (stored with defaults applied)
+	if value, ok := updates["commission"]; ok {
+		settings, err := decodeSettings[models.CommissionSettings]("commission", value)
+		if err != nil {
+			return err
+		}
+		updates["commission"] = settings
+	}
+
 	// Update tenant in repository
 	if err := s.tenantRepo.Update(ctx, id, updates); err != nil {
 		return fmt.Errorf("failed to update tenant: %w", err)
@@ -12,16 +12,16 @@ import (
 
 // UserService handles business logic for administrative user management
 type UserService struct {
-	userRepo        *repositories.UserRepository
-	tenantRepo      *repositories.TenantRepository
-	activityLogRepo *repositories.ActivityLogRepository
+	userRepo        repositories.UserStore
+	tenantRepo      repositories.TenantStore
+	activityLogRepo repositories.ActivityLogStore
 }
 
 // NewUserService creates a new user service
 func NewUserService(
-	userRepo *repositories.UserRepository,
-	tenantRepo *repositories.TenantRepository,
-	activityLogRepo *repositories.ActivityLogRepository,
+	userRepo repositories.UserStore,
+	tenantRepo repositories.TenantStore,
+	activityLogRepo repositories.ActivityLogStore,
 ) *UserService {
 	return &UserService{
 		userRepo:        userRepo,
@@ -120,7 +120,7 @@ func (s *UserService) CreateUser(ctx context.Context, user *models.User) error {
 		user.Role = "admin" // Default role
 	}
 	if !models.IsValidUserRole(user.Role) {
-		return fmt.Errorf("invalid role: must be 'admin' or 'manager'")
+		return fmt.Errorf("invalid role: must be one of %v", models.ValidUserRoles())
 	}
 
 	// Set timestamps
@@ -230,7 +230,7 @@ func (s *UserService) UpdateUser(ctx context.Context, tenantID, userID string, u
 	// Validate role if being updated
 	if role, ok := updates["role"].(string); ok && role != "" {
 		if !models.IsValidUserRole(role) {
-			return fmt.Errorf("invalid role: must be 'admin' or 'manager'")
+			return fmt.Errorf("invalid role: must be one of %v", models.ValidUserRoles())
 		}
 	}
 
@@ -327,6 +327,10 @@ func (s *UserService) DeleteUser(ctx context.Context, tenantID, userID string) e
 
 // GrantPermission adds a permission to a user
 func (s *UserService) GrantPermission(ctx context.Context, tenantID, userID, permission string) error {
+	if !models.IsValidPermission(permission) {
+		return fmt.Errorf("invalid permission: %s", permission)
+	}
+
 	user, err := s.userRepo.Get(ctx, tenantID, userID)
 	if err != nil {
 		return fmt.Errorf("user not found: %w", err)
@@ -337,8 +341,9 @@ func (s *UserService) GrantPermission(ctx context.Context, tenantID, userID, per
 
 	// Update user
 	updates := map[string]interface{}{
-		"permissions": user.Permissions,
-		"updated_at":  time.Now(),
+		"permissions":        user.Permissions,
+		"denied_permissions": user.DeniedPermissions,
+		"updated_at":         time.Now(),
 	}
 
 	if err := s.userRepo.Update(ctx, tenantID, userID, updates); err != nil {
@@ -366,8 +371,9 @@ func (s *UserService) RevokePermission(ctx context.Context, tenantID, userID, pe
 
 	// Update user
 	updates := map[string]interface{}{
-		"permissions": user.Permissions,
-		"updated_at":  time.Now(),
+		"permissions":        user.Permissions,
+		"denied_permissions": user.DeniedPermissions,
+		"updated_at":         time.Now(),
 	}
 
 	if err := s.userRepo.Update(ctx, tenantID, userID, updates); err != nil {
@@ -182,7 +182,7 @@ func (m *MockTenantRepository) Delete(ctx context.Context, tenantID string) erro
 	return nil
 }
 
-func (m *MockTenantRepository) List(ctx context.Context) ([]*models.Tenant, error) {
+func (m *MockTenantRepository) List(ctx context.Context, opts repositories.PaginationOptions) ([]*models.Tenant, error) {
 	var tenants []*models.Tenant
 	for _, t := range m.tenants {
 		tenants = append(tenants, t)
@@ -190,7 +190,7 @@ func (m *MockTenantRepository) List(ctx context.Context) ([]*models.Tenant, erro
 	return tenants, nil
 }
 
-func (m *MockTenantRepository) ListActive(ctx context.Context) ([]*models.Tenant, error) {
+func (m *MockTenantRepository) ListActive(ctx context.Context, opts repositories.PaginationOptions) ([]*models.Tenant, error) {
 	var tenants []*models.Tenant
 	for _, t := range m.tenants {
 		if t.IsActive {
@@ -211,6 +211,8 @@ func (m *MockTenantRepository) GetBySlug(ctx context.Context, slug string) (*mod
 
 // MockActivityLogRepository is a mock implementation of ActivityLogRepository for testing
 type MockActivityLogRepository struct {
+	repositories.ActivityLogStore // unimplemented methods panic if called
+
 	logs []*models.ActivityLog
 }
 
@@ -226,7 +228,7 @@ func (m *MockActivityLogRepository) Create(ctx context.Context, log *models.Acti
 	return nil
 }
 
-func (m *MockActivityLogRepository) List(ctx context.Context, tenantID string, limit int) ([]*models.ActivityLog, error) {
+func (m *MockActivityLogRepository) List(ctx context.Context, tenantID string, filters *repositories.ActivityLogFilters, opts repositories.PaginationOptions) ([]*models.ActivityLog, error) {
 	var filtered []*models.ActivityLog
 	for _, log := range m.logs {
 		if log.TenantID == tenantID {
@@ -403,12 +405,12 @@ func TestCreateUser_InvalidRole(t *testing.T) {
 		FirebaseUID: "firebase-uid-1",
 		Name:        "John Admin",
 		Email:       "john@example.com",
-		Role:        "broker", // Invalid role for admin users
+		Role:        "superuser", // Not in the role catalog
 	}
 
 	err := service.CreateUser(context.Background(), user)
 	if err == nil {
-		t.Error("Expected error for invalid role 'broker', got nil")
+		t.Error("Expected error for invalid role 'superuser', got nil")
 	}
 }
 
@@ -484,6 +486,35 @@ func TestGrantPermission(t *testing.T) {
 	}
 }
 
+// Test GrantPermission - Unknown permission
+func TestGrantPermission_InvalidPermission(t *testing.T) {
+	mockUserRepo := NewMockUserRepository()
+	mockTenantRepo := NewMockTenantRepository()
+	mockActivityLogRepo := NewMockActivityLogRepository()
+
+	service := NewUserService(mockUserRepo, mockTenantRepo, mockActivityLogRepo)
+
+	user := &models.User{
+		ID:          "user-1",
+		TenantID:    "tenant-1",
+		FirebaseUID: "firebase-uid-1",
+		Name:        "John Admin",
+		Email:       "john@example.com",
+		Role:        "manager",
+	}
+	mockUserRepo.Create(context.Background(), user)
+
+	err := service.GrantPermission(context.Background(), "tenant-1", "user-1", "properties.edit_all")
+	if err == nil {
+		t.Error("Expected error for permission outside the catalog, got nil")
+	}
+
+	updated, _ := mockUserRepo.Get(context.Background(), "tenant-1", "user-1")
+	if len(updated.Permissions) != 0 {
+		t.Errorf("Expected 0 permissions, got %d", len(updated.Permissions))
+	}
+}
+
 // Test RevokePermission
 func TestRevokePermission(t *testing.T) {
 	mockUserRepo := NewMockUserRepository()
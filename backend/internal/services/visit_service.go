@@ -0,0 +1,812 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/ical"
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/notify"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+)
+
+// Limits of visit scheduling
+const (
+	minVisitNotice       = 2 * time.Hour // Public requests start at least this far ahead
+	defaultVisitSlotDays = 14
+	maxVisitSlotDays     = 31
+	brokerVisitsLimit    = 1000 // Visits read to check a broker's schedule
+	visitCalendarPast    = 30 * 24 * time.Hour
+	visitCalendarAhead   = 180 * 24 * time.Hour
+)
+
+// ErrVisitConflict is returned when the broker cannot take a visit at the
+// requested time (outside availability, blocked or overlapping another visit)
+var ErrVisitConflict = errors.New("visit time not available")
+
+// VisitService handles property visits and broker availability
+type VisitService struct {
+	visitRepo       repositories.VisitStore
+	leadService     *LeadService
+	propertyRepo    repositories.PropertyStore
+	roleRepo        repositories.PropertyBrokerRoleStore
+	brokerRepo      repositories.BrokerStore
+	tenantRepo      repositories.TenantStore
+	activityLogRepo repositories.ActivityLogStore
+	notifier        *notify.Dispatcher // optional, see SetNotifier
+}
+
+// NewVisitService creates a new visit service
+func NewVisitService(
+	visitRepo repositories.VisitStore,
+	leadService *LeadService,
+	propertyRepo repositories.PropertyStore,
+	roleRepo repositories.PropertyBrokerRoleStore,
+	brokerRepo repositories.BrokerStore,
+	tenantRepo repositories.TenantStore,
+	activityLogRepo repositories.ActivityLogStore,
+) *VisitService {
+	return &VisitService{
+		visitRepo:       visitRepo,
+		leadService:     leadService,
+		propertyRepo:    propertyRepo,
+		roleRepo:        roleRepo,
+		brokerRepo:      brokerRepo,
+		tenantRepo:      tenantRepo,
+		activityLogRepo: activityLogRepo,
+	}
+}
+
+// SetNotifier enables the messages of visit requests, confirmations and
+// cancellations. Without it visits are managed from the dashboard only.
+func (s *VisitService) SetNotifier(notifier *notify.Dispatcher) {
+	s.notifier = notifier
+}
+
+// ============================================================================
+// Availability
+// ============================================================================
+
+// GetBrokerAvailability returns the visit calendar of a broker (the default
+// one when not configured)
+func (s *VisitService) GetBrokerAvailability(ctx context.Context, tenantID, brokerID string) (*models.BrokerAvailability, error) {
+	broker, err := s.broker(ctx, tenantID, brokerID)
+	if err != nil {
+		return nil, err
+	}
+
+	availability := broker.EffectiveAvailability()
+	return &availability, nil
+}
+
+// SetBrokerAvailability replaces the visit calendar of a broker. Visits
+// already booked are kept.
+func (s *VisitService) SetBrokerAvailability(ctx context.Context, tenantID, brokerID string, availability models.BrokerAvailability, actorID string) (*models.BrokerAvailability, error) {
+	if _, err := s.broker(ctx, tenantID, brokerID); err != nil {
+		return nil, err
+	}
+
+	availability = availability.WithDefaults()
+	if err := availability.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", repositories.ErrInvalidInput, err)
+	}
+
+	// Past time off is no longer needed
+	now := time.Now()
+	blocks := availability.Blocks[:0]
+	for _, block := range availability.Blocks {
+		if block.EndAt.After(now) {
+			blocks = append(blocks, block)
+		}
+	}
+	availability.Blocks = blocks
+
+	if err := s.brokerRepo.Update(ctx, tenantID, brokerID, map[string]interface{}{"availability": &availability}); err != nil {
+		return nil, fmt.Errorf("failed to update broker availability: %w", err)
+	}
+
+	_ = s.logActivity(ctx, tenantID, "broker_availability_updated", models.ActorTypeUser, actorID, map[string]interface{}{
+		"broker_id": brokerID,
+		"windows":   len(availability.Windows),
+		"blocks":    len(availability.Blocks),
+	})
+
+	return &availability, nil
+}
+
+// VisitSlots are the free visit slots of the broker that shows a property
+type VisitSlots struct {
+	PropertyID string             `json:"property_id"`
+	BrokerID   string             `json:"broker_id"`
+	Timezone   string             `json:"timezone"`
+	Slots      []models.VisitSlot `json:"slots"`
+}
+
+// AvailableSlots lists the free visit slots of a property over the given
+// number of days from from (never earlier than the minimum notice)
+func (s *VisitService) AvailableSlots(ctx context.Context, tenantID, propertyID string, from time.Time, days int) (*VisitSlots, error) {
+	_, broker, err := s.propertyBroker(ctx, tenantID, propertyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if days <= 0 {
+		days = defaultVisitSlotDays
+	}
+	if days > maxVisitSlotDays {
+		days = maxVisitSlotDays
+	}
+	if earliest := time.Now().Add(minVisitNotice); from.Before(earliest) {
+		from = earliest
+	}
+
+	availability := broker.EffectiveAvailability()
+	local := from.In(availability.Location())
+	until := time.Date(local.Year(), local.Month(), local.Day()+days, 0, 0, 0, 0, local.Location())
+
+	busy, err := s.brokerVisits(ctx, tenantID, broker.ID, from, until)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &VisitSlots{
+		PropertyID: propertyID,
+		BrokerID:   broker.ID,
+		Timezone:   availability.Timezone,
+		Slots:      []models.VisitSlot{},
+	}
+	for _, slot := range availability.Slots(from, until) {
+		if !overlapsAny(busy, slot.StartAt, slot.EndAt) {
+			response.Slots = append(response.Slots, slot)
+		}
+	}
+	return response, nil
+}
+
+// ============================================================================
+// Booking
+// ============================================================================
+
+// RequestVisit books a visit asked for from the property page: it holds the
+// slot with a requested visit, then creates the lead (assigned to the broker
+// that shows the property) and tells the broker. The returned token lets the lead cancel.
+func (s *VisitService) RequestVisit(ctx context.Context, lead *models.Lead, startAt time.Time) (*models.Visit, string, error) {
+	property, broker, err := s.propertyBroker(ctx, lead.TenantID, lead.PropertyID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if property.Status != models.PropertyStatusAvailable {
+		return nil, "", fmt.Errorf("%w: property is not available", repositories.ErrInvalidInput)
+	}
+	if startAt.Before(time.Now().Add(minVisitNotice)) {
+		return nil, "", fmt.Errorf("%w: start_at must be at least %s ahead", repositories.ErrInvalidInput, minVisitNotice)
+	}
+	availability := broker.EffectiveAvailability()
+	endAt := startAt.Add(availability.VisitLength())
+	if !availability.Covers(startAt, endAt) {
+		return nil, "", fmt.Errorf("%w: outside the broker's visit hours", ErrVisitConflict)
+	}
+	if err := s.checkSchedule(ctx, broker, startAt, endAt); err != nil {
+		return nil, "", err
+	}
+
+	token, tokenHash, err := newVisitToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	visit := &models.Visit{
+		TenantID:        lead.TenantID,
+		PropertyID:      property.ID,
+		BrokerID:        broker.ID,
+		Status:          models.VisitStatusRequested,
+		Source:          models.VisitSourcePublic,
+		StartAt:         startAt,
+		EndAt:           endAt,
+		Notes:           lead.Message,
+		CancelTokenHash: tokenHash,
+	}
+	created, err := s.visitRepo.CreateIfAvailable(ctx, visit)
+	if err != nil {
+		return nil, "", err
+	}
+	if !created {
+		return nil, "", fmt.Errorf("%w: the slot was just taken", ErrVisitConflict)
+	}
+
+	if lead.Channel == "" {
+		lead.Channel = models.LeadChannelForm
+	}
+	lead.AssignedBrokerID = broker.ID
+	if err := s.leadService.CreateLead(ctx, lead); err != nil {
+		// Release the slot held for a request nobody can follow up
+		if cancelErr := s.cancel(ctx, visit, "", "lead could not be created"); cancelErr != nil {
+			log.Printf("⚠️  Failed to release visit %s after its lead failed: %v", visit.ID, cancelErr)
+		}
+		return nil, "", err
+	}
+
+	visit.LeadID = lead.ID
+	if err := s.visitRepo.Update(ctx, visit.TenantID, visit.ID, map[string]interface{}{
+		"lead_id": visit.LeadID,
+	}); err != nil {
+		log.Printf("⚠️  Failed to link visit %s to lead %s: %v", visit.ID, lead.ID, err)
+	}
+
+	_ = s.logActivity(ctx, visit.TenantID, "visit_requested", models.ActorTypeLead, lead.ID, visitLogMetadata(visit))
+
+	s.notify(ctx, visit, models.NotificationTemplateVisitRequested, recipient{Name: broker.Name, Phone: broker.Phone, Email: broker.Email}, "")
+
+	return visit, token, nil
+}
+
+// ScheduleVisit books a confirmed visit for a lead from the dashboard. The
+// property defaults to the lead's and the broker to the lead's broker (or the
+// broker that shows the property). Weekly hours are not enforced, but the
+// broker's time off and other visits are. The lead is told and receives a
+// token to cancel.
+func (s *VisitService) ScheduleVisit(ctx context.Context, visit *models.Visit, actorID string) (string, error) {
+	lead, err := s.leadService.activeLead(ctx, visit.TenantID, visit.LeadID)
+	if err != nil {
+		return "", err
+	}
+
+	if visit.PropertyID == "" {
+		visit.PropertyID = lead.PropertyID
+	}
+	if visit.BrokerID == "" {
+		visit.BrokerID = lead.AssignedBrokerID
+	}
+
+	var broker *models.Broker
+	if visit.BrokerID == "" {
+		if _, broker, err = s.propertyBroker(ctx, visit.TenantID, visit.PropertyID); err != nil {
+			return "", err
+		}
+		visit.BrokerID = broker.ID
+	} else {
+		if _, err := s.propertyRepo.Get(ctx, visit.TenantID, visit.PropertyID); err != nil {
+			return "", fmt.Errorf("property not found: %w", err)
+		}
+		if broker, err = s.activeBroker(ctx, visit.TenantID, visit.BrokerID); err != nil {
+			return "", err
+		}
+	}
+
+	if visit.StartAt.IsZero() {
+		return "", fmt.Errorf("%w: start_at is required", repositories.ErrInvalidInput)
+	}
+	if visit.StartAt.Before(time.Now()) {
+		return "", fmt.Errorf("%w: start_at must be in the future", repositories.ErrInvalidInput)
+	}
+	if visit.EndAt.IsZero() {
+		visit.EndAt = visit.StartAt.Add(broker.EffectiveAvailability().VisitLength())
+	}
+	if !visit.EndAt.After(visit.StartAt) || visit.EndAt.Sub(visit.StartAt) > models.MaxVisitMinutes*time.Minute {
+		return "", fmt.Errorf("%w: end_at must be after start_at and within %d minutes", repositories.ErrInvalidInput, models.MaxVisitMinutes)
+	}
+	if broker.EffectiveAvailability().Blocked(visit.StartAt, visit.EndAt) {
+		return "", fmt.Errorf("%w: the broker is off at this time", ErrVisitConflict)
+	}
+	if err := s.checkSchedule(ctx, broker, visit.StartAt, visit.EndAt); err != nil {
+		return "", err
+	}
+
+	token, tokenHash, err := newVisitToken()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	visit.Status = models.VisitStatusConfirmed
+	visit.Source = models.VisitSourceAdmin
+	visit.ConfirmedAt = &now
+	visit.CancelTokenHash = tokenHash
+	visit.CancelledAt, visit.CancelledBy, visit.CancelReason, visit.CompletedAt = nil, "", "", nil
+	created, err := s.visitRepo.CreateIfAvailable(ctx, visit)
+	if err != nil {
+		return "", err
+	}
+	if !created {
+		return "", fmt.Errorf("%w: the slot was just taken", ErrVisitConflict)
+	}
+
+	_ = s.logActivity(ctx, visit.TenantID, "visit_scheduled", models.ActorTypeUser, actorID, visitLogMetadata(visit))
+
+	s.notify(ctx, visit, models.NotificationTemplateVisitConfirmed, recipient{Name: lead.Name, Phone: lead.Phone, Email: lead.Email}, "")
+
+	return token, nil
+}
+
+// ConfirmVisit confirms a requested visit and tells the lead
+func (s *VisitService) ConfirmVisit(ctx context.Context, tenantID, id, actorID string) (*models.Visit, error) {
+	visit, err := s.GetVisit(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if visit.Status != models.VisitStatusRequested {
+		return nil, fmt.Errorf("%w: visit is %s", repositories.ErrInvalidInput, visit.Status)
+	}
+	if visit.StartAt.Before(time.Now()) {
+		return nil, fmt.Errorf("%w: visit time has passed", repositories.ErrInvalidInput)
+	}
+
+	now := time.Now()
+	visit.Status = models.VisitStatusConfirmed
+	visit.ConfirmedAt = &now
+	if err := s.visitRepo.Update(ctx, tenantID, id, map[string]interface{}{
+		"status":       visit.Status,
+		"confirmed_at": visit.ConfirmedAt,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to update visit: %w", err)
+	}
+
+	_ = s.logActivity(ctx, tenantID, "visit_confirmed", models.ActorTypeUser, actorID, visitLogMetadata(visit))
+
+	if lead, err := s.leadService.activeLead(ctx, tenantID, visit.LeadID); err == nil {
+		s.notify(ctx, visit, models.NotificationTemplateVisitConfirmed, recipient{Name: lead.Name, Phone: lead.Phone, Email: lead.Email}, "")
+	}
+
+	return visit, nil
+}
+
+// CancelVisit cancels a requested or confirmed visit from the dashboard and
+// tells the lead
+func (s *VisitService) CancelVisit(ctx context.Context, tenantID, id, actorID, reason string) (*models.Visit, error) {
+	visit, err := s.GetVisit(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.cancel(ctx, visit, actorID, reason); err != nil {
+		return nil, err
+	}
+
+	_ = s.logActivity(ctx, tenantID, "visit_cancelled", models.ActorTypeUser, actorID, visitLogMetadata(visit))
+
+	if lead, err := s.leadService.activeLead(ctx, tenantID, visit.LeadID); err == nil {
+		s.notify(ctx, visit, models.NotificationTemplateVisitCancelled, recipient{Name: lead.Name, Phone: lead.Phone, Email: lead.Email}, reason)
+	}
+
+	return visit, nil
+}
+
+// CancelVisitByLead cancels a visit with the token given to the lead and
+// tells the broker. An unknown visit or wrong token is ErrNotFound.
+func (s *VisitService) CancelVisitByLead(ctx context.Context, tenantID, id, token, reason string) (*models.Visit, error) {
+	visit, err := s.visitRepo.Get(ctx, tenantID, id)
+	if err != nil {
+		return nil, fmt.Errorf("visit not found: %w", err)
+	}
+	if token == "" || !tokenMatches(visit.CancelTokenHash, token) {
+		return nil, fmt.Errorf("visit not found: %w", repositories.ErrNotFound)
+	}
+	if err := s.cancel(ctx, visit, models.VisitCancelledByLead, reason); err != nil {
+		return nil, err
+	}
+
+	_ = s.logActivity(ctx, tenantID, "visit_cancelled", models.ActorTypeLead, visit.LeadID, visitLogMetadata(visit))
+
+	if broker, err := s.brokerRepo.Get(ctx, tenantID, visit.BrokerID); err == nil {
+		s.notify(ctx, visit, models.NotificationTemplateVisitCancelled, recipient{Name: broker.Name, Phone: broker.Phone, Email: broker.Email}, reason)
+	}
+
+	return visit, nil
+}
+
+// cancel marks a visit that still holds its slot as cancelled
+func (s *VisitService) cancel(ctx context.Context, visit *models.Visit, by, reason string) error {
+	if !visit.HoldsSlot() {
+		return fmt.Errorf("%w: visit is %s", repositories.ErrInvalidInput, visit.Status)
+	}
+
+	now := time.Now()
+	visit.Status = models.VisitStatusCancelled
+	visit.CancelledAt = &now
+	visit.CancelledBy = by
+	visit.CancelReason = strings.TrimSpace(reason)
+	if err := s.visitRepo.Update(ctx, visit.TenantID, visit.ID, map[string]interface{}{
+		"status":        visit.Status,
+		"cancelled_at":  visit.CancelledAt,
+		"cancelled_by":  visit.CancelledBy,
+		"cancel_reason": visit.CancelReason,
+	}); err != nil {
+		return fmt.Errorf("failed to update visit: %w", err)
+	}
+	return nil
+}
+
+// CompleteVisit records the outcome of a confirmed visit after its start:
+// completed (logged as a visit interaction on the lead) or no_show
+func (s *VisitService) CompleteVisit(ctx context.Context, tenantID, id string, status models.VisitStatus, actorID string) (*models.Visit, error) {
+	if status != models.VisitStatusCompleted && status != models.VisitStatusNoShow {
+		return nil, fmt.Errorf("%w: status must be 'completed' or 'no_show'", repositories.ErrInvalidInput)
+	}
+
+	visit, err := s.GetVisit(ctx, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	if visit.Status != models.VisitStatusConfirmed {
+		return nil, fmt.Errorf("%w: visit is %s", repositories.ErrInvalidInput, visit.Status)
+	}
+	if visit.StartAt.After(time.Now()) {
+		return nil, fmt.Errorf("%w: visit has not started yet", repositories.ErrInvalidInput)
+	}
+
+	now := time.Now()
+	visit.Status = status
+	visit.CompletedAt = &now
+	if err := s.visitRepo.Update(ctx, tenantID, id, map[string]interface{}{
+		"status":       visit.Status,
+		"completed_at": visit.CompletedAt,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to update visit: %w", err)
+	}
+
+	_ = s.logActivity(ctx, tenantID, "visit_"+string(status), models.ActorTypeUser, actorID, visitLogMetadata(visit))
+
+	if status == models.VisitStatusCompleted && s.leadService.interactionRepo != nil {
+		interaction := &models.LeadInteraction{
+			TenantID:        tenantID,
+			LeadID:          visit.LeadID,
+			Type:            models.LeadInteractionVisit,
+			Direction:       models.LeadInteractionOutbound,
+			BrokerID:        visit.BrokerID,
+			OccurredAt:      visit.StartAt,
+			DurationMinutes: int(visit.EndAt.Sub(visit.StartAt).Minutes()),
+			Outcome:         "visit_completed",
+			Summary:         visit.Notes,
+		}
+		if err := s.leadService.LogInteraction(ctx, interaction); err != nil {
+			log.Printf("⚠️  Failed to log visit %s on lead %s: %v", visit.ID, visit.LeadID, err)
+		}
+	}
+
+	return visit, nil
+}
+
+// GetVisit retrieves a visit by ID
+func (s *VisitService) GetVisit(ctx context.Context, tenantID, id string) (*models.Visit, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+
+	visit, err := s.visitRepo.Get(ctx, tenantID, id)
+	if err != nil {
+		return nil, fmt.Errorf("visit not found: %w", err)
+	}
+	return visit, nil
+}
+
+// ListVisits lists visits with filters, earliest first
+func (s *VisitService) ListVisits(ctx context.Context, tenantID string, filters *repositories.VisitFilters, opts repositories.PaginationOptions) ([]*models.Visit, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+
+	visits, err := s.visitRepo.List(ctx, tenantID, filters, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list visits: %w", err)
+	}
+	return visits, nil
+}
+
+// ============================================================================
+// Calendar feed
+// ============================================================================
+
+// RotateCalendarToken issues a new secret for the .ics feed of a broker. The
+// previous feed URL stops working.
+func (s *VisitService) RotateCalendarToken(ctx context.Context, tenantID, brokerID, actorID string) (string, error) {
+	if _, err := s.broker(ctx, tenantID, brokerID); err != nil {
+		return "", err
+	}
+
+	token, tokenHash, err := newVisitToken()
+	if err != nil {
+		return "", err
+	}
+	if err := s.brokerRepo.Update(ctx, tenantID, brokerID, map[string]interface{}{"calendar_token_hash": tokenHash}); err != nil {
+		return "", fmt.Errorf("failed to update broker: %w", err)
+	}
+
+	_ = s.logActivity(ctx, tenantID, "broker_calendar_token_rotated", models.ActorTypeUser, actorID, map[string]interface{}{
+		"broker_id": brokerID,
+	})
+
+	return token, nil
+}
+
+// BrokerCalendar renders the iCalendar feed of a broker's visits, from 30 days
+// ago to 180 days ahead. An unknown broker or wrong token is ErrNotFound.
+func (s *VisitService) BrokerCalendar(ctx context.Context, tenantID, brokerID, token string) ([]byte, error) {
+	broker, err := s.brokerRepo.Get(ctx, tenantID, brokerID)
+	if err != nil {
+		return nil, fmt.Errorf("broker not found: %w", err)
+	}
+	if token == "" || !tokenMatches(broker.CalendarTokenHash, token) {
+		return nil, fmt.Errorf("broker not found: %w", repositories.ErrNotFound)
+	}
+
+	now := time.Now()
+	from, until := now.Add(-visitCalendarPast), now.Add(visitCalendarAhead)
+	visits, err := s.visitRepo.List(ctx, tenantID, &repositories.VisitFilters{
+		BrokerID:    brokerID,
+		StartFrom:   &from,
+		StartBefore: &until,
+	}, repositories.PaginationOptions{Limit: brokerVisitsLimit})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list visits: %w", err)
+	}
+
+	calendar := &ical.Calendar{
+		ProdID: "-//Ecosistema Imob//Visitas//PT-BR",
+		Name:   "Visitas - " + broker.Name,
+	}
+	properties := map[string]*models.Property{}
+	for _, visit := range visits {
+		property, ok := properties[visit.PropertyID]
+		if !ok {
+			property, _ = s.propertyRepo.Get(ctx, tenantID, visit.PropertyID)
+			properties[visit.PropertyID] = property
+		}
+		lead, _ := s.leadService.leadRepo.Get(ctx, tenantID, visit.LeadID)
+		calendar.Events = append(calendar.Events, visitEvent(visit, property, lead))
+	}
+
+	return calendar.Encode(now), nil
+}
+
+// visitEvent builds the calendar event of a visit
+func visitEvent(visit *models.Visit, property *models.Property, lead *models.Lead) ical.Event {
+	event := ical.Event{
+		UID:          visit.ID + "@" + visit.TenantID + ".visits",
+		Start:        visit.StartAt,
+		End:          visit.EndAt,
+		LastModified: visit.UpdatedAt,
+	}
+
+	reference := visit.PropertyID
+	if property != nil {
+		reference = propertyReference(property)
+		location := []string{}
+		if address := propertyAddress(property); address != "" {
+			location = append(location, address)
+		}
+		if property.City != "" {
+			location = append(location, property.City)
+		}
+		event.Location = strings.Join(location, ", ")
+	}
+
+	var description []string
+	event.Summary = "Visita: " + reference
+	if lead != nil && !lead.IsAnonymized {
+		event.Summary += " - " + lead.Name
+		description = append(description, "Lead: "+lead.Name)
+		if lead.Phone != "" {
+			description = append(description, "Telefone: "+lead.Phone)
+		}
+		if lead.Email != "" {
+			description = append(description, "E-mail: "+lead.Email)
+		}
+	}
+	if visit.Notes != "" {
+		description = append(description, visit.Notes)
+	}
+	event.Description = strings.Join(description, "\n")
+
+	// Sequence increases with each status change so calendar apps update the event
+	switch visit.Status {
+	case models.VisitStatusRequested:
+		event.Status = ical.StatusTentative
+	case models.VisitStatusCancelled:
+		event.Status = ical.StatusCancelled
+		event.Sequence = 2
+	default:
+		event.Status = ical.StatusConfirmed
+		event.Sequence = 1
+	}
+	return event
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+// broker returns a broker of the tenant
+func (s *VisitService) broker(ctx context.Context, tenantID, brokerID string) (*models.Broker, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenant_id is required")
+	}
+
+	broker, err := s.brokerRepo.Get(ctx, tenantID, brokerID)
+	if err != nil {
+		return nil, fmt.Errorf("broker not found: %w", err)
+	}
+	return broker, nil
+}
+
+// activeBroker returns a broker that can take visits
+func (s *VisitService) activeBroker(ctx context.Context, tenantID, brokerID string) (*models.Broker, error) {
+	broker, err := s.broker(ctx, tenantID, brokerID)
+	if err != nil {
+		return nil, err
+	}
+	if !broker.IsActive {
+		return nil, fmt.Errorf("%w: broker %s is not active", repositories.ErrInvalidInput, brokerID)
+	}
+	return broker, nil
+}
+
+// propertyBroker returns a property and the broker that shows it: its primary
+// broker, or its captador
+func (s *VisitService) propertyBroker(ctx context.Context, tenantID, propertyID string) (*models.Property, *models.Broker, error) {
+	if tenantID == "" {
+		return nil, nil, fmt.Errorf("tenant_id is required")
+	}
+
+	property, err := s.propertyRepo.Get(ctx, tenantID, propertyID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("property not found: %w", err)
+	}
+
+	brokerID := property.CaptadorID
+	if role, err := s.roleRepo.GetPrimaryBroker(ctx, tenantID, propertyID); err == nil && role != nil {
+		brokerID = role.BrokerID
+	}
+	if brokerID == "" {
+		return nil, nil, fmt.Errorf("%w: property has no broker to show it", repositories.ErrInvalidInput)
+	}
+
+	broker, err := s.activeBroker(ctx, tenantID, brokerID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return property, broker, nil
+}
+
+// brokerVisits returns the visits holding the broker's time that overlap [from, until)
+func (s *VisitService) brokerVisits(ctx context.Context, tenantID, brokerID string, from, until time.Time) ([]*models.Visit, error) {
+	startFrom := from.Add(-models.MaxVisitMinutes * time.Minute)
+	visits, err := s.visitRepo.List(ctx, tenantID, &repositories.VisitFilters{
+		BrokerID:    brokerID,
+		Statuses:    []models.VisitStatus{models.VisitStatusRequested, models.VisitStatusConfirmed},
+		StartFrom:   &startFrom,
+		StartBefore: &until,
+	}, repositories.PaginationOptions{Limit: brokerVisitsLimit})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list broker visits: %w", err)
+	}
+	return visits, nil
+}
+
+// checkSchedule verifies that no other visit of the broker overlaps [start, end)
+func (s *VisitService) checkSchedule(ctx context.Context, broker *models.Broker, start, end time.Time) error {
+	busy, err := s.brokerVisits(ctx, broker.TenantID, broker.ID, start, end)
+	if err != nil {
+		return err
+	}
+	if overlapsAny(busy, start, end) {
+		return fmt.Errorf("%w: the broker has another visit at this time", ErrVisitConflict)
+	}
+	return nil
+}
+
+// overlapsAny reports whether a visit holding its slot overlaps [start, end)
+func overlapsAny(visits []*models.Visit, start, end time.Time) bool {
+	for _, visit := range visits {
+		if visit.HoldsSlot() && visit.Overlaps(start, end) {
+			return true
+		}
+	}
+	return false
+}
+
+// VisitMessageData is the data available to the visit templates
+type VisitMessageData struct {
+	RecipientName     string
+	TenantName        string
+	LeadName          string
+	BrokerName        string
+	PropertyReference string
+	PropertyAddress   string
+	StartAt           string // dd/mm/yyyy hh:mm, broker's time zone
+	Notes             string
+	Reason            string // Cancellation reason
+}
+
+// notify sends a visit message; failures are logged, never returned
+func (s *VisitService) notify(ctx context.Context, visit *models.Visit, name string, to recipient, reason string) {
+	if s.notifier == nil {
+		return
+	}
+
+	tenant, err := s.tenantRepo.Get(ctx, visit.TenantID)
+	if err != nil {
+		log.Printf("⚠️  Failed to load tenant %s for visit %s: %v", visit.TenantID, visit.ID, err)
+		return
+	}
+
+	data := VisitMessageData{
+		RecipientName: firstName(to.Name),
+		TenantName:    tenant.Name,
+		Notes:         visit.Notes,
+		Reason:        reason,
+	}
+	location := models.DefaultBrokerAvailability().Location()
+	if broker, err := s.brokerRepo.Get(ctx, visit.TenantID, visit.BrokerID); err == nil {
+		data.BrokerName = broker.Name
+		location = broker.EffectiveAvailability().Location()
+	}
+	data.StartAt = visit.StartAt.In(location).Format("02/01/2006 15:04")
+	data.PropertyReference = visit.PropertyID
+	if property, err := s.propertyRepo.Get(ctx, visit.TenantID, visit.PropertyID); err == nil {
+		data.PropertyReference = propertyReference(property)
+		data.PropertyAddress = propertyAddress(property)
+	}
+	if lead, err := s.leadService.leadRepo.Get(ctx, visit.TenantID, visit.LeadID); err == nil {
+		data.LeadName = lead.Name
+	}
+
+	_, _, _, err = sendNotification(ctx, s.notifier, tenant, name, to, data,
+		[]string{data.RecipientName, data.PropertyReference, data.StartAt})
+	if err != nil {
+		log.Printf("⚠️  Failed to send %s of visit %s: %v", name, visit.ID, err)
+	}
+}
+
+// visitLogMetadata returns the activity log metadata of a visit
+func visitLogMetadata(visit *models.Visit) map[string]interface{} {
+	return map[string]interface{}{
+		"visit_id":    visit.ID,
+		"lead_id":     visit.LeadID,
+		"property_id": visit.PropertyID,
+		"broker_id":   visit.BrokerID,
+		"start_at":    visit.StartAt,
+		"status":      visit.Status,
+	}
+}
+
+// newVisitToken generates a random URL-safe token and its SHA-256 hash
+func newVisitToken() (string, string, error) {
+	tokenBytes := make([]byte, 32)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return "", "", fmt.Errorf("failed to generate random token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(tokenBytes)
+	return token, hashToken(token), nil
+}
+
+// hashToken returns the hex SHA-256 of a token
+func hashToken(token string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(token)))
+}
+
+// tokenMatches compares a token with a stored hash in constant time
+func tokenMatches(tokenHash, token string) bool {
+	return tokenHash != "" && subtle.ConstantTimeCompare([]byte(tokenHash), []byte(hashToken(token))) == 1
+}
+
+// logActivity logs an activity
+func (s *VisitService) logActivity(ctx context.Context, tenantID, eventType string, actorType models.ActorType, actorID string, metadata map[string]interface{}) error {
+	log := &models.ActivityLog{
+		TenantID:  tenantID,
+		EventType: eventType,
+		ActorType: actorType,
+		ActorID:   actorID,
+		Metadata:  metadata,
+		Timestamp: time.Now(),
+	}
+
+	return s.activityLogRepo.Create(ctx, log)
+}
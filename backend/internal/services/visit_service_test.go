@@ -0,0 +1,322 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/altatech/ecosistema-imob/backend/internal/models"
+	"github.com/altatech/ecosistema-imob/backend/internal/notify"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories"
+	"github.com/altatech/ecosistema-imob/backend/internal/repositories/memory"
+)
+
+// newVisitTestService returns a visit service over the routing test data:
+// property "moema" (available) is shown by broker-3 ("Paulo Souza")
+func newVisitTestService(t *testing.T) (*VisitService, *notify.FakeNotifier) {
+	t.Helper()
+
+	leadService, whatsapp := newActivityTestService(t)
+	require.NoError(t, leadService.propertyRepo.Update(context.Background(), "tenant-1", "moema", map[string]interface{}{
+		"status":    models.PropertyStatusAvailable,
+		"reference": "AP00335",
+	}))
+
+	service := NewVisitService(
+		memory.NewVisitRepository(),
+		leadService,
+		leadService.propertyRepo,
+		leadService.roleRepo,
+		leadService.brokerRepo,
+		leadService.tenantRepo,
+		leadService.activityLogRepo,
+	)
+	service.SetNotifier(notify.NewDispatcher(notify.RetryPolicy{MaxAttempts: 1}, whatsapp))
+	return service, whatsapp
+}
+
+// nextMonday returns the given hour (São Paulo) of a Monday at least two days ahead
+func nextMonday(hour int) time.Time {
+	location := models.DefaultBrokerAvailability().Location()
+	day := time.Now().In(location).AddDate(0, 0, 2)
+	for day.Weekday() != time.Monday {
+		day = day.AddDate(0, 0, 1)
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), hour, 0, 0, 0, location)
+}
+
+func visitTestLead(name, phone string) *models.Lead {
+	return &models.Lead{
+		TenantID:     "tenant-1",
+		PropertyID:   "moema",
+		Name:         name,
+		Phone:        phone,
+		Message:      "Posso levar meu arquiteto?",
+		Channel:      models.LeadChannelForm,
+		ConsentGiven: true,
+	}
+}
+
+func TestRequestVisit_CreatesLeadAndNotifiesBroker(t *testing.T) {
+	ctx := context.Background()
+	service, whatsapp := newVisitTestService(t)
+	startAt := nextMonday(10)
+
+	lead := visitTestLead("Ana Costa", "(11) 98888-0000")
+	visit, token, err := service.RequestVisit(ctx, lead, startAt)
+	require.NoError(t, err)
+	assert.NotEmpty(t, token)
+	assert.NotEqual(t, token, visit.CancelTokenHash)
+
+	assert.NotEmpty(t, lead.ID)
+	assert.Equal(t, "broker-3", lead.AssignedBrokerID)
+	assert.Equal(t, models.LeadChannelForm, lead.Channel)
+
+	assert.Equal(t, models.VisitStatusRequested, visit.Status)
+	assert.Equal(t, models.VisitSourcePublic, visit.Source)
+	assert.Equal(t, lead.ID, visit.LeadID)
+	assert.Equal(t, "broker-3", visit.BrokerID)
+	assert.Equal(t, time.Hour, visit.EndAt.Sub(visit.StartAt))
+
+	sent := whatsapp.Sent()
+	require.Len(t, sent, 1)
+	assert.Equal(t, "(11) 97777-1234", sent[0].To)
+	assert.Contains(t, sent[0].Body, "Ana Costa")
+	assert.Contains(t, sent[0].Body, "AP00335")
+	assert.Contains(t, sent[0].Body, startAt.Format("02/01/2006 15:04"))
+
+	// The slot is taken, also for an overlapping request
+	_, _, err = service.RequestVisit(ctx, visitTestLead("Bruno Lima", "(11) 96666-0000"), startAt.Add(30*time.Minute))
+	assert.True(t, errors.Is(err, ErrVisitConflict))
+
+	slots, err := service.AvailableSlots(ctx, "tenant-1", "moema", startAt.Add(-time.Hour), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "broker-3", slots.BrokerID)
+	for _, slot := range slots.Slots {
+		assert.False(t, slot.StartAt.Equal(startAt), "booked slot offered")
+	}
+	assert.Len(t, slots.Slots, 8)
+}
+
+func TestRequestVisit_Validation(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newVisitTestService(t)
+
+	// Outside the broker's hours (Sunday)
+	_, _, err := service.RequestVisit(ctx, visitTestLead("Ana Costa", "(11) 98888-0000"), nextMonday(10).AddDate(0, 0, -1))
+	assert.True(t, errors.Is(err, ErrVisitConflict))
+
+	// Too soon
+	_, _, err = service.RequestVisit(ctx, visitTestLead("Ana Costa", "(11) 98888-0000"), time.Now().Add(time.Hour))
+	assert.True(t, errors.Is(err, repositories.ErrInvalidInput))
+
+	// Property without a broker
+	lead := visitTestLead("Ana Costa", "(11) 98888-0000")
+	lead.PropertyID = "centro"
+	_, _, err = service.RequestVisit(ctx, lead, nextMonday(10))
+	assert.True(t, errors.Is(err, repositories.ErrInvalidInput))
+
+	// Rejected requests create no lead
+	leads, err := service.leadService.ListLeads(ctx, "tenant-1", nil, repositories.PaginationOptions{Limit: 10})
+	require.NoError(t, err)
+	assert.Empty(t, leads)
+}
+
+func TestRequestVisit_ConcurrentRequestsHoldTheSlotOnce(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newVisitTestService(t)
+	startAt := nextMonday(10)
+
+	const requests = 5
+	errs := make(chan error, requests)
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, _, err := service.RequestVisit(ctx, visitTestLead("Ana Costa", fmt.Sprintf("(11) 98888-000%d", i)), startAt)
+			errs <- err
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	booked := 0
+	for err := range errs {
+		if err == nil {
+			booked++
+			continue
+		}
+		assert.True(t, errors.Is(err, ErrVisitConflict), err)
+	}
+	assert.Equal(t, 1, booked)
+
+	// Losing requests leave no lead behind
+	leads, err := service.leadService.ListLeads(ctx, "tenant-1", nil, repositories.PaginationOptions{Limit: 10})
+	require.NoError(t, err)
+	assert.Len(t, leads, 1)
+}
+
+func TestRequestVisit_ReleasesSlotWhenTheLeadFails(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newVisitTestService(t)
+	startAt := nextMonday(10)
+
+	// Leads need a valid contact
+	_, _, err := service.RequestVisit(ctx, visitTestLead("Ana Costa", "123"), startAt)
+	require.Error(t, err)
+
+	visits, err := service.ListVisits(ctx, "tenant-1", nil, repositories.PaginationOptions{})
+	require.NoError(t, err)
+	require.Len(t, visits, 1)
+	assert.Equal(t, models.VisitStatusCancelled, visits[0].Status)
+	assert.Empty(t, visits[0].LeadID)
+
+	_, _, err = service.RequestVisit(ctx, visitTestLead("Ana Costa", "(11) 98888-0000"), startAt)
+	require.NoError(t, err)
+}
+
+func TestVisit_ConfirmAndCancelByLead(t *testing.T) {
+	ctx := context.Background()
+	service, whatsapp := newVisitTestService(t)
+	startAt := nextMonday(11)
+
+	visit, token, err := service.RequestVisit(ctx, visitTestLead("Ana Costa", "(11) 98888-0000"), startAt)
+	require.NoError(t, err)
+
+	confirmed, err := service.ConfirmVisit(ctx, "tenant-1", visit.ID, "broker-3")
+	require.NoError(t, err)
+	assert.Equal(t, models.VisitStatusConfirmed, confirmed.Status)
+	assert.NotNil(t, confirmed.ConfirmedAt)
+
+	_, err = service.ConfirmVisit(ctx, "tenant-1", visit.ID, "broker-3")
+	assert.True(t, errors.Is(err, repositories.ErrInvalidInput))
+
+	sent := whatsapp.Sent()
+	require.Len(t, sent, 2)
+	assert.Equal(t, "(11) 98888-0000", sent[1].To)
+	assert.Contains(t, sent[1].Body, "Paulo Souza")
+
+	// Wrong token looks like an unknown visit
+	_, err = service.CancelVisitByLead(ctx, "tenant-1", visit.ID, "wrong", "")
+	assert.True(t, errors.Is(err, repositories.ErrNotFound))
+
+	cancelled, err := service.CancelVisitByLead(ctx, "tenant-1", visit.ID, token, "Imprevisto")
+	require.NoError(t, err)
+	assert.Equal(t, models.VisitStatusCancelled, cancelled.Status)
+	assert.Equal(t, models.VisitCancelledByLead, cancelled.CancelledBy)
+	assert.Equal(t, "Imprevisto", cancelled.CancelReason)
+
+	sent = whatsapp.Sent()
+	require.Len(t, sent, 3)
+	assert.Equal(t, "(11) 97777-1234", sent[2].To)
+	assert.Contains(t, sent[2].Body, "Imprevisto")
+
+	// The slot is free again
+	_, _, err = service.RequestVisit(ctx, visitTestLead("Bruno Lima", "(11) 96666-0000"), startAt)
+	assert.NoError(t, err)
+}
+
+func TestScheduleVisit_ChecksTimeOffAndCompletes(t *testing.T) {
+	ctx := context.Background()
+	service, whatsapp := newVisitTestService(t)
+	lead := visitTestLead("Ana Costa", "(11) 98888-0000")
+	require.NoError(t, service.leadService.CreateLead(ctx, lead))
+	sunday := nextMonday(10).AddDate(0, 0, -1)
+
+	availability := models.DefaultBrokerAvailability()
+	availability.Blocks = []models.AvailabilityBlock{{StartAt: sunday.AddDate(0, 0, 1), EndAt: sunday.AddDate(0, 0, 2), Reason: "Curso"}}
+	_, err := service.SetBrokerAvailability(ctx, "tenant-1", "broker-3", availability, "admin-1")
+	require.NoError(t, err)
+
+	// Time off is enforced
+	_, err = service.ScheduleVisit(ctx, &models.Visit{TenantID: "tenant-1", LeadID: lead.ID, StartAt: sunday.AddDate(0, 0, 1).Add(10 * time.Hour)}, "broker-3")
+	assert.True(t, errors.Is(err, ErrVisitConflict))
+
+	// Weekly hours are not: the broker schedules a Sunday visit
+	visit := &models.Visit{TenantID: "tenant-1", LeadID: lead.ID, StartAt: sunday, Notes: "Levar chaves"}
+	token, err := service.ScheduleVisit(ctx, visit, "broker-3")
+	require.NoError(t, err)
+	assert.NotEmpty(t, token)
+	assert.Equal(t, models.VisitStatusConfirmed, visit.Status)
+	assert.Equal(t, models.VisitSourceAdmin, visit.Source)
+	assert.Equal(t, "moema", visit.PropertyID)
+	assert.Equal(t, "broker-3", visit.BrokerID)
+	assert.Equal(t, time.Hour, visit.EndAt.Sub(visit.StartAt))
+
+	// Not started yet
+	_, err = service.CompleteVisit(ctx, "tenant-1", visit.ID, models.VisitStatusCompleted, "broker-3")
+	assert.True(t, errors.Is(err, repositories.ErrInvalidInput))
+
+	// A visit that took place is logged on the lead and settles its SLA
+	past := &models.Visit{
+		TenantID:   "tenant-1",
+		PropertyID: "moema",
+		LeadID:     lead.ID,
+		BrokerID:   "broker-3",
+		Status:     models.VisitStatusConfirmed,
+		StartAt:    time.Now().Add(-2 * time.Hour),
+		EndAt:      time.Now().Add(-time.Hour),
+	}
+	require.NoError(t, service.visitRepo.Create(ctx, past))
+	completed, err := service.CompleteVisit(ctx, "tenant-1", past.ID, models.VisitStatusCompleted, "broker-3")
+	require.NoError(t, err)
+	assert.Equal(t, models.VisitStatusCompleted, completed.Status)
+	assert.NotNil(t, completed.CompletedAt)
+
+	interactions, err := service.leadService.ListInteractions(ctx, "tenant-1", lead.ID, repositories.PaginationOptions{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, interactions, 1)
+	assert.Equal(t, models.LeadInteractionVisit, interactions[0].Type)
+	assert.Equal(t, 60, interactions[0].DurationMinutes)
+
+	updated, err := service.leadService.GetLead(ctx, "tenant-1", lead.ID)
+	require.NoError(t, err)
+	assert.NotNil(t, updated.FirstResponseAt)
+
+	// Only the scheduled visit was sent to the lead
+	sent := whatsapp.Sent()
+	require.Len(t, sent, 1)
+	assert.Equal(t, "(11) 98888-0000", sent[0].To)
+	assert.Contains(t, sent[0].Body, "Paulo Souza")
+}
+
+func TestBrokerCalendar(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newVisitTestService(t)
+
+	_, _, err := service.RequestVisit(ctx, visitTestLead("Ana Costa", "(11) 98888-0000"), nextMonday(10))
+	require.NoError(t, err)
+
+	// No token issued yet
+	_, err = service.BrokerCalendar(ctx, "tenant-1", "broker-3", "")
+	assert.True(t, errors.Is(err, repositories.ErrNotFound))
+
+	token, err := service.RotateCalendarToken(ctx, "tenant-1", "broker-3", "broker-3")
+	require.NoError(t, err)
+
+	_, err = service.BrokerCalendar(ctx, "tenant-1", "broker-3", "wrong")
+	assert.True(t, errors.Is(err, repositories.ErrNotFound))
+
+	calendar, err := service.BrokerCalendar(ctx, "tenant-1", "broker-3", token)
+	require.NoError(t, err)
+	body := string(calendar)
+	assert.True(t, strings.HasPrefix(body, "BEGIN:VCALENDAR\r\n"))
+	assert.Equal(t, 1, strings.Count(body, "BEGIN:VEVENT"))
+	assert.Contains(t, body, "SUMMARY:Visita: AP00335 - Ana Costa")
+	assert.Contains(t, body, "STATUS:TENTATIVE")
+	assert.Contains(t, body, "Moema")
+
+	// A new token revokes the previous feed URL
+	_, err = service.RotateCalendarToken(ctx, "tenant-1", "broker-3", "broker-3")
+	require.NoError(t, err)
+	_, err = service.BrokerCalendar(ctx, "tenant-1", "broker-3", token)
+	assert.True(t, errors.Is(err, repositories.ErrNotFound))
+}
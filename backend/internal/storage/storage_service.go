@@ -59,14 +59,14 @@ type ImageMetadata struct {
 type StorageService struct {
 	storageClient   *storage.Client
 	bucketName      string
-	activityLogRepo *repositories.ActivityLogRepository
+	activityLogRepo repositories.ActivityLogStore
 }
 
 // NewStorageService creates a new storage service
 func NewStorageService(
 	ctx context.Context,
 	bucketName string,
-	activityLogRepo *repositories.ActivityLogRepository,
+	activityLogRepo repositories.ActivityLogStore,
 ) (*StorageService, error) {
 	// Initialize GCS client directly
 	client, err := storage.NewClient(ctx)
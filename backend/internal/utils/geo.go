@@ -0,0 +1,230 @@
+package utils
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ============================================================================
+// Geolocation (coordinates, geohash, distances)
+// ============================================================================
+
+// EarthRadiusKm is the mean Earth radius used for distance calculations
+const EarthRadiusKm = 6371.0
+
+// DefaultGeohashPrecision is the geohash length stored on properties (cells of ~4.8m x 4.8m)
+const DefaultGeohashPrecision = 9
+
+// MaxBoundsSpanDegrees is the largest latitude or longitude span of a
+// geographic search (~550 km). Larger boxes would read most of the properties.
+const MaxBoundsSpanDegrees = 5.0
+
+const geohashBase32 = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// GeoBounds is a latitude/longitude bounding box (south-west to north-east corner)
+type GeoBounds struct {
+	MinLat float64 `json:"min_lat"`
+	MinLng float64 `json:"min_lng"`
+	MaxLat float64 `json:"max_lat"`
+	MaxLng float64 `json:"max_lng"`
+}
+
+// GeohashRange is an inclusive [Start, End] range of geohash strings
+type GeohashRange struct {
+	Start string
+	End   string
+}
+
+// ValidateCoordinates validates a latitude/longitude pair
+func ValidateCoordinates(lat, lng float64) error {
+	if math.IsNaN(lat) || math.IsNaN(lng) {
+		return errors.New("coordenadas inválidas")
+	}
+	if lat < -90 || lat > 90 {
+		return errors.New("latitude deve estar entre -90 e 90")
+	}
+	if lng < -180 || lng > 180 {
+		return errors.New("longitude deve estar entre -180 e 180")
+	}
+	return nil
+}
+
+// ParseCoordinate parses a coordinate as found in feeds ("-23.5505", "-23,5505").
+// Returns ok=false for empty or malformed values.
+func ParseCoordinate(value string) (float64, bool) {
+	value = strings.TrimSpace(strings.ReplaceAll(value, ",", "."))
+	if value == "" {
+		return 0, false
+	}
+
+	coord, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(coord) || math.IsInf(coord, 0) {
+		return 0, false
+	}
+	return coord, true
+}
+
+// EncodeGeohash encodes a coordinate into a geohash of the given precision
+func EncodeGeohash(lat, lng float64, precision int) string {
+	if precision <= 0 {
+		precision = DefaultGeohashPrecision
+	}
+
+	minLat, maxLat := -90.0, 90.0
+	minLng, maxLng := -180.0, 180.0
+
+	var hash strings.Builder
+	bit, ch := 0, 0
+	evenBit := true
+
+	for hash.Len() < precision {
+		if evenBit {
+			mid := (minLng + maxLng) / 2
+			if lng >= mid {
+				ch = ch<<1 | 1
+				minLng = mid
+			} else {
+				ch = ch << 1
+				maxLng = mid
+			}
+		} else {
+			mid := (minLat + maxLat) / 2
+			if lat >= mid {
+				ch = ch<<1 | 1
+				minLat = mid
+			} else {
+				ch = ch << 1
+				maxLat = mid
+			}
+		}
+		evenBit = !evenBit
+
+		bit++
+		if bit == 5 {
+			hash.WriteByte(geohashBase32[ch])
+			bit, ch = 0, 0
+		}
+	}
+
+	return hash.String()
+}
+
+// HaversineKm returns the great-circle distance in km between two coordinates
+func HaversineKm(lat1, lng1, lat2, lng2 float64) float64 {
+	dLat := toRadians(lat2 - lat1)
+	dLng := toRadians(lng2 - lng1)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRadians(lat1))*math.Cos(toRadians(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+
+	return EarthRadiusKm * 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
+
+// BoundsAroundPoint returns the bounding box enclosing a circle of radiusKm around a point
+func BoundsAroundPoint(lat, lng, radiusKm float64) GeoBounds {
+	latDelta := radiusKm / EarthRadiusKm * 180 / math.Pi
+
+	// Near the poles a longitude degree shrinks to nothing; cover every longitude
+	lngDelta := 180.0
+	if cosLat := math.Cos(toRadians(lat)); cosLat > 1e-9 {
+		lngDelta = math.Min(180, latDelta/cosLat)
+	}
+
+	return GeoBounds{
+		MinLat: math.Max(-90, lat-latDelta),
+		MinLng: math.Max(-180, lng-lngDelta),
+		MaxLat: math.Min(90, lat+latDelta),
+		MaxLng: math.Min(180, lng+lngDelta),
+	}
+}
+
+// Validate validates that the bounding box is well formed and spans at most
+// MaxBoundsSpanDegrees in each direction
+func (b GeoBounds) Validate() error {
+	if err := ValidateCoordinates(b.MinLat, b.MinLng); err != nil {
+		return err
+	}
+	if err := ValidateCoordinates(b.MaxLat, b.MaxLng); err != nil {
+		return err
+	}
+	if b.MinLat > b.MaxLat || b.MinLng > b.MaxLng {
+		return errors.New("bounding box inválido: mínimo maior que máximo")
+	}
+	if b.MaxLat-b.MinLat > MaxBoundsSpanDegrees || b.MaxLng-b.MinLng > MaxBoundsSpanDegrees {
+		return errors.New("bounding box muito grande: aproxime o mapa")
+	}
+	return nil
+}
+
+// Contains reports whether the coordinate lies inside the bounding box
+func (b GeoBounds) Contains(lat, lng float64) bool {
+	return lat >= b.MinLat && lat <= b.MaxLat && lng >= b.MinLng && lng <= b.MaxLng
+}
+
+// GeohashRanges returns the geohash ranges that together cover the bounding box.
+// A range query on a stored geohash for each range returns a superset of the
+// coordinates inside the box; callers must still filter with Contains.
+// At most four ranges are returned. A box too large for any geohash prefix
+// yields a single range covering everything; Validate rejects such boxes.
+func (b GeoBounds) GeohashRanges() []GeohashRange {
+	precision := b.coveringPrecision()
+	if precision == 0 {
+		return []GeohashRange{{Start: "", End: "~"}}
+	}
+
+	latBits, lngBits := geohashBits(precision)
+	cellHeight := 180 / math.Pow(2, float64(latBits))
+	cellWidth := 360 / math.Pow(2, float64(lngBits))
+
+	// The box is never larger than a cell, so it spans at most 2 x 2 cells
+	seen := make(map[string]bool)
+	for _, lat := range []float64{b.MinLat, math.Min(b.MinLat+cellHeight, b.MaxLat), b.MaxLat} {
+		for _, lng := range []float64{b.MinLng, math.Min(b.MinLng+cellWidth, b.MaxLng), b.MaxLng} {
+			seen[EncodeGeohash(lat, lng, precision)] = true
+		}
+	}
+
+	hashes := make([]string, 0, len(seen))
+	for hash := range seen {
+		hashes = append(hashes, hash)
+	}
+	sort.Strings(hashes)
+
+	ranges := make([]GeohashRange, 0, len(hashes))
+	for _, hash := range hashes {
+		ranges = append(ranges, GeohashRange{Start: hash, End: hash + "~"})
+	}
+	return ranges
+}
+
+// coveringPrecision returns the longest geohash precision whose cells are at
+// least as large as the bounding box in both dimensions
+func (b GeoBounds) coveringPrecision() int {
+	height := b.MaxLat - b.MinLat
+	width := b.MaxLng - b.MinLng
+
+	precision := 0
+	for p := 1; p <= DefaultGeohashPrecision; p++ {
+		latBits, lngBits := geohashBits(p)
+		if 180/math.Pow(2, float64(latBits)) < height || 360/math.Pow(2, float64(lngBits)) < width {
+			break
+		}
+		precision = p
+	}
+	return precision
+}
+
+// geohashBits returns how many of the 5*precision bits encode latitude and longitude
+func geohashBits(precision int) (latBits, lngBits int) {
+	total := precision * 5
+	lngBits = (total + 1) / 2
+	latBits = total / 2
+	return latBits, lngBits
+}
+
+func toRadians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
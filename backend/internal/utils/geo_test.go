@@ -0,0 +1,125 @@
+package utils
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestEncodeGeohash(t *testing.T) {
+	tests := []struct {
+		name      string
+		lat, lng  float64
+		precision int
+		want      string
+	}{
+		{"Known reference point", 57.64911, 10.40744, 11, "u4pruydqqvj"},
+		{"São Paulo", -23.5505, -46.6333, 6, "6gyf4b"},
+		{"Default precision", -23.5505, -46.6333, 0, "6gyf4bf8m"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EncodeGeohash(tt.lat, tt.lng, tt.precision); got != tt.want {
+				t.Errorf("EncodeGeohash(%v, %v, %d) = %q, want %q", tt.lat, tt.lng, tt.precision, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCoordinate(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  string
+		want   float64
+		wantOK bool
+	}{
+		{"Dot decimal", "-23.5505", -23.5505, true},
+		{"Comma decimal", "-23,5505", -23.5505, true},
+		{"Whitespace", "  -46.6333 ", -46.6333, true},
+		{"Empty", "", 0, false},
+		{"Garbage", "abc", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseCoordinate(tt.value)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("ParseCoordinate(%q) = %v, %v, want %v, %v", tt.value, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestHaversineKm(t *testing.T) {
+	// São Paulo (Praça da Sé) -> Rio de Janeiro (Cinelândia) ≈ 357 km
+	got := HaversineKm(-23.5505, -46.6333, -22.9111, -43.1763)
+	if math.Abs(got-357) > 5 {
+		t.Errorf("HaversineKm() = %.1f, want ≈ 357", got)
+	}
+
+	if got := HaversineKm(-23.5505, -46.6333, -23.5505, -46.6333); got != 0 {
+		t.Errorf("HaversineKm() same point = %v, want 0", got)
+	}
+}
+
+func TestGeoBounds_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		bounds  GeoBounds
+		wantErr bool
+	}{
+		{"Valid", GeoBounds{MinLat: -24, MinLng: -47, MaxLat: -23, MaxLng: -46}, false},
+		{"Inverted latitude", GeoBounds{MinLat: -23, MinLng: -47, MaxLat: -24, MaxLng: -46}, true},
+		{"Out of range", GeoBounds{MinLat: -91, MinLng: -47, MaxLat: -23, MaxLng: -46}, true},
+		{"Too large", GeoBounds{MinLat: -34, MinLng: -74, MaxLat: 5, MaxLng: -34}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.bounds.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGeoBounds_GeohashRangesCoverBox(t *testing.T) {
+	center := struct{ lat, lng float64 }{-23.5505, -46.6333}
+	bounds := BoundsAroundPoint(center.lat, center.lng, 3)
+
+	ranges := bounds.GeohashRanges()
+	if len(ranges) == 0 || len(ranges) > 4 {
+		t.Fatalf("GeohashRanges() returned %d ranges, want 1-4", len(ranges))
+	}
+
+	// Every point of a grid inside the box must fall into one of the ranges
+	for i := 0; i <= 10; i++ {
+		for j := 0; j <= 10; j++ {
+			lat := bounds.MinLat + (bounds.MaxLat-bounds.MinLat)*float64(i)/10
+			lng := bounds.MinLng + (bounds.MaxLng-bounds.MinLng)*float64(j)/10
+			hash := EncodeGeohash(lat, lng, DefaultGeohashPrecision)
+
+			covered := false
+			for _, r := range ranges {
+				if hash >= r.Start && hash <= r.End && strings.HasPrefix(hash, r.Start) {
+					covered = true
+					break
+				}
+			}
+			if !covered {
+				t.Fatalf("point (%v, %v) hash %s not covered by %v", lat, lng, hash, ranges)
+			}
+		}
+	}
+}
+
+func TestGeoBounds_GeohashRangesWholeWorld(t *testing.T) {
+	bounds := GeoBounds{MinLat: -90, MinLng: -180, MaxLat: 90, MaxLng: 180}
+
+	ranges := bounds.GeohashRanges()
+	if len(ranges) != 1 || ranges[0].Start != "" {
+		t.Errorf("GeohashRanges() = %v, want a single unbounded range", ranges)
+	}
+}